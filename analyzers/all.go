@@ -1,173 +1,363 @@
 // Package analyzers provides a registry of all golint-sl analyzers.
 //
 // This package exports all analyzers in a single slice for convenient use
-// with multichecker and plugin systems.
+// with multichecker and plugin systems, and looks them up by name for
+// programs embedding a subset of them:
+//
+//	a, ok := analyzers.ByName("nilcheck")
+//	info, _ := analyzers.Metadata("sideeffects") // info.RequiresSSA == true
 package analyzers
 
 import (
+	"sort"
+	"strings"
+
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
 
+	"github.com/spechtlabs/golint-sl/apiversionskew"
+	"github.com/spechtlabs/golint-sl/atomicvalue"
+	"github.com/spechtlabs/golint-sl/buffereduse"
+	"github.com/spechtlabs/golint-sl/chancap"
+	"github.com/spechtlabs/golint-sl/clientretryafter"
 	"github.com/spechtlabs/golint-sl/clockinterface"
 	"github.com/spechtlabs/golint-sl/closurecomplexity"
 	"github.com/spechtlabs/golint-sl/contextfirst"
+	"github.com/spechtlabs/golint-sl/contextkeys"
 	"github.com/spechtlabs/golint-sl/contextlogger"
 	"github.com/spechtlabs/golint-sl/contextpropagation"
+	"github.com/spechtlabs/golint-sl/ctxsignal"
 	"github.com/spechtlabs/golint-sl/dataflow"
+	"github.com/spechtlabs/golint-sl/depinject"
+	"github.com/spechtlabs/golint-sl/doccodefence"
 	"github.com/spechtlabs/golint-sl/emptyinterface"
+	"github.com/spechtlabs/golint-sl/enumjson"
+	"github.com/spechtlabs/golint-sl/errmsgstyle"
+	"github.com/spechtlabs/golint-sl/errorsas"
 	"github.com/spechtlabs/golint-sl/errorwrap"
+	"github.com/spechtlabs/golint-sl/exhauststruct"
 	"github.com/spechtlabs/golint-sl/exporteddoc"
+	"github.com/spechtlabs/golint-sl/fieldpadding"
 	"github.com/spechtlabs/golint-sl/functionsize"
 	"github.com/spechtlabs/golint-sl/goroutineleak"
+	"github.com/spechtlabs/golint-sl/gracedrain"
+	"github.com/spechtlabs/golint-sl/grpcinterceptors"
 	"github.com/spechtlabs/golint-sl/hardcodedcreds"
 	"github.com/spechtlabs/golint-sl/httpclient"
 	"github.com/spechtlabs/golint-sl/humaneerror"
 	"github.com/spechtlabs/golint-sl/interfaceconsistency"
 	"github.com/spechtlabs/golint-sl/lifecycle"
+	"github.com/spechtlabs/golint-sl/localelower"
+	"github.com/spechtlabs/golint-sl/loggershutdown"
+	"github.com/spechtlabs/golint-sl/mapiteration"
 	"github.com/spechtlabs/golint-sl/mockverify"
+	"github.com/spechtlabs/golint-sl/multierror"
 	"github.com/spechtlabs/golint-sl/nestingdepth"
 	"github.com/spechtlabs/golint-sl/nilcheck"
 	"github.com/spechtlabs/golint-sl/nopanic"
 	"github.com/spechtlabs/golint-sl/optionspattern"
+	"github.com/spechtlabs/golint-sl/orphanconst"
 	"github.com/spechtlabs/golint-sl/pkgnaming"
+	"github.com/spechtlabs/golint-sl/pollinterval"
+	"github.com/spechtlabs/golint-sl/probeorder"
+	"github.com/spechtlabs/golint-sl/readadoption"
 	"github.com/spechtlabs/golint-sl/reconciler"
+	"github.com/spechtlabs/golint-sl/requeueresult"
 	"github.com/spechtlabs/golint-sl/resourceclose"
 	"github.com/spechtlabs/golint-sl/returninterface"
+	"github.com/spechtlabs/golint-sl/rowscan"
 	"github.com/spechtlabs/golint-sl/sentinelerrors"
+	"github.com/spechtlabs/golint-sl/shutdownorder"
 	"github.com/spechtlabs/golint-sl/sideeffects"
+	"github.com/spechtlabs/golint-sl/slicealias"
+	"github.com/spechtlabs/golint-sl/spanname"
 	"github.com/spechtlabs/golint-sl/statusupdate"
+	"github.com/spechtlabs/golint-sl/structtags"
 	"github.com/spechtlabs/golint-sl/syncaccess"
+	"github.com/spechtlabs/golint-sl/testglobals"
+	"github.com/spechtlabs/golint-sl/testhelper"
+	"github.com/spechtlabs/golint-sl/timectx"
 	"github.com/spechtlabs/golint-sl/todotracker"
+	"github.com/spechtlabs/golint-sl/tracecardinality"
 	"github.com/spechtlabs/golint-sl/wideevents"
+	"github.com/spechtlabs/golint-sl/wrapboundary"
+)
+
+// Category slugs, as accepted by golint-sl list -category
+const (
+	errorHandling = "error-handling"
+	observability = "observability"
+	kubernetes    = "kubernetes"
+	testability   = "testability"
+	resources     = "resources"
+	safety        = "safety"
+	cleanCode     = "clean-code"
+	architecture  = "architecture"
 )
 
+// entry registers an analyzer with its category slug
+type entry struct {
+	analyzer *analysis.Analyzer
+	category string
+
+	// optIn analyzers only run when enabled by name
+	optIn bool
+}
+
+// registry lists every analyzer once, grouped by category in the order the
+// documentation lists them. All, the category functions, and the lookups
+// are derived from it.
+var registry = []entry{
+	// Error Handling
+	{analyzer: humaneerror.Analyzer, category: errorHandling},
+	{analyzer: errorwrap.Analyzer, category: errorHandling},
+	{analyzer: sentinelerrors.Analyzer, category: errorHandling},
+	{analyzer: wrapboundary.Analyzer, category: errorHandling},
+	{analyzer: multierror.Analyzer, category: errorHandling},
+	{analyzer: errmsgstyle.Analyzer, category: errorHandling},
+	{analyzer: errorsas.Analyzer, category: errorHandling},
+
+	// Observability
+	{analyzer: wideevents.Analyzer, category: observability},
+	{analyzer: contextlogger.Analyzer, category: observability},
+	{analyzer: contextpropagation.Analyzer, category: observability},
+	{analyzer: spanname.Analyzer, category: observability},
+	{analyzer: loggershutdown.Analyzer, category: observability},
+	{analyzer: tracecardinality.Analyzer, category: observability},
+
+	// Kubernetes
+	{analyzer: reconciler.Analyzer, category: kubernetes},
+	{analyzer: statusupdate.Analyzer, category: kubernetes},
+	{analyzer: sideeffects.Analyzer, category: kubernetes},
+	{analyzer: apiversionskew.Analyzer, category: kubernetes},
+	{analyzer: requeueresult.Analyzer, category: kubernetes},
+
+	// Testability
+	{analyzer: clockinterface.Analyzer, category: testability},
+	{analyzer: interfaceconsistency.Analyzer, category: testability},
+	{analyzer: mockverify.Analyzer, category: testability},
+	{analyzer: optionspattern.Analyzer, category: testability},
+	{analyzer: testglobals.Analyzer, category: testability},
+	{analyzer: testhelper.Analyzer, category: testability},
+
+	// Resources
+	{analyzer: resourceclose.Analyzer, category: resources},
+	{analyzer: httpclient.Analyzer, category: resources},
+	{analyzer: rowscan.Analyzer, category: resources},
+	{analyzer: readadoption.Analyzer, category: resources},
+	{analyzer: gracedrain.Analyzer, category: resources},
+	{analyzer: clientretryafter.Analyzer, category: resources},
+	{analyzer: buffereduse.Analyzer, category: resources},
+	{analyzer: grpcinterceptors.Analyzer, category: resources},
+	{analyzer: fieldpadding.Analyzer, category: resources, optIn: true},
+	{analyzer: pollinterval.Analyzer, category: resources},
+
+	// Safety
+	{analyzer: goroutineleak.Analyzer, category: safety},
+	{analyzer: nilcheck.Analyzer, category: safety},
+	{analyzer: nopanic.Analyzer, category: safety},
+	{analyzer: nestingdepth.Analyzer, category: safety},
+	{analyzer: syncaccess.Analyzer, category: safety},
+	{analyzer: chancap.Analyzer, category: safety},
+	{analyzer: timectx.Analyzer, category: safety},
+	{analyzer: atomicvalue.Analyzer, category: safety},
+	{analyzer: probeorder.Analyzer, category: safety},
+	{analyzer: ctxsignal.Analyzer, category: safety},
+	{analyzer: mapiteration.Analyzer, category: safety},
+	{analyzer: exhauststruct.Analyzer, category: safety},
+	{analyzer: contextkeys.Analyzer, category: safety},
+	{analyzer: slicealias.Analyzer, category: safety},
+
+	// Clean Code
+	{analyzer: closurecomplexity.Analyzer, category: cleanCode},
+	{analyzer: emptyinterface.Analyzer, category: cleanCode},
+	{analyzer: returninterface.Analyzer, category: cleanCode},
+	{analyzer: localelower.Analyzer, category: cleanCode},
+	{analyzer: enumjson.Analyzer, category: cleanCode},
+	{analyzer: structtags.Analyzer, category: cleanCode},
+
+	// Architecture
+	{analyzer: contextfirst.Analyzer, category: architecture},
+	{analyzer: pkgnaming.Analyzer, category: architecture},
+	{analyzer: functionsize.Analyzer, category: architecture},
+	{analyzer: exporteddoc.Analyzer, category: architecture},
+	{analyzer: todotracker.Analyzer, category: architecture},
+	{analyzer: hardcodedcreds.Analyzer, category: architecture},
+	{analyzer: lifecycle.Analyzer, category: architecture},
+	{analyzer: dataflow.Analyzer, category: architecture},
+	{analyzer: depinject.Analyzer, category: architecture},
+	{analyzer: orphanconst.Analyzer, category: architecture},
+	{analyzer: doccodefence.Analyzer, category: architecture},
+	{analyzer: shutdownorder.Analyzer, category: architecture},
+}
+
 // All returns all available analyzers.
 // Analyzers are grouped by category for clarity.
 func All() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		// Error Handling
-		humaneerror.Analyzer,
-		errorwrap.Analyzer,
-		sentinelerrors.Analyzer,
-
-		// Observability
-		wideevents.Analyzer,
-		contextlogger.Analyzer,
-		contextpropagation.Analyzer,
-
-		// Kubernetes
-		reconciler.Analyzer,
-		statusupdate.Analyzer,
-		sideeffects.Analyzer,
-
-		// Testability
-		clockinterface.Analyzer,
-		interfaceconsistency.Analyzer,
-		mockverify.Analyzer,
-		optionspattern.Analyzer,
-
-		// Resources
-		resourceclose.Analyzer,
-		httpclient.Analyzer,
-
-		// Safety
-		goroutineleak.Analyzer,
-		nilcheck.Analyzer,
-		nopanic.Analyzer,
-		nestingdepth.Analyzer,
-		syncaccess.Analyzer,
-
-		// Clean Code
-		closurecomplexity.Analyzer,
-		emptyinterface.Analyzer,
-		returninterface.Analyzer,
-
-		// Architecture
-		contextfirst.Analyzer,
-		pkgnaming.Analyzer,
-		functionsize.Analyzer,
-		exporteddoc.Analyzer,
-		todotracker.Analyzer,
-		hardcodedcreds.Analyzer,
-		lifecycle.Analyzer,
-		dataflow.Analyzer,
+	all := make([]*analysis.Analyzer, len(registry))
+	for i, e := range registry {
+		all[i] = e.analyzer
 	}
+	return all
 }
 
-// ErrorHandling returns analyzers focused on error handling patterns.
-func ErrorHandling() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		humaneerror.Analyzer,
-		errorwrap.Analyzer,
-		sentinelerrors.Analyzer,
+// inCategory returns the registered analyzers with the given category slug
+func inCategory(slug string) []*analysis.Analyzer {
+	var list []*analysis.Analyzer
+	for _, e := range registry {
+		if e.category == slug {
+			list = append(list, e.analyzer)
+		}
 	}
+	return list
 }
 
+// ErrorHandling returns analyzers focused on error handling patterns.
+func ErrorHandling() []*analysis.Analyzer { return inCategory(errorHandling) }
+
 // Observability returns analyzers focused on logging and observability.
-func Observability() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		wideevents.Analyzer,
-		contextlogger.Analyzer,
-		contextpropagation.Analyzer,
+func Observability() []*analysis.Analyzer { return inCategory(observability) }
+
+// Kubernetes returns analyzers focused on Kubernetes patterns.
+func Kubernetes() []*analysis.Analyzer { return inCategory(kubernetes) }
+
+// Testability returns analyzers focused on testable code patterns.
+func Testability() []*analysis.Analyzer { return inCategory(testability) }
+
+// Resources returns analyzers focused on resource management.
+func Resources() []*analysis.Analyzer { return inCategory(resources) }
+
+// Safety returns analyzers focused on code safety.
+func Safety() []*analysis.Analyzer { return inCategory(safety) }
+
+// CleanCode returns analyzers focused on clean code patterns.
+func CleanCode() []*analysis.Analyzer { return inCategory(cleanCode) }
+
+// Architecture returns analyzers focused on architectural patterns.
+func Architecture() []*analysis.Analyzer { return inCategory(architecture) }
+
+// OptIn returns the names of analyzers that only run when enabled by name,
+// because they report on code that is correct as written.
+func OptIn() map[string]bool {
+	optIn := make(map[string]bool)
+	for _, e := range registry {
+		if e.optIn {
+			optIn[e.analyzer.Name] = true
+		}
 	}
+	return optIn
 }
 
-// Kubernetes returns analyzers focused on Kubernetes patterns.
-func Kubernetes() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		reconciler.Analyzer,
-		statusupdate.Analyzer,
-		sideeffects.Analyzer,
+// Category is a group of related analyzers, as listed in the documentation.
+type Category struct {
+	// Name is the display name, like "Error Handling".
+	Name string
+
+	// Slug identifies the category on the command line, like "error-handling".
+	Slug string
+
+	// Analyzers returns the analyzers in the category.
+	Analyzers func() []*analysis.Analyzer
+}
+
+// Categories returns the analyzer categories in the order All lists them.
+func Categories() []Category {
+	return []Category{
+		{Name: "Error Handling", Slug: errorHandling, Analyzers: ErrorHandling},
+		{Name: "Observability", Slug: observability, Analyzers: Observability},
+		{Name: "Kubernetes", Slug: kubernetes, Analyzers: Kubernetes},
+		{Name: "Testability", Slug: testability, Analyzers: Testability},
+		{Name: "Resources", Slug: resources, Analyzers: Resources},
+		{Name: "Safety", Slug: safety, Analyzers: Safety},
+		{Name: "Clean Code", Slug: cleanCode, Analyzers: CleanCode},
+		{Name: "Architecture", Slug: architecture, Analyzers: Architecture},
 	}
 }
 
-// Testability returns analyzers focused on testable code patterns.
-func Testability() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		clockinterface.Analyzer,
-		interfaceconsistency.Analyzer,
-		mockverify.Analyzer,
-		optionspattern.Analyzer,
+// CategoryOf returns the category of the analyzer with the given name.
+func CategoryOf(name string) (Category, bool) {
+	e, ok := lookup(name)
+	if !ok {
+		return Category{}, false
+	}
+	for _, c := range Categories() {
+		if c.Slug == e.category {
+			return c, true
+		}
 	}
+	return Category{}, false
 }
 
-// Resources returns analyzers focused on resource management.
-func Resources() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		resourceclose.Analyzer,
-		httpclient.Analyzer,
+// lookup returns the registry entry of the analyzer with the given name
+func lookup(name string) (entry, bool) {
+	for _, e := range registry {
+		if e.analyzer.Name == name {
+			return e, true
+		}
 	}
+	return entry{}, false
 }
 
-// Safety returns analyzers focused on code safety.
-func Safety() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		goroutineleak.Analyzer,
-		nilcheck.Analyzer,
-		nopanic.Analyzer,
-		nestingdepth.Analyzer,
-		syncaccess.Analyzer,
+// ByName returns the analyzer with the given name.
+func ByName(name string) (*analysis.Analyzer, bool) {
+	e, ok := lookup(name)
+	return e.analyzer, ok
+}
+
+// Names returns the names of all analyzers, sorted.
+func Names() []string {
+	names := make([]string, len(registry))
+	for i, e := range registry {
+		names[i] = e.analyzer.Name
 	}
+	sort.Strings(names)
+	return names
 }
 
-// CleanCode returns analyzers focused on clean code patterns.
-func CleanCode() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		closurecomplexity.Analyzer,
-		emptyinterface.Analyzer,
-		returninterface.Analyzer,
+// Info describes an analyzer for programs selecting analyzers without
+// running them.
+type Info struct {
+	// Name is the analyzer name, as used in flags and nolint directives.
+	Name string
+
+	// Category is the category the analyzer is listed under.
+	Category Category
+
+	// Summary is the first line of the analyzer's documentation.
+	Summary string
+
+	// RequiresSSA reports whether the analyzer builds SSA form, directly or
+	// through the analyzers it requires, which makes it expensive on large
+	// packages.
+	RequiresSSA bool
+
+	// OptIn reports whether the analyzer only runs when enabled by name.
+	OptIn bool
+}
+
+// Metadata returns the description of the analyzer with the given name.
+func Metadata(name string) (Info, bool) {
+	e, ok := lookup(name)
+	if !ok {
+		return Info{}, false
 	}
+	c, _ := CategoryOf(name)
+	summary, _, _ := strings.Cut(e.analyzer.Doc, "\n")
+	return Info{
+		Name:        name,
+		Category:    c,
+		Summary:     strings.TrimSpace(summary),
+		RequiresSSA: requires(e.analyzer, buildssa.Analyzer),
+		OptIn:       e.optIn,
+	}, true
 }
 
-// Architecture returns analyzers focused on architectural patterns.
-func Architecture() []*analysis.Analyzer {
-	return []*analysis.Analyzer{
-		contextfirst.Analyzer,
-		pkgnaming.Analyzer,
-		functionsize.Analyzer,
-		exporteddoc.Analyzer,
-		todotracker.Analyzer,
-		hardcodedcreds.Analyzer,
-		lifecycle.Analyzer,
-		dataflow.Analyzer,
+// requires reports whether a depends on dep, directly or transitively
+func requires(a, dep *analysis.Analyzer) bool {
+	for _, r := range a.Requires {
+		if r == dep || requires(r, dep) {
+			return true
+		}
 	}
+	return false
 }
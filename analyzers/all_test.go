@@ -0,0 +1,118 @@
+package analyzers_test
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spechtlabs/golint-sl/analyzers"
+)
+
+func TestEveryAnalyzerHasOneCategory(t *testing.T) {
+	count := make(map[string]int)
+	for _, c := range analyzers.Categories() {
+		for _, a := range c.Analyzers() {
+			count[a.Name]++
+		}
+	}
+
+	all := make(map[string]bool)
+	for _, a := range analyzers.All() {
+		all[a.Name] = true
+		switch count[a.Name] {
+		case 0:
+			t.Errorf("analyzer %s is in All() but in no category", a.Name)
+		case 1:
+		default:
+			t.Errorf("analyzer %s is in %d categories", a.Name, count[a.Name])
+		}
+	}
+
+	for name := range count {
+		if !all[name] {
+			t.Errorf("analyzer %s has a category but is missing from All()", name)
+		}
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	c, ok := analyzers.CategoryOf("reconciler")
+	if !ok || c.Slug != "kubernetes" || c.Name != "Kubernetes" {
+		t.Errorf("CategoryOf(reconciler) = %+v, %v; want Kubernetes", c, ok)
+	}
+
+	if _, ok := analyzers.CategoryOf("nosuchanalyzer"); ok {
+		t.Errorf("CategoryOf(nosuchanalyzer) found a category")
+	}
+}
+
+func TestRegistryIsComplete(t *testing.T) {
+	// Every analyzer package is a top-level directory named after its analyzer
+	files, err := filepath.Glob(filepath.Join("..", "*", "analyzer.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no analyzer packages found")
+	}
+
+	for _, f := range files {
+		name := filepath.Base(filepath.Dir(f))
+		if _, ok := analyzers.ByName(name); !ok {
+			t.Errorf("analyzer package %s is missing from the registry", name)
+		}
+	}
+
+	names := analyzers.Names()
+	if len(names) != len(files) {
+		t.Errorf("registry has %d analyzers, found %d analyzer packages", len(names), len(files))
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Names() is not sorted: %v", names)
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i] == names[i-1] {
+			t.Errorf("analyzer %s is registered twice", names[i])
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, want := range analyzers.All() {
+		got, ok := analyzers.ByName(want.Name)
+		if !ok || got != want {
+			t.Errorf("ByName(%s) = %v, %v; want the registered analyzer", want.Name, got, ok)
+		}
+	}
+
+	if a, ok := analyzers.ByName("nosuchanalyzer"); ok || a != nil {
+		t.Errorf("ByName(nosuchanalyzer) = %v, %v; want nil, false", a, ok)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	info, ok := analyzers.Metadata("sideeffects")
+	if !ok {
+		t.Fatal("Metadata(sideeffects) not found")
+	}
+	if info.Category.Slug != "kubernetes" || !info.RequiresSSA || info.OptIn {
+		t.Errorf("Metadata(sideeffects) = %+v; want an SSA analyzer in kubernetes", info)
+	}
+
+	info, _ = analyzers.Metadata("nilcheck")
+	if info.RequiresSSA {
+		t.Errorf("Metadata(nilcheck).RequiresSSA = true; want false")
+	}
+	if info.Summary == "" || strings.Contains(info.Summary, "\n") {
+		t.Errorf("Metadata(nilcheck).Summary = %q; want the first Doc line", info.Summary)
+	}
+
+	if info, _ := analyzers.Metadata("fieldpadding"); !info.OptIn {
+		t.Errorf("Metadata(fieldpadding).OptIn = false; want true")
+	}
+
+	if _, ok := analyzers.Metadata("nosuchanalyzer"); ok {
+		t.Errorf("Metadata(nosuchanalyzer) found an analyzer")
+	}
+}
@@ -0,0 +1,401 @@
+// Package apiversionskew provides an analyzer that detects Kubernetes API
+// types used with a controller-runtime client or builder whose group version
+// was never added to the manager's scheme.
+//
+// Reconcilers that Get a v1beta1.Widget while main only registers v1 compile
+// fine and fail at runtime with "no kind is registered for the type".
+// Registrations and uses live in different packages, so both are recorded as
+// package facts and compared where they meet: usually the manager's main
+// package, which imports the reconcilers.
+package apiversionskew
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect API types used with controller-runtime but never added to the scheme
+
+Every package records the API packages it adds to a scheme with AddToScheme,
+the types its SchemeBuilder.Register calls register, and the types it passes
+to client Get/List/Create/Update/Patch/Delete/Watch and builder
+For/Owns/Watches calls. Where registrations and uses meet, the analyzer
+reports:
+1. Types whose API package is never added to the scheme, at the use when
+   it is in the same package, or at the registration otherwise
+2. Types missing from their package's SchemeBuilder.Register calls
+3. API packages added to the scheme in a main package whose types no call
+   uses (advisory)
+
+Types from k8s.io packages are registered in client-go's default scheme and
+are not checked.
+
+Bad:
+    // main.go
+    utilruntime.Must(appsv1.AddToScheme(scheme))
+
+    // controllers/widget.go
+    var w appsv1beta1.Widget
+    err := r.Get(ctx, req.NamespacedName, &w) // no kind is registered
+
+Good:
+    utilruntime.Must(appsv1.AddToScheme(scheme))
+    utilruntime.Must(appsv1beta1.AddToScheme(scheme))`
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "apiversionskew",
+	Doc:       Doc,
+	Requires:  nolint.Requires(inspect.Analyzer),
+	Run:       run,
+	FactTypes: []analysis.Fact{(*schemeFact)(nil)},
+}
+
+// schemeFact records the scheme registrations and client uses of a package
+type schemeFact struct {
+	Registered []string // API package paths added to a scheme
+	Types      []string // type names registered with the package's own SchemeBuilder
+	Uses       []Use
+}
+
+func (*schemeFact) AFact() {}
+
+func (f *schemeFact) String() string {
+	return fmt.Sprintf("registered(%d) types(%d) uses(%d)", len(f.Registered), len(f.Types), len(f.Uses))
+}
+
+// Use is an API type passed to a client or builder call
+type Use struct {
+	Pkg    string // package path of the type
+	Name   string // type name without package
+	Call   string // method called, like Get or Owns
+	Caller string // package path of the call
+}
+
+const (
+	clientPkg  = "sigs.k8s.io/controller-runtime/pkg/client"
+	builderPkg = "sigs.k8s.io/controller-runtime/pkg/builder"
+)
+
+// clientMethods are client methods taking an object of a registered type
+var clientMethods = map[string]bool{
+	"Get":         true,
+	"List":        true,
+	"Create":      true,
+	"Update":      true,
+	"Patch":       true,
+	"Delete":      true,
+	"DeleteAllOf": true,
+	"Watch":       true,
+}
+
+// builderMethods are controller builder methods taking an object
+var builderMethods = map[string]bool{
+	"For":     true,
+	"Owns":    true,
+	"Watches": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	own := &schemeFact{}
+	regPos := make(map[string]token.Pos) // registered package -> first registration
+	usePos := make(map[int]token.Pos)    // index in own.Uses -> call
+
+	nodeFilter := []ast.Node{
+		(*ast.SelectorExpr)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			// Calls and function values alike: runtime.NewSchemeBuilder(v1.AddToScheme)
+			if node.Sel.Name != "AddToScheme" {
+				return
+			}
+			pkg := registeredPackage(pass, node)
+			if pkg == "" || pkg == pass.Pkg.Path() {
+				return
+			}
+			if _, ok := regPos[pkg]; !ok {
+				regPos[pkg] = node.Pos()
+				own.Registered = append(own.Registered, pkg)
+			}
+
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			if sel.Sel.Name == "Register" && isOwnSchemeBuilder(pass, sel.X) {
+				own.Types = append(own.Types, registeredTypes(pass, node)...)
+				return
+			}
+			if use, ok := useOf(pass, node, sel); ok {
+				usePos[len(own.Uses)] = node.Pos()
+				own.Uses = append(own.Uses, use)
+			}
+		}
+	})
+
+	deps := make(map[string]*schemeFact) // package path -> fact
+	for _, pf := range pass.AllPackageFacts() {
+		if f, ok := pf.Fact.(*schemeFact); ok && pf.Package != pass.Pkg {
+			deps[pf.Package.Path()] = f
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, pkg := range own.Registered {
+		registered[pkg] = true
+	}
+	for _, f := range deps {
+		for _, pkg := range f.Registered {
+			registered[pkg] = true
+		}
+	}
+
+	if len(registered) > 0 {
+		checkOwnUses(reporter, own, deps, registered, usePos)
+		if len(own.Registered) > 0 {
+			checkDepUses(reporter, own, deps, registered, regPos)
+		}
+	}
+	if pass.Pkg.Name() == "main" && len(own.Registered) > 0 {
+		checkUnused(reporter, own, deps, regPos)
+	}
+
+	if len(own.Registered) > 0 || len(own.Types) > 0 || len(own.Uses) > 0 {
+		pass.ExportPackageFact(own)
+	}
+	return nil, nil
+}
+
+// checkOwnUses reports uses in this package of types that are not
+// registered, at the call
+func checkOwnUses(reporter *nolint.Reporter, own *schemeFact, deps map[string]*schemeFact, registered map[string]bool, usePos map[int]token.Pos) {
+	for i, use := range own.Uses {
+		if msg, ok := problem(use, deps, registered); ok {
+			reporter.Reportf(usePos[i], "%s passed to %s %s", typeName(use), use.Call, msg)
+		}
+	}
+}
+
+// checkDepUses reports uses in dependencies of types that are not
+// registered, at the first registration of this package, which is where
+// the missing registration belongs
+func checkDepUses(reporter *nolint.Reporter, own *schemeFact, deps map[string]*schemeFact, registered map[string]bool, regPos map[string]token.Pos) {
+	pos := regPos[own.Registered[0]]
+
+	paths := make([]string, 0, len(deps))
+	for p := range deps {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var uses []Use
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		for _, use := range deps[p].Uses {
+			key := use.Pkg + "." + use.Name
+			if !seen[key] {
+				seen[key] = true
+				uses = append(uses, use)
+			}
+		}
+	}
+	// Deterministic order of reports
+	sort.Slice(uses, func(i, j int) bool {
+		if uses[i].Pkg != uses[j].Pkg {
+			return uses[i].Pkg < uses[j].Pkg
+		}
+		return uses[i].Name < uses[j].Name
+	})
+
+	for _, use := range uses {
+		if msg, ok := problem(use, deps, registered); ok {
+			reporter.Reportf(pos, "%s passed to %s in %s %s", typeName(use), use.Call, use.Caller, msg)
+		}
+	}
+}
+
+// checkUnused reports API packages added to the scheme whose types are not
+// used by this program
+func checkUnused(reporter *nolint.Reporter, own *schemeFact, deps map[string]*schemeFact, regPos map[string]token.Pos) {
+	used := make(map[string]bool)
+	for _, use := range own.Uses {
+		used[use.Pkg] = true
+	}
+	for _, f := range deps {
+		for _, use := range f.Uses {
+			used[use.Pkg] = true
+		}
+	}
+
+	for _, pkg := range own.Registered {
+		if used[pkg] || strings.HasPrefix(pkg, "k8s.io/") {
+			continue
+		}
+		reporter.Report(&analysis.Diagnostic{
+			Pos:      regPos[pkg],
+			Category: nolint.CategoryAdvisory,
+			Message: fmt.Sprintf("%s is added to the scheme but no Get, List, Create, Watch, For, or Owns call uses its types; remove the registration if the group is not needed",
+				path.Base(pkg)),
+		})
+	}
+}
+
+// problem describes why use fails at runtime, if it does
+func problem(use Use, deps map[string]*schemeFact, registered map[string]bool) (string, bool) {
+	if !registered[use.Pkg] {
+		return fmt.Sprintf("but %s is never added to the scheme; register it with %s.AddToScheme",
+			use.Pkg, path.Base(use.Pkg)), true
+	}
+
+	f := deps[use.Pkg]
+	if f == nil || len(f.Types) == 0 {
+		return "", false
+	}
+	for _, name := range f.Types {
+		if name == use.Name {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("but %s.SchemeBuilder.Register never registers it; add &%s{} to the Register call",
+		path.Base(use.Pkg), use.Name), true
+}
+
+// typeName returns the short qualified name of the used type
+func typeName(use Use) string {
+	return path.Base(use.Pkg) + "." + use.Name
+}
+
+// registeredPackage returns the API package an AddToScheme selector adds to
+// a scheme: the package of the function or variable, or of the scheme
+// builder the method is called on
+func registeredPackage(pass *analysis.Pass, sel *ast.SelectorExpr) string {
+	switch obj := pass.TypesInfo.Uses[sel.Sel].(type) {
+	case *types.Var:
+		if obj.Pkg() != nil && !obj.IsField() {
+			return obj.Pkg().Path()
+		}
+	case *types.Func:
+		sig, ok := obj.Type().(*types.Signature)
+		if !ok {
+			return ""
+		}
+		if sig.Recv() == nil {
+			if obj.Pkg() == nil {
+				return ""
+			}
+			return obj.Pkg().Path()
+		}
+		// v1.SchemeBuilder.AddToScheme
+		var ident *ast.Ident
+		switch x := ast.Unparen(sel.X).(type) {
+		case *ast.Ident:
+			ident = x
+		case *ast.SelectorExpr:
+			ident = x.Sel
+		}
+		if ident == nil {
+			return ""
+		}
+		if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+			return v.Pkg().Path()
+		}
+	}
+	return ""
+}
+
+// isOwnSchemeBuilder checks if expr is a package-level scheme builder
+// variable of the package being analyzed
+func isOwnSchemeBuilder(pass *analysis.Pass, expr ast.Expr) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	return ok && v.Pkg() == pass.Pkg && v.Parent() == pass.Pkg.Scope() && strings.HasSuffix(v.Name(), "SchemeBuilder")
+}
+
+// registeredTypes returns the names of the package's own types passed to a
+// SchemeBuilder.Register call
+func registeredTypes(pass *analysis.Pass, call *ast.CallExpr) []string {
+	var names []string
+	for _, arg := range call.Args {
+		if named := objectType(pass.TypesInfo.TypeOf(arg)); named != nil && named.Obj().Pkg() == pass.Pkg {
+			names = append(names, named.Obj().Name())
+		}
+	}
+	return names
+}
+
+// useOf returns the API type passed to a controller-runtime client or
+// builder call
+func useOf(pass *analysis.Pass, call *ast.CallExpr, sel *ast.SelectorExpr) (Use, bool) {
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return Use{}, false
+	}
+	switch fn.Pkg().Path() {
+	case clientPkg:
+		if !clientMethods[fn.Name()] {
+			return Use{}, false
+		}
+	case builderPkg:
+		if !builderMethods[fn.Name()] {
+			return Use{}, false
+		}
+	default:
+		return Use{}, false
+	}
+
+	for _, arg := range call.Args {
+		named := objectType(pass.TypesInfo.TypeOf(arg))
+		if named == nil || named.Obj().Pkg() == nil {
+			continue
+		}
+		pkg := named.Obj().Pkg().Path()
+		if strings.HasPrefix(pkg, "k8s.io/") || strings.HasPrefix(pkg, "sigs.k8s.io/") {
+			return Use{}, false
+		}
+		return Use{
+			Pkg:    pkg,
+			Name:   named.Obj().Name(),
+			Call:   fn.Name(),
+			Caller: pass.Pkg.Path(),
+		}, true
+	}
+	return Use{}, false
+}
+
+// objectType returns the named struct type t points to, the shape of every
+// Kubernetes object passed to a client
+func objectType(t types.Type) *types.Named {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named
+}
@@ -0,0 +1,15 @@
+package apiversionskew_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/apiversionskew"
+)
+
+func TestAPIVersionSkewAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, apiversionskew.Analyzer,
+		"example.com/op/cmd/manager", "example.com/clean/cmd/manager", "example.com/single")
+}
@@ -0,0 +1,25 @@
+package main // want package:"registered\\(3\\) types\\(0\\) uses\\(0\\)"
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"example.com/clean/controllers"
+	v1 "example.com/op/api/v1"
+	"example.com/op/api/v1beta1"
+)
+
+func main() {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		v1.AddToScheme,
+		v1beta1.SchemeBuilder.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			panic(err)
+		}
+	}
+
+	_ = &controllers.WidgetReconciler{}
+}
@@ -0,0 +1,27 @@
+package controllers // want package:"registered\\(0\\) types\\(0\\) uses\\(3\\)"
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "example.com/op/api/v1"
+	"example.com/op/api/v1beta1"
+)
+
+type WidgetReconciler struct {
+	Client client.Client
+}
+
+func (r *WidgetReconciler) Reconcile(ctx context.Context, key client.ObjectKey) error {
+	var w v1.Widget
+	if err := r.Client.Get(ctx, key, &w); err != nil {
+		return err
+	}
+	return r.Client.Update(ctx, &v1beta1.Widget{})
+}
+
+func (r *WidgetReconciler) SetupWithManager(mgr interface{}) error {
+	return builder.ControllerManagedBy(mgr).For(&v1.Widget{}).Complete(r)
+}
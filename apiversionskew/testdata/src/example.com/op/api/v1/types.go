@@ -0,0 +1,18 @@
+package v1 // want package:"registered\\(0\\) types\\(2\\) uses\\(0\\)"
+
+import "sigs.k8s.io/controller-runtime/pkg/scheme"
+
+type Widget struct{}
+
+type WidgetList struct{}
+
+type Gadget struct{}
+
+var (
+	SchemeBuilder = &scheme.Builder{}
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func init() {
+	SchemeBuilder.Register(&Widget{}, &WidgetList{})
+}
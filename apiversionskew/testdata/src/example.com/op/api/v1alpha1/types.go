@@ -0,0 +1,14 @@
+package v1alpha1 // want package:"registered\\(0\\) types\\(1\\) uses\\(0\\)"
+
+import "sigs.k8s.io/controller-runtime/pkg/scheme"
+
+type Sprocket struct{}
+
+var (
+	SchemeBuilder = &scheme.Builder{}
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func init() {
+	SchemeBuilder.Register(&Sprocket{})
+}
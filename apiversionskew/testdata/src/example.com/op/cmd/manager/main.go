@@ -0,0 +1,25 @@
+package main // want package:"registered\\(3\\) types\\(0\\) uses\\(0\\)"
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	v1 "example.com/op/api/v1"
+	"example.com/op/api/v1alpha1"
+	"example.com/op/controllers"
+)
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	scheme := runtime.NewScheme()
+	must(clientgoscheme.AddToScheme(scheme)) // want `v1.Gadget passed to Create in example.com/op/controllers but v1.SchemeBuilder.Register never registers it` `v1beta1.Widget passed to Get in example.com/op/controllers but example.com/op/api/v1beta1 is never added to the scheme; register it with v1beta1.AddToScheme`
+	must(v1.AddToScheme(scheme))
+	must(v1alpha1.AddToScheme(scheme)) // want `v1alpha1 is added to the scheme but no Get, List, Create, Watch, For, or Owns call uses its types`
+
+	_ = &controllers.WidgetReconciler{}
+}
@@ -0,0 +1,47 @@
+package controllers // want package:"registered\\(0\\) types\\(0\\) uses\\(7\\)"
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "example.com/op/api/v1"
+	"example.com/op/api/v1beta1"
+)
+
+type WidgetReconciler struct {
+	client.Client
+}
+
+func (r *WidgetReconciler) Reconcile(ctx context.Context, key client.ObjectKey) error {
+	var w v1.Widget
+	if err := r.Get(ctx, key, &w); err != nil {
+		return err
+	}
+	var list v1.WidgetList
+	if err := r.List(ctx, &list); err != nil {
+		return err
+	}
+
+	// Registration problems are reported where the scheme is set up
+	var legacy v1beta1.Widget
+	if err := r.Get(ctx, key, &legacy); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, &v1.Gadget{}); err != nil {
+		return err
+	}
+
+	// Built-in types come with client-go's scheme
+	var pod corev1.Pod
+	return r.Get(ctx, key, &pod)
+}
+
+func (r *WidgetReconciler) SetupWithManager(mgr interface{}) error {
+	return builder.ControllerManagedBy(mgr).
+		For(&v1.Widget{}).
+		Owns(&corev1.ConfigMap{}).
+		Complete(r)
+}
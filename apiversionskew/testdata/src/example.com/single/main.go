@@ -0,0 +1,26 @@
+package main // want package:"registered\\(1\\) types\\(0\\) uses\\(2\\)"
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "example.com/op/api/v1"
+	"example.com/op/api/v1beta1"
+)
+
+func reconcile(ctx context.Context, c client.Client, key client.ObjectKey) error {
+	if err := c.Get(ctx, key, &v1.Widget{}); err != nil {
+		return err
+	}
+	return c.Get(ctx, key, &v1beta1.Widget{}) // want `v1beta1.Widget passed to Get but example.com/op/api/v1beta1 is never added to the scheme`
+}
+
+func main() {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	_ = reconcile
+}
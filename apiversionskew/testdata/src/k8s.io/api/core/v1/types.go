@@ -0,0 +1,5 @@
+package v1
+
+type Pod struct{}
+
+type ConfigMap struct{}
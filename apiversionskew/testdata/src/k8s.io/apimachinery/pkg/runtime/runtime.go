@@ -0,0 +1,5 @@
+package runtime
+
+type Scheme struct{}
+
+func NewScheme() *Scheme { return &Scheme{} }
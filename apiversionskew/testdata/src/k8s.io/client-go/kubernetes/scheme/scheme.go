@@ -0,0 +1,5 @@
+package scheme
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+func AddToScheme(s *runtime.Scheme) error { return nil }
@@ -0,0 +1,13 @@
+package builder
+
+import "sigs.k8s.io/controller-runtime/pkg/client"
+
+type Builder struct{}
+
+func ControllerManagedBy(m interface{}) *Builder { return &Builder{} }
+
+func (b *Builder) For(obj client.Object) *Builder { return b }
+
+func (b *Builder) Owns(obj client.Object) *Builder { return b }
+
+func (b *Builder) Complete(r interface{}) error { return nil }
@@ -0,0 +1,25 @@
+package client
+
+import "context"
+
+type Object interface{}
+
+type ObjectList interface{}
+
+type ObjectKey struct{ Namespace, Name string }
+
+type Reader interface {
+	Get(ctx context.Context, key ObjectKey, obj Object) error
+	List(ctx context.Context, list ObjectList) error
+}
+
+type Writer interface {
+	Create(ctx context.Context, obj Object) error
+	Update(ctx context.Context, obj Object) error
+	Delete(ctx context.Context, obj Object) error
+}
+
+type Client interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,9 @@
+package scheme
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+type Builder struct{}
+
+func (bld *Builder) Register(object ...interface{}) *Builder { return bld }
+
+func (bld *Builder) AddToScheme(s *runtime.Scheme) error { return nil }
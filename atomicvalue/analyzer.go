@@ -0,0 +1,387 @@
+// Package atomicvalue provides an analyzer that detects sync/atomic misuse and
+// suggests the typed atomics introduced in Go 1.19.
+//
+// atomic.AddInt64(&s.count, 1) works only as long as every access to s.count
+// goes through the atomic package and the field happens to be 64-bit aligned.
+// atomic.Int64 enforces both. atomic.Value panics when Store is called with
+// different concrete types, and a Load followed by a Store is not atomic at all.
+package atomicvalue
+
+import (
+	"flag"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect sync/atomic misuse and suggest typed atomics
+
+This analyzer detects:
+1. atomic.AddInt64/LoadInt64/... on plain integer struct fields
+   (use atomic.Int64 and friends, Go 1.19+)
+2. 64-bit atomic operations on struct fields that are not 64-bit aligned on
+   32-bit platforms (enabled with -check-32bit); these panic on ARM and 386
+3. atomic.Value.Store called with different concrete types in one package,
+   which panics at runtime
+4. Read-modify-write sequences built from Load and Store instead of a
+   CompareAndSwap loop or Add
+
+Bad:
+    type Stats struct {
+        ready bool
+        hits  int64
+    }
+    atomic.AddInt64(&s.hits, 1)
+
+    n := s.count.Load()
+    s.count.Store(n + 1) // lost updates under contention
+
+Good:
+    type Stats struct {
+        ready bool
+        hits  atomic.Int64
+    }
+    s.hits.Add(1)
+
+    s.count.Add(1)
+
+Flags:
+    -check-32bit  report 64-bit atomics on fields misaligned on 32-bit platforms`
+
+var check32Bit bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "atomicvalue",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("atomicvalue", flag.ExitOnError)
+	fs.BoolVar(&check32Bit, "check-32bit", false,
+		"report 64-bit atomics on struct fields misaligned on 32-bit platforms")
+	return *fs
+}
+
+// typedAtomics maps the type suffix of sync/atomic functions to the typed
+// replacement, e.g. AddInt64 -> atomic.Int64
+var typedAtomics = map[string]string{
+	"Int32":   "atomic.Int32",
+	"Int64":   "atomic.Int64",
+	"Uint32":  "atomic.Uint32",
+	"Uint64":  "atomic.Uint64",
+	"Uintptr": "atomic.Uintptr",
+}
+
+// atomicOps are the operation prefixes of the sync/atomic functions
+var atomicOps = []string{"Add", "Load", "Store", "Swap", "CompareAndSwap", "And", "Or"}
+
+// valueStore records one atomic.Value.Store call site
+type valueStore struct {
+	call *ast.CallExpr
+	typ  types.Type
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.BlockStmt)(nil),
+	}
+
+	// Store types are collected package-wide, then compared per atomic.Value
+	stores := make(map[types.Object][]valueStore)
+	var storeOrder []types.Object
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			checkFuncCall(reporter, pass, node)
+
+			if obj, st, ok := valueStoreOf(pass, node); ok {
+				if _, seen := stores[obj]; !seen {
+					storeOrder = append(storeOrder, obj)
+				}
+				stores[obj] = append(stores[obj], st)
+			}
+		case *ast.BlockStmt:
+			checkLoadThenStore(reporter, pass, node)
+		}
+	})
+
+	for _, obj := range storeOrder {
+		checkStoreTypes(reporter, pass, obj, stores[obj])
+	}
+
+	return nil, nil
+}
+
+// atomicFunc returns the sync/atomic function called, or nil
+func atomicFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sync/atomic" {
+		return nil
+	}
+	return fn
+}
+
+// splitOp splits a sync/atomic function name such as AddInt64 into its
+// operation and type suffix
+func splitOp(name string) (string, string) {
+	for _, op := range atomicOps {
+		if suffix, ok := strings.CutPrefix(name, op); ok {
+			if _, typed := typedAtomics[suffix]; typed {
+				return op, suffix
+			}
+		}
+	}
+	return "", ""
+}
+
+// checkFuncCall flags atomic.XxxInt64(&s.field, ...) on plain struct fields
+func checkFuncCall(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr) {
+	fn := atomicFunc(pass, call)
+	if fn == nil || isMethod(fn) || len(call.Args) == 0 {
+		return
+	}
+
+	_, suffix := splitOp(fn.Name())
+	if suffix == "" {
+		return
+	}
+
+	field, sel := addressedField(pass, call.Args[0])
+	if field == nil {
+		return
+	}
+
+	if check32Bit && (suffix == "Int64" || suffix == "Uint64") {
+		if offset, ok := offset32(pass, sel); ok && offset%8 != 0 {
+			reporter.Reportf(call.Pos(),
+				"atomic.%s on field %s at offset %d is not 64-bit aligned on 32-bit platforms and will panic; use %s",
+				fn.Name(), field.Name(), offset, typedAtomics[suffix])
+			return
+		}
+	}
+
+	reporter.Reportf(call.Pos(),
+		"atomic.%s on plain field %s; declare it as %s so every access is atomic",
+		fn.Name(), field.Name(), typedAtomics[suffix])
+}
+
+// addressedField returns the struct field for an &x.field argument
+func addressedField(pass *analysis.Pass, arg ast.Expr) (*types.Var, *ast.SelectorExpr) {
+	unary, ok := ast.Unparen(arg).(*ast.UnaryExpr)
+	if !ok {
+		return nil, nil
+	}
+
+	sel, ok := ast.Unparen(unary.X).(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+
+	field, ok := pass.TypesInfo.ObjectOf(sel.Sel).(*types.Var)
+	if !ok || !field.IsField() {
+		return nil, nil
+	}
+	return field, sel
+}
+
+// offset32 computes the offset of a selected field within its struct using
+// 32-bit (386) sizes
+func offset32(pass *analysis.Pass, sel *ast.SelectorExpr) (int64, bool) {
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || len(selection.Index()) != 1 {
+		// Promoted fields through embedding are not followed
+		return 0, false
+	}
+
+	recv := selection.Recv()
+	if ptr, ok := recv.Underlying().(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	st, ok := recv.Underlying().(*types.Struct)
+	if !ok {
+		return 0, false
+	}
+
+	sizes := types.SizesFor("gc", "386")
+	if sizes == nil {
+		return 0, false
+	}
+
+	fields := make([]*types.Var, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(fields)
+	return offsets[selection.Index()[0]], true
+}
+
+func isMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() != nil
+}
+
+// isAtomicValue checks if t is sync/atomic.Value
+func isAtomicValue(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "sync/atomic" && named.Obj().Name() == "Value"
+}
+
+// valueStoreOf returns the atomic.Value variable and stored type for v.Store(x)
+func valueStoreOf(pass *analysis.Pass, call *ast.CallExpr) (types.Object, valueStore, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Store" || len(call.Args) != 1 {
+		return nil, valueStore{}, false
+	}
+
+	if !isAtomicValue(pass.TypesInfo.TypeOf(sel.X)) {
+		return nil, valueStore{}, false
+	}
+
+	var obj types.Object
+	switch x := ast.Unparen(sel.X).(type) {
+	case *ast.Ident:
+		obj = pass.TypesInfo.ObjectOf(x)
+	case *ast.SelectorExpr:
+		obj = pass.TypesInfo.ObjectOf(x.Sel)
+	}
+	if obj == nil {
+		return nil, valueStore{}, false
+	}
+
+	// Interface-typed arguments have an unknown dynamic type
+	typ := pass.TypesInfo.TypeOf(call.Args[0])
+	if typ == nil || types.IsInterface(typ) {
+		return nil, valueStore{}, false
+	}
+	if basic, ok := typ.(*types.Basic); ok && basic.Kind() == types.UntypedNil {
+		return nil, valueStore{}, false
+	}
+
+	return obj, valueStore{call: call, typ: types.Default(typ)}, true
+}
+
+// checkStoreTypes flags Store calls whose type differs from the first Store
+func checkStoreTypes(reporter *nolint.Reporter, pass *analysis.Pass, obj types.Object, stores []valueStore) {
+	first := stores[0]
+	for _, st := range stores[1:] {
+		if types.Identical(st.typ, first.typ) {
+			continue
+		}
+		reporter.Reportf(st.call.Pos(),
+			"atomic.Value %s stores %s here but %s at line %d; storing inconsistent types panics at runtime",
+			obj.Name(), types.TypeString(st.typ, types.RelativeTo(pass.Pkg)),
+			types.TypeString(first.typ, types.RelativeTo(pass.Pkg)),
+			pass.Fset.Position(first.call.Pos()).Line)
+	}
+}
+
+// atomicAccess returns the accessed atomic and the operation for
+// atomic.LoadInt64(&x) style calls and x.Load() on typed atomics
+func atomicAccess(pass *analysis.Pass, expr ast.Expr) (string, string, *ast.CallExpr) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return "", "", nil
+	}
+
+	fn := atomicFunc(pass, call)
+	if fn == nil {
+		return "", "", nil
+	}
+
+	if isMethod(fn) {
+		sel := call.Fun.(*ast.SelectorExpr)
+		return types.ExprString(sel.X), fn.Name(), call
+	}
+
+	op, _ := splitOp(fn.Name())
+	if op == "" || len(call.Args) == 0 {
+		return "", "", nil
+	}
+	unary, ok := ast.Unparen(call.Args[0]).(*ast.UnaryExpr)
+	if !ok {
+		return "", "", nil
+	}
+	return types.ExprString(unary.X), op, call
+}
+
+// storedValue returns the value argument of a Store call
+func storedValue(call *ast.CallExpr) ast.Expr {
+	return call.Args[len(call.Args)-1]
+}
+
+// checkLoadThenStore flags x.Store(f(x.Load())) and v := x.Load(); x.Store(f(v))
+// within one block
+func checkLoadThenStore(reporter *nolint.Reporter, pass *analysis.Pass, block *ast.BlockStmt) {
+	// Local variables holding a loaded atomic, by the atomic they came from
+	loaded := make(map[types.Object]string)
+
+	for _, stmt := range block.List {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if len(s.Lhs) == 1 && len(s.Rhs) == 1 {
+				if target, op, _ := atomicAccess(pass, s.Rhs[0]); op == "Load" {
+					if ident, ok := s.Lhs[0].(*ast.Ident); ok {
+						if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+							loaded[obj] = target
+						}
+					}
+				}
+			}
+		case *ast.ExprStmt:
+			target, op, call := atomicAccess(pass, s.X)
+			if op != "Store" || len(call.Args) == 0 {
+				continue
+			}
+			if usesLoad(pass, storedValue(call), target, loaded) {
+				reporter.Reportf(call.Pos(),
+					"Store of a value computed from Load of %s is not atomic; concurrent updates are lost, use Add or a CompareAndSwap loop",
+					target)
+			}
+		}
+	}
+}
+
+// usesLoad checks if expr reads target through a Load call or a variable
+// holding a previous Load of target
+func usesLoad(pass *analysis.Pass, expr ast.Expr, target string, loaded map[types.Object]string) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if t, op, _ := atomicAccess(pass, node); op == "Load" && t == target {
+				found = true
+			}
+		case *ast.Ident:
+			if t, ok := loaded[pass.TypesInfo.Uses[node]]; ok && t == target {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,24 @@
+package atomicvalue_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/atomicvalue"
+)
+
+func TestAtomicValueAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, atomicvalue.Analyzer, "a")
+}
+
+func TestAtomicValueCheck32Bit(t *testing.T) {
+	if err := atomicvalue.Analyzer.Flags.Set("check-32bit", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = atomicvalue.Analyzer.Flags.Set("check-32bit", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, atomicvalue.Analyzer, "align")
+}
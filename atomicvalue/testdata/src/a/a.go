@@ -0,0 +1,99 @@
+package a
+
+import "sync/atomic"
+
+type Stats struct {
+	ready bool
+	hits  int64
+	total uint32
+}
+
+type Counter struct {
+	count atomic.Int64
+	hits  atomic.Int64
+}
+
+type Config struct {
+	Name string
+}
+
+var current atomic.Value
+
+var settings atomic.Value
+
+// Bad: plain field with the function API
+func BadAdd(s *Stats) {
+	atomic.AddInt64(&s.hits, 1) // want `atomic.AddInt64 on plain field hits; declare it as atomic.Int64`
+}
+
+func BadLoad(s *Stats) uint32 {
+	return atomic.LoadUint32(&s.total) // want `atomic.LoadUint32 on plain field total; declare it as atomic.Uint32`
+}
+
+// Good: typed atomics
+func GoodAdd(c *Counter) {
+	c.hits.Add(1)
+}
+
+// Good: package-level variables are not struct fields
+var requests int64
+
+func GoodGlobal() {
+	atomic.AddInt64(&requests, 1)
+}
+
+// Bad: atomic.Value stores different concrete types
+func SetConfig(c *Config) {
+	current.Store(c)
+}
+
+func SetName(name string) {
+	current.Store(name) // want `atomic.Value current stores string here but \*Config at line \d+`
+}
+
+// Good: the same type everywhere
+func SetSettings(c Config) {
+	settings.Store(c)
+}
+
+func ResetSettings() {
+	settings.Store(Config{})
+}
+
+// Bad: Load, compute, Store
+func BadIncrement(c *Counter) {
+	n := c.count.Load()
+	c.count.Store(n + 1) // want `Store of a value computed from Load of c.count is not atomic`
+}
+
+func BadInline(c *Counter) {
+	c.count.Store(c.count.Load() * 2) // want `Store of a value computed from Load of c.count is not atomic`
+}
+
+func BadFunctionAPI() {
+	v := atomic.LoadInt64(&requests)
+	atomic.StoreInt64(&requests, v+1) // want `Store of a value computed from Load of requests is not atomic`
+}
+
+// Good: CompareAndSwap loop
+func GoodDouble(c *Counter) {
+	for {
+		n := c.count.Load()
+		if c.count.CompareAndSwap(n, n*2) {
+			return
+		}
+	}
+}
+
+// Good: Store of a value unrelated to the Load
+func GoodReset(c *Counter) int64 {
+	n := c.count.Load()
+	c.count.Store(0)
+	return n
+}
+
+// Good: Load of a different atomic
+func GoodCopy(c *Counter) {
+	n := c.hits.Load()
+	c.count.Store(n)
+}
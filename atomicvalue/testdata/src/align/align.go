@@ -0,0 +1,21 @@
+package align
+
+import "sync/atomic"
+
+type Misaligned struct {
+	ready bool
+	hits  int64
+}
+
+type Aligned struct {
+	hits  int64
+	ready bool
+}
+
+func BadMisaligned(m *Misaligned) {
+	atomic.AddInt64(&m.hits, 1) // want `atomic.AddInt64 on field hits at offset 4 is not 64-bit aligned on 32-bit platforms`
+}
+
+func FirstField(a *Aligned) {
+	atomic.AddInt64(&a.hits, 1) // want `atomic.AddInt64 on plain field hits; declare it as atomic.Int64`
+}
@@ -0,0 +1,342 @@
+// Package buffereduse provides an analyzer that detects buffered and
+// compressing writers that are not flushed or closed before the resource they
+// wrap.
+//
+// bufio.NewWriter(f) keeps up to 4KB in memory; if f is closed before Flush,
+// the tail of the file is silently lost. A gzip.Writer closed after its file
+// never writes the gzip footer, producing a truncated archive.
+package buffereduse
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect writers that are not flushed or closed before the resource they wrap
+
+This analyzer detects:
+1. bufio.Writer and csv.Writer that are never flushed
+2. gzip/zlib/flate, tar, and zip writers that are never closed
+3. Wrapped resources closed before the wrapper is flushed or closed,
+   including defers registered in the wrong order (defers run LIFO)
+4. csv.Writer flushed without checking Error()
+
+Bad:
+    gz := gzip.NewWriter(f)
+    defer gz.Close()
+    defer f.Close() // runs before gz.Close: the footer is never written
+
+Good:
+    defer f.Close()
+    gz := gzip.NewWriter(f)
+    defer gz.Close() // runs first, then f.Close`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "buffereduse",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// finalizer is the call that pushes a wrapper's data to the wrapped writer
+type finalizer int
+
+const (
+	flush finalizer = iota
+	closeWriter
+	csvFlush
+)
+
+// constructors maps "pkgpath.Func" to how the returned writer is finalized
+var constructors = map[string]finalizer{
+	"bufio.NewWriter":              flush,
+	"bufio.NewWriterSize":          flush,
+	"encoding/csv.NewWriter":       csvFlush,
+	"compress/gzip.NewWriter":      closeWriter,
+	"compress/gzip.NewWriterLevel": closeWriter,
+	"compress/zlib.NewWriter":      closeWriter,
+	"compress/zlib.NewWriterLevel": closeWriter,
+	"compress/flate.NewWriter":     closeWriter,
+	"archive/tar.NewWriter":        closeWriter,
+	"archive/zip.NewWriter":        closeWriter,
+}
+
+// wrapper is a writer constructed around another writer
+type wrapper struct {
+	name     string
+	typeName string
+	wrapped  string
+	kind     finalizer
+	pos      token.Pos
+}
+
+// method returns the call that finalizes the wrapper
+func (w wrapper) method() string {
+	if w.kind == closeWriter {
+		return "Close"
+	}
+	return "Flush"
+}
+
+// callSite is a method call on a named value
+type callSite struct {
+	method   string
+	pos      token.Pos
+	deferred bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			body = node.Body
+		case *ast.FuncLit:
+			body = node.Body
+		}
+		if body == nil {
+			return
+		}
+
+		checkFunc(reporter, pass, body)
+	})
+
+	return nil, nil
+}
+
+// constructorOf returns the wrapper kind and display name for a constructor call
+func constructorOf(pass *analysis.Pass, call *ast.CallExpr) (finalizer, string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return 0, "", false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return 0, "", false
+	}
+
+	kind, ok := constructors[fn.Pkg().Path()+"."+fn.Name()]
+	if !ok {
+		return 0, "", false
+	}
+	return kind, path.Base(fn.Pkg().Path()) + ".Writer", true
+}
+
+// checkFunc collects wrappers and method calls in one function body and
+// checks each wrapper is finalized before its wrapped writer is closed
+func checkFunc(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var wrappers []wrapper
+	calls := make(map[string][]callSite)
+	escaped := make(map[string]bool)
+
+	record := func(call *ast.CallExpr, deferPos token.Pos) {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		site := callSite{method: sel.Sel.Name, pos: call.Pos()}
+		if deferPos.IsValid() {
+			// A deferred call takes effect in defer order, not at its position
+			site.pos = deferPos
+			site.deferred = true
+		}
+		calls[ident.Name] = append(calls[ident.Name], site)
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Analyzed separately; deferred closures are handled below
+			return false
+
+		case *ast.DeferStmt:
+			// defer func() { _ = w.Close() }() counts as a deferred Close
+			if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				ast.Inspect(lit.Body, func(child ast.Node) bool {
+					if call, ok := child.(*ast.CallExpr); ok {
+						record(call, node.Pos())
+					}
+					return true
+				})
+			} else {
+				record(node.Call, node.Pos())
+			}
+			return false
+
+		case *ast.CallExpr:
+			record(node, token.NoPos)
+
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 && len(node.Lhs) >= 1 {
+				if call, ok := node.Rhs[0].(*ast.CallExpr); ok {
+					if w, ok := newWrapper(pass, node.Lhs[0], call); ok {
+						wrappers = append(wrappers, w)
+					}
+				}
+			}
+			for _, rhs := range node.Rhs {
+				if ident, ok := rhs.(*ast.Ident); ok {
+					escaped[ident.Name] = true
+				}
+			}
+
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if ident, ok := ast.Unparen(result).(*ast.Ident); ok {
+					escaped[ident.Name] = true
+				}
+			}
+
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				if ident, ok := elt.(*ast.Ident); ok {
+					escaped[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+
+	for _, w := range wrappers {
+		if escaped[w.name] {
+			continue
+		}
+		checkWrapper(reporter, pass, w, calls)
+	}
+}
+
+// newWrapper records w := bufio.NewWriter(f) style constructions
+func newWrapper(pass *analysis.Pass, lhs ast.Expr, call *ast.CallExpr) (wrapper, bool) {
+	ident, ok := lhs.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return wrapper{}, false
+	}
+
+	kind, typeName, ok := constructorOf(pass, call)
+	if !ok {
+		return wrapper{}, false
+	}
+
+	return wrapper{
+		name:     ident.Name,
+		typeName: typeName,
+		wrapped:  types.ExprString(call.Args[0]),
+		kind:     kind,
+		pos:      call.Pos(),
+	}, true
+}
+
+// firstCall returns the first call of method on name after pos
+func firstCall(calls map[string][]callSite, name, method string, after token.Pos) (callSite, bool) {
+	for _, c := range calls[name] {
+		if c.method == method && c.pos > after {
+			return c, true
+		}
+	}
+	return callSite{}, false
+}
+
+// checkWrapper reports a missing or misordered finalization for one wrapper
+func checkWrapper(reporter *nolint.Reporter, pass *analysis.Pass, w wrapper, calls map[string][]callSite) {
+	method := w.method()
+
+	final, ok := firstCall(calls, w.name, method, w.pos)
+	if !ok {
+		switch w.kind {
+		case closeWriter:
+			reporter.Reportf(w.pos,
+				"%s %s wrapping %s is never closed; call %s.Close() before closing %s or the stream is truncated",
+				w.typeName, w.name, w.wrapped, w.name, w.wrapped)
+		default:
+			reporter.Reportf(w.pos,
+				"%s %s wrapping %s is never flushed; call %s.Flush() before %s is closed or the buffered tail is lost",
+				w.typeName, w.name, w.wrapped, w.name, w.wrapped)
+		}
+		return
+	}
+
+	if w.kind == csvFlush {
+		if _, ok := firstCall(calls, w.name, "Error", w.pos); !ok {
+			reporter.Reportf(final.pos,
+				"csv.Writer %s is flushed but %s.Error() is never checked; write errors are only reported there",
+				w.name, w.name)
+		}
+	}
+
+	deferredClose, hasDeferred := deferredCall(calls, w.wrapped, "Close")
+	directClose, hasDirect := lastDirectCall(calls, w.wrapped, "Close", w.pos)
+
+	line := func(pos token.Pos) int { return pass.Fset.Position(pos).Line }
+
+	switch {
+	case final.deferred && hasDeferred:
+		// Defers run LIFO: the wrapper's defer must be registered last
+		if final.pos < deferredClose.pos {
+			reporter.Reportf(final.pos,
+				"defer %s.%s() is registered before defer %s.Close() at line %d; defers run LIFO, so %s is closed first",
+				w.name, method, w.wrapped, line(deferredClose.pos), w.wrapped)
+		}
+	case final.deferred && hasDirect:
+		reporter.Reportf(final.pos,
+			"deferred %s.%s() runs after %s.Close() at line %d; %s the wrapper before closing %s",
+			w.name, method, w.wrapped, line(directClose.pos), finalizeVerb(w), w.wrapped)
+	case !final.deferred && hasDirect && directClose.pos < final.pos:
+		reporter.Reportf(directClose.pos,
+			"%s is closed before %s.%s() at line %d; %s the wrapper first",
+			w.wrapped, w.name, method, line(final.pos), finalizeVerb(w))
+	}
+}
+
+// deferredCall returns the first deferred call of method on name
+func deferredCall(calls map[string][]callSite, name, method string) (callSite, bool) {
+	for _, c := range calls[name] {
+		if c.method == method && c.deferred {
+			return c, true
+		}
+	}
+	return callSite{}, false
+}
+
+// lastDirectCall returns the last non-deferred call of method on name after
+// pos. Earlier calls are usually error paths that abandon the output.
+func lastDirectCall(calls map[string][]callSite, name, method string, after token.Pos) (callSite, bool) {
+	var last callSite
+	found := false
+	for _, c := range calls[name] {
+		if c.method == method && !c.deferred && c.pos > after {
+			last, found = c, true
+		}
+	}
+	return last, found
+}
+
+func finalizeVerb(w wrapper) string {
+	if w.kind == closeWriter {
+		return "close"
+	}
+	return "flush"
+}
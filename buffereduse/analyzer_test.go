@@ -0,0 +1,14 @@
+package buffereduse_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/buffereduse"
+)
+
+func TestBufferedUseAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, buffereduse.Analyzer, "a")
+}
@@ -0,0 +1,144 @@
+package a
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"os"
+)
+
+// Bad: buffered tail lost
+func WriteLines(name string, lines []string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f) // want `bufio.Writer w wrapping f is never flushed`
+	for _, line := range lines {
+		if _, err := w.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bad: defers in the wrong order
+func Compress(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	defer gz.Close() // want `defer gz.Close\(\) is registered before defer f.Close\(\) at line \d+; defers run LIFO, so f is closed first`
+	defer f.Close()
+
+	_, err = gz.Write(data)
+	return err
+}
+
+// Bad: file closed before the flush
+func WriteAndClose(f *os.File, data []byte) error {
+	w := bufio.NewWriter(f)
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	f.Close() // want `f is closed before w.Flush\(\) at line \d+; flush the wrapper first`
+	return w.Flush()
+}
+
+// Bad: deferred flush runs after the direct close
+func WriteDeferredFlush(f *os.File, data []byte) error {
+	w := bufio.NewWriter(f)
+	defer w.Flush() // want `deferred w.Flush\(\) runs after f.Close\(\) at line \d+`
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Bad: zip writer never closed
+func Archive(out io.Writer) error {
+	zw := zip.NewWriter(out) // want `zip.Writer zw wrapping out is never closed`
+	_, err := zw.Create("empty.txt")
+	return err
+}
+
+// Bad: csv errors never checked
+func ExportCSV(out io.Writer, rows [][]string) {
+	cw := csv.NewWriter(out)
+	for _, row := range rows {
+		_ = cw.Write(row)
+	}
+	cw.Flush() // want `csv.Writer cw is flushed but cw.Error\(\) is never checked`
+}
+
+// Good: correct LIFO defers
+func CompressCorrectly(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	_, err = gz.Write(data)
+	return err
+}
+
+// Good: explicit close order with error handling
+func CompressExplicitly(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Good: deferred closure
+func WriteBuffered(name string, data []byte) (err error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	defer func() {
+		if flushErr := w.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+	_, err = w.Write(data)
+	return err
+}
+
+// Good: csv flushed and checked
+func ExportCSVChecked(out io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(out)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Good: the wrapper escapes to the caller
+func NewBuffered(f *os.File) *bufio.Writer {
+	w := bufio.NewWriter(f)
+	return w
+}
@@ -0,0 +1,390 @@
+// Package chancap provides an analyzer that detects channel buffers, slice
+// sizes, and loop bounds derived from unvalidated configuration values.
+//
+// make(chan T, cfg.N) panics on a negative N and silently becomes unbuffered
+// on zero; a worker loop bounded by cfg.Workers spawns nothing when the value
+// is zero and the service deadlocks. Values read from config structs or parsed
+// with strconv must be checked before they size anything.
+package chancap
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `ensure config-derived sizes are validated before use
+
+This analyzer detects:
+1. make(chan T, n) where n comes from a config field or strconv.Atoi
+2. make([]T, n) / make([]T, 0, n) sized from the same sources
+3. for loops and range-over-int bounded by such values
+
+A negative channel or slice size panics at runtime, a zero buffer silently
+makes a channel unbuffered, and a zero worker count spawns no workers:
+
+    size := cfg.QueueSize
+    if size <= 0 {
+        size = defaultQueueSize
+    }
+    queue := make(chan Job, size)
+
+The value must be compared against a bound in an if statement before it is
+used. Validation inside a separate Validate() method is not tracked.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "chancap",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// configTypeSuffixes are struct type name suffixes that mark configuration types
+var configTypeSuffixes = []string{
+	"Config",
+	"Configuration",
+	"Cfg",
+	"Conf",
+	"Options",
+	"Opts",
+	"Settings",
+}
+
+// parseFuncs are strconv functions whose results are untrusted integers
+var parseFuncs = map[string]bool{
+	"Atoi":      true,
+	"ParseInt":  true,
+	"ParseUint": true,
+}
+
+// sizeSource describes where an untrusted size value came from.
+type sizeSource struct {
+	keys   []string // expressions whose validation also validates this value
+	origin string   // human readable origin for diagnostics
+}
+
+// guard is a bounds comparison found in an if condition.
+type guard struct {
+	key string
+	pos token.Pos
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return
+		}
+
+		checkFunction(reporter, pass, fn)
+	})
+
+	return nil, nil
+}
+
+func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	tainted := make(map[string]sizeSource)
+	var guards []guard
+
+	// First pass: track config-derived locals and bound checks in source order
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Closures run at a different time; don't mix their guards in
+			return false
+		case *ast.AssignStmt:
+			trackAssignment(pass, node, tainted)
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if i < len(node.Values) {
+					if src, ok := sourceOf(pass, node.Values[i], tainted); ok {
+						tainted[name.Name] = withKey(src, name.Name)
+					}
+				}
+			}
+		case *ast.IfStmt:
+			guards = append(guards, collectGuards(node.Cond)...)
+		}
+		return true
+	})
+
+	// Second pass: find sinks sized by unvalidated values
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			checkMake(reporter, pass, node, tainted, guards)
+		case *ast.ForStmt:
+			checkForBound(reporter, pass, node, tainted, guards)
+		case *ast.RangeStmt:
+			checkRangeBound(reporter, pass, node, tainted, guards)
+		}
+		return true
+	})
+}
+
+// trackAssignment records LHS identifiers that receive config-derived values
+func trackAssignment(pass *analysis.Pass, assign *ast.AssignStmt, tainted map[string]sizeSource) {
+	// n, err := strconv.Atoi(s)
+	if len(assign.Rhs) == 1 && len(assign.Lhs) == 2 {
+		if call, ok := assign.Rhs[0].(*ast.CallExpr); ok && isParseCall(pass, call) {
+			if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+				tainted[ident.Name] = sizeSource{
+					keys:   []string{ident.Name},
+					origin: "strconv." + calleeName(call),
+				}
+			}
+			return
+		}
+	}
+
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+
+	for i, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+
+		src, ok := sourceOf(pass, assign.Rhs[i], tainted)
+		if !ok {
+			continue
+		}
+		tainted[ident.Name] = withKey(src, ident.Name)
+	}
+}
+
+// withKey returns a copy of src that also accepts guards on key
+func withKey(src sizeSource, key string) sizeSource {
+	keys := append([]string{key}, src.keys...)
+	return sizeSource{keys: keys, origin: src.origin}
+}
+
+// sourceOf reports whether expr derives from a config field or a tainted local
+func sourceOf(pass *analysis.Pass, expr ast.Expr, tainted map[string]sizeSource) (sizeSource, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return sourceOf(pass, e.X, tainted)
+	case *ast.Ident:
+		src, ok := tainted[e.Name]
+		return src, ok
+	case *ast.SelectorExpr:
+		if isConfigField(pass, e) {
+			key := types.ExprString(e)
+			return sizeSource{keys: []string{key}, origin: key}, true
+		}
+	case *ast.CallExpr:
+		// Conversions like int(cfg.Size) keep the taint
+		if len(e.Args) == 1 && isConversion(pass, e) {
+			return sourceOf(pass, e.Args[0], tainted)
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+			if src, ok := sourceOf(pass, e.X, tainted); ok {
+				return src, true
+			}
+			return sourceOf(pass, e.Y, tainted)
+		}
+	}
+	return sizeSource{}, false
+}
+
+// isConfigField checks if sel reads an integer field of a config struct
+func isConfigField(pass *analysis.Pass, sel *ast.SelectorExpr) bool {
+	fieldType := pass.TypesInfo.TypeOf(sel)
+	if fieldType == nil {
+		return false
+	}
+	basic, ok := fieldType.Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsInteger == 0 {
+		return false
+	}
+
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return false
+	}
+
+	recv := selection.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	name := named.Obj().Name()
+	for _, suffix := range configTypeSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isParseCall checks if call is strconv.Atoi / ParseInt / ParseUint
+func isParseCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	obj, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || obj.Pkg() == nil {
+		return false
+	}
+
+	return obj.Pkg().Path() == "strconv" && parseFuncs[obj.Name()]
+}
+
+// isConversion checks if call is a type conversion such as int(x)
+func isConversion(pass *analysis.Pass, call *ast.CallExpr) bool {
+	tv, ok := pass.TypesInfo.Types[call.Fun]
+	return ok && tv.IsType()
+}
+
+func calleeName(call *ast.CallExpr) string {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		return sel.Sel.Name
+	}
+	return types.ExprString(call.Fun)
+}
+
+// collectGuards extracts bound comparisons (x <= 0, x > max, ...) from an if condition
+func collectGuards(cond ast.Expr) []guard {
+	var guards []guard
+
+	ast.Inspect(cond, func(n ast.Node) bool {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+
+		switch bin.Op {
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			guards = append(guards,
+				guard{key: types.ExprString(bin.X), pos: bin.Pos()},
+				guard{key: types.ExprString(bin.Y), pos: bin.Pos()},
+			)
+		}
+		return true
+	})
+
+	return guards
+}
+
+// isGuarded checks if any key of src was bounds-checked before pos
+func isGuarded(src sizeSource, pos token.Pos, guards []guard) bool {
+	for _, g := range guards {
+		if g.pos >= pos {
+			continue
+		}
+		for _, key := range src.keys {
+			if g.key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkMake flags make(chan T, n) and make([]T, n) with unvalidated sizes
+func checkMake(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr, tainted map[string]sizeSource, guards []guard) {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "make" || len(call.Args) < 2 {
+		return
+	}
+	if _, isBuiltin := pass.TypesInfo.Uses[ident].(*types.Builtin); !isBuiltin {
+		return
+	}
+
+	switch call.Args[0].(type) {
+	case *ast.ChanType:
+		src, ok := sourceOf(pass, call.Args[1], tainted)
+		if !ok || isGuarded(src, call.Pos(), guards) {
+			return
+		}
+		reporter.Reportf(call.Args[1].Pos(),
+			"channel buffer size from %s is not validated; a negative value panics and 0 makes the channel unbuffered, check it is > 0 first",
+			src.origin)
+
+	case *ast.ArrayType:
+		for _, arg := range call.Args[1:] {
+			src, ok := sourceOf(pass, arg, tainted)
+			if !ok || isGuarded(src, call.Pos(), guards) {
+				continue
+			}
+			reporter.Reportf(arg.Pos(),
+				"slice size from %s is not validated; a negative value panics, check it is >= 0 first",
+				src.origin)
+			return
+		}
+	}
+}
+
+// checkForBound flags for i := 0; i < n; i++ with an unvalidated n
+func checkForBound(reporter *nolint.Reporter, pass *analysis.Pass, loop *ast.ForStmt, tainted map[string]sizeSource, guards []guard) {
+	bin, ok := loop.Cond.(*ast.BinaryExpr)
+	if !ok {
+		return
+	}
+
+	var bound ast.Expr
+	switch bin.Op {
+	case token.LSS, token.LEQ:
+		bound = bin.Y
+	case token.GTR, token.GEQ:
+		bound = bin.X
+	default:
+		return
+	}
+
+	src, ok := sourceOf(pass, bound, tainted)
+	if !ok || isGuarded(src, loop.Pos(), guards) {
+		return
+	}
+
+	reporter.Reportf(bound.Pos(),
+		"loop bound from %s is not validated; a zero or negative value runs no iterations, check it is > 0 first",
+		src.origin)
+}
+
+// checkRangeBound flags for range n (Go 1.22 range-over-int) with an unvalidated n
+func checkRangeBound(reporter *nolint.Reporter, pass *analysis.Pass, loop *ast.RangeStmt, tainted map[string]sizeSource, guards []guard) {
+	t := pass.TypesInfo.TypeOf(loop.X)
+	if t == nil {
+		return
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsInteger == 0 {
+		return
+	}
+
+	src, ok := sourceOf(pass, loop.X, tainted)
+	if !ok || isGuarded(src, loop.Pos(), guards) {
+		return
+	}
+
+	reporter.Reportf(loop.X.Pos(),
+		"loop bound from %s is not validated; a zero or negative value runs no iterations, check it is > 0 first",
+		src.origin)
+}
@@ -0,0 +1,14 @@
+package chancap_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/chancap"
+)
+
+func TestChanCapAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, chancap.Analyzer, "a")
+}
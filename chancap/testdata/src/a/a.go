@@ -0,0 +1,65 @@
+package a
+
+import (
+	"os"
+	"strconv"
+)
+
+type Config struct {
+	QueueSize int
+	Workers   int
+	Name      string
+}
+
+type Job struct{}
+
+const defaultQueueSize = 64
+
+// Bad: buffer size straight from config
+func BadChannel(cfg *Config) chan Job {
+	return make(chan Job, cfg.QueueSize) // want `channel buffer size from cfg.QueueSize is not validated`
+}
+
+// Bad: flows through a local
+func BadSlice(cfg Config) []Job {
+	n := cfg.QueueSize * 2
+	return make([]Job, 0, n) // want `slice size from cfg.QueueSize is not validated`
+}
+
+// Bad: env-derived worker count
+func BadWorkers() {
+	workers, _ := strconv.Atoi(os.Getenv("WORKERS"))
+	for i := 0; i < workers; i++ { // want `loop bound from strconv.Atoi is not validated`
+		go func() {}()
+	}
+}
+
+// Bad: range over config int
+func BadRange(cfg *Config) {
+	for range cfg.Workers { // want `loop bound from cfg.Workers is not validated`
+	}
+}
+
+// Good: validated with a default
+func GoodChannel(cfg *Config) chan Job {
+	size := cfg.QueueSize
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	return make(chan Job, size)
+}
+
+// Good: validated directly on the field
+func GoodWorkers(cfg *Config) {
+	if cfg.Workers < 1 || cfg.Workers > 128 {
+		return
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		go func() {}()
+	}
+}
+
+// Good: constant size
+func GoodConstant() chan Job {
+	return make(chan Job, defaultQueueSize)
+}
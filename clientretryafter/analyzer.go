@@ -0,0 +1,259 @@
+// Package clientretryafter provides an analyzer that checks outbound HTTP
+// response handling classifies status codes and respects Retry-After.
+//
+// A client that treats every non-200 response the same retries 400s that will
+// never succeed and hammers upstreams that answered 429 or 503 with a
+// Retry-After header asking it to back off.
+package clientretryafter
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check outbound HTTP clients classify status codes and respect Retry-After
+
+This analyzer detects:
+1. Response handling that only compares resp.StatusCode against 200 and
+   treats every other status the same
+2. The same check inside a retry loop, which retries 4xx responses that
+   will never succeed
+3. Packages that handle 429 Too Many Requests but never read the
+   Retry-After header
+
+Bad:
+    for attempt := 0; attempt < 3; attempt++ {
+        resp, err := client.Do(req)
+        ...
+        if resp.StatusCode != http.StatusOK {
+            continue
+        }
+    }
+
+Good:
+    switch {
+    case resp.StatusCode == http.StatusTooManyRequests:
+        wait := retryAfter(resp.Header.Get("Retry-After"))
+        ...
+    case resp.StatusCode >= 500:
+        // retry with backoff
+    case resp.StatusCode >= 400:
+        return fmt.Errorf("request rejected: %s", resp.Status)
+    }`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "clientretryafter",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.BasicLit)(nil),
+		(*ast.SelectorExpr)(nil),
+	}
+
+	// Package-wide facts for the Retry-After check
+	var firstTooMany ast.Node
+	readsRetryAfter := false
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Body != nil {
+				checkStatusHandling(reporter, pass, node.Body)
+			}
+		case *ast.FuncLit:
+			checkStatusHandling(reporter, pass, node.Body)
+		case *ast.BasicLit:
+			switch {
+			case node.Kind == token.STRING && isRetryAfterLiteral(node):
+				readsRetryAfter = true
+			case node.Kind == token.INT && node.Value == "429" && firstTooMany == nil:
+				firstTooMany = node
+			}
+		case *ast.SelectorExpr:
+			if firstTooMany == nil && isTooManyRequests(pass, node) {
+				firstTooMany = node
+			}
+		}
+	})
+
+	if firstTooMany != nil && !readsRetryAfter {
+		reporter.Reportf(firstTooMany.Pos(),
+			"429 Too Many Requests is handled but Retry-After is never read; wait as long as the upstream asks before retrying")
+	}
+
+	return nil, nil
+}
+
+// isRetryAfterLiteral checks for the "Retry-After" header name in any case
+func isRetryAfterLiteral(lit *ast.BasicLit) bool {
+	v := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+	if v.Kind() != constant.String {
+		return false
+	}
+	return strings.EqualFold(constant.StringVal(v), "Retry-After")
+}
+
+// isTooManyRequests checks for http.StatusTooManyRequests
+func isTooManyRequests(pass *analysis.Pass, sel *ast.SelectorExpr) bool {
+	c, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Const)
+	return ok && c.Pkg() != nil && c.Pkg().Path() == "net/http" && c.Name() == "StatusTooManyRequests"
+}
+
+// isStatusCode checks if expr is the StatusCode field of an *http.Response
+func isStatusCode(pass *analysis.Pass, expr ast.Expr) bool {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "StatusCode" {
+		return false
+	}
+
+	t := pass.TypesInfo.TypeOf(sel.X)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == "Response"
+}
+
+// intValue returns the constant integer value of expr
+func intValue(pass *analysis.Pass, expr ast.Expr) (int64, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(tv.Value)
+}
+
+// isSingleOKCheck checks for resp.StatusCode ==/!= 200
+func isSingleOKCheck(pass *analysis.Pass, bin *ast.BinaryExpr) bool {
+	if bin.Op != token.EQL && bin.Op != token.NEQ {
+		return false
+	}
+	for _, pair := range [][2]ast.Expr{{bin.X, bin.Y}, {bin.Y, bin.X}} {
+		if isStatusCode(pass, pair[0]) {
+			if v, ok := intValue(pass, pair[1]); ok && v == 200 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isHTTPCall checks for client.Do/Get/Post/Head/PostForm or the http package
+// functions of the same name
+func isHTTPCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "net/http" {
+		return false
+	}
+
+	switch fn.Name() {
+	case "Do", "Get", "Post", "Head", "PostForm":
+		return true
+	}
+	return false
+}
+
+// checkStatusHandling flags functions whose only status classification is a
+// single comparison against 200
+func checkStatusHandling(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	statusUses := 0
+	var okCheck *ast.BinaryExpr
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Analyzed separately
+			return false
+		case *ast.BinaryExpr:
+			if okCheck == nil && isSingleOKCheck(pass, node) {
+				okCheck = node
+			}
+		case *ast.SelectorExpr:
+			if isStatusCode(pass, node) {
+				statusUses++
+			}
+		}
+		return true
+	})
+
+	// Any other use of StatusCode (a switch, a range check, logging it)
+	// means the function distinguishes more than success and failure
+	if okCheck == nil || statusUses != 1 {
+		return
+	}
+
+	if loop := enclosingRetryLoop(pass, body, okCheck); loop != nil {
+		reporter.Reportf(okCheck.Pos(),
+			"retry loop treats every non-200 status alike and retries 4xx responses that will never succeed; "+
+				"fail fast on 4xx and back off on 429/5xx")
+		return
+	}
+
+	reporter.Reportf(okCheck.Pos(),
+		"only 200 is distinguished from other status codes; classify 429 and 5xx (retryable) separately from 4xx (permanent)")
+}
+
+// enclosingRetryLoop returns the innermost loop around node that also makes an
+// HTTP request
+func enclosingRetryLoop(pass *analysis.Pass, body *ast.BlockStmt, node ast.Node) ast.Node {
+	var loop ast.Node
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.Pos() > node.Pos() || n.End() < node.End() {
+			return false
+		}
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			if callsHTTP(pass, stmt.Body) {
+				loop = stmt
+			}
+		case *ast.RangeStmt:
+			if callsHTTP(pass, stmt.Body) {
+				loop = stmt
+			}
+		}
+		return true
+	})
+
+	return loop
+}
+
+// callsHTTP checks if a block makes an outbound HTTP request
+func callsHTTP(pass *analysis.Pass, block *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(block, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isHTTPCall(pass, call) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
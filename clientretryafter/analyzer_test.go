@@ -0,0 +1,14 @@
+package clientretryafter_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/clientretryafter"
+)
+
+func TestClientRetryAfterAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, clientretryafter.Analyzer, "a", "classified")
+}
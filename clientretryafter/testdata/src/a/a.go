@@ -0,0 +1,57 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Bad: naive 200-or-error handling inside a retry loop
+func FetchWithRetry(client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			time.Sleep(time.Second)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK { // want `retry loop treats every non-200 status alike`
+			resp.Body.Close()
+			time.Sleep(time.Second)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, errors.New("giving up")
+}
+
+// Bad: single 200 check outside a loop
+func Fetch(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 200 { // want `only 200 is distinguished from other status codes`
+		return nil
+	}
+	return errors.New("request failed")
+}
+
+// Bad: 429 handled but Retry-After never read
+func IsThrottled(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests // want `429 Too Many Requests is handled but Retry-After is never read`
+}
+
+// Good: the status is reported, so callers can classify it
+func FetchStatus(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package classified
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var errRetry = errors.New("retryable")
+
+// Good: status-class switch with Retry-After
+func Fetch(client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			resp.Body.Close()
+			time.Sleep(retryAfter(resp.Header.Get("Retry-After")))
+		case resp.StatusCode >= 500:
+			resp.Body.Close()
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		case resp.StatusCode >= 400:
+			resp.Body.Close()
+			return nil, fmt.Errorf("request rejected: %s", resp.Status)
+		default:
+			return resp, nil
+		}
+	}
+	return nil, errRetry
+}
+
+func retryAfter(v string) time.Duration {
+	seconds, err := strconv.Atoi(v)
+	if err != nil {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
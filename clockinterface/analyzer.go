@@ -12,8 +12,10 @@
 package clockinterface
 
 import (
+	"flag"
 	"go/ast"
 	"go/types"
+	"path"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -46,15 +48,31 @@ Example of the recommended pattern:
 	type MockClock struct { mock.Mock }
 	func (m *MockClock) Now() time.Time { return m.Called().Get(0).(time.Time) }
 
-Functions that need time should accept a Clock parameter or have it injected.`
+Functions that need time should accept a Clock parameter or have it injected.
+Methods whose receiver already has a clock field providing the same method
+are not reported, since the type was designed for an injected clock.
+
+Flags:
+    -exempt-packages  comma-separated globs of package paths where direct
+                      time calls are intended, like internal/telemetry`
+
+var exemptPackages string
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "clockinterface",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("clockinterface", flag.ExitOnError)
+	fs.StringVar(&exemptPackages, "exempt-packages", "",
+		"comma-separated globs of package paths where direct time calls are intended")
+	return *fs
+}
+
 // ExemptPackages are packages where time.Now is acceptable
 var ExemptPackages = []string{
 	"main",  // Entry points are fine
@@ -102,6 +120,10 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		}
 	}
 
+	if isExemptPath(pkgPath) {
+		return nil, nil
+	}
+
 	// Track if there's a Clock interface defined
 	hasClockInterface := false
 	nodeFilter := []ast.Node{
@@ -146,6 +168,8 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
+		clock := receiverClock(pass, fn)
+
 		ast.Inspect(fn.Body, func(n ast.Node) bool {
 			call, ok := n.(*ast.CallExpr)
 			if !ok {
@@ -163,6 +187,10 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			}
 
 			if ident.Name == "time" {
+				if clock != nil && clockProvides(clock, sel.Sel.Name) {
+					return true
+				}
+
 				switch sel.Sel.Name {
 				case "Now":
 					suggestion := "inject a Clock interface for testability"
@@ -212,16 +240,71 @@ func hasClockParameter(fn *ast.FuncDecl) bool {
 		}
 	}
 
-	// Also check if receiver type has a clock field
-	if fn.Recv != nil && len(fn.Recv.List) > 0 {
-		// We'd need type info to check struct fields, so just check naming
-		recvType := types.ExprString(fn.Recv.List[0].Type)
-		_ = recvType // Could enhance to check if receiver struct has clock field
-	}
+	return false
+}
 
+// isExemptPath checks if pkgPath matches one of the -exempt-packages globs.
+// A glob matches a run of whole path elements, so internal/telemetry covers
+// example.com/app/internal/telemetry and the packages below it.
+func isExemptPath(pkgPath string) bool {
+	elems := strings.Split(pkgPath, "/")
+	for _, glob := range strings.Split(exemptPackages, ",") {
+		glob = strings.Trim(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+		n := strings.Count(glob, "/") + 1
+		for i := 0; i+n <= len(elems); i++ {
+			if ok, _ := path.Match(glob, strings.Join(elems[i:i+n], "/")); ok {
+				return true
+			}
+		}
+	}
 	return false
 }
 
+// receiverClock returns the clock field of the struct fn is a method of, if
+// it has one. Fields whose type is named Clock or ends in Clock, like
+// clockwork.Clock or clock.PassiveClock, count.
+func receiverClock(pass *analysis.Pass, fn *ast.FuncDecl) *types.Var {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return nil
+	}
+
+	t := pass.TypesInfo.TypeOf(fn.Recv.List[0].Type)
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		ft := types.Unalias(field.Type())
+		if ptr, ok := ft.(*types.Pointer); ok {
+			ft = ptr.Elem()
+		}
+		if named, ok := ft.(*types.Named); ok && strings.HasSuffix(named.Obj().Name(), "Clock") {
+			return field
+		}
+	}
+	return nil
+}
+
+// clockProvides reports whether the clock field has a method named like the
+// time function, so the type was designed for an injected clock and the
+// direct call is a deliberate exception
+func clockProvides(clock *types.Var, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(clock.Type(), true, clock.Pkg(), name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
 // ClockPatternInfo contains information about clock usage in a package
 type ClockPatternInfo struct {
 	HasClockInterface    bool
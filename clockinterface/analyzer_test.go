@@ -0,0 +1,25 @@
+package clockinterface_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/clockinterface"
+)
+
+func TestClockInterfaceReceiverField(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, clockinterface.Analyzer, "receiver")
+}
+
+func TestClockInterfaceExemptPackages(t *testing.T) {
+	if err := clockinterface.Analyzer.Flags.Set("exempt-packages", "internal/telemetry"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = clockinterface.Analyzer.Flags.Set("exempt-packages", "") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, clockinterface.Analyzer,
+		"example.com/app/internal/telemetry", "example.com/app/internal/store")
+}
@@ -0,0 +1,7 @@
+package store
+
+import "time"
+
+func Stamp() time.Time {
+	return time.Now() // want `direct time.Now\(\) call in business logic; inject a Clock interface for testability`
+}
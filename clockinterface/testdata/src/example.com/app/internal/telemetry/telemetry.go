@@ -0,0 +1,7 @@
+package telemetry
+
+import "time"
+
+func Stamp() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,15 @@
+package clockwork
+
+import "time"
+
+type Ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+	NewTicker(d time.Duration) Ticker
+}
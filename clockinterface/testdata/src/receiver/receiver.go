@@ -0,0 +1,54 @@
+package receiver
+
+import (
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type Service struct {
+	clock Clock
+}
+
+// Types holding a clock field are designed for injection; a direct call
+// there is a deliberate exception
+func (s *Service) Expired(deadline time.Time) bool {
+	return time.Now().After(deadline)
+}
+
+func (s *Service) Wait() {
+	<-time.After(time.Second)
+}
+
+// Clock has no Sleep, so the general message applies
+func (s *Service) Pause() {
+	time.Sleep(time.Second) // want `time.Sleep\(\) in business logic is usually a code smell`
+}
+
+type Worker struct {
+	clk clockwork.Clock
+}
+
+func (w *Worker) Rest() {
+	time.Sleep(time.Second)
+}
+
+func (w *Worker) Tick() *time.Ticker {
+	return time.NewTicker(time.Second)
+}
+
+// The receiver name does not matter
+func (*Worker) Started() time.Time {
+	return time.Now()
+}
+
+type Plain struct{}
+
+func (p *Plain) Stamp() time.Time {
+	return time.Now() // want `direct time.Now\(\) call in business logic; use the Clock interface defined in this package`
+}
@@ -5,7 +5,11 @@
 package closurecomplexity
 
 import (
+	"flag"
+	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -43,29 +47,76 @@ Bad pattern:
     }()
 
 This analyzer flags:
-1. Closures with more than 10 statements
-2. Closures with nesting depth > 2
-3. Closures capturing many variables (> 3)
+1. Closures with cyclomatic complexity > 8
+2. Closures with more than 15 statements
+3. Closures with nesting depth > 2
+4. Closures capturing many variables (> 5)
+
+Cyclomatic complexity is 1 plus the number of decision points: if, for,
+range, case and select clauses other than default, and && and ||. A switch
+or select whose clauses contain no decision points, like one mapping enum
+values, counts as a single decision point. A closure that is long but within
+the complexity limit is reported as advisory only.
+
+Closures passed to callbacks that run them out of line, like errgroup's
+g.Go, t.Cleanup, sync.OnceFunc and retry.Do, and closures converted to a
+named function type, like http.HandlerFunc, are exempt: extracting them
+would lose the error or state they capture.
 
 Note: Test files are skipped, as table-driven tests commonly use
-longer closures for setup, fixtures, and mock configuration.`
+longer closures for setup, fixtures, and mock configuration.
+
+Flags:
+    -max-complexity    maximum cyclomatic complexity of a closure (default 8)
+    -max-statements    maximum statements in a closure (default 15)
+    -max-nesting       maximum nesting depth in a closure (default 2)
+    -max-captured      maximum variables captured from the outer scope (default 5)
+    -exempt-callbacks  comma-separated functions whose closure arguments are exempt
+                       (default Go,Cleanup,OnceFunc,OnceValue,HandlerFunc,Do,RetryNotify)`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "closurecomplexity",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
 const (
-	// MaxClosureStatements is the maximum statements allowed in a closure
+	// MaxClosureComplexity is the default maximum cyclomatic complexity of a closure
+	MaxClosureComplexity = 8
+	// MaxClosureStatements is the default maximum statements allowed in a closure
 	MaxClosureStatements = 15
-	// MaxClosureNesting is the maximum nesting depth in a closure
+	// MaxClosureNesting is the default maximum nesting depth in a closure
 	MaxClosureNesting = 2
-	// MaxCapturedVars is the maximum variables captured from outer scope
+	// MaxCapturedVars is the default maximum variables captured from outer scope
 	MaxCapturedVars = 5
 )
 
+// defaultExemptCallbacks are the callbacks exempt without configuration
+const defaultExemptCallbacks = "Go,Cleanup,OnceFunc,OnceValue,HandlerFunc,Do,RetryNotify"
+
+// Configured limits, defaulting to the constants above
+var (
+	maxComplexity int
+	maxStatements int
+	maxNesting    int
+	maxCaptured   int
+
+	exemptCallbacks string
+)
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("closurecomplexity", flag.ExitOnError)
+	fs.IntVar(&maxComplexity, "max-complexity", MaxClosureComplexity, "maximum cyclomatic complexity of a closure")
+	fs.IntVar(&maxStatements, "max-statements", MaxClosureStatements, "maximum statements in a closure")
+	fs.IntVar(&maxNesting, "max-nesting", MaxClosureNesting, "maximum nesting depth in a closure")
+	fs.IntVar(&maxCaptured, "max-captured", MaxCapturedVars, "maximum variables captured from the outer scope")
+	fs.StringVar(&exemptCallbacks, "exempt-callbacks", defaultExemptCallbacks,
+		"comma-separated functions whose closure arguments are exempt")
+	return *fs
+}
+
 // exemptCobraFields are struct fields in Cobra commands that commonly have large closures
 var exemptCobraFields = map[string]bool{
 	"RunE":              true,
@@ -105,6 +156,7 @@ var exemptVisitorFuncs = map[string]bool{
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	callbacks := parseCallbacks(exemptCallbacks)
 
 	var currentFunc *ast.FuncDecl
 	var inTestFile bool
@@ -153,9 +205,12 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			}
 
 		case *ast.CallExpr:
-			// Check for visitor pattern callbacks (e.g., ast.Inspect, f.VisitAll)
+			// Check for visitor pattern callbacks (e.g., ast.Inspect, f.VisitAll),
+			// the -exempt-callbacks (e.g., g.Go, t.Cleanup), and conversions to
+			// named function types (e.g., http.HandlerFunc(func...))
 			funcName := getCallFuncName(node)
-			if exemptVisitorFuncs[funcName] {
+			if exemptVisitorFuncs[funcName] || isExemptCallback(node, callbacks) ||
+				isFuncTypeConversion(pass, node) {
 				for _, arg := range node.Args {
 					if funcLit, ok := arg.(*ast.FuncLit); ok {
 						exemptClosures[funcLit] = true
@@ -210,31 +265,115 @@ func checkClosure(reporter *nolint.Reporter, closure *ast.FuncLit, parentFunc *a
 		return
 	}
 
-	// Count statements
+	complexity := cyclomaticComplexity(closure.Body)
+	if complexity > maxComplexity {
+		reporter.Reportf(closure.Pos(),
+			"closure has cyclomatic complexity of %d (max %d); extract the branching logic into a named function for testability",
+			complexity, maxComplexity)
+	}
+
+	// Count statements; a long closure with little branching, like a flat
+	// switch, is only advisory
 	stmtCount := countStatements(closure.Body)
-	if stmtCount > MaxClosureStatements {
+	switch {
+	case stmtCount <= maxStatements:
+	case complexity <= maxComplexity:
+		reporter.Report(&analysis.Diagnostic{
+			Pos:      closure.Pos(),
+			Category: nolint.CategoryAdvisory,
+			Message: fmt.Sprintf("closure has %d statements (max %d) but little branching; consider extracting it into a named function",
+				stmtCount, maxStatements),
+		})
+	default:
 		reporter.Reportf(closure.Pos(),
 			"closure has %d statements (max %d); extract complex logic into a named function for testability",
-			stmtCount, MaxClosureStatements)
+			stmtCount, maxStatements)
 	}
 
 	// Check nesting depth
 	depth := maxNestingDepth(closure.Body, 0)
-	if depth > MaxClosureNesting {
+	if depth > maxNesting {
 		reporter.Reportf(closure.Pos(),
 			"closure has nesting depth of %d (max %d); extract into a named function",
-			depth, MaxClosureNesting)
+			depth, maxNesting)
 	}
 
 	// Count captured variables
 	if parentFunc != nil {
 		captured := countCapturedVars(closure, parentFunc)
-		if captured > MaxCapturedVars {
+		if captured > maxCaptured {
 			reporter.Reportf(closure.Pos(),
 				"closure captures %d variables from outer scope (max %d); consider passing them as parameters or extracting to a named function",
-				captured, MaxCapturedVars)
+				captured, maxCaptured)
+		}
+	}
+}
+
+// cyclomaticComplexity returns 1 plus the number of decision points in
+// body, not counting nested closures
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	return 1 + decisionPoints(body)
+}
+
+func decisionPoints(node ast.Node) int {
+	count := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			count++
+		case *ast.SwitchStmt:
+			if isFlat(n.Body) {
+				count++
+				return false
+			}
+		case *ast.TypeSwitchStmt:
+			if isFlat(n.Body) {
+				count++
+				return false
+			}
+		case *ast.SelectStmt:
+			if isFlat(n.Body) {
+				count++
+				return false
+			}
+		case *ast.CaseClause:
+			if n.List != nil {
+				count++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				count++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// isFlat checks if no clause of a switch or select body contains a
+// decision point
+func isFlat(body *ast.BlockStmt) bool {
+	for _, stmt := range body.List {
+		var clauseBody []ast.Stmt
+		switch clause := stmt.(type) {
+		case *ast.CaseClause:
+			clauseBody = clause.Body
+		case *ast.CommClause:
+			clauseBody = clause.Body
+		}
+		for _, s := range clauseBody {
+			if decisionPoints(s) > 0 {
+				return false
+			}
 		}
 	}
+	return true
 }
 
 func countStatements(block *ast.BlockStmt) int {
@@ -440,6 +579,51 @@ func isBuiltinOrCommon(name string) bool {
 	return builtins[name]
 }
 
+// parseCallbacks splits the -exempt-callbacks list into a set
+func parseCallbacks(list string) map[string]bool {
+	callbacks := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			callbacks[name] = true
+		}
+	}
+	return callbacks
+}
+
+// isExemptCallback checks if call calls one of the -exempt-callbacks.
+// Entries match the function name alone, like Do, or qualified by the
+// package or receiver it is selected from, like retry.Do.
+func isExemptCallback(call *ast.CallExpr, callbacks map[string]bool) bool {
+	name := getCallFuncName(call)
+	if name == "" {
+		return false
+	}
+	if callbacks[name] {
+		return true
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if x, ok := sel.X.(*ast.Ident); ok {
+			return callbacks[x.Name+"."+name]
+		}
+	}
+	return false
+}
+
+// isFuncTypeConversion checks if call converts a value to a named function
+// type, like http.HandlerFunc
+func isFuncTypeConversion(pass *analysis.Pass, call *ast.CallExpr) bool {
+	tv, ok := pass.TypesInfo.Types[call.Fun]
+	if !ok || !tv.IsType() {
+		return false
+	}
+	named, ok := types.Unalias(tv.Type).(*types.Named)
+	if !ok {
+		return false
+	}
+	_, ok = named.Underlying().(*types.Signature)
+	return ok
+}
+
 // getCallFuncName extracts the function name from a call expression
 func getCallFuncName(call *ast.CallExpr) string {
 	switch fn := call.Fun.(type) {
@@ -0,0 +1,46 @@
+package closurecomplexity_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/closurecomplexity"
+)
+
+func TestClosureComplexity(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, closurecomplexity.Analyzer, "closures")
+}
+
+func TestClosureComplexityLimits(t *testing.T) {
+	for name, value := range map[string]string{"max-complexity": "2", "max-statements": "5"} {
+		if err := closurecomplexity.Analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		_ = closurecomplexity.Analyzer.Flags.Set("max-complexity", "8")
+		_ = closurecomplexity.Analyzer.Flags.Set("max-statements", "15")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, closurecomplexity.Analyzer, "limits")
+}
+
+func TestClosureComplexityCallbacks(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, closurecomplexity.Analyzer, "callbacks")
+}
+
+func TestClosureComplexityExemptCallbacks(t *testing.T) {
+	if err := closurecomplexity.Analyzer.Flags.Set("exempt-callbacks", "retry.Do"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = closurecomplexity.Analyzer.Flags.Set("exempt-callbacks", "Go,Cleanup,OnceFunc,OnceValue,HandlerFunc,Do,RetryNotify")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, closurecomplexity.Analyzer, "retried")
+}
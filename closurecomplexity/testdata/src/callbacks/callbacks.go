@@ -0,0 +1,136 @@
+package callbacks
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Fetch runs a branchy closure per URL in an errgroup.
+func Fetch(urls []string, limit int, strict bool) error {
+	var g errgroup.Group
+	for _, url := range urls {
+		g.Go(func() error {
+			if url == "" && strict {
+				return errors.New("empty url")
+			}
+			if len(url) > limit || limit < 0 {
+				return errors.New("url too long")
+			}
+			if url[0] == '/' || url[0] == '.' {
+				return nil
+			}
+			if strict && url[0] != 'h' {
+				return errors.New("not http")
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// Setup registers a branchy cleanup.
+func Setup(t *testing.T, dirs []string, keep, verbose bool) {
+	t.Cleanup(func() {
+		for _, dir := range dirs {
+			if keep && verbose {
+				t.Log("keeping", dir)
+				continue
+			}
+			if dir == "" || dir == "/" || dir == "." {
+				continue
+			}
+			if verbose || testing.Verbose() {
+				t.Log("removing", dir)
+			}
+		}
+	})
+}
+
+// Loader loads a value once with a branchy closure.
+func Loader(path string, fallback, strict bool) func() string {
+	load := sync.OnceValue(func() string {
+		if path == "" && fallback {
+			return "default"
+		}
+		if len(path) > 10 || strict {
+			return "long"
+		}
+		if path[0] == '/' || path[0] == '.' {
+			return "local"
+		}
+		if strict && fallback {
+			return "strict"
+		}
+		return path
+	})
+	return load
+}
+
+// Handler converts a branchy closure to http.HandlerFunc.
+func Handler(admin, debug bool) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if admin || debug {
+			w.Header().Set("X-Admin", "true")
+		}
+		if r.URL.Path == "/" || r.URL.Path == "" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if debug && r.URL.Query().Has("trace") {
+			w.Header().Set("X-Trace", "true")
+		}
+	})
+	return h
+}
+
+// Visitor is a named function type.
+type Visitor func(name string) bool
+
+// Visit converts a branchy closure to a named function type.
+func Visit(hidden, strict bool) Visitor {
+	v := Visitor(func(name string) bool {
+		if name == "" || name == "." {
+			return false
+		}
+		if hidden && name[0] == '.' {
+			return true
+		}
+		if strict || len(name) > 10 {
+			return false
+		}
+		if name[0] == '_' && !hidden {
+			return false
+		}
+		return true
+	})
+	return v
+}
+
+func apply(f func(string) bool, name string) bool { return f(name) }
+
+// Check passes a branchy closure to a function that is not exempt.
+func Check(name string, hidden, strict bool) bool {
+	return apply(func(name string) bool { // want `closure has cyclomatic complexity of 9 \(max 8\)`
+		if name == "" || name == "." {
+			return false
+		}
+		if hidden && name[0] == '.' {
+			return true
+		}
+		if strict || len(name) > 10 {
+			return false
+		}
+		if name[0] == '_' && !hidden {
+			return false
+		}
+		return true
+	}, name)
+}
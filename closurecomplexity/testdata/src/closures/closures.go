@@ -0,0 +1,105 @@
+package closures
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+	Fatal
+	Panic
+	Trace
+	Audit
+)
+
+// Names maps levels to names with a long but flat switch.
+func Names(levels []Level) []string {
+	name := func(l Level) string { // want `closure has 20 statements \(max 15\) but little branching`
+		switch l {
+		case Debug:
+			return "debug"
+		case Info:
+			return "info"
+		case Warn:
+			return "warn"
+		case Error:
+			return "error"
+		case Fatal:
+			return "fatal"
+		case Panic:
+			return "panic"
+		case Trace:
+			return "trace"
+		case Audit:
+			return "audit"
+		}
+		return "unknown"
+	}
+
+	var names []string
+	for _, l := range levels {
+		names = append(names, name(l))
+	}
+	return names
+}
+
+// Allowed decides access with a short closure full of branches.
+func Allowed(user string, admin, owner, banned bool, quota int) bool {
+	check := func(action string) bool { // want `closure has cyclomatic complexity of 10 \(max 8\)`
+		if banned && action != "read" {
+			return false
+		}
+		if admin || owner {
+			return true
+		}
+		if action == "read" || action == "list" {
+			return quota > 0 && user != ""
+		}
+		if action == "write" && quota > 10 {
+			return true
+		}
+		return false
+	}
+	return check("read")
+}
+
+// Sum uses a simple closure.
+func Sum(values []int) int {
+	total := 0
+	add := func(v int) {
+		if v > 0 {
+			total += v
+		}
+	}
+	for _, v := range values {
+		add(v)
+	}
+	return total
+}
+
+// Dispatch has a switch whose clauses branch, so every case counts.
+func Dispatch(kind string, n int) int {
+	handle := func() int { // want `closure has cyclomatic complexity of 9 \(max 8\)` `closure has 20 statements \(max 15\); extract complex logic`
+		switch kind {
+		case "a":
+			if n > 0 {
+				return 1
+			}
+		case "b":
+			return 2
+		case "c":
+			return 3
+		case "d":
+			return 4
+		case "e":
+			return 5
+		case "f":
+			return 6
+		case "g":
+			return 7
+		}
+		return 0
+	}
+	return handle()
+}
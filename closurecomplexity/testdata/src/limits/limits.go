@@ -0,0 +1,20 @@
+package limits
+
+// Clamp uses a closure within the default limits.
+func Clamp(values []int, lo, hi int) []int {
+	clamp := func(v int) int { // want `closure has cyclomatic complexity of 3 \(max 2\)` `closure has 8 statements \(max 5\); extract complex logic`
+		if v < lo {
+			return lo
+		}
+		if v > hi {
+			return hi
+		}
+		return v
+	}
+
+	out := make([]int, 0, len(values))
+	for _, v := range values {
+		out = append(out, clamp(v))
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package retried
+
+import (
+	"errors"
+
+	"retry"
+)
+
+// Send retries a branchy closure with retry.Do.
+func Send(payload []byte, limit int, strict bool) error {
+	return retry.Do(3, func() error {
+		if len(payload) == 0 && strict {
+			return errors.New("empty payload")
+		}
+		if len(payload) > limit || limit < 0 {
+			return errors.New("payload too large")
+		}
+		if payload[0] == '{' || payload[0] == '[' {
+			return nil
+		}
+		if strict && payload[0] != '<' {
+			return errors.New("unknown format")
+		}
+		return nil
+	})
+}
+
+type queue struct{}
+
+func (queue) Do(f func() error) error { return f() }
+
+// Drain passes a branchy closure to a Do method that is not retry.Do.
+func Drain(q queue, items []string, limit int, strict bool) error {
+	return q.Do(func() error { // want `closure has cyclomatic complexity of 9 \(max 8\)`
+		if len(items) == 0 && strict {
+			return errors.New("empty queue")
+		}
+		if len(items) > limit || limit < 0 {
+			return errors.New("queue too long")
+		}
+		if items[0] == "" || items[0] == "-" {
+			return nil
+		}
+		if strict && items[0] != "ok" {
+			return errors.New("bad item")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,12 @@
+package retry
+
+// Do calls f until it succeeds or attempts are exhausted.
+func Do(attempts int, f func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = f(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
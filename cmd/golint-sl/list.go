@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/spechtlabs/golint-sl/analyzers"
+	"github.com/spechtlabs/golint-sl/internal/config"
+	"github.com/spechtlabs/golint-sl/internal/driver"
+)
+
+// listEntry is one analyzer in the output of golint-sl list
+type listEntry struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+
+	// status explains Enabled in the table: yes, no, no (opt-in), or
+	// paths when only a paths rule enables the analyzer
+	status string
+}
+
+// list implements golint-sl list, which prints every analyzer with its
+// category, the first line of its documentation, and whether cfg enables it.
+// It returns the exit code.
+func list(w io.Writer, args []string, cfg *config.Config, all []*analysis.Analyzer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print a JSON array instead of a table")
+	category := fs.String("category", "", "only list analyzers in this category, like kubernetes or error-handling")
+
+	// Read by configFlags before the config is loaded
+	fs.String("config", "", "configuration file to use instead of searching for "+config.ConfigFileName)
+	fs.Bool("v", false, "print the configuration file in use")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return driver.ExitClean
+		}
+		return driver.ExitError
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "golint-sl list: unexpected argument %q\n", fs.Arg(0))
+		return driver.ExitError
+	}
+
+	var only *analyzers.Category
+	if *category != "" {
+		var slugs []string
+		for _, c := range analyzers.Categories() {
+			if strings.EqualFold(*category, c.Slug) || strings.EqualFold(*category, c.Name) {
+				only = &c
+				break
+			}
+			slugs = append(slugs, c.Slug)
+		}
+		if only == nil {
+			fmt.Fprintf(os.Stderr, "golint-sl list: unknown category %q; use one of %s\n", *category, strings.Join(slugs, ", "))
+			return driver.ExitError
+		}
+	}
+
+	entries := listEntries(cfg, all, only)
+
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Fprintf(os.Stderr, "golint-sl list: %v\n", err)
+			return driver.ExitError
+		}
+		return driver.ExitClean
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCATEGORY\tENABLED\tDESCRIPTION")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Name, e.Category, e.status, e.Description)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "golint-sl list: %v\n", err)
+		return driver.ExitError
+	}
+	return driver.ExitClean
+}
+
+// listEntries describes the analyzers in all, in order, keeping only those
+// in category if it is not nil
+func listEntries(cfg *config.Config, all []*analysis.Analyzer, category *analyzers.Category) []listEntry {
+	runs := make(map[string]bool)
+	for _, a := range cfg.FilterAnalyzers(all) {
+		runs[a.Name] = true
+	}
+
+	entries := []listEntry{}
+	for _, a := range all {
+		c, _ := analyzers.CategoryOf(a.Name)
+		if category != nil && c.Slug != category.Slug {
+			continue
+		}
+
+		description, _, _ := strings.Cut(a.Doc, "\n")
+		e := listEntry{
+			Name:        a.Name,
+			Category:    c.Name,
+			Description: description,
+			Enabled:     runs[a.Name],
+		}
+
+		switch {
+		case cfg.IsEnabled(a.Name):
+			e.status = "yes"
+		case runs[a.Name]:
+			e.status = "paths"
+		case cfg.OptIn[a.Name] && !explicitlyDisabled(cfg, a.Name):
+			e.status = "no (opt-in)"
+		default:
+			e.status = "no"
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// explicitlyDisabled checks if the analyzers section disables name by name
+func explicitlyDisabled(cfg *config.Config, name string) bool {
+	val, ok := cfg.Analyzers[name]
+	return ok && !val
+}
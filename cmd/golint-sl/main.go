@@ -7,12 +7,28 @@
 //	# Standalone
 //	golint-sl ./...
 //
-//	# With golangci-lint (as plugin)
-//	golangci-lint run --enable=golint-sl ./...
+//	# Machine-readable output for review bots and GitHub code scanning
+//	golint-sl -format=json ./...
+//	golint-sl -format=sarif ./... > golint-sl.sarif
+//
+//	# Count findings per analyzer and package; always exits 0
+//	golint-sl -stats ./...
+//	golint-sl -stats -stats-format=json ./...
+//
+//	# List the analyzers, their categories, and whether the config enables them
+//	golint-sl list
+//	golint-sl list -json -category=kubernetes
+//
+//	# With golangci-lint, as a module plugin built from the plugin package
+//	golangci-lint custom
+//	./custom-gcl run ./...
 //
 // Configuration:
 //
-// Create a .golint-sl.yaml file in your project root to configure analyzers:
+// Create a .golint-sl.yaml file in your project root to configure analyzers.
+// golint-sl uses the first one found in the current directory or its parents,
+// up to the module root. GOLINT_SL_CONFIG or -config name a file explicitly,
+// and -v prints the file in use:
 //
 //	analyzers:
 //	  # Disable specific analyzers
@@ -24,32 +40,71 @@
 //	  # nilcheck: true
 //	  # contextfirst: true
 //
-// Available analyzers (31 total):
+//	# Enable or disable analyzers for parts of the tree; the longest
+//	# matching pattern wins over the analyzers section
+//	paths:
+//	  "internal/operator/**":
+//	    reconciler: true
+//	  "pkg/sdk/**":
+//	    humaneerror: false
+//
+//	# Override analyzer thresholds; command-line flags still take precedence
+//	analyzer-settings:
+//	  functionsize:
+//	    warn: 100
+//	    error: 150
+//	  nestingdepth:
+//	    max-depth: 4
+//
+// Exit codes: 0 when no issues are found, 1 when issues are found, and 2 on
+// errors such as invalid flags or packages that fail to load. With -stats,
+// issues do not change the exit code.
+//
+// Available analyzers (66 total):
 //
 // Error handling:
 //   - humaneerror: Enforce humane-errors-go with actionable advice
 //   - errorwrap: Detect bare error returns without context
 //   - sentinelerrors: Prefer sentinel errors over inline errors.New()
+//   - wrapboundary: Detect errors wrapped zero or twice at layer boundaries
+//   - multierror: Detect loops that keep only the last error instead of aggregating
+//   - errmsgstyle: Enforce Go error string conventions
+//   - errorsas: Use errors.Is/errors.As over == and type assertions on errors
 //
 // Observability:
 //   - wideevents: Enforce wide events pattern over scattered logs
 //   - contextlogger: Enforce context-based logging patterns
 //   - contextpropagation: Ensure context is propagated through call chains
+//   - spanname: OpenTelemetry span, tracer, and attribute naming
+//   - loggershutdown: Flush loggers and OpenTelemetry providers before exit
+//   - tracecardinality: Detect unbounded span attribute, metric label, and log field values
 //
 // Kubernetes:
 //   - reconciler: Kubernetes reconciler best practices
 //   - statusupdate: Ensure reconcilers update Status after changes
 //   - sideeffects: SSA-based side effect detection in reconcilers
+//   - apiversionskew: Detect API types used with controller-runtime but never added to the scheme
+//   - requeueresult: Detect controller-runtime Results that requeue incorrectly
 //
 // Testability:
 //   - clockinterface: Enforce Clock interface for testable time operations
 //   - interfaceconsistency: Interface-driven design patterns
 //   - mockverify: Ensure mocks have compile-time interface verification
 //   - optionspattern: Functional options pattern enforcement
+//   - testglobals: Detect package-level mutable state shared across tests
+//   - testhelper: Enforce t.Helper() in shared test helpers and safe parallel subtests
 //
 // Resources:
 //   - resourceclose: Detect unclosed resources (response bodies, files)
 //   - httpclient: Enforce http.Client best practices (timeouts)
+//   - rowscan: Detect SELECT columns drifting from db struct tags
+//   - readadoption: Detect readers consumed twice or read short
+//   - gracedrain: Enforce graceful, bounded http.Server shutdown
+//   - clientretryafter: Classify HTTP status codes and respect Retry-After
+//   - buffereduse: Detect writers not flushed or closed before what they wrap
+//   - grpcinterceptors: gRPC servers install recovery, telemetry, and auth interceptors in order
+//   - fieldpadding: Report padding in structs allocated in bulk (opt-in)
+//   - pollinterval: Polling loops use jittered, backed-off intervals above a floor
 //
 // Safety:
 //   - goroutineleak: Detect goroutines that may leak
@@ -57,11 +112,23 @@
 //   - nopanic: Ensure library code returns errors instead of panicking
 //   - nestingdepth: Enforce shallow nesting and early returns
 //   - syncaccess: Detect potential data races and synchronization issues
+//   - chancap: Validate config-derived channel, slice, and loop sizes
+//   - timectx: Use context deadlines over manual elapsed-time checks
+//   - atomicvalue: Detect sync/atomic misuse, suggest typed atomics
+//   - probeorder: Detect file system check-then-use races
+//   - ctxsignal: Detect lost, uncatchable, and unhandled OS signals
+//   - mapiteration: Detect nondeterministic map order in slices and output
+//   - exhauststruct: Detect opt-in exhaustive structs with missing fields
+//   - contextkeys: Detect colliding context keys and mismatched value types
+//   - slicealias: Detect exported methods returning internal slice and map fields
 //
 // Clean code:
 //   - closurecomplexity: Detect complex anonymous functions
 //   - emptyinterface: Flag problematic interface{}/any usage
 //   - returninterface: Enforce "accept interfaces, return structs"
+//   - localelower: Use strings.EqualFold over ToLower comparisons
+//   - enumjson: Check enums round-trip through JSON/YAML
+//   - structtags: Check json/yaml struct tags for consistency
 //
 // Architecture:
 //   - contextfirst: Ensure context.Context is first parameter
@@ -72,16 +139,22 @@
 //   - hardcodedcreds: Detect potential hardcoded secrets
 //   - lifecycle: Enforce component lifecycle (Run/Close) patterns
 //   - dataflow: SSA-based data flow and taint analysis
+//   - depinject: Constructors set every dependency methods use
+//   - orphanconst: Exported symbols nothing in the module uses
+//   - doccodefence: Check that code examples in doc comments reference existing identifiers
+//   - shutdownorder: Detect entrypoints without signal handling or with exits that skip defers
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-
-	"golang.org/x/tools/go/analysis/multichecker"
+	"strconv"
+	"strings"
 
 	"github.com/spechtlabs/golint-sl/analyzers"
 	"github.com/spechtlabs/golint-sl/internal/config"
+	"github.com/spechtlabs/golint-sl/internal/driver"
 	"github.com/spechtlabs/golint-sl/internal/version"
 )
 
@@ -89,25 +162,92 @@ func main() {
 	// Handle version flag
 	if len(os.Args) > 1 && (os.Args[1] == "-version" || os.Args[1] == "--version" || os.Args[1] == "version") {
 		fmt.Println(version.Info())
-		fmt.Println("GoLint SpechtLabs - 31 analyzers for Go best practices")
+		fmt.Println("GoLint SpechtLabs - 66 analyzers for Go best practices")
 		fmt.Println("https://github.com/SpechtLabs/golint-sl")
 		os.Exit(0)
 	}
 
+	// The config file decides which analyzers the driver sees, so -config
+	// and -v are read before the driver parses the command line. They are
+	// registered too, so the driver accepts them and lists them in -help.
+	flag.String("config", "", "configuration file to use instead of searching for "+config.ConfigFileName+" (overrides "+config.EnvVar+")")
+	flag.Bool("v", false, "print the configuration file in use")
+	configPath, verbose := configFlags(os.Args[1:])
+
 	// Load configuration
-	cfg, err := config.Load()
+	var cfg *config.Config
+	var err error
+	if configPath != "" {
+		cfg, err = config.LoadFrom(configPath)
+	} else {
+		cfg, err = config.Load()
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "golint-sl: error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Filter analyzers based on configuration
-	enabledAnalyzers := cfg.FilterAnalyzers(analyzers.All())
+	if verbose {
+		if cfg.Path != "" {
+			fmt.Fprintf(os.Stderr, "golint-sl: using config %s\n", cfg.Path)
+		} else {
+			fmt.Fprintf(os.Stderr, "golint-sl: no %s found, using defaults\n", config.ConfigFileName)
+		}
+	}
+
+	// Apply analyzer settings before the driver parses the command line,
+	// so explicit flags override the config file
+	all := analyzers.All()
+	if err := cfg.ApplySettings(all); err != nil {
+		fmt.Fprintf(os.Stderr, "golint-sl: error in config: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg.OptIn = analyzers.OptIn()
+
+	if len(os.Args) > 1 && os.Args[1] == "list" {
+		os.Exit(list(os.Stdout, os.Args[2:], cfg, all))
+	}
+
+	// Filter analyzers based on configuration, then restrict the ones that
+	// paths rules enable or disable to the matching files
+	enabledAnalyzers := cfg.ApplyPaths(cfg.FilterAnalyzers(all))
 
 	if len(enabledAnalyzers) == 0 {
 		fmt.Fprintf(os.Stderr, "golint-sl: no analyzers enabled (check your .golint-sl.yaml configuration)\n")
 		os.Exit(1)
 	}
 
-	multichecker.Main(enabledAnalyzers...)
+	os.Exit(driver.Main(enabledAnalyzers...))
+}
+
+// configFlags returns the values of -config and -v in args, accepting the
+// same spellings as the flag package. Scanning stops at "--".
+func configFlags(args []string) (string, bool) {
+	configPath, verbose := "", false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch name {
+		case "config":
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			configPath = value
+		case "v":
+			verbose = true
+			if hasValue {
+				verbose, _ = strconv.ParseBool(value)
+			}
+		}
+	}
+	return configPath, verbose
 }
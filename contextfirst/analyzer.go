@@ -5,6 +5,8 @@
 package contextfirst
 
 import (
+	"bytes"
+	"fmt"
 	"go/ast"
 	"go/types"
 	"strings"
@@ -29,12 +31,16 @@ Bad:
     func ProcessRequest(req *Request, ctx context.Context) error
     func (s *Service) Handle(id string, ctx context.Context) (*Result, error)
 
+Function and method declarations get a suggested fix that moves the context
+first and reorders the arguments of calls in the same package. Callers in
+other packages must be updated by hand.
+
 Reference: https://go.dev/blog/context#package-context`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "contextfirst",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -42,6 +48,8 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
+	refs := collectReferences(pass, inspect)
+
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
 		(*ast.FuncLit)(nil),
@@ -78,15 +86,160 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 		// If context exists but isn't first, report
 		if ctxPos > 0 {
-			reporter.Reportf(pos.Pos(),
-				"context.Context should be the first parameter in %s, not parameter %d",
-				name, ctxPos+1)
+			diag := &analysis.Diagnostic{
+				Pos: pos.Pos(),
+				Message: fmt.Sprintf("context.Context should be the first parameter in %s, not parameter %d",
+					name, ctxPos+1),
+			}
+			if fn, ok := n.(*ast.FuncDecl); ok {
+				if fix, ok := reorderFix(pass, fn, ctxPos, refs); ok {
+					diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+					if fn.Name.IsExported() {
+						diag.Message += "; the fix updates callers in this package only"
+					}
+				}
+			}
+			reporter.Report(diag)
 		}
 	})
 
 	return nil, nil
 }
 
+// references are the uses of the functions of a package
+type references struct {
+	calls map[*types.Func][]*ast.CallExpr // direct calls
+	other map[*types.Func]bool            // used as a value, so its type must not change
+}
+
+// collectReferences finds the direct calls of each function and method of
+// the package, and the functions used any other way
+func collectReferences(pass *analysis.Pass, inspect *inspector.Inspector) references {
+	refs := references{
+		calls: make(map[*types.Func][]*ast.CallExpr),
+		other: make(map[*types.Func]bool),
+	}
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		fn, ok := pass.TypesInfo.Uses[n.(*ast.Ident)].(*types.Func)
+		if !ok || fn.Pkg() != pass.Pkg {
+			return true
+		}
+
+		fun := ast.Node(n)
+		parent := stack[len(stack)-2]
+		if sel, ok := parent.(*ast.SelectorExpr); ok && sel.Sel == n {
+			fun = sel
+			parent = stack[len(stack)-3]
+		}
+		if call, ok := parent.(*ast.CallExpr); ok && call.Fun == fun {
+			refs.calls[fn] = append(refs.calls[fn], call)
+		} else {
+			refs.other[fn] = true
+		}
+		return true
+	})
+
+	return refs
+}
+
+// reorderFix returns a fix that moves the context parameter of fn, the
+// field at index ctxPos, to the front, along with the matching argument of
+// every call in the package. There is no fix when that would not compile:
+// fn is used as a value, a call passes the results of another call, or a
+// method may implement an interface of the package.
+func reorderFix(pass *analysis.Pass, fn *ast.FuncDecl, ctxPos int, refs references) (analysis.SuggestedFix, bool) {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok || refs.other[obj] || (fn.Recv != nil && inInterface(pass, fn.Name.Name)) {
+		return analysis.SuggestedFix{}, false
+	}
+
+	params := fn.Type.Params.List
+	field := params[ctxPos]
+	if len(field.Names) > 1 {
+		return analysis.SuggestedFix{}, false
+	}
+
+	// Index of the context among the arguments
+	argPos := 0
+	for _, f := range params[:ctxPos] {
+		argPos += max(len(f.Names), 1)
+	}
+
+	edits, err := moveToFront(pass, params[0], params[ctxPos-1], field)
+	if err != nil {
+		return analysis.SuggestedFix{}, false
+	}
+	fix := analysis.SuggestedFix{
+		Message:   "Move the context.Context parameter first",
+		TextEdits: edits,
+	}
+
+	for _, call := range refs.calls[obj] {
+		if len(call.Args) <= argPos {
+			return analysis.SuggestedFix{}, false
+		}
+		edits, err := moveToFront(pass, call.Args[0], call.Args[argPos-1], call.Args[argPos])
+		if err != nil {
+			return analysis.SuggestedFix{}, false
+		}
+		fix.TextEdits = append(fix.TextEdits, edits...)
+	}
+
+	return fix, true
+}
+
+// moveToFront returns the edits that move node from after prev to before
+// first in a comma-separated list
+func moveToFront(pass *analysis.Pass, first, prev, node ast.Node) ([]analysis.TextEdit, error) {
+	text, err := sourceText(pass, node)
+	if err != nil {
+		return nil, err
+	}
+	return []analysis.TextEdit{
+		{
+			Pos:     first.Pos(),
+			End:     first.Pos(),
+			NewText: append(text, ", "...),
+		},
+		{
+			Pos: prev.End(),
+			End: node.End(),
+		},
+	}, nil
+}
+
+// sourceText returns the source code of node
+func sourceText(pass *analysis.Pass, node ast.Node) ([]byte, error) {
+	tf := pass.Fset.File(node.Pos())
+	content, err := pass.ReadFile(tf.Name())
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(content[tf.Offset(node.Pos()):tf.Offset(node.End())]), nil
+}
+
+// inInterface checks if an interface declared in the package has a method
+// called name, which a reordered method might no longer satisfy
+func inInterface(pass *analysis.Pass, name string) bool {
+	scope := pass.Pkg.Scope()
+	for _, n := range scope.Names() {
+		iface, ok := scope.Lookup(n).Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		for i := 0; i < iface.NumMethods(); i++ {
+			if iface.Method(i).Name() == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func isContextType(expr ast.Expr) bool {
 	typeStr := types.ExprString(expr)
 	return typeStr == "context.Context" || strings.HasSuffix(typeStr, ".Context")
@@ -0,0 +1,14 @@
+package contextfirst_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/contextfirst"
+)
+
+func TestContextFirstFixes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, contextfirst.Analyzer, "a")
+}
@@ -0,0 +1,61 @@
+package a
+
+import "context"
+
+type User struct{ ID string }
+
+func Fetch(id string, ctx context.Context) (*User, error) { // want `context.Context should be the first parameter in Fetch, not parameter 2; the fix updates callers in this package only`
+	return &User{ID: id}, ctx.Err()
+}
+
+func load(ctx context.Context, id string) (*User, error) {
+	return Fetch(id, ctx)
+}
+
+func loadAll(ctx context.Context, ids []string) []*User {
+	var users []*User
+	for _, id := range ids {
+		if u, err := Fetch(id+"-"+"suffix", ctx); err == nil {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+type Store struct{}
+
+func (s *Store) save(u *User, retries int, ctx context.Context, tags ...string) error { // want `context.Context should be the first parameter in save, not parameter 3`
+	return ctx.Err()
+}
+
+func (s *Store) SaveAll(ctx context.Context, users []*User) error {
+	for _, u := range users {
+		if err := s.save(u, 3, ctx, "a", "b"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Getter interface {
+	Get(ctx context.Context, key string) string
+}
+
+func (s *Store) Get(key string, ctx context.Context) string { // want `context.Context should be the first parameter in Get, not parameter 2`
+	return key
+}
+
+func handler(name string, ctx context.Context) error { // want `context.Context should be the first parameter in handler, not parameter 2`
+	return nil
+}
+
+var handlers = []func(string, context.Context) error{handler}
+
+func twoValues() (string, context.Context) { return "", context.Background() }
+
+func spread(id string, ctx context.Context) { // want `context.Context should be the first parameter in spread, not parameter 2`
+}
+
+func callSpread() {
+	spread(twoValues())
+}
@@ -0,0 +1,387 @@
+// Package contextkeys provides an analyzer that detects context keys that
+// collide or are used with inconsistent value types across packages.
+//
+// Typed keys avoid the worst collisions, but two packages that both declare
+// a "user" key still confuse readers and collide as soon as one of them uses
+// a plain string. Worse, storing a User under a key in one package and
+// asserting *User in another compiles fine and the lookup always fails.
+package contextkeys
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect colliding context keys and mismatched value types across packages
+
+Every package records the context keys it passes to context.WithValue and
+ctx.Value, the types it stores under them, and the types it asserts when
+reading them. Using these facts, the analyzer reports:
+1. Two distinct keys with the same string value, such as "user" declared
+   as a key in two packages
+2. A ctx.Value(key).(T) assertion where the value stored under the same key
+   has a different concrete type, so the assertion always fails
+
+Problems are reported in the package that sees both sides: the one using a
+key or site, when the other is in the same package or one it imports.
+
+Bad:
+    // package auth
+    ctx = context.WithValue(ctx, keys.User, user) // stores User
+
+    // package handlers
+    u, ok := ctx.Value(keys.User).(*auth.User) // never ok
+
+Good:
+    // package auth
+    func WithUser(ctx context.Context, u *User) context.Context {
+        return context.WithValue(ctx, userKey{}, u)
+    }
+
+    func UserFrom(ctx context.Context) (*User, bool) {
+        u, ok := ctx.Value(userKey{}).(*User)
+        return u, ok
+    }`
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "contextkeys",
+	Doc:       Doc,
+	Requires:  nolint.Requires(inspect.Analyzer),
+	Run:       run,
+	FactTypes: []analysis.Fact{(*keysFact)(nil)},
+}
+
+// keysFact records the context keys a package uses and the value types it
+// stores and retrieves under them
+type keysFact struct {
+	Keys   []Key
+	Stores []TypedUse
+	Loads  []TypedUse
+}
+
+func (*keysFact) AFact() {}
+
+func (f *keysFact) String() string {
+	return fmt.Sprintf("keys(%d) stores(%d) loads(%d)", len(f.Keys), len(f.Stores), len(f.Loads))
+}
+
+// Key is a context key identified by its declaration, or by type and value
+// for keys written inline
+type Key struct {
+	ID    string // unique identity of the key
+	Name  string // display name, like auth.userKey
+	Value string // string value, if the key is a string constant
+	Known bool   // Value is set
+}
+
+// TypedUse is a value type stored or asserted under a key
+type TypedUse struct {
+	Key       Key
+	Type      string // type with full package paths, see canonical
+	Interface bool   // the type is an interface, matched by assignability
+
+	typ types.Type // the type itself, for uses in the current package
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	own := &keysFact{}
+	keyPos := make(map[string]ast.Expr) // first use of each own key
+	storePos := make(map[int]ast.Node)  // index in own.Stores -> site
+	loadPos := make(map[int]ast.Node)   // index in own.Loads -> site
+
+	addKey := func(k Key, expr ast.Expr) {
+		if _, ok := keyPos[k.ID]; ok {
+			return
+		}
+		keyPos[k.ID] = expr
+		own.Keys = append(own.Keys, k)
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.TypeAssertExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			switch {
+			case isWithValue(pass, node) && len(node.Args) == 3:
+				k, ok := keyOf(pass, node.Args[1])
+				if !ok {
+					return
+				}
+				addKey(k, node.Args[1])
+				if t := storedType(pass, node.Args[2]); t != nil {
+					storePos[len(own.Stores)] = node
+					own.Stores = append(own.Stores, typedUse(k, t))
+				}
+			case isValueCall(pass, node) && len(node.Args) == 1:
+				if k, ok := keyOf(pass, node.Args[0]); ok {
+					addKey(k, node.Args[0])
+				}
+			}
+
+		case *ast.TypeAssertExpr:
+			call, ok := ast.Unparen(node.X).(*ast.CallExpr)
+			if !ok || node.Type == nil || !isValueCall(pass, call) || len(call.Args) != 1 {
+				return
+			}
+			k, ok := keyOf(pass, call.Args[0])
+			if !ok {
+				return
+			}
+			if t := pass.TypesInfo.TypeOf(node.Type); t != nil {
+				loadPos[len(own.Loads)] = node
+				own.Loads = append(own.Loads, typedUse(k, t))
+			}
+		}
+	})
+
+	var deps []*keysFact
+	for _, pf := range pass.AllPackageFacts() {
+		if f, ok := pf.Fact.(*keysFact); ok && pf.Package != pass.Pkg {
+			deps = append(deps, f)
+		}
+	}
+	// Deterministic messages when several keys collide
+	sort.Slice(deps, func(i, j int) bool { return factKey(deps[i]) < factKey(deps[j]) })
+
+	checkCollisions(reporter, own, deps, keyPos)
+	checkMismatches(reporter, own, deps, storePos, loadPos)
+
+	if len(own.Keys) > 0 {
+		pass.ExportPackageFact(own)
+	}
+	return nil, nil
+}
+
+// factKey orders facts by their first key
+func factKey(f *keysFact) string {
+	if len(f.Keys) == 0 {
+		return ""
+	}
+	return f.Keys[0].ID
+}
+
+// checkCollisions reports own keys whose string value equals that of a
+// different key used earlier in this package or in a dependency
+func checkCollisions(reporter *nolint.Reporter, own *keysFact, deps []*keysFact, keyPos map[string]ast.Expr) {
+	var earlier []Key
+	for _, f := range deps {
+		earlier = append(earlier, f.Keys...)
+	}
+
+	for _, k := range own.Keys {
+		if k.Known {
+			for _, other := range earlier {
+				if other.Known && other.Value == k.Value && other.ID != k.ID {
+					reporter.Reportf(keyPos[k.ID].Pos(),
+						"context key %s has the same value %q as %s; share one key instead of declaring another",
+						k.Name, k.Value, other.Name)
+					break
+				}
+			}
+		}
+		earlier = append(earlier, k)
+	}
+}
+
+// checkMismatches reports retrievals whose asserted type cannot match the
+// type stored under the same key, at the site in this package
+func checkMismatches(reporter *nolint.Reporter, own *keysFact, deps []*keysFact, storePos, loadPos map[int]ast.Node) {
+	var depStores, depLoads []TypedUse
+	for _, f := range deps {
+		depStores = append(depStores, f.Stores...)
+		depLoads = append(depLoads, f.Loads...)
+	}
+
+	for i, load := range own.Loads {
+		for _, store := range append(own.Stores, depStores...) {
+			if conflicts(store, load) {
+				reporter.Reportf(loadPos[i].Pos(),
+					"context key %s holds %s but is asserted as %s; the assertion always fails",
+					load.Key.Name, store.Type, load.Type)
+				break
+			}
+		}
+	}
+
+	for i, store := range own.Stores {
+		for _, load := range depLoads {
+			if conflicts(store, load) {
+				reporter.Reportf(storePos[i].Pos(),
+					"context key %s stores %s but is asserted as %s elsewhere; the assertion always fails",
+					store.Key.Name, store.Type, load.Type)
+				break
+			}
+		}
+	}
+}
+
+// conflicts checks if a value stored as store can never satisfy the
+// assertion load. Interface assertions are not judged from facts. Types
+// of the current package are compared directly, those from facts by their
+// canonical strings.
+func conflicts(store, load TypedUse) bool {
+	if store.Key.ID != load.Key.ID || store.Interface || load.Interface {
+		return false
+	}
+	if store.typ != nil && load.typ != nil {
+		return !types.Identical(types.Unalias(store.typ), types.Unalias(load.typ))
+	}
+	return store.Type != load.Type
+}
+
+// typedUse describes type t stored or asserted under k
+func typedUse(k Key, t types.Type) TypedUse {
+	return TypedUse{
+		Key:       k,
+		Type:      types.TypeString(canonical(t), qualifier),
+		Interface: types.IsInterface(t),
+		typ:       t,
+	}
+}
+
+// canonical returns t with aliases resolved and the parameter names of
+// function types dropped, so identical types print the same: a stored
+// t.Logf is a func(format string, args ...any), but is asserted as
+// func(string, ...any)
+func canonical(t types.Type) types.Type {
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return types.NewPointer(canonical(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(canonical(t.Elem()))
+	case *types.Array:
+		return types.NewArray(canonical(t.Elem()), t.Len())
+	case *types.Map:
+		return types.NewMap(canonical(t.Key()), canonical(t.Elem()))
+	case *types.Chan:
+		return types.NewChan(t.Dir(), canonical(t.Elem()))
+	case *types.Signature:
+		return types.NewSignatureType(nil, nil, nil, canonicalTuple(t.Params()), canonicalTuple(t.Results()), t.Variadic())
+	default:
+		return t
+	}
+}
+
+// canonicalTuple returns tuple without names and with canonical types
+func canonicalTuple(tuple *types.Tuple) *types.Tuple {
+	vars := make([]*types.Var, tuple.Len())
+	for i := range vars {
+		vars[i] = types.NewParam(token.NoPos, nil, "", canonical(tuple.At(i).Type()))
+	}
+	return types.NewTuple(vars...)
+}
+
+// qualifier writes types with their full package path
+func qualifier(p *types.Package) string { return p.Path() }
+
+// storedType returns the type of a value stored with WithValue, or nil for
+// untyped nil
+func storedType(pass *analysis.Pass, expr ast.Expr) types.Type {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return nil
+	}
+	if b, ok := t.(*types.Basic); ok && b.Kind() == types.UntypedNil {
+		return nil
+	}
+	return types.Default(t)
+}
+
+// keyOf identifies the key expression of a WithValue or Value call
+func keyOf(pass *analysis.Pass, expr ast.Expr) (Key, bool) {
+	expr = ast.Unparen(expr)
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok {
+		return Key{}, false
+	}
+
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	case *ast.CompositeLit:
+		t := types.TypeString(tv.Type, qualifier)
+		return Key{ID: t + "{}", Name: types.TypeString(tv.Type, types.RelativeTo(pass.Pkg)) + "{}"}, true
+	}
+
+	if ident != nil {
+		switch obj := pass.TypesInfo.Uses[ident].(type) {
+		case *types.Const, *types.Var:
+			if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+				break
+			}
+			k := Key{ID: obj.Pkg().Path() + "." + obj.Name(), Name: obj.Pkg().Name() + "." + obj.Name()}
+			if c, ok := obj.(*types.Const); ok && c.Val().Kind() == constant.String {
+				k.Value, k.Known = constant.StringVal(c.Val()), true
+			}
+			return k, true
+		}
+	}
+
+	// Inline constants, like "user" or ctxKey("user")
+	if tv.Value == nil {
+		return Key{}, false
+	}
+	t := types.Default(tv.Type)
+	id := types.TypeString(t, qualifier) + "(" + tv.Value.ExactString() + ")"
+	if _, builtin := t.(*types.Basic); builtin {
+		// Plain strings are scoped to the package that writes them, so the
+		// same literal in two packages counts as two keys
+		id = pass.Pkg.Path() + ":" + id
+	}
+	k := Key{ID: id, Name: pass.Pkg.Name() + "." + types.ExprString(expr)}
+	if tv.Value.Kind() == constant.String {
+		k.Value, k.Known = constant.StringVal(tv.Value), true
+	}
+	return k, true
+}
+
+// isWithValue checks if call is context.WithValue
+func isWithValue(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "context" && fn.Name() == "WithValue"
+}
+
+// isValueCall checks if call is the Value method of a context.Context
+func isValueCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Value" {
+		return false
+	}
+	return isContextType(pass.TypesInfo.TypeOf(sel.X))
+}
+
+// isContextType checks if t is context.Context
+func isContextType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
@@ -0,0 +1,29 @@
+package contextkeys_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/contextkeys"
+)
+
+func TestContextKeysCollision(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextkeys.Analyzer, "example.com/collide/a", "example.com/collide/b")
+}
+
+func TestContextKeysMismatch(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextkeys.Analyzer, "example.com/mismatch/store", "example.com/mismatch/handlers")
+}
+
+func TestContextKeysShared(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextkeys.Analyzer, "example.com/shared/auth", "example.com/shared/api")
+}
+
+func TestContextKeysIdenticalTypes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextkeys.Analyzer, "example.com/identical/conn", "example.com/identical/use")
+}
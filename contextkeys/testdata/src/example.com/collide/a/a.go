@@ -0,0 +1,16 @@
+package a // want package:"keys\\(1\\) stores\\(1\\) loads\\(1\\)"
+
+import "context"
+
+type ctxKey string
+
+const userKey ctxKey = "user"
+
+func WithUser(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, userKey, name)
+}
+
+func User(ctx context.Context) string {
+	name, _ := ctx.Value(userKey).(string)
+	return name
+}
@@ -0,0 +1,19 @@
+package b // want package:"keys\\(2\\) stores\\(2\\) loads\\(0\\)"
+
+import (
+	"context"
+
+	"example.com/collide/a"
+)
+
+type key string
+
+const userKey key = "user"
+
+func WithUser(ctx context.Context, name string) context.Context {
+	return context.WithValue(a.WithUser(ctx, name), userKey, name) // want `context key b.userKey has the same value "user" as a.userKey; share one key instead of declaring another`
+}
+
+func Tenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, "user", "acme") // want `context key b."user" has the same value "user" as a.userKey`
+}
@@ -0,0 +1,41 @@
+package conn // want package:"keys\\(2\\) stores\\(2\\) loads\\(2\\)"
+
+import (
+	"context"
+	"fmt"
+)
+
+type key int
+
+var (
+	LogKey  = key(1)
+	ConnKey = key(2)
+)
+
+type serverConn struct{ id int }
+
+// ServerConn is an alias, identical to serverConn
+type ServerConn = serverConn
+
+type T struct{}
+
+func (T) Logf(format string, args ...any) { fmt.Printf(format, args...) }
+
+// WithLogf stores a method value, whose type has parameter names
+func WithLogf(ctx context.Context, t T) context.Context {
+	return context.WithValue(ctx, LogKey, t.Logf)
+}
+
+func Logf(ctx context.Context) func(string, ...any) {
+	logf, _ := ctx.Value(LogKey).(func(string, ...any))
+	return logf
+}
+
+func WithConn(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ConnKey, &serverConn{id: 1})
+}
+
+func Conn(ctx context.Context) *ServerConn {
+	c, _ := ctx.Value(ConnKey).(*ServerConn)
+	return c
+}
@@ -0,0 +1,18 @@
+package use // want package:"keys\\(2\\) stores\\(0\\) loads\\(2\\)"
+
+import (
+	"context"
+
+	"example.com/identical/conn"
+)
+
+// Logf asserts the stored t.Logf without parameter names or the any alias
+func Logf(ctx context.Context) func(string, ...interface{}) {
+	logf, _ := ctx.Value(conn.LogKey).(func(string, ...interface{}))
+	return logf
+}
+
+func Conn(ctx context.Context) *conn.ServerConn {
+	c, _ := ctx.Value(conn.ConnKey).(*conn.ServerConn)
+	return c
+}
@@ -0,0 +1,8 @@
+package keys
+
+type ctxKey int
+
+const (
+	User ctxKey = iota
+	Request
+)
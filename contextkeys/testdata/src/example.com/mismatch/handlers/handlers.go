@@ -0,0 +1,30 @@
+package handlers // want package:"keys\\(2\\) stores\\(1\\) loads\\(3\\)"
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/keys"
+	"example.com/mismatch/store"
+)
+
+func CurrentUser(ctx context.Context) *store.User {
+	u, _ := ctx.Value(keys.User).(*store.User) // want `context key keys.User holds example.com/mismatch/store.User but is asserted as \*example.com/mismatch/store.User; the assertion always fails`
+	return u
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(keys.Request).(string) // want `context key keys.Request holds int but is asserted as string`
+	return id
+}
+
+func Describe(ctx context.Context) string {
+	if s, ok := ctx.Value(keys.User).(fmt.Stringer); ok {
+		return s.String()
+	}
+	return ""
+}
+
+func Login(ctx context.Context) context.Context {
+	return context.WithValue(ctx, keys.Request, 42)
+}
@@ -0,0 +1,19 @@
+package store // want package:"keys\\(2\\) stores\\(2\\) loads\\(0\\)"
+
+import (
+	"context"
+
+	"example.com/keys"
+)
+
+type User struct {
+	Name string
+}
+
+func WithUser(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, keys.User, User{Name: name})
+}
+
+func WithRequest(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, keys.Request, id)
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"context"
+
+	"example.com/shared/auth"
+)
+
+func Handle(ctx context.Context) string {
+	ctx = auth.WithUser(ctx, &auth.User{Name: "ada"})
+	if u, ok := auth.UserFrom(ctx); ok {
+		return u.Name + auth.SessionFrom(ctx)
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package auth // want package:"keys\\(2\\) stores\\(2\\) loads\\(2\\)"
+
+import "context"
+
+type User struct {
+	Name string
+}
+
+type userKey struct{}
+
+type sessionKey string
+
+const session sessionKey = "session"
+
+func WithUser(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+func UserFrom(ctx context.Context) (*User, bool) {
+	u, ok := ctx.Value(userKey{}).(*User)
+	return u, ok
+}
+
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, session, id)
+}
+
+func SessionFrom(ctx context.Context) string {
+	id, _ := ctx.Value(session).(string)
+	return id
+}
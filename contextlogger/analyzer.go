@@ -10,6 +10,7 @@ package contextlogger
 
 import (
 	"go/ast"
+	"go/token"
 	"go/types"
 	"strings"
 
@@ -41,35 +42,45 @@ Example:
 
     // Bad: Using global logger when context is available
     func handleRequest(ctx context.Context) {
-        log.Info("handling request")  // Loses context
-    }`
+        zap.L().Info("handling request")  // Loses context
+    }
+
+Global loggers are resolved by type: package-level functions of the
+standard log package, zap.L() and zap.S(), and logrus. A logger in a
+variable is never global, even one named log. Global logger calls after a
+FromContext call in the same function are not reported.`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "contextlogger",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
-// GlobalLoggerPatterns are patterns that indicate global logger usage
-// These should use context-derived loggers instead for proper tracing
-var GlobalLoggerPatterns = []string{
-	"log.Info",
-	"log.Error",
-	"log.Warn",
-	"log.Debug",
-	"log.Fatal",
-	"log.Print",
-	"log.Printf",
-	"log.Println",
-	"zap.L()",
-	"zap.S()",
-	"logrus.Info",
-	"logrus.Error",
-	"logrus.Warn",
-	"logrus.Debug",
-	"logrus.WithField",
-	"logrus.WithFields",
+// GlobalLoggers maps import paths of packages with a global logger to their
+// package-level functions that use it. These should use context-derived
+// loggers instead for proper tracing
+var GlobalLoggers = map[string]map[string]bool{
+	"log":             levelFuncs([]string{"Print", "Fatal", "Panic"}),
+	"go.uber.org/zap": {"L": true, "S": true},
+	"github.com/sirupsen/logrus": levelFuncs(
+		[]string{"Trace", "Debug", "Info", "Print", "Warn", "Warning", "Error", "Fatal", "Panic"},
+		"WithField", "WithFields", "WithError", "WithContext", "WithTime", "StandardLogger"),
+}
+
+// levelFuncs returns the Level, Levelf and Levelln functions of levels,
+// plus extra
+func levelFuncs(levels []string, extra ...string) map[string]bool {
+	funcs := make(map[string]bool)
+	for _, level := range levels {
+		funcs[level] = true
+		funcs[level+"f"] = true
+		funcs[level+"ln"] = true
+	}
+	for _, name := range extra {
+		funcs[name] = true
+	}
+	return funcs
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
@@ -101,7 +112,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		}
 
 		// Check for global logger usage
-		checkGlobalLoggerUsage(reporter, fn)
+		checkGlobalLoggerUsage(reporter, pass, fn)
 
 		// Check for logger passed as parameter (should use context instead)
 		checkLoggerParameter(reporter, fn)
@@ -127,8 +138,8 @@ func hasContextParameter(fn *ast.FuncDecl) bool {
 }
 
 // checkGlobalLoggerUsage detects usage of global logger when context is available
-func checkGlobalLoggerUsage(reporter *nolint.Reporter, fn *ast.FuncDecl) {
-	usesFromContext := false
+func checkGlobalLoggerUsage(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	fromContext := token.NoPos
 	var globalLoggerCalls []*ast.CallExpr
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
@@ -137,30 +148,66 @@ func checkGlobalLoggerUsage(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 			return true
 		}
 
-		callStr := exprToString(call.Fun)
-
 		// Check if FromContext is used
-		if strings.Contains(callStr, "FromContext") {
-			usesFromContext = true
+		if isFromContextCall(pass, call) && !fromContext.IsValid() {
+			fromContext = call.Pos()
 		}
 
-		// Check for global logger patterns
-		for _, pattern := range GlobalLoggerPatterns {
-			if strings.Contains(callStr, pattern) {
-				globalLoggerCalls = append(globalLoggerCalls, call)
-			}
+		// Check for global logger calls; zap.L() is reported once however
+		// long the chain built on it
+		if isGlobalLoggerCall(pass, call) {
+			globalLoggerCalls = append(globalLoggerCalls, call)
 		}
 
 		return true
 	})
 
-	// If context is available but global logger is used without FromContext
-	if !usesFromContext && len(globalLoggerCalls) > 0 {
-		for _, call := range globalLoggerCalls {
-			reporter.Reportf(call.Pos(),
-				"function has context parameter but uses global logger; use log.FromContext(ctx) instead")
+	// A logger taken from the context first, in whatever variable, covers
+	// the rest of the function, including helpers it is passed to
+	for _, call := range globalLoggerCalls {
+		if fromContext.IsValid() && fromContext < call.Pos() {
+			continue
 		}
+		reporter.Reportf(call.Pos(),
+			"function has context parameter but uses global logger; use log.FromContext(ctx) instead")
+	}
+}
+
+// isGlobalLoggerCall checks if call is a package-level function of
+// GlobalLoggers, like log.Printf, zap.L() or logrus.WithField. Methods on
+// loggers held in variables are never global, whatever the variable's name
+func isGlobalLoggerCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkgName, ok := pass.TypesInfo.Uses[ident].(*types.PkgName)
+	if !ok {
+		return false
 	}
+	return GlobalLoggers[pkgName.Imported().Path()][sel.Sel.Name]
+}
+
+// isFromContextCall checks if call is a FromContext function, like
+// log.FromContext(ctx) or logr.FromContextOrDiscard(ctx)
+func isFromContextCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	var name *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		name = fun
+	case *ast.SelectorExpr:
+		name = fun.Sel
+	default:
+		return false
+	}
+	if _, ok := pass.TypesInfo.Uses[name].(*types.Func); !ok {
+		return false
+	}
+	return strings.Contains(name.Name, "FromContext")
 }
 
 // checkLoggerParameter detects logger passed as parameter (anti-pattern)
@@ -194,20 +241,6 @@ func checkLoggerParameter(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	}
 }
 
-// exprToString converts an expression to a readable string
-func exprToString(expr ast.Expr) string {
-	switch e := expr.(type) {
-	case *ast.Ident:
-		return e.Name
-	case *ast.SelectorExpr:
-		return exprToString(e.X) + "." + e.Sel.Name
-	case *ast.CallExpr:
-		return exprToString(e.Fun) + "()"
-	default:
-		return types.ExprString(expr)
-	}
-}
-
 // ContextLoggerInfo contains information about logger patterns in a package
 type ContextLoggerInfo struct {
 	HasFromContext     bool
@@ -239,15 +272,11 @@ func AnalyzeContextLogger(pass *analysis.Pass) *ContextLoggerInfo {
 			}
 
 		case *ast.CallExpr:
-			callStr := exprToString(node.Fun)
-			if strings.Contains(callStr, "FromContext") {
+			if isFromContextCall(pass, node) {
 				info.ContextLoggerCalls++
 			}
-			for _, pattern := range GlobalLoggerPatterns {
-				if strings.Contains(callStr, pattern) {
-					info.GlobalLoggerCalls++
-					break
-				}
+			if isGlobalLoggerCall(pass, node) {
+				info.GlobalLoggerCalls++
 			}
 		}
 	})
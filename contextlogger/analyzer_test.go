@@ -0,0 +1,14 @@
+package contextlogger_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/contextlogger"
+)
+
+func TestContextLogger(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextlogger.Analyzer, "loggers")
+}
@@ -0,0 +1,9 @@
+package logging
+
+import "context"
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string, keysAndValues ...interface{}) {}
+
+func FromContext(ctx context.Context) *Logger { return &Logger{} }
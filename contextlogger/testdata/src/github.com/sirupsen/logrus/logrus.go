@@ -0,0 +1,13 @@
+package logrus
+
+type Fields map[string]interface{}
+
+type Entry struct{}
+
+func (e *Entry) Info(args ...interface{}) {}
+
+func WithField(key string, value interface{}) *Entry { return &Entry{} }
+
+func Info(args ...interface{}) {}
+
+func SetLevel(level uint32) {}
@@ -0,0 +1,23 @@
+package zap
+
+type Field struct{}
+
+func String(key, value string) Field { return Field{} }
+
+type Logger struct{}
+
+func L() *Logger { return &Logger{} }
+
+func (l *Logger) With(fields ...Field) *Logger { return l }
+
+func (l *Logger) Info(msg string, fields ...Field) {}
+
+func (l *Logger) Sugar() *SugaredLogger { return &SugaredLogger{} }
+
+type SugaredLogger struct{}
+
+func S() *SugaredLogger { return &SugaredLogger{} }
+
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...interface{}) {}
+
+func NewNop() *Logger { return &Logger{} }
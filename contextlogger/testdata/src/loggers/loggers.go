@@ -0,0 +1,64 @@
+package loggers
+
+import (
+	"context"
+	stdlog "log"
+
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+
+	"example.com/logging"
+)
+
+// Reported: zap global, once per chain
+func Chained(ctx context.Context, id string) {
+	zap.L().With(zap.String("id", id)).Info("chained") // want `function has context parameter but uses global logger`
+	zap.S().Infow("sugared", "id", id)                 // want `function has context parameter but uses global logger`
+}
+
+// Reported: standard library log, under any import name
+func Std(ctx context.Context) {
+	stdlog.Printf("starting") // want `function has context parameter but uses global logger`
+}
+
+// Reported: logrus package-level functions, here imported as log
+func Logrus(ctx context.Context) {
+	log.WithField("id", 1).Info("logrus") // want `function has context parameter but uses global logger`
+	log.Info("plain")                     // want `function has context parameter but uses global logger`
+	log.SetLevel(4)
+}
+
+// Not reported: a local variable named log is not the global logger, even
+// when FromContext was called in another helper
+func LocalLog(ctx context.Context) {
+	log := loggerFor(ctx)
+	log.Info("local")
+}
+
+func loggerFor(ctx context.Context) *logging.Logger {
+	return logging.FromContext(ctx)
+}
+
+// Not reported: the logger comes from the context first and is passed on
+func FromContextFirst(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	helper(logger)
+	zap.L().Info("after FromContext")
+}
+
+// Reported: the global logger is used before the context logger is taken
+func FromContextLater(ctx context.Context) {
+	zap.L().Info("before FromContext") // want `function has context parameter but uses global logger`
+	logger := logging.FromContext(ctx)
+	helper(logger)
+}
+
+// Not reported: a zap logger held in a variable
+func Held(ctx context.Context) {
+	zl := zap.NewNop()
+	zl.Info("held")
+}
+
+func helper(l *logging.Logger) {
+	l.Info("helper")
+}
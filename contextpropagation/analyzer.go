@@ -3,14 +3,23 @@
 package contextpropagation
 
 import (
+	"fmt"
 	"go/ast"
+	"go/build"
+	"go/token"
 	"go/types"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 
+	"github.com/spechtlabs/golint-sl/internal/ctxfix"
+	"github.com/spechtlabs/golint-sl/internal/facts"
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
 
@@ -19,9 +28,17 @@ const Doc = `ensure context.Context is properly propagated through call chains
 This analyzer detects:
 1. HTTP calls without context (http.Get vs http.NewRequestWithContext)
 2. Database calls without context (db.Query vs db.QueryContext)
-3. context.Background()/context.TODO() when a real context is available
+3. context.Background()/context.TODO() when a real context is available;
+   detached work (a context derived from Background and only used in go
+   statements) and helpers returning a context, or named like NewContext
+   or DetachedContext, are exempt, and a Background used alongside the
+   propagated parameter is an advisory
 4. Context parameter received but not used in function body
 5. Sub-calls that accept context but aren't passed the available context
+6. context.Context stored in struct fields, which hides the cancellation
+   scope; pass ctx as the first argument of methods instead
+7. Calls to functions, in this or an imported package, that do I/O without
+   accepting a context, so the available context cannot cancel them
 
 Proper context propagation is critical for:
 - Request tracing (OpenTelemetry, Jaeger, etc.)
@@ -30,10 +47,11 @@ Proper context propagation is critical for:
 - Request-scoped values (user info, request ID)`
 
 var Analyzer = &analysis.Analyzer{
-	Name:     "contextpropagation",
-	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
-	Run:      run,
+	Name:      "contextpropagation",
+	Doc:       Doc,
+	Requires:  nolint.Requires(inspect.Analyzer),
+	Run:       run,
+	FactTypes: []analysis.Fact{(*facts.PerformsIO)(nil)},
 }
 
 // packageLevelCallsWithoutContext are package-level functions that should use context variants
@@ -63,6 +81,32 @@ var methodsRequiringContext = map[string]string{
 	"Begin":    "use BeginTx instead",
 }
 
+// ioFuncs are the I/O operations that have a context-accepting variant, by
+// their types.Func full name
+var ioFuncs = map[string]bool{
+	"net/http.Get":                true,
+	"net/http.Head":               true,
+	"net/http.Post":               true,
+	"net/http.PostForm":           true,
+	"(*net/http.Client).Get":      true,
+	"(*net/http.Client).Head":     true,
+	"(*net/http.Client).Post":     true,
+	"(*net/http.Client).PostForm": true,
+	"net.Dial":                    true,
+	"net.DialTimeout":             true,
+	"os/exec.Command":             true,
+	"(*database/sql.DB).Query":    true,
+	"(*database/sql.DB).QueryRow": true,
+	"(*database/sql.DB).Exec":     true,
+	"(*database/sql.DB).Prepare":  true,
+	"(*database/sql.DB).Begin":    true,
+	"(*database/sql.Tx).Query":    true,
+	"(*database/sql.Tx).QueryRow": true,
+	"(*database/sql.Tx).Exec":     true,
+	"(*database/sql.Tx).Prepare":  true,
+	"google.golang.org/grpc.Dial": true,
+}
+
 // nonContextFunctions are functions that commonly don't need context
 var exemptFunctions = map[string]bool{
 	"main":          true,
@@ -109,40 +153,44 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.FuncDecl)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
-		fn, ok := n.(*ast.FuncDecl)
-		if !ok || fn.Body == nil {
+	io := collectIO(pass, inspect)
+
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		if isMockPkg {
 			return
 		}
+		checkContextFields(reporter, pass, n.(*ast.TypeSpec))
+	})
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !push || !ok || fn.Body == nil {
+			return true
+		}
 
 		// Get file path for context-aware checks
 		pos := pass.Fset.Position(fn.Pos())
 		filePath := pos.Filename
 
-		// Skip test files entirely
-		if strings.HasSuffix(filePath, "_test.go") {
-			return
-		}
-
-		// Skip mock files - mocks often intentionally ignore context
-		if strings.Contains(filePath, "/mock/") || strings.Contains(filePath, "/mocks/") ||
-			strings.HasSuffix(filePath, "_mock.go") || strings.HasSuffix(filePath, "_mocks.go") {
-			return
+		// Skip test and mock files - tests use context.Background intentionally
+		// and mocks often ignore context
+		if isExemptFile(filePath) {
+			return true
 		}
 
 		// Skip exempt functions
 		if fn.Name != nil && exemptFunctions[fn.Name.Name] {
-			return
+			return true
 		}
 
 		// Skip test functions (they often use context.Background intentionally)
 		if fn.Name != nil && strings.HasPrefix(fn.Name.Name, "Test") {
-			return
+			return true
 		}
 
 		// Skip mock packages and mock type methods - mocks often don't propagate context
 		if isMockPkg || isMockFunction(fn) {
-			return
+			return true
 		}
 
 		// Get context parameter info
@@ -151,22 +199,262 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 		if hasContext {
 			// Check if context is used
-			checkContextUsed(reporter, fn, ctxParam)
+			checkContextUsed(reporter, pass, fn, ctxParam)
 
 			// Check for context.Background/TODO when real context available
-			checkUnnecessaryBackgroundContext(reporter, fn)
+			checkUnnecessaryBackgroundContext(reporter, pass, fn, ctxParam)
 
 			// Check calls that should use context
-			checkCallsWithoutContext(reporter, fn, ctxParam)
+			checkCallsWithoutContext(reporter, pass, fn)
+
+			// Check calls to functions doing I/O the context cannot cancel
+			checkIOCalls(reporter, pass, fn, io)
 		}
 
 		// Even without context param, check for problematic patterns
-		checkContextAwareCalls(reporter, fn, hasContext)
+		file := stack[0].(*ast.File)
+		checkContextAwareCalls(reporter, pass, file, fn, hasContext)
+		return true
 	})
 
 	return nil, nil
 }
 
+// collectIO finds the functions of the package that do I/O without accepting
+// a context, directly or through other functions of this or imported
+// packages, and exports a PerformsIO fact for each
+func collectIO(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Func]*facts.PerformsIO {
+	io := make(map[*types.Func]*facts.PerformsIO)
+
+	var decls []*ast.FuncDecl
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		if fn := n.(*ast.FuncDecl); fn.Body != nil {
+			decls = append(decls, fn)
+		}
+	})
+
+	// Callers are marked once their callees are, so repeat until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range decls {
+			obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+			if !ok || io[obj] != nil || acceptsContextParam(pass, obj) {
+				continue
+			}
+			if via := ioVia(pass, fn.Body, io); via != "" {
+				io[obj] = &facts.PerformsIO{Via: via}
+				changed = true
+			}
+		}
+	}
+
+	for fn, fact := range io {
+		pass.ExportObjectFact(fn, fact)
+	}
+	return io
+}
+
+// ioVia returns the I/O operation the first I/O call in body ends up in, or
+// "" if body does no I/O without a context
+func ioVia(pass *analysis.Pass, body *ast.BlockStmt, io map[*types.Func]*facts.PerformsIO) string {
+	via := ""
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || via != "" {
+			return via == ""
+		}
+		if fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok && ioFuncs[fn.FullName()] {
+			via = fn.FullName()
+		} else if fact := performsIO(pass, call, io); fact != nil {
+			via = fact.Via
+		}
+		return via == ""
+	})
+	return via
+}
+
+// performsIO returns the PerformsIO fact of the function called, if it has one
+func performsIO(pass *analysis.Pass, call *ast.CallExpr, io map[*types.Func]*facts.PerformsIO) *facts.PerformsIO {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return nil
+	}
+	if fn.Pkg() == pass.Pkg {
+		return io[fn]
+	}
+	fact := new(facts.PerformsIO)
+	if !pass.ImportObjectFact(fn, fact) {
+		return nil
+	}
+	return fact
+}
+
+// acceptsContextParam checks if any parameter of fn is a context
+func acceptsContextParam(pass *analysis.Pass, fn *types.Func) bool {
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if isContext(pass, params.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIOCalls reports calls to functions doing I/O without accepting a
+// context. I/O operations of the standard library are checked on their own,
+// and its other functions are left alone since callers cannot change them.
+func checkIOCalls(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, io map[*types.Func]*facts.PerformsIO) {
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fact := performsIO(pass, call, io)
+		if fact == nil {
+			return true
+		}
+		callee := typeutil.StaticCallee(pass.TypesInfo, call)
+		if ioFuncs[callee.FullName()] || isStdlib(callee.Pkg()) {
+			return true
+		}
+
+		reporter.Reportf(call.Pos(),
+			"%s does I/O through %s without accepting a context, so ctx cannot cancel it; add a context.Context parameter to %s",
+			types.ExprString(call.Fun), fact.Via, callee.Name())
+		return true
+	})
+}
+
+// stdlib caches whether import paths belong to the standard library
+var stdlib sync.Map // import path -> bool
+
+// isStdlib checks if pkg belongs to the standard library: its source is in
+// GOROOT. Import paths without a dot are not enough, since a module may be
+// declared as module myservice.
+func isStdlib(pkg *types.Package) bool {
+	if pkg == nil {
+		return true
+	}
+	path := pkg.Path()
+	if std, ok := stdlib.Load(path); ok {
+		return std.(bool)
+	}
+	info, err := os.Stat(filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(path)))
+	std := err == nil && info.IsDir()
+	stdlib.Store(path, std)
+	return std
+}
+
+// isExemptFile checks for test and mock files, where contexts are commonly
+// ignored or stored on purpose
+func isExemptFile(filePath string) bool {
+	return strings.HasSuffix(filePath, "_test.go") ||
+		strings.Contains(filePath, "/mock/") || strings.Contains(filePath, "/mocks/") ||
+		strings.HasSuffix(filePath, "_mock.go") || strings.HasSuffix(filePath, "_mocks.go")
+}
+
+// isContextType checks for context.Context or *context.Context, including
+// aliases of it
+func isContextType(t types.Type) bool {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// isContext checks if t is context.Context or implements it, such as a
+// derived context type. Only contexts of packages importing "context" are
+// recognized by their methods.
+func isContext(pass *analysis.Pass, t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if isContextType(t) {
+		return true
+	}
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() != "context" {
+			continue
+		}
+		obj := imp.Scope().Lookup("Context")
+		if obj == nil {
+			return false
+		}
+		iface, ok := obj.Type().Underlying().(*types.Interface)
+		return ok && types.Implements(t, iface)
+	}
+	return false
+}
+
+// isHTTPRequest checks for net/http.Request or a pointer to it
+func isHTTPRequest(t types.Type) bool {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == "Request"
+}
+
+// implementsContext checks if the type declares one of the context.Context
+// methods itself, which makes it a derived context rather than a holder of one
+func implementsContext(named *types.Named) bool {
+	for i := 0; i < named.NumMethods(); i++ {
+		if contextMethods[named.Method(i).Name()] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkContextFields reports struct fields holding a context.Context
+func checkContextFields(reporter *nolint.Reporter, pass *analysis.Pass, spec *ast.TypeSpec) {
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok || st.Fields == nil {
+		return
+	}
+	if isExemptFile(pass.Fset.Position(spec.Pos()).Filename) {
+		return
+	}
+
+	// Types embedding a request carry its context, and types overriding
+	// context methods are derived contexts
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 && isHTTPRequest(pass.TypesInfo.TypeOf(field.Type)) {
+			return
+		}
+	}
+	if obj, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName); ok {
+		if named, ok := obj.Type().(*types.Named); ok && implementsContext(named) {
+			return
+		}
+	}
+
+	for _, field := range st.Fields.List {
+		if !isContextType(pass.TypesInfo.TypeOf(field.Type)) {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			reporter.Reportf(field.Pos(),
+				"struct %s embeds context.Context, which hides the cancellation scope; pass ctx as the first argument of its methods instead",
+				spec.Name.Name)
+			continue
+		}
+		for _, name := range field.Names {
+			reporter.Reportf(name.Pos(),
+				"struct field %s.%s stores a context.Context, which hides the cancellation scope; pass ctx as the first argument of its methods instead",
+				spec.Name.Name, name.Name)
+		}
+	}
+}
+
 // getContextParam returns the name of the context parameter if present
 func getContextParam(fn *ast.FuncDecl) string {
 	if fn.Type.Params == nil {
@@ -196,7 +484,7 @@ var contextMethods = map[string]bool{
 }
 
 // checkContextUsed verifies the context parameter is actually used AND passed to sub-calls
-func checkContextUsed(reporter *nolint.Reporter, fn *ast.FuncDecl, ctxParam string) {
+func checkContextUsed(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, ctxParam string) {
 	if fn.Body == nil {
 		return
 	}
@@ -267,7 +555,7 @@ func checkContextUsed(reporter *nolint.Reporter, fn *ast.FuncDecl, ctxParam stri
 				"context parameter %q is received but never used; pass it to sub-calls or remove it",
 				ctxParam)
 		}
-	} else if !contextMeaningfullyUsed && hasFunctionCalls && !isSimpleFunction(fn) {
+	} else if !contextMeaningfullyUsed && hasFunctionCalls && !isSimpleFunction(pass, fn) {
 		// Context is referenced but not used meaningfully (not passed to calls, no methods called)
 		// This might indicate missing context propagation
 		if ctxParam == "_" {
@@ -296,68 +584,210 @@ func containsIdent(expr ast.Expr, name string) bool {
 	return found
 }
 
-// isSimpleFunction checks if a function is simple enough that not propagating context is okay
-// (e.g., just returns a value, only does local computation)
-func isSimpleFunction(fn *ast.FuncDecl) bool {
+// isSimpleFunction checks if a function is simple enough that not propagating context is okay,
+// that is none of the functions it calls accept a context. Calls whose callee cannot be
+// resolved, such as conversions, are ignored.
+func isSimpleFunction(pass *analysis.Pass, fn *ast.FuncDecl) bool {
 	if fn.Body == nil {
 		return true
 	}
 
-	// Very short functions are likely simple
-	if len(fn.Body.List) <= 2 {
-		return true
+	simple := true
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && acceptsContext(pass, call) {
+			simple = false
+		}
+		return simple
+	})
+
+	return simple
+}
+
+// acceptsContext checks if the callee of a call has a context parameter
+func acceptsContext(pass *analysis.Pass, call *ast.CallExpr) bool {
+	t := pass.TypesInfo.TypeOf(call.Fun)
+	if t == nil {
+		return false
+	}
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok {
+		return false
+	}
+	params := sig.Params()
+	for i := 0; i < params.Len(); i++ {
+		if isContext(pass, params.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// derivingFuncs are the context functions deriving a cancelable context from a parent
+var derivingFuncs = map[string]bool{
+	"WithCancel":        true,
+	"WithCancelCause":   true,
+	"WithTimeout":       true,
+	"WithTimeoutCause":  true,
+	"WithDeadline":      true,
+	"WithDeadlineCause": true,
+}
+
+// checkUnnecessaryBackgroundContext detects context.Background/TODO when context available.
+// Detached goroutines and helpers constructing contexts are exempt, and a Background
+// used alongside the propagated parameter gets an advisory instead.
+func checkUnnecessaryBackgroundContext(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, ctxParam string) {
+	if isContextConstructor(pass, fn) {
+		return
 	}
 
-	// Functions that only have assignments and returns
-	hasOnlySimpleStmts := true
+	detached := detachedRoots(pass, fn.Body)
+	propagated := ctxParam != "_" && passesIdent(fn.Body, ctxParam)
+
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
-		switch n.(type) {
-		case *ast.CallExpr:
-			// Has function calls, not simple
-			hasOnlySimpleStmts = false
-			return false
+		call, ok := n.(*ast.CallExpr)
+		if !ok || detached[call] {
+			return true
 		}
+
+		name := contextFunc(pass, call)
+		if name != "Background" && name != "TODO" {
+			return true
+		}
+
+		if propagated {
+			reporter.Report(&analysis.Diagnostic{
+				Pos:      call.Pos(),
+				Category: nolint.CategoryAdvisory,
+				Message: fmt.Sprintf("context.%s() used alongside context parameter %q; derive from it with context.WithoutCancel(%s) if this work must outlive the call",
+					name, ctxParam, ctxParam),
+			})
+			return true
+		}
+
+		reporter.Reportf(call.Pos(),
+			"context.%s() used when context parameter is available; use the passed context instead", name)
 		return true
 	})
+}
 
-	return hasOnlySimpleStmts
+// isContextConstructor checks for helpers whose job is building a new or
+// detached context: those named like NewContext, DetachedContext, or
+// withoutCancel, and those returning a context
+func isContextConstructor(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	lower := strings.ToLower(fn.Name.Name)
+	if strings.HasSuffix(lower, "context") || strings.HasSuffix(lower, "ctx") ||
+		strings.Contains(lower, "detach") || strings.Contains(lower, "withoutcancel") {
+		return true
+	}
+	if fn.Type.Results == nil {
+		return false
+	}
+	for _, field := range fn.Type.Results.List {
+		if isContext(pass, pass.TypesInfo.TypeOf(field.Type)) {
+			return true
+		}
+	}
+	return false
 }
 
-// checkUnnecessaryBackgroundContext detects context.Background/TODO when context available
-func checkUnnecessaryBackgroundContext(reporter *nolint.Reporter, fn *ast.FuncDecl) {
-	ast.Inspect(fn.Body, func(n ast.Node) bool {
+// contextFunc returns the name of the context package function called, if any
+func contextFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "context" {
+		return ""
+	}
+	if fn.Type().(*types.Signature).Recv() != nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+// passesIdent checks if an identifier with the given name is passed as an argument to any call
+func passesIdent(body *ast.BlockStmt, name string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
-		if !ok {
-			return true
+		if !ok || found {
+			return !found
+		}
+		for _, arg := range call.Args {
+			if containsIdent(arg, name) {
+				found = true
+			}
 		}
+		return !found
+	})
+	return found
+}
 
-		sel, ok := call.Fun.(*ast.SelectorExpr)
+// detachedRoots returns the context.Background/TODO calls that root a context derived
+// with a timeout or cancellation and only used inside go statements, as in
+//
+//	bg, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	go func() { defer cancel(); cleanup(bg) }()
+func detachedRoots(pass *analysis.Pass, body *ast.BlockStmt) map[*ast.CallExpr]bool {
+	var goStmts []*ast.GoStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		if g, ok := n.(*ast.GoStmt); ok {
+			goStmts = append(goStmts, g)
+		}
+		return true
+	})
+	inGo := func(pos token.Pos) bool {
+		for _, g := range goStmts {
+			if g.Pos() <= pos && pos < g.End() {
+				return true
+			}
+		}
+		return false
+	}
+
+	roots := make(map[*ast.CallExpr]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return true
+		}
+		derive, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok || !derivingFuncs[contextFunc(pass, derive)] || len(derive.Args) == 0 {
+			return true
+		}
+		root, ok := derive.Args[0].(*ast.CallExpr)
 		if !ok {
 			return true
 		}
-
-		ident, ok := sel.X.(*ast.Ident)
+		if name := contextFunc(pass, root); name != "Background" && name != "TODO" {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
 		if !ok {
 			return true
 		}
+		obj := pass.TypesInfo.ObjectOf(ident)
+		if obj == nil {
+			return true
+		}
 
-		if ident.Name == "context" {
-			switch sel.Sel.Name {
-			case "Background":
-				reporter.Reportf(call.Pos(),
-					"context.Background() used when context parameter is available; use the passed context instead")
-			case "TODO":
-				reporter.Reportf(call.Pos(),
-					"context.TODO() used when context parameter is available; use the passed context instead")
+		uses := 0
+		detached := true
+		ast.Inspect(body, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if ok && id != ident && pass.TypesInfo.Uses[id] == obj {
+				uses++
+				detached = detached && inGo(id.Pos())
 			}
+			return true
+		})
+		if uses > 0 && detached {
+			roots[root] = true
 		}
-
 		return true
 	})
+	return roots
 }
 
 // checkCallsWithoutContext checks for calls that should pass context but don't
-func checkCallsWithoutContext(reporter *nolint.Reporter, fn *ast.FuncDecl, ctxParam string) {
+func checkCallsWithoutContext(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
 		if !ok {
@@ -388,7 +818,7 @@ func checkCallsWithoutContext(reporter *nolint.Reporter, fn *ast.FuncDecl, ctxPa
 		methodName := sel.Sel.Name
 		if advice, needsContext := methodsRequiringContext[methodName]; needsContext {
 			// Check if first argument is context
-			if !firstArgIsContext(call, ctxParam) {
+			if !firstArgIsContext(pass, call) {
 				reporter.Reportf(call.Pos(),
 					"%s() called without context as first argument; %s", methodName, advice)
 			}
@@ -398,45 +828,17 @@ func checkCallsWithoutContext(reporter *nolint.Reporter, fn *ast.FuncDecl, ctxPa
 	})
 }
 
-// firstArgIsContext checks if the first argument to a call is a context
-func firstArgIsContext(call *ast.CallExpr, ctxParam string) bool {
+// firstArgIsContext checks if the static type of the first argument to a call is a context,
+// whatever the argument is named
+func firstArgIsContext(pass *analysis.Pass, call *ast.CallExpr) bool {
 	if len(call.Args) == 0 {
 		return false
 	}
-
-	firstArg := call.Args[0]
-
-	// Check if it's the context parameter directly
-	if ident, ok := firstArg.(*ast.Ident); ok {
-		if ident.Name == ctxParam || ident.Name == "ctx" {
-			return true
-		}
-	}
-
-	// Check for context.Background(), context.TODO(), or context.WithX()
-	if call, ok := firstArg.(*ast.CallExpr); ok {
-		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-			if ident, ok := sel.X.(*ast.Ident); ok {
-				if ident.Name == "context" {
-					return true
-				}
-			}
-		}
-	}
-
-	// Check for derived contexts like ctx.WithValue, etc.
-	argStr := types.ExprString(firstArg)
-	if strings.Contains(argStr, "ctx") || strings.Contains(argStr, "Context") {
-		return true
-	}
-
-	return false
+	return isContext(pass, pass.TypesInfo.TypeOf(call.Args[0]))
 }
 
 // checkContextAwareCalls checks for calls that have context-aware variants
-func checkContextAwareCalls(reporter *nolint.Reporter, fn *ast.FuncDecl, hasContext bool) {
-	ctxParam := getContextParam(fn)
-
+func checkContextAwareCalls(reporter *nolint.Reporter, pass *analysis.Pass, file *ast.File, fn *ast.FuncDecl, hasContext bool) {
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
 		if !ok {
@@ -451,9 +853,15 @@ func checkContextAwareCalls(reporter *nolint.Reporter, fn *ast.FuncDecl, hasCont
 		// ALWAYS flag http.NewRequest - there's no good reason to use it
 		// Use http.NewRequestWithContext even with context.Background() to be explicit
 		if callName == "http.NewRequest" {
-			reporter.Reportf(call.Pos(),
-				"http.NewRequest is deprecated in favor of http.NewRequestWithContext; "+
-					"always use http.NewRequestWithContext(ctx, method, url, body) for proper context propagation")
+			reporter.Report(&analysis.Diagnostic{
+				Pos: call.Pos(),
+				End: call.End(),
+				Message: "http.NewRequest is deprecated in favor of http.NewRequestWithContext; " +
+					"always use http.NewRequestWithContext(ctx, method, url, body) for proper context propagation",
+				SuggestedFixes: []analysis.SuggestedFix{
+					ctxfix.NewRequestWithContext(file, call, ctxfix.FuncContextParam(pass.TypesInfo, fn.Type)),
+				},
+			})
 		}
 
 		// Only check the rest if context is available
@@ -477,7 +885,7 @@ func checkContextAwareCalls(reporter *nolint.Reporter, fn *ast.FuncDecl, hasCont
 
 		// Check if this is a method that has a Context variant and context isn't being passed
 		if advice, needsContext := methodsRequiringContext[methodName]; needsContext {
-			if !firstArgIsContext(call, ctxParam) {
+			if !firstArgIsContext(pass, call) {
 				reporter.Reportf(call.Pos(),
 					"%s() called without context; %s", methodName, advice)
 			}
@@ -0,0 +1,34 @@
+package contextpropagation_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/contextpropagation"
+)
+
+func TestContextPropagationAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextpropagation.Analyzer, "a")
+}
+
+func TestContextPropagationNewRequestFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, contextpropagation.Analyzer, "newrequest")
+}
+
+func TestContextPropagationTypes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextpropagation.Analyzer, "calls")
+}
+
+func TestContextPropagationBackground(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextpropagation.Analyzer, "background")
+}
+
+func TestContextPropagationAcrossPackages(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, contextpropagation.Analyzer, "example.com/app", "myservice/app")
+}
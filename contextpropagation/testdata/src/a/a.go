@@ -0,0 +1,79 @@
+package a
+
+import (
+	"context"
+	stdctx "context"
+	"net/http"
+	"time"
+)
+
+// Bad: context stored in a field
+type worker struct {
+	ctx  context.Context // want `struct field worker.ctx stores a context.Context`
+	name string
+}
+
+func (w *worker) run() error {
+	return w.ctx.Err()
+}
+
+// Bad: pointer-to-struct with an aliased context import
+type server struct {
+	base   stdctx.Context // want `struct field server.base stores a context.Context`
+	parent *server
+}
+
+func newServer(parent *server) *server {
+	return &server{parent: parent}
+}
+
+// Bad: alias of the context type
+type Ctx = context.Context
+
+type job struct {
+	c Ctx // want `struct field job.c stores a context.Context`
+}
+
+// Bad: pointer to a context
+type holder struct {
+	ctx *context.Context // want `struct field holder.ctx stores a context.Context`
+}
+
+// Bad: embedded context
+type session struct {
+	context.Context // want `struct session embeds context.Context`
+	user            string
+}
+
+// Good: derived context overriding context methods
+type valueCtx struct {
+	context.Context
+	key, val any
+}
+
+func (c *valueCtx) Value(key any) any {
+	if key == c.key {
+		return c.val
+	}
+	return c.Context.Value(key)
+}
+
+// Good: request-embedding types carry the request's context
+type wrappedRequest struct {
+	*http.Request
+	ctx context.Context
+}
+
+// Good: context passed to the method
+type poller struct {
+	interval time.Duration
+}
+
+func (p *poller) poll(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Suppressed with nolint
+type legacy struct {
+	ctx context.Context //nolint:contextpropagation // matches the upstream API
+}
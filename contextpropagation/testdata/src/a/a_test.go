@@ -0,0 +1,8 @@
+package a
+
+import "context"
+
+// Good: test harness structs are exempt
+type harness struct {
+	ctx context.Context
+}
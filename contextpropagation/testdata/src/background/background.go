@@ -0,0 +1,75 @@
+package background
+
+import (
+	"context"
+	"time"
+)
+
+func save(ctx context.Context, id string) error { return ctx.Err() }
+
+func cleanup(ctx context.Context, id string) { <-ctx.Done() }
+
+// Good: cleanup outlives the request, so it gets its own deadline
+func Delete(ctx context.Context, id string) error {
+	if err := save(ctx, id); err != nil {
+		return err
+	}
+
+	bg, cancel := context.WithTimeout(context.Background(), time.Minute)
+	go func() {
+		defer cancel()
+		cleanup(bg, id)
+	}()
+	return nil
+}
+
+// Good: helpers building detached contexts are the one place for Background
+func DetachedContext(ctx context.Context) context.Context {
+	return &detached{parent: ctx, Context: context.Background()}
+}
+
+func newContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.TODO()
+	}
+	return ctx
+}
+
+type detached struct {
+	context.Context
+	parent context.Context
+}
+
+func (d *detached) Value(key any) any { return d.parent.Value(key) }
+
+// Bad: the derived context is also used outside the goroutine
+func Archive(ctx context.Context, id string) error {
+	bg, cancel := context.WithTimeout(context.Background(), time.Minute) // want `context.Background\(\) used alongside context parameter "ctx"`
+	defer cancel()
+	go cleanup(bg, id)
+	if err := save(bg, id); err != nil {
+		return err
+	}
+	return save(ctx, id)
+}
+
+// Bad: Background instead of the parameter
+func Update(ctx context.Context, id string) error { // want `context parameter "ctx" is not passed to any sub-function calls`
+	if ctx == nil {
+		return nil
+	}
+	return save(context.Background(), id) // want `context.Background\(\) used when context parameter is available; use the passed context instead`
+}
+
+// Bad: TODO alongside the propagated parameter
+func Touch(ctx context.Context, id string) error {
+	if err := save(ctx, id); err != nil {
+		return err
+	}
+	return save(context.TODO(), id) // want `context.TODO\(\) used alongside context parameter "ctx"; derive from it with context.WithoutCancel\(ctx\)`
+}
+
+// Good: returns a context built around the parameter's values
+func valuesOnly(ctx context.Context) context.Context {
+	return &detached{parent: ctx, Context: context.Background()}
+}
@@ -0,0 +1,69 @@
+package calls
+
+import (
+	"context"
+	"time"
+)
+
+type db struct{}
+
+func (db) Query(query string, args ...any) error { return nil }
+
+func (db) QueryContext(ctx context.Context, query string, args ...any) error { return ctx.Err() }
+
+type store struct{}
+
+// Query takes the context first, like a repository wrapping the database
+func (store) Query(ctx context.Context, query string) error { return ctx.Err() }
+
+type ctxData struct {
+	SQL string
+}
+
+// Bad: a struct named like a context is not a context
+func lookup(ctx context.Context, d db, ctxData ctxData, args []any) error {
+	if err := d.QueryContext(ctx, ctxData.SQL, args...); err != nil {
+		return err
+	}
+	return d.Query(ctxData.SQL, args...) // want `Query\(\) called without context as first argument` `Query\(\) called without context;`
+}
+
+// Good: a renamed context parameter is still a context
+func find(c context.Context, s store) error {
+	return s.Query(c, "SELECT 1")
+}
+
+// Good: a derived context under another name
+func findWithTimeout(ctx context.Context, s store) error {
+	c, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	return s.Query(c, "SELECT 1")
+}
+
+type service struct {
+	s store
+}
+
+func (svc *service) load(ctx context.Context, id string) error {
+	return svc.s.Query(ctx, id)
+}
+
+func (svc *service) name(id string) string {
+	return "service/" + id
+}
+
+// Bad: the only call takes a context, but not the one received
+func (svc *service) Get(ctx context.Context, id string) error { // want `context parameter "ctx" is not passed to any sub-function calls`
+	if ctx == nil {
+		return nil
+	}
+	return svc.load(context.Background(), id) // want `context.Background\(\) used when context parameter is available`
+}
+
+// Good: none of the callees accept a context
+func (svc *service) Describe(ctx context.Context, id string) string {
+	if ctx == nil {
+		return ""
+	}
+	return svc.name(id)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"example.com/client"
+)
+
+func Load(ctx context.Context, c *client.Client, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	resp, err := c.Fetch(id) // want `c.Fetch does I/O through net/http.Get without accepting a context, so ctx cannot cancel it; add a context.Context parameter to Fetch`
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func Sync(ctx context.Context, c *client.Client, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Refresh(id) // want `c.Refresh does I/O through net/http.Get without accepting a context`
+}
+
+func Report(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return client.Version() // want `client.Version does I/O through os/exec.Command without accepting a context`
+}
+
+func LoadContext(ctx context.Context, c *client.Client, id string) (*http.Response, error) {
+	_ = c.URL(id)
+	return c.FetchContext(ctx, id)
+}
+
+// local does I/O through an imported function without a context of its own
+func local(c *client.Client) string { // want local:"performsIO\\(net/http.Get\\)"
+	if _, err := c.Fetch("local"); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func UseLocal(ctx context.Context, c *client.Client) string {
+	_ = ctx.Err()
+	return local(c) // want `local does I/O through net/http.Get without accepting a context`
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+)
+
+type Client struct {
+	base string
+}
+
+func (c *Client) Fetch(id string) (*http.Response, error) { // want Fetch:"performsIO\\(net/http.Get\\)"
+	return http.Get(c.base + "/items/" + id)
+}
+
+// Refresh does its I/O through Fetch, so it inherits Fetch's operation
+func (c *Client) Refresh(id string) error { // want Refresh:"performsIO\\(net/http.Get\\)"
+	resp, err := c.Fetch(id)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func Version() ([]byte, error) { // want Version:"performsIO\\(os/exec.Command\\)"
+	return exec.Command("git", "--version").Output()
+}
+
+// FetchContext accepts a context, so callers can cancel it
+func (c *Client) FetchContext(ctx context.Context, id string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/items/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// URL does no I/O
+func (c *Client) URL(id string) string {
+	return c.base + "/items/" + id
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"context"
+
+	"myservice/client"
+)
+
+func Load(ctx context.Context, c *client.Client, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	resp, err := c.Fetch(id) // want `c.Fetch does I/O through net/http.Get without accepting a context`
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,13 @@
+// Package client is in a module declared as module myservice, whose import
+// paths have no dot but are not the standard library
+package client
+
+import "net/http"
+
+type Client struct {
+	base string
+}
+
+func (c *Client) Fetch(id string) (*http.Response, error) { // want Fetch:"performsIO\\(net/http.Get\\)"
+	return http.Get(c.base + "/items/" + id)
+}
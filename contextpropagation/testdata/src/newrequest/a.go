@@ -0,0 +1,9 @@
+package newrequest
+
+import (
+	"net/http"
+)
+
+func Fetch(url string) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, url, nil) // want `http.NewRequest is deprecated in favor of http.NewRequestWithContext`
+}
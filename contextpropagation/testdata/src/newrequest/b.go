@@ -0,0 +1,17 @@
+package newrequest
+
+import (
+	"context"
+	"net/http"
+)
+
+func Named(reqCtx context.Context, url string) (*http.Request, error) {
+	if err := reqCtx.Err(); err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodGet, url, nil) // want `http.NewRequest is deprecated in favor of http.NewRequestWithContext`
+}
+
+func Fixed(ctx context.Context, url string) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+}
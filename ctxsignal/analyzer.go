@@ -0,0 +1,430 @@
+// Package ctxsignal provides an analyzer that checks os/signal handling at
+// program entry points.
+//
+// signal.Notify never blocks when delivering a signal; if the channel has no
+// buffer space the signal is dropped. Notify without a signal list subscribes
+// to everything, including the SIGURG the runtime uses for preemption. And a
+// NotifyContext whose stop function is never deferred keeps intercepting
+// Ctrl-C after the program has decided to shut down.
+package ctxsignal
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check os/signal handling for lost, uncatchable, and ignored signals
+
+This analyzer detects:
+1. signal.Notify with an unbuffered channel; signals are dropped when the
+   receiver is not ready
+2. signal.Notify without a signal list, which also delivers runtime noise
+   such as SIGURG
+3. Notify/NotifyContext/Ignore on SIGKILL or SIGSTOP, which cannot be caught
+4. Notify channels that are never read
+5. Notify channels read exactly once without signal.Stop or signal.Reset, so
+   a second Ctrl-C cannot force the program to exit (advisory)
+6. signal.NotifyContext whose stop function is not deferred
+
+Bad:
+    sigs := make(chan os.Signal)
+    signal.Notify(sigs)
+
+    ctx, _ := signal.NotifyContext(ctx, os.Interrupt)
+
+Good:
+    ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    <-ctx.Done()
+    stop() // a second Ctrl-C now terminates immediately
+
+Flags:
+    -check-force-exit  report channels read once without signal.Stop or Reset`
+
+var checkForceExit bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "ctxsignal",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("ctxsignal", flag.ExitOnError)
+	fs.BoolVar(&checkForceExit, "check-force-exit", true,
+		"report signal channels read once without signal.Stop or signal.Reset")
+	return *fs
+}
+
+// uncatchable lists signals the kernel never delivers to the process
+var uncatchable = map[string]bool{
+	"SIGKILL": true,
+	"SIGSTOP": true,
+}
+
+// notifyCall is a signal.Notify call on a local channel
+type notifyCall struct {
+	call *ast.CallExpr
+	ch   types.Object
+}
+
+// stopFunc is the stop function returned by signal.NotifyContext
+type stopFunc struct {
+	call *ast.CallExpr
+	obj  types.Object
+}
+
+// channelUse summarizes how a function uses a signal channel
+type channelUse struct {
+	reads    int
+	loopRead bool
+	stopped  bool
+	made     *ast.CallExpr
+}
+
+// funcState collects signal handling facts for one function body
+type funcState struct {
+	pass     *analysis.Pass
+	reporter *nolint.Reporter
+	notifies []notifyCall
+	stops    []stopFunc
+	uses     map[types.Object]*channelUse
+	escaped  map[types.Object]bool
+	deferred map[types.Object]bool
+	reset    bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil {
+			return
+		}
+
+		s := &funcState{
+			pass:     pass,
+			reporter: reporter,
+			uses:     make(map[types.Object]*channelUse),
+			escaped:  make(map[types.Object]bool),
+			deferred: make(map[types.Object]bool),
+		}
+		s.inspect(fn.Body)
+		s.report()
+	})
+
+	return nil, nil
+}
+
+// signalFunc returns the name of an os/signal function called by call
+func signalFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "os/signal" {
+		return ""
+	}
+	return fn.Name()
+}
+
+// localObject returns the variable an identifier expression refers to
+func localObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !ok || v.IsField() {
+		return nil
+	}
+	return v
+}
+
+// use returns the channel summary for obj, creating it on first use
+func (s *funcState) use(obj types.Object) *channelUse {
+	if s.uses[obj] == nil {
+		s.uses[obj] = &channelUse{}
+	}
+	return s.uses[obj]
+}
+
+// inspect walks one function body, including its closures, so a channel read
+// in a goroutine counts for the Notify call that set it up
+func (s *funcState) inspect(body *ast.BlockStmt) {
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			s.checkCall(node)
+		case *ast.DeferStmt:
+			s.recordDefer(node)
+		case *ast.UnaryExpr:
+			if obj := localObject(s.pass, node.X); obj != nil && node.Op == token.ARROW {
+				u := s.use(obj)
+				u.reads++
+				if insideLoop(stack) {
+					u.loopRead = true
+				}
+			}
+		case *ast.RangeStmt:
+			if obj := localObject(s.pass, node.X); obj != nil {
+				u := s.use(obj)
+				u.reads++
+				u.loopRead = true
+			}
+		case *ast.AssignStmt:
+			s.recordAssign(node.Lhs, node.Rhs)
+			s.markEscapes(assignedValues(node.Lhs, node.Rhs))
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			s.recordAssign(lhs, node.Values)
+			s.markEscapes(node.Values)
+		case *ast.ReturnStmt:
+			s.markEscapes(node.Results)
+		case *ast.SendStmt:
+			s.markEscapes([]ast.Expr{node.Value})
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				s.markEscapes([]ast.Expr{elt})
+			}
+		}
+		return true
+	})
+}
+
+// checkCall handles the os/signal functions and marks values passed to any
+// other function as escaping
+func (s *funcState) checkCall(call *ast.CallExpr) {
+	switch signalFunc(s.pass, call) {
+	case "Notify":
+		if len(call.Args) == 0 {
+			return
+		}
+		s.reportUncatchable(call.Args[1:])
+		if len(call.Args) == 1 {
+			s.reporter.Reportf(call.Pos(),
+				"signal.Notify without a signal list relays every signal, including runtime SIGURG; list the signals to handle")
+		}
+		if obj := localObject(s.pass, call.Args[0]); obj != nil {
+			s.use(obj)
+			s.notifies = append(s.notifies, notifyCall{call: call, ch: obj})
+		}
+	case "NotifyContext":
+		if len(call.Args) > 0 {
+			s.reportUncatchable(call.Args[1:])
+		}
+	case "Ignore":
+		s.reportUncatchable(call.Args)
+	case "Reset":
+		s.reportUncatchable(call.Args)
+		s.reset = true
+	case "Stop":
+		if len(call.Args) == 1 {
+			if obj := localObject(s.pass, call.Args[0]); obj != nil {
+				s.use(obj).stopped = true
+			}
+		}
+	default:
+		s.markEscapes(call.Args)
+	}
+}
+
+// recordDefer remembers functions called by defer, directly or from a deferred closure
+func (s *funcState) recordDefer(stmt *ast.DeferStmt) {
+	if obj := localObject(s.pass, stmt.Call.Fun); obj != nil {
+		s.deferred[obj] = true
+		return
+	}
+	lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if obj := localObject(s.pass, call.Fun); obj != nil {
+				s.deferred[obj] = true
+			}
+		}
+		return true
+	})
+}
+
+// recordAssign remembers ch := make(chan os.Signal, n) and
+// ctx, stop := signal.NotifyContext(...)
+func (s *funcState) recordAssign(lhs, rhs []ast.Expr) {
+	if len(rhs) == 1 && len(lhs) == 2 {
+		if call, ok := ast.Unparen(rhs[0]).(*ast.CallExpr); ok && signalFunc(s.pass, call) == "NotifyContext" {
+			s.stops = append(s.stops, stopFunc{call: call, obj: localObject(s.pass, lhs[1])})
+		}
+		return
+	}
+
+	if len(lhs) != len(rhs) {
+		return
+	}
+	for i, r := range rhs {
+		call, ok := ast.Unparen(r).(*ast.CallExpr)
+		if !ok || !isMakeChan(s.pass, call) {
+			continue
+		}
+		if obj := localObject(s.pass, lhs[i]); obj != nil {
+			s.use(obj).made = call
+		}
+	}
+}
+
+// markEscapes records values handed to code this function cannot see
+func (s *funcState) markEscapes(exprs []ast.Expr) {
+	for _, e := range exprs {
+		if obj := localObject(s.pass, e); obj != nil {
+			s.escaped[obj] = true
+		}
+	}
+}
+
+// assignedValues drops values assigned to the blank identifier, which do not
+// escape
+func assignedValues(lhs, rhs []ast.Expr) []ast.Expr {
+	if len(lhs) != len(rhs) {
+		return rhs
+	}
+	var values []ast.Expr
+	for i, r := range rhs {
+		if ident, ok := lhs[i].(*ast.Ident); ok && ident.Name == "_" {
+			continue
+		}
+		values = append(values, r)
+	}
+	return values
+}
+
+// insideLoop checks if the innermost function on the stack has a loop around
+// the current node
+func insideLoop(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		case *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
+
+// isMakeChan checks for make(chan T[, n])
+func isMakeChan(pass *analysis.Pass, call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	if _, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); !ok || ident.Name != "make" {
+		return false
+	}
+	_, ok = pass.TypesInfo.TypeOf(call.Args[0]).Underlying().(*types.Chan)
+	return ok
+}
+
+// reportUncatchable flags SIGKILL and SIGSTOP in a signal list
+func (s *funcState) reportUncatchable(args []ast.Expr) {
+	for _, arg := range args {
+		sel, ok := ast.Unparen(arg).(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		c, ok := s.pass.TypesInfo.Uses[sel.Sel].(*types.Const)
+		if !ok || !uncatchable[c.Name()] {
+			continue
+		}
+		s.reporter.Reportf(arg.Pos(),
+			"%s cannot be caught, blocked, or ignored; handling it here is dead code", c.Name())
+	}
+}
+
+// report emits the diagnostics that need the whole function body
+func (s *funcState) report() {
+	for _, nc := range s.notifies {
+		s.checkChannel(nc)
+	}
+
+	for _, sf := range s.stops {
+		switch {
+		case sf.obj == nil:
+			s.reporter.Reportf(sf.call.Pos(),
+				"stop function of signal.NotifyContext is discarded; signal handling is never restored and the context leaks")
+		case !s.deferred[sf.obj] && !s.escaped[sf.obj]:
+			s.reporter.Reportf(sf.call.Pos(),
+				"stop function %s of signal.NotifyContext is not deferred; add defer %s() so signal handling is restored on every return path",
+				sf.obj.Name(), sf.obj.Name())
+		}
+	}
+}
+
+// checkChannel reports buffer and read problems for one Notify channel
+func (s *funcState) checkChannel(nc notifyCall) {
+	u := s.uses[nc.ch]
+
+	if u.made != nil && isUnbuffered(s.pass, u.made) {
+		s.reporter.Reportf(nc.call.Pos(),
+			"signal.Notify on unbuffered channel %s; signals sent while the receiver is busy are dropped, use make(chan os.Signal, 1)",
+			nc.ch.Name())
+	}
+
+	if s.escaped[nc.ch] {
+		return
+	}
+
+	switch {
+	case u.reads == 0:
+		s.reporter.Reportf(nc.call.Pos(),
+			"signal channel %s is never read; the signals are silently swallowed", nc.ch.Name())
+	case checkForceExit && u.reads == 1 && !u.loopRead && !u.stopped && !s.reset:
+		s.reporter.Reportf(nc.call.Pos(),
+			"signal channel %s is read once and never stopped; call signal.Stop(%s) after the first signal so a second one forces exit",
+			nc.ch.Name(), nc.ch.Name())
+	}
+}
+
+// isUnbuffered checks for make(chan T) or make(chan T, 0)
+func isUnbuffered(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if len(call.Args) < 2 {
+		return true
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[1]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return false
+	}
+	return constant.Sign(tv.Value) == 0
+}
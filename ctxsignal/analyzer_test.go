@@ -0,0 +1,14 @@
+package ctxsignal_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/ctxsignal"
+)
+
+func TestCtxSignalAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, ctxsignal.Analyzer, "a")
+}
@@ -0,0 +1,138 @@
+package a
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Bad: unbuffered channel drops signals
+func UnbufferedNotify() {
+	sigs := make(chan os.Signal)
+	signal.Notify(sigs, os.Interrupt) // want `signal.Notify on unbuffered channel sigs`
+	for range sigs {
+	}
+}
+
+// Bad: explicit zero buffer
+func ZeroBuffer() {
+	var sigs = make(chan os.Signal, 0)
+	signal.Notify(sigs, syscall.SIGTERM) // want `signal.Notify on unbuffered channel sigs`
+	for range sigs {
+	}
+}
+
+// Bad: no signal list
+func AllSignals() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs) // want `signal.Notify without a signal list relays every signal`
+	for range sigs {
+	}
+}
+
+// Bad: SIGKILL can never be delivered
+func CatchKill() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGKILL) // want `SIGKILL cannot be caught, blocked, or ignored`
+	for range sigs {
+	}
+}
+
+// Bad: ignoring SIGSTOP has no effect
+func IgnoreStop() {
+	signal.Ignore(syscall.SIGSTOP) // want `SIGSTOP cannot be caught, blocked, or ignored`
+}
+
+// Bad: nobody reads the channel
+func NeverRead() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt) // want `signal channel sigs is never read`
+}
+
+// Bad: a second Ctrl-C is swallowed too
+func ReadOnce() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt) // want `signal channel sigs is read once and never stopped`
+	<-sigs
+	shutdown()
+}
+
+// Bad: stop is never deferred
+func NotifyContextNoDefer(ctx context.Context) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt) // want `stop function stop of signal.NotifyContext is not deferred`
+	<-ctx.Done()
+	stop()
+}
+
+// Bad: assigning to blank is not a hand-off
+func NotifyContextBlank(ctx context.Context) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt) // want `stop function stop of signal.NotifyContext is not deferred`
+	_ = stop
+	<-ctx.Done()
+}
+
+// Bad: stop is discarded
+func NotifyContextDiscarded(ctx context.Context) {
+	ctx, _ = signal.NotifyContext(ctx, os.Interrupt) // want `stop function of signal.NotifyContext is discarded`
+	<-ctx.Done()
+}
+
+// Good: canonical setup
+func Canonical(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	<-ctx.Done()
+	stop()
+	return nil
+}
+
+// Good: buffered channel, stopped after the first signal
+func ForceExit() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	<-sigs
+	signal.Stop(sigs)
+	shutdown()
+}
+
+// Good: read in a goroutine loop
+func Background() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-sigs:
+				reload()
+			}
+		}
+	}()
+}
+
+// Good: stop deferred from a closure
+func DeferredClosure(ctx context.Context) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer func() {
+		stop()
+	}()
+	<-ctx.Done()
+}
+
+// Good: the channel is handed to another component
+func Handoff() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	watch(sigs)
+}
+
+// Good: the stop function is returned to the caller
+func Setup(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	return ctx, stop
+}
+
+func shutdown()            {}
+func reload()              {}
+func watch(chan os.Signal) {}
@@ -5,6 +5,8 @@
 package dataflow
 
 import (
+	"flag"
+	"go/token"
 	"go/types"
 	"strings"
 
@@ -12,6 +14,7 @@ import (
 	"golang.org/x/tools/go/analysis/passes/buildssa"
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/spechtlabs/golint-sl/internal/facts"
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
 
@@ -20,18 +23,48 @@ const Doc = `track data flow using SSA to detect security issues
 This analyzer uses SSA to trace how values flow through the program:
 1. Sensitive data (passwords, tokens, secrets) should not flow to logs
 2. User input should be validated before reaching dangerous operations
-3. Context should be propagated correctly through the call chain
+3. Context should be propagated correctly through the call chain: a
+   function with a context must not call one that passes its context on,
+   in this or an imported package, with nil or context.Background()
 4. Errors should be wrapped, not discarded
 
-SSA analysis provides more accurate flow tracking than AST alone.`
+SSA analysis provides more accurate flow tracking than AST alone.
+
+Sensitive parameters are found by name. A value passed through a sanitizer,
+a function whose name contains Redact, Mask, Hash, or Sanitize, is no
+longer sensitive. Sinks are logging and print functions, plus any functions
+given with -sinks.
+
+Flags:
+    -sensitive   comma-separated additional parameter name patterns
+    -sinks       comma-separated additional sink patterns, matched against
+                 qualified names like example.com/audit.Emit or
+                 example.com/audit.Logger.Emit
+    -sanitizers  comma-separated additional sanitizer name patterns`
+
+// Configured patterns, appended to the defaults below
+var (
+	sensitiveNames string
+	sinkFuncs      string
+	sanitizerFuncs string
+)
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "dataflow",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(buildssa.Analyzer, facts.NeedsContextAnalyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("dataflow", flag.ExitOnError)
+	fs.StringVar(&sensitiveNames, "sensitive", "", "comma-separated additional parameter name patterns")
+	fs.StringVar(&sinkFuncs, "sinks", "", "comma-separated additional sink patterns, matched against qualified function names")
+	fs.StringVar(&sanitizerFuncs, "sanitizers", "", "comma-separated additional sanitizer name patterns")
+	return *fs
+}
+
 // SensitivePatterns are parameter/variable names that might contain sensitive data
 var SensitivePatterns = []string{
 	"password", "passwd", "pwd",
@@ -41,6 +74,10 @@ var SensitivePatterns = []string{
 	"private", "cert", "certificate",
 }
 
+// Sanitizers are patterns of function names whose result no longer holds the
+// sensitive data passed to them
+var Sanitizers = []string{"Redact", "Mask", "Hash", "Sanitize"}
+
 // DangerousSinks are functions that should not receive unvalidated/sensitive data
 var DangerousSinks = []string{
 	"log.Print", "log.Printf", "log.Println",
@@ -50,16 +87,44 @@ var DangerousSinks = []string{
 	"sql.Query", "sql.Exec", // SQL injection risk
 }
 
+// patterns holds the defaults plus the configured patterns
+type patterns struct {
+	sensitive  []string
+	sinks      []string
+	sanitizers []string
+}
+
+func configured() patterns {
+	return patterns{
+		sensitive:  append(splitList(sensitiveNames), SensitivePatterns...),
+		sinks:      splitList(sinkFuncs),
+		sanitizers: append(splitList(sanitizerFuncs), Sanitizers...),
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	ssaInfo := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	cfg := configured()
+	needs := pass.ResultOf[facts.NeedsContextAnalyzer].(facts.NeedsContextResult)
 
 	for _, fn := range ssaInfo.SrcFuncs {
 		// Check for sensitive data flowing to logs
-		checkSensitiveDataLeaks(reporter, fn)
+		checkSensitiveDataLeaks(reporter, cfg, fn)
 
 		// Check for context propagation
-		checkContextPropagation(reporter, fn)
+		checkContextPropagation(reporter, fn, needs)
 
 		// Check for error handling
 		checkErrorFlow(fn)
@@ -69,14 +134,14 @@ func run(pass *analysis.Pass) (interface{}, error) {
 }
 
 // checkSensitiveDataLeaks traces sensitive parameters to see if they reach logging
-func checkSensitiveDataLeaks(reporter *nolint.Reporter, fn *ssa.Function) {
+func checkSensitiveDataLeaks(reporter *nolint.Reporter, cfg patterns, fn *ssa.Function) {
 	for _, param := range fn.Params {
 		paramName := strings.ToLower(param.Name())
 
 		// Check if this parameter looks sensitive
 		isSensitive := false
-		for _, pattern := range SensitivePatterns {
-			if strings.Contains(paramName, pattern) {
+		for _, pattern := range cfg.sensitive {
+			if strings.Contains(paramName, strings.ToLower(pattern)) {
 				isSensitive = true
 				break
 			}
@@ -87,63 +152,214 @@ func checkSensitiveDataLeaks(reporter *nolint.Reporter, fn *ssa.Function) {
 		}
 
 		// Trace where this value flows
-		sinks := traceToSinks(param, make(map[ssa.Value]bool))
-
-		for _, sink := range sinks {
-			if call, ok := sink.(*ssa.Call); ok {
-				callee := call.Call.StaticCallee()
-				if callee != nil && isLoggingOrPrintFunction(callee) {
-					reporter.Reportf(call.Pos(),
-						"sensitive parameter %q may be logged; sanitize or redact before logging",
-						param.Name())
-				}
+		for _, call := range traceToSinks(cfg, param) {
+			callee := call.Call.StaticCallee()
+			if isLoggingOrPrintFunction(callee) {
+				reporter.Reportf(call.Pos(),
+					"sensitive parameter %q may be logged; sanitize or redact before logging",
+					param.Name())
+			} else {
+				reporter.Reportf(call.Pos(),
+					"sensitive parameter %q reaches %s; sanitize or redact it first",
+					param.Name(), callee.Name())
 			}
 		}
 	}
 }
 
-// traceToSinks follows a value through the SSA graph to find where it's used
-func traceToSinks(value ssa.Value, visited map[ssa.Value]bool) []ssa.Instruction {
-	if visited[value] {
-		return nil
+// stringBuilders are functions whose result contains their arguments, so
+// taint flows through them
+var stringBuilders = map[string]bool{
+	"fmt.Sprint":   true,
+	"fmt.Sprintf":  true,
+	"fmt.Sprintln": true,
+	"fmt.Append":   true,
+	"fmt.Appendf":  true,
+	"fmt.Appendln": true,
+	"strings.Join": true,
+}
+
+// taint follows a sensitive value through one function. Besides values
+// computed from it, it flows through string-building calls, string
+// concatenation, and stores: into variadic argument arrays, into variables,
+// and into struct fields, which taints later loads of the same field and
+// uses of the whole struct.
+type taint struct {
+	cfg     patterns
+	visited map[ssa.Value]bool
+	// fields holds the tainted fields of structs that are only partly
+	// tainted, keyed by the struct pointer or value
+	fields map[ssa.Value]map[int]bool
+	seen   map[*ssa.Call]bool
+	sinks  []*ssa.Call
+}
+
+// traceToSinks returns the logging and configured sink calls value reaches
+func traceToSinks(cfg patterns, value ssa.Value) []*ssa.Call {
+	t := &taint{
+		cfg:     cfg,
+		visited: make(map[ssa.Value]bool),
+		fields:  make(map[ssa.Value]map[int]bool),
+		seen:    make(map[*ssa.Call]bool),
 	}
-	visited[value] = true
+	t.trace(value)
+	return t.sinks
+}
 
-	var sinks []ssa.Instruction
+func (t *taint) trace(value ssa.Value) {
+	if t.visited[value] {
+		return
+	}
+	t.visited[value] = true
 
 	refs := value.Referrers()
 	if refs == nil {
-		return sinks
+		return
 	}
 
 	for _, ref := range *refs {
-		// If this is a call instruction, it's a potential sink
-		if call, ok := ref.(*ssa.Call); ok {
-			sinks = append(sinks, call)
+		switch instr := ref.(type) {
+		case *ssa.Call:
+			t.call(instr)
+
+		case *ssa.Store:
+			if instr.Val == value {
+				t.store(instr.Addr)
+			}
+
+		case *ssa.FieldAddr:
+			if t.untaintedField(value, instr.Field) {
+				continue
+			}
+			t.trace(instr)
+
+		case *ssa.Field:
+			if t.untaintedField(value, instr.Field) {
+				continue
+			}
+			t.trace(instr)
+
+		case *ssa.UnOp:
+			// Loading a partly tainted struct gives a partly tainted value
+			if fields := t.fields[value]; fields != nil && instr.Op == token.MUL {
+				t.fields[instr] = fields
+			}
+			t.trace(instr)
+
+		case *ssa.BinOp:
+			// Only concatenation keeps the data; comparisons and lengths
+			// do not reveal it
+			if instr.Op == token.ADD && isString(instr.Type()) {
+				t.trace(instr)
+			}
+
+		case ssa.Value:
+			// Conversions, phis, slices, interfaces, type assertions
+			t.trace(instr)
 		}
+	}
+}
+
+// call records call as a sink if it logs, and follows its result if it
+// builds a string from its arguments. Sanitizers stop the taint.
+func (t *taint) call(call *ssa.Call) {
+	callee := call.Call.StaticCallee()
+	if callee == nil || isSanitizer(callee, t.cfg.sanitizers) {
+		return
+	}
 
-		// If it produces a new value, trace that too
-		if instr, ok := ref.(ssa.Value); ok {
-			sinks = append(sinks, traceToSinks(instr, visited)...)
+	if isLoggingOrPrintFunction(callee) || matchesAny(qualifiedName(callee), t.cfg.sinks) {
+		if !t.seen[call] {
+			t.seen[call] = true
+			t.sinks = append(t.sinks, call)
 		}
+		return
+	}
+
+	if callee.Pkg != nil && stringBuilders[callee.Pkg.Pkg.Path()+"."+callee.Name()] {
+		t.trace(call)
+	}
+}
 
-		// Handle phi nodes (merge points in control flow)
-		if phi, ok := ref.(*ssa.Phi); ok {
-			sinks = append(sinks, traceToSinks(phi, visited)...)
+// store taints the memory at addr
+func (t *taint) store(addr ssa.Value) {
+	switch a := addr.(type) {
+	case *ssa.IndexAddr:
+		// An element of an array or slice, such as variadic arguments
+		t.trace(a.X)
+
+	case *ssa.FieldAddr:
+		// Loads of the same field, then uses of the struct as a whole
+		fields := t.fields[a.X]
+		if fields == nil {
+			if t.visited[a.X] {
+				return // the whole struct is already tainted
+			}
+			fields = make(map[int]bool)
+			t.fields[a.X] = fields
+		}
+		fields[a.Field] = true
+
+		if refs := a.X.Referrers(); refs != nil {
+			for _, ref := range *refs {
+				if fa, ok := ref.(*ssa.FieldAddr); ok && fa.Field == a.Field {
+					t.trace(fa)
+				}
+			}
 		}
+		t.trace(a.X)
+
+	default:
+		// A variable; loads from it are tainted
+		t.trace(addr)
+	}
+}
+
+// untaintedField checks if field of the struct value is not tainted because
+// only other fields of it are
+func (t *taint) untaintedField(value ssa.Value, field int) bool {
+	fields := t.fields[value]
+	return fields != nil && !fields[field]
+}
+
+// isSanitizer checks if the name of fn contains a sanitizer pattern
+func isSanitizer(fn *ssa.Function, sanitizers []string) bool {
+	return matchesAny(fn.Name(), sanitizers)
+}
 
-		// Handle field access
-		if field, ok := ref.(*ssa.FieldAddr); ok {
-			sinks = append(sinks, traceToSinks(field, visited)...)
+// matchesAny checks if name contains one of patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(name, pattern) {
+			return true
 		}
+	}
+	return false
+}
 
-		// Handle type assertions
-		if assert, ok := ref.(*ssa.TypeAssert); ok {
-			sinks = append(sinks, traceToSinks(assert, visited)...)
+// qualifiedName returns the package path and name of fn, with the receiver
+// type name for methods: example.com/audit.Logger.Emit
+func qualifiedName(fn *ssa.Function) string {
+	name := fn.Name()
+	if recv := fn.Signature.Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
 		}
+		if named, ok := types.Unalias(t).(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	if fn.Pkg == nil {
+		return name
 	}
+	return fn.Pkg.Pkg.Path() + "." + name
+}
 
-	return sinks
+// isString checks if t is a string type
+func isString(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
 }
 
 // isLoggingOrPrintFunction checks if a function is for logging/printing
@@ -155,18 +371,19 @@ func isLoggingOrPrintFunction(fn *ssa.Function) bool {
 	pkgPath := fn.Pkg.Pkg.Path()
 	fullName := pkgPath + "." + fn.Name()
 
+	// String formatting returns strings instead of printing them
+	if stringBuilders[fullName] {
+		return false
+	}
+
 	// Check common logging packages
 	loggingIndicators := []string{
 		"log", "zap", "logrus", "zerolog",
-		"fmt.Print", "fmt.Fprint", "fmt.Sprint",
+		"fmt.Print", "fmt.Fprint",
 	}
 
 	for _, indicator := range loggingIndicators {
 		if strings.Contains(fullName, indicator) {
-			// Exclude string formatting that returns strings
-			if fn.Name() == "Sprintf" || fn.Name() == "Sprint" {
-				return false
-			}
 			return true
 		}
 	}
@@ -174,8 +391,10 @@ func isLoggingOrPrintFunction(fn *ssa.Function) bool {
 	return false
 }
 
-// checkContextPropagation ensures context is passed through the call chain
-func checkContextPropagation(reporter *nolint.Reporter, fn *ssa.Function) {
+// checkContextPropagation reports functions with a context parameter that
+// call a function needing a context with nil or a fresh context.Background()
+// or context.TODO(), which cuts the callee off from the caller's cancellation
+func checkContextPropagation(reporter *nolint.Reporter, fn *ssa.Function, needs facts.NeedsContextResult) {
 	// Check if function accepts context
 	hasContextParam := false
 	for _, param := range fn.Params {
@@ -192,55 +411,60 @@ func checkContextPropagation(reporter *nolint.Reporter, fn *ssa.Function) {
 	// Check all calls within the function
 	for _, block := range fn.Blocks {
 		for _, instr := range block.Instrs {
-			call, ok := instr.(*ssa.Call)
+			call, ok := instr.(ssa.CallInstruction)
 			if !ok {
 				continue
 			}
 
-			callee := call.Call.StaticCallee()
-			if callee == nil {
+			callee := call.Common().StaticCallee()
+			if callee == nil || !needsContext(callee, needs) {
 				continue
 			}
 
-			// Check if callee expects context
-			if calleeExpectsContext(callee) {
-				// Check if context is passed
-				contextPassed := false
-				for _, arg := range call.Call.Args {
-					if isContextType(arg.Type()) {
-						contextPassed = true
-						break
-					}
-				}
-
-				if !contextPassed {
+			for _, arg := range call.Common().Args {
+				if isContextType(arg.Type()) && isDetachedContext(arg) {
 					reporter.Reportf(call.Pos(),
 						"function %s expects context but none was passed; propagate context through the call chain",
 						callee.Name())
+					break
 				}
 			}
 		}
 	}
 }
 
-// isContextType checks if a type is context.Context
-func isContextType(t types.Type) bool {
-	return strings.Contains(t.String(), "context.Context")
+// needsContext checks if callee passes its context on, from the facts of
+// this package or an imported one
+func needsContext(callee *ssa.Function, needs facts.NeedsContextResult) bool {
+	if callee.Origin() != nil {
+		callee = callee.Origin()
+	}
+	obj, ok := callee.Object().(*types.Func)
+	return ok && needs[obj]
 }
 
-// calleeExpectsContext checks if a function's first parameter is context
-func calleeExpectsContext(fn *ssa.Function) bool {
-	if fn.Signature == nil {
-		return false
+// isDetachedContext checks if v is nil or the result of context.Background()
+// or context.TODO()
+func isDetachedContext(v ssa.Value) bool {
+	if mi, ok := v.(*ssa.MakeInterface); ok {
+		v = mi.X
 	}
-
-	params := fn.Signature.Params()
-	if params.Len() == 0 {
-		return false
+	switch v := v.(type) {
+	case *ssa.Const:
+		return v.IsNil()
+	case *ssa.Call:
+		callee := v.Call.StaticCallee()
+		if callee == nil || callee.Pkg == nil || callee.Pkg.Pkg.Path() != "context" {
+			return false
+		}
+		return callee.Name() == "Background" || callee.Name() == "TODO"
 	}
+	return false
+}
 
-	firstParam := params.At(0)
-	return isContextType(firstParam.Type())
+// isContextType checks if a type is context.Context
+func isContextType(t types.Type) bool {
+	return strings.Contains(t.String(), "context.Context")
 }
 
 // checkErrorFlow ensures errors are handled properly, not discarded
@@ -268,8 +492,9 @@ func checkErrorFlow(fn *ssa.Function) {
 
 // TaintAnalysis performs taint tracking from sources to sinks
 type TaintAnalysis struct {
-	Sources map[ssa.Value]string // value -> source description
-	Sinks   []TaintSink
+	Sources    map[ssa.Value]string // value -> source description
+	Sinks      []TaintSink
+	Sanitizers []string // function name patterns that clear taint
 }
 
 // TaintSink represents a location where tainted data reached
@@ -282,7 +507,8 @@ type TaintSink struct {
 // NewTaintAnalysis creates a new taint analysis tracker
 func NewTaintAnalysis() *TaintAnalysis {
 	return &TaintAnalysis{
-		Sources: make(map[ssa.Value]string),
+		Sources:    make(map[ssa.Value]string),
+		Sanitizers: configured().sanitizers,
 	}
 }
 
@@ -305,6 +531,13 @@ func (t *TaintAnalysis) Propagate() {
 			}
 
 			for _, ref := range *refs {
+				// Sanitized values are clean, and sanitizers are not sinks
+				if call, ok := ref.(*ssa.Call); ok {
+					if callee := call.Call.StaticCallee(); callee != nil && isSanitizer(callee, t.Sanitizers) {
+						continue
+					}
+				}
+
 				// If this instruction produces a new value, it's also tainted
 				if newVal, ok := ref.(ssa.Value); ok {
 					if _, exists := t.Sources[newVal]; !exists {
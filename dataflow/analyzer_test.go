@@ -0,0 +1,44 @@
+package dataflow_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/dataflow"
+)
+
+func TestDataflowSensitiveLeaks(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, dataflow.Analyzer, "leaks")
+}
+
+func TestDataflowCustomSinksUnconfigured(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, dataflow.Analyzer, "sinks")
+}
+
+func TestDataflowConfigured(t *testing.T) {
+	for name, value := range map[string]string{
+		"sinks":      "example.com/audit.",
+		"sensitive":  "passphrase",
+		"sanitizers": "Scrub",
+	} {
+		if err := dataflow.Analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		for _, name := range []string{"sinks", "sensitive", "sanitizers"} {
+			_ = dataflow.Analyzer.Flags.Set(name, "")
+		}
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, dataflow.Analyzer, "configured")
+}
+
+func TestDataflowContextAcrossPackages(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, dataflow.Analyzer, "example.com/handler")
+}
@@ -0,0 +1,40 @@
+package configured
+
+import (
+	"log"
+
+	"example.com/audit"
+	"example.com/logutil"
+)
+
+// Emitted reaches the configured audit.Emit sink
+func Emitted(user, token string) {
+	audit.Emit("login", user, token) // want `sensitive parameter "token" reaches Emit; sanitize or redact it first`
+}
+
+// Recorded reaches a configured method sink
+func Recorded(l *audit.Logger, token string) {
+	l.Record("login", token) // want `sensitive parameter "token" reaches Record`
+}
+
+// Passphrase matches a configured sensitive name
+func Passphrase(passphrase string) {
+	log.Println(passphrase) // want `sensitive parameter "passphrase" may be logged`
+}
+
+// Redacted passes the token through a default sanitizer in the audit
+// package, whose functions are all sinks
+func Redacted(user, token string) {
+	audit.Emit("login", user, audit.Redact(token))
+}
+
+// Masked uses a sanitizer from a logging package
+func Masked(token string) {
+	log.Println(logutil.MaskToken(token))
+}
+
+// Scrubbed uses a configured sanitizer from a logging package
+func Scrubbed(token string) {
+	line := "token=" + logutil.Scrub(token)
+	log.Println(line)
+}
@@ -0,0 +1,9 @@
+package audit
+
+func Emit(event string, fields ...interface{}) {}
+
+func Redact(s string) string { return "***" }
+
+type Logger struct{}
+
+func (l *Logger) Record(event string, fields ...interface{}) {}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+
+	"example.com/store"
+)
+
+func Show(ctx context.Context, s *store.Store, id string) (string, error) {
+	return s.Get(context.Background(), id) // want `function Get expects context but none was passed; propagate context through the call chain`
+}
+
+func ShowTODO(ctx context.Context, s *store.Store, id string) (string, error) {
+	return s.Get(context.TODO(), id) // want `function Get expects context but none was passed`
+}
+
+func ShowNil(ctx context.Context, s *store.Store, id string) (string, error) {
+	return s.Get(nil, id) // want `function Get expects context but none was passed`
+}
+
+func ShowLocal(ctx context.Context, s *store.Store, id string) (string, error) {
+	return fetch(context.Background(), s, id) // want `function fetch expects context but none was passed`
+}
+
+func fetch(ctx context.Context, s *store.Store, id string) (string, error) {
+	return s.Get(ctx, id)
+}
+
+// Propagated passes its own context on
+func Propagated(ctx context.Context, s *store.Store, id string) (string, error) {
+	return s.Get(ctx, id)
+}
+
+// Count calls a function that ignores its context
+func Count(ctx context.Context, s *store.Store) int {
+	return s.Len(context.Background())
+}
+
+// Background has no context of its own to pass
+func Background(s *store.Store, id string) (string, error) {
+	return s.Get(context.Background(), id)
+}
@@ -0,0 +1,5 @@
+package logutil
+
+func MaskToken(s string) string { return s[:4] + "..." }
+
+func Scrub(s string) string { return "" }
@@ -0,0 +1,24 @@
+package store
+
+import "context"
+
+type Store struct {
+	rows map[string]string
+}
+
+// Get passes ctx on, so the context it is given decides its cancellation
+func (s *Store) Get(ctx context.Context, id string) (string, error) {
+	return s.lookup(ctx, id)
+}
+
+func (s *Store) lookup(ctx context.Context, id string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return s.rows[id], nil
+}
+
+// Len ignores its context, so any context will do
+func (s *Store) Len(ctx context.Context) int {
+	return len(s.rows)
+}
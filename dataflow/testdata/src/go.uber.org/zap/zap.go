@@ -0,0 +1,14 @@
+package zap
+
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Field { return Field{Key: key, Value: value} }
+
+func Any(key string, value interface{}) Field { return Field{Key: key, Value: value} }
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string, fields ...Field) {}
@@ -0,0 +1,86 @@
+package leaks
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type Credentials struct {
+	User     string
+	Password string
+}
+
+type Service struct {
+	logger *zap.Logger
+}
+
+// Direct passes the parameter itself to the logger
+func (s *Service) Direct(user, password string) {
+	s.logger.Info("login", zap.String("password", password)) // want `sensitive parameter "password" may be logged`
+}
+
+// Sprintf builds the message first
+func (s *Service) Sprintf(user, password string) {
+	msg := fmt.Sprintf("user=%s pass=%s", user, password)
+	s.logger.Info(msg) // want `sensitive parameter "password" may be logged`
+}
+
+// Concat joins strings with +
+func Concat(user, token string) {
+	line := "user=" + user + " token=" + token
+	log.Println(line) // want `sensitive parameter "token" may be logged`
+}
+
+// Join builds the message with strings.Join
+func Join(user, secret string) {
+	log.Print(strings.Join([]string{user, secret}, ":")) // want `sensitive parameter "secret" may be logged`
+}
+
+// Appendf formats into a buffer
+func Appendf(apiKey string) {
+	buf := fmt.Appendf(nil, "key=%s", apiKey)
+	log.Printf("%s", buf) // want `sensitive parameter "apiKey" may be logged`
+}
+
+// FieldPointer stores the parameter in a struct later logged whole
+func (s *Service) FieldPointer(user, password string) {
+	creds := &Credentials{User: user}
+	creds.Password = password
+	s.logger.Info("login", zap.Any("creds", creds)) // want `sensitive parameter "password" may be logged`
+}
+
+// FieldValue stores the parameter in a struct value and logs the field
+func FieldValue(user, password string) {
+	var creds Credentials
+	creds.User = user
+	creds.Password = password
+	log.Println(creds.Password) // want `sensitive parameter "password" may be logged`
+}
+
+// OtherField logs only a field that holds no sensitive data
+func (s *Service) OtherField(user, password string) {
+	creds := &Credentials{User: user, Password: password}
+	s.logger.Info("login", zap.String("user", creds.User))
+}
+
+// Compared logs only whether the parameter is set
+func Compared(password string) {
+	log.Println(len(password) > 0, password == "")
+}
+
+// Hashed logs a value computed by a function that is not a string builder
+func Hashed(password string) {
+	log.Println(hash(password))
+}
+
+func hash(s string) int {
+	return len(s) * 31
+}
+
+// Formatted returns the string instead of logging it
+func Formatted(password string) string {
+	return fmt.Sprintf("pass=%s", password)
+}
@@ -0,0 +1,13 @@
+package sinks
+
+import "example.com/audit"
+
+// Emitted reaches audit.Emit, which is only a sink when configured
+func Emitted(user, token string) {
+	audit.Emit("login", user, token)
+}
+
+// Passphrase is only sensitive when configured
+func Passphrase(passphrase string) {
+	audit.Emit("unlock", passphrase)
+}
@@ -0,0 +1,371 @@
+// Package depinject provides an analyzer that checks constructors wire every
+// dependency their type uses.
+//
+// With manual dependency injection, adding a field to a struct without adding
+// it to the New* constructor compiles fine and leaves a nil dependency that
+// panics on first use, usually far from main where the wiring happens.
+package depinject
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check New* constructors set every dependency their type's methods use
+
+For structs with a New* constructor in the same package, this analyzer
+reports unexported fields of interface, pointer, or func type that:
+1. The constructor never assigns, neither from a parameter nor as a default
+2. Are called or dereferenced in the type's methods without a nil check
+
+Fields set by a setter, by functional options, or by another method the
+constructor calls are considered wired.
+
+Bad:
+    type Service struct {
+        store Store
+        cache Cache // added later, forgotten in NewService
+    }
+
+    func NewService(store Store) *Service {
+        return &Service{store: store}
+    }
+
+    func (s *Service) Get(k string) string {
+        return s.cache.Get(k) // nil pointer dereference
+    }
+
+Good:
+    func NewService(store Store, cache Cache) *Service {
+        return &Service{store: store, cache: cache}
+    }`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "depinject",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// structInfo collects what the package does with one struct type
+type structInfo struct {
+	named        *types.Named
+	fields       map[*types.Var]bool // dependency fields
+	constructors []*ast.FuncDecl
+	methods      map[string]*ast.FuncDecl
+	used         map[*types.Var]bool // called or dereferenced in a method
+	guarded      map[*types.Var]bool // compared against nil in a method
+	setOutside   map[*types.Var]bool // assigned outside the constructors
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	structs := make(map[*types.Named]*structInfo)
+
+	// Collect structs, constructors, and methods
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil {
+			return
+		}
+
+		if fn.Recv != nil {
+			if info := infoFor(pass, structs, pass.TypesInfo.TypeOf(fn.Recv.List[0].Type)); info != nil {
+				info.methods[fn.Name.Name] = fn
+			}
+			return
+		}
+
+		if !strings.HasPrefix(fn.Name.Name, "New") || fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+			return
+		}
+		if info := infoFor(pass, structs, pass.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)); info != nil {
+			info.constructors = append(info.constructors, fn)
+		}
+	})
+
+	// Record field uses, nil checks, and assignments outside constructors
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil {
+			return
+		}
+		// Tests wiring fakes into a literal do not make the constructor complete
+		inTest := strings.HasSuffix(pass.Fset.File(fn.Pos()).Name(), "_test.go")
+		for _, info := range structs {
+			if isConstructor(info, fn) {
+				continue
+			}
+			if !inTest {
+				recordAssignments(pass, info, fn.Body, info.setOutside)
+			}
+			if fn.Recv != nil && info.methods[fn.Name.Name] == fn {
+				recordUses(pass, info, fn.Body)
+			}
+		}
+	})
+
+	for _, info := range sortedStructs(structs) {
+		for _, ctor := range info.constructors {
+			checkConstructor(reporter, pass, info, ctor)
+		}
+	}
+
+	return nil, nil
+}
+
+// infoFor returns the structInfo for T or *T when T is a non-generic struct
+// declared in this package
+func infoFor(pass *analysis.Pass, structs map[*types.Named]*structInfo, t types.Type) *structInfo {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != pass.Pkg || named.TypeParams().Len() > 0 {
+		return nil
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	if info := structs[named]; info != nil {
+		return info
+	}
+
+	info := &structInfo{
+		named:      named,
+		fields:     make(map[*types.Var]bool),
+		methods:    make(map[string]*ast.FuncDecl),
+		used:       make(map[*types.Var]bool),
+		guarded:    make(map[*types.Var]bool),
+		setOutside: make(map[*types.Var]bool),
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if f := st.Field(i); isDependency(f) {
+			info.fields[f] = true
+		}
+	}
+	structs[named] = info
+	return info
+}
+
+// matches checks if t is the struct type or a pointer to it
+func (info *structInfo) matches(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named == info.named
+}
+
+// isDependency checks for unexported, non-embedded fields that panic when
+// used while nil. Exported fields can be set by callers after construction.
+func isDependency(f *types.Var) bool {
+	if f.Embedded() || f.Exported() {
+		return false
+	}
+	switch f.Type().Underlying().(type) {
+	case *types.Interface, *types.Pointer, *types.Signature:
+		return true
+	}
+	return false
+}
+
+// isConstructor checks if fn is one of the constructors of info's type
+func isConstructor(info *structInfo, fn *ast.FuncDecl) bool {
+	for _, ctor := range info.constructors {
+		if ctor == fn {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldOf returns the dependency field selected by expr, if any
+func fieldOf(pass *analysis.Pass, info *structInfo, expr ast.Expr) *types.Var {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return nil
+	}
+	f, ok := selection.Obj().(*types.Var)
+	if !ok || !info.fields[f] {
+		return nil
+	}
+	return f
+}
+
+// recordAssignments adds the fields assigned in body, either through a
+// selector or a keyed composite literal, to set
+func recordAssignments(pass *analysis.Pass, info *structInfo, body ast.Node, set map[*types.Var]bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				// Resetting a dependency to nil does not wire it
+				if len(node.Lhs) == len(node.Rhs) && isNil(pass, node.Rhs[i]) {
+					continue
+				}
+				if f := fieldOf(pass, info, lhs); f != nil {
+					set[f] = true
+				}
+			}
+		case *ast.CompositeLit:
+			if !info.matches(pass.TypesInfo.TypeOf(node)) {
+				return true
+			}
+			for _, elt := range node.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					// Unkeyed literals set every field
+					for f := range info.fields {
+						set[f] = true
+					}
+					break
+				}
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					if f, ok := pass.TypesInfo.Uses[key].(*types.Var); ok && info.fields[f] {
+						set[f] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// recordUses marks fields that are called or dereferenced in a method body,
+// and fields that are compared against nil
+func recordUses(pass *analysis.Pass, info *structInfo, body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			// s.dep.Method() or s.ptr.Field
+			if f := fieldOf(pass, info, node.X); f != nil {
+				info.used[f] = true
+			}
+		case *ast.CallExpr:
+			// s.fn()
+			if f := fieldOf(pass, info, node.Fun); f != nil {
+				info.used[f] = true
+			}
+		case *ast.StarExpr:
+			if f := fieldOf(pass, info, node.X); f != nil {
+				info.used[f] = true
+			}
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			for _, pair := range [][2]ast.Expr{{node.X, node.Y}, {node.Y, node.X}} {
+				if f := fieldOf(pass, info, pair[0]); f != nil && isNil(pass, pair[1]) {
+					info.guarded[f] = true
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isNil checks if expr is the predeclared nil
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = pass.TypesInfo.Uses[ident].(*types.Nil)
+	return ok
+}
+
+// checkConstructor reports dependency fields the constructor leaves nil
+func checkConstructor(reporter *nolint.Reporter, pass *analysis.Pass, info *structInfo, ctor *ast.FuncDecl) {
+	set := make(map[*types.Var]bool)
+	recordAssignments(pass, info, ctor.Body, set)
+
+	if handsOff(pass, info, ctor, set) {
+		return
+	}
+
+	for _, f := range sortedFields(info) {
+		if set[f] || info.setOutside[f] || !info.used[f] || info.guarded[f] {
+			continue
+		}
+		reporter.Reportf(f.Pos(),
+			"field %s is used in methods but never set by %s; callers will hit a nil dependency",
+			f.Name(), ctor.Name.Name)
+	}
+}
+
+// handsOff checks if the constructor passes the value it builds to another
+// function, such as functional options, that may set any field. Methods
+// called on the value are followed one level and their assignments added to set.
+func handsOff(pass *analysis.Pass, info *structInfo, ctor *ast.FuncDecl, set map[*types.Var]bool) bool {
+	escapes := false
+
+	ast.Inspect(ctor.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !escapes
+		}
+
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			selection, ok := pass.TypesInfo.Selections[sel]
+			if ok && selection.Kind() == types.MethodVal && info.matches(selection.Recv()) {
+				if method := info.methods[sel.Sel.Name]; method != nil {
+					recordAssignments(pass, info, method.Body, set)
+				}
+			}
+		}
+
+		for _, arg := range call.Args {
+			if _, ok := ast.Unparen(arg).(*ast.CompositeLit); ok {
+				continue
+			}
+			if info.matches(pass.TypesInfo.TypeOf(arg)) {
+				escapes = true
+			}
+		}
+		return !escapes
+	})
+
+	return escapes
+}
+
+// sortedStructs returns the structs in declaration order
+func sortedStructs(structs map[*types.Named]*structInfo) []*structInfo {
+	infos := make([]*structInfo, 0, len(structs))
+	for _, info := range structs {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].named.Obj().Pos() < infos[j].named.Obj().Pos()
+	})
+	return infos
+}
+
+// sortedFields returns the dependency fields in declaration order
+func sortedFields(info *structInfo) []*types.Var {
+	fields := make([]*types.Var, 0, len(info.fields))
+	for f := range info.fields {
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Pos() < fields[j].Pos()
+	})
+	return fields
+}
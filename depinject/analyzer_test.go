@@ -0,0 +1,14 @@
+package depinject_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/depinject"
+)
+
+func TestDepInjectAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, depinject.Analyzer, "a")
+}
@@ -0,0 +1,137 @@
+package a
+
+type Store interface {
+	Get(key string) string
+}
+
+type Logger interface {
+	Info(msg string)
+}
+
+type Client struct {
+	Endpoint string
+}
+
+func (c *Client) Fetch(key string) string { return c.Endpoint + key }
+
+// Bad: cache was added to the struct but not to NewService
+type Service struct {
+	store  Store
+	cache  Store // want `field cache is used in methods but never set by NewService; callers will hit a nil dependency`
+	logger Logger
+	client *Client
+	hook   func(string) // want `field hook is used in methods but never set by NewService; callers will hit a nil dependency`
+	name   string
+}
+
+func NewService(store Store, name string) *Service {
+	s := &Service{store: store, name: name}
+	s.client = &Client{Endpoint: "http://localhost"} // Good: in-constructor default
+	return s
+}
+
+func (s *Service) Get(key string) string {
+	// Good: optional field guarded by a nil check
+	if s.logger != nil {
+		s.logger.Info("get " + key)
+	}
+	if v := s.cache.Get(key); v != "" {
+		return v
+	}
+	s.hook(key)
+	return s.store.Get(key) + s.client.Fetch(key)
+}
+
+func (s *Service) Close() {
+	s.cache = nil
+}
+
+// Good: setter injection
+type Worker struct {
+	logger Logger
+}
+
+func NewWorker() *Worker {
+	return &Worker{}
+}
+
+func (w *Worker) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *Worker) Run() {
+	w.logger.Info("run")
+}
+
+// Good: functional options may set any field
+type Server struct {
+	store Store
+}
+
+type Option func(*Server)
+
+func WithStore(st Store) Option {
+	return func(s *Server) { s.store = st }
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+func (s *Server) Lookup(key string) string {
+	return s.store.Get(key)
+}
+
+// Good: defaults applied by a method the constructor calls
+type Cache struct {
+	backend Store
+}
+
+func NewCache() *Cache {
+	c := &Cache{}
+	c.init()
+	return c
+}
+
+func (c *Cache) init() {
+	c.backend = memStore{}
+}
+
+func (c *Cache) Get(key string) string {
+	return c.backend.Get(key)
+}
+
+// Good: exported fields can be set by callers
+type Handler struct {
+	Store Store
+}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+func (h *Handler) Serve(key string) string {
+	return h.Store.Get(key)
+}
+
+// Good: unkeyed literal sets every field
+type Pair struct {
+	left  Store
+	right Store
+}
+
+func NewPair(l, r Store) Pair {
+	return Pair{l, r}
+}
+
+func (p Pair) Get(key string) string {
+	return p.left.Get(key) + p.right.Get(key)
+}
+
+type memStore struct{}
+
+func (memStore) Get(string) string { return "" }
@@ -174,6 +174,10 @@ export default defineUserConfig({
 								{ text: "humaneerror", link: "humaneerror" },
 								{ text: "errorwrap", link: "errorwrap" },
 								{ text: "sentinelerrors", link: "sentinelerrors" },
+								{ text: "wrapboundary", link: "wrapboundary" },
+								{ text: "multierror", link: "multierror" },
+								{ text: "errmsgstyle", link: "errmsgstyle" },
+								{ text: "errorsas", link: "errorsas" },
 							],
 						},
 						{
@@ -184,6 +188,9 @@ export default defineUserConfig({
 								{ text: "wideevents", link: "wideevents" },
 								{ text: "contextlogger", link: "contextlogger" },
 								{ text: "contextpropagation", link: "contextpropagation" },
+								{ text: "spanname", link: "spanname" },
+								{ text: "loggershutdown", link: "loggershutdown" },
+								{ text: "tracecardinality", link: "tracecardinality" },
 							],
 						},
 						{
@@ -194,6 +201,8 @@ export default defineUserConfig({
 								{ text: "reconciler", link: "reconciler" },
 								{ text: "statusupdate", link: "statusupdate" },
 								{ text: "sideeffects", link: "sideeffects" },
+								{ text: "apiversionskew", link: "apiversionskew" },
+								{ text: "requeueresult", link: "requeueresult" },
 							],
 						},
 						{
@@ -205,6 +214,8 @@ export default defineUserConfig({
 								{ text: "interfaceconsistency", link: "interfaceconsistency" },
 								{ text: "mockverify", link: "mockverify" },
 								{ text: "optionspattern", link: "optionspattern" },
+								{ text: "testglobals", link: "testglobals" },
+								{ text: "testhelper", link: "testhelper" },
 							],
 						},
 						{
@@ -214,6 +225,14 @@ export default defineUserConfig({
 							items: [
 								{ text: "resourceclose", link: "resourceclose" },
 								{ text: "httpclient", link: "httpclient" },
+								{ text: "rowscan", link: "rowscan" },
+								{ text: "readadoption", link: "readadoption" },
+								{ text: "gracedrain", link: "gracedrain" },
+								{ text: "clientretryafter", link: "clientretryafter" },
+								{ text: "buffereduse", link: "buffereduse" },
+								{ text: "grpcinterceptors", link: "grpcinterceptors" },
+								{ text: "fieldpadding", link: "fieldpadding" },
+								{ text: "pollinterval", link: "pollinterval" },
 							],
 						},
 						{
@@ -226,6 +245,15 @@ export default defineUserConfig({
 								{ text: "nopanic", link: "nopanic" },
 								{ text: "nestingdepth", link: "nestingdepth" },
 								{ text: "syncaccess", link: "syncaccess" },
+								{ text: "chancap", link: "chancap" },
+								{ text: "timectx", link: "timectx" },
+								{ text: "atomicvalue", link: "atomicvalue" },
+								{ text: "probeorder", link: "probeorder" },
+								{ text: "ctxsignal", link: "ctxsignal" },
+								{ text: "mapiteration", link: "mapiteration" },
+								{ text: "exhauststruct", link: "exhauststruct" },
+								{ text: "contextkeys", link: "contextkeys" },
+								{ text: "slicealias", link: "slicealias" },
 							],
 						},
 						{
@@ -236,6 +264,9 @@ export default defineUserConfig({
 								{ text: "closurecomplexity", link: "closurecomplexity" },
 								{ text: "emptyinterface", link: "emptyinterface" },
 								{ text: "returninterface", link: "returninterface" },
+								{ text: "localelower", link: "localelower" },
+								{ text: "enumjson", link: "enumjson" },
+								{ text: "structtags", link: "structtags" },
 							],
 						},
 						{
@@ -251,6 +282,10 @@ export default defineUserConfig({
 								{ text: "hardcodedcreds", link: "hardcodedcreds" },
 								{ text: "lifecycle", link: "lifecycle" },
 								{ text: "dataflow", link: "dataflow" },
+								{ text: "depinject", link: "depinject" },
+								{ text: "orphanconst", link: "orphanconst" },
+								{ text: "doccodefence", link: "doccodefence" },
+								{ text: "shutdownorder", link: "shutdownorder" },
 							],
 						},
 					],
@@ -0,0 +1,476 @@
+// Package doccodefence provides an analyzer that checks code examples in doc
+// comments against the identifiers that actually exist.
+//
+// Examples in doc comments are never compiled, so they rot quietly when the
+// code they describe is renamed. This analyzer parses indented code blocks
+// leniently and resolves the names they reference against the package and
+// its imports. It does not type check the examples.
+package doccodefence
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+	"github.com/spechtlabs/golint-sl/internal/suggest"
+)
+
+const Doc = `check that code examples in doc comments reference existing identifiers
+
+Indented code blocks in package docs and in doc comments of exported
+symbols are parsed as declarations or statements. The analyzer reports:
+1. Exported names used in an example that the package does not declare,
+   when the package declares a similar name they were likely renamed from
+2. pkg.Name references to the package itself or to a package it imports
+   where Name does not exist
+3. T.Name references to a type or variable of the package where T has no
+   such field or method
+
+Names the example declares itself, single letters, and common placeholders
+like Foo or MyType are skipped. Blocks that do not parse, for example
+because they contain pseudo-code, are skipped as a whole.
+
+Bad:
+    // Process handles a request:
+    //
+    //	c := NewClient()
+    //	c.Do(ProcesRequest(req)) // renamed to ProcessRequest
+
+Good:
+    // Process handles a request:
+    //
+    //	c := NewClient()
+    //	c.Do(ProcessRequest(req))`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "doccodefence",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// placeholders are names examples use for "your type here"
+var placeholders = map[string]bool{
+	"Foo":           true,
+	"Bar":           true,
+	"Baz":           true,
+	"Qux":           true,
+	"MyType":        true,
+	"MyStruct":      true,
+	"MyInterface":   true,
+	"MyService":     true,
+	"YourType":      true,
+	"SomeType":      true,
+	"Something":     true,
+	"TypeName":      true,
+	"StructName":    true,
+	"InterfaceName": true,
+	"FuncName":      true,
+	"FunctionName":  true,
+	"MethodName":    true,
+}
+
+// ellipsis matches "..." used as pseudo-code, as opposed to variadic
+// arguments like args...
+var ellipsis = regexp.MustCompile(`(^|[^\w\)\]])\.\.\.`)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	c := &checker{pass: pass, reporter: reporter, imports: make(map[string]*types.Package)}
+	for _, imp := range pass.Pkg.Imports() {
+		c.imports[imp.Name()] = imp
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.File)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.GenDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		if strings.HasSuffix(pass.Fset.Position(n.Pos()).Filename, "_test.go") {
+			return
+		}
+
+		switch node := n.(type) {
+		case *ast.File:
+			c.checkDoc(node.Doc)
+		case *ast.FuncDecl:
+			if ast.IsExported(node.Name.Name) {
+				c.checkDoc(node.Doc)
+			}
+		case *ast.GenDecl:
+			if exportsAny(node) {
+				c.checkDoc(node.Doc)
+			}
+			for _, spec := range node.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if ast.IsExported(s.Name.Name) {
+						c.checkDoc(s.Doc)
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if ast.IsExported(name.Name) {
+							c.checkDoc(s.Doc)
+							break
+						}
+					}
+				}
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// exportsAny checks if a declaration declares an exported name
+func exportsAny(decl *ast.GenDecl) bool {
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			if ast.IsExported(s.Name.Name) {
+				return true
+			}
+		case *ast.ValueSpec:
+			for _, name := range s.Names {
+				if ast.IsExported(name.Name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+type checker struct {
+	pass     *analysis.Pass
+	reporter *nolint.Reporter
+	imports  map[string]*types.Package // package name -> imported package
+}
+
+// checkDoc checks every code block of a doc comment
+func (c *checker) checkDoc(doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+
+	var p comment.Parser
+	reported := make(map[string]bool)
+	declared := make(map[string]bool) // names declared in this or an earlier block
+	for _, block := range p.Parse(doc.Text()).Content {
+		code, ok := block.(*comment.Code)
+		if !ok {
+			continue
+		}
+		file := parseExample(code.Text)
+		if file == nil {
+			continue
+		}
+		for name := range declaredNames(file) {
+			declared[name] = true
+		}
+		for _, ref := range c.unresolved(file, declared) {
+			if reported[ref.name] {
+				continue
+			}
+			reported[ref.name] = true
+
+			msg := fmt.Sprintf("doc example references %s, which does not exist", ref.name)
+			if ref.suggestion != "" {
+				msg = fmt.Sprintf("doc example references %s; did you mean %s?", ref.name, ref.suggestion)
+			}
+			c.reporter.Reportf(position(doc, ref.name), "%s", msg)
+		}
+	}
+}
+
+// parseExample parses a code block as declarations, or failing that, as
+// statements in a function body. It returns nil if neither works.
+func parseExample(code string) *ast.File {
+	code = ellipsis.ReplaceAllString(code, "$1")
+
+	fset := token.NewFileSet()
+	if file, err := parser.ParseFile(fset, "", "package p\n"+code, 0); err == nil {
+		return file
+	}
+	if file, err := parser.ParseFile(fset, "", "package p\nfunc _() {\n"+code+"\n}", 0); err == nil {
+		return file
+	}
+	return nil
+}
+
+// position returns the position of the first mention of name in doc
+func position(doc *ast.CommentGroup, name string) token.Pos {
+	for _, cm := range doc.List {
+		if i := strings.Index(cm.Text, name); i >= 0 {
+			return cm.Pos() + token.Pos(i)
+		}
+	}
+	return doc.Pos()
+}
+
+// ref is a name an example uses that does not resolve
+type ref struct {
+	name       string
+	suggestion string
+}
+
+// unresolved returns the references in an example that do not resolve.
+// Names in declared, like variables of earlier blocks of the same doc
+// comment, are the example's own.
+func (c *checker) unresolved(file *ast.File, declared map[string]bool) []ref {
+	scope := c.pass.Pkg.Scope()
+
+	var refs []ref
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Field:
+			// Field, parameter, and method names declare, they don't reference
+			if node.Type != nil {
+				ast.Inspect(node.Type, visit)
+			}
+			return false
+
+		case *ast.KeyValueExpr:
+			// Struct literal keys name fields
+			if _, ok := node.Key.(*ast.Ident); !ok {
+				ast.Inspect(node.Key, visit)
+			}
+			ast.Inspect(node.Value, visit)
+			return false
+
+		case *ast.SelectorExpr:
+			x, ok := node.X.(*ast.Ident)
+			if !ok {
+				ast.Inspect(node.X, visit)
+				return false
+			}
+			if !declared[x.Name] {
+				if r, ok := c.checkSelector(x.Name, node.Sel.Name); ok {
+					refs = append(refs, r)
+				}
+			}
+			return false
+
+		case *ast.Ident:
+			name := node.Name
+			if !ast.IsExported(name) || len(name) == 1 || placeholders[name] || declared[name] {
+				return false
+			}
+			if scope.Lookup(name) != nil || types.Universe.Lookup(name) != nil {
+				return false
+			}
+			// Unknown names without a close match are usually illustrative,
+			// like Arg in func Do(ctx context.Context, arg Arg)
+			if suggestion := closest(name, scope.Names()); suggestion != "" {
+				refs = append(refs, ref{name: name, suggestion: suggestion})
+			}
+		}
+		return true
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil {
+				ast.Inspect(d.Recv, visit)
+			}
+			ast.Inspect(d.Type, visit)
+			if d.Body != nil {
+				ast.Inspect(d.Body, visit)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					ast.Inspect(s.Type, visit)
+				case *ast.ValueSpec:
+					if s.Type != nil {
+						ast.Inspect(s.Type, visit)
+					}
+					for _, v := range s.Values {
+						ast.Inspect(v, visit)
+					}
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// checkSelector resolves x.sel, where x is the package itself, an imported
+// package, or a type or variable of the package. References it cannot
+// resolve x for, like local variables, are not reported.
+func (c *checker) checkSelector(x, sel string) (ref, bool) {
+	if !ast.IsExported(sel) {
+		return ref{}, false
+	}
+	name := x + "." + sel
+
+	var pkg *types.Package
+	switch {
+	case x == c.pass.Pkg.Name():
+		pkg = c.pass.Pkg
+	case c.imports[x] != nil:
+		pkg = c.imports[x]
+	}
+	if pkg != nil {
+		if pkg.Scope().Lookup(sel) != nil {
+			return ref{}, false
+		}
+		return ref{name: name, suggestion: qualify(x, closest(sel, exportedNames(pkg.Scope())))}, true
+	}
+
+	obj := c.pass.Pkg.Scope().Lookup(x)
+	if obj == nil || placeholders[x] {
+		return ref{}, false
+	}
+	switch obj.(type) {
+	case *types.TypeName:
+		// An example can't use unexported types; file.Read in the docs of
+		// package os uses a variable called file, not the type os.file
+		if !obj.Exported() {
+			return ref{}, false
+		}
+	case *types.Var, *types.Const:
+	default:
+		return ref{}, false
+	}
+
+	t := obj.Type()
+	if found, _, _ := types.LookupFieldOrMethod(t, true, c.pass.Pkg, sel); found != nil {
+		return ref{}, false
+	}
+	if _, ok := t.Underlying().(*types.Interface); !ok {
+		if found, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, c.pass.Pkg, sel); found != nil {
+			return ref{}, false
+		}
+	}
+	return ref{name: name, suggestion: closest(sel, memberNames(t))}, true
+}
+
+// qualify prefixes a suggested name with its package
+func qualify(pkg, name string) string {
+	if name == "" {
+		return ""
+	}
+	return pkg + "." + name
+}
+
+// exportedNames returns the exported names of a package scope
+func exportedNames(scope *types.Scope) []string {
+	var names []string
+	for _, name := range scope.Names() {
+		if ast.IsExported(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// memberNames returns the fields and methods of t and *t, or of the type
+// t points to
+func memberNames(t types.Type) []string {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	var names []string
+	mset := types.NewMethodSet(types.NewPointer(t))
+	if types.IsInterface(t) {
+		mset = types.NewMethodSet(t)
+	}
+	for i := 0; i < mset.Len(); i++ {
+		names = append(names, mset.At(i).Obj().Name())
+	}
+	if st, ok := t.Underlying().(*types.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			names = append(names, st.Field(i).Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// declaredNames returns the names an example declares itself
+func declaredNames(file *ast.File) map[string]bool {
+	declared := make(map[string]bool)
+	add := func(idents ...*ast.Ident) {
+		for _, ident := range idents {
+			declared[ident.Name] = true
+		}
+	}
+	addFields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, f := range fields.List {
+			add(f.Names...)
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Name.Name != "_" {
+				add(node.Name)
+			}
+			addFields(node.Recv)
+		case *ast.FuncType:
+			addFields(node.TypeParams)
+			addFields(node.Params)
+			addFields(node.Results)
+		case *ast.TypeSpec:
+			add(node.Name)
+			addFields(node.TypeParams)
+		case *ast.ValueSpec:
+			add(node.Names...)
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						add(ident)
+					}
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				for _, e := range []ast.Expr{node.Key, node.Value} {
+					if ident, ok := e.(*ast.Ident); ok {
+						add(ident)
+					}
+				}
+			}
+		case *ast.LabeledStmt:
+			add(node.Label)
+		}
+		return true
+	})
+	return declared
+}
+
+// closest returns the candidate nearest to name by edit distance, if one is
+// close enough to be a likely rename or typo
+func closest(name string, candidates []string) string {
+	var exported []string
+	for _, cand := range candidates {
+		if cand != name && ast.IsExported(cand) {
+			exported = append(exported, cand)
+		}
+	}
+	return suggest.Closest(name, exported, max(len(name)/3+1, 3))
+}
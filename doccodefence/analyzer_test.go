@@ -0,0 +1,14 @@
+package doccodefence_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/doccodefence"
+)
+
+func TestDocCodeFenceAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, doccodefence.Analyzer, "a")
+}
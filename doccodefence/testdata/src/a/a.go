@@ -0,0 +1,118 @@
+// Package a shows how doc examples are checked.
+//
+// Create a client and process a request:
+//
+//	c := NewClient(store.Opne("dsn")) // want `doc example references store.Opne; did you mean store.Open\?`
+//	resp, err := c.Do(ProcesRequest(req)) // want `doc example references ProcesRequest; did you mean ProcessRequest\?`
+package a
+
+import "a/store"
+
+// Client sends requests.
+type Client struct {
+	Timeout int
+	db      *store.DB
+}
+
+// NewClient creates a Client.
+//
+//	c := NewClient(db)
+//	c.Timeout = 5
+//	resp, err := c.Do(ProcessRequest(req))
+func NewClient(db *store.DB) *Client { return &Client{db: db} }
+
+// Do sends a request.
+func (c *Client) Do(r *Request) (*Response, error) { return &Response{}, nil }
+
+// Request is a request.
+type Request struct{ ID string }
+
+// Response is a response.
+type Response struct{}
+
+// ProcessRequest prepares a request, for example with a default client:
+//
+//	resp, err := DefaultClient.Dispatch(ProcessRequest(req)) // want `doc example references DefaultClient.Dispatch, which does not exist`
+//	resp, err = DefaultClient.Doo(req) // want `doc example references DefaultClient.Doo; did you mean Do\?`
+func ProcessRequest(r *Request) *Request { return r }
+
+// DefaultClient is used when none is given.
+var DefaultClient = &Client{}
+
+// Retry runs fn until it succeeds. The example declares its own helpers:
+//
+//	type Fetcher struct{ URL string }
+//
+//	func (f Fetcher) Fetch() error { return nil }
+//
+//	err := Retry(Fetcher{URL: "http://example.com"}.Fetch)
+func Retry(fn func() error) error { return fn() }
+
+// Configure shows placeholders and pseudo-code, which are not checked:
+//
+//	cfg := Configure(MyType{...})
+//	var x Foo
+//	Configure(x, ...)
+func Configure(v interface{}) interface{} { return v }
+
+// Unparsable examples are skipped entirely:
+//
+//	client.<Method>(args)
+func Unparsable() {}
+
+// Stale points at a function that no longer exists:
+//
+//	store.Lookup(key) // want `doc example references store.Lookup, which does not exist`
+func Stale() {}
+
+// helper is unexported, so its doc is not checked:
+//
+//	Missing()
+func helper() {}
+
+// Illustrate names types that only exist in the example's world:
+//
+//	func DoSomething(ctx context.Context, arg Arg) (Result, error)
+func Illustrate() {}
+
+// file is unexported, so examples naming a variable file don't refer to it.
+type file struct{ name string }
+
+// Open opens a file:
+//
+//	f, err := Open("notes.txt")
+//
+// Read from it into a buffer:
+//
+//	data := make([]byte, 100)
+//	count, err := f.Read(data)
+//
+// Selectors on names matching unexported types are not resolved:
+//
+//	file.Read(data)
+func Open(name string) (*File, error) { return &File{f: &file{name: name}}, nil }
+
+// File is an open file.
+type File struct{ f *file }
+
+// Read reads from the file.
+func (f *File) Read(b []byte) (int, error) { return 0, nil }
+
+// conn is the package's own connection, a *Client without Write.
+var conn = &Client{}
+
+// Dial connects to addr:
+//
+//	conn, err := Dial("localhost:80")
+//
+// Variables of earlier blocks stay in scope in later ones, so this conn is
+// the one from the example above, not the package's conn:
+//
+//	conn.Write(data)
+func Dial(addr string) (*Conn, error) { return &Conn{}, nil }
+
+// Conn is a connection.
+type Conn struct{}
+
+// Write writes to the connection.
+func (c *Conn) Write(b []byte) (int, error) { return len(b), nil }
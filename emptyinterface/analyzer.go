@@ -5,7 +5,9 @@
 package emptyinterface
 
 import (
+	"flag"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"strings"
 
@@ -13,6 +15,7 @@ import (
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
 
+	"github.com/spechtlabs/golint-sl/internal/goversion"
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
 
@@ -25,13 +28,22 @@ Common problematic patterns:
    - Wrap with type-safe getters/setters
    
 2. Slices of interface{}: []interface{}
-   - Use concrete types or generics (Go 1.18+)
+   - Use concrete types or generics (Go 1.18+; generics are not
+     suggested to modules targeting older versions)
    
 3. Functions returning interface{}
    - Return concrete types; "accept interfaces, return structs"
 
-4. Type assertions without ok check
+4. Exported functions taking interface{}, []interface{}, or ...interface{}
+   - Use a concrete type or a type parameter with a constraint:
+     func Batch[T Item](items ...T)
+   - Formatting functions, like Logf(format string, args ...any), and
+     packages using reflect throughout are not reported
+
+5. Type assertions without ok check
    - Always use val, ok := x.(Type)
+   - Not reported inside a type switch case for the same type, or guarded by
+     an earlier comma-ok assertion of the same expression and type
 
 Acceptable uses:
 - json.Marshal/Unmarshal (stdlib necessity)
@@ -54,18 +66,40 @@ Example of wrapping unsafe code:
             return Item{}, ErrInvalidType
         }
         return item, nil
-    }`
+    }
+
+Flags:
+    -include-tests  also check type assertions in _test.go files`
+
+// includeTests holds the -include-tests flag
+var includeTests bool
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "emptyinterface",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("emptyinterface", flag.ExitOnError)
+	fs.BoolVar(&includeTests, "include-tests", false, "also check type assertions in _test.go files")
+	return *fs
+}
+
+// reflectHeavyUses is the number of references to package reflect from which
+// a package is taken to be reflection-based, like an encoder or an ORM
+const reflectHeavyUses = 10
+
+// formatNames are parts of the names of formatting and logging functions,
+// which take their operands as ...any
+var formatNames = []string{"Print", "Log", "Errorf"}
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	reflective := usesReflect(pass)
 
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
@@ -73,17 +107,28 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.TypeAssertExpr)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
 		switch node := n.(type) {
 		case *ast.FuncDecl:
 			checkFuncDecl(reporter, node)
+			if !reflective {
+				checkParams(reporter, node)
+			}
 
 		case *ast.TypeSpec:
 			checkTypeSpec(reporter, node)
 
 		case *ast.TypeAssertExpr:
-			checkTypeAssertion(node)
+			filename := pass.Fset.Position(node.Pos()).Filename
+			if includeTests || !strings.HasSuffix(filename, "_test.go") {
+				checkTypeAssertion(reporter, pass, node, stack)
+			}
 		}
+		return true
 	})
 
 	return nil, nil
@@ -119,6 +164,87 @@ func checkFuncDecl(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	}
 }
 
+// checkParams reports exported functions taking interface{}, []interface{},
+// or ...interface{}. Methods are left alone, since their signatures are often
+// fixed by the interfaces they implement, like sql.Scanner.
+func checkParams(reporter *nolint.Reporter, fn *ast.FuncDecl) {
+	if fn.Recv != nil || !fn.Name.IsExported() || fn.Type.Params == nil {
+		return
+	}
+	if isAllowedFuncName(fn.Name.Name) || isFormatFunc(fn) {
+		return
+	}
+
+	for _, field := range fn.Type.Params.List {
+		prefix, ok := emptyInterfaceParam(field.Type)
+		if !ok {
+			continue
+		}
+
+		names := []string{"_"}
+		if len(field.Names) > 0 {
+			names = strings.Split(getFieldNames(field), ", ")
+		}
+		for _, name := range names {
+			// Type parameters need Go 1.18
+			if goversion.Before(reporter.Pass, field.Pos(), goversion.Generics) {
+				reporter.Reportf(field.Pos(),
+					"exported function %s takes %s as %s, which accepts anything and defers type errors to run time; use a concrete type or an interface with the methods it needs",
+					fn.Name.Name, name, types.ExprString(field.Type))
+				continue
+			}
+			reporter.Reportf(field.Pos(),
+				"exported function %s takes %s as %s, which accepts anything and defers type errors to run time; use a concrete type or a type parameter with a constraint, like func %s[T Constraint](%s %sT)",
+				fn.Name.Name, name, types.ExprString(field.Type), fn.Name.Name, name, prefix)
+		}
+	}
+}
+
+// emptyInterfaceParam checks if expr is interface{}, []interface{}, or
+// ...interface{}, returning the prefix of the element type
+func emptyInterfaceParam(expr ast.Expr) (string, bool) {
+	switch t := expr.(type) {
+	case *ast.Ellipsis:
+		return "...", isEmptyInterface(t.Elt)
+	case *ast.ArrayType:
+		return "[]", t.Len == nil && isEmptyInterface(t.Elt)
+	}
+	return "", isEmptyInterface(expr)
+}
+
+// isFormatFunc checks for formatting and logging functions: named like
+// Printf or Logf, or taking a string right before a final ...any
+func isFormatFunc(fn *ast.FuncDecl) bool {
+	for _, part := range formatNames {
+		if strings.Contains(fn.Name.Name, part) {
+			return true
+		}
+	}
+
+	params := fn.Type.Params.List
+	if len(params) < 2 {
+		return false
+	}
+	last, ok := params[len(params)-1].Type.(*ast.Ellipsis)
+	if !ok || !isEmptyInterface(last.Elt) {
+		return false
+	}
+	prev, ok := params[len(params)-2].Type.(*ast.Ident)
+	return ok && prev.Name == "string"
+}
+
+// usesReflect checks if the package refers to package reflect at least
+// reflectHeavyUses times
+func usesReflect(pass *analysis.Pass) bool {
+	uses := 0
+	for _, obj := range pass.TypesInfo.Uses {
+		if obj.Pkg() != nil && obj.Pkg().Path() == "reflect" {
+			uses++
+		}
+	}
+	return uses >= reflectHeavyUses
+}
+
 func checkTypeSpec(reporter *nolint.Reporter, ts *ast.TypeSpec) {
 	// Check struct fields
 	structType, ok := ts.Type.(*ast.StructType)
@@ -138,20 +264,183 @@ func checkTypeSpec(reporter *nolint.Reporter, ts *ast.TypeSpec) {
 		// Flag []interface{} fields
 		if isSliceOfEmptyInterface(field.Type) {
 			fieldNames := getFieldNames(field)
+			suggestion := "a concrete slice type or generics"
+			if goversion.Before(reporter.Pass, field.Pos(), goversion.Generics) {
+				suggestion = "a concrete slice type"
+			}
 			reporter.Reportf(field.Pos(),
-				"field %q is []interface{}; consider using a concrete slice type or generics",
-				fieldNames)
+				"field %q is []interface{}; consider using %s",
+				fieldNames, suggestion)
 		}
 	}
 }
 
-func checkTypeAssertion(_ *ast.TypeAssertExpr) {
-	// Type assertion checking is complex and requires parent context
-	// to determine if the ok pattern is used. This is left as a
-	// placeholder for future implementation.
-	//
-	// TODO: Implement proper type assertion checking by analyzing
-	// the parent assignment statement.
+// checkTypeAssertion reports x.(T) used in the single-value form, which
+// panics when x does not hold a T
+func checkTypeAssertion(reporter *nolint.Reporter, pass *analysis.Pass, expr *ast.TypeAssertExpr, stack []ast.Node) {
+	// x.(type) in a type switch
+	if expr.Type == nil {
+		return
+	}
+	if isCommaOk(expr, stack) || inTypeSwitchCase(pass, expr, stack) || isGuarded(pass, expr, stack) {
+		return
+	}
+
+	reporter.Reportf(expr.Pos(),
+		"type assertion %s panics when %s does not hold a %s; use the comma-ok form v, ok := %s and handle !ok",
+		types.ExprString(expr), types.ExprString(expr.X), types.ExprString(expr.Type), types.ExprString(expr))
+}
+
+// isCommaOk checks if the assertion is the single value assigned to two
+// variables, as in v, ok := x.(T) or var v, ok = x.(T)
+func isCommaOk(expr *ast.TypeAssertExpr, stack []ast.Node) bool {
+	var child ast.Node = expr
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch parent := stack[i].(type) {
+		case *ast.ParenExpr:
+			child = parent
+			continue
+		case *ast.AssignStmt:
+			return len(parent.Lhs) == 2 && len(parent.Rhs) == 1 && parent.Rhs[0] == child
+		case *ast.ValueSpec:
+			return len(parent.Names) == 2 && len(parent.Values) == 1 && parent.Values[0] == child
+		}
+		return false
+	}
+	return false
+}
+
+// inTypeSwitchCase checks if the assertion is inside a type switch on the same
+// expression, in a case listing the asserted type
+func inTypeSwitchCase(pass *analysis.Pass, expr *ast.TypeAssertExpr, stack []ast.Node) bool {
+	for i := len(stack) - 2; i > 0; i-- {
+		clause, ok := stack[i].(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+		ts, ok := stack[i-2].(*ast.TypeSwitchStmt)
+		if !ok || !sameExpr(switchSubject(ts), expr.X) {
+			continue
+		}
+		for _, t := range clause.List {
+			if sameType(pass, t, expr.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// switchSubject returns x of switch x.(type) or switch v := x.(type)
+func switchSubject(ts *ast.TypeSwitchStmt) ast.Expr {
+	var assert ast.Expr
+	switch s := ts.Assign.(type) {
+	case *ast.ExprStmt:
+		assert = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			assert = s.Rhs[0]
+		}
+	}
+	if ta, ok := ast.Unparen(assert).(*ast.TypeAssertExpr); ok {
+		return ta.X
+	}
+	return nil
+}
+
+// isGuarded checks if the assertion follows a comma-ok assertion of the same
+// expression and type that was checked, either as the body of
+// if _, ok := x.(T); ok { ... } or after if _, ok := x.(T); !ok { return }
+func isGuarded(pass *analysis.Pass, expr *ast.TypeAssertExpr, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.IfStmt:
+			if i+1 < len(stack) && stack[i+1] == node.Body && guardsAssertion(pass, node, expr, false) {
+				return true
+			}
+		case *ast.BlockStmt:
+			if i+1 < len(stack) && precededByGuard(pass, node, stack[i+1], expr) {
+				return true
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
+
+// precededByGuard checks if the statement before stmt in block is an early
+// exit on a failed comma-ok assertion matching expr
+func precededByGuard(pass *analysis.Pass, block *ast.BlockStmt, stmt ast.Node, expr *ast.TypeAssertExpr) bool {
+	for i, s := range block.List {
+		if s != stmt {
+			continue
+		}
+		if i == 0 {
+			return false
+		}
+		ifStmt, ok := block.List[i-1].(*ast.IfStmt)
+		return ok && ifStmt.Else == nil && exits(ifStmt.Body) && guardsAssertion(pass, ifStmt, expr, true)
+	}
+	return false
+}
+
+// guardsAssertion checks if ifStmt is if _, ok := x.(T); ok, or !ok when
+// negated, for the x and T of expr
+func guardsAssertion(pass *analysis.Pass, ifStmt *ast.IfStmt, expr *ast.TypeAssertExpr, negated bool) bool {
+	assign, ok := ifStmt.Init.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return false
+	}
+	prior, ok := ast.Unparen(assign.Rhs[0]).(*ast.TypeAssertExpr)
+	if !ok || prior.Type == nil || !sameExpr(prior.X, expr.X) || !sameType(pass, prior.Type, expr.Type) {
+		return false
+	}
+	okIdent, ok := assign.Lhs[1].(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	cond := ast.Unparen(ifStmt.Cond)
+	if negated {
+		not, ok := cond.(*ast.UnaryExpr)
+		if !ok || not.Op != token.NOT {
+			return false
+		}
+		cond = ast.Unparen(not.X)
+	}
+	ident, ok := cond.(*ast.Ident)
+	return ok && ident.Name == okIdent.Name
+}
+
+// exits checks if block ends in a return, panic, break, or continue
+func exits(block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := last.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	}
+	return false
+}
+
+// sameExpr checks if a and b are spelled the same
+func sameExpr(a, b ast.Expr) bool {
+	return a != nil && b != nil && types.ExprString(ast.Unparen(a)) == types.ExprString(ast.Unparen(b))
+}
+
+// sameType checks if the type expressions a and b denote identical types
+func sameType(pass *analysis.Pass, a, b ast.Expr) bool {
+	ta, tb := pass.TypesInfo.TypeOf(a), pass.TypesInfo.TypeOf(b)
+	return ta != nil && tb != nil && types.Identical(ta, tb)
 }
 
 func isEmptyInterface(expr ast.Expr) bool {
@@ -0,0 +1,35 @@
+package emptyinterface_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/emptyinterface"
+)
+
+func TestEmptyInterfaceTypeAssertions(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, emptyinterface.Analyzer, "a")
+}
+
+func TestEmptyInterfaceIncludeTests(t *testing.T) {
+	if err := emptyinterface.Analyzer.Flags.Set("include-tests", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = emptyinterface.Analyzer.Flags.Set("include-tests", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, emptyinterface.Analyzer, "tests")
+}
+
+func TestEmptyInterfaceParams(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, emptyinterface.Analyzer, "params", "reflective")
+}
+
+func TestEmptyInterfaceGoVersion(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, filepath.Join(testdata, "legacy"), emptyinterface.Analyzer, "example.com/legacy")
+}
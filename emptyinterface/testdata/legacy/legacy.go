@@ -0,0 +1,10 @@
+// Package legacy targets Go 1.17, before type parameters.
+package legacy
+
+type Batch struct {
+	Items []interface{} // want `field "Items" is \[\]interface\{\}; consider using a concrete slice type$`
+}
+
+func Enqueue(queue string, items []interface{}) int { // want `exported function Enqueue takes items as \[\]interface\{\}, which accepts anything and defers type errors to run time; use a concrete type or an interface with the methods it needs$`
+	return len(queue) + len(items)
+}
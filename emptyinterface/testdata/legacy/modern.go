@@ -0,0 +1,11 @@
+//go:build go1.18
+
+package legacy
+
+type Queue struct {
+	Items []interface{} // want `field "Items" is \[\]interface\{\}; consider using a concrete slice type or generics`
+}
+
+func Store(key string, value interface{}) int { // want `exported function Store takes value as interface\{\}, which accepts anything and defers type errors to run time; use a concrete type or a type parameter with a constraint`
+	return len(key)
+}
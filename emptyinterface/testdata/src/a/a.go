@@ -0,0 +1,75 @@
+package a
+
+import "fmt"
+
+type Foo struct{ Name string }
+
+func bare(x any) string {
+	v := x.(Foo) // want `type assertion x.\(Foo\) panics when x does not hold a Foo; use the comma-ok form v, ok := x.\(Foo\) and handle !ok`
+	return v.Name
+}
+
+func inline(x any) string {
+	return x.(fmt.Stringer).String() // want `type assertion x.\(fmt.Stringer\) panics when x does not hold a fmt.Stringer`
+}
+
+func commaOk(x any) (string, bool) {
+	v, ok := x.(Foo)
+	if !ok {
+		return "", false
+	}
+	return v.Name, true
+}
+
+func commaOkVar(x any) bool {
+	var _, ok = (x).(Foo)
+	return ok
+}
+
+func typeSwitch(x any) string {
+	switch v := x.(type) {
+	case Foo:
+		return v.Name
+	case fmt.Stringer:
+		return v.String()
+	}
+	return ""
+}
+
+func inTypeSwitchCase(x any) string {
+	switch x.(type) {
+	case Foo, *Foo:
+		return x.(Foo).Name
+	case fmt.Stringer:
+		return x.(Foo).Name // want `type assertion x.\(Foo\) panics`
+	}
+	return ""
+}
+
+func guardedBody(x any) string {
+	if _, ok := x.(fmt.Stringer); ok {
+		return x.(fmt.Stringer).String()
+	}
+	return ""
+}
+
+func guardedEarlyReturn(x any) string {
+	if _, ok := x.(Foo); !ok {
+		return ""
+	}
+	return x.(Foo).Name
+}
+
+func guardedOtherType(x any) string {
+	if _, ok := x.(fmt.Stringer); !ok {
+		return ""
+	}
+	return x.(Foo).Name // want `type assertion x.\(Foo\) panics`
+}
+
+func guardedOtherExpr(x, y any) string {
+	if _, ok := x.(Foo); ok {
+		return y.(Foo).Name // want `type assertion y.\(Foo\) panics`
+	}
+	return ""
+}
@@ -0,0 +1,10 @@
+package a
+
+import "testing"
+
+func TestBare(t *testing.T) {
+	if bare(Foo{Name: "x"}) != "x" {
+		t.Fatal("name")
+	}
+	_ = any(Foo{}).(Foo)
+}
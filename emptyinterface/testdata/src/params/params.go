@@ -0,0 +1,60 @@
+package params
+
+import "fmt"
+
+type Item struct{ ID string }
+
+func Batch(items ...any) int { // want `exported function Batch takes items as \.\.\.any, which accepts anything and defers type errors to run time; use a concrete type or a type parameter with a constraint, like func Batch\[T Constraint\]\(items \.\.\.T\)`
+	return len(items)
+}
+
+func Enqueue(queue string, items []interface{}) int { // want `exported function Enqueue takes items as \[\]interface\{\}`
+	return len(queue) + len(items)
+}
+
+func Store(key string, value any) string { // want `exported function Store takes value as any`
+	return fmt.Sprint(key, value)
+}
+
+func Pair(a, b interface{}) bool { // want `exported function Pair takes a as interface\{\}` `exported function Pair takes b as interface\{\}`
+	return a == b
+}
+
+// Logf formats like fmt.Printf
+func Logf(format string, args ...any) string {
+	return fmt.Sprintf(format, args...)
+}
+
+// Info takes a message and key-value pairs, like slog
+func Info(msg string, keysAndValues ...any) int {
+	return len(msg) + len(keysAndValues)
+}
+
+func Println(args ...any) string {
+	return fmt.Sprintln(args...)
+}
+
+// Marshal encodes any value
+func Marshal(v any) ([]byte, error) {
+	return []byte(fmt.Sprint(v)), nil
+}
+
+// BatchOf is the generic form
+func BatchOf[T fmt.Stringer](items ...T) int {
+	return len(items)
+}
+
+func Items(items ...Item) int {
+	return len(items)
+}
+
+func batch(items ...any) int {
+	return len(items)
+}
+
+type Queue struct{}
+
+// Push is a method, whose signature an interface may fix
+func (Queue) Push(item any) bool {
+	return item != nil
+}
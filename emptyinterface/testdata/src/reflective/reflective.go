@@ -0,0 +1,24 @@
+package reflective
+
+import "reflect"
+
+// Walk is reflection-based, so it takes any
+func Walk(v any) int {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Struct:
+		return rv.NumField()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len()
+	case reflect.Pointer, reflect.Interface:
+		return Walk(rv.Elem().Interface())
+	}
+	if rv.Type().Kind() == reflect.String {
+		return 1
+	}
+	return 0
+}
+
+func Copy(dst, src any) {
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(src))
+}
@@ -0,0 +1,10 @@
+package tests
+
+import "testing"
+
+type Foo struct{}
+
+func TestAssert(t *testing.T) {
+	var x any = Foo{}
+	_ = x.(Foo) // want `type assertion x.\(Foo\) panics`
+}
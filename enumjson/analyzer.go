@@ -0,0 +1,474 @@
+// Package enumjson provides an analyzer that checks enum types round-trip
+// through JSON and YAML consistently.
+//
+// A named integer enum without marshalers is serialized as a bare number, so
+// reordering the constants silently changes the wire format. Marshalers that
+// forget a constant, or unmarshalers that turn unknown input into the zero
+// value, corrupt data without an error.
+package enumjson
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check that enum types round-trip through JSON/YAML consistently
+
+This analyzer detects:
+1. Integer enum types used in json-tagged struct fields without
+   MarshalJSON/MarshalText (serialized as bare ints; disable with
+   -require-marshalers=false)
+2. Enum types with a marshaler but no unmarshaler, or the other way around
+3. Marshal/Unmarshal methods that do not handle every constant of the enum
+4. Unmarshal methods without an error path for unknown values (they
+   silently decode to the zero value)
+5. String enum constants containing characters that need JSON escaping
+
+An enum is a named integer or string type with at least two constants.
+
+Bad:
+    type Phase int
+    const (
+        Pending Phase = iota
+        Running
+    )
+    type Status struct {
+        Phase Phase ` + "`json:\"phase\"`" + ` // encoded as 0, 1
+    }
+
+Good:
+    func (p Phase) MarshalText() ([]byte, error) { ... }
+    func (p *Phase) UnmarshalText(b []byte) error {
+        switch string(b) {
+        case "pending": *p = Pending
+        case "running": *p = Running
+        default: return fmt.Errorf("unknown phase %q", b)
+        }
+        return nil
+    }
+
+Flags:
+    -require-marshalers  require marshalers for integer enums in tagged structs (default true)`
+
+var requireMarshalers bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "enumjson",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("enumjson", flag.ExitOnError)
+	fs.BoolVar(&requireMarshalers, "require-marshalers", true,
+		"require marshalers for integer enums used in json-tagged struct fields")
+	return *fs
+}
+
+// marshalMethods and unmarshalMethods are the encoding hooks that control the
+// wire format
+var (
+	marshalMethods   = []string{"MarshalJSON", "MarshalText", "MarshalYAML"}
+	unmarshalMethods = []string{"UnmarshalJSON", "UnmarshalText", "UnmarshalYAML"}
+)
+
+// enum is a named type with its constants in declaration order
+type enum struct {
+	named     *types.Named
+	constants []*types.Const
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	enums := collectEnums(pass)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.StructType)(nil),
+	}
+
+	funcs := make(map[*types.Func]*ast.FuncDecl)
+	var methods []*ast.FuncDecl
+	var structs []*ast.StructType
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if fn, ok := pass.TypesInfo.Defs[node.Name].(*types.Func); ok {
+				funcs[fn] = node
+			}
+			if node.Recv != nil && node.Body != nil {
+				methods = append(methods, node)
+			}
+		case *ast.StructType:
+			structs = append(structs, node)
+		}
+	})
+
+	for _, st := range structs {
+		checkTaggedFields(reporter, pass, st, enums)
+	}
+
+	for _, e := range sortedEnums(enums) {
+		checkMethodPairs(reporter, pass, e, funcs)
+		checkStringConstants(reporter, pass, e)
+	}
+
+	for _, fd := range methods {
+		checkEncodingMethod(reporter, pass, fd, enums, funcs)
+	}
+
+	return nil, nil
+}
+
+// collectEnums finds named integer and string types with at least two constants
+func collectEnums(pass *analysis.Pass) map[*types.Named]*enum {
+	enums := make(map[*types.Named]*enum)
+
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok {
+			continue
+		}
+		named, ok := c.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != pass.Pkg || !isEnumUnderlying(named) {
+			continue
+		}
+		if enums[named] == nil {
+			enums[named] = &enum{named: named}
+		}
+		enums[named].constants = append(enums[named].constants, c)
+	}
+
+	for named, e := range enums {
+		if len(e.constants) < 2 {
+			delete(enums, named)
+			continue
+		}
+		sort.Slice(e.constants, func(i, j int) bool {
+			return e.constants[i].Pos() < e.constants[j].Pos()
+		})
+	}
+
+	return enums
+}
+
+// sortedEnums returns enums in declaration order for stable diagnostics
+func sortedEnums(enums map[*types.Named]*enum) []*enum {
+	sorted := make([]*enum, 0, len(enums))
+	for _, e := range enums {
+		sorted = append(sorted, e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].named.Obj().Pos() < sorted[j].named.Obj().Pos()
+	})
+	return sorted
+}
+
+func isEnumUnderlying(named *types.Named) bool {
+	basic, ok := named.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsInteger|types.IsString) != 0
+}
+
+func isIntegerEnum(e *enum) bool {
+	basic, ok := e.named.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// lookupMethod finds a method on the enum type or its pointer
+func lookupMethod(pass *analysis.Pass, named *types.Named, name string) *types.Func {
+	obj, _, _ := types.LookupFieldOrMethod(named, true, pass.Pkg, name)
+	fn, _ := obj.(*types.Func)
+	return fn
+}
+
+// firstMethod returns the first of names implemented by the enum type
+func firstMethod(pass *analysis.Pass, named *types.Named, names []string) *types.Func {
+	for _, name := range names {
+		if fn := lookupMethod(pass, named, name); fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// checkTaggedFields flags integer enums without marshalers in json-tagged fields
+func checkTaggedFields(reporter *nolint.Reporter, pass *analysis.Pass, st *ast.StructType, enums map[*types.Named]*enum) {
+	if !requireMarshalers || st.Fields == nil {
+		return
+	}
+
+	for _, field := range st.Fields.List {
+		if !hasJSONTag(field) {
+			continue
+		}
+
+		e := enums[enumOf(pass.TypesInfo.TypeOf(field.Type))]
+		if e == nil || !isIntegerEnum(e) {
+			continue
+		}
+		if firstMethod(pass, e.named, marshalMethods) != nil {
+			continue
+		}
+
+		reporter.Reportf(field.Type.Pos(),
+			"enum %s is encoded as a bare integer; implement MarshalText/UnmarshalText so the wire format uses names",
+			e.named.Obj().Name())
+	}
+}
+
+// hasJSONTag checks for a json struct tag that does not skip the field
+func hasJSONTag(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	return ok && value != "-"
+}
+
+// enumOf unwraps pointers, slices, arrays, and map values to a named type
+func enumOf(t types.Type) *types.Named {
+	for t != nil {
+		switch u := t.(type) {
+		case *types.Named:
+			return u
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkMethodPairs flags enums that can be encoded but not decoded, or vice versa
+func checkMethodPairs(reporter *nolint.Reporter, pass *analysis.Pass, e *enum, funcs map[*types.Func]*ast.FuncDecl) {
+	marshal := firstMethod(pass, e.named, marshalMethods)
+	unmarshal := firstMethod(pass, e.named, unmarshalMethods)
+
+	switch {
+	case marshal != nil && unmarshal == nil:
+		if fd := funcs[marshal]; fd != nil {
+			reporter.Reportf(fd.Name.Pos(),
+				"enum %s has %s but no Unmarshal counterpart; decoding its own output fails",
+				e.named.Obj().Name(), marshal.Name())
+		}
+	case unmarshal != nil && marshal == nil:
+		if fd := funcs[unmarshal]; fd != nil {
+			reporter.Reportf(fd.Name.Pos(),
+				"enum %s has %s but no Marshal counterpart; encoding produces a different format than decoding accepts",
+				e.named.Obj().Name(), unmarshal.Name())
+		}
+	}
+}
+
+// checkEncodingMethod checks a Marshal*/Unmarshal* method on an enum for
+// constant coverage and, for Unmarshal, an error path
+func checkEncodingMethod(reporter *nolint.Reporter, pass *analysis.Pass, fd *ast.FuncDecl, enums map[*types.Named]*enum, funcs map[*types.Func]*ast.FuncDecl) {
+	name := fd.Name.Name
+	isUnmarshal := contains(unmarshalMethods, name)
+	if !isUnmarshal && !contains(marshalMethods, name) {
+		return
+	}
+
+	fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	e := enums[enumOf(recv.Type())]
+	if e == nil {
+		return
+	}
+
+	if missing := missingConstants(pass, fd, e, funcs); len(missing) > 0 {
+		reporter.Reportf(fd.Name.Pos(),
+			"%s does not handle %s constants: %s",
+			name, e.named.Obj().Name(), strings.Join(missing, ", "))
+	}
+
+	if isUnmarshal && !hasErrorPath(pass, fd.Body) {
+		reporter.Reportf(fd.Name.Pos(),
+			"%s never returns an error for unknown values; unknown input silently decodes to the zero value",
+			name)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// missingConstants returns the enum constants not referenced by a method,
+// following package-level lookup tables and same-package helpers it calls.
+// It returns nil when the method references no constants at all, since the
+// coverage then cannot be determined.
+func missingConstants(pass *analysis.Pass, fd *ast.FuncDecl, e *enum, funcs map[*types.Func]*ast.FuncDecl) []string {
+	referenced := make(map[*types.Const]bool)
+	visited := make(map[ast.Node]bool)
+
+	var collect func(n ast.Node)
+	collect = func(n ast.Node) {
+		if n == nil || visited[n] {
+			return
+		}
+		visited[n] = true
+
+		ast.Inspect(n, func(child ast.Node) bool {
+			ident, ok := child.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			switch obj := pass.TypesInfo.Uses[ident].(type) {
+			case *types.Const:
+				referenced[obj] = true
+			case *types.Var:
+				// names := map[Phase]string{...}
+				if obj.Parent() == pass.Pkg.Scope() {
+					collect(packageVarSpec(pass, obj))
+				}
+			case *types.Func:
+				if decl := funcs[obj]; decl != nil {
+					collect(decl.Body)
+				}
+			}
+			return true
+		})
+	}
+	collect(fd.Body)
+
+	var missing []string
+	covered := 0
+	for _, c := range e.constants {
+		if referenced[c] {
+			covered++
+		} else {
+			missing = append(missing, c.Name())
+		}
+	}
+
+	if covered == 0 {
+		return nil
+	}
+	return missing
+}
+
+// packageVarSpec returns the declaration of a package-level variable
+func packageVarSpec(pass *analysis.Pass, obj *types.Var) ast.Node {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for _, name := range vs.Names {
+					if pass.TypesInfo.Defs[name] == obj {
+						return vs
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasErrorPath checks if a body can return a newly constructed or sentinel
+// error, as opposed to only nil or errors passed through from a decoder
+func hasErrorPath(pass *analysis.Pass, body *ast.BlockStmt) bool {
+	found := false
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) == 0 {
+			return !found
+		}
+
+		switch last := ast.Unparen(ret.Results[len(ret.Results)-1]).(type) {
+		case *ast.CallExpr, *ast.CompositeLit, *ast.UnaryExpr:
+			found = true
+		case *ast.Ident:
+			// Sentinel error: return ErrUnknownPhase
+			if v, ok := pass.TypesInfo.Uses[last].(*types.Var); ok && v.Parent() == pass.Pkg.Scope() {
+				found = true
+			}
+		case *ast.SelectorExpr:
+			if _, ok := pass.TypesInfo.Uses[last.Sel].(*types.Var); ok {
+				found = true
+			}
+		}
+		return !found
+	})
+
+	return found
+}
+
+// checkStringConstants flags string enum values that need JSON escaping
+func checkStringConstants(reporter *nolint.Reporter, pass *analysis.Pass, e *enum) {
+	if isIntegerEnum(e) {
+		return
+	}
+
+	for _, c := range e.constants {
+		if c.Val().Kind() != constant.String {
+			continue
+		}
+		value := constant.StringVal(c.Val())
+		if r, ok := needsEscape(value); ok {
+			reporter.Reportf(c.Pos(),
+				"enum constant %s value %q contains %q, which JSON escapes; clients comparing raw strings will not match",
+				c.Name(), value, r)
+		}
+	}
+}
+
+// needsEscape returns the first rune encoding/json escapes by default
+func needsEscape(s string) (rune, bool) {
+	for _, r := range s {
+		switch {
+		case r < 0x20, r == '"', r == '\\', r == '<', r == '>', r == '&',
+			r == '\u2028', r == '\u2029':
+			return r, true
+		}
+	}
+	return 0, false
+}
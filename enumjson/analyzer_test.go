@@ -0,0 +1,24 @@
+package enumjson_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/enumjson"
+)
+
+func TestEnumJSONAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, enumjson.Analyzer, "a")
+}
+
+func TestEnumJSONIntegerWireFormat(t *testing.T) {
+	if err := enumjson.Analyzer.Flags.Set("require-marshalers", "false"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = enumjson.Analyzer.Flags.Set("require-marshalers", "true") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, enumjson.Analyzer, "ints")
+}
@@ -0,0 +1,128 @@
+package a
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Bad: integer enum in an API struct without marshalers
+type Phase int
+
+const (
+	Pending Phase = iota
+	Running
+	Done
+)
+
+type Status struct {
+	Phase   Phase   `json:"phase"`   // want `enum Phase is encoded as a bare integer`
+	History []Phase `json:"history"` // want `enum Phase is encoded as a bare integer`
+	Local   Phase   `json:"-"`
+	Plain   Phase
+}
+
+// Good: full round trip
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+var colorNames = map[Color]string{
+	Red:   "red",
+	Green: "green",
+	Blue:  "blue",
+}
+
+var ErrUnknownColor = errors.New("unknown color")
+
+func (c Color) MarshalText() ([]byte, error) {
+	name, ok := colorNames[c]
+	if !ok {
+		return nil, fmt.Errorf("invalid color %d", int(c))
+	}
+	return []byte(name), nil
+}
+
+func (c *Color) UnmarshalText(b []byte) error {
+	for value, name := range colorNames {
+		if name == string(b) {
+			*c = value
+			return nil
+		}
+	}
+	return ErrUnknownColor
+}
+
+type Palette struct {
+	Primary Color `json:"primary"`
+}
+
+// Bad: marshaler misses a constant, unmarshaler defaults silently
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+)
+
+func (l Level) MarshalJSON() ([]byte, error) { // want `MarshalJSON does not handle Level constants: Warn`
+	switch l {
+	case Debug:
+		return json.Marshal("debug")
+	case Info:
+		return json.Marshal("info")
+	}
+	return nil, fmt.Errorf("invalid level %d", int(l))
+}
+
+func (l *Level) UnmarshalJSON(b []byte) error { // want `UnmarshalJSON never returns an error for unknown values`
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "debug":
+		*l = Debug
+	case "info":
+		*l = Info
+	case "warn":
+		*l = Warn
+	}
+	return nil
+}
+
+// Bad: can be encoded but not decoded
+type Mode int
+
+const (
+	ReadOnly Mode = iota
+	ReadWrite
+)
+
+func (m Mode) String() string {
+	if m == ReadOnly {
+		return "ro"
+	}
+	if m == ReadWrite {
+		return "rw"
+	}
+	return "unknown"
+}
+
+func (m Mode) MarshalText() ([]byte, error) { // want `enum Mode has MarshalText but no Unmarshal counterpart`
+	return []byte(m.String()), nil
+}
+
+// Bad: string enum values that JSON escapes
+type Op string
+
+const (
+	OpLess    Op = "<" // want `enum constant OpLess value "<" contains '<', which JSON escapes`
+	OpEqual   Op = "eq"
+	OpGreater Op = "gt"
+)
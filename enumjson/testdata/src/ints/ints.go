@@ -0,0 +1,13 @@
+package ints
+
+// Integer wire format is accepted when -require-marshalers=false
+type Phase int
+
+const (
+	Pending Phase = iota
+	Running
+)
+
+type Status struct {
+	Phase Phase `json:"phase"`
+}
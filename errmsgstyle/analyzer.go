@@ -0,0 +1,241 @@
+// Package errmsgstyle provides an analyzer that checks error strings follow
+// the Go conventions.
+//
+// Error strings are usually printed after other context, like
+// "load config: open config.yaml: no such file or directory", so they
+// should not be capitalized, end with punctuation, or announce that they
+// are errors.
+package errmsgstyle
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check error strings follow the Go conventions
+
+This analyzer checks the messages passed to errors.New, fmt.Errorf,
+humane.New and humane.Wrap, and New, Errorf, Wrap, Wrapf, WithMessage and
+WithMessagef from github.com/pkg/errors. It reports messages that:
+1. Start with a capital letter, unless the first word is an initialism
+   like HTTP or ID
+2. End with punctuation or a newline
+3. Start with "error:", which repeats what the value already is
+4. Repeat the name of the enclosing function
+
+Bad:
+    errors.New("Invalid user ID.")
+    fmt.Errorf("error: connection refused: %w", err)
+    fmt.Errorf("getUser: getUser failed: %w", err)
+
+Good:
+    errors.New("invalid user ID")
+    fmt.Errorf("connect: %w", err)
+    fmt.Errorf("getUser: %w", err)
+
+Capitalized messages come with a suggested fix that lowercases the first
+letter.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errmsgstyle",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// messageFuncs are the functions that take an error message, by package
+// path, with the index of the message argument
+var messageFuncs = map[string]map[string]int{
+	"errors": {"New": 0},
+	"fmt":    {"Errorf": 0},
+	"github.com/pkg/errors": {
+		"New": 0, "Errorf": 0,
+		"Wrap": 1, "Wrapf": 1, "WithMessage": 1, "WithMessagef": 1,
+	},
+	"github.com/sierrasoftworks/humane-errors-go": {"New": 0, "Wrap": 1},
+}
+
+// errorPrefix matches messages that start by saying they are an error
+var errorPrefix = regexp.MustCompile(`(?i)^(error|err)\s*:`)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		call := n.(*ast.CallExpr)
+		lit := messageArg(pass, call)
+		if lit == nil {
+			return true
+		}
+		msg, err := strconv.Unquote(lit.Value)
+		if err != nil || msg == "" {
+			return true
+		}
+
+		switch {
+		case errorPrefix.MatchString(msg):
+			reporter.Reportf(lit.Pos(),
+				"error strings should not start with %q; the value is already an error",
+				errorPrefix.FindString(msg))
+		case isCapitalized(msg):
+			reportCapitalized(reporter, lit)
+		}
+
+		if last, _ := utf8.DecodeLastRuneInString(msg); strings.ContainsRune(".!?:;\n", last) {
+			reporter.Reportf(lit.Pos(), "error strings should not end with punctuation or newlines")
+		}
+
+		if name := enclosingFunc(stack); name != "" && repeatsName(msg, name) {
+			reporter.Reportf(lit.Pos(),
+				"error string repeats the function name %s; mention it once", name)
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// messageArg returns the message of a call to one of messageFuncs if it is
+// a string literal
+func messageArg(pass *analysis.Pass, call *ast.CallExpr) *ast.BasicLit {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return nil
+	}
+	index, ok := messageFuncs[fn.Pkg().Path()][fn.Name()]
+	if !ok || index >= len(call.Args) {
+		return nil
+	}
+	lit, ok := call.Args[index].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	return lit
+}
+
+// isCapitalized checks if msg starts with an upper case letter and the
+// first word is not an initialism like HTTP, ID or gRPC, or an identifier
+// like Config.Load
+func isCapitalized(msg string) bool {
+	first, _ := utf8.DecodeRuneInString(msg)
+	if !unicode.IsUpper(first) {
+		return false
+	}
+
+	word, _, _ := strings.Cut(msg, " ")
+	word = strings.TrimRight(word, ":,")
+	for _, r := range word[utf8.RuneLen(first):] {
+		if unicode.IsUpper(r) || unicode.IsDigit(r) || r == '.' || r == '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// reportCapitalized reports a capitalized message with a fix that
+// lowercases its first letter when the literal spells it out unescaped
+func reportCapitalized(reporter *nolint.Reporter, lit *ast.BasicLit) {
+	diag := &analysis.Diagnostic{
+		Pos:     lit.Pos(),
+		Message: "error strings should not be capitalized",
+	}
+
+	first, size := utf8.DecodeRuneInString(lit.Value[1:])
+	if unicode.IsUpper(first) {
+		start := lit.Pos() + 1
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Lowercase the first letter",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     start,
+				End:     start + token.Pos(size),
+				NewText: []byte(string(unicode.ToLower(first))),
+			}},
+		}}
+	}
+
+	reporter.Report(diag)
+}
+
+// enclosingFunc returns the name of the function declaration around the top
+// of stack
+func enclosingFunc(stack []ast.Node) string {
+	for i := len(stack) - 2; i >= 0; i-- {
+		if fn, ok := stack[i].(*ast.FuncDecl); ok {
+			return fn.Name.Name
+		}
+	}
+	return ""
+}
+
+// repeatsName checks if msg mentions the function name more than once,
+// either as written or split into words, like getUser and "get user"
+func repeatsName(msg, name string) bool {
+	msg = strings.ToLower(msg)
+	count := countWord(msg, strings.ToLower(name))
+	if words := splitWords(name); words != strings.ToLower(name) {
+		count += countWord(msg, words)
+	}
+	return count > 1
+}
+
+// splitWords turns a camel case name into lower case words, like getUser
+// into "get user"
+func splitWords(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// countWord counts the occurrences of word in s that are not part of a
+// longer word
+func countWord(s, word string) int {
+	count := 0
+	for i := 0; i+len(word) <= len(s); {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			break
+		}
+		start, end := i+j, i+j+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			count++
+		}
+		i = end
+	}
+	return count
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
@@ -0,0 +1,14 @@
+package errmsgstyle_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/errmsgstyle"
+)
+
+func TestErrMsgStyleAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, errmsgstyle.Analyzer, "a")
+}
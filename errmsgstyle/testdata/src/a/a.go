@@ -0,0 +1,55 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sierrasoftworks/humane-errors-go"
+)
+
+var (
+	errCapital     = errors.New("Invalid user")        // want `error strings should not be capitalized`
+	errPunctuation = errors.New("invalid user.")       // want `error strings should not end with punctuation or newlines`
+	errNewline     = errors.New("invalid user\n")      // want `error strings should not end with punctuation or newlines`
+	errPrefix      = errors.New("error: invalid user") // want `error strings should not start with "error:"`
+	errPrefixUpper = errors.New("Error: invalid user") // want `error strings should not start with "Error:"`
+	errRaw         = errors.New(`Raw message`)         // want `error strings should not be capitalized`
+	errInitialism  = errors.New("HTTP request failed")
+	errIdentifier  = errors.New("Config.Load failed")
+	errShortID     = errors.New("ID must be set")
+	errGood        = errors.New("invalid user")
+	errEllipsis    = errors.New("retrying...") // want `error strings should not end with punctuation or newlines`
+)
+
+func getUser(id string) error {
+	if id == "" {
+		return fmt.Errorf("getUser: getUser failed") // want `error string repeats the function name getUser`
+	}
+	if id == "-" {
+		return fmt.Errorf("getUser: get user failed for %q", id) // want `error string repeats the function name getUser`
+	}
+	return fmt.Errorf("getUser: %s not found", id)
+}
+
+func wrap(err error) error {
+	if err == nil {
+		return pkgerrors.Wrap(err, "Loading config") // want `error strings should not be capitalized`
+	}
+	if err != nil {
+		return pkgerrors.Wrapf(err, "load %s!", "config") // want `error strings should not end with punctuation or newlines`
+	}
+	return fmt.Errorf("Load config: %w", err) // want `error strings should not be capitalized`
+}
+
+func friendly(err error) error {
+	if err != nil {
+		return humane.Wrap(err, "Could not load config", "Check the config file exists.") // want `error strings should not be capitalized`
+	}
+	return humane.New("config is empty", "Add at least one entry.")
+}
+
+func notAMessage(msg string) error {
+	fmt.Println("Hello.")
+	return errors.New(msg)
+}
@@ -0,0 +1,10 @@
+package humane
+
+type Error interface {
+	error
+	Advice() []string
+}
+
+func New(message string, advice ...string) Error { return nil }
+
+func Wrap(err error, message string, advice ...string) Error { return nil }
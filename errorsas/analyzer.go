@@ -0,0 +1,237 @@
+// Package errorsas provides an analyzer that enforces errors.Is and errors.As
+// over == comparisons, type assertions, and type switches on errors.
+//
+// Since Go 1.13 errors are wrapped with fmt.Errorf("...: %w", err). A wrapped
+// error is never == to the sentinel it wraps, and never has the concrete type
+// of the error it wraps, so direct comparisons silently stop matching.
+package errorsas
+
+import (
+	"flag"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `enforce errors.Is and errors.As over == and type assertions on errors
+
+A wrapped error is neither == to the sentinel it wraps nor of the type of
+the error it wraps, so these checks stop matching once a callee starts
+wrapping with %w:
+
+    if err == sql.ErrNoRows { ... }          // use errors.Is(err, sql.ErrNoRows)
+    if e, ok := err.(*NotFoundError); ok {}  // use errors.As(err, &e)
+    switch err.(type) { ... }                // use errors.As per case
+
+Only operands whose static type is error are checked. Comparisons to nil,
+io.EOF (which readers return unwrapped), and code inside Is and As methods,
+which implement the comparison errors.Is and errors.As delegate to, are not
+reported.
+
+Flags:
+    -exempt-packages  comma-separated globs of package paths that predate wrapping`
+
+// exemptPackages holds the -exempt-packages flag
+var exemptPackages string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorsas",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("errorsas", flag.ExitOnError)
+	fs.StringVar(&exemptPackages, "exempt-packages", "",
+		"comma-separated globs of package paths that predate error wrapping")
+	return *fs
+}
+
+// unwrappedErrors are sentinels their producers return as is, by contract
+var unwrappedErrors = map[string]bool{
+	"io.EOF": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if isExemptPath(pass.Pkg.Path()) {
+		return nil, nil
+	}
+
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.BinaryExpr)(nil),
+		(*ast.SwitchStmt)(nil),
+		(*ast.TypeAssertExpr)(nil),
+		(*ast.TypeSwitchStmt)(nil),
+	}
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push || inComparisonMethod(stack) {
+			return true
+		}
+
+		switch node := n.(type) {
+		case *ast.BinaryExpr:
+			checkComparison(reporter, pass, node)
+		case *ast.SwitchStmt:
+			checkSwitch(reporter, pass, node)
+		case *ast.TypeAssertExpr:
+			checkTypeAssertion(reporter, pass, node)
+		case *ast.TypeSwitchStmt:
+			checkTypeSwitch(reporter, pass, node)
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// checkComparison reports err == target and err != target
+func checkComparison(reporter *nolint.Reporter, pass *analysis.Pass, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	if !isError(pass, expr.X) || !isError(pass, expr.Y) {
+		return
+	}
+	if isNil(pass, expr.X) || isNil(pass, expr.Y) || isUnwrapped(pass, expr.X) || isUnwrapped(pass, expr.Y) {
+		return
+	}
+
+	call := "errors.Is"
+	if expr.Op == token.NEQ {
+		call = "!errors.Is"
+	}
+	reporter.Reportf(expr.Pos(),
+		"comparing errors with %s does not match wrapped errors; use %s(%s, %s)",
+		expr.Op, call, types.ExprString(expr.X), types.ExprString(expr.Y))
+}
+
+// checkSwitch reports switch err { case target: }, which compares with ==
+func checkSwitch(reporter *nolint.Reporter, pass *analysis.Pass, stmt *ast.SwitchStmt) {
+	if stmt.Tag == nil || !isError(pass, stmt.Tag) {
+		return
+	}
+
+	for _, clause := range stmt.Body.List {
+		for _, value := range clause.(*ast.CaseClause).List {
+			if isNil(pass, value) || isUnwrapped(pass, value) || !isError(pass, value) {
+				continue
+			}
+			reporter.Reportf(stmt.Pos(),
+				"switch on error %s compares with == and does not match wrapped errors; use a switch with errors.Is(%s, ...) cases",
+				types.ExprString(stmt.Tag), types.ExprString(stmt.Tag))
+			return
+		}
+	}
+}
+
+// checkTypeAssertion reports err.(*T); x.(type) is handled by checkTypeSwitch
+func checkTypeAssertion(reporter *nolint.Reporter, pass *analysis.Pass, expr *ast.TypeAssertExpr) {
+	if expr.Type == nil || !isError(pass, expr.X) {
+		return
+	}
+
+	reporter.Reportf(expr.Pos(),
+		"type assertion on error %s does not match wrapped errors; use errors.As(%s, &target) with a target of type %s",
+		types.ExprString(expr.X), types.ExprString(expr.X), types.ExprString(expr.Type))
+}
+
+// checkTypeSwitch reports switch err.(type)
+func checkTypeSwitch(reporter *nolint.Reporter, pass *analysis.Pass, stmt *ast.TypeSwitchStmt) {
+	var assert *ast.TypeAssertExpr
+	switch s := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		assert, _ = s.X.(*ast.TypeAssertExpr)
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			assert, _ = s.Rhs[0].(*ast.TypeAssertExpr)
+		}
+	}
+	if assert == nil || !isError(pass, assert.X) {
+		return
+	}
+
+	reporter.Reportf(stmt.Pos(),
+		"type switch on error %s does not match wrapped errors; use errors.As(%s, &target) for each case",
+		types.ExprString(assert.X), types.ExprString(assert.X))
+}
+
+// isError checks if the static type of expr is the error interface
+func isError(pass *analysis.Pass, expr ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(expr)
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// isNil checks if expr is the predeclared nil
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, isNil := pass.TypesInfo.Uses[ident].(*types.Nil)
+	return isNil
+}
+
+// isUnwrapped checks for sentinels that are returned unwrapped by contract
+func isUnwrapped(pass *analysis.Pass, expr ast.Expr) bool {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return false
+	}
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || v.Pkg() == nil {
+		return false
+	}
+	return unwrappedErrors[v.Pkg().Path()+"."+v.Name()]
+}
+
+// inComparisonMethod checks if the stack is inside an Is or As method, which
+// implements the comparison errors.Is and errors.As delegate to
+func inComparisonMethod(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		fn, ok := stack[i].(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		return fn.Recv != nil && (fn.Name.Name == "Is" || fn.Name.Name == "As")
+	}
+	return false
+}
+
+// isExemptPath checks if pkgPath matches one of the -exempt-packages globs.
+// A glob matches a run of whole path elements, so internal/legacy covers
+// example.com/app/internal/legacy and the packages below it.
+func isExemptPath(pkgPath string) bool {
+	elems := strings.Split(pkgPath, "/")
+	for _, glob := range strings.Split(exemptPackages, ",") {
+		glob = strings.Trim(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+		n := strings.Count(glob, "/") + 1
+		for i := 0; i+n <= len(elems); i++ {
+			if ok, _ := path.Match(glob, strings.Join(elems[i:i+n], "/")); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package errorsas_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/errorsas"
+)
+
+func TestErrorsAsAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, errorsas.Analyzer, "a")
+}
+
+func TestErrorsAsExemptPackages(t *testing.T) {
+	if err := errorsas.Analyzer.Flags.Set("exempt-packages", "legacy"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = errorsas.Analyzer.Flags.Set("exempt-packages", "") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, errorsas.Analyzer, "example.com/legacy/store", "example.com/current/store")
+}
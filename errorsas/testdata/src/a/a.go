@@ -0,0 +1,125 @@
+package a
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type QueryError struct {
+	Query string
+}
+
+func (e *QueryError) Error() string { return "query failed: " + e.Query }
+
+func find(db *sql.DB, id string) error {
+	var name string
+	if err := db.QueryRow("SELECT name FROM users WHERE id = ?", id).Scan(&name); err != nil {
+		return fmt.Errorf("find user %s: %w", id, err)
+	}
+	return nil
+}
+
+// Bad: find wraps sql.ErrNoRows, so == never matches
+func exists(db *sql.DB, id string) (bool, error) {
+	err := find(db, id)
+	if err == sql.ErrNoRows { // want `comparing errors with == does not match wrapped errors; use errors.Is\(err, sql.ErrNoRows\)`
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Bad: != against a package sentinel
+func mustExist(err error) error {
+	if err != nil && err != ErrNotFound { // want `comparing errors with != does not match wrapped errors; use !errors.Is\(err, ErrNotFound\)`
+		return err
+	}
+	return nil
+}
+
+// Bad: switch compares each case with ==
+func classify(err error) string {
+	switch err { // want `switch on error err compares with == and does not match wrapped errors`
+	case nil:
+		return "ok"
+	case ErrNotFound:
+		return "missing"
+	}
+	return "failed"
+}
+
+// Bad: type assertion on an error
+func query(err error) string {
+	if qe, ok := err.(*QueryError); ok { // want `type assertion on error err does not match wrapped errors; use errors.As\(err, &target\) with a target of type \*QueryError`
+		return qe.Query
+	}
+	return ""
+}
+
+// Bad: type switch on an error
+func describe(err error) string {
+	switch e := err.(type) { // want `type switch on error err does not match wrapped errors; use errors.As\(err, &target\) for each case`
+	case *QueryError:
+		return e.Query
+	case *os.PathError:
+		return e.Path
+	}
+	return ""
+}
+
+// Good: nil checks and errors.Is/errors.As
+func handle(err error) string {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return "missing"
+	}
+	var qe *QueryError
+	if errors.As(err, &qe) {
+		return qe.Query
+	}
+	return err.Error()
+}
+
+// Good: readers return io.EOF unwrapped
+func drain(r io.Reader) error {
+	buf := make([]byte, 512)
+	for {
+		_, err := r.Read(buf)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Good: comparisons between non-error types
+func sameQuery(a, b *QueryError) bool {
+	return a == b
+}
+
+// Good: Is and As implement the comparison errors.Is and errors.As use
+type TimeoutError struct{}
+
+func (TimeoutError) Error() string { return "timeout" }
+
+func (TimeoutError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+func (TimeoutError) As(target any) bool {
+	_, ok := target.(*QueryError)
+	return ok
+}
+
+// Suppressed with nolint
+func legacyCompare(err error) bool {
+	return err == ErrNotFound //nolint:errorsas // the store never wraps
+}
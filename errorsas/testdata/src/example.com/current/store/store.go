@@ -0,0 +1,10 @@
+package store
+
+import "errors"
+
+var ErrClosed = errors.New("store closed")
+
+// Bad: not exempted
+func IsClosed(err error) bool {
+	return err == ErrClosed // want `comparing errors with ==`
+}
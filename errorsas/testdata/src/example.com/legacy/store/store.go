@@ -0,0 +1,10 @@
+package store
+
+import "errors"
+
+var ErrClosed = errors.New("store closed")
+
+// Good: exempted with -exempt-packages=legacy
+func IsClosed(err error) bool {
+	return err == ErrClosed
+}
@@ -31,7 +31,7 @@ Bare error returns make debugging difficult because you lose the stack context.`
 var Analyzer = &analysis.Analyzer{
 	Name:     "errorwrap",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -71,25 +71,21 @@ func checkFunction(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 		return
 	}
 
-	// Track error assignments and their positions
+	// Track error assignments and their positions, and whether the value
+	// from the latest assignment to each variable is a wrapped error. A wrap
+	// call only counts when its result is assigned; every assignment starts
+	// a new generation, so err = doOther() after wrapping is unwrapped again.
+	// Assignments are taken in source order, regardless of branches.
 	errorAssignments := make(map[string]token.Pos)
 	errorWrapped := make(map[string]bool)
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.AssignStmt:
-			checkErrorAssignment(node, errorAssignments)
+			checkErrorAssignment(node, errorAssignments, errorWrapped)
 
-		case *ast.CallExpr:
-			// Check if this is an error wrapping call
-			if isErrorWrap(node) {
-				// Mark any error variables used as wrapped
-				for _, arg := range node.Args {
-					if ident, ok := arg.(*ast.Ident); ok {
-						errorWrapped[ident.Name] = true
-					}
-				}
-			}
+		case *ast.ValueSpec:
+			checkErrorDeclaration(node, errorAssignments, errorWrapped)
 
 		case *ast.ReturnStmt:
 			checkBareErrorReturn(reporter, node, fn, errorAssignments, errorWrapped)
@@ -117,18 +113,42 @@ func returnsHumaneError(fn *ast.FuncDecl) bool {
 	return false
 }
 
-func checkErrorAssignment(assign *ast.AssignStmt, errorAssignments map[string]token.Pos) {
-	// Look for assignments like: err := someCall()
-	for _, lhs := range assign.Lhs {
+func checkErrorAssignment(assign *ast.AssignStmt, errorAssignments map[string]token.Pos, errorWrapped map[string]bool) {
+	// Look for assignments like: err := someCall() or wrapped := fmt.Errorf(...)
+	for i, lhs := range assign.Lhs {
 		ident, ok := lhs.(*ast.Ident)
 		if !ok {
 			continue
 		}
 
-		// Common error variable names
-		if ident.Name == "err" || strings.HasSuffix(ident.Name, "Err") || strings.HasSuffix(ident.Name, "Error") {
-			errorAssignments[ident.Name] = assign.Pos()
+		var rhs ast.Expr
+		if len(assign.Rhs) == len(assign.Lhs) {
+			rhs = assign.Rhs[i]
+		}
+		recordErrorValue(ident, rhs, assign.Pos(), errorAssignments, errorWrapped)
+	}
+}
+
+func checkErrorDeclaration(spec *ast.ValueSpec, errorAssignments map[string]token.Pos, errorWrapped map[string]bool) {
+	// Look for declarations like: var err = someCall()
+	for i, ident := range spec.Names {
+		var rhs ast.Expr
+		if len(spec.Values) == len(spec.Names) {
+			rhs = spec.Values[i]
 		}
+		recordErrorValue(ident, rhs, spec.Pos(), errorAssignments, errorWrapped)
+	}
+}
+
+// recordErrorValue records a new value of ident, wrapped if rhs is a wrap call
+func recordErrorValue(ident *ast.Ident, rhs ast.Expr, pos token.Pos, errorAssignments map[string]token.Pos, errorWrapped map[string]bool) {
+	call, isCall := rhs.(*ast.CallExpr)
+	wrapped := isCall && isErrorWrap(call)
+
+	// Common error variable names, or any variable holding a wrapped error
+	if wrapped || ident.Name == "err" || strings.HasSuffix(ident.Name, "Err") || strings.HasSuffix(ident.Name, "Error") {
+		errorAssignments[ident.Name] = pos
+		errorWrapped[ident.Name] = wrapped
 	}
 }
 
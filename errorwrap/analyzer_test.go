@@ -0,0 +1,19 @@
+package errorwrap_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/errorwrap"
+)
+
+func TestErrorWrapNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, errorwrap.Analyzer, "nolint")
+}
+
+func TestErrorWrapGenerations(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, errorwrap.Analyzer, "generations")
+}
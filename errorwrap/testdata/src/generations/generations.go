@@ -0,0 +1,72 @@
+package generations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sierrasoftworks/humane-errors-go"
+)
+
+// WrapAndDiscard wraps err but returns the original
+func WrapAndDiscard(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		humane.Wrap(err, "failed to open config", "check that the file exists")
+		return err // want `returning error "err" without wrapping`
+	}
+	return f.Close()
+}
+
+// WrapIntoNewVar returns the wrapped error through a variable
+func WrapIntoNewVar(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		wrapped := fmt.Errorf("open %s: %w", path, err)
+		return wrapped
+	}
+	return f.Close()
+}
+
+// WrapIntoErrVar returns the wrapped error through an err-named variable
+func WrapIntoErrVar(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		openErr := humane.Wrap(err, "failed to open config", "check that the file exists")
+		return openErr
+	}
+	return f.Close()
+}
+
+// WrapInPlace overwrites err with the wrapped error
+func WrapInPlace(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		err = fmt.Errorf("open %s: %w", path, err)
+		return err
+	}
+	return f.Close()
+}
+
+// ReassignAfterWrap wraps err, then overwrites it with a new error
+func ReassignAfterWrap(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		err = fmt.Errorf("open %s: %w", path, err)
+		fmt.Println(err)
+	}
+	err = os.Remove(path)
+	if err != nil {
+		return err // want `returning error "err" without wrapping`
+	}
+	return f.Close()
+}
+
+// DeclaredWrapped declares the wrapped error with var
+func DeclaredWrapped(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		var wrapErr = fmt.Errorf("open %s: %w", path, err)
+		return wrapErr
+	}
+	return f.Close()
+}
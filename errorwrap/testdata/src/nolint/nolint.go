@@ -0,0 +1,21 @@
+package nolint
+
+import "os"
+
+// Reported: bare error return
+func Reported(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err // want `returning error "err" without wrapping`
+	}
+	return f.Close()
+}
+
+// Suppressed inline
+func Suppressed(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err //nolint:errorwrap // callers check os.IsNotExist
+	}
+	return f.Close()
+}
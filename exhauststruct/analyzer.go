@@ -0,0 +1,227 @@
+// Package exhauststruct provides an analyzer that checks opt-in structs are
+// fully initialized wherever they are constructed.
+//
+// Checking every composite literal for missing fields is noise; most structs
+// are meant to be partially initialized. Some are not: adding a field to a
+// config or API request type and forgetting one construction site leaves it
+// silently zero. Marking those types with //golint-sl:exhaustive makes every
+// composite literal of them list all fields.
+package exhauststruct
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check structs marked //golint-sl:exhaustive are fully initialized
+
+For struct types whose doc comment contains //golint-sl:exhaustive, this
+analyzer reports composite literals that:
+1. Leave out fields, except fields marked //golint-sl:optional
+2. Use positional (unkeyed) fields, which break when fields are reordered
+
+Bad:
+    // golint-sl:exhaustive
+    type Config struct {
+        Addr    string
+        Timeout time.Duration
+        Logger  *zap.Logger //golint-sl:optional
+    }
+
+    cfg := Config{Addr: ":8080"} // Timeout silently zero
+
+Good:
+    cfg := Config{Addr: ":8080", Timeout: 5 * time.Second}`
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "exhauststruct",
+	Doc:       Doc,
+	Requires:  nolint.Requires(inspect.Analyzer),
+	Run:       run,
+	FactTypes: []analysis.Fact{(*exhaustiveFact)(nil)},
+}
+
+// Markers are matched with or without a space after //, because gofmt adds
+// one in doc comments: "golint-sl" is not a valid directive name
+const (
+	exhaustiveMarker = "golint-sl:exhaustive"
+	optionalMarker   = "golint-sl:optional"
+)
+
+// exhaustiveFact marks a struct type as exhaustive so literals in other
+// packages are checked too
+type exhaustiveFact struct {
+	Optional []string // names of fields marked optional
+}
+
+func (*exhaustiveFact) AFact() {}
+
+func (f *exhaustiveFact) String() string {
+	return "exhaustive(" + strings.Join(f.Optional, ", ") + ")"
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Find marked types in this package
+	inspect.Preorder([]ast.Node{(*ast.GenDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.GenDecl)
+		for _, spec := range decl.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			// The marker sits on the spec, or on the declaration when it
+			// holds a single type
+			doc := ts.Doc
+			if doc == nil && len(decl.Specs) == 1 {
+				doc = decl.Doc
+			}
+			if !hasMarker(doc, exhaustiveMarker) {
+				continue
+			}
+
+			obj, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+			pass.ExportObjectFact(obj, &exhaustiveFact{Optional: optionalFields(st)})
+		}
+	})
+
+	inspect.Preorder([]ast.Node{(*ast.CompositeLit)(nil)}, func(n ast.Node) {
+		checkLiteral(reporter, pass, n.(*ast.CompositeLit))
+	})
+
+	return nil, nil
+}
+
+// hasMarker checks if a comment group contains the marker line
+func hasMarker(doc *ast.CommentGroup, marker string) bool {
+	if doc == nil {
+		return false
+	}
+	// CommentGroup.Text drops directive comments, so scan the raw list
+	for _, c := range doc.List {
+		text, ok := strings.CutPrefix(c.Text, "//")
+		if !ok {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		if text == marker || strings.HasPrefix(text, marker+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// optionalFields returns the names of fields marked optional in their doc or
+// line comment
+func optionalFields(st *ast.StructType) []string {
+	var names []string
+	for _, field := range st.Fields.List {
+		if !hasMarker(field.Doc, optionalMarker) && !hasMarker(field.Comment, optionalMarker) {
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		if len(field.Names) == 0 {
+			// Embedded fields are named after their type
+			names = append(names, embeddedName(field.Type))
+		}
+	}
+	return names
+}
+
+// embeddedName returns the field name of an embedded field type
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// checkLiteral reports missing and positional fields in a literal of a
+// marked type
+func checkLiteral(reporter *nolint.Reporter, pass *analysis.Pass, lit *ast.CompositeLit) {
+	named, ok := types.Unalias(pass.TypesInfo.TypeOf(lit)).(*types.Named)
+	if !ok {
+		return
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	fact := new(exhaustiveFact)
+	if !pass.ImportObjectFact(named.Origin().Obj(), fact) {
+		return
+	}
+	typeName := named.Obj().Name()
+
+	if len(lit.Elts) > 0 {
+		if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); !keyed {
+			reporter.Reportf(lit.Pos(),
+				"%s is marked exhaustive but initialized with positional fields; use field names so reordering fields cannot swap values",
+				typeName)
+			return
+		}
+	}
+
+	set := make(map[string]bool, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				set[key.Name] = true
+			}
+		}
+	}
+	optional := make(map[string]bool, len(fact.Optional))
+	for _, name := range fact.Optional {
+		optional[name] = true
+	}
+
+	samePkg := named.Obj().Pkg() == pass.Pkg
+	var missing []string
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if set[f.Name()] || optional[f.Name()] || f.Name() == "_" {
+			continue
+		}
+		// Unexported fields of other packages cannot be set here
+		if !f.Exported() && !samePkg {
+			continue
+		}
+		missing = append(missing, f.Name())
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	reporter.Reportf(lit.Pos(),
+		"%s is marked exhaustive but this literal does not set %s; set them or mark them //golint-sl:optional",
+		typeName, strings.Join(missing, ", "))
+}
@@ -0,0 +1,14 @@
+package exhauststruct_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/exhauststruct"
+)
+
+func TestExhaustStructAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, exhauststruct.Analyzer, "a", "b")
+}
@@ -0,0 +1,80 @@
+package a
+
+import (
+	"time"
+
+	"b"
+)
+
+// Config must be fully initialized.
+//
+// golint-sl:exhaustive
+type Config struct { // want Config:"exhaustive\\(Logger, Labels\\)"
+	Addr    string
+	Timeout time.Duration
+	Retries int
+	// Logger defaults to a no-op logger.
+	//golint-sl:optional
+	Logger func(string)
+	Labels map[string]string //golint-sl:optional
+}
+
+// Options is not marked.
+type Options struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+// golint-sl:exhaustive
+type Pair[T any] struct { // want Pair:"exhaustive\\(\\)"
+	Key   string
+	Value T
+}
+
+// Good: all fields set
+func Full() Config {
+	return Config{Addr: ":8080", Timeout: time.Second, Retries: 3}
+}
+
+// Bad: Retries missing at one construction site
+func Partial() *Config {
+	return &Config{Addr: ":8080", Timeout: time.Second} // want `Config is marked exhaustive but this literal does not set Retries`
+}
+
+// Bad: empty literal
+func Empty() Config {
+	return Config{} // want `Config is marked exhaustive but this literal does not set Addr, Timeout, Retries`
+}
+
+// Bad: positional fields
+func Positional() Config {
+	return Config{":8080", time.Second, 3, nil, nil} // want `Config is marked exhaustive but initialized with positional fields`
+}
+
+// Good: unmarked struct is ignored
+func Unmarked() Options {
+	return Options{Addr: ":8080"}
+}
+
+// Bad: elided type in a slice literal
+func List() []Config {
+	return []Config{
+		{Addr: ":8080", Timeout: time.Second, Retries: 1},
+		{Addr: ":8081"}, // want `does not set Timeout, Retries`
+	}
+}
+
+// Bad: generic type instance
+func Generic() Pair[int] {
+	return Pair[int]{Key: "a"} // want `Pair is marked exhaustive but this literal does not set Value`
+}
+
+// Bad: marked type from another package; unexported fields are skipped
+func Imported() b.Request {
+	return b.Request{ID: "1"} // want `Request is marked exhaustive but this literal does not set Body`
+}
+
+// Suppressed with nolint
+func Defaults() Config {
+	return Config{Addr: ":8080"} //nolint:exhauststruct // zero values are the defaults here
+}
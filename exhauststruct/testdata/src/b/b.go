@@ -0,0 +1,10 @@
+// Package b declares marked types used from another package.
+package b
+
+// golint-sl:exhaustive
+type Request struct { // want Request:"exhaustive\\(Trace\\)"
+	ID    string
+	Body  []byte
+	Trace string //golint-sl:optional
+	seq   int
+}
@@ -5,7 +5,10 @@
 package exporteddoc
 
 import (
+	"flag"
+	"fmt"
 	"go/ast"
+	"go/token"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -31,15 +34,33 @@ Bad:
     type Service struct { ... }  // No documentation
     
     // handles requests  // Doesn't start with function name
-    func ProcessRequest(...) ...`
+    func ProcessRequest(...) ...
+
+With -strict-prefix, the first word of every doc comment on an exported
+function, method, type, constant, or variable must be the symbol name,
+optionally after "A", "An", or "The". Comments starting with "Deprecated:"
+are accepted. Reports come with a fix that prepends the name.
+
+Flags:
+    -strict-prefix  require doc comments to start with the symbol name (default false)`
+
+var strictPrefix bool
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "exporteddoc",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("exporteddoc", flag.ExitOnError)
+	fs.BoolVar(&strictPrefix, "strict-prefix", false,
+		"require doc comments of all exported symbols to start with the symbol name")
+	return *fs
+}
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
@@ -84,6 +105,9 @@ func checkFuncDoc(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 
 	// Skip methods - they're often self-explanatory
 	if fn.Recv != nil {
+		if strictPrefix && fn.Doc != nil {
+			checkStrictPrefix(reporter, fn.Doc, fn.Name.Name)
+		}
 		return
 	}
 
@@ -94,6 +118,11 @@ func checkFuncDoc(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 		return
 	}
 
+	if strictPrefix {
+		checkStrictPrefix(reporter, fn.Doc, fn.Name.Name)
+		return
+	}
+
 	// Check that doc starts with function name
 	firstLine := fn.Doc.List[0].Text
 	if !strings.HasPrefix(firstLine, "// "+fn.Name.Name) {
@@ -124,6 +153,14 @@ func checkGenDecl(reporter *nolint.Reporter, decl *ast.GenDecl) {
 				continue
 			}
 
+			if strictPrefix {
+				// A comment on a group of several types describes the group
+				if doc == s.Doc || ownsDoc(decl) {
+					checkStrictPrefix(reporter, doc, s.Name.Name)
+				}
+				continue
+			}
+
 			// Check that doc starts with type name
 			firstLine := doc.List[0].Text
 			if !strings.HasPrefix(firstLine, "// "+s.Name.Name) {
@@ -153,8 +190,75 @@ func checkGenDecl(reporter *nolint.Reporter, decl *ast.GenDecl) {
 					reporter.Reportf(name.Pos(),
 						"exported variable %s should have a documentation comment",
 						name.Name)
+					continue
+				}
+
+				// A comment on several names or a group describes all of them
+				if strictPrefix && len(s.Names) == 1 && (doc == s.Doc || ownsDoc(decl)) {
+					checkStrictPrefix(reporter, doc, name.Name)
 				}
 			}
 		}
 	}
 }
+
+// ownsDoc checks if the doc comment of decl documents a single spec
+func ownsDoc(decl *ast.GenDecl) bool {
+	return !decl.Lparen.IsValid() || len(decl.Specs) == 1
+}
+
+// articles may precede the symbol name in a doc comment
+var articles = map[string]bool{"A": true, "An": true, "The": true}
+
+// checkStrictPrefix reports doc if its first word is not name. The fix
+// prepends name to the first line of the comment.
+func checkStrictPrefix(reporter *nolint.Reporter, doc *ast.CommentGroup, name string) {
+	line, pos, ok := firstLine(doc)
+	if !ok {
+		return
+	}
+
+	words := strings.Fields(line)
+	if len(words) > 0 && articles[words[0]] {
+		words = words[1:]
+	}
+	if len(words) > 0 && (words[0] == "Deprecated:" || strings.TrimRight(words[0], ".,:;") == name) {
+		return
+	}
+
+	reporter.Report(&analysis.Diagnostic{
+		Pos:     pos,
+		Message: fmt.Sprintf("documentation for %s should start with %q", name, name),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Prepend %s to the comment", name),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     pos,
+				End:     pos,
+				NewText: []byte(name + " "),
+			}},
+		}},
+	})
+}
+
+// firstLine returns the text of the first line comment in doc that is not
+// a directive, and the position where that text starts
+func firstLine(doc *ast.CommentGroup) (string, token.Pos, bool) {
+	for _, c := range doc.List {
+		text, ok := strings.CutPrefix(c.Text, "//")
+		if !ok {
+			// Block comments are left alone
+			return "", token.NoPos, false
+		}
+		if text == "" || (text[0] != ' ' && text[0] != '\t') {
+			// Directives like //go:generate and empty lines
+			continue
+		}
+		trimmed := strings.TrimLeft(text, " \t")
+		if trimmed == "" {
+			continue
+		}
+		offset := len(c.Text) - len(trimmed)
+		return trimmed, c.Pos() + token.Pos(offset), true
+	}
+	return "", token.NoPos, false
+}
@@ -0,0 +1,24 @@
+package exporteddoc_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/exporteddoc"
+)
+
+func TestExportedDocAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, exporteddoc.Analyzer, "lenient")
+}
+
+func TestExportedDocStrictPrefix(t *testing.T) {
+	if err := exporteddoc.Analyzer.Flags.Set("strict-prefix", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = exporteddoc.Analyzer.Flags.Set("strict-prefix", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, exporteddoc.Analyzer, "strict")
+}
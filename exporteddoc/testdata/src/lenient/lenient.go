@@ -0,0 +1,17 @@
+package lenient
+
+func Undocumented() {} // want `exported function Undocumented should have a documentation comment`
+
+// does stuff // want `documentation for Stale should start with "Stale"`
+func Stale() {}
+
+// Documented does what it says.
+func Documented() {}
+
+type Config struct{} // want `exported type Config should have a documentation comment`
+
+// stale copy-paste, methods are not checked without -strict-prefix
+func (Config) Load() {}
+
+// holds the timeout, only presence is checked without -strict-prefix
+var Timeout = 5
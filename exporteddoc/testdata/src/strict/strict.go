@@ -0,0 +1,68 @@
+package strict
+
+// does stuff // want `documentation for Stale should start with "Stale"`
+func Stale() {}
+
+// Fresh does what it says.
+func Fresh() {}
+
+// A Widget is something with parts.
+type Widget struct{}
+
+// The Gadget type is checked after the article.
+type Gadget struct{}
+
+// loads the widget // want `documentation for Load should start with "Load"`
+func (Widget) Load() {}
+
+// Save writes the widget.
+func (w *Widget) Save() {}
+
+// Deprecated: use Save instead.
+func (w *Widget) Store() {}
+
+// Undocumented methods are still not required to have a comment
+func (Widget) internal() {}
+
+func (Widget) Close() {}
+
+type (
+	// holds requests // want `documentation for Request should start with "Request"`
+	Request struct{}
+
+	// Response is what comes back.
+	Response struct{}
+)
+
+// Shared comments describe a group of types and are not checked.
+type (
+	Left  struct{}
+	Right struct{}
+)
+
+// the default timeout // want `documentation for Timeout should start with "Timeout"`
+var Timeout = 5
+
+// MaxRetries bounds retries.
+const MaxRetries = 3
+
+const (
+	// number of workers // want `documentation for Workers should start with "Workers"`
+	Workers = 4
+
+	// Queue, the size of the queue.
+	Queue = 16
+)
+
+// Limits for uploads.
+const (
+	MinSize = 1
+	MaxSize = 10
+)
+
+//go:generate echo hello
+// builds things // want `documentation for Build should start with "Build"`
+func Build() {}
+
+/* Block comments are left alone. */
+func Block() {}
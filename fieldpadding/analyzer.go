@@ -0,0 +1,337 @@
+// Package fieldpadding provides an analyzer that reports structs whose field
+// order wastes memory on padding, limited to types allocated in bulk.
+//
+// Reordering fields only pays off for types that exist many times at once.
+// Instead of reporting every struct like fieldalignment, this analyzer looks
+// at slice and array elements, map values, and types marked as hot.
+package fieldpadding
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `report padding in structs that are allocated in bulk
+
+This analyzer reports structs declared in the package whose field order
+wastes at least -waste bytes of padding, when the struct is:
+1. The element type of a slice or array in the package
+2. The value type of a map in the package
+3. Marked with a // golint-sl:hot comment
+
+The report shows the current size, the optimal size, and the field order
+that reaches it. Structs without comments between their fields come with a
+fix that reorders them.
+
+Bad:
+    type Event struct {
+        Active bool
+        ID     int64
+        Flag   bool
+        Count  int32
+    } // 24 bytes
+
+    events := make([]Event, 0, n)
+
+Good:
+    type Event struct {
+        ID     int64
+        Count  int32
+        Active bool
+        Flag   bool
+    } // 16 bytes
+
+Flags:
+    -waste  minimum number of wasted bytes to report (default 8)`
+
+// hotMarker marks a struct as worth laying out tightly regardless of use
+const hotMarker = "golint-sl:hot"
+
+var minWaste int
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "fieldpadding",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("fieldpadding", flag.ExitOnError)
+	fs.IntVar(&minWaste, "waste", 8,
+		"minimum number of wasted bytes to report")
+	return *fs
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	if pass.TypesSizes == nil {
+		return nil, nil
+	}
+
+	// Why each struct is worth laying out tightly, by type name
+	reasons := make(map[*types.TypeName]string)
+	mark := func(expr ast.Expr, reason string) {
+		named, ok := pass.TypesInfo.TypeOf(expr).(*types.Named)
+		if !ok || named.Obj().Pkg() != pass.Pkg {
+			return
+		}
+		if _, ok := reasons[named.Obj()]; !ok {
+			reasons[named.Obj()] = reason
+		}
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.GenDecl)(nil),
+		(*ast.ArrayType)(nil),
+		(*ast.MapType)(nil),
+	}
+
+	var specs []*ast.TypeSpec
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			if node.Tok != token.TYPE {
+				return
+			}
+			for _, spec := range node.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if _, ok := ts.Type.(*ast.StructType); !ok || ts.TypeParams != nil {
+					continue
+				}
+				specs = append(specs, ts)
+				if hasHotMarker(ts.Doc) || (len(node.Specs) == 1 && hasHotMarker(node.Doc)) {
+					if obj, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName); ok {
+						reasons[obj] = "marked hot"
+					}
+				}
+			}
+		case *ast.ArrayType:
+			if node.Len == nil {
+				mark(node.Elt, "slice element")
+			} else {
+				mark(node.Elt, "array element")
+			}
+		case *ast.MapType:
+			mark(node.Value, "map value")
+		}
+	})
+
+	for _, ts := range specs {
+		obj, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+		if !ok {
+			continue
+		}
+		reason, ok := reasons[obj]
+		if !ok {
+			continue
+		}
+		checkStruct(pass, reporter, ts, obj, reason)
+	}
+
+	return nil, nil
+}
+
+// hasHotMarker checks if a doc comment contains the hot marker
+func hasHotMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		text, ok := strings.CutPrefix(c.Text, "//")
+		if !ok {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		if text == hotMarker || strings.HasPrefix(text, hotMarker+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// field is one named or embedded field of a struct declaration
+type field struct {
+	name  string
+	v     *types.Var
+	size  int64
+	align int64
+}
+
+// checkStruct reports ts if reordering its fields saves enough padding
+func checkStruct(pass *analysis.Pass, reporter *nolint.Reporter, ts *ast.TypeSpec, obj *types.TypeName, reason string) {
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok || st.NumFields() < 2 {
+		return
+	}
+
+	sizes := pass.TypesSizes
+	fields := make([]field, st.NumFields())
+	for i := range fields {
+		v := st.Field(i)
+		fields[i] = field{
+			name:  v.Name(),
+			v:     v,
+			size:  sizes.Sizeof(v.Type()),
+			align: sizes.Alignof(v.Type()),
+		}
+	}
+
+	optimal := optimalOrder(fields)
+	current := sizes.Sizeof(st)
+	best := sizes.Sizeof(structOf(optimal))
+	if current-best < int64(minWaste) {
+		return
+	}
+
+	names := make([]string, len(optimal))
+	for i, f := range optimal {
+		names[i] = f.name
+	}
+
+	diag := &analysis.Diagnostic{
+		Pos: ts.Name.Pos(),
+		Message: fmt.Sprintf("struct %s (%s) is %d bytes, %d with fields ordered as %s",
+			ts.Name.Name, reason, current, best, strings.Join(names, ", ")),
+	}
+	if fix, ok := reorderFix(pass, ts.Type.(*ast.StructType), optimal); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+	}
+	reporter.Report(diag)
+}
+
+// optimalOrder sorts fields like the fieldalignment pass: zero-sized fields
+// first, then by decreasing alignment and size. Ties keep declaration order.
+func optimalOrder(fields []field) []field {
+	sorted := append([]field(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.size == 0) != (b.size == 0) {
+			return a.size == 0
+		}
+		if a.align != b.align {
+			return a.align > b.align
+		}
+		return a.size > b.size
+	})
+	return sorted
+}
+
+// structOf builds a struct type with fields in the given order
+func structOf(fields []field) *types.Struct {
+	vars := make([]*types.Var, len(fields))
+	for i, f := range fields {
+		vars[i] = f.v
+	}
+	return types.NewStruct(vars, nil)
+}
+
+// reorderFix rewrites the fields of st in the given order. It gives up when
+// comments would have to move along with fields or when a line declares
+// several fields, since those cannot be moved one by one.
+func reorderFix(pass *analysis.Pass, st *ast.StructType, order []field) (analysis.SuggestedFix, bool) {
+	if st.Fields == nil || len(st.Fields.List) == 0 {
+		return analysis.SuggestedFix{}, false
+	}
+	first, last := st.Fields.List[0], st.Fields.List[len(st.Fields.List)-1]
+	if hasComments(pass, first.Pos(), last.End()) {
+		return analysis.SuggestedFix{}, false
+	}
+
+	// Only the one-field-per-line layout gofmt produces is rewritten
+	line := pass.Fset.Position(st.Fields.Opening).Line
+	byVar := make(map[*types.Var]*ast.Field)
+	for _, f := range st.Fields.List {
+		next := pass.Fset.Position(f.Pos()).Line
+		if len(f.Names) > 1 || next == line {
+			return analysis.SuggestedFix{}, false
+		}
+		line = next
+
+		var ident *ast.Ident
+		if len(f.Names) == 1 {
+			ident = f.Names[0]
+		} else {
+			ident = embeddedIdent(f.Type)
+		}
+		v, ok := pass.TypesInfo.Defs[ident].(*types.Var)
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		byVar[v] = f
+	}
+
+	file := pass.Fset.File(st.Pos())
+	src, err := pass.ReadFile(file.Name())
+	if err != nil {
+		return analysis.SuggestedFix{}, false
+	}
+	indent := strings.Repeat("\t", pass.Fset.Position(first.Pos()).Column-1)
+
+	lines := make([]string, len(order))
+	for i, f := range order {
+		node, ok := byVar[f.v]
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		lines[i] = string(src[file.Offset(node.Pos()):file.Offset(node.End())])
+	}
+
+	return analysis.SuggestedFix{
+		Message: "Reorder fields to remove padding",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     first.Pos(),
+			End:     last.End(),
+			NewText: []byte(strings.Join(lines, "\n"+indent)),
+		}},
+	}, true
+}
+
+// hasComments checks if a comment touches a line from pos to end, where it
+// would stay behind when the fields on those lines move
+func hasComments(pass *analysis.Pass, pos, end token.Pos) bool {
+	from, to := pass.Fset.Position(pos).Line, pass.Fset.Position(end).Line
+	for _, file := range pass.Files {
+		if file.Pos() > pos || file.End() < end {
+			continue
+		}
+		for _, cg := range file.Comments {
+			line := pass.Fset.Position(cg.Pos()).Line
+			if cg.End() > pos && line <= to && pass.Fset.Position(cg.End()).Line >= from {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// embeddedIdent returns the identifier naming an embedded field
+func embeddedIdent(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.StarExpr:
+		return embeddedIdent(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.IndexExpr:
+		return embeddedIdent(e.X)
+	case *ast.IndexListExpr:
+		return embeddedIdent(e.X)
+	}
+	return nil
+}
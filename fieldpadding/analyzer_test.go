@@ -0,0 +1,14 @@
+package fieldpadding_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/fieldpadding"
+)
+
+func TestFieldPaddingAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, fieldpadding.Analyzer, "a")
+}
@@ -0,0 +1,89 @@
+package a
+
+// Event is allocated in bulk as a slice element
+type Event struct { // want `struct Event \(slice element\) is 24 bytes, 16 with fields ordered as ID, Count, Active, Flag`
+	Active bool
+	ID     int64
+	Flag   bool
+	Count  int32
+}
+
+func Collect(n int) []Event {
+	return make([]Event, 0, n)
+}
+
+// Entry is a map value
+type Entry struct { // want `struct Entry \(map value\) is 24 bytes, 16 with fields ordered as Expires, Hits, Valid, Stale`
+	Valid   bool
+	Expires int64
+	Stale   bool
+	Hits    uint32
+}
+
+var cache map[string]Entry
+
+// Cell lives in a fixed-size array
+type Cell struct { // want `struct Cell \(array element\) is 24 bytes, 16 with fields ordered as Pad, Value, Set`
+	Set   bool
+	Value float64
+	Pad   [0]int64
+}
+
+type Grid [4]Cell
+
+// Sample is marked hot, but its comments stay attached, so there is no fix
+//
+// golint-sl:hot
+type Sample struct { // want `struct Sample \(marked hot\) is 32 bytes, 24 with fields ordered as Time, Value, Valid, Final`
+	// Valid is set once Value is known
+	Valid bool
+	Time  int64
+	Value int64 // nanoseconds
+	Final bool
+}
+
+// Embedded fields are reordered along with named ones
+type Span struct { // want `struct Span \(slice element\) is 40 bytes, 32 with fields ordered as Point, Open, Closed`
+	Open bool
+	Point
+	Closed bool
+}
+
+var spans []Span
+
+// Config is declared badly but only exists once
+type Config struct {
+	Debug   bool
+	Timeout int64
+	Verbose bool
+}
+
+// Node is only referenced through pointers
+type Node struct {
+	Leaf  bool
+	Value int64
+	Dirty bool
+}
+
+var nodes []*Node
+
+// Point is already tight
+type Point struct {
+	X, Y int64
+	Set  bool
+}
+
+var points []Point
+
+// Pair declares several fields per line, so there is no fix
+type Pair struct { // want `struct Pair \(slice element\) is 24 bytes, 16 with fields ordered as N, A, B, C`
+	A, B bool
+	N    int64
+	C    bool
+}
+
+var pairs []Pair
+
+func use() (map[string]Entry, []Span, []*Node, []Point, []Pair) {
+	return cache, spans, nodes, points, pairs
+}
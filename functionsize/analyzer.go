@@ -5,7 +5,9 @@
 package functionsize
 
 import (
+	"flag"
 	"go/ast"
+	"go/token"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -20,6 +22,11 @@ const Doc = `enforce function size limits with refactoring advice
 Functions should be small and focused. This analyzer flags functions that are
 too long and provides specific advice on how to refactor them.
 
+Length is measured in lines of code: comment-only and blank lines in the body
+are not counted, so documenting a function does not push it over the limit.
+The number of statements is a second signal, catching dense or generated
+functions that fit on few lines.
+
 Guidelines:
 - Ideal: 10-30 lines
 - Acceptable: 30-80 lines  
@@ -30,12 +37,21 @@ Long functions often indicate:
 1. Multiple responsibilities (extract into separate functions)
 2. Deep nesting (use early returns)
 3. Repeated patterns (extract helper functions)
-4. Complex conditionals (use strategy pattern or lookup tables)`
+4. Complex conditionals (use strategy pattern or lookup tables)
+
+Flags:
+    -warn                     lines to trigger a warning (default 80)
+    -error                    lines to trigger an error (default 120)
+    -extended-warn            warning threshold for Init/Setup/Load/... functions (default 120)
+    -extended-error           error threshold for Init/Setup/Load/... functions (default 180)
+    -max-statements           statements to trigger a warning (default 100)
+    -extended-max-statements  statement threshold for Init/Setup/Load/... functions (default 150)`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "functionsize",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -46,8 +62,36 @@ const (
 	// Extended thresholds for functions that are expected to be longer
 	extendedWarnThreshold  = 120
 	extendedErrorThreshold = 180
+
+	// Statements to trigger a warning, regardless of how many lines they span
+	statementThreshold         = 100
+	extendedStatementThreshold = 150
 )
 
+// Configured thresholds, defaulting to the constants above
+var (
+	warnLines          int
+	errorLines         int
+	extendedWarnLines  int
+	extendedErrorLines int
+	maxStatements      int
+	extendedStatements int
+)
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("functionsize", flag.ExitOnError)
+	fs.IntVar(&warnLines, "warn", warnThreshold, "lines to trigger a warning")
+	fs.IntVar(&errorLines, "error", errorThreshold, "lines to trigger an error")
+	fs.IntVar(&extendedWarnLines, "extended-warn", extendedWarnThreshold,
+		"warning threshold for functions expected to be longer (Init, Setup, Load, ...)")
+	fs.IntVar(&extendedErrorLines, "extended-error", extendedErrorThreshold,
+		"error threshold for functions expected to be longer (Init, Setup, Load, ...)")
+	fs.IntVar(&maxStatements, "max-statements", statementThreshold, "statements to trigger a warning")
+	fs.IntVar(&extendedStatements, "extended-max-statements", extendedStatementThreshold,
+		"statement threshold for functions expected to be longer (Init, Setup, Load, ...)")
+	return *fs
+}
+
 // exemptFuncPrefixes are function name prefixes that are allowed to be longer
 // These functions often require setup of multiple related components
 var exemptFuncPrefixes = []string{
@@ -89,40 +133,89 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
-		// Calculate function length
+		// Calculate function length, with and without comments and blank lines
 		startLine := pass.Fset.Position(fn.Body.Lbrace).Line
 		endLine := pass.Fset.Position(fn.Body.Rbrace).Line
-		lines := endLine - startLine + 1
+		rawLines := endLine - startLine + 1
+		lines := codeLines(pass.Fset, fn.Body)
+		statements := countStatements(fn.Body)
 
 		// Determine thresholds based on function name
-		warnLimit := warnThreshold
-		errorLimit := errorThreshold
+		warnLimit := warnLines
+		errorLimit := errorLines
+		statementLimit := maxStatements
 		if isExemptFunction(fn.Name.Name) {
-			warnLimit = extendedWarnThreshold
-			errorLimit = extendedErrorThreshold
+			warnLimit = extendedWarnLines
+			errorLimit = extendedErrorLines
+			statementLimit = extendedStatements
 		}
 
-		if lines < warnLimit {
+		if lines < warnLimit && statements < statementLimit {
 			return
 		}
 
 		// Analyze function to provide specific advice
 		advice := analyzeFunction(fn)
 
-		if lines >= errorLimit {
+		switch {
+		case lines >= errorLimit:
+			reporter.Reportf(fn.Pos(),
+				"function %s is %d lines of code, %d with comments and blank lines (max %d); %s",
+				fn.Name.Name, lines, rawLines, errorLimit, advice)
+		case lines >= warnLimit:
 			reporter.Reportf(fn.Pos(),
-				"function %s is %d lines (max %d); %s",
-				fn.Name.Name, lines, errorLimit, advice)
-		} else if lines >= warnLimit {
+				"function %s is %d lines of code, %d with comments and blank lines (recommended max %d); %s",
+				fn.Name.Name, lines, rawLines, warnLimit, advice)
+		default:
 			reporter.Reportf(fn.Pos(),
-				"function %s is %d lines (recommended max %d); %s",
-				fn.Name.Name, lines, warnLimit, advice)
+				"function %s has %d statements in %d lines of code (recommended max %d statements); %s",
+				fn.Name.Name, statements, lines, statementLimit, advice)
 		}
 	})
 
 	return nil, nil
 }
 
+// codeLines counts the lines of a function body holding code, from the opening
+// to the closing brace. Comments are not part of the syntax tree, so lines only
+// holding comments or nothing at all are never marked.
+func codeLines(fset *token.FileSet, body *ast.BlockStmt) int {
+	lines := make(map[int]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		start := fset.Position(n.Pos()).Line
+		end := fset.Position(n.End() - 1).Line
+		lines[start] = true
+		lines[end] = true
+
+		// Multi-line raw strings are code on every line
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			for line := start; line <= end; line++ {
+				lines[line] = true
+			}
+		}
+		return true
+	})
+	return len(lines)
+}
+
+// countStatements counts the statements of a function body, including those
+// of nested function literals. Blocks are not statements of their own.
+func countStatements(body *ast.BlockStmt) int {
+	count := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.EmptyStmt:
+		case ast.Stmt:
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 // isExemptFunction checks if a function name should use extended thresholds
 func isExemptFunction(name string) bool {
 	// Check exact name matches
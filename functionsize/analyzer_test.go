@@ -0,0 +1,14 @@
+package functionsize_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/functionsize"
+)
+
+func TestFunctionSizeAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, functionsize.Analyzer, "a")
+}
@@ -0,0 +1,413 @@
+package a
+
+func work(n int) int { return n + 1 }
+
+func do(fns ...func()) {
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// Good: heavily documented, well under the limit once comments are skipped
+func documented(n int) int {
+	// step 0 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 1 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 2 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 3 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 4 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 5 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 6 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 7 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 8 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 9 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 10 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 11 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 12 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 13 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 14 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 15 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 16 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 17 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 18 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 19 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 20 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 21 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 22 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 23 explains why the value is adjusted here,
+	// so the next reader does not have to guess
+	n = work(n)
+
+	// step 24 explains why the value is adjusted here,
+	return n
+}
+
+// Bad: 85 lines of code without a single comment
+func dense(n int) int { // want `function dense is 85 lines of code, 85 with comments and blank lines \(recommended max 80\)`
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	return n
+}
+
+// Bad: few lines, but more statements than the limit
+func packed(n int) int { // want `function packed has 121 statements in 23 lines of code \(recommended max 100 statements\)`
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	do(func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ }, func() { n++ })
+	return n
+}
+
+// Good: setup functions get the extended thresholds
+func setupEverything(n int) int {
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	return n
+}
+
+// Suppressed with nolint
+
+//nolint:functionsize // a flat table of steps reads best in one place
+func steps(n int) int {
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	n = work(n)
+	return n
+}
@@ -10,6 +10,8 @@ package goroutineleak
 
 import (
 	"go/ast"
+	"go/constant"
+	"go/token"
 	"go/types"
 	"strings"
 
@@ -28,9 +30,17 @@ This analyzer detects patterns that commonly cause goroutine leaks:
 2. Goroutines without WaitGroup or done channel for synchronization
 3. Goroutines spawned in loops without proper lifecycle management
 4. Channel sends/receives without select and context
+5. Goroutines started on every loop iteration without a concurrency bound:
+   a semaphore acquired before the go statement, errgroup.SetLimit, a
+   worker pool reading from a channel, a WaitGroup waited on inside the
+   loop, or a small constant number of iterations
 
 Goroutine leaks cause memory growth over time and can exhaust system resources.
 
+Function literals and direct calls to functions and methods of the same
+package, like go s.worker(ctx), are checked. Passing the parent context to
+the started function counts as a way to stop it.
+
 Good patterns:
     // With context cancellation
     go func() {
@@ -62,7 +72,7 @@ Bad patterns:
 var Analyzer = &analysis.Analyzer{
 	Name:     "goroutineleak",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -75,22 +85,264 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.FuncDecl)(nil),
 	}
 
+	// Collect the functions and methods declared in this package, so that
+	// go worker(ctx) and go s.run() can be checked like go func() { ... }()
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok && fn.Body != nil {
+			decls[obj] = fn
+		}
+	})
+
 	// Track if we're in a function that accepts context
 	var currentFuncHasContext bool
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
 		switch node := n.(type) {
 		case *ast.FuncDecl:
 			currentFuncHasContext = hasContextParam(node)
 
 		case *ast.GoStmt:
-			checkGoroutine(reporter, node, currentFuncHasContext)
+			checkGoroutine(reporter, pass, node, currentFuncHasContext, decls)
+			checkLoopSpawn(reporter, pass, node, stack, decls)
 		}
+		return true
 	})
 
 	return nil, nil
 }
 
+// smallLoopBound is the largest constant iteration count of a loop that may
+// start a goroutine per iteration without further bounds
+const smallLoopBound = 10
+
+// checkLoopSpawn reports goroutines started on every iteration of a loop
+// with nothing limiting how many run at once
+func checkLoopSpawn(reporter *nolint.Reporter, pass *analysis.Pass, goStmt *ast.GoStmt, stack []ast.Node, decls map[*types.Func]*ast.FuncDecl) {
+	loops, fnBody := enclosingLoops(stack)
+	if len(loops) == 0 {
+		return
+	}
+	loop := loops[0]
+
+	if hasSmallConstantBound(pass, loop) ||
+		acquiresBefore(pass, loopBody(loop), goStmt) ||
+		isWorkerPool(pass, loop, goStmt, decls) ||
+		callsMethod(pass, fnBody, "SetLimit", "golang.org/x/sync/errgroup") {
+		return
+	}
+	for _, l := range loops {
+		if callsMethod(pass, loopBody(l), "Wait", "sync") {
+			return
+		}
+	}
+
+	reporter.Reportf(goStmt.Pos(),
+		"goroutine spawned per loop iteration without concurrency bound; use errgroup.SetLimit or a worker pool")
+}
+
+// enclosingLoops returns the loops around the top of stack within the same
+// function, innermost first, and the body of that function
+func enclosingLoops(stack []ast.Node) ([]ast.Stmt, *ast.BlockStmt) {
+	var loops []ast.Stmt
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.ForStmt:
+			loops = append(loops, node)
+		case *ast.RangeStmt:
+			loops = append(loops, node)
+		case *ast.FuncLit:
+			return loops, node.Body
+		case *ast.FuncDecl:
+			return loops, node.Body
+		}
+	}
+	return nil, nil
+}
+
+// loopBody returns the body of a for or range loop
+func loopBody(loop ast.Stmt) *ast.BlockStmt {
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		return l.Body
+	case *ast.RangeStmt:
+		return l.Body
+	}
+	return nil
+}
+
+// hasSmallConstantBound checks for loops like for i := 0; i < 3; i++ and
+// for range 3
+func hasSmallConstantBound(pass *analysis.Pass, loop ast.Stmt) bool {
+	var bound ast.Expr
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		cond, ok := l.Cond.(*ast.BinaryExpr)
+		if !ok || (cond.Op != token.LSS && cond.Op != token.LEQ) {
+			return false
+		}
+		bound = cond.Y
+	case *ast.RangeStmt:
+		bound = l.X
+	}
+
+	tv, ok := pass.TypesInfo.Types[bound]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return false
+	}
+	n, exact := constant.Int64Val(tv.Value)
+	return exact && n <= smallLoopBound
+}
+
+// acquiresBefore checks if body acquires a semaphore before goStmt, either
+// by sending to a buffered channel or with semaphore.Weighted.Acquire
+func acquiresBefore(pass *analysis.Pass, body *ast.BlockStmt, goStmt *ast.GoStmt) bool {
+	acquires := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= goStmt.Pos() {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.SendStmt:
+			acquires = true
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && (sel.Sel.Name == "Acquire" || sel.Sel.Name == "TryAcquire") {
+				acquires = true
+			}
+		}
+		return !acquires
+	})
+	return acquires
+}
+
+// isWorkerPool checks for a fixed number of goroutines started by a counted
+// loop that each read their work from a channel:
+//
+//	for i := 0; i < workers; i++ {
+//	    go func() {
+//	        for job := range jobs { ... }
+//	    }()
+//	}
+func isWorkerPool(pass *analysis.Pass, loop ast.Stmt, goStmt *ast.GoStmt, decls map[*types.Func]*ast.FuncDecl) bool {
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		cond, ok := l.Cond.(*ast.BinaryExpr)
+		if !ok || (cond.Op != token.LSS && cond.Op != token.LEQ) || callsLen(cond.Y) {
+			return false
+		}
+	case *ast.RangeStmt:
+		if !isInteger(pass.TypesInfo.TypeOf(l.X)) {
+			return false
+		}
+	}
+
+	body := goroutineBody(pass, goStmt, decls)
+	if body == nil {
+		return false
+	}
+	receives := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.UnaryExpr:
+			receives = receives || node.Op == token.ARROW
+		case *ast.RangeStmt:
+			_, isChan := pass.TypesInfo.TypeOf(node.X).Underlying().(*types.Chan)
+			receives = receives || isChan
+		}
+		return !receives
+	})
+	return receives
+}
+
+// callsLen checks if expr is a len(...) call, the bound of a loop over data
+func callsLen(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "len"
+}
+
+func isInteger(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// callsMethod checks if node calls the method name of a type from pkgPath,
+// like wg.Wait() on a sync.WaitGroup
+func callsMethod(pass *analysis.Pass, node ast.Node, name, pkgPath string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != name {
+			return true
+		}
+		fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+		if ok && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// goroutineBody returns the body the go statement runs: the function literal,
+// or the declaration of a function or method of this package called directly
+func goroutineBody(pass *analysis.Pass, goStmt *ast.GoStmt, decls map[*types.Func]*ast.FuncDecl) *ast.BlockStmt {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(goStmt.Call.Fun).(type) {
+	case *ast.FuncLit:
+		return fun.Body
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+
+	obj, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return nil
+	}
+	if decl, ok := decls[obj.Origin()]; ok {
+		return decl.Body
+	}
+	return nil
+}
+
+// passesContext checks if the go statement hands a context.Context to the
+// function it starts, which can then stop when the context is cancelled
+func passesContext(pass *analysis.Pass, goStmt *ast.GoStmt) bool {
+	for _, arg := range goStmt.Call.Args {
+		if isContextType(pass.TypesInfo.TypeOf(arg)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
 func hasContextParam(fn *ast.FuncDecl) bool {
 	if fn.Type.Params == nil {
 		return false
@@ -105,18 +357,11 @@ func hasContextParam(fn *ast.FuncDecl) bool {
 	return false
 }
 
-func checkGoroutine(reporter *nolint.Reporter, goStmt *ast.GoStmt, parentHasContext bool) {
-	// Get the function being called in the go statement
-	var funcLit *ast.FuncLit
-	switch call := goStmt.Call.Fun.(type) {
-	case *ast.FuncLit:
-		funcLit = call
-	default:
-		// go someFunc() - harder to analyze, skip for now
-		return
-	}
-
-	if funcLit == nil || funcLit.Body == nil {
+func checkGoroutine(reporter *nolint.Reporter, pass *analysis.Pass, goStmt *ast.GoStmt, parentHasContext bool, decls map[*types.Func]*ast.FuncDecl) {
+	// Get the body of the function being called in the go statement.
+	// Functions from other packages can't be analyzed.
+	body := goroutineBody(pass, goStmt, decls)
+	if body == nil {
 		return
 	}
 
@@ -126,7 +371,7 @@ func checkGoroutine(reporter *nolint.Reporter, goStmt *ast.GoStmt, parentHasCont
 	hasDoneChannel := false
 	hasInfiniteLoop := false
 
-	ast.Inspect(funcLit.Body, func(n ast.Node) bool {
+	ast.Inspect(body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.SelectStmt:
 			// Check if select has ctx.Done() case
@@ -172,7 +417,9 @@ func checkGoroutine(reporter *nolint.Reporter, goStmt *ast.GoStmt, parentHasCont
 			"goroutine with infinite loop has no way to stop; add select with <-ctx.Done() or done channel")
 	}
 
-	if !hasContextCheck && !hasWaitGroupDone && !hasDoneChannel && parentHasContext {
+	// A function that is handed the parent context can be cancelled through
+	// it, even if the loop check above finds it never does
+	if !hasContextCheck && !hasWaitGroupDone && !hasDoneChannel && parentHasContext && !passesContext(pass, goStmt) {
 		reporter.Reportf(goStmt.Pos(),
 			"goroutine spawned without cleanup mechanism; consider passing context and checking ctx.Done(), or use sync.WaitGroup")
 	}
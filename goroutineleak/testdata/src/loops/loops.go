@@ -0,0 +1,133 @@
+package loops
+
+import (
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type Item struct{ ID string }
+
+func process(Item) {}
+
+func Unbounded(items []Item) {
+	for _, item := range items {
+		go process(item) // want `goroutine spawned per loop iteration without concurrency bound`
+	}
+}
+
+func UnboundedCounted(items []Item) {
+	for i := 0; i < len(items); i++ {
+		go func() { // want `goroutine spawned per loop iteration without concurrency bound`
+			process(items[i])
+		}()
+	}
+}
+
+func WaitGroupOutsideLoop(items []Item) {
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() { // want `goroutine spawned per loop iteration without concurrency bound`
+			defer wg.Done()
+			process(item)
+		}()
+	}
+	wg.Wait()
+}
+
+func Semaphore(items []Item) {
+	sem := make(chan struct{}, 8)
+	for _, item := range items {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			process(item)
+		}()
+	}
+}
+
+func ErrgroupLimit(items []Item) {
+	var g errgroup.Group
+	g.SetLimit(8)
+	done := make(chan struct{})
+	for _, item := range items {
+		go func() {
+			process(item)
+			done <- struct{}{}
+		}()
+	}
+	_ = g.Wait()
+}
+
+func WorkerPool(items []Item, workers int) {
+	jobs := make(chan Item)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for item := range jobs {
+				process(item)
+			}
+		}()
+	}
+	for range workers {
+		go worker(jobs)
+	}
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+}
+
+func worker(jobs chan Item) {
+	for item := range jobs {
+		process(item)
+	}
+}
+
+func CountedWithoutChannel(workers int) {
+	for i := 0; i < workers; i++ {
+		go process(Item{}) // want `goroutine spawned per loop iteration without concurrency bound`
+	}
+}
+
+func Batches(batches [][]Item) {
+	for _, batch := range batches {
+		var wg sync.WaitGroup
+		for _, item := range batch {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				process(item)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func SmallConstant() {
+	for i := 0; i < 3; i++ {
+		go process(Item{})
+	}
+	for range 4 {
+		go process(Item{})
+	}
+}
+
+func LargeConstant() {
+	for i := 0; i < 1000; i++ {
+		go process(Item{}) // want `goroutine spawned per loop iteration without concurrency bound`
+	}
+}
+
+func NotInLoop(item Item) {
+	go process(item)
+}
+
+func ClosureInLoop(items []Item) {
+	for _, item := range items {
+		run := func() {
+			go process(item)
+		}
+		run()
+	}
+}
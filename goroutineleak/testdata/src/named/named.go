@@ -0,0 +1,87 @@
+package named
+
+import (
+	"context"
+	"sync"
+)
+
+type Job struct{ ID string }
+
+type Server struct {
+	jobs chan Job
+	wg   sync.WaitGroup
+}
+
+func process(Job) {}
+
+// worker loops forever and never looks at its context
+func worker(ctx context.Context, jobs chan Job) {
+	for {
+		process(<-jobs)
+	}
+}
+
+// cancellableWorker stops when its context is cancelled
+func cancellableWorker(ctx context.Context, jobs chan Job) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-jobs:
+			process(job)
+		}
+	}
+}
+
+func drain(jobs chan Job) {
+	for job := range jobs {
+		process(job)
+	}
+}
+
+func (s *Server) loop() {
+	for {
+		process(<-s.jobs)
+	}
+}
+
+func (s *Server) run(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.jobs:
+			process(job)
+		}
+	}
+}
+
+func StartWorker(ctx context.Context, jobs chan Job) {
+	go worker(ctx, jobs) // want `goroutine with infinite loop has no way to stop`
+}
+
+func StartCancellableWorker(ctx context.Context, jobs chan Job) {
+	go cancellableWorker(ctx, jobs)
+}
+
+func StartDrain(ctx context.Context, jobs chan Job) {
+	go drain(jobs) // want `goroutine spawned without cleanup mechanism`
+}
+
+func StartDrainWithoutContext(jobs chan Job) {
+	go drain(jobs)
+}
+
+func (s *Server) Start(ctx context.Context) {
+	go s.loop() // want `goroutine with infinite loop has no way to stop` `goroutine spawned without cleanup mechanism`
+}
+
+func (s *Server) StartRun(ctx context.Context) {
+	s.wg.Add(1)
+	go s.run(ctx)
+}
+
+func StartExternal(ctx context.Context, wg *sync.WaitGroup) {
+	go wg.Wait()
+}
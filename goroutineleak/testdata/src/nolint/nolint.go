@@ -0,0 +1,21 @@
+package nolint
+
+func work() {}
+
+// Reported: infinite loop without a stop signal
+func Reported() {
+	go func() { // want `goroutine with infinite loop has no way to stop`
+		for {
+			work()
+		}
+	}()
+}
+
+// Suppressed inline
+func Suppressed() {
+	go func() { //nolint:goroutineleak // lives for the whole process
+		for {
+			work()
+		}
+	}()
+}
@@ -0,0 +1,324 @@
+// Package gracedrain provides an analyzer that enforces graceful http.Server
+// shutdown.
+//
+// srv.Close() drops every in-flight request, Shutdown(context.Background())
+// waits forever for slow clients and hangs deploys, and Shutdown does not wait
+// for hijacked connections such as WebSockets at all.
+package gracedrain
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `enforce graceful http.Server shutdown
+
+This analyzer detects:
+1. srv.Close() without a preceding Shutdown (drops in-flight requests)
+2. srv.Shutdown(context.Background()) (never times out; hangs deploys)
+3. Shutdown errors that are ignored
+4. Packages that hijack connections (WebSocket upgraders, http.Hijacker)
+   and call Shutdown without RegisterOnShutdown to close them; Shutdown
+   does not wait for hijacked connections
+5. http.Server literals without ReadHeaderTimeout or ReadTimeout (slowloris)
+
+Bad:
+    srv := &http.Server{Addr: ":8080", Handler: mux}
+    ...
+    srv.Shutdown(context.Background())
+
+Good:
+    srv := &http.Server{Addr: ":8080", Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+    ...
+    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+    if err := srv.Shutdown(ctx); err != nil {
+        return fmt.Errorf("shutdown: %w", err)
+    }`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "gracedrain",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// unboundedContexts are context constructors that never expire
+var unboundedContexts = map[string]bool{
+	"Background": true,
+	"TODO":       true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CompositeLit)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	// Package-wide facts for the hijacked connection check
+	var shutdowns []*ast.CallExpr
+	hijacks := false
+	registersOnShutdown := false
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			checkServerLiteral(reporter, pass, node)
+
+		case *ast.FuncDecl:
+			if node.Body != nil {
+				checkFunc(reporter, pass, node.Body)
+			}
+
+		case *ast.FuncLit:
+			checkFunc(reporter, pass, node.Body)
+
+		case *ast.CallExpr:
+			switch serverMethod(pass, node) {
+			case "Shutdown":
+				shutdowns = append(shutdowns, node)
+			case "RegisterOnShutdown":
+				registersOnShutdown = true
+			}
+			if isHijack(pass, node) {
+				hijacks = true
+			}
+		}
+	})
+
+	if hijacks && !registersOnShutdown {
+		for _, call := range shutdowns {
+			reporter.Reportf(call.Pos(),
+				"Shutdown does not wait for hijacked connections (WebSockets, streams) in this package; "+
+					"close them via srv.RegisterOnShutdown")
+		}
+	}
+
+	return nil, nil
+}
+
+// isServerType checks if t is net/http.Server or a pointer to it
+func isServerType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == "Server"
+}
+
+// serverMethod returns the name of the http.Server method called, or ""
+func serverMethod(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if !isServerType(pass.TypesInfo.TypeOf(sel.X)) {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// checkServerLiteral flags http.Server{} without read timeouts
+func checkServerLiteral(reporter *nolint.Reporter, pass *analysis.Pass, lit *ast.CompositeLit) {
+	if !isServerType(pass.TypesInfo.TypeOf(lit)) {
+		return
+	}
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if key.Name == "ReadHeaderTimeout" || key.Name == "ReadTimeout" {
+			return
+		}
+	}
+
+	reporter.Reportf(lit.Pos(),
+		"http.Server without ReadHeaderTimeout or ReadTimeout is open to slowloris attacks; set ReadHeaderTimeout (e.g., 10*time.Second)")
+}
+
+// checkFunc checks the Close and Shutdown calls in one function body
+func checkFunc(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var closes []*ast.CallExpr
+	hasShutdown := false
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Analyzed separately
+			return false
+
+		case *ast.ExprStmt:
+			if call, ok := node.X.(*ast.CallExpr); ok && serverMethod(pass, call) == "Shutdown" {
+				reportIgnoredError(reporter, call)
+			}
+
+		case *ast.DeferStmt:
+			if serverMethod(pass, node.Call) == "Shutdown" {
+				reportIgnoredError(reporter, node.Call)
+			}
+
+		case *ast.GoStmt:
+			if serverMethod(pass, node.Call) == "Shutdown" {
+				reportIgnoredError(reporter, node.Call)
+			}
+
+		case *ast.AssignStmt:
+			if len(node.Lhs) == 1 && len(node.Rhs) == 1 && isBlank(node.Lhs[0]) {
+				if call, ok := node.Rhs[0].(*ast.CallExpr); ok && serverMethod(pass, call) == "Shutdown" {
+					reportIgnoredError(reporter, call)
+				}
+			}
+
+		case *ast.CallExpr:
+			switch serverMethod(pass, node) {
+			case "Close":
+				closes = append(closes, node)
+			case "Shutdown":
+				hasShutdown = true
+				if len(node.Args) == 1 {
+					checkShutdownContext(reporter, pass, body, node.Args[0])
+				}
+			}
+		}
+		return true
+	})
+
+	// Close as the fallback after a failed Shutdown is the documented pattern
+	if hasShutdown {
+		return
+	}
+	for _, call := range closes {
+		reporter.Reportf(call.Pos(),
+			"http.Server.Close drops in-flight requests; use Shutdown with a timeout context and fall back to Close only if it fails")
+	}
+}
+
+func reportIgnoredError(reporter *nolint.Reporter, call *ast.CallExpr) {
+	reporter.Reportf(call.Pos(),
+		"Shutdown error is ignored; a timed-out shutdown means requests were dropped, log or return it")
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// unboundedContext returns "context.Background" or "context.TODO" if expr
+// calls one of them
+func unboundedContext(pass *analysis.Pass, expr ast.Expr) string {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "context" || !unboundedContexts[fn.Name()] {
+		return ""
+	}
+	return "context." + fn.Name()
+}
+
+// checkShutdownContext flags Shutdown contexts that can never time out
+func checkShutdownContext(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt, arg ast.Expr) {
+	name := unboundedContext(pass, arg)
+
+	// ctx := context.Background(); srv.Shutdown(ctx)
+	if ident, ok := ast.Unparen(arg).(*ast.Ident); ok && name == "" {
+		name = assignedUnbounded(pass, body, pass.TypesInfo.Uses[ident])
+	}
+
+	if name == "" {
+		return
+	}
+
+	reporter.Reportf(arg.Pos(),
+		"Shutdown with %s() never times out and can hang deploys; use context.WithTimeout", name)
+}
+
+// assignedUnbounded checks if every assignment to obj in body is an
+// unbounded context, returning its name
+func assignedUnbounded(pass *analysis.Pass, body *ast.BlockStmt, obj types.Object) string {
+	if obj == nil {
+		return ""
+	}
+
+	name := ""
+	bounded := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok || pass.TypesInfo.ObjectOf(ident) != obj {
+				continue
+			}
+			if len(assign.Lhs) != len(assign.Rhs) {
+				// ctx, cancel := context.WithTimeout(...)
+				bounded = true
+				continue
+			}
+			if u := unboundedContext(pass, assign.Rhs[i]); u != "" {
+				name = u
+			} else {
+				bounded = true
+			}
+		}
+		return true
+	})
+
+	if bounded {
+		return ""
+	}
+	return name
+}
+
+// isHijack checks for connection hijacking: http.Hijacker.Hijack or a
+// WebSocket Upgrader's Upgrade method
+func isHijack(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+
+	switch fn.Name() {
+	case "Hijack":
+		return fn.Pkg().Path() == "net/http"
+	case "Upgrade", "Accept":
+		return strings.Contains(fn.Pkg().Path(), "websocket")
+	}
+	return false
+}
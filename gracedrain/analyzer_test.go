@@ -0,0 +1,14 @@
+package gracedrain_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/gracedrain"
+)
+
+func TestGraceDrainAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gracedrain.Analyzer, "a", "stream")
+}
@@ -0,0 +1,64 @@
+package a
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Bad: no read timeouts
+func NewBareServer(h http.Handler) *http.Server {
+	return &http.Server{Addr: ":8080", Handler: h} // want `http.Server without ReadHeaderTimeout or ReadTimeout`
+}
+
+// Good: bounded header read
+func NewServer(h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":8080",
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// Bad: Close instead of Shutdown
+func StopHard(srv *http.Server) error {
+	return srv.Close() // want `http.Server.Close drops in-flight requests`
+}
+
+// Bad: Background context never times out
+func StopForever(srv *http.Server) error {
+	return srv.Shutdown(context.Background()) // want `Shutdown with context.Background\(\) never times out`
+}
+
+// Bad: Background context through a variable
+func StopForeverVar(srv *http.Server) error {
+	ctx := context.TODO()
+	return srv.Shutdown(ctx) // want `Shutdown with context.TODO\(\) never times out`
+}
+
+// Bad: error ignored
+func StopQuietly(srv *http.Server, ctx context.Context) {
+	srv.Shutdown(ctx) // want `Shutdown error is ignored`
+}
+
+func StopBlank(srv *http.Server, ctx context.Context) {
+	_ = srv.Shutdown(ctx) // want `Shutdown error is ignored`
+}
+
+// Good: bounded Shutdown with Close as fallback
+func Stop(srv *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("graceful shutdown failed: %v", err)
+		return srv.Close()
+	}
+	return nil
+}
+
+// Good: caller-provided context
+func StopWith(ctx context.Context, srv *http.Server) error {
+	return srv.Shutdown(ctx)
+}
@@ -0,0 +1,13 @@
+package websocket
+
+import "net/http"
+
+type Conn struct{}
+
+func (c *Conn) Close() error { return nil }
+
+type Upgrader struct{}
+
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, h http.Header) (*Conn, error) {
+	return &Conn{}, nil
+}
@@ -0,0 +1,611 @@
+// Package grpcinterceptors provides an analyzer that checks gRPC servers are
+// built with the interceptors every service needs.
+//
+// A gRPC server without a recovery interceptor dies on the first panic in a
+// handler, one without OpenTelemetry instrumentation is invisible in traces,
+// and one without the auth interceptor serves every caller. Interceptors in
+// the wrong order are almost as bad: recovery inside auth does not catch
+// panics raised by auth.
+package grpcinterceptors
+
+import (
+	"flag"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check gRPC servers include recovery, telemetry, and auth interceptors in order
+
+For every grpc.NewServer call outside tests, this analyzer reconstructs the
+server options, following option variables, append calls, slices spread
+with opts..., and helper functions of the same package. It reports:
+1. grpc.NewServer called without any options
+2. Interceptor classes from -required that no option installs
+3. Interceptors that run before a class listed earlier in -order, such as
+   recovery inside auth
+4. Servers without grpc.MaxRecvMsgSize in packages that handle uploads
+
+Interceptors are classified by the qualified name of the function that
+creates them, like github.com/grpc-ecosystem/go-grpc-middleware/v2/
+interceptors/recovery.UnaryServerInterceptor. A grpc.StatsHandler counts
+for its class too, so otelgrpc.NewServerHandler() satisfies otel. When an
+option cannot be resolved, for example because it is a parameter, missing
+classes are not reported.
+
+Bad:
+    srv := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(
+            auth.UnaryServerInterceptor(authFn),
+            recovery.UnaryServerInterceptor(), // does not recover auth panics
+        ),
+    )
+
+Good:
+    srv := grpc.NewServer(
+        grpc.StatsHandler(otelgrpc.NewServerHandler()),
+        grpc.ChainUnaryInterceptor(
+            recovery.UnaryServerInterceptor(),
+            auth.UnaryServerInterceptor(authFn),
+        ),
+    )
+
+Flags:
+    -classes   comma-separated class=glob pairs; globs match qualified
+               function names case-insensitively, * matches anything
+               (default "recovery=*recovery*,otel=*otelgrpc*,auth=*auth*")
+    -required  comma-separated classes every server must install
+               (default "recovery,otel,auth")
+    -order     comma-separated classes, outermost first
+               (default "recovery,otel,auth")
+    -uploads   glob for declared names that mark a package as handling
+               uploads; empty disables the check (default "*upload*")`
+
+var (
+	classes  string
+	required string
+	order    string
+	uploads  string
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "grpcinterceptors",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("grpcinterceptors", flag.ExitOnError)
+	fs.StringVar(&classes, "classes", "recovery=*recovery*,otel=*otelgrpc*,auth=*auth*",
+		"comma-separated class=glob pairs matched against qualified interceptor function names")
+	fs.StringVar(&required, "required", "recovery,otel,auth",
+		"comma-separated interceptor classes every server must install")
+	fs.StringVar(&order, "order", "recovery,otel,auth",
+		"comma-separated interceptor classes, outermost first")
+	fs.StringVar(&uploads, "uploads", "*upload*",
+		"glob for declared names that mark a package as handling uploads; empty disables the check")
+	return *fs
+}
+
+const grpcPackage = "google.golang.org/grpc"
+
+// classPattern is one glob of an interceptor class
+type classPattern struct {
+	class string
+	re    *regexp.Regexp
+}
+
+// settings holds the parsed flags for one run
+type settings struct {
+	patterns []classPattern
+	required []string
+	rank     map[string]int // position in -order
+}
+
+func parseSettings() settings {
+	s := settings{rank: make(map[string]int)}
+
+	for _, pair := range splitList(classes) {
+		class, glob, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		s.patterns = append(s.patterns, classPattern{
+			class: strings.TrimSpace(class),
+			re:    globRegexp(strings.TrimSpace(glob)),
+		})
+	}
+	s.required = splitList(required)
+	for i, class := range splitList(order) {
+		s.rank[class] = i
+	}
+
+	return s
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// globRegexp compiles a case-insensitive glob where * matches anything
+func globRegexp(glob string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + quoted + "$")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	s := parseSettings()
+
+	funcs := make(map[*types.Func]*ast.FuncDecl)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok && fn.Body != nil {
+			funcs[obj] = fn
+		}
+	})
+
+	handlesUploads := uploads != "" && declaresMatching(pass, globRegexp(uploads))
+
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		if !isGRPCFunc(pass, call, "NewServer") {
+			return true
+		}
+		if strings.HasSuffix(pass.Fset.File(call.Pos()).Name(), "_test.go") {
+			return true
+		}
+
+		c := &collector{
+			pass:     pass,
+			settings: s,
+			funcs:    funcs,
+			visiting: make(map[types.Object]bool),
+			present:  make(map[string]bool),
+			complete: true,
+		}
+		body := enclosingBody(stack)
+		for i, arg := range call.Args {
+			spread := call.Ellipsis.IsValid() && i == len(call.Args)-1
+			c.option(arg, body, spread, 0)
+		}
+
+		checkServer(reporter, call, c, s, handlesUploads)
+		return true
+	})
+
+	return nil, nil
+}
+
+// checkServer reports the problems of one grpc.NewServer call
+func checkServer(reporter *nolint.Reporter, call *ast.CallExpr, c *collector, s settings, handlesUploads bool) {
+	if len(call.Args) == 0 {
+		reporter.Reportf(call.Pos(),
+			"grpc.NewServer called without options; install %s interceptors",
+			strings.Join(s.required, ", "))
+		return
+	}
+
+	for _, chain := range [][]interceptor{c.chain(c.unaryFirst, c.unary), c.chain(c.streamFirst, c.stream)} {
+		checkOrder(reporter, chain, s)
+	}
+
+	if !c.complete {
+		return
+	}
+
+	for _, class := range s.required {
+		if !c.present[class] {
+			reporter.Reportf(call.Pos(),
+				"gRPC server has no %s interceptor; add it to the server options", class)
+		}
+	}
+
+	if handlesUploads && !c.maxRecvMsgSize {
+		reporter.Reportf(call.Pos(),
+			"package handles uploads but the gRPC server does not set grpc.MaxRecvMsgSize; "+
+				"the 4 MiB default rejects large messages")
+	}
+}
+
+// checkOrder reports interceptors that run outside a class that -order
+// requires to be further out
+func checkOrder(reporter *nolint.Reporter, chain []interceptor, s settings) {
+	reported := make(map[[2]string]bool)
+
+	for i, outer := range chain {
+		outerRank, ok := s.rank[outer.class]
+		if !ok {
+			continue
+		}
+		for _, inner := range chain[i+1:] {
+			innerRank, ok := s.rank[inner.class]
+			if !ok || innerRank >= outerRank {
+				continue
+			}
+			pair := [2]string{inner.class, outer.class}
+			if reported[pair] {
+				continue
+			}
+			reported[pair] = true
+			reporter.Reportf(inner.pos,
+				"%s interceptor runs inside %s interceptor; move it before %s in the chain",
+				inner.class, outer.class, outer.class)
+		}
+	}
+}
+
+// interceptor is one classified interceptor in a chain
+type interceptor struct {
+	class string
+	pos   token.Pos
+}
+
+// collector reconstructs the options passed to one grpc.NewServer call
+type collector struct {
+	pass     *analysis.Pass
+	settings settings
+	funcs    map[*types.Func]*ast.FuncDecl
+	visiting map[types.Object]bool // variables and functions being followed
+
+	// grpc.UnaryInterceptor and StreamInterceptor run before the chained ones
+	unaryFirst, unary   []interceptor
+	streamFirst, stream []interceptor
+
+	present        map[string]bool // classes installed by any option
+	maxRecvMsgSize bool
+	complete       bool // every option could be resolved
+}
+
+// maxDepth bounds how far options are followed through variables and helpers
+const maxDepth = 8
+
+// chain returns the interceptors of a chain in execution order
+func (c *collector) chain(first, chained []interceptor) []interceptor {
+	return append(append([]interceptor(nil), first...), chained...)
+}
+
+// option collects a single server option, or a slice of options when spread
+// is set. body is the function body variables are resolved in.
+func (c *collector) option(expr ast.Expr, body *ast.BlockStmt, spread bool, depth int) {
+	if depth > maxDepth {
+		c.complete = false
+		return
+	}
+	expr = ast.Unparen(expr)
+
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if !spread {
+			c.complete = false
+			return
+		}
+		for _, elt := range e.Elts {
+			c.option(elt, body, false, depth+1)
+		}
+		return
+
+	case *ast.Ident:
+		c.variable(e, body, spread, depth)
+		return
+
+	case *ast.CallExpr:
+		if spread && isBuiltin(c.pass, e, "append") {
+			for i, arg := range e.Args {
+				c.option(arg, body, i == 0 || (e.Ellipsis.IsValid() && i == len(e.Args)-1), depth+1)
+			}
+			return
+		}
+		if !spread && c.grpcOption(e, body, depth) {
+			return
+		}
+		if fn := c.helper(e); fn != nil {
+			c.visiting[c.pass.TypesInfo.Defs[fn.Name]] = true
+			defer delete(c.visiting, c.pass.TypesInfo.Defs[fn.Name])
+			for _, result := range returnedValues(fn) {
+				c.option(result, fn.Body, spread, depth+1)
+			}
+			return
+		}
+	}
+
+	c.complete = false
+}
+
+// variable follows an option variable to the values assigned to it
+func (c *collector) variable(ident *ast.Ident, body *ast.BlockStmt, spread bool, depth int) {
+	obj, ok := c.pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok {
+		c.complete = false
+		return
+	}
+	if c.visiting[obj] {
+		// append(opts, ...) refers back to the slice being collected
+		return
+	}
+
+	values := assignedValues(c.pass, body, obj)
+	if len(values) == 0 {
+		c.complete = false
+		return
+	}
+
+	c.visiting[obj] = true
+	defer delete(c.visiting, obj)
+	for _, v := range values {
+		c.option(v, body, spread, depth+1)
+	}
+}
+
+// grpcOption records an option created by a grpc package function and
+// reports whether call was one
+func (c *collector) grpcOption(call *ast.CallExpr, body *ast.BlockStmt, depth int) bool {
+	callee := calleeOf(c.pass, call)
+	if callee == nil || callee.Pkg() == nil || callee.Pkg().Path() != grpcPackage {
+		return false
+	}
+
+	switch callee.Name() {
+	case "ChainUnaryInterceptor":
+		c.unary = append(c.unary, c.interceptors(call, body, depth)...)
+	case "UnaryInterceptor":
+		c.unaryFirst = append(c.unaryFirst, c.interceptors(call, body, depth)...)
+	case "ChainStreamInterceptor":
+		c.stream = append(c.stream, c.interceptors(call, body, depth)...)
+	case "StreamInterceptor":
+		c.streamFirst = append(c.streamFirst, c.interceptors(call, body, depth)...)
+	case "StatsHandler":
+		c.interceptors(call, body, depth)
+	case "MaxRecvMsgSize":
+		c.maxRecvMsgSize = true
+	}
+	return true
+}
+
+// interceptors classifies the arguments of an interceptor option and marks
+// their classes present. Arguments of unknown class are left out of the
+// chain.
+func (c *collector) interceptors(call *ast.CallExpr, body *ast.BlockStmt, depth int) []interceptor {
+	var result []interceptor
+	for _, arg := range call.Args {
+		for _, name := range c.names(arg, body, depth+1) {
+			class := c.classify(name)
+			if class == "" {
+				continue
+			}
+			c.present[class] = true
+			result = append(result, interceptor{class: class, pos: arg.Pos()})
+			break
+		}
+	}
+	return result
+}
+
+// names returns the qualified names an interceptor expression may be
+// classified by: the function creating or implementing it, and the
+// variables it passes through
+func (c *collector) names(expr ast.Expr, body *ast.BlockStmt, depth int) []string {
+	if depth > maxDepth {
+		return nil
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		if callee := calleeOf(c.pass, e); callee != nil {
+			return []string{qualifiedName(callee)}
+		}
+
+	case *ast.Ident, *ast.SelectorExpr:
+		var ident *ast.Ident
+		if sel, ok := e.(*ast.SelectorExpr); ok {
+			ident = sel.Sel
+		} else {
+			ident = e.(*ast.Ident)
+		}
+
+		switch obj := c.pass.TypesInfo.Uses[ident].(type) {
+		case *types.Func:
+			return []string{qualifiedName(obj)}
+		case *types.Var:
+			names := []string{qualifiedName(obj)}
+			if c.visiting[obj] {
+				return names
+			}
+			c.visiting[obj] = true
+			defer delete(c.visiting, obj)
+			for _, v := range assignedValues(c.pass, body, obj) {
+				names = append(names, c.names(v, body, depth+1)...)
+			}
+			return names
+		}
+	}
+
+	return nil
+}
+
+// classify returns the class of the first pattern matching name, or ""
+func (c *collector) classify(name string) string {
+	for _, p := range c.settings.patterns {
+		if p.re.MatchString(name) {
+			return p.class
+		}
+	}
+	return ""
+}
+
+// helper returns the declaration of a function of this package called by
+// call, unless it is already being followed
+func (c *collector) helper(call *ast.CallExpr) *ast.FuncDecl {
+	callee := calleeOf(c.pass, call)
+	if callee == nil {
+		return nil
+	}
+	fn := c.funcs[callee]
+	if fn == nil || c.visiting[callee] {
+		return nil
+	}
+	return fn
+}
+
+// returnedValues returns the first result of every return statement of fn,
+// skipping closures
+func returnedValues(fn *ast.FuncDecl) []ast.Expr {
+	var values []ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) > 0 {
+				values = append(values, node.Results[0])
+			}
+		}
+		return true
+	})
+	return values
+}
+
+// assignedValues returns every value assigned to obj in body, including its
+// declaration. Package-level variables are resolved through their
+// declaration.
+func assignedValues(pass *analysis.Pass, body *ast.BlockStmt, obj *types.Var) []ast.Expr {
+	var values []ast.Expr
+
+	record := func(lhs []ast.Expr, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, l := range lhs {
+			if ident, ok := l.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+				values = append(values, rhs[i])
+			}
+		}
+	}
+
+	visit := func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			record(node.Lhs, node.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			record(lhs, node.Values)
+		}
+		return true
+	}
+
+	if obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+		for _, f := range pass.Files {
+			for _, decl := range f.Decls {
+				if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+					ast.Inspect(gen, visit)
+				}
+			}
+		}
+		return values
+	}
+
+	if body != nil {
+		ast.Inspect(body, visit)
+	}
+	return values
+}
+
+// enclosingBody returns the body of the innermost function in stack
+func enclosingBody(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			return fn.Body
+		case *ast.FuncLit:
+			return fn.Body
+		}
+	}
+	return nil
+}
+
+// declaresMatching checks if the package declares a function, method, or
+// type whose name matches re
+func declaresMatching(pass *analysis.Pass, re *regexp.Regexp) bool {
+	for _, obj := range pass.TypesInfo.Defs {
+		switch obj.(type) {
+		case *types.Func, *types.TypeName:
+			if re.MatchString(obj.Name()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isGRPCFunc checks if call calls the named function of the grpc package
+func isGRPCFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	callee := calleeOf(pass, call)
+	return callee != nil && callee.Pkg() != nil && callee.Pkg().Path() == grpcPackage && callee.Name() == name
+}
+
+// isBuiltin checks if call calls the named builtin function
+func isBuiltin(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := pass.TypesInfo.Uses[ident].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// calleeOf returns the statically called function or method, if any
+func calleeOf(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		fn, _ := pass.TypesInfo.Uses[fun].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := pass.TypesInfo.Uses[fun.Sel].(*types.Func)
+		return fn
+	}
+	return nil
+}
+
+// qualifiedName formats obj as path.Name, or path.Type.Method for methods
+func qualifiedName(obj types.Object) string {
+	name := obj.Name()
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			t := recv.Type()
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			if named, ok := t.(*types.Named); ok {
+				name = named.Obj().Name() + "." + name
+			}
+		}
+	}
+	if obj.Pkg() == nil {
+		return name
+	}
+	return obj.Pkg().Path() + "." + name
+}
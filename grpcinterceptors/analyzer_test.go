@@ -0,0 +1,14 @@
+package grpcinterceptors_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/grpcinterceptors"
+)
+
+func TestGRPCInterceptorsAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, grpcinterceptors.Analyzer, "a", "uploads")
+}
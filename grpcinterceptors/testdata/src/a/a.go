@@ -0,0 +1,104 @@
+package a
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+func authenticate(ctx context.Context) (context.Context, error) { return ctx, nil }
+
+// Bad: no options at all
+func Bare() *grpc.Server {
+	return grpc.NewServer() // want `grpc.NewServer called without options; install recovery, otel, auth interceptors`
+}
+
+// Good: canonical chain
+func Canonical() *grpc.Server {
+	return grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			recovery.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(authenticate),
+		),
+		grpc.ChainStreamInterceptor(
+			recovery.StreamServerInterceptor(),
+			auth.StreamServerInterceptor(authenticate),
+		),
+	)
+}
+
+// Bad: recovery does not cover panics in auth
+func RecoveryAfterAuth() *grpc.Server {
+	return grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			auth.UnaryServerInterceptor(authenticate),
+			recovery.UnaryServerInterceptor(), // want `recovery interceptor runs inside auth interceptor; move it before auth in the chain`
+		),
+	)
+}
+
+// Bad: UnaryInterceptor runs before the chained interceptors
+func RecoveryChainedAfterUnaryAuth() *grpc.Server {
+	return grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(recovery.UnaryServerInterceptor()), // want `recovery interceptor runs inside auth interceptor`
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(authenticate)),
+	)
+}
+
+// Bad: missing telemetry
+func MissingOtel() *grpc.Server {
+	return grpc.NewServer( // want `gRPC server has no otel interceptor`
+		grpc.ChainUnaryInterceptor(
+			recovery.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(authenticate),
+		),
+	)
+}
+
+// Good: options built in a slice and a helper
+func Built() *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	}
+	opts = append(opts, interceptors()...)
+	return grpc.NewServer(opts...)
+}
+
+func interceptors() []grpc.ServerOption {
+	authn := auth.UnaryServerInterceptor(authenticate)
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(recovery.UnaryServerInterceptor(), authn),
+	}
+}
+
+// Bad: helper-built options missing auth
+func BuiltWithoutAuth() *grpc.Server {
+	opts := append(baseOptions(), grpc.ConnectionTimeout(10))
+	return grpc.NewServer(opts...) // want `gRPC server has no auth interceptor`
+}
+
+func baseOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		recoveryOption,
+	}
+}
+
+var recoveryOption = grpc.ChainUnaryInterceptor(recovery.UnaryServerInterceptor())
+
+// Unknown: options come from the caller, only ordering is checked
+func FromCaller(extra ...grpc.ServerOption) *grpc.Server {
+	opts := append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(recovery.UnaryServerInterceptor())}, extra...)
+	return grpc.NewServer(opts...)
+}
+
+// Suppressed
+func Internal() *grpc.Server {
+	return grpc.NewServer() //nolint:grpcinterceptors // in-process test harness
+}
@@ -0,0 +1,11 @@
+package a
+
+import (
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func TestServer(t *testing.T) {
+	_ = grpc.NewServer()
+}
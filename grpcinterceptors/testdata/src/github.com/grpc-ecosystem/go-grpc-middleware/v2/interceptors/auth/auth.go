@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+type AuthFunc func(ctx context.Context) (context.Context, error)
+
+func UnaryServerInterceptor(f AuthFunc) grpc.UnaryServerInterceptor { return nil }
+
+func StreamServerInterceptor(f AuthFunc) grpc.StreamServerInterceptor { return nil }
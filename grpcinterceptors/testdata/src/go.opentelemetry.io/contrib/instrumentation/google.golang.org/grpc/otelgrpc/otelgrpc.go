@@ -0,0 +1,9 @@
+package otelgrpc
+
+import "google.golang.org/grpc/stats"
+
+type handler struct{}
+
+func (handler) HandleRPC() {}
+
+func NewServerHandler() stats.Handler { return handler{} }
@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/stats"
+)
+
+type Server struct{}
+
+type ServerOption interface{ apply() }
+
+type option struct{}
+
+func (option) apply() {}
+
+type UnaryServerInfo struct{}
+
+type UnaryHandler func(ctx context.Context, req any) (any, error)
+
+type UnaryServerInterceptor func(ctx context.Context, req any, info *UnaryServerInfo, handler UnaryHandler) (any, error)
+
+type StreamServerInterceptor func(srv any, ss any, info any, handler any) error
+
+func NewServer(opt ...ServerOption) *Server { return &Server{} }
+
+func ChainUnaryInterceptor(interceptors ...UnaryServerInterceptor) ServerOption { return option{} }
+
+func UnaryInterceptor(i UnaryServerInterceptor) ServerOption { return option{} }
+
+func ChainStreamInterceptor(interceptors ...StreamServerInterceptor) ServerOption { return option{} }
+
+func StreamInterceptor(i StreamServerInterceptor) ServerOption { return option{} }
+
+func StatsHandler(h stats.Handler) ServerOption { return option{} }
+
+func MaxRecvMsgSize(m int) ServerOption { return option{} }
+
+func ConnectionTimeout(d int64) ServerOption { return option{} }
@@ -0,0 +1,3 @@
+package stats
+
+type Handler interface{ HandleRPC() }
@@ -0,0 +1,36 @@
+package uploads
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+type FileService struct{}
+
+func (s *FileService) UploadFile(ctx context.Context, data []byte) error { return nil }
+
+func authenticate(ctx context.Context) (context.Context, error) { return ctx, nil }
+
+func options() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			recovery.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(authenticate),
+		),
+	}
+}
+
+// Bad: uploads are limited to the 4 MiB default
+func Default() *grpc.Server {
+	return grpc.NewServer(options()...) // want `package handles uploads but the gRPC server does not set grpc.MaxRecvMsgSize`
+}
+
+// Good: raised receive limit
+func Large() *grpc.Server {
+	return grpc.NewServer(append(options(), grpc.MaxRecvMsgSize(64<<20))...)
+}
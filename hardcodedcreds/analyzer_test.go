@@ -0,0 +1,24 @@
+package hardcodedcreds_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/hardcodedcreds"
+)
+
+func TestHardcodedCreds(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, hardcodedcreds.Analyzer, "creds", "fixtures/testdata/keys")
+}
+
+func TestHardcodedCredsIncludeTests(t *testing.T) {
+	if err := hardcodedcreds.Analyzer.Flags.Set("include-tests", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = hardcodedcreds.Analyzer.Flags.Set("include-tests", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, hardcodedcreds.Analyzer, "included")
+}
@@ -0,0 +1,4 @@
+package keys
+
+// Not reported: packages under testdata/ are skipped
+var apiToken = "q8JxT2mN4vRb7LpZ0sWk3YhD6cFg9EaU"
@@ -0,0 +1,4 @@
+package included
+
+// Reported: test files are checked with -include-tests
+var testPassword = "hunter2" // want `potential hardcoded credential in "testPassword"`
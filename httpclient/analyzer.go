@@ -4,12 +4,15 @@ package httpclient
 
 import (
 	"go/ast"
+	"go/constant"
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
 
+	"github.com/spechtlabs/golint-sl/internal/ctxfix"
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
 
@@ -19,7 +22,13 @@ This analyzer detects:
 1. http.Client{} without Timeout set (will hang forever on slow servers)
 2. http.DefaultClient usage (has no timeout, shared globally)
 3. http.Get/Post/etc direct calls (use shared DefaultClient)
-4. Missing Transport configuration for connection pooling
+4. http.Transport{} without DialContext, TLSHandshakeTimeout, or
+   ResponseHeaderTimeout, which can hang while connecting even when the
+   client has a Timeout
+5. var c http.Client used before c.Timeout is assigned
+
+A client or transport assigned to a local variable may also get its
+timeouts field by field, as long as that happens before it is used.
 
 HTTP clients without timeouts are a common source of goroutine leaks
 and hung services in production.`
@@ -27,7 +36,7 @@ and hung services in production.`
 var Analyzer = &analysis.Analyzer{
 	Name:     "httpclient",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -39,58 +48,205 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.CompositeLit)(nil),
 		(*ast.CallExpr)(nil),
 		(*ast.SelectorExpr)(nil),
+		(*ast.ValueSpec)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
 		switch node := n.(type) {
 		case *ast.CompositeLit:
-			checkClientLiteral(reporter, pass, node)
+			checkClientLiteral(reporter, pass, node, stack)
+			checkTransportLiteral(reporter, pass, node, stack)
+		case *ast.ValueSpec:
+			checkClientVar(reporter, pass, node, stack)
 		case *ast.CallExpr:
-			checkDirectHTTPCalls(reporter, node)
+			checkDirectHTTPCalls(reporter, pass, node, stack)
 		case *ast.SelectorExpr:
 			checkDefaultClient(reporter, node)
 		}
+		return true
 	})
 
 	return nil, nil
 }
 
-// checkClientLiteral detects http.Client{} without Timeout
-func checkClientLiteral(reporter *nolint.Reporter, pass *analysis.Pass, lit *ast.CompositeLit) {
+// timeoutFields are the http.Client fields that bound a request
+var timeoutFields = map[string]bool{"Timeout": true}
+
+// transportTimeoutFields are the http.Transport fields that bound
+// connecting to a server
+var transportTimeoutFields = map[string]bool{
+	"DialContext":           true,
+	"Dial":                  true,
+	"DialTLSContext":        true,
+	"DialTLS":               true,
+	"TLSHandshakeTimeout":   true,
+	"ResponseHeaderTimeout": true,
+}
+
+// checkClientLiteral detects http.Client{} without Timeout, unless the
+// variable it is assigned to gets a Timeout before it is used
+func checkClientLiteral(reporter *nolint.Reporter, pass *analysis.Pass, lit *ast.CompositeLit, stack []ast.Node) {
 	// Check if this is an http.Client composite literal
 	if !isHTTPClientType(pass, lit.Type) {
 		return
 	}
 
-	hasTimeout := false
-	hasTransport := false
+	if hasField(lit, timeoutFields) || configuredLater(pass, lit, stack, timeoutFields) {
+		return
+	}
+
+	reporter.Reportf(lit.Pos(),
+		"http.Client without Timeout will wait forever; always set Timeout (e.g., 30*time.Second)")
+}
+
+// checkClientVar detects var c http.Client declarations that are used
+// before a Timeout is assigned
+func checkClientVar(reporter *nolint.Reporter, pass *analysis.Pass, spec *ast.ValueSpec, stack []ast.Node) {
+	if len(spec.Values) > 0 || !isHTTPClientType(pass, spec.Type) {
+		return
+	}
 
+	for _, name := range spec.Names {
+		if name.Name == "_" || configuredLater(pass, name, stack, timeoutFields) {
+			continue
+		}
+		reporter.Reportf(name.Pos(),
+			"http.Client %s is used without setting Timeout and will wait forever; assign %s.Timeout before using it",
+			name.Name, name.Name)
+	}
+}
+
+// checkTransportLiteral detects http.Transport{} without any timeout on
+// connecting, which can hang even when the client has a Timeout
+func checkTransportLiteral(reporter *nolint.Reporter, pass *analysis.Pass, lit *ast.CompositeLit, stack []ast.Node) {
+	if !isHTTPType(pass.TypesInfo.TypeOf(lit.Type), "Transport") {
+		return
+	}
+
+	if hasField(lit, transportTimeoutFields) || configuredLater(pass, lit, stack, transportTimeoutFields) {
+		return
+	}
+
+	reporter.Reportf(lit.Pos(),
+		"http.Transport without DialContext, TLSHandshakeTimeout, or ResponseHeaderTimeout can hang while connecting; "+
+			"set a dialer with a timeout and TLSHandshakeTimeout")
+}
+
+// hasField checks if lit sets one of fields
+func hasField(lit *ast.CompositeLit, fields map[string]bool) bool {
 	for _, elt := range lit.Elts {
 		kv, ok := elt.(*ast.KeyValueExpr)
 		if !ok {
 			continue
 		}
+		if key, ok := kv.Key.(*ast.Ident); ok && fields[key.Name] {
+			return true
+		}
+	}
+	return false
+}
 
-		key, ok := kv.Key.(*ast.Ident)
-		if !ok {
-			continue
+// configuredLater checks if node, a composite literal assigned to a local
+// variable or the name of a declared variable, gets one of fields assigned
+// to a non-zero value before the variable is used for anything else:
+//
+//	c := &http.Client{}
+//	c.Timeout = 30 * time.Second
+//	return c
+func configuredLater(pass *analysis.Pass, node ast.Node, stack []ast.Node, fields map[string]bool) bool {
+	v, body := assignedVar(pass, node, stack)
+	if v == nil || body == nil {
+		return false
+	}
+
+	configured, used := false, false
+	fieldWrites := make(map[*ast.Ident]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if configured || used {
+			return false
+		}
+		if n == nil || n.End() <= node.End() {
+			return n != nil
 		}
 
-		switch key.Name {
-		case "Timeout":
-			hasTimeout = true
-		case "Transport":
-			hasTransport = true
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				sel, ok := lhs.(*ast.SelectorExpr)
+				if !ok {
+					continue
+				}
+				ident, ok := sel.X.(*ast.Ident)
+				if !ok || pass.TypesInfo.Uses[ident] != v {
+					continue
+				}
+				fieldWrites[ident] = true
+				if fields[sel.Sel.Name] && len(node.Rhs) == len(node.Lhs) && !isZero(pass, node.Rhs[i]) {
+					configured = true
+				}
+			}
+		case *ast.Ident:
+			if !fieldWrites[node] && pass.TypesInfo.Uses[node] == v {
+				used = true
+			}
 		}
+		return true
+	})
+	return configured
+}
+
+// assignedVar returns the local variable node is assigned to, or that node
+// names, and the body of the function declaring it
+func assignedVar(pass *analysis.Pass, node ast.Node, stack []ast.Node) (types.Object, *ast.BlockStmt) {
+	var v types.Object
+	if ident, ok := node.(*ast.Ident); ok {
+		v = pass.TypesInfo.Defs[ident]
+	} else {
+		i := len(stack) - 2
+		var expr ast.Node = node
+		if unary, ok := stack[i].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			expr = unary
+			i--
+		}
+		switch parent := stack[i].(type) {
+		case *ast.AssignStmt:
+			for j, rhs := range parent.Rhs {
+				if rhs == expr && len(parent.Lhs) == len(parent.Rhs) {
+					if ident, ok := parent.Lhs[j].(*ast.Ident); ok {
+						v = pass.TypesInfo.ObjectOf(ident)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for j, value := range parent.Values {
+				if value == expr && len(parent.Names) == len(parent.Values) {
+					v = pass.TypesInfo.Defs[parent.Names[j]]
+				}
+			}
+		}
+	}
+	if v == nil || v.Parent() == v.Pkg().Scope() {
+		return nil, nil
 	}
 
-	if !hasTimeout {
-		reporter.Reportf(lit.Pos(),
-			"http.Client without Timeout will wait forever; always set Timeout (e.g., 30*time.Second)")
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			return v, fn.Body
+		case *ast.FuncLit:
+			return v, fn.Body
+		}
 	}
+	return nil, nil
+}
 
-	// Transport is recommended but not required
-	_ = hasTransport
+// isZero checks if expr is the constant zero, like Timeout = 0
+func isZero(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	return ok && tv.Value != nil && tv.Value.Kind() != constant.Unknown && constant.Sign(tv.Value) == 0
 }
 
 // isHTTPClientType checks if a type is http.Client
@@ -119,6 +275,16 @@ func isHTTPClientType(pass *analysis.Pass, expr ast.Expr) bool {
 	return obj.Name() == "Client" && obj.Pkg() != nil && obj.Pkg().Path() == "net/http"
 }
 
+// isHTTPType checks if t is the named type net/http.<name>
+func isHTTPType(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == name && obj.Pkg() != nil && obj.Pkg().Path() == "net/http"
+}
+
 // isHTTPClientAST checks using AST when type info isn't available
 func isHTTPClientAST(expr ast.Expr) bool {
 	sel, ok := expr.(*ast.SelectorExpr)
@@ -139,7 +305,7 @@ func isHTTPClientAST(expr ast.Expr) bool {
 }
 
 // checkDirectHTTPCalls detects http.Get, http.Post, etc.
-func checkDirectHTTPCalls(reporter *nolint.Reporter, call *ast.CallExpr) {
+func checkDirectHTTPCalls(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -154,12 +320,24 @@ func checkDirectHTTPCalls(reporter *nolint.Reporter, call *ast.CallExpr) {
 		return
 	}
 
+	if sel.Sel.Name == "NewRequest" {
+		file := stack[0].(*ast.File)
+		reporter.Report(&analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: "http.NewRequest doesn't support context; use http.NewRequestWithContext instead",
+			SuggestedFixes: []analysis.SuggestedFix{
+				ctxfix.NewRequestWithContext(file, call, ctxfix.ContextParam(pass.TypesInfo, stack)),
+			},
+		})
+		return
+	}
+
 	directCalls := map[string]string{
-		"Get":        "http.Get uses DefaultClient with no timeout; create a client with Timeout",
-		"Post":       "http.Post uses DefaultClient with no timeout; create a client with Timeout",
-		"PostForm":   "http.PostForm uses DefaultClient with no timeout; create a client with Timeout",
-		"Head":       "http.Head uses DefaultClient with no timeout; create a client with Timeout",
-		"NewRequest": "http.NewRequest doesn't support context; use http.NewRequestWithContext instead",
+		"Get":      "http.Get uses DefaultClient with no timeout; create a client with Timeout",
+		"Post":     "http.Post uses DefaultClient with no timeout; create a client with Timeout",
+		"PostForm": "http.PostForm uses DefaultClient with no timeout; create a client with Timeout",
+		"Head":     "http.Head uses DefaultClient with no timeout; create a client with Timeout",
 	}
 
 	if msg, found := directCalls[sel.Sel.Name]; found {
@@ -0,0 +1,19 @@
+package httpclient_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/httpclient"
+)
+
+func TestHTTPClientNewRequestFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, httpclient.Analyzer, "newrequest")
+}
+
+func TestHTTPClientVariables(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, httpclient.Analyzer, "vars")
+}
@@ -0,0 +1,34 @@
+package newrequest
+
+import (
+	"context"
+	"net/http"
+)
+
+func Named(reqCtx context.Context, url string) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, url, nil) // want `http.NewRequest doesn't support context`
+}
+
+func Closure(ctx context.Context, urls []string) error {
+	build := func(url string) error {
+		_, err := http.NewRequest(http.MethodGet, url, nil) // want `http.NewRequest doesn't support context`
+		return err
+	}
+	for _, url := range urls {
+		if err := build(url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Inner(url string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := http.NewRequest(http.MethodGet, url, nil) // want `http.NewRequest doesn't support context`
+		return err
+	}
+}
+
+func Unnamed(_ context.Context, url string) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, url, nil) // want `http.NewRequest doesn't support context`
+}
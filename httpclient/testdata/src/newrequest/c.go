@@ -0,0 +1,7 @@
+package newrequest
+
+import "net/http"
+
+func Head(url string) (*http.Request, error) {
+	return http.NewRequest(http.MethodHead, url, nil) // want `http.NewRequest doesn't support context`
+}
@@ -0,0 +1,69 @@
+package vars
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+func fieldByField() *http.Client {
+	c := &http.Client{}
+	c.Timeout = 30 * time.Second
+	return c
+}
+
+func zeroTimeout() *http.Client {
+	c := &http.Client{} // want `http.Client without Timeout will wait forever`
+	c.Timeout = 0
+	return c
+}
+
+func usedBeforeTimeout(req *http.Request) (*http.Response, error) {
+	c := &http.Client{} // want `http.Client without Timeout will wait forever`
+	resp, err := c.Do(req)
+	c.Timeout = time.Second
+	return resp, err
+}
+
+func newHTTPClient(transport http.RoundTripper) *http.Client {
+	c := &http.Client{} // want `http.Client without Timeout will wait forever`
+	c.Transport = transport
+	return c
+}
+
+func declared() *http.Client {
+	var c http.Client
+	c.Timeout = 10 * time.Second
+	return &c
+}
+
+func declaredWithoutTimeout() *http.Client {
+	var c http.Client // want `http.Client c is used without setting Timeout and will wait forever`
+	return &c
+}
+
+func transportWithoutTimeouts() *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{MaxIdleConns: 10}, // want `http.Transport without DialContext, TLSHandshakeTimeout, or ResponseHeaderTimeout can hang while connecting`
+	}
+}
+
+func transportWithDialer() *http.Transport {
+	return &http.Transport{
+		DialContext: (&net.Dialer{Timeout: 5 * time.Second}).DialContext,
+	}
+}
+
+func transportFieldByField() *http.Transport {
+	t := &http.Transport{}
+	t.TLSHandshakeTimeout = 10 * time.Second
+	return t
+}
+
+func transportUsedFirst() *http.Client {
+	t := &http.Transport{} // want `http.Transport without DialContext`
+	c := &http.Client{Transport: t, Timeout: time.Minute}
+	t.ResponseHeaderTimeout = time.Second
+	return c
+}
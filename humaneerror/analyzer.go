@@ -8,8 +8,11 @@
 package humaneerror
 
 import (
+	"flag"
 	"go/ast"
+	"go/constant"
 	"go/types"
+	"regexp"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -27,16 +30,37 @@ This analyzer ensures that:
 3. All calls to humane.Wrap() include at least one advice string
 4. Plain errors.New() and fmt.Errorf() are flagged in favor of humane equivalents
 
-The goal is to ensure all errors in the codebase provide actionable user guidance.`
+Advice given as constants is checked for quality like string literals.
+Advice spread from a slice (advice...) counts as advice unless the slice
+is an empty literal or nil.
+
+The goal is to ensure all errors in the codebase provide actionable user guidance.
+
+Flags:
+    -non-actionable  comma-separated phrases that make short advice non-actionable`
+
+// defaultNonActionable are the phrases of advice that does not tell the user
+// what to do
+const defaultNonActionable = "see underlying error,check error,something went wrong,an error occurred,failed,error:"
+
+var nonActionable string
 
 // Analyzer is the humane error analyzer
 var Analyzer = &analysis.Analyzer{
 	Name:     "humaneerror",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("humaneerror", flag.ExitOnError)
+	fs.StringVar(&nonActionable, "non-actionable", defaultNonActionable,
+		"comma-separated phrases that make short advice non-actionable")
+	return *fs
+}
+
 const (
 	humanePackage = "github.com/sierrasoftworks/humane-errors-go"
 	humaneAlias   = "humane"
@@ -49,78 +73,71 @@ var commonHumaneIdentifiers = map[string]bool{
 	"he":      true,
 }
 
-// stdlibInterfaceMethods lists method signatures that are required by standard library
-// interfaces and must return plain `error`. We exempt these from humane.Error requirements.
-// Format: "MethodName:paramCount" or just "MethodName" for any param count
-var stdlibInterfaceMethods = map[string]bool{
+// interfaceMethods lists the methods of standard library and common framework
+// interfaces that must return plain `error`, with the signatures that satisfy
+// them. A method is only exempt from humane.Error requirements when its
+// parameter and result types match one of these. Types use full package
+// paths, with byte, rune, and any for their aliases.
+var interfaceMethods = map[string][]string{
 	// io package
-	"Read":  true, // io.Reader
-	"Write": true, // io.Writer
-	"Close": true, // io.Closer
-	"Seek":  true, // io.Seeker
+	"Read":       {"([]byte) (int, error)"},        // io.Reader
+	"Write":      {"([]byte) (int, error)"},        // io.Writer
+	"Close":      {"() error"},                     // io.Closer
+	"Seek":       {"(int64, int) (int64, error)"},  // io.Seeker
+	"ReadFrom":   {"(io.Reader) (int64, error)"},   // io.ReaderFrom
+	"WriteTo":    {"(io.Writer) (int64, error)"},   // io.WriterTo
+	"ReadAt":     {"([]byte, int64) (int, error)"}, // io.ReaderAt
+	"WriteAt":    {"([]byte, int64) (int, error)"}, // io.WriterAt
+	"ReadByte":   {"() (byte, error)"},             // io.ByteReader
+	"WriteByte":  {"(byte) error"},                 // io.ByteWriter
+	"UnreadByte": {"() error"},                     // io.ByteScanner
+	"ReadRune":   {"() (rune, int, error)"},        // io.RuneReader
+	"UnreadRune": {"() error"},                     // io.RuneScanner
 
 	// encoding/json
-	"MarshalJSON":   true, // json.Marshaler
-	"UnmarshalJSON": true, // json.Unmarshaler
+	"MarshalJSON":   {"() ([]byte, error)"}, // json.Marshaler
+	"UnmarshalJSON": {"([]byte) error"},     // json.Unmarshaler
 
 	// encoding
-	"MarshalText":     true, // encoding.TextMarshaler
-	"UnmarshalText":   true, // encoding.TextUnmarshaler
-	"MarshalBinary":   true, // encoding.BinaryMarshaler
-	"UnmarshalBinary": true, // encoding.BinaryUnmarshaler
+	"MarshalText":     {"() ([]byte, error)"}, // encoding.TextMarshaler
+	"UnmarshalText":   {"([]byte) error"},     // encoding.TextUnmarshaler
+	"MarshalBinary":   {"() ([]byte, error)"}, // encoding.BinaryMarshaler
+	"UnmarshalBinary": {"([]byte) error"},     // encoding.BinaryUnmarshaler
+
+	// gob package
+	"GobEncode": {"() ([]byte, error)"}, // gob.GobEncoder
+	"GobDecode": {"([]byte) error"},     // gob.GobDecoder
 
-	// database/sql
-	"Scan":  true, // sql.Scanner, fmt.Scanner
-	"Value": true, // driver.Valuer
+	// database/sql and fmt
+	"Scan": {
+		"(any) error",                 // sql.Scanner
+		"(fmt.ScanState, rune) error", // fmt.Scanner
+	},
+	"Value": {"() (database/sql/driver.Value, error)"}, // driver.Valuer
 
 	// net package
-	"Accept":           true, // net.Listener
-	"Dial":             true, // net.Dialer
-	"DialContext":      true, // net.Dialer
-	"SetDeadline":      true, // net.Conn
-	"SetReadDeadline":  true, // net.Conn
-	"SetWriteDeadline": true, // net.Conn
+	"Accept":           {"() (net.Conn, error)"},                                // net.Listener
+	"Dial":             {"(string, string) (net.Conn, error)"},                  // net.Dialer
+	"DialContext":      {"(context.Context, string, string) (net.Conn, error)"}, // net.Dialer
+	"Listen":           {"(string, string) (net.Listener, error)"},              // tsnet.Server and similar
+	"Serve":            {"(net.Listener) error"},                                // http.Server, grpc.Server
+	"SetDeadline":      {"(time.Time) error"},                                   // net.Conn
+	"SetReadDeadline":  {"(time.Time) error"},                                   // net.Conn
+	"SetWriteDeadline": {"(time.Time) error"},                                   // net.Conn
 
 	// flag package
-	"Set": true, // flag.Value
-
-	// sort package (no error returns)
-
-	// context - usually not implemented by user types
+	"Set": {"(string) error"}, // flag.Value
 
-	// http package - ResponseWriter.Write is covered by io.Writer
+	// context
+	"Err": {"() error"}, // context.Context
 
-	// gob package
-	"GobEncode": true,
-	"GobDecode": true,
-
-	// Other common stdlib interface methods
-	"ReadFrom":   true, // io.ReaderFrom
-	"WriteTo":    true, // io.WriterTo
-	"ReadAt":     true, // io.ReaderAt
-	"WriteAt":    true, // io.WriterAt
-	"ReadByte":   true, // io.ByteReader
-	"WriteByte":  true, // io.ByteWriter
-	"UnreadByte": true, // io.ByteScanner
-	"ReadRune":   true, // io.RuneReader
-	"UnreadRune": true, // io.RuneScanner
-
-	// Kubernetes controller-runtime
-	"Reconcile":        true, // reconcile.Reconciler
-	"SetupWithManager": true, // Often part of controller setup
-
-	// Tailscale tsnet.Server interface
-	"Up":           true, // tsnet.Server
-	"Listen":       true, // net.Listener (also tsnet)
-	"ListenTLS":    true, // tsnet.Server
-	"ListenFunnel": true, // tsnet.Server
-	"LocalWhoIs":   true, // tsnet whois
-
-	// gRPC interfaces
-	"Serve":           true, // grpc.Server
-	"GracefulStop":    true, // grpc.Server (no error but common pattern)
-	"RegisterService": true, // grpc registration
+	// Long-running components, see the lifecycle analyzer
+	"Run": {"(context.Context) error"},
+}
 
+// callbackMethods are framework callbacks exempt by name alone, because their
+// signatures involve types that are not always available to check against
+var callbackMethods = map[string]bool{
 	// Cobra command callbacks - must return plain error
 	"RunE":               true, // cobra.Command.RunE
 	"PreRunE":            true, // cobra.Command.PreRunE
@@ -128,21 +145,22 @@ var stdlibInterfaceMethods = map[string]bool{
 	"PersistentPreRunE":  true, // cobra.Command.PersistentPreRunE
 	"PersistentPostRunE": true, // cobra.Command.PersistentPostRunE
 
-	// HTTP handlers and middleware
-	"ServeHTTP":   true, // http.Handler
-	"HandlerFunc": true, // common pattern
-
-	// Context functions
-	"Err": true, // context.Context.Err()
-
-	// Testing
-	"Run": true, // testing.T.Run callback, also common component pattern
+	// Kubernetes controller-runtime
+	"Reconcile":        true, // reconcile.Reconciler
+	"SetupWithManager": true, // Often part of controller setup
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
+	var patterns []string
+	for _, pattern := range strings.Split(nonActionable, ",") {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
 	// Track imports to understand package aliases
 	imports := make(map[string]string) // path -> local name
 
@@ -150,10 +168,13 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.File)(nil),
 		(*ast.FuncDecl)(nil),
 		(*ast.CallExpr)(nil),
-		(*ast.ReturnStmt)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
 		switch node := n.(type) {
 		case *ast.File:
 			// Reset imports for each file
@@ -172,19 +193,13 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			}
 
 		case *ast.FuncDecl:
-			// Track current function context for nested checks
-			if node.Name != nil {
-				currentFunc = funcContext{
-					name:                 node.Name.Name,
-					mustReturnPlainError: isFrameworkCallback(node.Name.Name),
-				}
-			}
 			checkFuncReturnsHumaneError(reporter, node, imports)
 
 		case *ast.CallExpr:
-			checkHumaneCallHasAdvice(reporter, node, imports)
-			checkForbiddenErrorCalls(reporter, node, imports)
+			checkHumaneCallHasAdvice(reporter, node, imports, patterns)
+			checkForbiddenErrorCalls(reporter, node, enclosingFunc(pass, stack))
 		}
+		return true
 	})
 
 	return nil, nil
@@ -205,7 +220,7 @@ func checkFuncReturnsHumaneError(reporter *nolint.Reporter, fn *ast.FuncDecl, _
 
 	// Skip methods that implement standard library interfaces
 	// These MUST return plain error to satisfy the interface
-	if isStdlibInterfaceMethod(fn) {
+	if isInterfaceMethod(reporter.Pass, fn) || callbackMethods[fn.Name.Name] {
 		return
 	}
 
@@ -225,31 +240,76 @@ func checkFuncReturnsHumaneError(reporter *nolint.Reporter, fn *ast.FuncDecl, _
 	}
 }
 
-// isStdlibInterfaceMethod checks if a function is implementing a standard library interface method
-func isStdlibInterfaceMethod(fn *ast.FuncDecl) bool {
-	if fn.Name == nil {
+// isInterfaceMethod checks if a method has the name and signature of a
+// standard library or framework interface method that returns plain error
+func isInterfaceMethod(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	if fn.Name == nil || fn.Recv == nil || len(fn.Recv.List) == 0 {
 		return false
 	}
 
-	methodName := fn.Name.Name
+	signatures, ok := interfaceMethods[fn.Name.Name]
+	if !ok {
+		return false
+	}
+
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
 
-	// Check if this method name is in our stdlib interface list
-	if exempt, exists := stdlibInterfaceMethods[methodName]; exists && exempt {
-		// For methods (has receiver), this is likely implementing an interface
-		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+	sig := signatureString(obj.Type().(*types.Signature))
+	for _, want := range signatures {
+		if sig == want {
 			return true
 		}
+	}
+	return false
+}
 
-		// For functions (no receiver), still exempt if it matches common patterns
-		// like standalone Close(), Read(), Write() functions that wrap interfaces
-		return true
+// builtinAliases matches the spellings types.TypeString uses for byte, rune,
+// and any when they are not written with their alias
+var builtinAliases = regexp.MustCompile(`\buint8\b|\bint32\b|interface\{\}`)
+
+// signatureString formats the parameter and result types of sig without
+// names or receiver, like "([]byte) (int, error)"
+func signatureString(sig *types.Signature) string {
+	qualifier := func(p *types.Package) string { return p.Path() }
+
+	tuple := func(t *types.Tuple, variadic bool) []string {
+		parts := make([]string, t.Len())
+		for i := 0; i < t.Len(); i++ {
+			typ := t.At(i).Type()
+			if variadic && i == t.Len()-1 {
+				parts[i] = "..." + types.TypeString(typ.(*types.Slice).Elem(), qualifier)
+				continue
+			}
+			parts[i] = types.TypeString(typ, qualifier)
+		}
+		return parts
 	}
 
-	return false
+	result := "(" + strings.Join(tuple(sig.Params(), sig.Variadic()), ", ") + ")"
+	switch results := tuple(sig.Results(), false); len(results) {
+	case 0:
+	case 1:
+		result += " " + results[0]
+	default:
+		result += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return builtinAliases.ReplaceAllStringFunc(result, func(m string) string {
+		switch m {
+		case "uint8":
+			return "byte"
+		case "int32":
+			return "rune"
+		}
+		return "any"
+	})
 }
 
 // checkHumaneCallHasAdvice ensures humane.New() and humane.Wrap() include advice
-func checkHumaneCallHasAdvice(reporter *nolint.Reporter, call *ast.CallExpr, imports map[string]string) {
+func checkHumaneCallHasAdvice(reporter *nolint.Reporter, call *ast.CallExpr, imports map[string]string, patterns []string) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -296,10 +356,13 @@ func checkHumaneCallHasAdvice(reporter *nolint.Reporter, call *ast.CallExpr, imp
 		return
 	}
 
+	// A spread of an empty slice literal or nil provides no advice
+	noAdvice := call.Ellipsis.IsValid() && isEmptySlice(call.Args[len(call.Args)-1])
+
 	switch funcName {
 	case "New":
 		// humane.New(message string, advice ...string) requires at least 2 args for advice
-		if len(call.Args) < 2 {
+		if len(call.Args) < 2 || noAdvice {
 			reporter.Reportf(call.Pos(),
 				"humane.New() should include at least one advice string: humane.New(message, advice1, advice2, ...)")
 		}
@@ -308,45 +371,59 @@ func checkHumaneCallHasAdvice(reporter *nolint.Reporter, call *ast.CallExpr, imp
 
 	case "Wrap":
 		// humane.Wrap(err, message string, advice ...string) requires at least 3 args for advice
-		if len(call.Args) < 3 {
+		if len(call.Args) < 3 || noAdvice {
 			reporter.Reportf(call.Pos(),
 				"humane.Wrap() should include at least one advice string: humane.Wrap(err, message, advice1, ...)")
 		}
 	}
 
 	// Check advice string quality (should be actionable)
-	checkAdviceQuality(reporter, call, funcName)
+	checkAdviceQuality(reporter, call, funcName, patterns)
+}
+
+// isEmptySlice checks if expr is nil or a slice literal without elements
+func isEmptySlice(expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name == "nil"
+	case *ast.CompositeLit:
+		return len(e.Elts) == 0
+	}
+	return false
 }
 
-// checkAdviceQuality verifies that advice strings are actionable
-func checkAdviceQuality(reporter *nolint.Reporter, call *ast.CallExpr, funcName string) {
+// checkAdviceQuality verifies that advice strings are actionable. Advice is
+// checked when its value is known at compile time: literals, constants, and
+// the elements of a spread slice literal.
+func checkAdviceQuality(reporter *nolint.Reporter, call *ast.CallExpr, funcName string, patterns []string) {
 	startIdx := 1 // For New(), advice starts at index 1
 	if funcName == "Wrap" {
 		startIdx = 2 // For Wrap(), advice starts at index 2
 	}
 
+	var adviceArgs []ast.Expr
 	for i := startIdx; i < len(call.Args); i++ {
-		lit, ok := call.Args[i].(*ast.BasicLit)
-		if !ok {
+		arg := call.Args[i]
+		if lit, ok := ast.Unparen(arg).(*ast.CompositeLit); ok && call.Ellipsis.IsValid() && i == len(call.Args)-1 {
+			adviceArgs = append(adviceArgs, lit.Elts...)
+			continue
+		}
+		adviceArgs = append(adviceArgs, arg)
+	}
+
+	for _, arg := range adviceArgs {
+		tv, ok := reporter.Pass.TypesInfo.Types[arg]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
 			continue
 		}
 
-		advice := strings.Trim(lit.Value, `"`)
+		advice := constant.StringVal(tv.Value)
 		adviceLower := strings.ToLower(advice)
 
 		// Check for non-actionable advice patterns
-		nonActionablePatterns := []string{
-			"see underlying error",
-			"check error",
-			"something went wrong",
-			"an error occurred",
-			"failed",
-			"error:",
-		}
-
-		for _, pattern := range nonActionablePatterns {
+		for _, pattern := range patterns {
 			if strings.Contains(adviceLower, pattern) && len(advice) < 50 {
-				reporter.Reportf(lit.Pos(),
+				reporter.Reportf(arg.Pos(),
 					"advice %q may not be actionable; provide specific steps the user can take to resolve the issue",
 					advice)
 				break
@@ -362,17 +439,64 @@ func checkAdviceQuality(reporter *nolint.Reporter, call *ast.CallExpr, funcName
 	}
 }
 
-// currentFuncContext tracks context about the current function being analyzed
+// funcContext describes the function a call appears in
 type funcContext struct {
 	name                 string
 	mustReturnPlainError bool
 }
 
-var currentFunc funcContext
+// enclosingFunc derives the context of the innermost function around the
+// node at the top of stack. Function literals inherit the context of the
+// function they appear in, unless they are assigned to a framework callback
+// field like cobra.Command.RunE themselves. Outside any function, such as in
+// package-level variable initializers, the context is empty.
+func enclosingFunc(pass *analysis.Pass, stack []ast.Node) funcContext {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncLit:
+			if name := callbackField(stack[:i], fn); name != "" && isFrameworkCallback(name) {
+				return funcContext{name: name, mustReturnPlainError: true}
+			}
+
+		case *ast.FuncDecl:
+			return funcContext{
+				name:                 fn.Name.Name,
+				mustReturnPlainError: isInterfaceMethod(pass, fn) || isFrameworkCallback(fn.Name.Name),
+			}
+		}
+	}
+	return funcContext{}
+}
+
+// callbackField returns the name of the field a function literal is
+// assigned to, in a composite literal or an assignment, or ""
+func callbackField(parents []ast.Node, lit *ast.FuncLit) string {
+	if len(parents) == 0 {
+		return ""
+	}
+
+	switch parent := parents[len(parents)-1].(type) {
+	case *ast.KeyValueExpr:
+		if key, ok := parent.Key.(*ast.Ident); ok && parent.Value == lit {
+			return key.Name
+		}
+
+	case *ast.AssignStmt:
+		for i, rhs := range parent.Rhs {
+			if rhs != lit || i >= len(parent.Lhs) {
+				continue
+			}
+			if sel, ok := parent.Lhs[i].(*ast.SelectorExpr); ok {
+				return sel.Sel.Name
+			}
+		}
+	}
+	return ""
+}
 
 // checkForbiddenErrorCalls flags direct use of errors.New and fmt.Errorf
 // but exempts framework callbacks where plain error is required
-func checkForbiddenErrorCalls(reporter *nolint.Reporter, call *ast.CallExpr, _ map[string]string) {
+func checkForbiddenErrorCalls(reporter *nolint.Reporter, call *ast.CallExpr, fn funcContext) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -397,7 +521,7 @@ func checkForbiddenErrorCalls(reporter *nolint.Reporter, call *ast.CallExpr, _ m
 	if ident.Name == "fmt" && funcName == "Errorf" {
 		// Allow fmt.Errorf in functions that must return plain error
 		// (framework callbacks, interface implementations)
-		if !currentFunc.mustReturnPlainError {
+		if !fn.mustReturnPlainError {
 			reporter.Reportf(call.Pos(),
 				"avoid fmt.Errorf(); use humane.Wrap(err, message, advice...) or humane.New(message, advice...) instead")
 		}
@@ -407,8 +531,8 @@ func checkForbiddenErrorCalls(reporter *nolint.Reporter, call *ast.CallExpr, _ m
 // isFrameworkCallback checks if a function is a framework callback
 // where plain error returns are required
 func isFrameworkCallback(funcName string) bool {
-	// Check against known stdlib/framework interface methods
-	if stdlibInterfaceMethods[funcName] {
+	// Check against known framework callbacks
+	if callbackMethods[funcName] {
 		return true
 	}
 
@@ -12,3 +12,35 @@ func TestHumaneErrorAnalyzer(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, humaneerror.Analyzer, "a")
 }
+
+func TestHumaneErrorInterfaceSignatures(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, humaneerror.Analyzer, "signatures")
+}
+
+func TestHumaneErrorFunctionContext(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, humaneerror.Analyzer, "closures")
+}
+
+func TestHumaneErrorNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, humaneerror.Analyzer, "nolint")
+}
+
+func TestHumaneErrorAdviceValues(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, humaneerror.Analyzer, "advice")
+}
+
+func TestHumaneErrorNonActionableFlag(t *testing.T) {
+	if err := humaneerror.Analyzer.Flags.Set("non-actionable", "contact support"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = humaneerror.Analyzer.Flags.Set("non-actionable", "see underlying error,check error,something went wrong,an error occurred,failed,error:")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, humaneerror.Analyzer, "patterns")
+}
@@ -0,0 +1,58 @@
+package advice
+
+import (
+	humane "github.com/sierrasoftworks/humane-errors-go"
+)
+
+const (
+	adviceCheckConn = "check error"
+	adviceRestart   = "Restart the agent with --reset-state to rebuild the cache"
+	advicePrefix    = "Something went "
+)
+
+var connAdvice = []string{"Verify the database host in DATABASE_URL is reachable"}
+
+// Bad: the constant advice is not actionable
+func ConstAdvice(msg string) humane.Error {
+	return humane.New(msg, adviceCheckConn) // want `advice "check error" may not be actionable`
+}
+
+// Bad: constant expressions are resolved too
+func ConcatenatedAdvice(msg string) humane.Error {
+	return humane.New(msg, advicePrefix+"wrong") // want `advice "Something went wrong" may not be actionable`
+}
+
+// Good: actionable constant advice
+func GoodConstAdvice(err error) humane.Error {
+	return humane.Wrap(err, "loading cache", adviceRestart)
+}
+
+// Good: advice spread from a slice
+func SpreadAdvice(err error) humane.Error {
+	return humane.Wrap(err, "connecting", connAdvice...)
+}
+
+// Good: advice spread from a variable slice with one argument
+func SpreadNew(msg string, advice []string) humane.Error {
+	return humane.New(msg, advice...)
+}
+
+// Bad: the spread slice is empty
+func EmptySpread(msg string) humane.Error {
+	return humane.New(msg, []string{}...) // want `humane.New\(\) should include at least one advice string`
+}
+
+// Bad: the spread slice is nil
+func NilSpread(err error) humane.Error {
+	return humane.Wrap(err, "connecting", nil...) // want `humane.Wrap\(\) should include at least one advice string`
+}
+
+// Bad: elements of a spread literal are checked
+func SpreadLiteral(msg string) humane.Error {
+	return humane.New(msg, []string{"an error occurred"}...) // want `advice "an error occurred" may not be actionable`
+}
+
+// Bad: literal advice is still checked
+func LiteralAdvice(err error) humane.Error {
+	return humane.Wrap(err, "connecting", "see underlying error") // want `advice "see underlying error" may not be actionable`
+}
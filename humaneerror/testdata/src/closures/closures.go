@@ -0,0 +1,58 @@
+package closures
+
+import (
+	"fmt"
+
+	humane "github.com/sierrasoftworks/humane-errors-go"
+)
+
+type Command struct {
+	RunE func(args []string) error
+}
+
+type cli struct{}
+
+// Good: fmt.Errorf in a closure inside a framework callback keeps its context
+func (c *cli) RunE(args []string) error {
+	validate := func(arg string) error {
+		return fmt.Errorf("invalid argument %q", arg)
+	}
+	for _, arg := range args {
+		if err := validate(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bad: package-level initializer after a framework callback is not inside it
+var errUsage = fmt.Errorf("usage: tool <args>") // want `avoid fmt.Errorf\(\)`
+
+// Good: closure assigned to a cobra-style RunE field
+func NewCommand() *Command {
+	return &Command{
+		RunE: func(args []string) error {
+			return fmt.Errorf("not implemented: %v", args)
+		},
+	}
+}
+
+// Good: closure assigned to the field after construction
+func Configure(cmd *Command) {
+	cmd.RunE = func(args []string) error {
+		return fmt.Errorf("not implemented: %v", args)
+	}
+}
+
+// Bad: closure in a regular function
+func Process(items []string) humane.Error {
+	check := func(item string) error {
+		return fmt.Errorf("bad item %q", item) // want `avoid fmt.Errorf\(\)`
+	}
+	for _, item := range items {
+		if err := check(item); err != nil {
+			return humane.Wrap(err, "processing failed", "remove the invalid item and retry")
+		}
+	}
+	return nil
+}
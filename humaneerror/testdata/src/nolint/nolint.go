@@ -0,0 +1,18 @@
+package nolint
+
+// Reported: exported function returns plain error
+func Reported() error { // want `exported function "Reported" returns plain 'error'`
+	return nil
+}
+
+// Suppressed on the line above
+//
+//nolint:humaneerror // implements a third-party interface
+func Suppressed() error {
+	return nil
+}
+
+// Suppressed inline
+func Inline() error { //nolint:humaneerror // implements io.Closer semantics
+	return nil
+}
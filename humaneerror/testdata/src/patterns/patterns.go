@@ -0,0 +1,15 @@
+package patterns
+
+import (
+	humane "github.com/sierrasoftworks/humane-errors-go"
+)
+
+// Bad: matches the configured phrase
+func Configured(msg string) humane.Error {
+	return humane.New(msg, "Contact support") // want `advice "Contact support" may not be actionable`
+}
+
+// Good: the default phrases are replaced
+func Default(msg string) humane.Error {
+	return humane.New(msg, "check error")
+}
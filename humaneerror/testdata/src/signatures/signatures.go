@@ -0,0 +1,84 @@
+package signatures
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+)
+
+type File struct{}
+
+// Good: implements io.Closer
+func (f *File) Close() error {
+	return nil
+}
+
+// Good: implements io.Reader
+func (f *File) Read(p []uint8) (int, error) {
+	return 0, nil
+}
+
+// Good: implements io.WriterTo
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	return 0, nil
+}
+
+var _ io.ReadCloser = (*File)(nil)
+
+type Job struct{}
+
+// Bad: named like a component but not Run(ctx context.Context) error
+func (j *Job) Run(x int) error { // want `exported function "Run" returns plain 'error'`
+	return nil
+}
+
+// Bad: Close with arguments is not io.Closer
+func (j *Job) Close(force bool) error { // want `exported function "Close" returns plain 'error'`
+	return nil
+}
+
+// Bad: Read that does not take a buffer
+func (j *Job) Read(key string) (string, error) { // want `exported function "Read" returns plain 'error'`
+	return "", nil
+}
+
+type Component struct{}
+
+// Good: component lifecycle
+func (c *Component) Run(ctx context.Context) error {
+	return nil
+}
+
+type Level int
+
+// Good: implements flag.Value
+func (l *Level) Set(s string) error {
+	return nil
+}
+
+// Good: implements driver.Valuer
+func (l Level) Value() (driver.Value, error) {
+	return int64(l), nil
+}
+
+// Good: implements sql.Scanner
+func (l *Level) Scan(src interface{}) error {
+	return nil
+}
+
+type Settings struct{}
+
+// Bad: business setter, not flag.Value
+func (s *Settings) Set(key, value string) error { // want `exported function "Set" returns plain 'error'`
+	return nil
+}
+
+// Bad: functions without a receiver implement no interface
+func Close() error { // want `exported function "Close" returns plain 'error'`
+	return nil
+}
+
+// Good: cobra callbacks are exempt by name
+func RunE(args []string) error {
+	return nil
+}
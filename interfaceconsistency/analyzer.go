@@ -29,7 +29,7 @@ Interface-driven design enables testability and loose coupling.`
 var Analyzer = &analysis.Analyzer{
 	Name:     "interfaceconsistency",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -94,7 +94,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			filename := pass.Fset.Position(node.Pos()).Filename
 			isTestFile := strings.HasSuffix(filename, "_test.go")
 
-			checkConstructorReturnsInterface(reporter, node, interfaces)
+			checkConstructorReturnsInterface(reporter, pass, node)
 			checkDependencyInjection(reporter, node, isTestFile)
 		}
 	})
@@ -148,55 +148,63 @@ func isInterfaceType(pass *analysis.Pass, expr ast.Expr) bool {
 }
 
 // checkConstructorReturnsInterface ensures New* functions return interfaces when appropriate
-func checkConstructorReturnsInterface(reporter *nolint.Reporter, fn *ast.FuncDecl, interfaces map[string]*ast.TypeSpec) {
-	if fn.Name == nil {
+func checkConstructorReturnsInterface(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	if fn.Name == nil || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "New") {
 		return
 	}
 
-	name := fn.Name.Name
-	if !strings.HasPrefix(name, "New") {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
 		return
 	}
-
-	if fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+	results := obj.Type().(*types.Signature).Results()
+	if results.Len() == 0 {
 		return
 	}
 
-	// Get the type name being constructed
-	typeName := strings.TrimPrefix(name, "New")
-
-	// Check if there's a corresponding interface
-	interfaceName := typeName
-	possibleInterfaceNames := []string{
-		typeName,
-		typeName + "Interface",
-		"I" + typeName,
+	// Only constructors returning *T for a struct T of this package; one
+	// returning an interface already abstracts
+	ptr, ok := results.At(0).Type().(*types.Pointer)
+	if !ok {
+		return
 	}
-
-	hasInterface := false
-	for _, ifaceName := range possibleInterfaceNames {
-		if _, exists := interfaces[ifaceName]; exists {
-			hasInterface = true
-			interfaceName = ifaceName
-			break
-		}
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	if !ok || named.Obj().Pkg() != pass.Pkg || named.TypeParams().Len() > 0 {
+		return
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return
 	}
 
-	if !hasInterface {
-		return // No interface defined, that's a separate concern
+	implemented := implementedInterfaces(pass.Pkg, ptr)
+	if len(implemented) != 1 {
+		return // None to suggest, or no way to tell which one callers need
 	}
 
-	// Check if the return type is the interface
-	for _, result := range fn.Type.Results.List {
-		resultType := types.ExprString(result.Type)
+	reporter.Reportf(fn.Pos(),
+		"constructor %q returns concrete type *%s; consider returning interface %q, which it implements, for better abstraction",
+		fn.Name.Name, named.Obj().Name(), implemented[0].Name())
+}
 
-		// If returning concrete type instead of interface
-		if strings.Contains(resultType, "*"+typeName) && !strings.Contains(resultType, interfaceName) {
-			reporter.Reportf(fn.Pos(),
-				"constructor %q returns concrete type; consider returning interface %q for better abstraction",
-				name, interfaceName)
+// implementedInterfaces returns the exported interfaces with at least one
+// method declared in pkg that t implements
+func implementedInterfaces(pkg *types.Package, t types.Type) []*types.TypeName {
+	var implemented []*types.TypeName
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !tn.Exported() || tn.IsAlias() {
+			continue
+		}
+		iface, ok := tn.Type().Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 {
+			continue
+		}
+		if types.Implements(t, iface) {
+			implemented = append(implemented, tn)
 		}
 	}
+	return implemented
 }
 
 // checkDependencyInjection ensures dependencies are injected, not created internally
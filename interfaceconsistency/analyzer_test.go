@@ -0,0 +1,14 @@
+package interfaceconsistency_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/interfaceconsistency"
+)
+
+func TestConstructorReturnsInterface(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, interfaceconsistency.Analyzer, "constructors")
+}
@@ -0,0 +1,92 @@
+package constructors
+
+import "context"
+
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Put(ctx context.Context, key, value string) error
+}
+
+// PostgresStore implements Store, though neither name contains the other
+type PostgresStore struct {
+	dsn string
+}
+
+func NewPostgresStore(dsn string) *PostgresStore { // want `constructor "NewPostgresStore" returns concrete type \*PostgresStore; consider returning interface "Store", which it implements, for better abstraction`
+	return &PostgresStore{dsn: dsn}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (string, error) { return "", nil }
+
+func (s *PostgresStore) Put(ctx context.Context, key, value string) error { return nil }
+
+type Notifier interface {
+	Notify(msg string) error
+}
+
+type Closer interface {
+	Close() error
+}
+
+// Mailer implements both Notifier and Closer; which one a caller needs is
+// not for the constructor to decide
+type Mailer struct{}
+
+func NewMailer() *Mailer {
+	return &Mailer{}
+}
+
+func (m *Mailer) Notify(msg string) error { return nil }
+
+func (m *Mailer) Close() error { return nil }
+
+// Hook implements only Notifier
+type Hook struct {
+	url string
+}
+
+func NewHook(url string) (*Hook, error) { // want `constructor "NewHook" returns concrete type \*Hook; consider returning interface "Notifier", which it implements, for better abstraction`
+	return &Hook{url: url}, nil
+}
+
+func (h *Hook) Notify(msg string) error { return nil }
+
+// Config implements no interface
+type Config struct {
+	Name string
+}
+
+func NewConfig(name string) *Config {
+	return &Config{Name: name}
+}
+
+// Already returns the interface
+func NewMemoryNotifier() Notifier {
+	return &Hook{}
+}
+
+// ICache matches Cache by name, but Cache does not implement it
+type ICache interface {
+	Evict(key string)
+}
+
+type Cache struct{}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// cacheProxy implements only an unexported interface
+type cacher interface {
+	Cache() error
+}
+
+var _ cacher = (*cacheProxy)(nil)
+
+type cacheProxy struct{}
+
+func (c *cacheProxy) Cache() error { return nil }
+
+func NewCacheProxy() *cacheProxy {
+	return &cacheProxy{}
+}
@@ -2,8 +2,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"gopkg.in/yaml.v3"
@@ -12,21 +16,58 @@ import (
 // ConfigFileName is the default configuration file name.
 const ConfigFileName = ".golint-sl.yaml"
 
+// EnvVar names the environment variable that points Load at a specific
+// configuration file instead of searching for one.
+const EnvVar = "GOLINT_SL_CONFIG"
+
 // Config represents the golint-sl configuration.
 type Config struct {
 	// Analyzers configures which analyzers are enabled/disabled.
 	// Use "default: false" to disable all by default, then enable specific ones.
 	// Use "default: true" (or omit) to enable all by default, then disable specific ones.
 	Analyzers map[string]bool `yaml:"analyzers"`
+
+	// AnalyzerSettings overrides analyzer flags, keyed by analyzer name and
+	// then flag name, e.g. functionsize: { warn: 100, error: 150 }.
+	// Settings that are not specified keep the analyzer's default.
+	AnalyzerSettings map[string]map[string]any `yaml:"analyzer-settings"`
+
+	// Paths overrides Analyzers for files matching a glob pattern, relative
+	// to Root, e.g. "pkg/sdk/**": { humaneerror: false }. A pattern matches
+	// a file or any directory containing it, and "**" matches any number of
+	// directories. When several patterns match, the longest one that
+	// mentions the analyzer, or sets "default", wins.
+	Paths map[string]map[string]bool `yaml:"paths"`
+
+	// Root is the directory Paths patterns are relative to. LoadFrom sets it
+	// to the directory of the config file.
+	Root string `yaml:"-"`
+
+	// Path is the file the configuration was loaded from, or empty when no
+	// config file was found.
+	Path string `yaml:"-"`
+
+	// OptIn names analyzers that only run when enabled by name in Analyzers;
+	// "default: true" does not enable them. It is set by the caller, not
+	// read from the file.
+	OptIn map[string]bool `yaml:"-"`
 }
 
-// Load attempts to load configuration from .golint-sl.yaml in the current
-// directory or any parent directory up to the filesystem root.
+// Load loads the configuration file named by the GOLINT_SL_CONFIG environment
+// variable, or else the first .golint-sl.yaml found in the current directory
+// or its parents, up to the filesystem root or the directory containing
+// go.mod. Without a config file, all analyzers are enabled.
 func Load() (*Config, error) {
-	path, err := findConfigFile()
+	if path := os.Getenv(EnvVar); path != "" {
+		return LoadFrom(path)
+	}
+
+	dir, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
+
+	path := findConfigFile(dir)
 	if path == "" {
 		// No config file found, return default config
 		return &Config{
@@ -38,15 +79,15 @@ func Load() (*Config, error) {
 }
 
 // LoadFrom loads configuration from the specified path.
-func LoadFrom(path string) (*Config, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path comes from findConfigFile which validates it's within the project tree
+func LoadFrom(file string) (*Config, error) {
+	data, err := os.ReadFile(file) //nolint:gosec // G304: path comes from findConfigFile, GOLINT_SL_CONFIG, or -config, all chosen by the user
 	if err != nil {
 		return nil, err
 	}
 
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", file, err)
 	}
 
 	// Ensure Analyzers map exists
@@ -54,33 +95,49 @@ func LoadFrom(path string) (*Config, error) {
 		cfg.Analyzers = map[string]bool{"default": true}
 	}
 
-	return &cfg, nil
-}
+	for pattern := range cfg.Paths {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s: paths: invalid pattern %q: %w", file, pattern, err)
+		}
+	}
 
-// findConfigFile searches for .golint-sl.yaml starting from the current
-// directory and walking up to parent directories.
-func findConfigFile() (string, error) {
-	dir, err := os.Getwd()
+	root, err := filepath.Abs(filepath.Dir(file))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	cfg.Root = root
+	cfg.Path = file
+
+	return &cfg, nil
+}
 
+// findConfigFile searches for .golint-sl.yaml starting from dir and walking
+// up to parent directories. The search stops at the module root, the first
+// directory containing go.mod, so a config outside the module is not picked
+// up by accident. It returns "" if no config file is found.
+func findConfigFile(dir string) string {
 	for {
 		configPath := filepath.Join(dir, ConfigFileName)
 		if _, err := os.Stat(configPath); err == nil {
-			return configPath, nil
+			return configPath
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			// Reached the module root
+			return ""
 		}
 
 		parent := filepath.Dir(dir)
 		if parent == dir {
 			// Reached filesystem root
-			return "", nil
+			return ""
 		}
 		dir = parent
 	}
 }
 
 // FilterAnalyzers returns only the analyzers that are enabled according to the config.
+// Analyzers disabled globally are kept when a Paths rule enables them.
 func (c *Config) FilterAnalyzers(all []*analysis.Analyzer) []*analysis.Analyzer {
 	if c == nil || c.Analyzers == nil {
 		return all
@@ -94,6 +151,11 @@ func (c *Config) FilterAnalyzers(all []*analysis.Analyzer) []*analysis.Analyzer
 
 	var enabled []*analysis.Analyzer
 	for _, a := range all {
+		if c.enabledByPath(a.Name) {
+			enabled = append(enabled, a)
+			continue
+		}
+
 		// Check if this specific analyzer has an override
 		if val, ok := c.Analyzers[a.Name]; ok {
 			if val {
@@ -103,8 +165,8 @@ func (c *Config) FilterAnalyzers(all []*analysis.Analyzer) []*analysis.Analyzer
 			continue
 		}
 
-		// Use default setting
-		if defaultEnabled {
+		// Use default setting, unless the analyzer must be enabled by name
+		if defaultEnabled && !c.OptIn[a.Name] {
 			enabled = append(enabled, a)
 		}
 	}
@@ -123,6 +185,10 @@ func (c *Config) IsEnabled(name string) bool {
 		return val
 	}
 
+	if c.OptIn[name] {
+		return false
+	}
+
 	// Check default
 	if val, ok := c.Analyzers["default"]; ok {
 		return val
@@ -130,3 +196,249 @@ func (c *Config) IsEnabled(name string) bool {
 
 	return true
 }
+
+// IsEnabledAt checks if a specific analyzer is enabled for file. Paths rules
+// take precedence over the Analyzers section; files outside Root only use
+// the Analyzers section.
+func (c *Config) IsEnabledAt(name, file string) bool {
+	if c == nil || len(c.Paths) == 0 {
+		return c.IsEnabled(name)
+	}
+
+	rel, ok := c.relative(file)
+	if !ok {
+		return c.IsEnabled(name)
+	}
+
+	for _, pattern := range c.patterns() {
+		if !matchPath(pattern, rel) {
+			continue
+		}
+		if val, ok := c.pathRule(pattern, name); ok {
+			return val
+		}
+	}
+
+	return c.IsEnabled(name)
+}
+
+// ApplyPaths wraps the analyzers that Paths rules mention, directly or with
+// "default", so they drop diagnostics in files where they are disabled.
+// Packages whose files are all disabled are not analyzed at all, unless
+// other analyzers need the result or the analyzer exports facts, which the
+// packages importing them rely on. The analyzers passed in are not modified.
+func (c *Config) ApplyPaths(analyzers []*analysis.Analyzer) []*analysis.Analyzer {
+	if c == nil || len(c.Paths) == 0 {
+		return analyzers
+	}
+
+	result := make([]*analysis.Analyzer, len(analyzers))
+	for i, a := range analyzers {
+		result[i] = a
+		if c.mentionedByPath(a.Name) {
+			result[i] = c.wrap(a)
+		}
+	}
+	return result
+}
+
+// wrap returns a copy of a whose Run filters diagnostics by file
+func (c *Config) wrap(a *analysis.Analyzer) *analysis.Analyzer {
+	wrapped := *a
+	run := a.Run
+	wrapped.Run = func(pass *analysis.Pass) (any, error) {
+		if a.ResultType == nil && len(a.FactTypes) == 0 && !c.enabledInAny(a.Name, pass) {
+			return nil, nil
+		}
+
+		report := pass.Report
+		pass.Report = func(d analysis.Diagnostic) {
+			if file := pass.Fset.File(d.Pos); file != nil && !c.IsEnabledAt(a.Name, file.Name()) {
+				return
+			}
+			report(d)
+		}
+		return run(pass)
+	}
+	return &wrapped
+}
+
+// enabledInAny checks if the analyzer is enabled for any file of the package
+func (c *Config) enabledInAny(name string, pass *analysis.Pass) bool {
+	for _, f := range pass.Files {
+		if c.IsEnabledAt(name, pass.Fset.File(f.Pos()).Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// enabledByPath checks if any Paths rule enables the analyzer
+func (c *Config) enabledByPath(name string) bool {
+	for pattern := range c.Paths {
+		if val, ok := c.pathRule(pattern, name); ok && val {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionedByPath checks if any Paths rule applies to the analyzer
+func (c *Config) mentionedByPath(name string) bool {
+	for pattern := range c.Paths {
+		if _, ok := c.pathRule(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pathRule returns the setting a Paths pattern has for the analyzer, by name
+// or through "default"
+func (c *Config) pathRule(pattern, name string) (bool, bool) {
+	rules := c.Paths[pattern]
+	if val, ok := rules[name]; ok {
+		return val, true
+	}
+	if val, ok := rules["default"]; ok && !(val && c.OptIn[name]) {
+		return val, true
+	}
+	return false, false
+}
+
+// patterns returns the Paths patterns, longest first and sorted within
+// the same length, so the most specific pattern is tried first
+func (c *Config) patterns() []string {
+	patterns := make([]string, 0, len(c.Paths))
+	for pattern := range c.Paths {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	return patterns
+}
+
+// relative returns file relative to Root in slash form, or false if file is
+// outside Root
+func (c *Config) relative(file string) (string, bool) {
+	root := c.Root
+	if root == "" {
+		root = "."
+	}
+	if filepath.IsAbs(file) {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+	}
+
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// matchPath checks if pattern matches the slash-separated path rel or any
+// directory containing it
+func matchPath(pattern, rel string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	parts := strings.Split(rel, "/")
+	for i := len(parts); i > 0; i-- {
+		if matchParts(patternParts, parts[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchParts matches path elements against pattern elements, where "**"
+// matches zero or more elements
+func matchParts(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchParts(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchParts(pattern[1:], parts[1:])
+}
+
+// ApplySettings sets analyzer flags from the analyzer-settings section.
+// Unknown analyzer names are ignored, like in the analyzers section. Unknown
+// settings and values the flag cannot parse are errors, so a typo in a
+// threshold does not silently fall back to the default.
+func (c *Config) ApplySettings(all []*analysis.Analyzer) error {
+	if c == nil || len(c.AnalyzerSettings) == 0 {
+		return nil
+	}
+
+	byName := make(map[string]*analysis.Analyzer, len(all))
+	for _, a := range all {
+		byName[a.Name] = a
+	}
+
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(c.AnalyzerSettings))
+	for name := range c.AnalyzerSettings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a, ok := byName[name]
+		if !ok {
+			// Forward compatibility with configs written for newer versions
+			continue
+		}
+
+		settings := c.AnalyzerSettings[name]
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if a.Flags.Lookup(key) == nil {
+				return fmt.Errorf("analyzer-settings: analyzer %q has no setting %q", name, key)
+			}
+			if err := a.Flags.Set(key, settingValue(settings[key])); err != nil {
+				return fmt.Errorf("analyzer-settings: %s.%s: %w", name, key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// settingValue renders a YAML value as a flag value; lists become
+// comma-separated strings
+func settingValue(v any) string {
+	list, ok := v.([]any)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+
+	parts := make([]string, len(list))
+	for i, item := range list {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"flag"
+	"go/ast"
+	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"golang.org/x/tools/go/analysis"
@@ -61,6 +65,22 @@ func TestFilterAnalyzers(t *testing.T) {
 			},
 			want: []string{"analyzer1", "analyzer3"},
 		},
+		{
+			name: "opt-in analyzer skipped by default",
+			config: &Config{
+				Analyzers: map[string]bool{"default": true},
+				OptIn:     map[string]bool{"analyzer2": true},
+			},
+			want: []string{"analyzer1", "analyzer3"},
+		},
+		{
+			name: "opt-in analyzer enabled by name",
+			config: &Config{
+				Analyzers: map[string]bool{"default": false, "analyzer2": true},
+				OptIn:     map[string]bool{"analyzer2": true},
+			},
+			want: []string{"analyzer2"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -116,6 +136,15 @@ func TestIsEnabled(t *testing.T) {
 			analyzer:    "other",
 			wantEnabled: false,
 		},
+		{
+			name: "opt-in ignores default",
+			config: &Config{
+				Analyzers: map[string]bool{"default": true},
+				OptIn:     map[string]bool{"myanalyzer": true},
+			},
+			analyzer:    "myanalyzer",
+			wantEnabled: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,3 +186,455 @@ func TestLoadFrom(t *testing.T) {
 		t.Errorf("todotracker = %v, want false", cfg.Analyzers["todotracker"])
 	}
 }
+
+// newThresholdAnalyzer returns an analyzer with warn/error flags defaulting to 80/120
+func newThresholdAnalyzer(name string) *analysis.Analyzer {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Int("warn", 80, "lines to trigger a warning")
+	fs.Int("error", 120, "lines to trigger an error")
+	fs.String("keys", "", "comma-separated keys")
+	return &analysis.Analyzer{Name: name, Flags: *fs}
+}
+
+func TestApplySettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]map[string]any
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "no settings keeps defaults",
+			settings: nil,
+			want:     map[string]string{"warn": "80", "error": "120", "keys": ""},
+		},
+		{
+			name: "partial override keeps other defaults",
+			settings: map[string]map[string]any{
+				"functionsize": {"warn": 100},
+			},
+			want: map[string]string{"warn": "100", "error": "120", "keys": ""},
+		},
+		{
+			name: "full override",
+			settings: map[string]map[string]any{
+				"functionsize": {"warn": 100, "error": 150},
+			},
+			want: map[string]string{"warn": "100", "error": "150", "keys": ""},
+		},
+		{
+			name: "lists become comma separated",
+			settings: map[string]map[string]any{
+				"functionsize": {"keys": []any{"a", "b"}},
+			},
+			want: map[string]string{"warn": "80", "error": "120", "keys": "a,b"},
+		},
+		{
+			name: "unknown analyzer is ignored",
+			settings: map[string]map[string]any{
+				"nosuchanalyzer": {"warn": 100},
+			},
+			want: map[string]string{"warn": "80", "error": "120", "keys": ""},
+		},
+		{
+			name: "unknown setting",
+			settings: map[string]map[string]any{
+				"functionsize": {"max-depth": 4},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid value",
+			settings: map[string]map[string]any{
+				"functionsize": {"warn": "lots"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newThresholdAnalyzer("functionsize")
+			cfg := &Config{AnalyzerSettings: tt.settings}
+
+			err := cfg.ApplySettings([]*analysis.Analyzer{a})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplySettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for key, want := range tt.want {
+				if got := a.Flags.Lookup(key).Value.String(); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFromAnalyzerSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".golint-sl.yaml")
+
+	configContent := `analyzer-settings: { functionsize: { warn: 100, error: 150 }, nestingdepth: { max-depth: 4 } }
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+
+	fs := flag.NewFlagSet("nestingdepth", flag.ExitOnError)
+	fs.Int("max-depth", 3, "maximum allowed nesting depth")
+	nesting := &analysis.Analyzer{Name: "nestingdepth", Flags: *fs}
+	size := newThresholdAnalyzer("functionsize")
+
+	if err := cfg.ApplySettings([]*analysis.Analyzer{size, nesting}); err != nil {
+		t.Fatalf("ApplySettings() error = %v", err)
+	}
+
+	if got := size.Flags.Lookup("warn").Value.String(); got != "100" {
+		t.Errorf("functionsize warn = %q, want 100", got)
+	}
+	if got := size.Flags.Lookup("error").Value.String(); got != "150" {
+		t.Errorf("functionsize error = %q, want 150", got)
+	}
+	if got := nesting.Flags.Lookup("max-depth").Value.String(); got != "4" {
+		t.Errorf("nestingdepth max-depth = %q, want 4", got)
+	}
+	if !cfg.IsEnabled("functionsize") {
+		t.Errorf("analyzers default should stay enabled when only analyzer-settings is given")
+	}
+}
+
+func TestIsEnabledAt(t *testing.T) {
+	cfg := &Config{
+		Analyzers: map[string]bool{
+			"default":     true,
+			"reconciler":  false,
+			"todotracker": false,
+		},
+		Paths: map[string]map[string]bool{
+			"internal/operator/**":     {"reconciler": true},
+			"internal/operator/legacy": {"reconciler": false},
+			"pkg/sdk/**":               {"humaneerror": false},
+			"internal/gen":             {"default": false},
+			"internal/*/fixtures":      {"todotracker": true},
+		},
+		OptIn: map[string]bool{"optin": true},
+		Root:  "/repo",
+	}
+
+	tests := []struct {
+		name     string
+		analyzer string
+		file     string
+		want     bool
+	}{
+		{"path rule enables globally disabled analyzer", "reconciler", "/repo/internal/operator/controller.go", true},
+		{"path rule matches nested directories", "reconciler", "/repo/internal/operator/api/v1/types.go", true},
+		{"global setting applies outside path rules", "reconciler", "/repo/cmd/main.go", false},
+		{"longest matching pattern wins", "reconciler", "/repo/internal/operator/legacy/old.go", false},
+		{"path rule disables globally enabled analyzer", "humaneerror", "/repo/pkg/sdk/client.go", false},
+		{"path rule for other analyzer falls back to global", "errorwrap", "/repo/pkg/sdk/client.go", true},
+		{"pattern without glob matches directory contents", "errorwrap", "/repo/internal/gen/zz_generated.go", false},
+		{"named rule beats shorter default rule", "todotracker", "/repo/internal/gen/fixtures/a.go", true},
+		{"default rule does not enable opt-in analyzers", "optin", "/repo/internal/operator/controller.go", false},
+		{"files outside root use global setting", "humaneerror", "/other/pkg/sdk/client.go", true},
+		{"relative file names are relative to root", "humaneerror", "pkg/sdk/client.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.IsEnabledAt(tt.analyzer, tt.file); got != tt.want {
+				t.Errorf("IsEnabledAt(%q, %q) = %v, want %v", tt.analyzer, tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterAnalyzersPaths(t *testing.T) {
+	mockAnalyzers := []*analysis.Analyzer{
+		{Name: "analyzer1"},
+		{Name: "analyzer2"},
+		{Name: "analyzer3"},
+	}
+
+	cfg := &Config{
+		Analyzers: map[string]bool{"default": false, "analyzer1": true},
+		Paths: map[string]map[string]bool{
+			"internal/operator/**": {"analyzer2": true, "analyzer1": false},
+			"pkg/**":               {"analyzer3": false},
+		},
+	}
+
+	got := cfg.FilterAnalyzers(mockAnalyzers)
+	want := []string{"analyzer1", "analyzer2"}
+	if len(got) != len(want) {
+		t.Fatalf("FilterAnalyzers() returned %d analyzers, want %d", len(got), len(want))
+	}
+	for i, a := range got {
+		if a.Name != want[i] {
+			t.Errorf("FilterAnalyzers()[%d].Name = %q, want %q", i, a.Name, want[i])
+		}
+	}
+}
+
+func TestApplyPaths(t *testing.T) {
+	fset := token.NewFileSet()
+	operator := fset.AddFile("/repo/internal/operator/controller.go", -1, 100)
+	sdk := fset.AddFile("/repo/pkg/sdk/client.go", -1, 100)
+
+	runs := 0
+	a := &analysis.Analyzer{
+		Name: "analyzer1",
+		Run: func(pass *analysis.Pass) (any, error) {
+			runs++
+			for _, f := range pass.Files {
+				pass.Report(analysis.Diagnostic{Pos: f.Pos(), Message: fset.File(f.Pos()).Name()})
+			}
+			return nil, nil
+		},
+	}
+	untouched := &analysis.Analyzer{Name: "analyzer2"}
+
+	cfg := &Config{
+		Analyzers: map[string]bool{"default": true},
+		Paths:     map[string]map[string]bool{"pkg/sdk": {"analyzer1": false}},
+		Root:      "/repo",
+	}
+
+	wrapped := cfg.ApplyPaths([]*analysis.Analyzer{a, untouched})
+	if wrapped[0] == a {
+		t.Fatalf("ApplyPaths() did not wrap analyzer1")
+	}
+	if wrapped[1] != untouched {
+		t.Errorf("ApplyPaths() wrapped analyzer2, which no path rule mentions")
+	}
+
+	run := func(files ...*token.File) []string {
+		var reported []string
+		pass := &analysis.Pass{
+			Fset:   fset,
+			Report: func(d analysis.Diagnostic) { reported = append(reported, d.Message) },
+		}
+		for _, f := range files {
+			pass.Files = append(pass.Files, &ast.File{Package: token.Pos(f.Base())})
+		}
+		if _, err := wrapped[0].Run(pass); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		return reported
+	}
+
+	got := run(operator, sdk)
+	if len(got) != 1 || got[0] != operator.Name() {
+		t.Errorf("reported %v, want only %s", got, operator.Name())
+	}
+
+	if got := run(sdk); len(got) != 0 || runs != 1 {
+		t.Errorf("package with only disabled files: reported %v after %d runs, want no run", got, runs)
+	}
+}
+
+// ioFact is an analysis.Fact for TestApplyPathsFacts
+type ioFact struct{}
+
+func (*ioFact) AFact() {}
+
+func TestApplyPathsFacts(t *testing.T) {
+	fset := token.NewFileSet()
+	client := fset.AddFile("/repo/client/client.go", -1, 100)
+
+	runs := 0
+	a := &analysis.Analyzer{
+		Name:      "facts",
+		FactTypes: []analysis.Fact{new(ioFact)},
+		Run: func(pass *analysis.Pass) (any, error) {
+			runs++
+			pass.Report(analysis.Diagnostic{Pos: pass.Files[0].Pos(), Message: "reported"})
+			return nil, nil
+		},
+	}
+
+	// Disabled globally and enabled under app, the analyzer still runs on
+	// client, which app imports, to export its facts
+	cfg := &Config{
+		Analyzers: map[string]bool{"facts": false},
+		Paths:     map[string]map[string]bool{"app/**": {"facts": true}},
+		Root:      "/repo",
+	}
+
+	var reported []string
+	pass := &analysis.Pass{
+		Fset:   fset,
+		Files:  []*ast.File{{Package: token.Pos(client.Base())}},
+		Report: func(d analysis.Diagnostic) { reported = append(reported, d.Message) },
+	}
+	if _, err := cfg.ApplyPaths([]*analysis.Analyzer{a})[0].Run(pass); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if runs != 1 || len(reported) != 0 {
+		t.Errorf("ran %d times and reported %v, want one run without diagnostics", runs, reported)
+	}
+}
+
+func TestLoadFromPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".golint-sl.yaml")
+
+	configContent := `paths:
+  "pkg/sdk/**":
+    humaneerror: false
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+
+	if cfg.Root != tmpDir {
+		t.Errorf("Root = %q, want %q", cfg.Root, tmpDir)
+	}
+	if cfg.IsEnabledAt("humaneerror", filepath.Join(tmpDir, "pkg", "sdk", "client.go")) {
+		t.Errorf("humaneerror should be disabled under pkg/sdk")
+	}
+	if !cfg.IsEnabledAt("humaneerror", filepath.Join(tmpDir, "cmd", "main.go")) {
+		t.Errorf("humaneerror should stay enabled outside pkg/sdk")
+	}
+
+	if err := os.WriteFile(configPath, []byte("paths: { \"pkg/[sdk\": { humaneerror: false } }\n"), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if _, err := LoadFrom(configPath); err == nil {
+		t.Errorf("LoadFrom() with a malformed pattern should fail")
+	}
+}
+
+// writeFile creates path with content, including missing parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ConfigFileName), "analyzers: {}\n")
+	writeFile(t, filepath.Join(root, "repo", "go.mod"), "module example.com/repo\n")
+	writeFile(t, filepath.Join(root, "repo", "svc", ConfigFileName), "analyzers: {}\n")
+	if err := os.MkdirAll(filepath.Join(root, "repo", "svc", "internal", "handler"), 0o750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "repo", "pkg", "sdk"), 0o750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{"config in the directory itself", filepath.Join(root, "repo", "svc"), filepath.Join(root, "repo", "svc", ConfigFileName)},
+		{"config in a parent directory", filepath.Join(root, "repo", "svc", "internal", "handler"), filepath.Join(root, "repo", "svc", ConfigFileName)},
+		{"search stops at the module root", filepath.Join(root, "repo", "pkg", "sdk"), ""},
+		{"search without a module reaches the parents", root, filepath.Join(root, ConfigFileName)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findConfigFile(tt.dir); got != tt.want {
+				t.Errorf("findConfigFile(%q) = %q, want %q", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/repo\n")
+	writeFile(t, filepath.Join(root, ConfigFileName), "analyzers:\n  todotracker: false\n")
+	if err := os.MkdirAll(filepath.Join(root, "internal", "store"), 0o750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	t.Chdir(filepath.Join(root, "internal", "store"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Path != filepath.Join(root, ConfigFileName) {
+		t.Errorf("Path = %q, want the config in the module root", cfg.Path)
+	}
+	if cfg.IsEnabled("todotracker") {
+		t.Errorf("todotracker should be disabled by the config in the module root")
+	}
+}
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/repo\n")
+	t.Chdir(root)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Path != "" {
+		t.Errorf("Path = %q, want empty without a config file", cfg.Path)
+	}
+	if !cfg.IsEnabled("todotracker") {
+		t.Errorf("all analyzers should be enabled without a config file")
+	}
+}
+
+func TestLoadEnvOverride(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/repo\n")
+	writeFile(t, filepath.Join(root, ConfigFileName), "analyzers:\n  todotracker: false\n")
+	override := filepath.Join(t.TempDir(), "ci.yaml")
+	writeFile(t, override, "analyzers:\n  nilcheck: false\n")
+	t.Chdir(root)
+	t.Setenv(EnvVar, override)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Path != override {
+		t.Errorf("Path = %q, want %q", cfg.Path, override)
+	}
+	if cfg.IsEnabled("nilcheck") || !cfg.IsEnabled("todotracker") {
+		t.Errorf("%s should replace the discovered config file", EnvVar)
+	}
+
+	t.Setenv(EnvVar, filepath.Join(root, "missing.yaml"))
+	if _, err := Load(); err == nil {
+		t.Errorf("Load() with %s naming a missing file should fail", EnvVar)
+	}
+}
+
+func TestLoadFromInvalidYAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigFileName)
+	writeFile(t, configPath, "analyzers: [\n")
+
+	_, err := LoadFrom(configPath)
+	if err == nil {
+		t.Fatalf("LoadFrom() with invalid YAML should fail")
+	}
+	if !strings.Contains(err.Error(), configPath) {
+		t.Errorf("LoadFrom() error = %q, want it to mention %s", err, configPath)
+	}
+}
@@ -0,0 +1,134 @@
+// Package ctxfix builds suggested fixes that pass a context.Context to calls
+// written without one.
+package ctxfix
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// NewRequestWithContext returns a fix rewriting call, a call of
+// http.NewRequest, to http.NewRequestWithContext. The context named ctxName
+// is passed as the first argument. Without one, context.TODO() is passed and
+// the context import is added to file when it is missing.
+func NewRequestWithContext(file *ast.File, call *ast.CallExpr, ctxName string) analysis.SuggestedFix {
+	fix := analysis.SuggestedFix{Message: "Replace with http.NewRequestWithContext"}
+
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+			Pos:     sel.Sel.Pos(),
+			End:     sel.Sel.End(),
+			NewText: []byte("NewRequestWithContext"),
+		})
+	}
+
+	arg := ctxName
+	if arg == "" {
+		name, edit := importContext(file)
+		if edit != nil {
+			fix.TextEdits = append(fix.TextEdits, *edit)
+		}
+		arg = name + ".TODO()"
+	}
+
+	fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+		Pos:     call.Lparen + 1,
+		End:     call.Lparen + 1,
+		NewText: []byte(arg + ", "),
+	})
+	return fix
+}
+
+// ContextParam returns the name of the first context.Context parameter of
+// the innermost function in stack that has a usable one, or "" if none does.
+func ContextParam(info *types.Info, stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		var ft *ast.FuncType
+		switch node := stack[i].(type) {
+		case *ast.FuncDecl:
+			ft = node.Type
+		case *ast.FuncLit:
+			ft = node.Type
+		default:
+			continue
+		}
+		if name := FuncContextParam(info, ft); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// FuncContextParam returns the name of the first context.Context parameter
+// of ft. Unnamed and blank parameters cannot be passed on and are skipped.
+func FuncContextParam(info *types.Info, ft *ast.FuncType) string {
+	if ft.Params == nil {
+		return ""
+	}
+	for _, field := range ft.Params.List {
+		if !isContext(info.TypeOf(field.Type)) {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				return name.Name
+			}
+		}
+	}
+	return ""
+}
+
+// isContext checks if t is context.Context
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// importContext returns the name file refers to the context package by and,
+// if file does not import it yet, the edit adding the import
+func importContext(file *ast.File) (string, *analysis.TextEdit) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != "context" {
+			continue
+		}
+		if spec.Name != nil && spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, nil
+		}
+		if spec.Name == nil {
+			return "context", nil
+		}
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return "context", &analysis.TextEdit{
+				Pos:     gen.Lparen + 1,
+				End:     gen.Lparen + 1,
+				NewText: []byte("\n\t\"context\""),
+			}
+		}
+		return "context", &analysis.TextEdit{
+			Pos:     gen.Pos(),
+			End:     gen.Pos(),
+			NewText: []byte("import \"context\"\n"),
+		}
+	}
+
+	return "context", &analysis.TextEdit{
+		Pos:     file.Name.End(),
+		End:     file.Name.End(),
+		NewText: []byte("\n\nimport \"context\""),
+	}
+}
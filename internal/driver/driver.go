@@ -0,0 +1,430 @@
+// Package driver runs analyzers over packages loaded with go/packages and
+// prints their diagnostics as text, JSON, or SARIF, or applies their
+// suggested fixes.
+//
+// It replaces multichecker.Main for the standalone binary so that findings
+// can be fed to code review bots and GitHub code scanning. The command line
+// stays compatible with multichecker: each analyzer gets an enable flag
+// (-nilcheck, -nilcheck=false) and its own flags are prefixed with its name
+// (-functionsize.warn=100).
+package driver
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/unitchecker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/spechtlabs/golint-sl/internal/generated"
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitClean       = 0 // no diagnostics
+	ExitDiagnostics = 1 // at least one diagnostic was reported
+	ExitError       = 2 // invalid flags, package load errors, or analyzer failures
+)
+
+// Diagnostic is a single finding flattened for output.
+type Diagnostic struct {
+	Analyzer string `json:"analyzer"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+
+	// Package is the import path of the package the diagnostic was
+	// reported in. It is only used for statistics.
+	Package string `json:"-"`
+}
+
+// Diagnostic severities. The analysis framework has no notion of severity;
+// the field exists so consumers do not have to special-case golint-sl.
+// Diagnostics are warnings unless the analyzer sets CategoryAdvisory.
+const (
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+// CategoryAdvisory is the analysis.Diagnostic category of findings that are
+// suggestions rather than defects. They are reported with SeverityNote.
+const CategoryAdvisory = nolint.CategoryAdvisory
+
+// Options controls a single Run.
+type Options struct {
+	// Format selects the output format.
+	Format Format
+
+	// Tests includes test files and test packages in the analysis.
+	Tests bool
+
+	// Output receives the formatted diagnostics.
+	Output io.Writer
+
+	// Dir is the directory patterns are resolved in; empty means the
+	// current directory. File names in the output are relative to it.
+	Dir string
+
+	// UnusedNolint reports nolint directives that did not suppress any
+	// diagnostic.
+	UnusedNolint bool
+
+	// Stats prints the number of diagnostics per analyzer and package
+	// instead of the diagnostics, in Format text or json. A stats run
+	// exits ExitClean even if there are diagnostics.
+	Stats bool
+
+	// Fix applies the first suggested fix of each diagnostic to the source
+	// files and prints the number of fixes per analyzer instead of the
+	// diagnostics. Fixes that overlap an earlier one are skipped, as are
+	// fixes to files outside the main module, vendored, or generated files.
+	// A fix run exits ExitClean even if there are diagnostics.
+	Fix bool
+
+	// Diff prints the changes Fix would make as unified diffs without
+	// writing any file. It takes precedence over Fix.
+	Diff bool
+
+	// Known lists every analyzer that could have run. Directives naming a
+	// known analyzer that was not selected are not reported as unused, and
+	// the golint-sl catch-all is only judged when all known analyzers ran.
+	// Nil means the analyzers passed to Run.
+	Known []*analysis.Analyzer
+}
+
+// unusedNolint describes unused directive diagnostics for SARIF rules
+var unusedNolint = &analysis.Analyzer{
+	Name: "nolint",
+	Doc:  "report nolint directives that do not suppress any diagnostic",
+}
+
+// Main parses the command line, runs the selected analyzers, and returns the
+// exit code for the command to exit with: ExitClean, ExitDiagnostics, or
+// ExitError.
+func Main(analyzers ...*analysis.Analyzer) int {
+	progname := filepath.Base(os.Args[0])
+	log.SetFlags(0)
+	log.SetPrefix(progname + ": ")
+
+	if err := analysis.Validate(analyzers); err != nil {
+		log.Print(err)
+		return ExitError
+	}
+
+	all := analyzers
+	format := flag.String("format", string(FormatText), "output format: text, json, or sarif")
+	tests := flag.Bool("test", true, "analyze test files and test packages")
+	unused := flag.Bool("unused-nolint", false, "report nolint directives that do not suppress any diagnostic")
+	stats := flag.Bool("stats", false, "print diagnostic counts per analyzer and package instead of the diagnostics, and exit 0")
+	statsFormat := flag.String("stats-format", string(FormatText), "output format of -stats: text or json")
+	fix := flag.Bool("fix", false, "apply suggested fixes and print the number of fixes per analyzer instead of the diagnostics, and exit 0")
+	diff := flag.Bool("diff", false, "print suggested fixes as unified diffs instead of the diagnostics without applying them, and exit 0")
+	enabled := registerFlags(flag.CommandLine, analyzers)
+	flag.Usage = func() { usage(progname, all) }
+	flag.Parse()
+
+	analyzers = selectAnalyzers(analyzers, enabled)
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage(progname, all)
+		return ExitError
+	}
+
+	if args[0] == "help" {
+		help(os.Stdout, analyzers, args[1:])
+		return ExitClean
+	}
+
+	// Invoked by go vet -vettool with a unit configuration file
+	if len(args) == 1 && strings.HasSuffix(args[0], ".cfg") {
+		// unitchecker.Run exits the process itself and never returns
+		unitchecker.Run(args[0], analyzers)
+		return ExitError
+	}
+
+	f, err := ParseFormat(*format)
+	if *stats {
+		f, err = ParseStatsFormat(*statsFormat)
+	}
+	if err == nil && *stats && (*fix || *diff) {
+		err = fmt.Errorf("-stats cannot be combined with -fix or -diff")
+	}
+	if err != nil {
+		log.Print(err)
+		return ExitError
+	}
+
+	return Run(args, analyzers, Options{
+		Format:       f,
+		Tests:        *tests,
+		Output:       os.Stdout,
+		UnusedNolint: *unused,
+		Stats:        *stats,
+		Fix:          *fix,
+		Diff:         *diff,
+		Known:        all,
+	})
+}
+
+// Run loads the packages matching patterns, applies the analyzers, and writes
+// the diagnostics to opts.Output. Errors are logged to stderr.
+func Run(patterns []string, analyzers []*analysis.Analyzer, opts Options) int {
+	cfg := &packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedModule, // modules bound the files -fix may change
+		Tests: opts.Tests,
+		Dir:   opts.Dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		log.Print(err)
+		return ExitError
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return ExitError
+	}
+
+	var usage *nolint.Usage
+	if opts.UnusedNolint {
+		usage = nolint.NewUsage()
+		nolint.Track(usage)
+		defer nolint.Track(nil)
+	}
+
+	graph, err := checker.Analyze(analyzers, pkgs, nil)
+	if err != nil {
+		log.Print(err)
+		return ExitError
+	}
+
+	diags, failed := collect(graph, opts.Dir)
+
+	rules := analyzers
+	if usage != nil && !failed {
+		diags = append(diags, unusedDirectives(pkgs, analyzers, opts, usage)...)
+		sortDiagnostics(diags)
+		rules = append(append([]*analysis.Analyzer(nil), analyzers...), unusedNolint)
+	}
+
+	if opts.Fix || opts.Diff {
+		if applyFixes(opts.Output, graph, opts.Dir, opts.Diff) || failed {
+			return ExitError
+		}
+		return ExitClean
+	}
+
+	if opts.Stats {
+		if err := WriteStats(opts.Output, opts.Format, Summarize(diags)); err != nil {
+			log.Print(err)
+			return ExitError
+		}
+		if failed {
+			return ExitError
+		}
+		return ExitClean
+	}
+
+	if err := Write(opts.Output, opts.Format, diags, rules); err != nil {
+		log.Print(err)
+		return ExitError
+	}
+
+	switch {
+	case failed:
+		return ExitError
+	case len(diags) > 0:
+		return ExitDiagnostics
+	default:
+		return ExitClean
+	}
+}
+
+// collect flattens the diagnostics of all root actions, dropping the
+// duplicates reported for a file that belongs to both a package and its test
+// variant. It reports whether any analyzer failed.
+func collect(graph *checker.Graph, dir string) ([]Diagnostic, bool) {
+	type key struct {
+		analyzer, file, message string
+		line, column            int
+	}
+	seen := make(map[key]bool)
+
+	var diags []Diagnostic
+	failed := false
+
+	for act := range graph.All() {
+		if !act.IsRoot {
+			continue
+		}
+		if act.Err != nil {
+			log.Printf("%s: %v", act.Analyzer.Name, act.Err)
+			failed = true
+			continue
+		}
+
+		for _, d := range act.Diagnostics {
+			posn := act.Package.Fset.Position(d.Pos)
+			diag := Diagnostic{
+				Analyzer: act.Analyzer.Name,
+				File:     relativePath(dir, posn.Filename),
+				Line:     posn.Line,
+				Column:   posn.Column,
+				Message:  d.Message,
+				Severity: severity(d),
+				Package:  act.Package.PkgPath,
+			}
+
+			k := key{diag.Analyzer, diag.File, diag.Message, diag.Line, diag.Column}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			diags = append(diags, diag)
+		}
+	}
+
+	sortDiagnostics(diags)
+
+	return diags, failed
+}
+
+// severity returns the output severity of d
+func severity(d analysis.Diagnostic) string {
+	if d.Category == CategoryAdvisory {
+		return SeverityNote
+	}
+	return SeverityWarning
+}
+
+// sortDiagnostics orders diagnostics by file, position, and analyzer
+func sortDiagnostics(diags []Diagnostic) {
+	sort.Slice(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Analyzer < b.Analyzer
+	})
+}
+
+// unusedDirectives reports the nolint directives in the root packages that
+// did not suppress any diagnostic. Names of other linters are ignored.
+func unusedDirectives(pkgs []*packages.Package, analyzers []*analysis.Analyzer, opts Options, usage *nolint.Usage) []Diagnostic {
+	known := opts.Known
+	if known == nil {
+		known = analyzers
+	}
+	ran := make(map[string]bool, len(analyzers))
+	for _, a := range analyzers {
+		ran[a.Name] = true
+	}
+	isKnown := make(map[string]bool, len(known))
+	for _, a := range known {
+		isKnown[a.Name] = true
+	}
+	allRan := len(analyzers) >= len(known)
+
+	// judged returns the golint-sl names of a directive, or nil if any of
+	// them belongs to an analyzer that did not run
+	judged := func(d *nolint.Directive) []string {
+		var names []string
+		for _, name := range d.Analyzers {
+			switch {
+			case name == nolint.CatchAll && !allRan:
+				return nil
+			case name == nolint.CatchAll, ran[name]:
+				names = append(names, name)
+			case isKnown[name]:
+				return nil
+			}
+		}
+		return names
+	}
+
+	seen := make(map[string]bool)
+	var diags []Diagnostic
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			if seen[filename] {
+				continue
+			}
+			seen[filename] = true
+
+			// Nothing is reported in generated files, so their directives
+			// never suppress anything
+			if generated.File(filename, file) {
+				continue
+			}
+
+			for _, d := range nolint.ParseFile(file, pkg.Fset).All() {
+				names := judged(d)
+				if len(names) == 0 || usage.Used(filename, d.Line) {
+					continue
+				}
+				diags = append(diags, Diagnostic{
+					Analyzer: unusedNolint.Name,
+					File:     relativePath(opts.Dir, filename),
+					Line:     d.Line,
+					Column:   d.Column,
+					Message:  fmt.Sprintf("nolint directive for %s is unused; remove it", describeNames(names)),
+					Severity: SeverityWarning,
+					Package:  pkg.PkgPath,
+				})
+			}
+		}
+	}
+
+	return diags
+}
+
+// describeNames formats the analyzer names of a directive for a message
+func describeNames(names []string) string {
+	if len(names) == 1 && names[0] == nolint.CatchAll {
+		return nolint.CatchAll
+	}
+	if len(names) == 1 {
+		return "analyzer " + names[0]
+	}
+	return "analyzers " + strings.Join(names, ", ")
+}
+
+// relativePath returns filename relative to dir (or the working directory)
+// when it lies beneath it, and filename unchanged otherwise
+func relativePath(dir, filename string) string {
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return filename
+		}
+		dir = wd
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filename
+	}
+	rel, err := filepath.Rel(abs, filename)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filename
+	}
+	return filepath.ToSlash(rel)
+}
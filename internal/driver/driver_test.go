@@ -0,0 +1,363 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+// flagged reports every function whose name starts with Flagged
+var flagged = &analysis.Analyzer{
+	Name: "flagged",
+	Doc:  "report functions named Flagged\n\nLonger description.",
+	Run: func(pass *analysis.Pass) (interface{}, error) {
+		reporter := nolint.NewReporter(pass)
+		for _, file := range pass.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && strings.HasPrefix(fn.Name.Name, "Flagged") {
+					reporter.Reportf(fn.Name.Pos(), "function %s is flagged", fn.Name.Name)
+				}
+			}
+		}
+		return nil, nil
+	},
+}
+
+// quiet reports nothing
+var quiet = &analysis.Analyzer{
+	Name: "quiet",
+	Doc:  "report nothing",
+	Run:  func(*analysis.Pass) (interface{}, error) { return nil, nil },
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"text", "json", "sarif", "SARIF"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") expected error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		format Format
+		check  func(t *testing.T, out []byte)
+	}{
+		{
+			format: FormatText,
+			check: func(t *testing.T, out []byte) {
+				want := "testdata/src/a/a.go:3:6: function Flagged is flagged\n"
+				if string(out) != want {
+					t.Errorf("got %q, want %q", out, want)
+				}
+			},
+		},
+		{
+			format: FormatJSON,
+			check: func(t *testing.T, out []byte) {
+				var diags []Diagnostic
+				if err := json.Unmarshal(out, &diags); err != nil {
+					t.Fatal(err)
+				}
+				want := Diagnostic{
+					Analyzer: "flagged",
+					File:     "testdata/src/a/a.go",
+					Line:     3,
+					Column:   6,
+					Message:  "function Flagged is flagged",
+					Severity: SeverityWarning,
+				}
+				if len(diags) != 1 || diags[0] != want {
+					t.Errorf("got %+v, want [%+v]", diags, want)
+				}
+			},
+		},
+		{
+			format: FormatSARIF,
+			check: func(t *testing.T, out []byte) {
+				var log sarifLog
+				if err := json.Unmarshal(out, &log); err != nil {
+					t.Fatal(err)
+				}
+				if log.Version != "2.1.0" || len(log.Runs) != 1 {
+					t.Fatalf("unexpected SARIF log: %s", out)
+				}
+				run := log.Runs[0]
+				rules := run.Tool.Driver.Rules
+				if len(rules) != 1 || rules[0].ID != "flagged" || rules[0].ShortDescription.Text != "report functions named Flagged" {
+					t.Errorf("unexpected rules: %+v", rules)
+				}
+				if len(run.Results) != 1 {
+					t.Fatalf("got %d results, want 1", len(run.Results))
+				}
+				res := run.Results[0]
+				loc := res.Locations[0].PhysicalLocation
+				if res.RuleID != "flagged" || res.Level != "warning" ||
+					loc.ArtifactLocation.URI != "testdata/src/a/a.go" || loc.Region.StartLine != 3 {
+					t.Errorf("unexpected result: %+v", res)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var out bytes.Buffer
+			code := Run([]string{"./testdata/src/a"}, []*analysis.Analyzer{flagged}, Options{
+				Format: tt.format,
+				Output: &out,
+			})
+			if code != ExitDiagnostics {
+				t.Errorf("exit code = %d, want %d", code, ExitDiagnostics)
+			}
+			tt.check(t, out.Bytes())
+		})
+	}
+}
+
+func TestRunClean(t *testing.T) {
+	var out bytes.Buffer
+	code := Run([]string{"./testdata/src/a"}, []*analysis.Analyzer{quiet}, Options{
+		Format: FormatJSON,
+		Output: &out,
+	})
+	if code != ExitClean {
+		t.Errorf("exit code = %d, want %d", code, ExitClean)
+	}
+	if got := strings.TrimSpace(out.String()); got != "[]" {
+		t.Errorf("got %q, want []", got)
+	}
+}
+
+func TestRunAdvisory(t *testing.T) {
+	advisory := &analysis.Analyzer{
+		Name: "advisory",
+		Doc:  "report functions named Flagged as advisory",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			for _, file := range pass.Files {
+				for _, decl := range file.Decls {
+					if fn, ok := decl.(*ast.FuncDecl); ok && strings.HasPrefix(fn.Name.Name, "Flagged") {
+						pass.Report(analysis.Diagnostic{Pos: fn.Name.Pos(), Category: CategoryAdvisory, Message: "advice"})
+					}
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	var out bytes.Buffer
+	Run([]string{"./testdata/src/a"}, []*analysis.Analyzer{advisory}, Options{
+		Format: FormatJSON,
+		Output: &out,
+	})
+
+	var diags []Diagnostic
+	if err := json.Unmarshal(out.Bytes(), &diags); err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 1 || diags[0].Severity != SeverityNote {
+		t.Errorf("got %+v, want one diagnostic with severity %q", diags, SeverityNote)
+	}
+}
+
+func TestRunLoadError(t *testing.T) {
+	var out bytes.Buffer
+	code := Run([]string{"./testdata/src/missing"}, []*analysis.Analyzer{flagged}, Options{
+		Format: FormatText,
+		Output: &out,
+	})
+	if code != ExitError {
+		t.Errorf("exit code = %d, want %d", code, ExitError)
+	}
+}
+
+func TestSelectAnalyzers(t *testing.T) {
+	a := &analysis.Analyzer{Name: "a"}
+	b := &analysis.Analyzer{Name: "b"}
+	c := &analysis.Analyzer{Name: "c"}
+	all := []*analysis.Analyzer{a, b, c}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "no flags runs all", args: nil, want: []string{"a", "b", "c"}},
+		{name: "enable selects only enabled", args: []string{"-b"}, want: []string{"b"}},
+		{name: "disable removes analyzer", args: []string{"-b=false"}, want: []string{"a", "c"}},
+		{name: "enable wins over disable", args: []string{"-a", "-b=false"}, want: []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			enabled := registerFlags(fs, all)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, a := range selectAnalyzers(all, enabled) {
+				got = append(got, a.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunUnusedNolint(t *testing.T) {
+	tests := []struct {
+		name      string
+		analyzers []*analysis.Analyzer
+		want      []string
+	}{
+		{
+			name:      "all analyzers",
+			analyzers: []*analysis.Analyzer{flagged, quiet},
+			want: []string{
+				"testdata/src/directives/directives.go:6:1: nolint directive for analyzer flagged is unused; remove it",
+				"testdata/src/directives/directives.go:9:1: nolint directive for analyzers quiet, flagged is unused; remove it",
+				"testdata/src/directives/directives.go:17:1: nolint directive for golint-sl is unused; remove it",
+			},
+		},
+		{
+			// quiet may have needed the directives naming it, and any
+			// analyzer may have needed the catch-all
+			name:      "subset of analyzers",
+			analyzers: []*analysis.Analyzer{flagged},
+			want: []string{
+				"testdata/src/directives/directives.go:6:1: nolint directive for analyzer flagged is unused; remove it",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			code := Run([]string{"./testdata/src/directives"}, tt.analyzers, Options{
+				Format:       FormatText,
+				Output:       &out,
+				UnusedNolint: true,
+				Known:        []*analysis.Analyzer{flagged, quiet},
+			})
+			if code != ExitDiagnostics {
+				t.Errorf("exit code = %d, want %d", code, ExitDiagnostics)
+			}
+
+			got := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	const prefix = "github.com/spechtlabs/golint-sl/internal/driver/testdata/src/stats/"
+
+	var out bytes.Buffer
+	code := Run([]string{"./testdata/src/stats/..."}, []*analysis.Analyzer{flagged, quiet}, Options{
+		Format: FormatJSON,
+		Output: &out,
+		Stats:  true,
+	})
+	if code != ExitClean {
+		t.Errorf("exit code = %d, want %d", code, ExitClean)
+	}
+
+	var stats Stats
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total != 3 || len(stats.Analyzers) != 1 {
+		t.Fatalf("got %+v, want 3 diagnostics of one analyzer", stats)
+	}
+	got := stats.Analyzers[0]
+	want := []PackageCount{{prefix + "one", 2}, {prefix + "two", 1}}
+	if got.Analyzer != "flagged" || got.Count != 3 || got.Packages != 2 ||
+		len(got.TopPackages) != 2 || got.TopPackages[0] != want[0] || got.TopPackages[1] != want[1] {
+		t.Errorf("got %+v, want flagged with 3 diagnostics in %+v", got, want)
+	}
+}
+
+func TestRunStatsText(t *testing.T) {
+	var out bytes.Buffer
+	Run([]string{"./testdata/src/stats/two"}, []*analysis.Analyzer{flagged}, Options{
+		Format: FormatText,
+		Output: &out,
+		Stats:  true,
+	})
+
+	want := `ANALYZER  COUNT  PACKAGES  TOP PACKAGES
+flagged   1      1         github.com/spechtlabs/golint-sl/internal/driver/testdata/src/stats/two (1)
+TOTAL     1
+`
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var diags []Diagnostic
+	add := func(analyzer, pkg string, n int) {
+		for i := 0; i < n; i++ {
+			diags = append(diags, Diagnostic{Analyzer: analyzer, Package: pkg})
+		}
+	}
+	add("b", "p1", 1)
+	add("b", "p2", 3)
+	add("b", "p3", 2)
+	add("b", "p4", 1)
+	add("b", "p5", 4)
+	add("b", "p6", 1)
+	add("a", "p1", 13)
+	add("c", "p1", 10)
+
+	stats := Summarize(diags)
+	if stats.Total != 35 {
+		t.Errorf("Total = %d, want 35", stats.Total)
+	}
+
+	var order []string
+	for _, as := range stats.Analyzers {
+		order = append(order, as.Analyzer)
+	}
+	if strings.Join(order, ",") != "a,b,c" {
+		t.Errorf("analyzers ordered %v, want [a b c]", order)
+	}
+
+	b := stats.Analyzers[1]
+	var top []string
+	for _, pc := range b.TopPackages {
+		top = append(top, pc.Package)
+	}
+	if b.Count != 12 || b.Packages != 6 || strings.Join(top, ",") != "p5,p2,p3,p1,p4" {
+		t.Errorf("got %+v, want 12 diagnostics in 6 packages, top p5,p2,p3,p1,p4", b)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteStats(&out, FormatJSON, Summarize(nil)); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if analyzers, ok := got["analyzers"].([]any); !ok || len(analyzers) != 0 || got["total"] != 0.0 {
+		t.Errorf("got %s, want no analyzers and total 0", out.String())
+	}
+}
@@ -0,0 +1,478 @@
+package driver
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/spechtlabs/golint-sl/internal/generated"
+)
+
+// diffContext is the number of unchanged lines around each hunk of a diff.
+const diffContext = 3
+
+// edit replaces src[start:end] of a file with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// fix is the first suggested fix of a diagnostic, resolved to byte offsets.
+type fix struct {
+	analyzer string
+	message  string
+	posn     token.Position
+	edits    map[string][]edit // by file name
+}
+
+// fixedFile is a file with the non-overlapping edits of the accepted fixes.
+type fixedFile struct {
+	src   []byte
+	mode  os.FileMode
+	edits []edit
+}
+
+// applyFixes collects the suggested fixes of the root actions, merges the
+// ones a package and its test variant report for the same diagnostic, drops
+// the ones that overlap an earlier fix, and either writes the fixed files and a
+// summary per analyzer to w, or, with diff set, writes unified diffs to w
+// and leaves the files alone. It reports whether anything failed.
+func applyFixes(w io.Writer, graph *checker.Graph, dir string, diff bool) bool {
+	fixes := mergeVariants(collectFixes(graph))
+	failed := false
+
+	files := make(map[string]*fixedFile)
+	fixed := make(map[string]map[string]bool) // analyzer -> file names
+	counts := make(map[string]int)            // analyzer -> fixes
+
+	for _, f := range fixes {
+		if !loadFiles(files, f) {
+			failed = true
+			continue
+		}
+		if overlaps(files, f) {
+			log.Printf("%s: %s: fix overlaps an earlier fix; skipped", f.analyzer, f.posn)
+			continue
+		}
+
+		counts[f.analyzer]++
+		if fixed[f.analyzer] == nil {
+			fixed[f.analyzer] = make(map[string]bool)
+		}
+		for name, edits := range f.edits {
+			files[name].edits = addEdits(files[name].edits, edits)
+			fixed[f.analyzer][name] = true
+		}
+	}
+
+	for _, name := range sortedKeys(files) {
+		file := files[name]
+		if len(file.edits) == 0 {
+			continue
+		}
+		sort.Slice(file.edits, func(i, j int) bool { return file.edits[i].start < file.edits[j].start })
+
+		if diff {
+			if _, err := io.WriteString(w, unifiedDiff(relativePath(dir, name), file.src, file.edits)); err != nil {
+				log.Print(err)
+				return true
+			}
+			continue
+		}
+
+		if err := os.WriteFile(name, applyEdits(file.src, file.edits), file.mode); err != nil {
+			log.Print(err)
+			failed = true
+		}
+	}
+
+	if !diff {
+		for _, analyzer := range sortedKeys(fixed) {
+			var names []string
+			for _, name := range sortedKeys(fixed[analyzer]) {
+				names = append(names, relativePath(dir, name))
+			}
+			fmt.Fprintf(w, "%s: %d %s in %s\n", analyzer, counts[analyzer], plural(counts[analyzer], "fix", "fixes"), strings.Join(names, ", "))
+		}
+	}
+
+	return failed
+}
+
+// collectFixes returns the first suggested fix of each diagnostic of the
+// root actions, ordered by position. Fixes touching files outside the main
+// module, vendored files, or generated files are dropped.
+func collectFixes(graph *checker.Graph) []*fix {
+	var fixes []*fix
+
+	for act := range graph.All() {
+		if !act.IsRoot || act.Err != nil {
+			continue
+		}
+		editable := editableFiles(act.Package)
+
+		for _, d := range act.Diagnostics {
+			if len(d.SuggestedFixes) == 0 {
+				continue
+			}
+			f, err := resolveFix(act.Package.Fset, act.Analyzer, d, editable)
+			if err != nil {
+				log.Printf("%s: %v", act.Analyzer.Name, err)
+				continue
+			}
+			if f != nil {
+				fixes = append(fixes, f)
+			}
+		}
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool {
+		a, b := fixes[i].posn, fixes[j].posn
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+		return fixes[i].analyzer < fixes[j].analyzer
+	})
+
+	return fixes
+}
+
+// resolveFix converts the first suggested fix of d to byte offsets. It
+// returns nil if the fix touches a file that may not be edited.
+func resolveFix(fset *token.FileSet, a *analysis.Analyzer, d analysis.Diagnostic, editable map[string]bool) (*fix, error) {
+	f := &fix{
+		analyzer: a.Name,
+		message:  d.Message,
+		posn:     fset.Position(d.Pos),
+		edits:    make(map[string][]edit),
+	}
+
+	for _, te := range d.SuggestedFixes[0].TextEdits {
+		tf := fset.File(te.Pos)
+		if tf == nil {
+			return nil, fmt.Errorf("%s: fix edit has no file", f.posn)
+		}
+		end := te.End
+		if !end.IsValid() {
+			end = te.Pos
+		}
+		if end < te.Pos || int(end)-tf.Base() > tf.Size() {
+			return nil, fmt.Errorf("%s: fix edit has an invalid range", f.posn)
+		}
+
+		name := tf.Name()
+		if !editable[name] {
+			return nil, nil
+		}
+		f.edits[name] = append(f.edits[name], edit{
+			start: tf.Offset(te.Pos),
+			end:   tf.Offset(end),
+			text:  string(te.NewText),
+		})
+	}
+
+	return f, nil
+}
+
+// mergeVariants merges the fixes reported for the same diagnostic, once for
+// a package and once for its test variant. The fix of the test variant may
+// also edit the _test.go files, so each merged fix gets the edits of all of
+// them, with identical edits kept once.
+func mergeVariants(fixes []*fix) []*fix {
+	type diagnostic struct {
+		analyzer, message, filename string
+		offset                      int
+	}
+
+	var merged []*fix
+	byDiagnostic := make(map[diagnostic]*fix)
+	for _, f := range fixes {
+		d := diagnostic{f.analyzer, f.message, f.posn.Filename, f.posn.Offset}
+		first, ok := byDiagnostic[d]
+		if !ok {
+			byDiagnostic[d] = f
+			merged = append(merged, f)
+			continue
+		}
+		for name, edits := range f.edits {
+			first.edits[name] = addEdits(first.edits[name], edits)
+		}
+	}
+	return merged
+}
+
+// addEdits appends the edits to edits that it does not contain yet
+func addEdits(edits, add []edit) []edit {
+	for _, e := range add {
+		if !slices.Contains(edits, e) {
+			edits = append(edits, e)
+		}
+	}
+	return edits
+}
+
+// editableFiles returns the files of pkg that fixes may change: files in a
+// main module that are neither vendored nor generated
+func editableFiles(pkg *packages.Package) map[string]bool {
+	editable := make(map[string]bool)
+	if pkg.Module == nil || !pkg.Module.Main || pkg.Module.Dir == "" {
+		return editable
+	}
+	root := filepath.Clean(pkg.Module.Dir) + string(filepath.Separator)
+
+	syntax := make(map[string]*ast.File, len(pkg.Syntax))
+	for _, file := range pkg.Syntax {
+		syntax[pkg.Fset.Position(file.Pos()).Filename] = file
+	}
+
+	for _, name := range pkg.CompiledGoFiles {
+		rel, ok := strings.CutPrefix(filepath.Clean(name), root)
+		if !ok || isVendored(rel) {
+			continue
+		}
+		if file := syntax[name]; file == nil || generated.File(name, file) {
+			continue
+		}
+		editable[name] = true
+	}
+
+	return editable
+}
+
+// isVendored checks if the module-relative path rel is in a vendor directory
+func isVendored(rel string) bool {
+	for _, elem := range strings.Split(filepath.ToSlash(rel), "/") {
+		if elem == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadFiles reads the files f edits that were not read yet and checks that
+// the edits lie within them
+func loadFiles(files map[string]*fixedFile, f *fix) bool {
+	for name, edits := range f.edits {
+		file, ok := files[name]
+		if !ok {
+			var err error
+			if file, err = loadFile(name); err != nil {
+				log.Print(err)
+				return false
+			}
+			files[name] = file
+		}
+		for _, e := range edits {
+			if e.end > len(file.src) {
+				log.Printf("%s: %s: fix edit is beyond the end of %s; was it changed?", f.analyzer, f.posn, name)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// loadFile reads name along with its permissions, which the fixed file
+// keeps
+func loadFile(name string) (*fixedFile, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	src, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return &fixedFile{src: src, mode: info.Mode().Perm()}, nil
+}
+
+// overlaps checks if an edit of f overlaps another edit of f or an edit of an
+// accepted fix. Insertions at the same offset overlap too, since their order
+// is undefined. Edits identical to an accepted one are applied once and do
+// not overlap it.
+func overlaps(files map[string]*fixedFile, f *fix) bool {
+	for name, edits := range f.edits {
+		all := addEdits(append([]edit(nil), files[name].edits...), edits)
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].start != all[j].start {
+				return all[i].start < all[j].start
+			}
+			return all[i].end < all[j].end
+		})
+		for i := 1; i < len(all); i++ {
+			prev, cur := all[i-1], all[i]
+			if cur.start < prev.end || cur.start == prev.start {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyEdits returns src with the sorted, non-overlapping edits applied
+func applyEdits(src []byte, edits []edit) []byte {
+	var out bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		out.Write(src[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.Write(src[last:])
+	return out.Bytes()
+}
+
+// unifiedDiff returns the unified diff between src and src with the sorted,
+// non-overlapping edits applied, naming the file name
+func unifiedDiff(name string, src []byte, edits []edit) string {
+	lines := splitLines(string(src))
+	starts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		starts[i+1] = starts[i] + len(line)
+	}
+	lineOf := func(offset int) int {
+		return sort.Search(len(lines), func(i int) bool { return starts[i+1] > offset })
+	}
+
+	// Changes replace whole lines [from, to) of src with lines of new text
+	type change struct {
+		from, to int
+		text     []string
+	}
+	var changes []change
+	for i := 0; i < len(edits); {
+		from := lineOf(edits[i].start)
+		to := lineOf(max(edits[i].end-1, edits[i].start)) + 1
+		j := i + 1
+		for j < len(edits) && lineOf(edits[j].start) < to {
+			to = max(to, lineOf(max(edits[j].end-1, edits[j].start))+1)
+			j++
+		}
+		to = min(to, len(lines))
+
+		var text strings.Builder
+		last := starts[from]
+		for _, e := range edits[i:j] {
+			text.WriteString(string(src[last:e.start]))
+			text.WriteString(e.text)
+			last = e.end
+		}
+		text.WriteString(string(src[last:starts[to]]))
+		changes = append(changes, change{from, to, splitLines(text.String())})
+		i = j
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+
+	delta := 0 // lines added minus lines removed before the current hunk
+	for i := 0; i < len(changes); {
+		// Changes whose context touches share a hunk
+		j := i + 1
+		for j < len(changes) && changes[j].from-changes[j-1].to <= 2*diffContext {
+			j++
+		}
+
+		from := max(changes[i].from-diffContext, 0)
+		to := min(changes[j-1].to+diffContext, len(lines))
+
+		var body strings.Builder
+		oldLen, newLen := 0, 0
+		at := from
+		for _, c := range changes[i:j] {
+			for ; at < c.from; at++ {
+				writeLine(&body, ' ', lines[at])
+				oldLen++
+				newLen++
+			}
+			for ; at < c.to; at++ {
+				writeLine(&body, '-', lines[at])
+				oldLen++
+			}
+			for _, line := range c.text {
+				writeLine(&body, '+', line)
+				newLen++
+			}
+		}
+		for ; at < to; at++ {
+			writeLine(&body, ' ', lines[at])
+			oldLen++
+			newLen++
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n%s", hunkRange(from, oldLen), hunkRange(from+delta, newLen), body.String())
+		delta += newLen - oldLen
+		i = j
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start line and length of a hunk side, where from is
+// the zero-based index of its first line
+func hunkRange(from, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", from)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", from+1)
+	}
+	return fmt.Sprintf("%d,%d", from+1, length)
+}
+
+// splitLines splits s after each newline, keeping the newlines
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// writeLine writes a diff line, marking a missing final newline
+func writeLine(b *strings.Builder, prefix byte, line string) {
+	b.WriteByte(prefix)
+	b.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		b.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// plural returns one for n == 1 and other otherwise
+func plural(n int, one, other string) string {
+	if n == 1 {
+		return one
+	}
+	return other
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,292 @@
+package driver
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// renamer suggests renaming functions named Flagged to Fixed. It reports with
+// pass.Report, so generated files are only skipped by the fix driver.
+var renamer = &analysis.Analyzer{
+	Name: "renamer",
+	Doc:  "rename functions named Flagged",
+	Run: func(pass *analysis.Pass) (interface{}, error) {
+		for _, file := range pass.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Name.Name != "Flagged" {
+					continue
+				}
+				pass.Report(analysis.Diagnostic{
+					Pos:     fn.Name.Pos(),
+					Message: "function Flagged is flagged",
+					SuggestedFixes: []analysis.SuggestedFix{{
+						Message:   "rename to Fixed",
+						TextEdits: []analysis.TextEdit{{Pos: fn.Name.Pos(), End: fn.Name.End(), NewText: []byte("Fixed")}},
+					}},
+				})
+			}
+		}
+		return nil, nil
+	},
+}
+
+// callRenamer renames functions named Flagged to Fixed along with their
+// calls in the package. The test variant of a package sees the calls in its
+// _test.go files, so its fix is a superset of the package's.
+var callRenamer = &analysis.Analyzer{
+	Name: "callrenamer",
+	Doc:  "rename functions named Flagged and their calls",
+	Run: func(pass *analysis.Pass) (interface{}, error) {
+		var decl *ast.FuncDecl
+		var edits []analysis.TextEdit
+		for _, file := range pass.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncDecl:
+					if n.Name.Name == "Flagged" {
+						decl = n
+						edits = append(edits, analysis.TextEdit{Pos: n.Name.Pos(), End: n.Name.End(), NewText: []byte("Fixed")})
+					}
+				case *ast.CallExpr:
+					if ident, ok := n.Fun.(*ast.Ident); ok && ident.Name == "Flagged" {
+						edits = append(edits, analysis.TextEdit{Pos: ident.Pos(), End: ident.End(), NewText: []byte("Fixed")})
+					}
+				}
+				return true
+			})
+		}
+		if decl != nil {
+			pass.Report(analysis.Diagnostic{
+				Pos:            decl.Name.Pos(),
+				Message:        "function Flagged is flagged",
+				SuggestedFixes: []analysis.SuggestedFix{{Message: "rename to Fixed", TextEdits: edits}},
+			})
+		}
+		return nil, nil
+	},
+}
+
+// writeModule creates a module with the given files in a temporary directory
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/fixme\n\ngo 1.22\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const (
+	fixSource = `package fixme
+
+// Flagged is renamed
+func Flagged() {}
+
+func other() {}
+`
+	fixGenerated = `// Code generated by hand. DO NOT EDIT.
+
+package fixme
+
+func Flagged() {}
+`
+)
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestRunFix(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a.go":            fixSource,
+		"gen/gen.go":      fixGenerated,
+		"other/b.go":      "package other\n\nfunc Flagged() {}\n",
+		"other/b_test.go": "package other\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) { Flagged() }\n",
+	})
+
+	var out bytes.Buffer
+	code := Run([]string{"./..."}, []*analysis.Analyzer{renamer}, Options{
+		Output: &out,
+		Dir:    dir,
+		Tests:  true,
+		Fix:    true,
+	})
+	if code != ExitClean {
+		t.Errorf("exit code = %d, want %d", code, ExitClean)
+	}
+
+	want := strings.Replace(fixSource, "func Flagged", "func Fixed", 1)
+	if got := readFile(t, dir, "a.go"); got != want {
+		t.Errorf("a.go after fix:\n%s\nwant:\n%s", got, want)
+	}
+	// The fix is reported for other and its test variant but applied once
+	if got := readFile(t, dir, "other/b.go"); got != "package other\n\nfunc Fixed() {}\n" {
+		t.Errorf("other/b.go after fix:\n%s", got)
+	}
+	if got := readFile(t, dir, "gen/gen.go"); got != fixGenerated {
+		t.Errorf("generated file was changed:\n%s", got)
+	}
+
+	if got, want := out.String(), "renamer: 2 fixes in a.go, other/b.go\n"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestRunFixTestVariant(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"svc/svc.go":      "package svc\n\nfunc Flagged() {}\n\nfunc call() { Flagged() }\n",
+		"svc/svc_test.go": "package svc\n\nimport \"testing\"\n\nfunc TestSvc(t *testing.T) { Flagged() }\n",
+	})
+
+	var out bytes.Buffer
+	code := Run([]string{"./..."}, []*analysis.Analyzer{callRenamer}, Options{
+		Output: &out,
+		Dir:    dir,
+		Tests:  true,
+		Fix:    true,
+	})
+	if code != ExitClean {
+		t.Errorf("exit code = %d, want %d", code, ExitClean)
+	}
+
+	// The package and its test variant fix the same diagnostic; the edits
+	// they share are applied once and the test variant's call is fixed too
+	if got, want := readFile(t, dir, "svc/svc.go"), "package svc\n\nfunc Fixed() {}\n\nfunc call() { Fixed() }\n"; got != want {
+		t.Errorf("svc/svc.go after fix:\n%s\nwant:\n%s", got, want)
+	}
+	if got := readFile(t, dir, "svc/svc_test.go"); !strings.Contains(got, "{ Fixed() }") {
+		t.Errorf("svc/svc_test.go after fix still calls Flagged:\n%s", got)
+	}
+	if got, want := out.String(), "callrenamer: 1 fix in svc/svc.go, svc/svc_test.go\n"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestMergeVariants(t *testing.T) {
+	posn := token.Position{Filename: "a.go", Offset: 2}
+	pkg := &fix{analyzer: "a", message: "m", posn: posn, edits: map[string][]edit{
+		"a.go": {{start: 2, end: 5, text: "x"}},
+	}}
+	test := &fix{analyzer: "a", message: "m", posn: posn, edits: map[string][]edit{
+		"a.go":      {{start: 2, end: 5, text: "x"}},
+		"a_test.go": {{start: 7, end: 9, text: "x"}},
+	}}
+	other := &fix{analyzer: "a", message: "other", posn: posn, edits: map[string][]edit{
+		"a.go": {{start: 6, end: 6, text: "y"}},
+	}}
+
+	merged := mergeVariants([]*fix{pkg, test, other})
+	if len(merged) != 2 {
+		t.Fatalf("mergeVariants returned %d fixes, want 2", len(merged))
+	}
+	if got := merged[0].edits; len(got["a.go"]) != 1 || len(got["a_test.go"]) != 1 {
+		t.Errorf("merged edits = %v, want one edit in a.go and a_test.go", got)
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := writeModule(t, map[string]string{"a.go": fixSource})
+
+	var out bytes.Buffer
+	code := Run([]string{"./..."}, []*analysis.Analyzer{renamer}, Options{
+		Output: &out,
+		Dir:    dir,
+		Diff:   true,
+		Fix:    true,
+	})
+	if code != ExitClean {
+		t.Errorf("exit code = %d, want %d", code, ExitClean)
+	}
+
+	if got := readFile(t, dir, "a.go"); got != fixSource {
+		t.Errorf("a.go was changed by -diff:\n%s", got)
+	}
+
+	want := `--- a/a.go
++++ b/a.go
+@@ -1,6 +1,6 @@
+ package fixme
+ 
+ // Flagged is renamed
+-func Flagged() {}
++func Fixed() {}
+ 
+ func other() {}
+`
+	if out.String() != want {
+		t.Errorf("diff:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	files := map[string]*fixedFile{"a.go": {src: []byte("0123456789"), edits: []edit{{start: 2, end: 5}}}}
+
+	tests := []struct {
+		name  string
+		edits []edit
+		want  bool
+	}{
+		{name: "before", edits: []edit{{start: 0, end: 2}}, want: false},
+		{name: "after", edits: []edit{{start: 5, end: 7}}, want: false},
+		{name: "inside", edits: []edit{{start: 3, end: 4}}, want: true},
+		{name: "same insertion point", edits: []edit{{start: 2, end: 2}}, want: true},
+		{name: "within the fix", edits: []edit{{start: 6, end: 8}, {start: 7, end: 9}}, want: true},
+		{name: "identical", edits: []edit{{start: 2, end: 5}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fix{edits: map[string][]edit{"a.go": tt.edits}}
+			if got := overlaps(files, f); got != tt.want {
+				t.Errorf("overlaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	var src strings.Builder
+	for i := 1; i <= 20; i++ {
+		src.WriteString(strings.Repeat("x", i%3+1) + "\n")
+	}
+	s := src.String()
+
+	// Replace line 2 and delete line 18
+	line := func(n int) (int, int) {
+		start := 0
+		for i := 1; i < n; i++ {
+			start += strings.Index(s[start:], "\n") + 1
+		}
+		return start, start + strings.Index(s[start:], "\n") + 1
+	}
+	s2, e2 := line(2)
+	s18, e18 := line(18)
+	got := unifiedDiff("f.txt", []byte(s), []edit{{start: s2, end: e2, text: "new\n"}, {start: s18, end: e18}})
+
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@\n") || !strings.Contains(got, "@@ -15,6 +15,5 @@\n") {
+		t.Errorf("unexpected hunk headers:\n%s", got)
+	}
+	if strings.Count(got, "\n-") != 2 || strings.Count(got, "\n+new") != 1 {
+		t.Errorf("unexpected changes:\n%s", got)
+	}
+}
@@ -0,0 +1,159 @@
+package driver
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// triState is an analyzer enable flag that remembers whether it was set
+type triState int
+
+const (
+	unset triState = iota
+	setTrue
+	setFalse
+)
+
+func (ts *triState) Get() any {
+	return *ts == setTrue
+}
+
+func (ts *triState) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("want true or false")
+	}
+	if b {
+		*ts = setTrue
+	} else {
+		*ts = setFalse
+	}
+	return nil
+}
+
+func (ts *triState) String() string {
+	switch *ts {
+	case setTrue:
+		return "true"
+	case setFalse:
+		return "false"
+	}
+	return "unset"
+}
+
+// IsBoolFlag lets -nilcheck be used without a value
+func (ts *triState) IsBoolFlag() bool {
+	return true
+}
+
+// registerFlags adds an enable flag for each analyzer and its own flags
+// prefixed with the analyzer name
+func registerFlags(fs *flag.FlagSet, analyzers []*analysis.Analyzer) map[*analysis.Analyzer]*triState {
+	enabled := make(map[*analysis.Analyzer]*triState, len(analyzers))
+
+	for _, a := range analyzers {
+		ts := new(triState)
+		fs.Var(ts, a.Name, "enable "+a.Name+" analysis")
+		enabled[a] = ts
+
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			fs.Var(f.Value, a.Name+"."+f.Name, f.Usage)
+		})
+	}
+
+	return enabled
+}
+
+// selectAnalyzers applies the enable flags: if any analyzer was explicitly
+// enabled only those run, otherwise all run except the disabled ones
+func selectAnalyzers(analyzers []*analysis.Analyzer, enabled map[*analysis.Analyzer]*triState) []*analysis.Analyzer {
+	anyTrue := false
+	for _, ts := range enabled {
+		if *ts == setTrue {
+			anyTrue = true
+			break
+		}
+	}
+
+	var selected []*analysis.Analyzer
+	for _, a := range analyzers {
+		switch {
+		case anyTrue && *enabled[a] == setTrue:
+			selected = append(selected, a)
+		case !anyTrue && *enabled[a] != setFalse:
+			selected = append(selected, a)
+		}
+	}
+	return selected
+}
+
+// usage prints the synopsis, the general flags, and the analyzers
+func usage(progname string, analyzers []*analysis.Analyzer) {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, `%[1]s is a tool for static analysis of Go programs.
+
+Usage: %[1]s [-flag] [package]
+
+Run '%[1]s help' for the list of analyzers,
+ or '%[1]s help name' for details and flags of a specific analyzer.
+
+Flags:
+`, progname)
+
+	names := make(map[string]bool, len(analyzers))
+	for _, a := range analyzers {
+		names[a.Name] = true
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		name, _, _ := strings.Cut(f.Name, ".")
+		if names[name] {
+			return
+		}
+		fmt.Fprintf(w, "  -%s\n    \t%s\n", f.Name, f.Usage)
+	})
+
+	fmt.Fprintln(w, "\nAnalyzers:")
+	help(w, analyzers, nil)
+}
+
+// help lists the analyzers, or prints the documentation and flags of the
+// named ones
+func help(w io.Writer, analyzers []*analysis.Analyzer, names []string) {
+	if len(names) == 0 {
+		for _, a := range analyzers {
+			title, _, _ := strings.Cut(a.Doc, "\n")
+			fmt.Fprintf(w, "  %-22s %s\n", a.Name, title)
+		}
+		return
+	}
+
+	for _, name := range names {
+		a := find(analyzers, name)
+		if a == nil {
+			fmt.Fprintf(os.Stderr, "unknown analyzer %q\n", name)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s: %s\n", a.Name, strings.TrimSpace(a.Doc))
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(w, "  -%s.%s\n    \t%s (default %s)\n", a.Name, f.Name, f.Usage, f.DefValue)
+		})
+		fmt.Fprintln(w)
+	}
+}
+
+// find returns the analyzer with the given name
+func find(analyzers []*analysis.Analyzer, name string) *analysis.Analyzer {
+	for _, a := range analyzers {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
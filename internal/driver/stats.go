@@ -0,0 +1,107 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// TopPackages is the number of packages listed per analyzer in statistics.
+const TopPackages = 5
+
+// Stats aggregates diagnostics per analyzer, for adopting golint-sl on a
+// code base with too many findings to read one by one.
+type Stats struct {
+	Total     int             `json:"total"`
+	Analyzers []AnalyzerStats `json:"analyzers"`
+}
+
+// AnalyzerStats counts the diagnostics of one analyzer.
+type AnalyzerStats struct {
+	Analyzer    string         `json:"analyzer"`
+	Count       int            `json:"count"`
+	Packages    int            `json:"packages"`     // packages with at least one diagnostic
+	TopPackages []PackageCount `json:"top_packages"` // at most TopPackages, most diagnostics first
+}
+
+// PackageCount is the number of diagnostics in a package.
+type PackageCount struct {
+	Package string `json:"package"`
+	Count   int    `json:"count"`
+}
+
+// Summarize counts diags per analyzer and package. Analyzers are ordered by
+// count, most diagnostics first, then by name.
+func Summarize(diags []Diagnostic) Stats {
+	perPackage := make(map[string]map[string]int)
+	for _, d := range diags {
+		if perPackage[d.Analyzer] == nil {
+			perPackage[d.Analyzer] = make(map[string]int)
+		}
+		perPackage[d.Analyzer][d.Package]++
+	}
+
+	stats := Stats{Total: len(diags), Analyzers: []AnalyzerStats{}}
+	for analyzer, counts := range perPackage {
+		as := AnalyzerStats{Analyzer: analyzer, Packages: len(counts)}
+		for pkg, count := range counts {
+			as.Count += count
+			as.TopPackages = append(as.TopPackages, PackageCount{Package: pkg, Count: count})
+		}
+		sort.Slice(as.TopPackages, func(i, j int) bool {
+			a, b := as.TopPackages[i], as.TopPackages[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Package < b.Package
+		})
+		if len(as.TopPackages) > TopPackages {
+			as.TopPackages = as.TopPackages[:TopPackages]
+		}
+		stats.Analyzers = append(stats.Analyzers, as)
+	}
+
+	sort.Slice(stats.Analyzers, func(i, j int) bool {
+		a, b := stats.Analyzers[i], stats.Analyzers[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Analyzer < b.Analyzer
+	})
+
+	return stats
+}
+
+// ParseStatsFormat validates a -stats-format value. Statistics have no
+// SARIF form.
+func ParseStatsFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown stats format %q (want text or json)", s)
+}
+
+// WriteStats serializes stats as a table or as JSON.
+func WriteStats(w io.Writer, format Format, stats Stats) error {
+	if format == FormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ANALYZER\tCOUNT\tPACKAGES\tTOP PACKAGES")
+	for _, as := range stats.Analyzers {
+		top := make([]string, 0, len(as.TopPackages))
+		for _, pc := range as.TopPackages {
+			top = append(top, fmt.Sprintf("%s (%d)", pc.Package, pc.Count))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", as.Analyzer, as.Count, as.Packages, strings.Join(top, ", "))
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\n", stats.Total)
+	return tw.Flush()
+}
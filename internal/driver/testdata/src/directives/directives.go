@@ -0,0 +1,18 @@
+package directives
+
+//nolint:flagged
+func Flagged() {}
+
+//nolint:flagged
+func Clean() {}
+
+//nolint:quiet,flagged
+func Multi() {}
+
+//nolint:flagged,quiet
+func Flagged2() {}
+
+func Other() {} //nolint:gosec
+
+//nolint:golint-sl
+func CatchAllUnused() {}
@@ -0,0 +1,3 @@
+package clean
+
+func Clean() {}
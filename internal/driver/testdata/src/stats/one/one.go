@@ -0,0 +1,5 @@
+package one
+
+func Flagged() {}
+
+func FlaggedToo() {}
@@ -0,0 +1,37 @@
+// Package facts defines the analysis facts analyzers record on functions so
+// that what they learn about a package carries over to the packages
+// importing it.
+//
+// The analysis framework lets only one analyzer register a fact type, so
+// each type below names the analyzer that owns it.
+//
+// An analyzer with facts also runs on every dependency of the packages it
+// checks. Facts for analyzers too expensive for that, like those building
+// SSA, are computed by a small analyzer in this package instead, which the
+// expensive analyzer requires.
+package facts
+
+// PerformsIO marks a function that does network, database, or process I/O
+// without accepting a context.Context, directly or through the functions it
+// calls. Callers holding a context cannot cancel that I/O.
+//
+// Owned by contextpropagation.
+type PerformsIO struct {
+	// Via is the I/O operation the function ends up calling, like
+	// net/http.Get or (*database/sql.DB).Query.
+	Via string
+}
+
+func (*PerformsIO) AFact() {}
+
+func (f *PerformsIO) String() string { return "performsIO(" + f.Via + ")" }
+
+// NeedsContext marks a function that accepts a context.Context and passes
+// it on, so the context it is given decides whether its work is cancelled.
+//
+// Owned by NeedsContextAnalyzer, for dataflow.
+type NeedsContext struct{}
+
+func (*NeedsContext) AFact() {}
+
+func (*NeedsContext) String() string { return "needsContext" }
@@ -0,0 +1,97 @@
+package facts
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// NeedsContextAnalyzer exports a NeedsContext fact for each function that
+// passes its context parameter on to a call. Its result is a
+// NeedsContextResult covering the functions of the package and the imported
+// functions it calls.
+var NeedsContextAnalyzer = &analysis.Analyzer{
+	Name:       "needscontext",
+	Doc:        "find functions that pass their context.Context parameter on",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	Run:        runNeedsContext,
+	ResultType: reflect.TypeOf(NeedsContextResult(nil)),
+	FactTypes:  []analysis.Fact{(*NeedsContext)(nil)},
+}
+
+// NeedsContextResult holds the functions with a NeedsContext fact
+type NeedsContextResult map[*types.Func]bool
+
+func runNeedsContext(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	needs := make(NeedsContextResult)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok || fn.Body == nil || !forwardsContext(pass, fn) {
+			return
+		}
+		needs[obj] = true
+		pass.ExportObjectFact(obj, &NeedsContext{})
+	})
+
+	// Imported callees, so the result answers for every call in the package
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		callee := typeutil.StaticCallee(pass.TypesInfo, n.(*ast.CallExpr))
+		if callee == nil || callee.Pkg() == pass.Pkg {
+			return
+		}
+		callee = callee.Origin()
+		if !needs[callee] && pass.ImportObjectFact(callee, new(NeedsContext)) {
+			needs[callee] = true
+		}
+	})
+
+	return needs, nil
+}
+
+// forwardsContext checks if a context parameter of fn is an argument of a call
+func forwardsContext(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	params := make(map[types.Object]bool)
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			if obj := pass.TypesInfo.Defs[name]; obj != nil && isContext(obj.Type()) {
+				params[obj] = true
+			}
+		}
+	}
+	if len(params) == 0 {
+		return false
+	}
+
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found {
+			return !found
+		}
+		for _, arg := range call.Args {
+			if ident, ok := ast.Unparen(arg).(*ast.Ident); ok && params[pass.TypesInfo.Uses[ident]] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isContext checks if t is context.Context
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
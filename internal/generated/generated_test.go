@@ -0,0 +1,79 @@
+package generated_test
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/emptyinterface"
+	"github.com/spechtlabs/golint-sl/errorwrap"
+	"github.com/spechtlabs/golint-sl/exporteddoc"
+	"github.com/spechtlabs/golint-sl/internal/generated"
+)
+
+func TestHasGeneratedName(t *testing.T) {
+	tests := map[string]bool{
+		"zz_generated.deepcopy.go":     true,
+		"api/v1/zz_generated.go":       true,
+		"api.pb.go":                    true,
+		"api.pb.gw.go":                 true,
+		"api_grpc.pb.go":               true,
+		"kind_string.go":               false,
+		"reconciler.go":                false,
+		"string.go":                    false,
+		"pb.go":                        false,
+		"generated.go":                 false,
+		"internal/zz_generated/api.go": false,
+	}
+	for name, want := range tests {
+		if got := generated.HasGeneratedName(name); got != want {
+			t.Errorf("HasGeneratedName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	tests := []struct {
+		filename string
+		src      string
+		want     bool
+	}{
+		{"a.go", "// Code generated by mockgen. DO NOT EDIT.\n\npackage a\n", true},
+		{"a.go", "// Package a does things.\n\n// Code generated by hand. DO NOT EDIT.\npackage a\n", true},
+		{"a.go", "package a\n\n// Code generated by mockgen. DO NOT EDIT.\n", false},
+		{"a.go", "// Code generated by mockgen.\n\npackage a\n", false},
+		{"a_string.go", "package a\n", false},
+		{"a_string.go", "// Code generated by \"stringer -type=A\"; DO NOT EDIT.\n\npackage a\n", true},
+		{"kind_string.go", "package a\n\nconst _Kind_name = \"AB\"\n\nvar _Kind_index = [...]uint8{0, 1, 2}\n\nfunc (i Kind) String() string { return _Kind_name[_Kind_index[i]:_Kind_index[i+1]] }\n", true},
+		{"kind_string.go", "package a\n\nconst _Kind_name_0 = \"AB\"\n\nfunc (i Kind) String() string { return _Kind_name_0 }\n", true},
+		{"kind_string.go", "package a\n\nfunc (k Kind) String() string { return \"kind\" }\n", false},
+		{"kind.go", "package a\n\nconst _Kind_name = \"AB\"\n\nfunc (i Kind) String() string { return _Kind_name }\n", false},
+		{"a.go", "package a\n", false},
+	}
+	for _, tt := range tests {
+		file, err := parser.ParseFile(token.NewFileSet(), tt.filename, tt.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := generated.File(tt.filename, file); got != tt.want {
+			t.Errorf("File(%q, %q) = %v, want %v", tt.filename, tt.src, got, tt.want)
+		}
+	}
+}
+
+// TestAnalyzersSkipGenerated runs analyzers that would report every file in
+// package gen except hand.go, which is the only one not generated.
+func TestAnalyzersSkipGenerated(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, exporteddoc.Analyzer, "gen")
+	analysistest.Run(t, testdata, errorwrap.Analyzer, "gen")
+	analysistest.Run(t, testdata, emptyinterface.Analyzer, "gen")
+}
+
+// TestHandWrittenStringFile checks that a hand-written *_string.go file is
+// still reported, while stringer output without its header is not.
+func TestHandWrittenStringFile(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), exporteddoc.Analyzer, "handstring")
+}
@@ -0,0 +1,12 @@
+package gen
+
+import "fmt"
+
+func Proto(v interface{}) (interface{}, error) {
+	n := normalize(fmt.Sprint(v))
+	err := decode(n)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,32 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Config is the decoded form of a value.
+type Config struct {
+	Name string
+}
+
+// Apply decodes v into a Config.
+func Apply(v string) (*Config, error) {
+	n := normalize(v)
+	err := decode(n)
+	if err != nil {
+		return nil, fmt.Errorf("applying %q: %w", v, err)
+	}
+	return &Config{Name: n}, nil
+}
+
+func normalize(v string) string {
+	return v
+}
+
+func decode(v interface{}) error {
+	if v == nil {
+		return errors.New("nil value")
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+package handstring
+
+// Kind is an enum in a hand-written file that only looks like stringer output.
+type Kind int
+
+func ParseKind(s string) Kind { // want `exported function ParseKind should have a documentation comment`
+	return 0
+}
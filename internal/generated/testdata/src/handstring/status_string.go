@@ -0,0 +1,17 @@
+package handstring
+
+// Status is stringer output that lost its header; the String method next to
+// the _Status_name table still marks it as generated.
+type Status int
+
+const _Status_name = "ActiveInactive"
+
+var _Status_index = [...]uint8{0, 6, 14}
+
+func (i Status) String() string {
+	return _Status_name[_Status_index[i]:_Status_index[i+1]]
+}
+
+func ParseStatus(s string) Status {
+	return 0
+}
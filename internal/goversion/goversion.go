@@ -0,0 +1,63 @@
+// Package goversion determines the Go language version code is checked
+// against, so analyzers can tailor or drop advice that depends on it, like
+// suggesting generics or warning about loop variables shared between
+// iterations.
+//
+// A version is unknown when neither the file nor its module declares one,
+// as for packages loaded without a go.mod. Callers then keep the advice
+// they gave before versions were known, which is why both AtLeast and
+// Before report false for unknown versions.
+package goversion
+
+import (
+	"go/token"
+	"go/version"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	// Generics is the first version with type parameters
+	Generics = "go1.18"
+	// ErrorsJoin is the first version with errors.Join
+	ErrorsJoin = "go1.20"
+	// SlicesMaps is the first version with the slices and maps packages
+	SlicesMaps = "go1.21"
+	// LoopVar is the first version declaring loop variables per iteration
+	LoopVar = "go1.22"
+	// Iterators is the first version with range-over-func iterators like
+	// slices.Values and maps.All
+	Iterators = "go1.23"
+)
+
+// At returns the language version of the file containing pos, like
+// "go1.22", or "" when it is unknown. A //go:build constraint of the file
+// takes precedence over the go directive of its module.
+func At(pass *analysis.Pass, pos token.Pos) string {
+	for _, file := range pass.Files {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			if v := pass.TypesInfo.FileVersions[file]; version.IsValid(v) {
+				return v
+			}
+			break
+		}
+	}
+	if v := pass.Pkg.GoVersion(); version.IsValid(v) {
+		return v
+	}
+	return ""
+}
+
+// AtLeast reports whether the code at pos is known to be checked against
+// version v or newer
+func AtLeast(pass *analysis.Pass, pos token.Pos, v string) bool {
+	current := At(pass, pos)
+	return current != "" && version.Compare(current, v) >= 0
+}
+
+// Before reports whether the code at pos is known to be checked against a
+// version older than v
+func Before(pass *analysis.Pass, pos token.Pos, v string) bool {
+	current := At(pass, pos)
+	return current != "" && version.Compare(current, v) < 0
+}
@@ -11,24 +11,43 @@
 // Comments can appear:
 //   - On the same line as the code (inline)
 //   - On the line immediately before the code
+//
+// Diagnostics in generated files are always dropped, see package generated.
+//
+// Directives that no longer suppress anything can be found by passing a
+// Usage to Track before running the analyzers and checking it afterwards.
+//
+// Analyzers list their dependencies with Requires, which adds
+// DirectivesAnalyzer, so a package's comments are scanned once however many
+// analyzers report on it.
 package nolint
 
 import (
 	"go/ast"
 	"go/token"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"golang.org/x/tools/go/analysis"
+
+	"github.com/spechtlabs/golint-sl/internal/generated"
 )
 
 // nolintRegex matches nolint directives in comments.
 // Matches: //nolint:name or // nolint:name or //nolint:name1,name2
 var nolintRegex = regexp.MustCompile(`^//\s*nolint:([a-zA-Z0-9_,-]+)`)
 
+// CatchAll is the directive name that suppresses every golint-sl analyzer.
+const CatchAll = "golint-sl"
+
 // Directive represents a parsed nolint directive.
 type Directive struct {
 	Line      int      // Line number where the directive appears
+	Column    int      // Column where the directive comment starts
 	Analyzers []string // List of analyzer names to suppress (empty means all)
 }
 
@@ -48,9 +67,10 @@ func ParseFile(file *ast.File, fset *token.FileSet) *FileDirectives {
 	for _, cg := range file.Comments {
 		for _, c := range cg.List {
 			if d := parseComment(c.Text); d != nil {
-				line := fset.Position(c.Pos()).Line
-				d.Line = line
-				fd.byLine[line] = d
+				position := fset.Position(c.Pos())
+				d.Line = position.Line
+				d.Column = position.Column
+				fd.byLine[d.Line] = d
 			}
 		}
 	}
@@ -85,32 +105,54 @@ func parseComment(text string) *Directive {
 // IsSuppressed checks if a diagnostic at the given position should be suppressed
 // for the specified analyzer.
 func (fd *FileDirectives) IsSuppressed(line int, analyzerName string) bool {
+	return fd.suppressing(line, analyzerName) != nil
+}
+
+// suppressing returns the directive that suppresses a diagnostic at line for
+// the specified analyzer, or nil.
+func (fd *FileDirectives) suppressing(line int, analyzerName string) *Directive {
 	if fd == nil {
-		return false
+		return nil
 	}
 
 	// Check the current line (inline comment)
 	if d := fd.byLine[line]; d != nil {
 		if d.matches(analyzerName) {
-			return true
+			return d
 		}
 	}
 
 	// Check the previous line (preceding comment)
 	if d := fd.byLine[line-1]; d != nil {
 		if d.matches(analyzerName) {
-			return true
+			return d
 		}
 	}
 
-	return false
+	return nil
+}
+
+// All returns the directives in the file ordered by line.
+func (fd *FileDirectives) All() []*Directive {
+	if fd == nil {
+		return nil
+	}
+
+	directives := make([]*Directive, 0, len(fd.byLine))
+	for _, d := range fd.byLine {
+		directives = append(directives, d)
+	}
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Line < directives[j].Line
+	})
+	return directives
 }
 
 // matches checks if the directive suppresses the given analyzer.
 func (d *Directive) matches(analyzerName string) bool {
 	for _, name := range d.Analyzers {
 		// "golint-sl" suppresses all analyzers
-		if name == "golint-sl" {
+		if name == CatchAll {
 			return true
 		}
 		// Match specific analyzer name
@@ -121,39 +163,87 @@ func (d *Directive) matches(analyzerName string) bool {
 	return false
 }
 
+// DirectivesAnalyzer parses the nolint directives of a package once for all
+// the analyzers requiring it. Its result is a *PackageDirectives.
+var DirectivesAnalyzer = &analysis.Analyzer{
+	Name:             "nolintdirectives",
+	Doc:              "parse the nolint directives and find the generated files of a package",
+	Run:              runDirectives,
+	RunDespiteErrors: true,
+	ResultType:       reflect.TypeOf((*PackageDirectives)(nil)),
+}
+
+// PackageDirectives holds the nolint directives of a package's files and
+// which of them are generated.
+type PackageDirectives struct {
+	Files     map[string]*FileDirectives // filename -> directives
+	Generated map[string]bool            // filenames of generated files
+}
+
+// Requires returns deps plus DirectivesAnalyzer, for the Requires field of
+// analyzers reporting through a Reporter.
+func Requires(deps ...*analysis.Analyzer) []*analysis.Analyzer {
+	return append(deps, DirectivesAnalyzer)
+}
+
+func runDirectives(pass *analysis.Pass) (interface{}, error) {
+	return parsePackage(pass), nil
+}
+
+// parsePackage parses the directives of every file in the package
+func parsePackage(pass *analysis.Pass) *PackageDirectives {
+	pd := &PackageDirectives{
+		Files:     make(map[string]*FileDirectives),
+		Generated: make(map[string]bool),
+	}
+
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		pd.Files[filename] = ParseFile(file, pass.Fset)
+		if generated.File(filename, file) {
+			pd.Generated[filename] = true
+		}
+	}
+
+	return pd
+}
+
+// CategoryAdvisory is the analysis.Diagnostic category of findings that are
+// suggestions rather than defects. The standalone binary reports them with
+// note severity.
+const CategoryAdvisory = "advisory"
+
 // Reporter wraps analysis.Pass to provide nolint-aware reporting.
 type Reporter struct {
 	Pass         *analysis.Pass
 	Directives   map[string]*FileDirectives // filename -> directives
 	AnalyzerName string
+
+	// generated holds the names of the generated files in the package
+	generated map[string]bool
 }
 
-// NewReporter creates a new nolint-aware reporter for the given pass.
+// NewReporter creates a new nolint-aware reporter for the given pass. It
+// uses the result of DirectivesAnalyzer when the analyzer requires it, and
+// parses the directives itself otherwise.
 func NewReporter(pass *analysis.Pass) *Reporter {
-	r := &Reporter{
-		Pass:         pass,
-		Directives:   make(map[string]*FileDirectives),
-		AnalyzerName: pass.Analyzer.Name,
+	pd, ok := pass.ResultOf[DirectivesAnalyzer].(*PackageDirectives)
+	if !ok {
+		pd = parsePackage(pass)
 	}
 
-	// Parse directives from all files in the package
-	for _, file := range pass.Files {
-		filename := pass.Fset.Position(file.Pos()).Filename
-		r.Directives[filename] = ParseFile(file, pass.Fset)
+	return &Reporter{
+		Pass:         pass,
+		Directives:   pd.Files,
+		AnalyzerName: pass.Analyzer.Name,
+		generated:    pd.Generated,
 	}
-
-	return r
 }
 
 // Reportf reports a diagnostic if it's not suppressed by a nolint directive.
 func (r *Reporter) Reportf(pos token.Pos, format string, args ...interface{}) {
-	position := r.Pass.Fset.Position(pos)
-
-	// Check if this position is suppressed
-	if fd := r.Directives[position.Filename]; fd != nil {
-		if fd.IsSuppressed(position.Line, r.AnalyzerName) {
-			return
-		}
+	if r.suppressed(pos) {
+		return
 	}
 
 	r.Pass.Reportf(pos, format, args...)
@@ -161,14 +251,72 @@ func (r *Reporter) Reportf(pos token.Pos, format string, args ...interface{}) {
 
 // Report reports a diagnostic if it's not suppressed by a nolint directive.
 func (r *Reporter) Report(d *analysis.Diagnostic) {
-	position := r.Pass.Fset.Position(d.Pos)
-
-	// Check if this position is suppressed
-	if fd := r.Directives[position.Filename]; fd != nil {
-		if fd.IsSuppressed(position.Line, r.AnalyzerName) {
-			return
-		}
+	if r.suppressed(d.Pos) {
+		return
 	}
 
 	r.Pass.Report(*d)
 }
+
+// suppressed checks if a diagnostic at pos is suppressed and records the
+// suppressing directive in the tracked Usage, if any. Diagnostics in
+// generated files are always suppressed.
+func (r *Reporter) suppressed(pos token.Pos) bool {
+	position := r.Pass.Fset.Position(pos)
+	if r.generated[position.Filename] {
+		return true
+	}
+
+	d := r.Directives[position.Filename].suppressing(position.Line, r.AnalyzerName)
+	if d == nil {
+		return false
+	}
+
+	if u := tracked.Load(); u != nil {
+		u.record(position.Filename, d.Line)
+	}
+	return true
+}
+
+// Usage records which nolint directives suppressed at least one diagnostic.
+// It is safe for concurrent use by analyzers running in parallel.
+type Usage struct {
+	mu   sync.Mutex
+	used map[usageKey]bool
+}
+
+// usageKey identifies a directive by file and line
+type usageKey struct {
+	filename string
+	line     int
+}
+
+// tracked is the Usage every Reporter records into, if any
+var tracked atomic.Pointer[Usage]
+
+// NewUsage creates an empty Usage.
+func NewUsage() *Usage {
+	return &Usage{used: make(map[usageKey]bool)}
+}
+
+// Track makes every Reporter record suppressions into u until Track(nil)
+// is called.
+func Track(u *Usage) {
+	tracked.Store(u)
+}
+
+// record marks the directive at filename:line as used
+func (u *Usage) record(filename string, line int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.used[usageKey{filename, line}] = true
+}
+
+// Used reports whether the directive at filename:line suppressed any
+// diagnostic. A directive naming several analyzers counts as used if any of
+// them was suppressed by it.
+func (u *Usage) Used(filename string, line int) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.used[usageKey{filename, line}]
+}
@@ -0,0 +1,80 @@
+package nolint_test
+
+import (
+	"go/ast"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+// reportFuncs reports every function through a Reporter, returning
+// the directives the Reporter used
+func reportFuncs(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				reporter.Reportf(fn.Name.Pos(), "function %s", fn.Name.Name)
+			}
+		}
+	}
+	return reporter.Directives, nil
+}
+
+var directivesType = reflect.TypeOf(map[string]*nolint.FileDirectives(nil))
+
+var (
+	first = &analysis.Analyzer{
+		Name:       "first",
+		Doc:        "report functions",
+		Requires:   nolint.Requires(),
+		Run:        reportFuncs,
+		ResultType: directivesType,
+	}
+	second = &analysis.Analyzer{
+		Name:       "second",
+		Doc:        "report functions",
+		Requires:   nolint.Requires(),
+		Run:        reportFuncs,
+		ResultType: directivesType,
+	}
+)
+
+// shared reports whether first and second used the same directives
+var shared = &analysis.Analyzer{
+	Name:     "shared",
+	Doc:      "check that directives are parsed once per package",
+	Requires: nolint.Requires(first, second),
+	Run: func(pass *analysis.Pass) (interface{}, error) {
+		a := reflect.ValueOf(pass.ResultOf[first]).Pointer()
+		b := reflect.ValueOf(pass.ResultOf[second]).Pointer()
+		c := reflect.ValueOf(nolint.NewReporter(pass).Directives).Pointer()
+		if a == b && b == c {
+			pass.Reportf(pass.Files[0].Name.Pos(), "directives shared")
+		}
+		return nil, nil
+	},
+}
+
+// fallback does not require DirectivesAnalyzer, like an analyzer embedded
+// on its own by a third party
+var fallback = &analysis.Analyzer{
+	Name:       "fallback",
+	Doc:        "report functions",
+	Run:        reportFuncs,
+	ResultType: directivesType,
+}
+
+func TestDirectivesAnalyzerIsShared(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, shared, "shared")
+}
+
+func TestReporterWithoutDirectivesAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, fallback, "fallback")
+}
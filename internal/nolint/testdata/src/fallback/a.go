@@ -0,0 +1,8 @@
+package fallback
+
+func Reported() {} // want "function Reported"
+
+//nolint:fallback,shared
+func Suppressed() {}
+
+func Inline() {} //nolint:golint-sl
@@ -0,0 +1,4 @@
+package shared // want "directives shared"
+
+//nolint:shared
+func Suppressed() {}
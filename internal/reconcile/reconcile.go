@@ -0,0 +1,35 @@
+// Package reconcile recognizes Kubernetes reconcilers for the analyzers
+// that only check Reconcile methods.
+package reconcile
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// receiverPatterns are substrings of the receiver types of reconcilers
+var receiverPatterns = []string{"Reconciler", "Controller", "Operator"}
+
+// IsMethod checks if fn is the Reconcile method of a reconciler: a method
+// named exactly Reconcile whose receiver type name contains Reconciler,
+// Controller, or Operator. Helper methods are not matched.
+func IsMethod(fn *ast.FuncDecl) bool {
+	if fn.Name == nil || fn.Name.Name != "Reconcile" {
+		return false
+	}
+
+	// Must have a receiver (it's a method)
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return false
+	}
+
+	recvType := types.ExprString(fn.Recv.List[0].Type)
+	for _, pattern := range receiverPatterns {
+		if strings.Contains(recvType, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
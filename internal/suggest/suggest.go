@@ -0,0 +1,38 @@
+// Package suggest finds the likely intended name for a misspelled one, for
+// "did you mean" hints in diagnostics and configuration errors.
+package suggest
+
+import "strings"
+
+// Closest returns the candidate nearest to name by edit distance, ignoring
+// case, or "" when none is closer than limit
+func Closest(name string, candidates []string, limit int) string {
+	best, bestDist := "", limit
+	for _, cand := range candidates {
+		if d := Distance(strings.ToLower(name), strings.ToLower(cand)); d < bestDist {
+			best, bestDist = cand, d
+		}
+	}
+	return best
+}
+
+// Distance is the Levenshtein distance between a and b
+func Distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
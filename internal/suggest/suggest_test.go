@@ -0,0 +1,40 @@
+package suggest
+
+import "testing"
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"errwrap", "errmsgstyle", "nilcheck"}
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{name: "errwarp", limit: 3, want: "errwrap"},
+		{name: "ErrWrap", limit: 3, want: "errwrap"},
+		{name: "nilchecks", limit: 3, want: "nilcheck"},
+		{name: "contextkeys", limit: 3, want: ""},
+		{name: "errwarp", limit: 2, want: ""},
+	}
+	for _, tt := range tests {
+		if got := Closest(tt.name, candidates, tt.limit); got != tt.want {
+			t.Errorf("Closest(%q, %d) = %q, want %q", tt.name, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"errwrap", "errwarp", 2},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
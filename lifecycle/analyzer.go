@@ -12,12 +12,14 @@ package lifecycle
 
 import (
 	"go/ast"
+	"go/token"
 	"go/types"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
@@ -29,6 +31,10 @@ This analyzer ensures:
 2. Run() methods accept context.Context for cancellation
 3. Long-running goroutines respect context cancellation
 4. Components implement graceful shutdown patterns
+5. Close()/Stop() does more than return nil when Run() starts goroutines or
+   creates a time.Ticker that Run does not stop itself
+6. Run() returns ctx.Err(), not nil, when its context is cancelled, so
+   callers can tell cancellation from a clean exit
 
 The lifecycle pattern ensures:
 - Clean startup and shutdown
@@ -56,7 +62,7 @@ Example of good patterns:
 var Analyzer = &analysis.Analyzer{
 	Name:     "lifecycle",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -75,6 +81,9 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	typeStopMethods := make(map[string]bool)  // type -> has stop method
 	runMethodPos := make(map[string]ast.Node) // type -> run method position
 
+	runFuncs := make(map[string][]*ast.FuncDecl)  // type -> run methods
+	stopFuncs := make(map[string][]*ast.FuncDecl) // type -> stop methods
+
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
 	}
@@ -96,12 +105,16 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			if fn.Name.Name == runMethod {
 				typeRunMethods[recvType] = true
 				runMethodPos[recvType] = fn
+				runFuncs[recvType] = append(runFuncs[recvType], fn)
 
 				// Check if Run accepts context
 				checkRunAcceptsContext(reporter, fn)
 
 				// Check if Run respects context cancellation
 				checkRunRespectsContext(reporter, fn)
+
+				// Check if Run reports cancellation to its caller
+				checkDoneReturnsErr(reporter, pass, fn)
 			}
 		}
 
@@ -109,10 +122,16 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		for _, stopMethod := range StopMethods {
 			if fn.Name.Name == stopMethod {
 				typeStopMethods[recvType] = true
+				stopFuncs[recvType] = append(stopFuncs[recvType], fn)
 			}
 		}
 	})
 
+	// Report Stop methods that cannot clean up what Run started
+	for typeName, stops := range stopFuncs {
+		checkStopCleansUp(reporter, pass, runFuncs[typeName], stops)
+	}
+
 	// Report types with Run but no Stop
 	for typeName, hasRun := range typeRunMethods {
 		if hasRun && !typeStopMethods[typeName] {
@@ -205,6 +224,219 @@ func checkRunRespectsContext(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	}
 }
 
+// checkStopCleansUp reports stop methods doing nothing for a type whose Run
+// starts goroutines or creates a ticker it does not stop itself
+func checkStopCleansUp(reporter *nolint.Reporter, pass *analysis.Pass, runs, stops []*ast.FuncDecl) {
+	for _, fn := range runs {
+		var owned, advice []string
+		goroutines, tickers := ownedResources(pass, fn)
+		if goroutines {
+			owned = append(owned, "starts goroutines")
+			advice = append(advice, "wait for the goroutines (e.g. with a sync.WaitGroup)")
+		}
+		if tickers {
+			owned = append(owned, "creates a time.Ticker")
+			advice = append(advice, "stop the ticker")
+		}
+		if len(owned) == 0 {
+			continue
+		}
+
+		for _, stop := range stops {
+			if !isEmptyBody(stop.Body) {
+				continue
+			}
+			reporter.Reportf(stop.Pos(),
+				"%s() does nothing, but %s() %s; %s before returning",
+				stop.Name.Name, fn.Name.Name, strings.Join(owned, " and "), strings.Join(advice, " and "))
+		}
+		return
+	}
+}
+
+// ownedResources reports whether a run method starts goroutines, and whether
+// it creates tickers without deferring their Stop
+func ownedResources(pass *analysis.Pass, fn *ast.FuncDecl) (goroutines, tickers bool) {
+	if fn.Body == nil {
+		return false, false
+	}
+
+	created := make(map[types.Object]bool)
+	stopped := make(map[types.Object]bool)
+	assigned := make(map[*ast.CallExpr]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			goroutines = true
+
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				call, ok := rhs.(*ast.CallExpr)
+				if !ok || !isTimeFunc(pass, call, "NewTicker") {
+					continue
+				}
+				assigned[call] = true
+				created[assignedObject(pass, node, i)] = true
+			}
+
+		case *ast.DeferStmt:
+			sel, ok := node.Call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Stop" {
+				return true
+			}
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				stopped[pass.TypesInfo.ObjectOf(ident)] = true
+			}
+
+		case *ast.CallExpr:
+			// Tickers created in place, like range time.NewTicker(d).C
+			if !assigned[node] && isTimeFunc(pass, node, "NewTicker") {
+				created[nil] = true
+			}
+		}
+		return true
+	})
+
+	for obj := range created {
+		if obj == nil || !stopped[obj] {
+			tickers = true
+		}
+	}
+	return goroutines, tickers
+}
+
+// assignedObject returns the variable the i-th right-hand side of an assignment
+// is assigned to, or nil if it is not a plain variable
+func assignedObject(pass *analysis.Pass, assign *ast.AssignStmt, i int) types.Object {
+	if i >= len(assign.Lhs) {
+		return nil
+	}
+	ident, ok := assign.Lhs[i].(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+	return pass.TypesInfo.ObjectOf(ident)
+}
+
+// isTimeFunc checks if call is a call of the named function of package time
+func isTimeFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "time" && fn.Name() == name
+}
+
+// isEmptyBody checks for a method body that is empty or only returns nil
+func isEmptyBody(body *ast.BlockStmt) bool {
+	if body == nil {
+		return false
+	}
+	switch len(body.List) {
+	case 0:
+		return true
+	case 1:
+		ret, ok := body.List[0].(*ast.ReturnStmt)
+		if !ok {
+			return false
+		}
+		for _, result := range ret.Results {
+			if ident, ok := result.(*ast.Ident); !ok || ident.Name != "nil" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// checkDoneReturnsErr reports run methods returning a nil error from a
+// <-ctx.Done() case, which hides the cancellation from callers
+func checkDoneReturnsErr(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	if fn.Body == nil || !returnsError(pass, fn) {
+		return
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		clause, ok := n.(*ast.CommClause)
+		if !ok {
+			return true
+		}
+		ctx := doneContext(pass, clause.Comm)
+		if ctx == nil {
+			return true
+		}
+
+		ast.Inspect(clause, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			ret, ok := n.(*ast.ReturnStmt)
+			if !ok || len(ret.Results) == 0 {
+				return true
+			}
+			last, ok := ret.Results[len(ret.Results)-1].(*ast.Ident)
+			if ok && last.Name == "nil" {
+				name := types.ExprString(ctx)
+				reporter.Reportf(ret.Pos(),
+					"%s() returns nil when %s is cancelled, which hides the cancellation from callers; return %s.Err() instead",
+					fn.Name.Name, name, name)
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// returnsError checks if the last result of fn is an error
+func returnsError(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	results := obj.Type().(*types.Signature).Results()
+	if results.Len() == 0 {
+		return false
+	}
+	return types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// doneContext returns the context of a <-ctx.Done() receive, or nil if comm
+// is not one
+func doneContext(pass *analysis.Pass, comm ast.Stmt) ast.Expr {
+	var x ast.Expr
+	switch stmt := comm.(type) {
+	case *ast.ExprStmt:
+		x = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			x = stmt.Rhs[0]
+		}
+	}
+	unary, ok := x.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.ARROW {
+		return nil
+	}
+	call, ok := unary.X.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Done" || !isContextType(pass.TypesInfo.TypeOf(sel.X)) {
+		return nil
+	}
+	return sel.X
+}
+
+// isContextType checks for context.Context
+func isContextType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
 // LifecycleInfo contains information about lifecycle patterns
 type LifecycleInfo struct {
 	TypesWithRun     []string
@@ -0,0 +1,14 @@
+package lifecycle_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/lifecycle"
+)
+
+func TestLifecycleAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, lifecycle.Analyzer, "a")
+}
@@ -0,0 +1,129 @@
+package a
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bad: Close does nothing while Run owns a ticker
+type poller struct {
+	ticker *time.Ticker
+}
+
+func (p *poller) Run(ctx context.Context) error {
+	p.ticker = time.NewTicker(time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.ticker.C:
+		}
+	}
+}
+
+func (p *poller) Close() error { // want `Close\(\) does nothing, but Run\(\) creates a time.Ticker; stop the ticker before returning`
+	return nil
+}
+
+// Bad: empty Stop while Run starts goroutines
+type fanout struct {
+	work chan int
+}
+
+func (f *fanout) Start(ctx context.Context) error {
+	go f.worker(ctx)
+	go f.worker(ctx)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (f *fanout) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.work:
+		}
+	}
+}
+
+func (f *fanout) Stop() {} // want `Stop\(\) does nothing, but Start\(\) starts goroutines; wait for the goroutines \(e.g. with a sync.WaitGroup\) before returning`
+
+// Good: the ticker is stopped by Run itself
+type heartbeat struct{}
+
+func (h *heartbeat) Run(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *heartbeat) Close() error { return nil }
+
+// Good: Close waits for the goroutines
+type pool struct {
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func (p *pool) Run(ctx context.Context) error {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		<-p.quit
+	}()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (p *pool) Close() error {
+	close(p.quit)
+	p.wg.Wait()
+	return nil
+}
+
+// Bad: cancellation reported as a clean exit
+type consumer struct {
+	events chan string
+}
+
+func (c *consumer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil // want `Run\(\) returns nil when ctx is cancelled, which hides the cancellation from callers; return ctx.Err\(\) instead`
+		case <-c.events:
+		}
+	}
+}
+
+func (c *consumer) Close() error {
+	close(c.events)
+	return nil
+}
+
+// Suppressed with nolint
+type server struct {
+	done chan struct{}
+}
+
+func (s *server) Serve(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil //nolint:lifecycle // shutdown by the caller is a clean exit
+	case <-s.done:
+		return nil
+	}
+}
+
+func (s *server) Shutdown() error {
+	close(s.done)
+	return nil
+}
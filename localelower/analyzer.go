@@ -0,0 +1,391 @@
+// Package localelower provides an analyzer that detects case-insensitive string
+// handling that allocates needlessly or falls into locale traps.
+//
+// strings.ToLower(a) == strings.ToLower(b) allocates two strings and still gets
+// some Unicode case folding wrong; strings.EqualFold does it correctly without
+// allocating. Keys normalized on one side of a map but not the other silently
+// miss lookups.
+package localelower
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect case-insensitive comparisons that should use strings.EqualFold
+
+This analyzer detects:
+1. strings.ToLower(a) == strings.ToLower(b) (use strings.EqualFold)
+2. Comparisons where only one side was lowercased/uppercased
+3. strings.Title, which is deprecated (use golang.org/x/text/cases)
+4. Map keys normalized with ToLower/ToUpper at insert but not at lookup,
+   or the other way around
+
+Bad:
+    if strings.ToLower(r.Header.Get("X-Mode")) == strings.ToLower(mode) { ... }
+
+Good:
+    if strings.EqualFold(r.Header.Get("X-Mode"), mode) { ... }
+
+EqualFold uses Unicode simple case folding, does not allocate, and avoids
+locale-dependent surprises such as the Turkish dotless i.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "localelower",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// caseFuncs are the strings functions that normalize case
+var caseFuncs = map[string]bool{
+	"ToLower": true,
+	"ToUpper": true,
+}
+
+// locals records what the functions of a package assign to their locals
+type locals struct {
+	// derived maps locals defined from a single-argument call, like
+	// words := split(name), to that argument
+	derived map[types.Object]ast.Expr
+	// normalized holds locals assigned a strings.ToLower or ToUpper result
+	normalized map[types.Object]bool
+}
+
+// mapKeyUse records how a map was indexed at one site.
+type mapKeyUse struct {
+	pos        token.Pos
+	normalized bool
+	insert     bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	// Map key uses are collected package-wide, then compared per map variable
+	mapUses := make(map[types.Object][]mapKeyUse)
+	var mapOrder []types.Object
+
+	locals := &locals{
+		derived:    make(map[types.Object]ast.Expr),
+		normalized: make(map[types.Object]bool),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Body == nil {
+				return
+			}
+			for obj, uses := range collectMapUses(pass, node.Body, locals.normalized) {
+				if _, seen := mapUses[obj]; !seen {
+					mapOrder = append(mapOrder, obj)
+				}
+				mapUses[obj] = append(mapUses[obj], uses...)
+			}
+			collectDerived(pass, node.Body, locals.derived)
+		case *ast.BinaryExpr:
+			checkComparison(reporter, pass, node, locals)
+		case *ast.CallExpr:
+			if name, _ := caseCall(pass, node); name == "Title" {
+				reporter.Reportf(node.Pos(),
+					"strings.Title is deprecated and does not handle Unicode word boundaries; use cases.Title from golang.org/x/text/cases")
+			}
+		}
+	})
+
+	for _, obj := range mapOrder {
+		checkMapNormalization(reporter, obj, mapUses[obj])
+	}
+
+	return nil, nil
+}
+
+// caseCall returns the strings function name and its first argument if expr
+// is a call into package strings
+func caseCall(pass *analysis.Pass, expr ast.Expr) (string, ast.Expr) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", nil
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "strings" {
+		return "", nil
+	}
+
+	return fn.Name(), call.Args[0]
+}
+
+// checkComparison flags ==/!= comparisons involving case-normalized strings
+func checkComparison(reporter *nolint.Reporter, pass *analysis.Pass, bin *ast.BinaryExpr, locals *locals) {
+	if bin.Op != token.EQL && bin.Op != token.NEQ {
+		return
+	}
+
+	leftFn, leftArg := caseCall(pass, bin.X)
+	rightFn, rightArg := caseCall(pass, bin.Y)
+	leftCase := caseFuncs[leftFn]
+	rightCase := caseFuncs[rightFn]
+
+	switch {
+	case leftCase && rightCase && leftFn == rightFn:
+		reportEqualFold(reporter, pass, bin, leftFn, leftArg, rightArg)
+
+	case leftCase && rightCase:
+		reporter.Reportf(bin.Pos(),
+			"comparing strings.%s with strings.%s only matches strings without letters; use strings.EqualFold",
+			leftFn, rightFn)
+
+	case leftCase:
+		checkOneSided(reporter, pass, bin, leftFn, leftArg, bin.Y, locals)
+
+	case rightCase:
+		checkOneSided(reporter, pass, bin, rightFn, rightArg, bin.X, locals)
+	}
+}
+
+// reportEqualFold reports ToLower(a) == ToLower(b) with a fix to EqualFold(a, b)
+func reportEqualFold(reporter *nolint.Reporter, pass *analysis.Pass, bin *ast.BinaryExpr, fn string, a, b ast.Expr) {
+	replacement := "strings.EqualFold(" + render(pass, a) + ", " + render(pass, b) + ")"
+	if bin.Op == token.NEQ {
+		replacement = "!" + replacement
+	}
+
+	reporter.Report(&analysis.Diagnostic{
+		Pos: bin.Pos(),
+		End: bin.End(),
+		Message: "comparing strings." + fn + " results allocates and mishandles some Unicode case folding; " +
+			"use strings.EqualFold",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: "Replace with strings.EqualFold",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     bin.Pos(),
+				End:     bin.End(),
+				NewText: []byte(replacement),
+			}},
+		}},
+	})
+}
+
+// checkOneSided flags comparisons where only one side was normalized. A
+// value compared with its own normalized form, as in s != strings.ToLower(s),
+// is a check for letters of the other case and is left alone, as is another
+// form derived from the same value, like split(name) != strings.ToLower(name).
+// A local that holds a normalized string, like lb := strings.ToLower(b), counts
+// as a normalized side.
+func checkOneSided(reporter *nolint.Reporter, pass *analysis.Pass, bin *ast.BinaryExpr, fn string, arg, other ast.Expr, locals *locals) {
+	if isNormalized(pass, other, locals.normalized) || sameExpr(arg, derivedFrom(pass, other, locals.derived)) {
+		return
+	}
+	if tv, ok := pass.TypesInfo.Types[other]; ok && tv.Value != nil {
+		// Constant: only a problem if it can never match the normalized side
+		s := tv.Value.ExactString()
+		if (fn == "ToLower" && s != strings.ToLower(s)) || (fn == "ToUpper" && s != strings.ToUpper(s)) {
+			reporter.Reportf(other.Pos(),
+				"comparison with strings.%s result can never match constant %s", fn, s)
+		}
+		return
+	}
+
+	reporter.Reportf(bin.Pos(),
+		"only one side of this comparison is normalized with strings.%s; use strings.EqualFold or normalize both sides",
+		fn)
+}
+
+// derivedFrom returns the value expr was computed from: the argument of a
+// single-argument call, or of the call that defined a local, else expr itself
+func derivedFrom(pass *analysis.Pass, expr ast.Expr, derived map[types.Object]ast.Expr) ast.Expr {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		if len(e.Args) == 1 {
+			return e.Args[0]
+		}
+	case *ast.Ident:
+		if src, ok := derived[pass.TypesInfo.Uses[e]]; ok {
+			return src
+		}
+	}
+	return expr
+}
+
+// collectDerived records locals defined from a single-argument call in body,
+// like words := split(name), with that argument
+func collectDerived(pass *analysis.Pass, body *ast.BlockStmt, derived map[types.Object]ast.Expr) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			call, ok := ast.Unparen(assign.Rhs[i]).(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				continue
+			}
+			if obj := pass.TypesInfo.Defs[ident]; obj != nil {
+				derived[obj] = call.Args[0]
+			}
+		}
+		return true
+	})
+}
+
+// sameExpr reports whether a and b are the same expression, ignoring parentheses
+func sameExpr(a, b ast.Expr) bool {
+	return types.ExprString(ast.Unparen(a)) == types.ExprString(ast.Unparen(b))
+}
+
+// render prints an expression back to source
+func render(pass *analysis.Pass, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pass.Fset, expr); err != nil {
+		return types.ExprString(expr)
+	}
+	return buf.String()
+}
+
+// collectMapUses finds index expressions on string-keyed maps in a function
+// body, recording locals holding normalized strings, like
+// k := strings.ToLower(name), in normalizedVars
+func collectMapUses(pass *analysis.Pass, body *ast.BlockStmt, normalizedVars map[types.Object]bool) map[types.Object][]mapKeyUse {
+	uses := make(map[types.Object][]mapKeyUse)
+
+	// Index expressions that are assignment targets
+	inserts := make(map[*ast.IndexExpr]bool)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if idx, ok := lhs.(*ast.IndexExpr); ok {
+					inserts[idx] = true
+				}
+			}
+			if len(node.Lhs) == len(node.Rhs) {
+				for i, lhs := range node.Lhs {
+					ident, ok := lhs.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					if fn, _ := caseCall(pass, node.Rhs[i]); caseFuncs[fn] {
+						if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+							normalizedVars[obj] = true
+						}
+					}
+				}
+			}
+
+		case *ast.IndexExpr:
+			obj := mapObject(pass, node.X)
+			if obj == nil {
+				return true
+			}
+			if tv, ok := pass.TypesInfo.Types[node.Index]; ok && tv.Value != nil {
+				// Constant keys are deliberate
+				return true
+			}
+			uses[obj] = append(uses[obj], mapKeyUse{
+				pos:        node.Index.Pos(),
+				normalized: isNormalized(pass, node.Index, normalizedVars),
+				insert:     inserts[node],
+			})
+		}
+		return true
+	})
+
+	return uses
+}
+
+// isNormalized checks if a key expression was produced by ToLower/ToUpper
+func isNormalized(pass *analysis.Pass, expr ast.Expr, normalizedVars map[types.Object]bool) bool {
+	if fn, _ := caseCall(pass, expr); caseFuncs[fn] {
+		return true
+	}
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+		return normalizedVars[pass.TypesInfo.ObjectOf(ident)]
+	}
+	return false
+}
+
+// mapObject returns the variable or field for a string-keyed map expression
+func mapObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return nil
+	}
+	m, ok := t.Underlying().(*types.Map)
+	if !ok {
+		return nil
+	}
+	if basic, ok := m.Key().Underlying().(*types.Basic); !ok || basic.Kind() != types.String {
+		return nil
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return pass.TypesInfo.ObjectOf(e)
+	case *ast.SelectorExpr:
+		return pass.TypesInfo.ObjectOf(e.Sel)
+	}
+	return nil
+}
+
+// checkMapNormalization flags lookups whose normalization disagrees with inserts
+func checkMapNormalization(reporter *nolint.Reporter, obj types.Object, uses []mapKeyUse) {
+	normalizedInserts, rawInserts := 0, 0
+	for _, u := range uses {
+		if !u.insert {
+			continue
+		}
+		if u.normalized {
+			normalizedInserts++
+		} else {
+			rawInserts++
+		}
+	}
+
+	// Mixed or absent inserts give no clear convention to compare against
+	if (normalizedInserts == 0) == (rawInserts == 0) {
+		return
+	}
+	insertsNormalized := normalizedInserts > 0
+
+	for _, u := range uses {
+		if u.insert || u.normalized == insertsNormalized {
+			continue
+		}
+		if insertsNormalized {
+			reporter.Reportf(u.pos,
+				"map %q keys are case-normalized at insert but this lookup is not; normalize the key the same way", obj.Name())
+		} else {
+			reporter.Reportf(u.pos,
+				"map %q keys are inserted as-is but this lookup is case-normalized; normalize at insert too", obj.Name())
+		}
+	}
+}
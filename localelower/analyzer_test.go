@@ -0,0 +1,14 @@
+package localelower_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/localelower"
+)
+
+func TestLocaleLowerAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, localelower.Analyzer, "a")
+}
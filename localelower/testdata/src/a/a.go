@@ -0,0 +1,90 @@
+package a
+
+import "strings"
+
+// Bad: both sides lowercased
+func SameMode(a, b string) bool {
+	return strings.ToLower(a) == strings.ToLower(b) // want `use strings.EqualFold`
+}
+
+// Bad: negated comparison
+func DifferentMode(a, b string) bool {
+	return strings.ToUpper(a) != strings.ToUpper(b) // want `use strings.EqualFold`
+}
+
+// Bad: only one side normalized
+func OneSided(a, b string) bool {
+	return strings.ToLower(a) == b // want `only one side of this comparison is normalized`
+}
+
+// Good: comparing a value with its own lowered form checks its case
+func HasUpper(s string) bool {
+	return s != strings.ToLower(s)
+}
+
+// Good: the same holds for field selectors and parenthesized operands
+func IsUpper(u struct{ Name string }) bool {
+	return strings.ToUpper(u.Name) == (u.Name)
+}
+
+// Good: another form of the same value is not a one-sided comparison
+func HasWords(name string) bool {
+	if words := strings.ToTitle(name); words != strings.ToLower(name) {
+		return true
+	}
+	return strings.TrimSpace(name) != strings.ToLower(name)
+}
+
+// Good: both sides normalized, one through a local
+func SameModeLocal(a, b string) bool {
+	lb := strings.ToLower(b)
+	return strings.ToLower(a) == lb
+}
+
+// Good: both sides normalized, one by reassignment
+func SameModeReassigned(a, b string) bool {
+	b = strings.ToLower(b)
+	return strings.ToLower(a) == b
+}
+
+// Bad: constant can never match
+func NeverMatches(a string) bool {
+	return strings.ToLower(a) == "Admin" // want `can never match constant "Admin"`
+}
+
+// Good: constant already lowercase
+func MatchesConstant(a string) bool {
+	return strings.ToLower(a) == "admin"
+}
+
+// Good: EqualFold
+func Good(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// Bad: deprecated
+func Heading(s string) string {
+	return strings.Title(s) // want `strings.Title is deprecated`
+}
+
+var users = map[string]int{}
+
+func AddUser(name string, id int) {
+	users[strings.ToLower(name)] = id
+}
+
+func AddAlias(name string, id int) {
+	key := strings.ToLower(name)
+	users[key] = id
+}
+
+// Bad: lookup without normalization
+func FindUser(name string) int {
+	return users[name] // want `map "users" keys are case-normalized at insert but this lookup is not`
+}
+
+// Good: lookup normalized the same way
+func HasUser(name string) bool {
+	_, ok := users[strings.ToLower(name)]
+	return ok
+}
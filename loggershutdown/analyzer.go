@@ -0,0 +1,547 @@
+// Package loggershutdown provides an analyzer that checks loggers and
+// OpenTelemetry providers are flushed before the program exits.
+//
+// zap buffers log entries; without logger.Sync() the last lines before exit,
+// usually the ones explaining why the program stopped, are lost. OpenTelemetry
+// providers batch spans and log records the same way and drop them unless
+// Shutdown runs, and anything logged after Shutdown goes nowhere.
+package loggershutdown
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check loggers and OpenTelemetry providers are flushed before exit
+
+This analyzer detects:
+1. Functions that construct a zap or otelzap logger but never defer Sync()
+2. Sync() errors logged through the logger that failed to sync
+3. Logging after an OpenTelemetry LoggerProvider (or, for otelzap, a
+   TracerProvider) was shut down in the same function
+4. OpenTelemetry log and trace providers that are constructed but never
+   shut down
+
+Bad:
+    logger := zap.Must(zap.NewProduction())
+    tp := sdktrace.NewTracerProvider()
+    otel.SetTracerProvider(tp)
+    ...
+    lp.Shutdown(ctx)
+    logger.Info("shutdown complete") // dropped
+
+Good:
+    logger := zap.Must(zap.NewProduction())
+    defer func() { _ = logger.Sync() }()
+
+    tp := sdktrace.NewTracerProvider()
+    defer func() { _ = tp.Shutdown(context.Background()) }()`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "loggershutdown",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+const (
+	zapPath     = "go.uber.org/zap"
+	otelzapPath = "github.com/uptrace/opentelemetry-go-extra/otelzap"
+)
+
+// loggerTypes are the logger types whose level methods write log entries,
+// keyed by "pkgpath.Type"
+var loggerTypes = map[string]bool{
+	zapPath + ".Logger":            true,
+	zapPath + ".SugaredLogger":     true,
+	otelzapPath + ".Logger":        true,
+	otelzapPath + ".SugaredLogger": true,
+	"log/slog.Logger":              true,
+}
+
+// providerKind distinguishes what a provider exports
+type providerKind int
+
+const (
+	logProvider providerKind = iota
+	traceProvider
+)
+
+// providerTypes are OpenTelemetry SDK providers that batch and must be shut down
+var providerTypes = map[string]providerKind{
+	"go.opentelemetry.io/otel/sdk/log.LoggerProvider":   logProvider,
+	"go.opentelemetry.io/otel/sdk/trace.TracerProvider": traceProvider,
+}
+
+// logLevels are the prefixes of logging methods (Info, Infof, Infow, InfoContext, ...)
+var logLevels = []string{"Debug", "Info", "Warn", "Error", "DPanic", "Panic", "Fatal", "Log"}
+
+// shutdown is a non-deferred provider Shutdown call
+type shutdown struct {
+	call *ast.CallExpr
+	name string
+	kind providerKind
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil {
+			return
+		}
+
+		checkLoggerSync(reporter, pass, fn)
+		checkSyncErrorLogged(reporter, pass, fn.Body)
+		checkProviders(reporter, pass, fn.Body)
+		checkLogAfterShutdown(reporter, pass, fn.Body)
+	})
+
+	return nil, nil
+}
+
+// namedType returns "pkgpath.Type" for T or *T
+func namedType(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+// methodCall returns the receiver type and method name of a method call
+func methodCall(pass *analysis.Pass, call *ast.CallExpr) (string, string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return "", "", false
+	}
+	return namedType(selection.Recv()), sel.Sel.Name, true
+}
+
+// isLogCall checks for a logging method on a known logger type; otelOnly
+// restricts it to otelzap loggers
+func isLogCall(pass *analysis.Pass, call *ast.CallExpr, otelOnly bool) bool {
+	recv, method, ok := methodCall(pass, call)
+	if !ok || !loggerTypes[recv] {
+		return false
+	}
+	if otelOnly && !strings.HasPrefix(recv, otelzapPath+".") {
+		return false
+	}
+	for _, level := range logLevels {
+		if strings.HasPrefix(method, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSyncCall checks for Sync() on a known logger type
+func isSyncCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	recv, method, ok := methodCall(pass, call)
+	return ok && method == "Sync" && loggerTypes[recv]
+}
+
+// isLoggerConstructor checks for zap.New*, zap.Config.Build, and otelzap.New
+func isLoggerConstructor(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+
+	switch fn.Pkg().Path() {
+	case zapPath:
+		if fn.Name() == "Build" {
+			return true
+		}
+		return fn.Type().(*types.Signature).Recv() == nil && strings.HasPrefix(fn.Name(), "New") &&
+			returnsLogger(fn)
+	case otelzapPath:
+		return fn.Name() == "New"
+	}
+	return false
+}
+
+// returnsLogger checks if fn's first result is a zap logger
+func returnsLogger(fn *types.Func) bool {
+	results := fn.Type().(*types.Signature).Results()
+	return results.Len() > 0 && loggerTypes[namedType(results.At(0).Type())]
+}
+
+// checkLoggerSync reports a logger constructed in fn without a deferred Sync
+func checkLoggerSync(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	var construction *ast.CallExpr
+	var loggerVars []types.Object
+	deferredSync := false
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Closures other than deferred ones run at unknown times
+			return false
+		case *ast.DeferStmt:
+			ast.Inspect(node.Call, func(child ast.Node) bool {
+				if call, ok := child.(*ast.CallExpr); ok && isSyncCall(pass, call) {
+					deferredSync = true
+				}
+				return true
+			})
+			return false
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				if !containsLoggerConstructor(pass, rhs) || i >= len(node.Lhs) {
+					continue
+				}
+				if ident, ok := node.Lhs[i].(*ast.Ident); ok {
+					if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+						loggerVars = append(loggerVars, obj)
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if construction == nil && isLoggerConstructor(pass, node) {
+				construction = node
+			}
+		}
+		return true
+	})
+
+	if construction == nil || deferredSync || loggerEscapes(pass, fn, loggerVars) {
+		return
+	}
+
+	reporter.Reportf(construction.Pos(),
+		"logger is constructed but Sync() is never deferred; buffered log entries are lost on exit, add defer func() { _ = logger.Sync() }()")
+}
+
+// containsLoggerConstructor checks for a constructor call in expr, including
+// wrapped ones such as zap.Must(zap.NewProduction())
+func containsLoggerConstructor(pass *analysis.Pass, expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isLoggerConstructor(pass, call) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// loggerEscapes checks if a constructed logger leaves the function, in which
+// case the caller is responsible for syncing it. zap.ReplaceGlobals counts
+// as escaping except in main, which must sync the global logger itself.
+func loggerEscapes(pass *analysis.Pass, fn *ast.FuncDecl, vars []types.Object) bool {
+	isVar := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		obj := pass.TypesInfo.ObjectOf(ident)
+		for _, v := range vars {
+			if obj == v {
+				return true
+			}
+		}
+		return false
+	}
+	// mentions checks for the logger anywhere in expr, e.g. logger.With(...)
+	mentions := func(expr ast.Expr) bool {
+		found := false
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if e, ok := n.(ast.Expr); ok && isVar(e) {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+
+	escapes := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for _, r := range node.Results {
+				isLogger := loggerTypes[namedType(pass.TypesInfo.TypeOf(r))]
+				escapes = escapes || (isLogger && mentions(r)) || containsLoggerConstructor(pass, r)
+			}
+		case *ast.AssignStmt:
+			for i, r := range node.Rhs {
+				if i < len(node.Lhs) && isVar(r) {
+					_, isField := node.Lhs[i].(*ast.SelectorExpr)
+					escapes = escapes || isField || isPackageVar(pass, node.Lhs[i])
+				}
+			}
+		case *ast.CallExpr:
+			if fn.Name.Name != "main" && isReplaceGlobals(pass, node) && len(node.Args) == 1 && isVar(node.Args[0]) {
+				escapes = true
+			}
+		}
+		return !escapes
+	})
+	return escapes
+}
+
+// isPackageVar checks if expr is a package-level variable
+func isPackageVar(pass *analysis.Pass, expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	return ok && v.Parent() == pass.Pkg.Scope()
+}
+
+// isReplaceGlobals checks for zap.ReplaceGlobals
+func isReplaceGlobals(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == zapPath && fn.Name() == "ReplaceGlobals"
+}
+
+// checkSyncErrorLogged reports Sync errors logged through the logger that
+// just failed to sync
+func checkSyncErrorLogged(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok {
+			return true
+		}
+
+		synced := syncReceiver(pass, ifStmt.Init)
+		if synced == "" {
+			synced = syncReceiver(pass, ifStmt.Cond)
+		}
+		if synced == "" {
+			return true
+		}
+
+		ast.Inspect(ifStmt.Body, func(child ast.Node) bool {
+			call, ok := child.(*ast.CallExpr)
+			if !ok || !isLogCall(pass, call, false) {
+				return true
+			}
+			if receiverString(call) == synced {
+				reporter.Reportf(call.Pos(),
+					"Sync error is logged through %s, the logger that failed to sync; write it to os.Stderr or ignore it",
+					synced)
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// syncReceiver returns the receiver of a logger Sync call within node
+func syncReceiver(pass *analysis.Pass, node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	receiver := ""
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isSyncCall(pass, call) {
+			receiver = receiverString(call)
+		}
+		return receiver == ""
+	})
+	return receiver
+}
+
+// receiverString returns the source text of a method call's receiver
+func receiverString(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	return types.ExprString(sel.X)
+}
+
+// providerConstructor checks for a function call returning an OpenTelemetry SDK provider
+func providerConstructor(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	t := pass.TypesInfo.TypeOf(call)
+	if tuple, ok := t.(*types.Tuple); ok && tuple.Len() > 0 {
+		t = tuple.At(0).Type()
+	}
+	_, ok = providerTypes[namedType(t)]
+	return ok && !isMethod(pass, call)
+}
+
+// isMethod checks if call is a method call
+func isMethod(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	_, ok = pass.TypesInfo.Selections[sel]
+	return ok
+}
+
+// checkProviders reports providers constructed in body that are never shut
+// down and do not leave the function
+func checkProviders(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	type provider struct {
+		call *ast.CallExpr
+		obj  types.Object
+	}
+	var providers []provider
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || !providerConstructor(pass, assign.Rhs[0]) {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+			if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+				providers = append(providers, provider{call: assign.Rhs[0].(*ast.CallExpr), obj: obj})
+			}
+		}
+		return true
+	})
+
+	for _, p := range providers {
+		if providerHandled(pass, body, p.obj) {
+			continue
+		}
+		reporter.Reportf(p.call.Pos(),
+			"%s is constructed but never shut down; call %s.Shutdown(ctx) before exit or buffered telemetry is lost",
+			p.obj.Name(), p.obj.Name())
+	}
+}
+
+// providerHandled checks if a provider's Shutdown is referenced, or the
+// provider is handed to code other than a global Set*Provider function
+func providerHandled(pass *analysis.Pass, body *ast.BlockStmt, obj types.Object) bool {
+	isProvider := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(ident) == obj
+	}
+
+	handled := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			// tp.Shutdown(ctx), defer tp.Shutdown(ctx), or shutdowns = append(shutdowns, tp.Shutdown)
+			if node.Sel.Name == "Shutdown" && isProvider(node.X) {
+				handled = true
+			}
+		case *ast.ReturnStmt:
+			for _, r := range node.Results {
+				handled = handled || isProvider(r)
+			}
+		case *ast.AssignStmt:
+			for _, r := range node.Rhs {
+				handled = handled || isProvider(r)
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				handled = handled || isProvider(elt)
+			}
+		case *ast.CallExpr:
+			if isSetProvider(node) {
+				return true
+			}
+			for _, arg := range node.Args {
+				handled = handled || isProvider(arg)
+			}
+		}
+		return !handled
+	})
+	return handled
+}
+
+// isSetProvider checks for otel.SetTracerProvider, global.SetLoggerProvider,
+// and similar registration functions that do not take over shutdown
+func isSetProvider(call *ast.CallExpr) bool {
+	var name string
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	case *ast.Ident:
+		name = fun.Name
+	}
+	return strings.HasPrefix(name, "Set") && strings.HasSuffix(name, "Provider")
+}
+
+// checkLogAfterShutdown reports log calls that run after a provider was shut
+// down in the same function
+func checkLogAfterShutdown(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var shutdowns []shutdown
+	var logCalls []*ast.CallExpr
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit, *ast.DeferStmt, *ast.GoStmt:
+			// Run at a different time than their position suggests
+			return false
+		case *ast.CallExpr:
+			recv, method, ok := methodCall(pass, node)
+			if !ok {
+				return true
+			}
+			if kind, isProvider := providerTypes[recv]; isProvider && method == "Shutdown" {
+				shutdowns = append(shutdowns, shutdown{call: node, name: receiverString(node), kind: kind})
+				return true
+			}
+			if loggerTypes[recv] {
+				logCalls = append(logCalls, node)
+			}
+		}
+		return true
+	})
+
+	for _, call := range logCalls {
+		for _, s := range shutdowns {
+			if call.Pos() < s.call.End() || !isLogCall(pass, call, s.kind == traceProvider) {
+				continue
+			}
+			reporter.Reportf(call.Pos(),
+				"log call after %s.Shutdown at line %d; %s",
+				s.name, pass.Fset.Position(s.call.Pos()).Line, droppedReason(s.kind))
+			break
+		}
+	}
+}
+
+// droppedReason explains what happens to a log entry after the provider is gone
+func droppedReason(kind providerKind) string {
+	if kind == traceProvider {
+		return "span events recorded by otelzap are dropped once the tracer provider is shut down"
+	}
+	return "the record is dropped once the logger provider is shut down; log before shutting it down"
+}
@@ -0,0 +1,14 @@
+package loggershutdown_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/loggershutdown"
+)
+
+func TestLoggerShutdownAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, loggershutdown.Analyzer, "a")
+}
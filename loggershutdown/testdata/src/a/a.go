@@ -0,0 +1,112 @@
+package a
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// Bad: Sync is never deferred
+func MissingSync() {
+	logger := zap.Must(zap.NewProduction()) // want `logger is constructed but Sync\(\) is never deferred`
+	logger.Info("starting")
+}
+
+// Bad: Sync called directly, skipped on early return
+func DirectSync() error {
+	logger, err := zap.NewProductionConfig().Build() // want `logger is constructed but Sync\(\) is never deferred`
+	if err != nil {
+		return err
+	}
+	logger.Info("starting")
+	return logger.Sync()
+}
+
+// Bad: the failing logger reports its own failure
+func SyncErrorLogged() {
+	logger := zap.Must(zap.NewProduction())
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			logger.Error("sync failed", zap.Error(err)) // want `Sync error is logged through logger, the logger that failed to sync`
+		}
+	}()
+}
+
+// Bad: logging after the provider is gone
+func LogAfterShutdown(ctx context.Context) {
+	logger := zap.Must(zap.NewProduction())
+	defer func() { _ = logger.Sync() }()
+
+	lp := sdklog.NewLoggerProvider()
+	global.SetLoggerProvider(lp)
+
+	_ = lp.Shutdown(ctx)
+	logger.Info("shutdown complete") // want `log call after lp.Shutdown at line 50`
+}
+
+// Bad: otelzap span events after the tracer provider is gone
+func OtelzapAfterTraceShutdown(ctx context.Context, log *otelzap.Logger) {
+	tp := sdktrace.NewTracerProvider()
+	_ = tp.Shutdown(ctx)
+	log.InfoContext(ctx, "stopped") // want `log call after tp.Shutdown at line 57; span events recorded by otelzap are dropped`
+}
+
+// Bad: registered globally but never shut down
+func ProviderNeverShutDown() {
+	tp := sdktrace.NewTracerProvider() // want `tp is constructed but never shut down`
+	otel.SetTracerProvider(tp)
+}
+
+// Good: canonical teardown
+func Canonical(ctx context.Context) error {
+	logger := zap.Must(zap.NewProduction())
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "sync logger: %v\n", err)
+		}
+	}()
+
+	tp := sdktrace.NewTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	lp := sdklog.NewLoggerProvider()
+	global.SetLoggerProvider(lp)
+
+	log := otelzap.New(logger)
+	log.InfoContext(ctx, "starting")
+
+	logger.Info("shutting down")
+	return lp.Shutdown(ctx)
+}
+
+// Good: the caller syncs a returned logger
+func NewLogger() (*zap.Logger, error) {
+	return zap.NewProduction()
+}
+
+// Good: the caller syncs a logger built here
+func BuildLogger() *zap.Logger {
+	logger := zap.Must(zap.NewDevelopment())
+	return logger.With()
+}
+
+// Good: the caller shuts down a returned provider
+func NewTracing() (*sdktrace.TracerProvider, error) {
+	tp := sdktrace.NewTracerProvider()
+	return tp, nil
+}
+
+// Good: shutdown registered for later
+func CollectShutdowns() []func(context.Context) error {
+	var shutdowns []func(context.Context) error
+	tp := sdktrace.NewTracerProvider()
+	shutdowns = append(shutdowns, tp.Shutdown)
+	return shutdowns
+}
@@ -0,0 +1,14 @@
+package otelzap
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Logger struct{ *zap.Logger }
+
+func New(l *zap.Logger) *Logger { return &Logger{l} }
+
+func (l *Logger) InfoContext(ctx context.Context, msg string, fields ...zap.Field) {}
+func (l *Logger) Sync() error                                                      { return nil }
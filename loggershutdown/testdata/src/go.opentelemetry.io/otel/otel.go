@@ -0,0 +1,3 @@
+package otel
+
+func SetTracerProvider(p interface{}) {}
@@ -0,0 +1,9 @@
+package log
+
+import "context"
+
+type LoggerProvider struct{}
+
+func NewLoggerProvider() *LoggerProvider { return &LoggerProvider{} }
+
+func (p *LoggerProvider) Shutdown(ctx context.Context) error { return nil }
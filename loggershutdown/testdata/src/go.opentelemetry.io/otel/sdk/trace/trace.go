@@ -0,0 +1,9 @@
+package trace
+
+import "context"
+
+type TracerProvider struct{}
+
+func NewTracerProvider() *TracerProvider { return &TracerProvider{} }
+
+func (p *TracerProvider) Shutdown(ctx context.Context) error { return nil }
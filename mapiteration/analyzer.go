@@ -0,0 +1,455 @@
+// Package mapiteration provides an analyzer that detects nondeterministic map
+// iteration order leaking into ordered outputs.
+//
+// Go randomizes map iteration order on purpose. A slice built by ranging over
+// a map and then returned, marshaled, or compared without sorting differs from
+// run to run, which shows up as flaky tests and generated files that change on
+// every build.
+package mapiteration
+
+import (
+	"flag"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect nondeterministic map iteration order reaching ordered outputs
+
+This analyzer detects:
+1. Slices appended to while ranging over a map and then returned,
+   marshaled, written, or compared without being sorted first
+2. Loops over a map that stop after the first iteration to pick "the first"
+   element, which is an arbitrary choice
+3. Output written directly while ranging over a map, as custom serializers
+   do (enabled with -check-writes; fmt sorts maps it prints itself)
+
+Bad:
+    var names []string
+    for name := range users {
+        names = append(names, name)
+    }
+    return names // different order on every call
+
+Good:
+    var names []string
+    for name := range users {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+
+    // or, since Go 1.23
+    return slices.Sorted(maps.Keys(users))
+
+Flags:
+    -check-writes  report output written while ranging over a map`
+
+var checkWrites bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "mapiteration",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("mapiteration", flag.ExitOnError)
+	fs.BoolVar(&checkWrites, "check-writes", false,
+		"report output written while ranging over a map")
+	return *fs
+}
+
+// orderedSinks maps packages to the functions whose output depends on slice
+// order; an empty list means every function in the package
+var orderedSinks = map[string][]string{
+	"encoding/json":                       nil,
+	"encoding/xml":                        nil,
+	"encoding/csv":                        nil,
+	"gopkg.in/yaml.v2":                    nil,
+	"gopkg.in/yaml.v3":                    nil,
+	"sigs.k8s.io/yaml":                    nil,
+	"fmt":                                 nil,
+	"strings":                             {"Join"},
+	"bytes":                               {"Join"},
+	"reflect":                             {"DeepEqual"},
+	"github.com/google/go-cmp/cmp":        {"Diff", "Equal"},
+	"github.com/stretchr/testify/assert":  {"Equal", "EqualValues", "Exactly"},
+	"github.com/stretchr/testify/require": {"Equal", "EqualValues", "Exactly"},
+}
+
+// sortFuncs are the sort and slices functions that order their first argument
+var sortFuncs = map[string]bool{
+	"sort.Strings":          true,
+	"sort.Ints":             true,
+	"sort.Float64s":         true,
+	"sort.Slice":            true,
+	"sort.SliceStable":      true,
+	"sort.Sort":             true,
+	"sort.Stable":           true,
+	"slices.Sort":           true,
+	"slices.SortFunc":       true,
+	"slices.SortStableFunc": true,
+}
+
+// mapSlice is a slice appended to inside a map range loop
+type mapSlice struct {
+	obj  types.Object
+	loop *ast.RangeStmt
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			body = node.Body
+		case *ast.FuncLit:
+			body = node.Body
+		}
+		if body == nil {
+			return
+		}
+
+		checkFunc(reporter, pass, body)
+	})
+
+	return nil, nil
+}
+
+// isMap checks if expr has a map type
+func isMap(pass *analysis.Pass, expr ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
+
+// calleeFunc returns the package path and name of a called package-level
+// function, e.g. "sort", "Strings"
+func calleeFunc(pass *analysis.Pass, call *ast.CallExpr) (string, string) {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.IndexExpr:
+		// slices.SortFunc[[]T] with explicit instantiation
+		if sel, ok := fun.X.(*ast.SelectorExpr); ok {
+			ident = sel.Sel
+		}
+	}
+	if ident == nil {
+		return "", ""
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return "", ""
+	}
+	if fn.Type().(*types.Signature).Recv() != nil {
+		// Methods such as (*json.Encoder).Encode count as their package
+		return fn.Pkg().Path(), "(" + fn.Name() + ")"
+	}
+	return fn.Pkg().Path(), fn.Name()
+}
+
+// checkFunc inspects the map range loops directly in one function body
+func checkFunc(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var built []mapSlice
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Analyzed separately
+			return false
+		case *ast.RangeStmt:
+			if !isMap(pass, node.X) {
+				return true
+			}
+			for _, obj := range appendedSlices(pass, node) {
+				built = append(built, mapSlice{obj: obj, loop: node})
+			}
+			checkFirstElement(reporter, pass, node)
+			if checkWrites {
+				checkLoopWrites(reporter, pass, node)
+			}
+		}
+		return true
+	})
+
+	for _, s := range built {
+		checkOrderedUse(reporter, pass, body, s)
+	}
+}
+
+// appendedSlices returns slices declared before the loop that the loop body
+// appends to
+func appendedSlices(pass *analysis.Pass, loop *ast.RangeStmt) []types.Object {
+	var objs []types.Object
+	seen := make(map[types.Object]bool)
+
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok || !isAppendTo(pass, assign.Rhs[i], ident) {
+				continue
+			}
+			obj := pass.TypesInfo.ObjectOf(ident)
+			if obj == nil || seen[obj] || obj.Pos() > loop.Pos() {
+				continue
+			}
+			seen[obj] = true
+			objs = append(objs, obj)
+		}
+		return true
+	})
+
+	return objs
+}
+
+// isAppendTo checks for append(ident, ...)
+func isAppendTo(pass *analysis.Pass, expr ast.Expr, ident *ast.Ident) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || fun.Name != "append" {
+		return false
+	}
+	if _, ok := pass.TypesInfo.Uses[fun].(*types.Builtin); !ok {
+		return false
+	}
+	first, ok := call.Args[0].(*ast.Ident)
+	return ok && pass.TypesInfo.ObjectOf(first) == pass.TypesInfo.ObjectOf(ident)
+}
+
+// refersTo checks if expr is the variable obj, optionally behind & or parens
+func refersTo(pass *analysis.Pass, expr ast.Expr, obj types.Object) bool {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+	ident, ok := expr.(*ast.Ident)
+	return ok && pass.TypesInfo.ObjectOf(ident) == obj
+}
+
+// mentions checks if obj appears anywhere in expr
+func mentions(pass *analysis.Pass, expr ast.Expr, obj types.Object) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkOrderedUse reports the first order-sensitive use of a map-built slice
+// after the loop that is not preceded by a sort
+func checkOrderedUse(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt, s mapSlice) {
+	var sorted token.Pos
+	reported := false
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if reported || n == nil || n.End() <= s.loop.End() && !contains(n, s.loop) {
+			return false
+		}
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if n.Pos() < s.loop.End() {
+			// Descend to reach statements after the loop
+			return true
+		}
+
+		var use string
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for _, r := range node.Results {
+				if refersTo(pass, r, s.obj) {
+					use = "returned"
+				}
+			}
+		case *ast.CallExpr:
+			pkg, name := calleeFunc(pass, node)
+			if sortFuncs[pkg+"."+name] && len(node.Args) > 0 && mentions(pass, node.Args[0], s.obj) {
+				sorted = node.Pos()
+				return true
+			}
+			if isOrderedSink(pkg, name) {
+				for _, arg := range node.Args {
+					if refersTo(pass, arg, s.obj) {
+						use = sinkUse(pkg)
+					}
+				}
+			}
+		}
+
+		if use == "" || sorted.IsValid() {
+			return true
+		}
+
+		reporter.Reportf(n.Pos(),
+			"%s is built by ranging over a map at line %d and %s without sorting; map iteration order is random, sort it first",
+			s.obj.Name(), pass.Fset.Position(s.loop.Pos()).Line, use)
+		reported = true
+		return false
+	})
+}
+
+// contains checks if outer encloses inner
+func contains(outer, inner ast.Node) bool {
+	return outer.Pos() <= inner.Pos() && inner.End() <= outer.End()
+}
+
+// isOrderedSink checks if a call's output depends on the order of its arguments
+func isOrderedSink(pkg, name string) bool {
+	names, ok := orderedSinks[pkg]
+	if !ok {
+		return false
+	}
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sinkUse describes what an ordered sink does with the slice
+func sinkUse(pkg string) string {
+	switch {
+	case pkg == "fmt":
+		return "printed"
+	case pkg == "strings" || pkg == "bytes":
+		return "joined"
+	case pkg == "reflect" || strings.HasPrefix(pkg, "github.com/"):
+		return "compared"
+	}
+	return "marshaled"
+}
+
+// checkFirstElement reports loops that always stop after one iteration and
+// use the key or value they got
+func checkFirstElement(reporter *nolint.Reporter, pass *analysis.Pass, loop *ast.RangeStmt) {
+	if !bindsElement(loop.Key) && !bindsElement(loop.Value) {
+		// for range m { return true } is an emptiness check
+		return
+	}
+	if len(loop.Body.List) == 0 {
+		return
+	}
+
+	switch last := loop.Body.List[len(loop.Body.List)-1].(type) {
+	case *ast.BranchStmt:
+		if last.Tok != token.BREAK || last.Label != nil {
+			return
+		}
+	case *ast.ReturnStmt:
+	default:
+		return
+	}
+
+	if hasContinue(loop.Body) {
+		return
+	}
+
+	reporter.Reportf(loop.Pos(),
+		"loop over map stops after the first iteration; the element it picks is arbitrary, choose one deterministically (e.g. the smallest key)")
+}
+
+// bindsElement checks if a range key or value is bound to a named variable
+func bindsElement(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name != "_"
+}
+
+// hasContinue checks for a continue statement that skips to the next iteration
+func hasContinue(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit, *ast.ForStmt, *ast.RangeStmt:
+			// continue in a nested loop targets that loop
+			return false
+		case *ast.BranchStmt:
+			if node.Tok == token.CONTINUE {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// checkLoopWrites reports the first output written directly inside a map
+// range loop
+func checkLoopWrites(reporter *nolint.Reporter, pass *analysis.Pass, loop *ast.RangeStmt) {
+	reported := false
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		if reported {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.RangeStmt:
+			// A nested loop over a map is checked on its own
+			return !isMap(pass, node.X)
+		case *ast.CallExpr:
+			if isWrite(pass, node) {
+				reporter.Reportf(node.Pos(),
+					"output written while ranging over a map comes out in random order; iterate over sorted keys")
+				reported = true
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// isWrite checks for fmt.Fprint* and Write/WriteString/WriteByte/WriteRune
+// method calls on writers
+func isWrite(pass *analysis.Pass, call *ast.CallExpr) bool {
+	pkg, name := calleeFunc(pass, call)
+	if pkg == "fmt" && strings.HasPrefix(name, "Fprint") {
+		return true
+	}
+	switch name {
+	case "(Write)", "(WriteString)", "(WriteByte)", "(WriteRune)":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package mapiteration_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/mapiteration"
+)
+
+func TestMapIterationAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, mapiteration.Analyzer, "a")
+}
+
+func TestMapIterationCheckWrites(t *testing.T) {
+	if err := mapiteration.Analyzer.Flags.Set("check-writes", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = mapiteration.Analyzer.Flags.Set("check-writes", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, mapiteration.Analyzer, "writes")
+}
@@ -0,0 +1,156 @@
+package a
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Bad: map-ranged slice returned unsorted
+func Names(users map[string]int) []string {
+	var names []string
+	for name := range users {
+		names = append(names, name)
+	}
+	return names // want `names is built by ranging over a map at line 15 and returned without sorting`
+}
+
+// Good: sorted before return
+func SortedNames(users map[string]int) []string {
+	var names []string
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Good: sorted with the slices package
+func SortedIDs(users map[string]int) []int {
+	ids := make([]int, 0, len(users))
+	for _, id := range users {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Bad: marshaled unsorted
+func Encode(users map[string]int) ([]byte, error) {
+	var names []string
+	for name := range users {
+		names = append(names, name)
+	}
+	return json.Marshal(names) // want `names is built by ranging over a map at line 44 and marshaled without sorting`
+}
+
+// Bad: joined unsorted
+func Join(tags map[string]bool) string {
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	return strings.Join(keys, ",") // want `keys is built by ranging over a map at line 53 and joined without sorting`
+}
+
+// Bad: printed unsorted
+func Print(tags map[string]bool) {
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	fmt.Println(keys) // want `keys is built by ranging over a map at line 62 and printed without sorting`
+}
+
+// Bad: compared unsorted
+func Same(a map[string]bool, want []string) bool {
+	var got []string
+	for k := range a {
+		got = append(got, k)
+	}
+	return reflect.DeepEqual(got, want) // want `got is built by ranging over a map at line 71 and compared without sorting`
+}
+
+// Good: order-insensitive use
+func Count(tags map[string]bool) int {
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	return len(keys)
+}
+
+// Good: ranging over a slice
+func Copy(in []string) []string {
+	var out []string
+	for _, s := range in {
+		out = append(out, s)
+	}
+	return out
+}
+
+// Bad: picks an arbitrary "first" element
+func First(users map[string]int) string {
+	for name := range users { // want `loop over map stops after the first iteration`
+		return name
+	}
+	return ""
+}
+
+// Bad: picks an arbitrary element with break
+func AnyValue(users map[string]int) int {
+	var v int
+	for _, id := range users { // want `loop over map stops after the first iteration`
+		v = id
+		break
+	}
+	return v
+}
+
+// Good: emptiness check binds nothing
+func Empty(users map[string]int) bool {
+	for range users {
+		return false
+	}
+	return true
+}
+
+// Good: search that returns on a match
+func Find(users map[string]int, id int) string {
+	for name, v := range users {
+		if v == id {
+			return name
+		}
+	}
+	return ""
+}
+
+// Good: loop that may continue
+func FirstAdmin(users map[string]int) string {
+	for name, v := range users {
+		if v != 0 {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// Good: writes are only reported with -check-writes
+func Serialize(b *strings.Builder, tags map[string]string) {
+	for k, v := range tags {
+		fmt.Fprintf(b, "%s=%s\n", k, v)
+	}
+}
+
+// Suppressed with nolint
+func Unordered(users map[string]int) []string {
+	var names []string
+	for name := range users {
+		names = append(names, name)
+	}
+	return names //nolint:mapiteration // callers sort
+}
@@ -0,0 +1,46 @@
+package writes
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Bad: custom serializer writes in map order
+func Serialize(b *strings.Builder, tags map[string]string) {
+	for k, v := range tags {
+		b.WriteString(k) // want `output written while ranging over a map comes out in random order`
+		b.WriteString(v)
+	}
+}
+
+// Bad: Fprintf inside a map range
+func Dump(w io.Writer, tags map[string]string) {
+	for k, v := range tags {
+		fmt.Fprintf(w, "%s=%s\n", k, v) // want `output written while ranging over a map comes out in random order`
+	}
+}
+
+// Bad: buffer writes
+func Encode(tags map[string][]byte) []byte {
+	var buf bytes.Buffer
+	for _, v := range tags {
+		buf.Write(v) // want `output written while ranging over a map comes out in random order`
+	}
+	return buf.Bytes()
+}
+
+// Good: iterates over sorted keys
+func SerializeSorted(b *strings.Builder, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteString(tags[k])
+	}
+}
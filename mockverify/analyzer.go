@@ -10,6 +10,8 @@ package mockverify
 
 import (
 	"go/ast"
+	"go/token"
+	"go/types"
 	"path/filepath"
 	"strings"
 
@@ -34,12 +36,19 @@ preventing issues like:
 - Incomplete mock implementations
 
 The analyzer checks files in mock/ directories or files named *_mock.go
-and ensures they have the verification pattern.`
+and ensures they have the verification pattern.
+
+The verification must name the interface the mock is for. When the mock's
+name contains the name of an interface declared in the package or in an
+imported package, such as UserService for UserServiceMock, a mock verified
+only against unrelated interfaces is reported:
+
+    var _ fmt.Stringer = &UserServiceMock{} // does not check UserService`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "mockverify",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -55,9 +64,9 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
 	// Track mock structs and their interface verifications
-	mockStructs := make(map[string]bool)       // mock name -> true
-	verifiedMocks := make(map[string]bool)     // mock name -> has verification
-	mockPositions := make(map[string]ast.Node) // mock name -> position for reporting
+	mockStructs := make(map[string]bool)             // mock name -> true
+	verifiedMocks := make(map[string][]verification) // mock name -> its verifications
+	mockPositions := make(map[string]ast.Node)       // mock name -> position for reporting
 
 	nodeFilter := []ast.Node{
 		(*ast.File)(nil),
@@ -80,7 +89,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			// Check for var _ Interface = &Mock{} pattern
 			for _, spec := range node.Specs {
 				if vs, ok := spec.(*ast.ValueSpec); ok {
-					checkInterfaceVerification(vs, verifiedMocks)
+					checkInterfaceVerification(pass, vs, verifiedMocks)
 				}
 			}
 
@@ -95,15 +104,18 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		}
 	})
 
-	// Report mocks without verification
+	// Report mocks without verification, or verified against the wrong interface
 	for mockName := range mockStructs {
-		if !verifiedMocks[mockName] {
+		verifications := verifiedMocks[mockName]
+		if len(verifications) == 0 {
 			if pos, ok := mockPositions[mockName]; ok {
 				reporter.Reportf(pos.Pos(),
 					"mock %q should have compile-time interface verification: var _ InterfaceName = &%s{}",
 					mockName, mockName)
 			}
+			continue
 		}
+		checkVerifiedInterface(reporter, pass, mockName, verifications)
 	}
 
 	return nil, nil
@@ -136,45 +148,120 @@ func isMockName(name string) bool {
 	return false
 }
 
+// verification is a var _ Interface = &Mock{} statement
+type verification struct {
+	iface *types.TypeName
+	node  ast.Node
+}
+
 // checkInterfaceVerification checks if a var spec is an interface verification
+// and records the interface against the mock
 // Pattern: var _ Interface = &Mock{}
-func checkInterfaceVerification(vs *ast.ValueSpec, verifiedMocks map[string]bool) {
+func checkInterfaceVerification(pass *analysis.Pass, vs *ast.ValueSpec, verifiedMocks map[string][]verification) {
 	// Must have blank identifier
 	if len(vs.Names) != 1 || vs.Names[0].Name != "_" {
 		return
 	}
 
 	// Must have exactly one value
-	if len(vs.Values) != 1 {
+	if len(vs.Values) != 1 || vs.Type == nil {
+		return
+	}
+
+	// Type should be Interface or pkg.Interface
+	named, ok := types.Unalias(pass.TypesInfo.TypeOf(vs.Type)).(*types.Named)
+	if !ok || !types.IsInterface(named) {
+		return
+	}
+
+	mockName := verifiedMockName(vs.Values[0])
+	if mockName == "" || !isMockName(mockName) {
 		return
 	}
 
-	// Value should be &MockType{} or (*MockType)(nil)
-	switch v := vs.Values[0].(type) {
+	verifiedMocks[mockName] = append(verifiedMocks[mockName], verification{iface: named.Obj(), node: vs})
+}
+
+// verifiedMockName returns the name of the type in &Mock{}, Mock{} or
+// (*Mock)(nil), or "" for other values
+func verifiedMockName(value ast.Expr) string {
+	switch v := value.(type) {
 	case *ast.UnaryExpr:
 		// &Mock{}
-		if v.Op.String() == "&" {
+		if v.Op == token.AND {
 			if composite, ok := v.X.(*ast.CompositeLit); ok {
-				if ident, ok := composite.Type.(*ast.Ident); ok {
-					if isMockName(ident.Name) {
-						verifiedMocks[ident.Name] = true
-					}
-				}
+				return identName(composite.Type)
 			}
 		}
 
+	case *ast.CompositeLit:
+		// Mock{}
+		return identName(v.Type)
+
 	case *ast.CallExpr:
 		// (*Mock)(nil)
 		if paren, ok := v.Fun.(*ast.ParenExpr); ok {
 			if star, ok := paren.X.(*ast.StarExpr); ok {
-				if ident, ok := star.X.(*ast.Ident); ok {
-					if isMockName(ident.Name) {
-						verifiedMocks[ident.Name] = true
-					}
-				}
+				return identName(star.X)
 			}
 		}
 	}
+	return ""
+}
+
+func identName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// checkVerifiedInterface reports verifications of a mock against interfaces
+// unrelated to the one its name says it mocks
+func checkVerifiedInterface(reporter *nolint.Reporter, pass *analysis.Pass, mockName string, verifications []verification) {
+	for _, v := range verifications {
+		if strings.Contains(mockName, v.iface.Name()) {
+			return
+		}
+	}
+
+	targets := mockedInterfaces(pass.Pkg, mockName)
+	if len(targets) == 0 {
+		return // Nothing to say which interface it mocks
+	}
+
+	for _, v := range verifications {
+		reporter.Reportf(v.node.Pos(),
+			"mock %q is verified against %s, which does not look like the interface it mocks; verify it against %s",
+			mockName, qualifiedName(pass.Pkg, v.iface), strings.Join(targets, " or "))
+	}
+}
+
+// mockedInterfaces returns the interfaces declared in pkg or its imports
+// whose names appear in mockName
+func mockedInterfaces(pkg *types.Package, mockName string) []string {
+	var targets []string
+	for _, p := range append([]*types.Package{pkg}, pkg.Imports()...) {
+		scope := p.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || (p != pkg && !tn.Exported()) || !strings.Contains(mockName, name) {
+				continue
+			}
+			if iface, ok := tn.Type().Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+				targets = append(targets, qualifiedName(pkg, tn))
+			}
+		}
+	}
+	return targets
+}
+
+// qualifiedName returns the name of tn as written in pkg, like hal.ComputeBladeHal
+func qualifiedName(pkg *types.Package, tn *types.TypeName) string {
+	if tn.Pkg() == nil || tn.Pkg() == pkg {
+		return tn.Name()
+	}
+	return tn.Pkg().Name() + "." + tn.Name()
 }
 
 // MockInfo contains information about mocks in a package
@@ -190,7 +277,7 @@ func AnalyzeMocks(pass *analysis.Pass) *MockInfo {
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
 	mockStructs := make(map[string]bool)
-	verifiedMocks := make(map[string]bool)
+	verifiedMocks := make(map[string][]verification)
 
 	nodeFilter := []ast.Node{
 		(*ast.GenDecl)(nil),
@@ -202,7 +289,7 @@ func AnalyzeMocks(pass *analysis.Pass) *MockInfo {
 		case *ast.GenDecl:
 			for _, spec := range node.Specs {
 				if vs, ok := spec.(*ast.ValueSpec); ok {
-					checkInterfaceVerification(vs, verifiedMocks)
+					checkInterfaceVerification(pass, vs, verifiedMocks)
 				}
 			}
 
@@ -217,7 +304,7 @@ func AnalyzeMocks(pass *analysis.Pass) *MockInfo {
 	})
 
 	for mock := range mockStructs {
-		if verifiedMocks[mock] {
+		if len(verifiedMocks[mock]) > 0 {
 			info.VerifiedMocks = append(info.VerifiedMocks, mock)
 		} else {
 			info.UnverifiedMocks = append(info.UnverifiedMocks, mock)
@@ -0,0 +1,19 @@
+package mockverify_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/mockverify"
+)
+
+func TestMockVerifyNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, mockverify.Analyzer, "nolint")
+}
+
+func TestMockVerifyAssertions(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, mockverify.Analyzer, "assertions")
+}
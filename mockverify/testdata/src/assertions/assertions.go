@@ -0,0 +1,70 @@
+package assertions
+
+import (
+	"fmt"
+
+	"example.com/hal"
+)
+
+type UserService interface {
+	GetUser(id string) (string, error)
+}
+
+type OrderService interface {
+	PlaceOrder(id string) error
+}
+
+type Store interface {
+	Get(key string) string
+}
+
+// Verified against the interface it mocks
+type UserServiceMock struct{}
+
+var _ UserService = &UserServiceMock{}
+
+func (m *UserServiceMock) GetUser(string) (string, error) { return "", nil }
+
+// Verified only against an unrelated interface
+type OrderServiceMock struct{}
+
+var _ fmt.Stringer = &OrderServiceMock{} // want `mock "OrderServiceMock" is verified against fmt.Stringer, which does not look like the interface it mocks; verify it against OrderService`
+
+func (m *OrderServiceMock) PlaceOrder(string) error { return nil }
+
+func (m *OrderServiceMock) String() string { return "" }
+
+// Verified against a package-qualified interface, by value
+type ComputeBladeHalMock struct{}
+
+var _ hal.ComputeBladeHal = ComputeBladeHalMock{}
+
+func (m ComputeBladeHalMock) SetFanSpeed(uint8) error { return nil }
+
+// Verified against an imported interface under the wrong name
+type ComputeBladeHalFake struct{}
+
+var _ Store = (*ComputeBladeHalFake)(nil) // want `mock "ComputeBladeHalFake" is verified against Store, which does not look like the interface it mocks; verify it against hal.ComputeBladeHal`
+
+func (f *ComputeBladeHalFake) Get(string) string { return "" }
+
+func (f *ComputeBladeHalFake) SetFanSpeed(uint8) error { return nil }
+
+// Also verified against an extra interface, which is fine
+type StoreStub struct{}
+
+var (
+	_ Store        = (*StoreStub)(nil)
+	_ fmt.Stringer = (*StoreStub)(nil)
+)
+
+func (s *StoreStub) Get(string) string { return "" }
+
+func (s *StoreStub) String() string { return "" }
+
+// The name does not say which interface it mocks
+type Mock struct{}
+
+var _ hal.ComputeBladeHal = &Mock{}
+
+func (m *Mock) SetFanSpeed(uint8) error { return nil }
@@ -0,0 +1,5 @@
+package hal
+
+type ComputeBladeHal interface {
+	SetFanSpeed(percent uint8) error
+}
@@ -0,0 +1,17 @@
+package nolint
+
+type Store interface {
+	Get(key string) string
+}
+
+// Reported: mock without interface verification
+type StoreMock struct{} // want `mock "StoreMock" should have compile-time interface verification`
+
+func (m *StoreMock) Get(string) string { return "" }
+
+// Suppressed on the line above
+//
+//nolint:mockverify // verified in the generated assertions file
+type StoreFake struct{}
+
+func (f *StoreFake) Get(string) string { return "" }
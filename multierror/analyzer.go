@@ -0,0 +1,282 @@
+// Package multierror provides an analyzer that detects loops where an error
+// variable is overwritten on every iteration and only the last error survives.
+//
+// Batch code often looks like this:
+//
+//	var err error
+//	for _, item := range items {
+//	    err = process(item)
+//	}
+//	return err
+//
+// Every failure but the last one is silently dropped, and a successful last
+// item hides all of them.
+package multierror
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/goversion"
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect loops that keep only the last error instead of aggregating
+
+This analyzer detects:
+1. Error variables declared before a loop, assigned with = inside it, never
+   read in the loop, and returned after it: every error but the last is lost
+2. errors.Join called with a single error inside a loop, which wraps one
+   error instead of accumulating them
+
+Bad:
+    var err error
+    for _, item := range items {
+        err = process(item)
+    }
+    return err
+
+Good:
+    var errs error
+    for _, item := range items {
+        errs = errors.Join(errs, process(item))
+    }
+    return errs
+
+Also good, when the first error should stop the loop:
+    for _, item := range items {
+        if err := process(item); err != nil {
+            return err
+        }
+    }
+
+In modules declaring a Go version before 1.20, which lacks errors.Join, the
+advice is to collect the errors in a slice instead, and single-error
+errors.Join calls are not reported.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "multierror",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		loop, fnBody := enclosingLoop(stack)
+		if loop == nil {
+			return true
+		}
+
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.ASSIGN {
+				checkOverwrite(reporter, pass, node, loop, fnBody)
+			}
+		case *ast.CallExpr:
+			checkSingleJoin(reporter, pass, node)
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// enclosingLoop returns the innermost loop around the top of stack within
+// the same function, and the body of that function
+func enclosingLoop(stack []ast.Node) (ast.Stmt, *ast.BlockStmt) {
+	var loop ast.Stmt
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.ForStmt:
+			if loop == nil {
+				loop = node
+			}
+		case *ast.RangeStmt:
+			if loop == nil {
+				loop = node
+			}
+		case *ast.FuncLit:
+			return loop, node.Body
+		case *ast.FuncDecl:
+			return loop, node.Body
+		}
+	}
+	return nil, nil
+}
+
+// checkOverwrite reports error variables that are overwritten in loop
+// without being read in it and returned after it
+func checkOverwrite(reporter *nolint.Reporter, pass *analysis.Pass, assign *ast.AssignStmt, loop ast.Stmt, fnBody *ast.BlockStmt) {
+	for _, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+		v, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok || !types.Identical(v.Type(), errorType) {
+			continue
+		}
+
+		// Variables declared inside the loop start fresh every iteration
+		if v.Pos() >= loop.Pos() && v.Pos() < loop.End() {
+			continue
+		}
+
+		if readsIn(pass, loopBody(loop), v) || !returnedAfter(pass, fnBody, loop, v) {
+			continue
+		}
+
+		advice := fmt.Sprintf("accumulate with errors.Join(%s, ...)", ident.Name)
+		if goversion.Before(pass, assign.Pos(), goversion.ErrorsJoin) {
+			advice = "collect the errors in a slice"
+		}
+		reporter.Reportf(assign.Pos(),
+			"%s is overwritten on every iteration, so only the last error is returned; "+
+				"%s or handle the error inside the loop",
+			ident.Name, advice)
+	}
+}
+
+// loopBody returns the body of a for or range loop
+func loopBody(loop ast.Stmt) *ast.BlockStmt {
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		return l.Body
+	case *ast.RangeStmt:
+		return l.Body
+	}
+	return nil
+}
+
+// readsIn checks if body reads v anywhere other than as the target of a
+// plain assignment. Conditions, calls, appends, and joins all count.
+func readsIn(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var) bool {
+	targets := make(map[*ast.Ident]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && assign.Tok == token.ASSIGN {
+			for _, lhs := range assign.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					targets[ident] = true
+				}
+			}
+		}
+		return true
+	})
+
+	reads := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && !targets[ident] && pass.TypesInfo.Uses[ident] == v {
+			reads = true
+		}
+		return !reads
+	})
+	return reads
+}
+
+// returnedAfter checks if a return statement after loop returns v, directly
+// or wrapped, or returns it as a named result with a bare return
+func returnedAfter(pass *analysis.Pass, fnBody *ast.BlockStmt, loop ast.Stmt, v *types.Var) bool {
+	named := isNamedResult(pass, fnBody, v)
+	returned := false
+	ast.Inspect(fnBody, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if node.Pos() < loop.End() {
+				return true
+			}
+			if len(node.Results) == 0 && named {
+				returned = true
+			}
+			for _, result := range node.Results {
+				ast.Inspect(result, func(n ast.Node) bool {
+					if ident, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[ident] == v {
+						returned = true
+					}
+					return !returned
+				})
+			}
+		}
+		return !returned
+	})
+	return returned
+}
+
+// isNamedResult checks if v is a named result of the function with body
+func isNamedResult(pass *analysis.Pass, fnBody *ast.BlockStmt, v *types.Var) bool {
+	for _, file := range pass.Files {
+		if file.Pos() > fnBody.Pos() || file.End() < fnBody.End() {
+			continue
+		}
+		var ft *ast.FuncType
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				if node.Body == fnBody {
+					ft = node.Type
+				}
+			case *ast.FuncLit:
+				if node.Body == fnBody {
+					ft = node.Type
+				}
+			}
+			return ft == nil
+		})
+		if ft == nil || ft.Results == nil {
+			return false
+		}
+		for _, field := range ft.Results.List {
+			for _, name := range field.Names {
+				if pass.TypesInfo.Defs[name] == v {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checkSingleJoin reports errors.Join with one non-variadic argument
+// inside a loop. Modules declaring a version without errors.Join are
+// skipped, since the advice is to call it.
+func checkSingleJoin(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) != 1 || call.Ellipsis.IsValid() {
+		return
+	}
+	if goversion.Before(pass, call.Pos(), goversion.ErrorsJoin) {
+		return
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Join" {
+		return
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "errors" {
+		return
+	}
+
+	reporter.Reportf(call.Pos(),
+		"errors.Join with a single error does not accumulate; pass the errors collected so far, like errs = errors.Join(errs, err)")
+}
@@ -0,0 +1,20 @@
+package multierror_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/multierror"
+)
+
+func TestMultiErrorAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, multierror.Analyzer, "a")
+}
+
+func TestMultiErrorGoVersion(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, filepath.Join(testdata, "legacy"), multierror.Analyzer, "example.com/legacy")
+}
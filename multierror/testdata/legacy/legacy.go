@@ -0,0 +1,22 @@
+package legacy
+
+import "errors"
+
+func process(item string) error { return nil }
+
+// Go 1.19 has no errors.Join, so the advice does not mention it
+func lastErrorWins(items []string) error {
+	var err error
+	for _, item := range items {
+		err = process(item) // want `err is overwritten on every iteration, so only the last error is returned; collect the errors in a slice or handle the error inside the loop`
+	}
+	return err
+}
+
+func singleJoin(items []string) error {
+	var errs []error
+	for _, item := range items {
+		errs = append(errs, errors.Join(process(item)))
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build go1.20
+
+package legacy
+
+func lastErrorModern(items []string) error {
+	var err error
+	for _, item := range items {
+		err = process(item) // want `accumulate with errors.Join\(err, \.\.\.\)`
+	}
+	return err
+}
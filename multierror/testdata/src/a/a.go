@@ -0,0 +1,141 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+)
+
+func process(item string) error { return nil }
+
+func count(item string) (int, error) { return 0, nil }
+
+// Last error wins
+
+func lastErrorWins(items []string) error {
+	var err error
+	for _, item := range items {
+		err = process(item) // want `err is overwritten on every iteration, so only the last error is returned`
+	}
+	return err
+}
+
+func lastErrorWrapped(items []string) error {
+	var err error
+	total := 0
+	for _, item := range items {
+		var n int
+		n, err = count(item) // want `err is overwritten on every iteration`
+		total += n
+	}
+	if err != nil {
+		return fmt.Errorf("counting items: %w", err)
+	}
+	return nil
+}
+
+func lastErrorNamed(items []string) (err error) {
+	for i := 0; i < len(items); i++ {
+		err = process(items[i]) // want `err is overwritten on every iteration`
+	}
+	return
+}
+
+func lastErrorConditional(items []string) error {
+	var err error
+	for _, item := range items {
+		if item != "" {
+			err = process(item) // want `err is overwritten on every iteration`
+		}
+	}
+	return err
+}
+
+func singleJoin(items []string) error {
+	var errs []error
+	for _, item := range items {
+		errs = append(errs, errors.Join(process(item))) // want `errors.Join with a single error does not accumulate`
+	}
+	return errors.Join(errs...)
+}
+
+// Accumulation
+
+func joined(items []string) error {
+	var errs error
+	for _, item := range items {
+		errs = errors.Join(errs, process(item))
+	}
+	return errs
+}
+
+func appended(items []string) error {
+	var errs []error
+	var err error
+	for _, item := range items {
+		err = process(item)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func wrappedJoin(items []string) error {
+	var err error
+	for _, item := range items {
+		if perr := process(item); perr != nil {
+			err = errors.Join(err, fmt.Errorf("item %s: %w", item, perr))
+		}
+	}
+	return err
+}
+
+// Early return
+
+func earlyReturn(items []string) error {
+	var err error
+	for _, item := range items {
+		err = process(item)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func shortDecl(items []string) error {
+	for _, item := range items {
+		if err := process(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Not returned after the loop
+
+func logged(items []string) {
+	var err error
+	for _, item := range items {
+		err = process(item)
+	}
+	_ = err
+}
+
+func declaredInLoop(items []string) {
+	for _, item := range items {
+		var err error
+		err = process(item)
+		_ = err
+	}
+}
+
+func joinOutsideLoop(err error) error {
+	return errors.Join(err)
+}
+
+func joinSpread(groups [][]error) error {
+	var errs error
+	for _, group := range groups {
+		errs = errors.Join(errs, errors.Join(group...))
+	}
+	return errs
+}
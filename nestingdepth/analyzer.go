@@ -5,7 +5,9 @@
 package nestingdepth
 
 import (
+	"flag"
 	"go/ast"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -53,21 +55,45 @@ Bad pattern (deep nesting):
         } else {
             return Item{}, ErrNotFound
         }
-    }`
+    }
+
+Test files are skipped, since table-driven tests with subtests are nested
+by design. Generated files, like event dispatchers, are skipped as well.
+
+Flags:
+    -max-depth          maximum allowed nesting depth (default 3)
+    -max-if-else-chain  maximum allowed if-else chain length (default 2)
+    -include-tests      also check _test.go files`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "nestingdepth",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
-// MaxNestingDepth is the maximum allowed nesting depth
+// MaxNestingDepth is the default maximum allowed nesting depth
 const MaxNestingDepth = 3
 
-// MaxIfElseChain is the maximum allowed if-else chain length
+// MaxIfElseChain is the default maximum allowed if-else chain length
 const MaxIfElseChain = 2
 
+// Configured limits, defaulting to the constants above
+var (
+	maxNestingDepth int
+	maxIfElse       int
+	includeTests    bool
+)
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("nestingdepth", flag.ExitOnError)
+	fs.IntVar(&maxNestingDepth, "max-depth", MaxNestingDepth, "maximum allowed nesting depth")
+	fs.IntVar(&maxIfElse, "max-if-else-chain", MaxIfElseChain, "maximum allowed if-else chain length")
+	fs.BoolVar(&includeTests, "include-tests", false, "also check _test.go files")
+	return *fs
+}
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
@@ -82,6 +108,11 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
+		filename := pass.Fset.Position(fn.Pos()).Filename
+		if !includeTests && strings.HasSuffix(filename, "_test.go") {
+			return
+		}
+
 		checkFunction(reporter, fn)
 	})
 
@@ -91,10 +122,10 @@ func run(pass *analysis.Pass) (interface{}, error) {
 func checkFunction(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	// Check overall nesting depth
 	maxDepth := calculateMaxDepth(fn.Body, 0)
-	if maxDepth > MaxNestingDepth {
+	if maxDepth > maxNestingDepth {
 		reporter.Reportf(fn.Pos(),
 			"function %q has nesting depth of %d (max %d); use early returns to flatten the code",
-			fn.Name.Name, maxDepth, MaxNestingDepth)
+			fn.Name.Name, maxDepth, maxNestingDepth)
 	}
 
 	// Check for if-else chains that should be early returns
@@ -225,7 +256,7 @@ func checkIfElseChains(reporter *nolint.Reporter, body *ast.BlockStmt) {
 			}
 		}
 
-		if chainLength > MaxIfElseChain {
+		if chainLength > maxIfElse {
 			// Check if this could be converted to early returns
 			if couldUseEarlyReturn(ifStmt) {
 				reporter.Reportf(ifStmt.Pos(),
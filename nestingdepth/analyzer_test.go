@@ -0,0 +1,44 @@
+package nestingdepth_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/nestingdepth"
+)
+
+func TestNestingDepthAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nestingdepth.Analyzer, "a")
+}
+
+func TestNestingDepthMaxDepth(t *testing.T) {
+	if err := nestingdepth.Analyzer.Flags.Set("max-depth", "4"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = nestingdepth.Analyzer.Flags.Set("max-depth", "3") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nestingdepth.Analyzer, "deep")
+}
+
+func TestNestingDepthNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nestingdepth.Analyzer, "nolint")
+}
+
+func TestNestingDepthSkipsTestsAndGenerated(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nestingdepth.Analyzer, "tables")
+}
+
+func TestNestingDepthIncludeTests(t *testing.T) {
+	if err := nestingdepth.Analyzer.Flags.Set("include-tests", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = nestingdepth.Analyzer.Flags.Set("include-tests", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nestingdepth.Analyzer, "includetests")
+}
@@ -0,0 +1,29 @@
+package a
+
+// Bad: four levels deep with the default limit of 3
+func Grid(rows, cols, layers, steps int) int { // want `function "Grid" has nesting depth of 4 \(max 3\)`
+	total := 0
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			for l := 0; l < layers; l++ {
+				for s := 0; s < steps; s++ {
+					total += r * c * l * s
+				}
+			}
+		}
+	}
+	return total
+}
+
+// Good: three levels
+func Plane(rows, cols, layers int) int {
+	total := 0
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			for l := 0; l < layers; l++ {
+				total += r * c * l
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,33 @@
+package deep
+
+// Good: four levels is within max-depth=4
+func Grid(rows, cols, layers, steps int) int {
+	total := 0
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			for l := 0; l < layers; l++ {
+				for s := 0; s < steps; s++ {
+					total += r * c * l * s
+				}
+			}
+		}
+	}
+	return total
+}
+
+// Bad: five levels
+func Volume(rows, cols, layers, steps, frames int) int { // want `function "Volume" has nesting depth of 5 \(max 4\)`
+	total := 0
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			for l := 0; l < layers; l++ {
+				for s := 0; s < steps; s++ {
+					for f := 0; f < frames; f++ {
+						total += r * c * l * s * f
+					}
+				}
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,16 @@
+package includetests
+
+import "testing"
+
+// Reported: test files are checked with -include-tests
+func TestParse(t *testing.T) { // want `function "TestParse" has nesting depth of 4 \(max 3\)`
+	for line := range map[string]bool{"a": true} {
+		if line != "" {
+			for i := 0; i < 2; i++ {
+				if i > 0 {
+					t.Log(Parse(line))
+				}
+			}
+		}
+	}
+}
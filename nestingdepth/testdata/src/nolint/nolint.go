@@ -0,0 +1,31 @@
+package nolint
+
+// Reported: four levels deep
+func Reported(n int) int { // want `function "Reported" has nesting depth of 4 \(max 3\)`
+	total := 0
+	for a := 0; a < n; a++ {
+		for b := 0; b < n; b++ {
+			for c := 0; c < n; c++ {
+				for d := 0; d < n; d++ {
+					total += a * b * c * d
+				}
+			}
+		}
+	}
+	return total
+}
+
+// Suppressed inline
+func Suppressed(n int) int { //nolint:nestingdepth // tensor walk reads best nested
+	total := 0
+	for a := 0; a < n; a++ {
+		for b := 0; b < n; b++ {
+			for c := 0; c < n; c++ {
+				for d := 0; d < n; d++ {
+					total += a * b * c * d
+				}
+			}
+		}
+	}
+	return total
+}
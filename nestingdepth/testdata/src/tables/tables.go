@@ -0,0 +1,6 @@
+package tables
+
+// Parse returns the fields of line
+func Parse(line string) []string {
+	return []string{line}
+}
@@ -0,0 +1,34 @@
+package tables
+
+import "testing"
+
+// Not reported: test files are skipped
+func TestParse(t *testing.T) {
+	tests := map[string][]string{
+		"a": {"a"},
+	}
+	for line, want := range tests {
+		t.Run(line, func(t *testing.T) {
+			got := Parse(line)
+			for i := range want {
+				if i < len(got) {
+					if got[i] != want[i] {
+						t.Errorf("field %d = %q, want %q", i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+	for line := range tests {
+		if line != "" {
+			for i := 0; i < 2; i++ {
+				if i > 0 {
+					switch line {
+					case "a":
+						t.Log(line)
+					}
+				}
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package nilcheck
 
 import (
+	"flag"
 	"go/ast"
 	"go/token"
 	"go/types"
@@ -34,15 +35,35 @@ Every pointer parameter should be validated at the start of a function:
         fmt.Println(user.Name)
     }
 
-This prevents nil pointer panics and provides better error messages.`
+This prevents nil pointer panics and provides better error messages.
+
+With -check-returned-pointers, local variables assigned from a call to a
+function of the same package that returns a single pointer and contains
+return nil are checked the same way:
+
+    user := s.cache.Lookup(id)  // Lookup returns nil when id is unknown
+    fmt.Println(user.Name)      // reported
+
+Flags:
+    -check-returned-pointers  also check pointers returned by functions that can return nil`
+
+var checkReturnedPointers bool
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "nilcheck",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("nilcheck", flag.ExitOnError)
+	fs.BoolVar(&checkReturnedPointers, "check-returned-pointers", false,
+		"also check pointers returned by functions that can return nil")
+	return *fs
+}
+
 // Types that are guaranteed non-nil by their callers (framework types)
 var trustedPointerTypes = map[string]bool{
 	// Testing
@@ -133,10 +154,9 @@ var trustedParamNames = map[string]bool{
 	"config": true, // config
 }
 
-// File patterns to skip (generated code, etc.)
+// File patterns to skip (mocks and generator output without the standard
+// header; the reporter drops other generated files)
 var skipFilePatterns = []string{
-	"zz_generated",
-	".pb.go",
 	"_gen.go",
 	"mock_",
 	"mocks/",
@@ -150,13 +170,20 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.FuncDecl)(nil),
 	}
 
+	validators := collectValidators(pass, inspect)
+
+	var nilReturners map[*types.Func]bool
+	if checkReturnedPointers {
+		nilReturners = collectNilReturners(pass, inspect)
+	}
+
 	inspect.Preorder(nodeFilter, func(n ast.Node) {
 		fn, ok := n.(*ast.FuncDecl)
 		if !ok || fn.Body == nil {
 			return
 		}
 
-		// Skip generated files
+		// Skip mocks and generator output
 		filename := pass.Fset.Position(fn.Pos()).Filename
 		for _, pattern := range skipFilePatterns {
 			if strings.Contains(filename, pattern) {
@@ -164,16 +191,17 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			}
 		}
 
-		checkFunction(reporter, pass, fn)
+		checkFunction(reporter, pass, fn, validators, nilReturners)
 	})
 
 	return nil, nil
 }
 
-func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
-	// Collect pointer parameters
+func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, validators map[*types.Func]map[int]bool, nilReturners map[*types.Func]bool) {
+	// Collect pointer parameters and variables holding pointers that may be nil
 	ptrParams := collectPointerParams(pass, fn)
-	if len(ptrParams) == 0 {
+	returnedPtrs := collectReturnedPointers(pass, fn, nilReturners)
+	if len(ptrParams) == 0 && len(returnedPtrs) == 0 {
 		return
 	}
 
@@ -182,10 +210,20 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 
 	// First pass: find nil checks
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
-		if ifStmt, ok := n.(*ast.IfStmt); ok {
-			// Check for: if x == nil or if x != nil
-			checkedParam := extractNilCheck(ifStmt.Cond)
-			if checkedParam != "" {
+		switch node := n.(type) {
+		case *ast.IfStmt:
+			// Check for: if x == nil, if x != nil && x.Enabled, ...
+			for _, checkedParam := range extractNilChecks(node.Cond) {
+				checkedParams[checkedParam] = true
+			}
+		case *ast.SwitchStmt:
+			// Check for: switch { case x == nil: } or switch x { case nil: }
+			for _, checkedParam := range switchNilChecks(node) {
+				checkedParams[checkedParam] = true
+			}
+		case *ast.BlockStmt:
+			// Check for: if err := validateUser(user); err != nil { return err }
+			for _, checkedParam := range validatedParams(pass, node, validators) {
 				checkedParams[checkedParam] = true
 			}
 		}
@@ -196,8 +234,7 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		// Skip the nil check conditions themselves
 		if ifStmt, ok := n.(*ast.IfStmt); ok {
-			checkedParam := extractNilCheck(ifStmt.Cond)
-			if checkedParam != "" {
+			for _, checkedParam := range extractNilChecks(ifStmt.Cond) {
 				// Skip checking inside the nil-check's then block if it's an early return
 				if isEarlyReturnBlock(ifStmt.Body) {
 					// After this if block, the param is effectively checked
@@ -211,6 +248,12 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 		case *ast.SelectorExpr:
 			// x.Field - check if x is an unchecked pointer param
 			if ident, ok := node.X.(*ast.Ident); ok {
+				if callee, ok := returnedPtrs[ident.Name]; ok && !checkedParams[ident.Name] {
+					reporter.Reportf(node.Pos(),
+						"%q is used without nil check, but %s can return nil; add 'if %s == nil { ... }' after the call",
+						ident.Name, callee, ident.Name)
+					checkedParams[ident.Name] = true
+				}
 				if ptrParams[ident.Name] && !checkedParams[ident.Name] {
 					reporter.Reportf(node.Pos(),
 						"pointer parameter %q used without nil check; add 'if %s == nil { return ... }' at function start",
@@ -223,6 +266,12 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 		case *ast.StarExpr:
 			// *x - explicit dereference
 			if ident, ok := node.X.(*ast.Ident); ok {
+				if callee, ok := returnedPtrs[ident.Name]; ok && !checkedParams[ident.Name] {
+					reporter.Reportf(node.Pos(),
+						"%q is dereferenced without nil check, but %s can return nil; add 'if %s == nil { ... }' after the call",
+						ident.Name, callee, ident.Name)
+					checkedParams[ident.Name] = true
+				}
 				if ptrParams[ident.Name] && !checkedParams[ident.Name] {
 					reporter.Reportf(node.Pos(),
 						"pointer parameter %q dereferenced without nil check; add 'if %s == nil { return ... }' at function start",
@@ -247,6 +296,255 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 	})
 }
 
+// collectReturnedPointers returns the local variables of fn assigned from a
+// single call to one of nilReturners, mapped to the name of the callee
+func collectReturnedPointers(pass *analysis.Pass, fn *ast.FuncDecl, nilReturners map[*types.Func]bool) map[string]string {
+	vars := make(map[string]string)
+	if len(nilReturners) == 0 {
+		return vars
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			return true
+		}
+		call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if callee := calledFunc(pass, call); nilReturners[callee] {
+			vars[ident.Name] = callee.Name()
+		}
+		return true
+	})
+
+	return vars
+}
+
+// collectNilReturners finds the functions of the package that return a
+// single pointer and contain return nil on some path, like
+//
+//	func (c *Cache) Lookup(id string) *User {
+//	    user, ok := c.users[id]
+//	    if !ok {
+//	        return nil
+//	    }
+//	    return user
+//	}
+func collectNilReturners(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Func]bool {
+	nilReturners := make(map[*types.Func]bool)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok || fn.Body == nil {
+			return
+		}
+		results := obj.Type().(*types.Signature).Results()
+		if results.Len() != 1 {
+			return
+		}
+		if _, ok := results.At(0).Type().Underlying().(*types.Pointer); !ok {
+			return
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				// Returns in closures don't return from fn
+				return false
+			case *ast.ReturnStmt:
+				if len(node.Results) == 1 && isNilIdent(ast.Unparen(node.Results[0])) {
+					nilReturners[obj] = true
+				}
+			}
+			return !nilReturners[obj]
+		})
+	})
+
+	return nilReturners
+}
+
+// collectValidators finds the functions of the package that return an error
+// and return early when a parameter is nil, like
+//
+//	func validateUser(u *User) error {
+//	    if u == nil {
+//	        return errors.New("user is nil")
+//	    }
+//	    ...
+//	}
+//
+// It maps each function to the indices of the parameters it checks.
+func collectValidators(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Func]map[int]bool {
+	validators := make(map[*types.Func]map[int]bool)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok || fn.Body == nil || !returnsError(obj) {
+			return
+		}
+
+		index := make(map[string]int)
+		i := 0
+		for _, field := range fn.Type.Params.List {
+			for _, name := range field.Names {
+				index[name.Name] = i
+				i++
+			}
+			if len(field.Names) == 0 {
+				i++
+			}
+		}
+
+		checked := make(map[int]bool)
+		for _, stmt := range fn.Body.List {
+			ifStmt, ok := stmt.(*ast.IfStmt)
+			if !ok || !isEarlyReturnBlock(ifStmt.Body) {
+				continue
+			}
+			for _, name := range extractNilEqualities(ifStmt.Cond) {
+				if i, ok := index[name]; ok {
+					checked[i] = true
+				}
+			}
+		}
+		if len(checked) > 0 {
+			validators[obj] = checked
+		}
+	})
+
+	return validators
+}
+
+// returnsError checks if the last result of fn is an error
+func returnsError(fn *types.Func) bool {
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() == 0 {
+		return false
+	}
+	return types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// extractNilEqualities returns the variables compared with == nil in a
+// condition, alone or joined with ||, the shape of a guard clause
+func extractNilEqualities(cond ast.Expr) []string {
+	expr, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return nil
+	}
+	switch expr.Op {
+	case token.LOR:
+		return append(extractNilEqualities(expr.X), extractNilEqualities(expr.Y)...)
+	case token.EQL:
+		if varName := extractNilCheck(expr); varName != "" {
+			return []string{varName}
+		}
+	}
+	return nil
+}
+
+// validatedParams returns the arguments passed to validators in block
+// whose error is checked with an early return:
+//
+//	if err := validateUser(user); err != nil { return err }
+//	err := validateUser(user)
+//	if err != nil { return err }
+//	if validateUser(user) != nil { return errInvalid }
+func validatedParams(pass *analysis.Pass, block *ast.BlockStmt, validators map[*types.Func]map[int]bool) []string {
+	var names []string
+	for i, stmt := range block.List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok || !isEarlyReturnBlock(ifStmt.Body) {
+			continue
+		}
+
+		var call *ast.CallExpr
+		switch {
+		case ifStmt.Init != nil:
+			call = errorCall(ifStmt.Init, ifStmt.Cond)
+		case i > 0:
+			call = errorCall(block.List[i-1], ifStmt.Cond)
+		}
+		if call == nil {
+			// if validateUser(user) != nil
+			call = nonNilCall(ifStmt.Cond)
+		}
+		if call == nil {
+			continue
+		}
+
+		checked := validators[calledFunc(pass, call)]
+		for i, arg := range call.Args {
+			if ident, ok := ast.Unparen(arg).(*ast.Ident); ok && checked[i] {
+				names = append(names, ident.Name)
+			}
+		}
+	}
+	return names
+}
+
+// errorCall returns the call in stmt, like err := validate(x), when cond
+// checks the error it assigns with err != nil
+func errorCall(stmt ast.Stmt, cond ast.Expr) *ast.CallExpr {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 {
+		return nil
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	errIdent, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	binExpr, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || binExpr.Op != token.NEQ {
+		return nil
+	}
+	if ident, ok := binExpr.X.(*ast.Ident); ok && ident.Name == errIdent.Name && isNilIdent(binExpr.Y) {
+		return call
+	}
+	return nil
+}
+
+// nonNilCall returns the call in a call() != nil condition
+func nonNilCall(cond ast.Expr) *ast.CallExpr {
+	binExpr, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || binExpr.Op != token.NEQ || !isNilIdent(binExpr.Y) {
+		return nil
+	}
+	call, _ := ast.Unparen(binExpr.X).(*ast.CallExpr)
+	return call
+}
+
+// calledFunc returns the function or method of the package a call invokes
+// directly, or nil
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() != pass.Pkg {
+		return nil
+	}
+	return fn
+}
+
 // isTrustedType checks if a type string matches any trusted type patterns
 func isTrustedType(typeStr string) bool {
 	// Check exact matches
@@ -340,6 +638,52 @@ func collectPointerParams(pass *analysis.Pass, fn *ast.FuncDecl) map[string]bool
 	return params
 }
 
+// extractNilChecks returns the variables compared to nil anywhere in a
+// condition joined with && and ||, like user == nil || user.ID == "".
+// Accesses after a short-circuiting x != nil && are guarded by the
+// comparison, so x counts as checked for them as well.
+func extractNilChecks(cond ast.Expr) []string {
+	switch expr := ast.Unparen(cond).(type) {
+	case *ast.BinaryExpr:
+		switch expr.Op {
+		case token.LAND, token.LOR:
+			return append(extractNilChecks(expr.X), extractNilChecks(expr.Y)...)
+		case token.EQL, token.NEQ:
+			if varName := extractNilCheck(expr); varName != "" {
+				return []string{varName}
+			}
+		}
+	case *ast.UnaryExpr:
+		// !(x == nil)
+		if expr.Op == token.NOT {
+			return extractNilChecks(expr.X)
+		}
+	}
+	return nil
+}
+
+// switchNilChecks returns the variables a switch statement compares to nil,
+// either in a tagless switch's case conditions or as switch x { case nil: }
+func switchNilChecks(stmt *ast.SwitchStmt) []string {
+	var names []string
+	for _, clause := range stmt.Body.List {
+		cc, ok := clause.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+		for _, expr := range cc.List {
+			if stmt.Tag == nil {
+				names = append(names, extractNilChecks(expr)...)
+				continue
+			}
+			if ident, ok := ast.Unparen(stmt.Tag).(*ast.Ident); ok && isNilIdent(expr) {
+				names = append(names, ident.Name)
+			}
+		}
+	}
+	return names
+}
+
 // extractNilCheck checks if a condition is a nil check and returns the variable name
 func extractNilCheck(cond ast.Expr) string {
 	binExpr, ok := cond.(*ast.BinaryExpr)
@@ -0,0 +1,34 @@
+package nilcheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/nilcheck"
+)
+
+func TestNilCheckNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nilcheck.Analyzer, "nolint")
+}
+
+func TestNilCheckGuards(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nilcheck.Analyzer, "guards")
+}
+
+func TestNilCheckValidators(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nilcheck.Analyzer, "validators")
+}
+
+func TestNilCheckReturnedPointers(t *testing.T) {
+	if err := nilcheck.Analyzer.Flags.Set("check-returned-pointers", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = nilcheck.Analyzer.Flags.Set("check-returned-pointers", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nilcheck.Analyzer, "returned")
+}
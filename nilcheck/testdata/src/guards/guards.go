@@ -0,0 +1,86 @@
+package guards
+
+import "errors"
+
+var errInvalid = errors.New("invalid")
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type Config struct {
+	Enabled bool
+	Retries int
+}
+
+func Disjunction(user *User) error {
+	if user == nil || user.ID == "" {
+		return errInvalid
+	}
+	return nil
+}
+
+func DisjunctionReversed(user *User) error {
+	if nil == user || user.ID == "" {
+		return errInvalid
+	}
+	return nil
+}
+
+func Conjunction(settings *Config) bool {
+	if settings != nil && settings.Enabled {
+		return true
+	}
+	return false
+}
+
+func ConjunctionReversed(settings *Config) bool {
+	return settings.Enabled // want `pointer parameter "settings" used without nil check`
+}
+
+func Nested(user *User, settings *Config) error {
+	if (user == nil || user.ID == "") || (settings != nil && (settings.Retries < 0 || !settings.Enabled)) {
+		return errInvalid
+	}
+	return nil
+}
+
+func NestedRightOperand(user *User) error {
+	if len(user.Name) > 10 && (user.ID == "" || nil != user) { // Compared to nil somewhere in the function
+		return errInvalid
+	}
+	return nil
+}
+
+func Negated(user *User) string {
+	if !(user != nil) {
+		return ""
+	}
+	return user.Name
+}
+
+func TaglessSwitch(user *User) error {
+	switch {
+	case user == nil:
+		return errInvalid
+	case user.ID == "":
+		return errInvalid
+	}
+	return nil
+}
+
+func TagSwitch(user *User) string {
+	switch user {
+	case nil:
+		return ""
+	}
+	return user.Name
+}
+
+func Unrelated(user *User, other *User) error {
+	if other == nil || other.ID == "" {
+		return errInvalid
+	}
+	return errors.New(user.Name) // want `pointer parameter "user" used without nil check`
+}
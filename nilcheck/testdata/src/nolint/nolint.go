@@ -0,0 +1,15 @@
+package nolint
+
+type User struct {
+	Name string
+}
+
+// Reported: pointer parameter used without nil check
+func Reported(u *User) string {
+	return u.Name // want `pointer parameter "u" used without nil check`
+}
+
+// Suppressed inline
+func Suppressed(u *User) string {
+	return u.Name //nolint:nilcheck // callers always pass a user
+}
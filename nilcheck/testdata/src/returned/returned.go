@@ -0,0 +1,59 @@
+package returned
+
+import "fmt"
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type Cache struct {
+	users map[string]*User
+}
+
+type Service struct {
+	cache *Cache
+}
+
+// Lookup returns nil for unknown ids
+func (c *Cache) Lookup(id string) *User {
+	user, ok := c.users[id]
+	if !ok {
+		return nil
+	}
+	return user
+}
+
+// NewUser never returns nil
+func NewUser(id string) *User {
+	return &User{ID: id}
+}
+
+func (s *Service) Name(id string) string {
+	user := s.cache.Lookup(id)
+	return user.Name // want `"user" is used without nil check, but Lookup can return nil`
+}
+
+func (s *Service) Copy(id string) User {
+	user := s.cache.Lookup(id)
+	return *user // want `"user" is dereferenced without nil check, but Lookup can return nil`
+}
+
+func (s *Service) Checked(id string) string {
+	user := s.cache.Lookup(id)
+	if user == nil {
+		return ""
+	}
+	return user.Name
+}
+
+func (s *Service) IfInit(id string) {
+	if user := s.cache.Lookup(id); user != nil {
+		fmt.Println(user.Name)
+	}
+}
+
+func Create(id string) string {
+	user := NewUser(id)
+	return user.Name
+}
@@ -0,0 +1,97 @@
+package validators
+
+import "errors"
+
+var errInvalid = errors.New("invalid")
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type Store struct{}
+
+func validateUser(user *User) error {
+	if user == nil {
+		return errors.New("user is nil")
+	}
+	if user.ID == "" {
+		return errInvalid
+	}
+	return nil
+}
+
+func validateName(user *User) error {
+	if user.Name == "" { // want `pointer parameter "user" used without nil check`
+		return errInvalid
+	}
+	return nil
+}
+
+func (s *Store) validate(id string, user *User) error {
+	if user == nil || id == "" {
+		return errInvalid
+	}
+	return nil
+}
+
+func IfInit(user *User) error {
+	if err := validateUser(user); err != nil {
+		return err
+	}
+	return save(user.ID)
+}
+
+func AssignThenCheck(user *User) error {
+	err := validateUser(user)
+	if err != nil {
+		return err
+	}
+	return save(user.ID)
+}
+
+func CallInCondition(user *User) error {
+	if validateUser(user) != nil {
+		return errInvalid
+	}
+	return save(user.ID)
+}
+
+func Method(s *Store, user *User) error {
+	if s == nil {
+		return errInvalid
+	}
+	if err := s.validate("id", user); err != nil {
+		return err
+	}
+	return save(user.ID)
+}
+
+func NoNilCheckInValidator(user *User) error {
+	if err := validateName(user); err != nil {
+		return err
+	}
+	return save(user.ID) // want `pointer parameter "user" used without nil check`
+}
+
+func IgnoredError(user *User) error {
+	validateUser(user)
+	return save(user.ID) // want `pointer parameter "user" used without nil check`
+}
+
+func DiscardedError(user *User) error {
+	_ = validateUser(user)
+	return save(user.ID) // want `pointer parameter "user" used without nil check`
+}
+
+func UncheckedError(user *User) error {
+	err := validateUser(user)
+	return errors.Join(err, save(user.ID)) // want `pointer parameter "user" used without nil check`
+}
+
+func save(id string) error {
+	if id == "" {
+		return errInvalid
+	}
+	return nil
+}
@@ -5,8 +5,15 @@
 package nopanic
 
 import (
+	"flag"
+	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -20,7 +27,16 @@ const Doc = `ensure library code returns errors instead of panicking
 This analyzer detects:
 1. panic() calls in non-main packages
 2. log.Fatal/log.Panic calls in library code
-3. Must* functions that panic on error
+
+panic(err) with an error value should return the error instead. A panic
+with a constant message, like panic("unreachable"), asserts an invariant:
+return an error if callers can trigger it, or document why they cannot.
+
+Panics stay allowed where they are an established pattern:
+- main packages and test files
+- init functions and package-level variable initializers, which check
+  programmer-error invariants at startup
+- Must-style wrappers like MustCompile, whose name starts with Must
 
 Library code should return errors and let the caller decide how to handle them.
 Panics make code difficult to use as a library and can crash the entire program.
@@ -35,74 +51,173 @@ Good pattern:
     }
 
 Bad pattern:
-    func MustParseConfig(data []byte) *Config {
+    func LoadConfig(data []byte) *Config {
         var cfg Config
         if err := json.Unmarshal(data, &cfg); err != nil {
             panic(err)  // Crashes the program!
         }
         return &cfg
-    }`
+    }
+
+Flags:
+    -allow-panic-in  comma-separated regexps; functions whose name, or
+                     Type.Method for methods, fully matches one may panic`
+
+var allowPanicIn string
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "nopanic",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("nopanic", flag.ExitOnError)
+	fs.StringVar(&allowPanicIn, "allow-panic-in", "",
+		"comma-separated regexps; functions whose name, or Type.Method for methods, fully matches one may panic")
+	return *fs
+}
+
 // Functions where panic is acceptable (initialization, tests)
 var allowedPanicFunctions = map[string]bool{
 	"init":     true,
 	"TestMain": true,
 }
 
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	// Skip main packages
 	if pass.Pkg.Name() == "main" {
 		return nil, nil
 	}
 
+	allowed, err := parseAllowed(allowPanicIn)
+	if err != nil {
+		return nil, err
+	}
+
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
-	var currentFunc string
-	var inTestFile bool
-
 	nodeFilter := []ast.Node{
-		(*ast.File)(nil),
-		(*ast.FuncDecl)(nil),
 		(*ast.CallExpr)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
-		switch node := n.(type) {
-		case *ast.File:
-			filename := pass.Fset.Position(node.Pos()).Filename
-			inTestFile = strings.HasSuffix(filename, "_test.go")
-
-		case *ast.FuncDecl:
-			if node.Name != nil {
-				currentFunc = node.Name.Name
-			}
-
-		case *ast.CallExpr:
-			if inTestFile {
-				return
-			}
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
 
-			// Skip allowed functions
-			if allowedPanicFunctions[currentFunc] {
-				return
-			}
+		filename := pass.Fset.Position(n.Pos()).Filename
+		if strings.HasSuffix(filename, "_test.go") {
+			return true
+		}
 
-			checkPanicCall(reporter, node, currentFunc)
+		// Calls outside any function run while initializing package-level
+		// variables, which is init time like init()
+		fn := enclosingFuncDecl(stack)
+		if fn == nil || isExempt(fn, allowed) {
+			return true
 		}
+
+		checkPanicCall(reporter, pass, n.(*ast.CallExpr))
+		return true
 	})
 
 	return nil, nil
 }
 
-func checkPanicCall(reporter *nolint.Reporter, call *ast.CallExpr, _ string) {
+// parseAllowed compiles the -allow-panic-in regexps, anchored to match
+// whole names
+func parseAllowed(value string) ([]*regexp.Regexp, error) {
+	var allowed []*regexp.Regexp
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("nopanic: invalid -allow-panic-in pattern %q: %w", pattern, err)
+		}
+		allowed = append(allowed, re)
+	}
+	return allowed, nil
+}
+
+// enclosingFuncDecl returns the function declaration around the top of
+// stack, or nil at package level
+func enclosingFuncDecl(stack []ast.Node) *ast.FuncDecl {
+	for i := len(stack) - 2; i >= 0; i-- {
+		if fn, ok := stack[i].(*ast.FuncDecl); ok {
+			return fn
+		}
+	}
+	return nil
+}
+
+// isExempt checks if fn may panic: init and TestMain, Must-style wrappers,
+// and functions matching -allow-panic-in
+func isExempt(fn *ast.FuncDecl, allowed []*regexp.Regexp) bool {
+	name := fn.Name.Name
+	if allowedPanicFunctions[name] || isMustName(name) {
+		return true
+	}
+
+	names := []string{name}
+	if recv := receiverName(fn); recv != "" {
+		names = append(names, recv+"."+name)
+	}
+	for _, re := range allowed {
+		for _, n := range names {
+			if re.MatchString(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isMustName checks for Must and MustX names, but not words like Mustard
+func isMustName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "Must")
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return unicode.IsUpper(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// receiverName returns the receiver type name of a method, without pointer
+// and type parameters
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func checkPanicCall(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr) {
 	var funcName string
 
 	switch fn := call.Fun.(type) {
@@ -120,8 +235,7 @@ func checkPanicCall(reporter *nolint.Reporter, call *ast.CallExpr, _ string) {
 
 	// Check for direct panic calls
 	if funcName == "panic" {
-		reporter.Reportf(call.Pos(),
-			"panic() in library code; return an error instead to let callers handle failures gracefully")
+		checkPanic(reporter, pass, call)
 		return
 	}
 
@@ -147,7 +261,45 @@ func checkPanicCall(reporter *nolint.Reporter, call *ast.CallExpr, _ string) {
 			"Fatal log in library code terminates the program; return an error instead")
 		return
 	}
+}
 
-	// Note: Must* functions that panic are generally acceptable
-	// as they follow Go conventions (e.g., regexp.MustCompile)
+// checkPanic reports a call to the panic builtin, with advice depending on
+// whether it panics with an error or asserts an invariant
+func checkPanic(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr) {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || len(call.Args) != 1 {
+		return
+	}
+	if _, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); !ok {
+		return
+	}
+	arg := call.Args[0]
+
+	if t := pass.TypesInfo.TypeOf(arg); t != nil && types.Implements(t, errorType) {
+		reporter.Reportf(call.Pos(),
+			"panic(%s) in library code; return the error instead to let callers handle failures gracefully",
+			shortExpr(arg))
+		return
+	}
+
+	if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		reporter.Reportf(call.Pos(),
+			"panic(%q) asserts an invariant; if callers can trigger it, return an error instead, "+
+				"otherwise document why it cannot happen with //nolint:nopanic // <reason>",
+			constant.StringVal(tv.Value))
+		return
+	}
+
+	reporter.Reportf(call.Pos(),
+		"panic() in library code; return an error instead to let callers handle failures gracefully")
+}
+
+// shortExpr renders names like err and pkg.ErrClosed, and elides anything
+// longer
+func shortExpr(expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return types.ExprString(expr)
+	}
+	return "..."
 }
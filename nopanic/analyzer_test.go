@@ -0,0 +1,26 @@
+package nopanic_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/nopanic"
+)
+
+func TestNoPanic(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nopanic.Analyzer, "lib", "app")
+}
+
+func TestNoPanicAllowPanicIn(t *testing.T) {
+	if err := nopanic.Analyzer.Flags.Set("allow-panic-in", `checkInvariant, Pool\.assert.*`); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = nopanic.Analyzer.Flags.Set("allow-panic-in", "")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, nopanic.Analyzer, "allow")
+}
@@ -0,0 +1,35 @@
+package allow
+
+import "errors"
+
+var errClosed = errors.New("closed")
+
+type Pool struct{ closed bool }
+
+// Matched by the Type.Method pattern
+func (p *Pool) assertOpen() {
+	if p.closed {
+		panic(errClosed)
+	}
+}
+
+// Matched by the name pattern
+func checkInvariant(ok bool) {
+	if !ok {
+		panic("invariant violated")
+	}
+}
+
+// Patterns match whole names, so checkInvariants is not matched
+func checkInvariants(ok bool) {
+	if !ok {
+		panic("invariants violated") // want `panic\("invariants violated"\) asserts an invariant`
+	}
+}
+
+func (p *Pool) Get() {
+	p.assertOpen()
+	if p.closed {
+		panic(errClosed) // want `panic\(errClosed\) in library code; return the error instead`
+	}
+}
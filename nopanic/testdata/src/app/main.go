@@ -0,0 +1,12 @@
+package main
+
+import "errors"
+
+func run() error { return errors.New("failed") }
+
+// Panics in main packages end the program the user started
+func main() {
+	if err := run(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+)
+
+type Config struct{ Name string }
+
+func parse(data []byte) (*Config, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty config")
+	}
+	return &Config{Name: string(data)}, nil
+}
+
+// Still flagged: an exported library function panicking with an error
+func LoadConfig(data []byte) *Config {
+	cfg, err := parse(data)
+	if err != nil {
+		panic(err) // want `panic\(err\) in library code; return the error instead`
+	}
+	return cfg
+}
+
+type Kind int
+
+func (k Kind) String() string {
+	switch k {
+	case 0:
+		return "zero"
+	}
+	panic("unreachable") // want `panic\("unreachable"\) asserts an invariant; if callers can trigger it, return an error instead, otherwise document why`
+}
+
+func Validate(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("negative: %d", n)) // want `panic\(\) in library code; return an error instead`
+	}
+}
+
+func Fail(err error) {
+	log.Fatal(err) // want `log.Fatal\(\) in library code terminates the program`
+}
+
+// Must-style wrappers may panic
+func MustLoadConfig(data []byte) *Config {
+	cfg, err := parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (c *Config) MustName() string {
+	if c.Name == "" {
+		panic("config has no name")
+	}
+	return c.Name
+}
+
+// Mustard is not a Must-style wrapper
+func Mustard() {
+	panic("mustard") // want `panic\("mustard"\) asserts an invariant`
+}
+
+// Panics while initializing the package check programmer errors
+var namePattern = regexp.MustCompile(`^[a-z]+$`)
+
+var defaultConfig = func() *Config {
+	cfg, err := parse([]byte("default"))
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}()
+
+var registry = map[string]*Config{}
+
+func init() {
+	if !namePattern.MatchString(defaultConfig.Name) {
+		panic("default config name is invalid")
+	}
+	registry[defaultConfig.Name] = defaultConfig
+}
@@ -3,6 +3,7 @@
 package optionspattern
 
 import (
+	"flag"
 	"go/ast"
 	"go/types"
 	"strings"
@@ -22,15 +23,34 @@ This analyzer ensures:
 3. Option functions are prefixed with 'With'
 4. Options files follow naming convention (options.go)
 
-The functional options pattern provides a clean, extensible API for configuration.`
+Generic option types like 'type Option[T any] func(*T)' are recognized,
+including in constructor parameters such as 'opts ...Option[Server]'.
+Option types returning their parameter for chaining, 'func(*T) *T', and
+With* methods returning their receiver are accepted unless
+-allow-chaining=false.
+
+The functional options pattern provides a clean, extensible API for configuration.
+
+Flags:
+    -allow-chaining  accept options and builders that return their parameter (default true)`
+
+var allowChaining bool
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "optionspattern",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("optionspattern", flag.ExitOnError)
+	fs.BoolVar(&allowChaining, "allow-chaining", true,
+		"accept option function types that return their parameter and With* methods that return their receiver")
+	return *fs
+}
+
 const (
 	maxConstructorParams = 4 // Constructors with more params should use options
 )
@@ -60,7 +80,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	inspect.Preorder(nodeFilter, func(n ast.Node) {
 		if ts, ok := n.(*ast.TypeSpec); ok {
 			if strings.HasSuffix(ts.Name.Name, "Option") || ts.Name.Name == "Option" {
-				if _, ok := ts.Type.(*ast.FuncType); ok {
+				if _, ok := ts.Type.(*ast.FuncType); ok || isInstantiation(ts.Type) {
 					optionTypes[ts.Name.Name] = true
 				}
 			}
@@ -71,7 +91,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	inspect.Preorder(nodeFilter, func(n ast.Node) {
 		switch node := n.(type) {
 		case *ast.TypeSpec:
-			checkOptionTypeDefinition(reporter, node)
+			checkOptionTypeDefinition(reporter, node, optionTypes)
 
 		case *ast.FuncDecl:
 			checkConstructorPattern(reporter, node, optionTypes)
@@ -83,12 +103,18 @@ func run(pass *analysis.Pass) (interface{}, error) {
 }
 
 // checkOptionTypeDefinition ensures Option types follow the pattern
-func checkOptionTypeDefinition(reporter *nolint.Reporter, ts *ast.TypeSpec) {
+func checkOptionTypeDefinition(reporter *nolint.Reporter, ts *ast.TypeSpec, optionTypes map[string]bool) {
 	// Check if this looks like an Option type
 	if !strings.HasSuffix(ts.Name.Name, "Option") && ts.Name.Name != "Option" {
 		return
 	}
 
+	// An instantiation of a generic Option type, like Option[Server], is
+	// checked where the generic type is defined
+	if isInstantiation(ts.Type) && isOptionType(ts.Type, optionTypes) {
+		return
+	}
+
 	// Should be a function type
 	ft, ok := ts.Type.(*ast.FuncType)
 	if !ok {
@@ -105,20 +131,91 @@ func checkOptionTypeDefinition(reporter *nolint.Reporter, ts *ast.TypeSpec) {
 		return
 	}
 
-	// Parameter should be a pointer type
+	// Parameter should be a pointer type, or a type parameter that may be one
 	param := ft.Params.List[0]
-	if _, ok := param.Type.(*ast.StarExpr); !ok {
+	if _, ok := param.Type.(*ast.StarExpr); !ok && !isTypeParam(ts, param.Type) {
 		reporter.Reportf(param.Pos(),
 			"Option function parameter should be a pointer type (*T)")
 	}
 
-	// Function should return nothing
-	if ft.Results != nil && len(ft.Results.List) > 0 {
+	// Function should return nothing, or its parameter for chaining
+	if ft.Results != nil && len(ft.Results.List) > 0 && !(allowChaining && returnsParam(ft)) {
 		reporter.Reportf(ts.Pos(),
 			"Option function should not return any values")
 	}
 }
 
+// isInstantiation checks if expr instantiates a generic type, like Option[Server]
+func isInstantiation(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+	return false
+}
+
+// isTypeParam checks if expr names a type parameter of ts, as PT in
+// type Option[T any, PT interface{ *T }] func(PT)
+func isTypeParam(ts *ast.TypeSpec, expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ts.TypeParams == nil {
+		return false
+	}
+	for _, field := range ts.TypeParams.List {
+		for _, name := range field.Names {
+			if name.Name == ident.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// returnsParam checks if ft returns exactly the type of its single
+// parameter, as in func(*T) *T
+func returnsParam(ft *ast.FuncType) bool {
+	if ft.Params == nil || len(ft.Params.List) != 1 || len(ft.Results.List) != 1 {
+		return false
+	}
+	result := ft.Results.List[0]
+	if len(result.Names) > 1 {
+		return false
+	}
+	return types.ExprString(result.Type) == types.ExprString(ft.Params.List[0].Type)
+}
+
+// baseTypeName returns the name of the type expr is built from, stripping
+// pointers, variadics and type arguments: Option for ...Option[T]
+func baseTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.Ellipsis:
+			expr = e.Elt
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel.Name
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// isOptionType checks if expr is an Option type, a slice of one, or an
+// instantiation of a generic one like Option[Server]
+func isOptionType(expr ast.Expr, optionTypes map[string]bool) bool {
+	name := baseTypeName(expr)
+	return strings.Contains(name, "Option") || optionTypes[name]
+}
+
 // checkConstructorPattern ensures New* functions use options when they have many params
 func checkConstructorPattern(reporter *nolint.Reporter, fn *ast.FuncDecl, optionTypes map[string]bool) {
 	if fn.Name == nil {
@@ -146,19 +243,9 @@ func checkConstructorPattern(reporter *nolint.Reporter, fn *ast.FuncDecl, option
 	hasOptions := false
 
 	for _, param := range fn.Type.Params.List {
-		paramType := types.ExprString(param.Type)
-
-		// Check if this is a variadic options parameter
-		if ellipsis, ok := param.Type.(*ast.Ellipsis); ok {
-			eltType := types.ExprString(ellipsis.Elt)
-			if strings.Contains(eltType, "Option") || optionTypes[eltType] {
-				hasOptions = true
-				continue
-			}
-		}
-
-		// Check if this is an Option slice
-		if strings.Contains(paramType, "Option") || strings.Contains(paramType, "...Option") {
+		// Check if this is an Option, variadic options or an Option slice,
+		// generic or not
+		if isOptionType(param.Type, optionTypes) {
 			hasOptions = true
 			continue
 		}
@@ -199,10 +286,7 @@ func checkOptionFunctionNaming(reporter *nolint.Reporter, fn *ast.FuncDecl, opti
 
 	// Check functions that return Option types
 	for _, result := range fn.Type.Results.List {
-		resultType := types.ExprString(result.Type)
-
-		isOptionReturn := strings.Contains(resultType, "Option") || optionTypes[resultType]
-		if !isOptionReturn {
+		if !isOptionType(result.Type, optionTypes) {
 			continue
 		}
 
@@ -217,12 +301,12 @@ func checkOptionFunctionNaming(reporter *nolint.Reporter, fn *ast.FuncDecl, opti
 		checkOptionFunctionBody(fn)
 	}
 
-	// Functions starting with "With" that don't return Option are suspicious
-	if strings.HasPrefix(name, "With") {
+	// Functions starting with "With" that don't return Option are suspicious,
+	// unless they are builder methods returning their receiver
+	if strings.HasPrefix(name, "With") && !(allowChaining && returnsReceiver(fn)) {
 		returnsOption := false
 		for _, result := range fn.Type.Results.List {
-			resultType := types.ExprString(result.Type)
-			if strings.Contains(resultType, "Option") || optionTypes[resultType] {
+			if isOptionType(result.Type, optionTypes) {
 				returnsOption = true
 				break
 			}
@@ -236,6 +320,19 @@ func checkOptionFunctionNaming(reporter *nolint.Reporter, fn *ast.FuncDecl, opti
 	}
 }
 
+// returnsReceiver checks if fn is a method returning exactly its receiver
+// type, like func (b *Builder) WithName(string) *Builder
+func returnsReceiver(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 || len(fn.Type.Results.List) != 1 {
+		return false
+	}
+	result := fn.Type.Results.List[0]
+	if len(result.Names) > 1 {
+		return false
+	}
+	return types.ExprString(result.Type) == types.ExprString(fn.Recv.List[0].Type)
+}
+
 // hasValidOptionPrefix checks if a function name starts with any valid option prefix
 func hasValidOptionPrefix(name string) bool {
 	for _, prefix := range validOptionFuncPrefixes {
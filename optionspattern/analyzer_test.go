@@ -0,0 +1,31 @@
+package optionspattern_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/optionspattern"
+)
+
+func TestOptionsPatternNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, optionspattern.Analyzer, "nolint")
+}
+
+func TestOptionsPatternGeneric(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, optionspattern.Analyzer, "generic")
+}
+
+func TestOptionsPatternNoChaining(t *testing.T) {
+	if err := optionspattern.Analyzer.Flags.Set("allow-chaining", "false"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = optionspattern.Analyzer.Flags.Set("allow-chaining", "true")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, optionspattern.Analyzer, "chaining")
+}
@@ -0,0 +1,18 @@
+package chaining
+
+type Config struct {
+	name string
+}
+
+// Reported: chaining is not allowed
+type Option func(*Config) *Config // want `Option function should not return any values`
+
+type Builder struct {
+	cfg Config
+}
+
+// Reported: chaining is not allowed
+func (b *Builder) WithName(name string) *Builder { // want `function "WithName" starts with 'With' but doesn't return an Option type`
+	b.cfg.name = name
+	return b
+}
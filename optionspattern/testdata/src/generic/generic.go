@@ -0,0 +1,73 @@
+package generic
+
+import "time"
+
+// Option configures any config struct T
+type Option[T any] func(*T)
+
+// PtrOption constrains PT to *T, so it can call methods of T
+type PtrOption[T any, PT interface{ *T }] func(PT)
+
+// ServerOption instantiates Option for Server
+type ServerOption Option[Server]
+
+// ChainOption returns its parameter for chaining
+type ChainOption[T any] func(*T) *T
+
+type Server struct {
+	addr    string
+	timeout time.Duration
+}
+
+func WithTimeout[T interface{ setTimeout(time.Duration) }](d time.Duration) Option[T] {
+	return func(t *T) {}
+}
+
+func WithAddr(addr string) Option[Server] {
+	return func(s *Server) { s.addr = addr }
+}
+
+// Reported: an Option-returning function without an option prefix
+func Addr(addr string) Option[Server] { // want `function "Addr" returns Option but doesn't use a standard option prefix`
+	return func(s *Server) { s.addr = addr }
+}
+
+// Not reported: the options are generic
+func NewServer(addr string, port int, user, pass, realm string, opts ...Option[Server]) *Server {
+	return &Server{addr: addr}
+}
+
+type Pair[K comparable, V any] struct{}
+
+// MapOption takes two type arguments
+type MapOption[K comparable, V any] func(*Pair[K, V])
+
+func NewPair[K comparable, V any](a, b, c, d, e string, opts ...MapOption[K, V]) *Pair[K, V] {
+	return &Pair[K, V]{}
+}
+
+// Reported: still too many parameters without options
+func NewClient(host string, port int, user, pass, realm string) *Server { // want `constructor "NewClient" has 5 parameters`
+	return &Server{}
+}
+
+// Reported: the parameter is not a pointer
+type ValueOption[T any] func(T, int) // want `Option function type should take exactly one parameter`
+
+// Reported: returns something other than its parameter
+type ErrOption[T any] func(*T) error // want `Option function should not return any values`
+
+type Builder[T any] struct {
+	cfg T
+}
+
+// Not reported: builder methods return their receiver
+func (b *Builder[T]) WithConfig(cfg T) *Builder[T] {
+	b.cfg = cfg
+	return b
+}
+
+// Reported: returns something other than its receiver
+func (b *Builder[T]) WithDefaults() T { // want `function "WithDefaults" starts with 'With' but doesn't return an Option type`
+	return b.cfg
+}
@@ -0,0 +1,13 @@
+package nolint
+
+type Server struct{}
+
+// Reported: too many constructor parameters
+func NewServer(host string, port int, user, pass, realm string) *Server { // want `constructor "NewServer" has 5 parameters`
+	return &Server{}
+}
+
+// Suppressed inline
+func NewLegacyServer(host string, port int, user, pass, realm string) *Server { //nolint:optionspattern // mirrors the v1 API
+	return &Server{}
+}
@@ -0,0 +1,474 @@
+// Package orphanconst provides an analyzer that reports exported constants,
+// sentinel errors, and functions that nothing in the module references.
+//
+// The unused check of staticcheck stops at exported symbols because another
+// package might use them. Within a module that is knowable: an exported
+// sentinel that no other package matches against is dead API that humaneerror
+// and sentinelerrors keep growing. Packages with external consumers, pkg/ by
+// default, are skipped.
+package orphanconst
+
+import (
+	"bufio"
+	"flag"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `report exported constants, sentinel errors, and functions nothing in the module uses
+
+For every package that is not a main package and not public API, this
+analyzer reports exported constants, package-level error variables, and
+functions that have:
+1. No reference from any other package in the module
+2. No reference in their own package outside their declaration
+
+Symbols that only tests reference get a distinct message, since they can
+usually move into a _test.go file.
+
+The whole module is scanned for references, so a symbol used by a package
+that is not being analyzed still counts as used. Files excluded by build
+constraints for the current GOOS, GOARCH, and build tags do not count. The
+scan is shared by the packages of one analysis run and redone for the next.
+Findings are advisory.
+
+Bad:
+    // package internal/store
+    var ErrConflict = errors.New("conflict") // nothing checks for it
+
+Good:
+    // package internal/store
+    var errConflict = errors.New("conflict")
+
+Flags:
+    -public  comma-separated globs of packages with external consumers,
+             relative to the module root (default "pkg")`
+
+var public string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "orphanconst",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("orphanconst", flag.ExitOnError)
+	fs.StringVar(&public, "public", "pkg",
+		"comma-separated globs of packages with external consumers, relative to the module root")
+	return *fs
+}
+
+// candidate is an exported symbol that may be unused
+type candidate struct {
+	ident *ast.Ident
+	kind  string
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if pass.Pkg.Name() == "main" || strings.HasSuffix(pass.Pkg.Name(), "_test") {
+		return nil, nil
+	}
+
+	var dir string
+	for _, f := range pass.Files {
+		if filename := pass.Fset.Position(f.Pos()).Filename; !isTestFile(filename) {
+			dir = filepath.Dir(filename)
+			break
+		}
+	}
+	if dir == "" {
+		return nil, nil
+	}
+
+	mod := moduleFor(pass.Fset, dir, pass.Pkg.Path())
+	if mod == nil || isPublic(mod.relative(pass.Pkg.Path())) {
+		return nil, nil
+	}
+
+	reporter := nolint.NewReporter(pass)
+
+	var candidates []candidate
+	used := make(map[types.Object]bool)
+
+	for ident, obj := range pass.TypesInfo.Uses {
+		if !isTestFile(pass.Fset.Position(ident.Pos()).Filename) {
+			used[obj] = true
+		}
+	}
+
+	for _, f := range pass.Files {
+		if isTestFile(pass.Fset.Position(f.Pos()).Filename) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			candidates = append(candidates, exportedCandidates(pass, decl)...)
+		}
+	}
+
+	for _, c := range candidates {
+		if used[pass.TypesInfo.Defs[c.ident]] {
+			continue
+		}
+
+		refs := mod.refsTo(pass.Pkg.Path(), c.ident.Name)
+		switch {
+		case refs.prod:
+			continue
+		case refs.test:
+			reporter.Report(&analysis.Diagnostic{
+				Pos:      c.ident.Pos(),
+				Category: nolint.CategoryAdvisory,
+				Message: "exported " + c.kind + " " + c.ident.Name +
+					" is only used in tests; unexport it or move it into a _test.go file",
+			})
+		default:
+			reporter.Report(&analysis.Diagnostic{
+				Pos:      c.ident.Pos(),
+				Category: nolint.CategoryAdvisory,
+				Message: "exported " + c.kind + " " + c.ident.Name +
+					" is not referenced anywhere in the module; unexport or delete it",
+			})
+		}
+	}
+
+	return nil, nil
+}
+
+// exportedCandidates returns the exported constants, error variables, and
+// functions declared by decl
+func exportedCandidates(pass *analysis.Pass, decl ast.Decl) []candidate {
+	var result []candidate
+
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil && d.Name.IsExported() {
+			result = append(result, candidate{ident: d.Name, kind: "function"})
+		}
+
+	case *ast.GenDecl:
+		if d.Tok != token.CONST && d.Tok != token.VAR {
+			return nil
+		}
+		for _, spec := range d.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, name := range vs.Names {
+				if !name.IsExported() {
+					continue
+				}
+				obj := pass.TypesInfo.Defs[name]
+				if obj == nil {
+					continue
+				}
+				switch {
+				case d.Tok == token.CONST:
+					result = append(result, candidate{ident: name, kind: "constant"})
+				case isError(obj.Type()):
+					result = append(result, candidate{ident: name, kind: "error"})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// isError checks if t implements the error interface
+func isError(t types.Type) bool {
+	errType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(t, errType)
+}
+
+// isPublic checks if the package at rel, relative to the module root,
+// matches a public API glob. A glob also covers the packages below it.
+func isPublic(rel string) bool {
+	for _, glob := range strings.Split(public, ",") {
+		glob = strings.Trim(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+		for p := rel; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+			if ok, _ := path.Match(glob, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTestFile checks if filename is a _test.go file
+func isTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go")
+}
+
+// refs records where a symbol is referenced from
+type refs struct {
+	prod bool // from non-test code of another package
+	test bool // from test files in any package
+}
+
+// module is a syntactic index of the references between the packages of a
+// module
+type module struct {
+	root  string // directory containing go.mod, or the GOPATH src directory
+	path  string // module path, empty for GOPATH layouts
+	refs  map[string]refs
+	names map[string]string // import path -> package name, from the package clauses
+}
+
+// The module indexes of the current analysis run. Drivers load the packages
+// of a run into one file set, so a new file set starts a new run and drops
+// the indexes of the last one, which long-lived hosts would otherwise keep
+// serving after the files changed.
+var (
+	modulesMu   sync.Mutex
+	modulesFset *token.FileSet
+	modules     map[string]*module // by root
+)
+
+// moduleFor returns the index of the module containing the package pkgPath in
+// dir for the run that loaded fset, building it on first use. It returns nil
+// if the module root cannot be determined.
+func moduleFor(fset *token.FileSet, dir, pkgPath string) *module {
+	root, modPath, ok := findRoot(dir, pkgPath)
+	if !ok {
+		return nil
+	}
+
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if fset != modulesFset {
+		modulesFset, modules = fset, make(map[string]*module)
+	}
+	if m, ok := modules[root]; ok {
+		return m
+	}
+	m := &module{root: root, path: modPath, refs: make(map[string]refs)}
+	m.index()
+	modules[root] = m
+	return m
+}
+
+// moduleLine matches the module directive of a go.mod file
+var moduleLine = regexp.MustCompile(`^module\s+"?([^"\s]+)"?`)
+
+// findRoot walks up from dir to the go.mod whose module path, joined with
+// the directories below it, gives pkgPath. Without one, dir is treated as
+// part of a GOPATH tree.
+func findRoot(dir, pkgPath string) (root, modPath string, ok bool) {
+	for d := dir; ; d = filepath.Dir(d) {
+		if modPath, ok := readModulePath(filepath.Join(d, "go.mod")); ok {
+			rel, err := filepath.Rel(d, dir)
+			if err == nil && joinPath(modPath, filepath.ToSlash(rel)) == pkgPath {
+				return d, modPath, true
+			}
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	suffix := filepath.FromSlash("/" + pkgPath)
+	if strings.HasSuffix(dir, suffix) {
+		return strings.TrimSuffix(dir, suffix), "", true
+	}
+	return "", "", false
+}
+
+// readModulePath returns the module path declared in the go.mod file at name
+func readModulePath(name string) (string, bool) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := moduleLine.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			return m[1], true
+		}
+	}
+	return "", false
+}
+
+// joinPath joins an import path prefix and a relative directory
+func joinPath(prefix, rel string) string {
+	switch {
+	case rel == ".":
+		return prefix
+	case prefix == "":
+		return rel
+	}
+	return prefix + "/" + rel
+}
+
+// relative returns pkgPath relative to the module root
+func (m *module) relative(pkgPath string) string {
+	if m.path == "" {
+		return pkgPath
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(pkgPath, m.path), "/")
+}
+
+// refsTo returns the references to name in the package pkgPath
+func (m *module) refsTo(pkgPath, name string) refs {
+	return m.refs[pkgPath+"."+name]
+}
+
+// index parses every Go file in the module that the build constraints of
+// build.Default select and records qualified references to the packages it
+// imports. Identifiers in in-package test files count as test references to
+// their own package, and identifiers in files with a dot import as references
+// to the imported package. Nested modules, vendor, testdata, and hidden
+// directories are skipped.
+func (m *module) index() {
+	type parsedFile struct {
+		f    *ast.File
+		self string
+		test bool
+	}
+	var files []parsedFile
+	m.names = make(map[string]string)
+
+	fset := token.NewFileSet()
+
+	_ = filepath.WalkDir(m.root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if name == m.root {
+				return nil
+			}
+			base := d.Name()
+			if base == "vendor" || base == "testdata" || strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(name, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+		if match, err := build.Default.MatchFile(filepath.Dir(name), d.Name()); err != nil || !match {
+			return nil
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(m.root, filepath.Dir(name))
+		if err != nil {
+			return nil
+		}
+		self := joinPath(m.path, filepath.ToSlash(rel))
+		if !strings.HasSuffix(f.Name.Name, "_test") {
+			m.names[self] = f.Name.Name
+		}
+		files = append(files, parsedFile{f: f, self: self, test: isTestFile(name)})
+		return nil
+	})
+
+	// Imports are resolved once the package names of all directories are known
+	for _, file := range files {
+		m.indexFile(file.f, file.self, file.test)
+	}
+}
+
+// indexFile records the references in f, a file of the package in the
+// directory with import path self
+func (m *module) indexFile(f *ast.File, self string, test bool) {
+	imports := make(map[string]string) // local name -> import path
+	var dotImports []string
+	for _, spec := range f.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		local, ok := m.names[importPath]
+		if !ok {
+			local = importName(importPath)
+		}
+		if spec.Name != nil {
+			local = spec.Name.Name
+		}
+		switch local {
+		case "_":
+		case ".":
+			dotImports = append(dotImports, importPath)
+		default:
+			imports[local] = importPath
+		}
+	}
+
+	record := func(key string) {
+		r := m.refs[key]
+		if test {
+			r.test = true
+		} else {
+			r.prod = true
+		}
+		m.refs[key] = r
+	}
+
+	inPackageTest := test && !strings.HasSuffix(f.Name.Name, "_test")
+	selected := make(map[*ast.Ident]bool) // Sel of selector expressions
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			selected[node.Sel] = true
+			if x, ok := node.X.(*ast.Ident); ok {
+				if importPath, ok := imports[x.Name]; ok {
+					record(importPath + "." + node.Sel.Name)
+				}
+			}
+		case *ast.Ident:
+			if inPackageTest {
+				record(self + "." + node.Name)
+			}
+			if !selected[node] {
+				for _, importPath := range dotImports {
+					record(importPath + "." + node.Name)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// versionSuffix matches major version suffixes like v2
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName guesses the package name of an import path outside the module
+// from its last element, skipping major version suffixes
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if versionSuffix.MatchString(name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
@@ -0,0 +1,55 @@
+package orphanconst_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/orphanconst"
+)
+
+func TestOrphanConstAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, filepath.Join(testdata, "app"), orphanconst.Analyzer,
+		"example.com/app/internal/errs", "example.com/app/internal/dot", "example.com/app/internal/lib", "example.com/app/service", "example.com/app/pkg/api", "example.com/app/cmd/app")
+}
+
+// discard collects nothing; the test inspects the results itself
+type discard struct{}
+
+func (discard) Errorf(string, ...interface{}) {}
+
+// TestOrphanConstRescansPerRun checks that a new run sees changes to the
+// module made after an earlier run.
+func TestOrphanConstRescansPerRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS(filepath.Join(analysistest.TestData(), "app"))); err != nil {
+		t.Fatal(err)
+	}
+
+	reported := func() bool {
+		for _, result := range analysistest.Run(discard{}, dir, orphanconst.Analyzer, "example.com/app/internal/errs") {
+			for _, diag := range result.Diagnostics {
+				if strings.Contains(diag.Message, "ErrConflict") {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if !reported() {
+		t.Fatal("ErrConflict is not reported before it is used")
+	}
+
+	use := "package service\n\nimport \"example.com/app/internal/errs\"\n\nvar _ = errs.ErrConflict\n"
+	if err := os.WriteFile(filepath.Join(dir, "service", "conflict.go"), []byte(use), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if reported() {
+		t.Error("ErrConflict is reported after a later run sees it used")
+	}
+}
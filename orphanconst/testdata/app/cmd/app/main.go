@@ -0,0 +1,11 @@
+package main
+
+import "example.com/app/service"
+
+// Exported names in main packages are not reported
+const Name = "app"
+
+func main() {
+	_ = service.Lookup(Name)
+	_, _ = service.Path(Name)
+}
@@ -0,0 +1,4 @@
+package dot
+
+// Only used through a dot import in the service package
+const Separator = "/"
@@ -0,0 +1,36 @@
+package errs
+
+import "errors"
+
+// Used by the service package
+var ErrNotFound = errors.New("not found")
+
+// Nothing matches against it
+var ErrConflict = errors.New("conflict") // want `exported error ErrConflict is not referenced anywhere in the module`
+
+// Only errs_test.go checks for it
+var ErrTimeout = errors.New("timeout") // want `exported error ErrTimeout is only used in tests`
+
+// Suppressed
+var ErrLegacy = errors.New("legacy") //nolint:orphanconst // kept for the v1 client
+
+// Not an error, not reported
+var Registry = map[string]int{}
+
+// Used in this package outside its declaration
+const MaxRetries = 3
+
+const Unused = "unused" // want `exported constant Unused is not referenced anywhere in the module`
+
+// Only referenced from an external test package
+const DefaultLimit = 10 // want `exported constant DefaultLimit is only used in tests`
+
+func Retries() int {
+	return MaxRetries
+}
+
+func Dead() {} // want `exported function Dead is not referenced anywhere in the module`
+
+func unexported() error {
+	return errors.New("unexported")
+}
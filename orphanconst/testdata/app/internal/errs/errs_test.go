@@ -0,0 +1,13 @@
+package errs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTimeout(t *testing.T) {
+	if !errors.Is(ErrTimeout, ErrTimeout) {
+		t.Fatal("not a timeout")
+	}
+	_ = unexported()
+}
@@ -0,0 +1,13 @@
+package errs_test
+
+import (
+	"testing"
+
+	"example.com/app/internal/errs"
+)
+
+func TestLimit(t *testing.T) {
+	if errs.DefaultLimit < errs.Retries() {
+		t.Fatal("limit below retries")
+	}
+}
@@ -0,0 +1,7 @@
+// Package library is named differently from its directory
+package library
+
+import "errors"
+
+// Used by the service package as library.ErrGone
+var ErrGone = errors.New("gone")
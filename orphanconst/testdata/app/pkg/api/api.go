@@ -0,0 +1,8 @@
+package api
+
+import "errors"
+
+// Public API for external consumers, not reported
+var ErrUnauthorized = errors.New("unauthorized")
+
+const Version = "v1"
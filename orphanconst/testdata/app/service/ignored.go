@@ -0,0 +1,9 @@
+//go:build ignore
+
+// Files excluded by build constraints are not part of any build, so their
+// references do not count
+package service
+
+import "example.com/app/internal/errs"
+
+var _ = errs.ErrConflict
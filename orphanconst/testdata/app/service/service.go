@@ -0,0 +1,23 @@
+package service
+
+import (
+	. "example.com/app/internal/dot"
+	"example.com/app/internal/errs"
+	"example.com/app/internal/lib"
+)
+
+func Lookup(id string) error {
+	for i := 0; i < errs.Retries(); i++ {
+		if id != "" {
+			return nil
+		}
+	}
+	return errs.ErrNotFound
+}
+
+func Path(id string) (string, error) {
+	if id == "" {
+		return "", library.ErrGone
+	}
+	return Separator + id, nil
+}
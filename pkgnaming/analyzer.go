@@ -6,6 +6,7 @@ package pkgnaming
 
 import (
 	"go/ast"
+	"path"
 	"strings"
 	"unicode"
 
@@ -26,13 +27,18 @@ Package naming rules:
    - Good: user.Service, http.Client
 4. Use singular form: "user" not "users"
 5. Avoid generic names: util, common, misc, helper
+6. Match the import path: directory httpclient should declare package
+   httpclient, not client, or goimports guesses the wrong name. Version
+   suffixes (/v2, .v3), main packages, and external _test packages are
+   exempt, as are directories that only add a trailing "s" (users holding
+   package user) or punctuation (golint-sl holding package golintsl)
 
 Reference: https://go.dev/blog/package-names`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "pkgnaming",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -57,6 +63,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 	// Check package name issues
 	checkPackageName(reporter, pass, pkgName)
+	checkImportPath(reporter, pass, pkgName)
 
 	nodeFilter := []ast.Node{
 		(*ast.TypeSpec)(nil),
@@ -137,6 +144,75 @@ func checkPackageName(reporter *nolint.Reporter, pass *analysis.Pass, name strin
 	}
 }
 
+// checkImportPath reports packages whose name differs from the name
+// goimports assumes from their import path
+func checkImportPath(reporter *nolint.Reporter, pass *analysis.Pass, name string) {
+	importPath := pass.Pkg.Path()
+	if name == "main" || strings.HasSuffix(name, "_test") || importPath == "command-line-arguments" || len(pass.Files) == 0 {
+		return
+	}
+
+	elem := path.Base(importPath)
+	assumed := assumedName(importPath)
+	if name == assumed || name == identifierOnly(elem) {
+		return
+	}
+	// Plural directories holding a singular package: users/ with package user
+	if assumed == name+"s" {
+		return
+	}
+
+	reporter.Reportf(pass.Files[0].Package,
+		"package name %q does not match its import path %s; goimports assumes package %s, so rename the package or its directory",
+		name, importPath, assumed)
+}
+
+// assumedName returns the package name goimports assumes for importPath:
+// the last element without a major version suffix, a go- prefix, or
+// anything after the first non-identifier character (yaml.v3 is yaml)
+func assumedName(importPath string) string {
+	base := path.Base(importPath)
+	if isMajorVersion(base) {
+		if dir := path.Dir(importPath); dir != "." {
+			base = path.Base(dir)
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, notIdentifier); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+// isMajorVersion checks if elem is a major version suffix like v2
+func isMajorVersion(elem string) bool {
+	digits, ok := strings.CutPrefix(elem, "v")
+	if !ok || digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// identifierOnly drops the characters of elem that cannot appear in an
+// identifier, so golint-sl becomes golintsl
+func identifierOnly(elem string) string {
+	return strings.Map(func(r rune) rune {
+		if notIdentifier(r) {
+			return -1
+		}
+		return r
+	}, elem)
+}
+
+func notIdentifier(r rune) bool {
+	return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
 func checkStutter(reporter *nolint.Reporter, pkgName, exportedName string, node ast.Node, kind string) {
 	pkgLower := strings.ToLower(pkgName)
 	nameLower := strings.ToLower(exportedName)
@@ -0,0 +1,24 @@
+package pkgnaming_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/pkgnaming"
+)
+
+func TestPkgNamingImportPath(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, pkgnaming.Analyzer,
+		"example.com/mod/v3",
+		"example.com/app/internal/store",
+		"example.com/app/internal/httpclient",
+		"gopkg.in/yaml.v3",
+		"example.com/widgets",
+		"example.com/sessions",
+		"example.com/golint-sl",
+		"example.com/go-cmp",
+		"example.com/cmd/tool",
+	)
+}
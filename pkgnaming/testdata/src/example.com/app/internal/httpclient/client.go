@@ -0,0 +1,2 @@
+// Package client does not match its directory.
+package client // want `package name "client" does not match its import path example.com/app/internal/httpclient; goimports assumes package httpclient`
@@ -0,0 +1,2 @@
+// Package store sits below an internal/ segment.
+package store
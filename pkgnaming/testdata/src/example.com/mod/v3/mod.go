@@ -0,0 +1,2 @@
+// Package mod is the v3 major version; the suffix is not part of the name.
+package mod
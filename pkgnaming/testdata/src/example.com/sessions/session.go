@@ -0,0 +1,2 @@
+// Package session lives in a plural directory, which is only a trailing s.
+package session
@@ -0,0 +1 @@
+package gadget // want `package name "gadget" does not match its import path example.com/widgets; goimports assumes package widgets`
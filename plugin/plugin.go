@@ -0,0 +1,177 @@
+// Package plugin registers golint-sl as a golangci-lint v2 module plugin.
+//
+// To use golint-sl with golangci-lint, build a custom binary that includes
+// this package. Reference it in .custom-gcl.yml:
+//
+//	version: v2.8.0
+//	plugins:
+//	  - module: 'github.com/spechtlabs/golint-sl'
+//	    import: 'github.com/spechtlabs/golint-sl/plugin'
+//	    version: v0.1.0
+//
+// Then run golangci-lint custom and enable the linter in .golangci.yml. The
+// settings mirror .golint-sl.yaml:
+//
+//	linters:
+//	  enable:
+//	    - golint-sl
+//	  settings:
+//	    custom:
+//	      golint-sl:
+//	        type: module
+//	        settings:
+//	          analyzers:
+//	            todotracker: false
+//	            fieldpadding: true
+//	          analyzer-settings:
+//	            functionsize:
+//	              warn: 100
+//
+// See https://golangci-lint.run/plugins/module-plugins/ for more details.
+package plugin
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/spechtlabs/golint-sl/analyzers"
+	"github.com/spechtlabs/golint-sl/internal/config"
+	"github.com/spechtlabs/golint-sl/internal/suggest"
+)
+
+// Name is the linter name golint-sl registers with golangci-lint.
+const Name = "golint-sl"
+
+//nolint:gochecknoinits // Required for golangci-lint module plugin registration
+func init() {
+	register.Plugin(Name, New)
+}
+
+// Settings configures the plugin from the settings section of the
+// golangci-lint configuration.
+type Settings struct {
+	// Analyzers enables or disables analyzers by name, like the analyzers
+	// section of .golint-sl.yaml. Use "default: false" to disable all by
+	// default, then enable specific ones.
+	Analyzers map[string]bool `json:"analyzers"`
+
+	// DisabledAnalyzers is a list of analyzer names to disable.
+	DisabledAnalyzers []string `json:"disabled-analyzers"`
+
+	// EnabledAnalyzers is a list of opt-in analyzer names to enable.
+	EnabledAnalyzers []string `json:"enabled-analyzers"`
+
+	// AnalyzerSettings overrides analyzer thresholds, using the same keys as
+	// the analyzer-settings section of .golint-sl.yaml.
+	AnalyzerSettings map[string]map[string]any `json:"analyzer-settings"`
+}
+
+type golintslPlugin struct {
+	settings Settings
+}
+
+// New creates a new golint-sl plugin instance. Unknown settings and unknown
+// analyzer names are errors, so a typo does not silently run everything.
+func New(conf any) (register.LinterPlugin, error) {
+	s, err := register.DecodeSettings[Settings](conf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", Name, err)
+	}
+
+	if err := s.validate(analyzers.All()); err != nil {
+		return nil, err
+	}
+
+	return &golintslPlugin{settings: s}, nil
+}
+
+// validate checks that every analyzer named in s exists
+func (s Settings) validate(all []*analysis.Analyzer) error {
+	known := make(map[string]bool, len(all))
+	names := make([]string, 0, len(all))
+	for _, a := range all {
+		known[a.Name] = true
+		names = append(names, a.Name)
+	}
+
+	check := func(section, name string) error {
+		if known[name] {
+			return nil
+		}
+		if suggestion := suggest.Closest(name, names, 3); suggestion != "" {
+			return fmt.Errorf("%s: unknown analyzer %q in %s; did you mean %q?", Name, name, section, suggestion)
+		}
+		return fmt.Errorf("%s: unknown analyzer %q in %s; run golint-sl help for the list of analyzers", Name, name, section)
+	}
+
+	for _, name := range sortedKeys(s.Analyzers) {
+		if name == "default" {
+			continue
+		}
+		if err := check("analyzers", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range s.EnabledAnalyzers {
+		if err := check("enabled-analyzers", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range s.DisabledAnalyzers {
+		if err := check("disabled-analyzers", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(s.AnalyzerSettings) {
+		if err := check("analyzer-settings", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildAnalyzers returns the list of analyzers to run.
+func (p *golintslPlugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	all := analyzers.All()
+
+	enabled := map[string]bool{"default": true}
+	for name, val := range p.settings.Analyzers {
+		enabled[name] = val
+	}
+	for _, name := range p.settings.EnabledAnalyzers {
+		enabled[name] = true
+	}
+	for _, name := range p.settings.DisabledAnalyzers {
+		enabled[name] = false
+	}
+
+	cfg := &config.Config{
+		Analyzers:        enabled,
+		AnalyzerSettings: p.settings.AnalyzerSettings,
+		OptIn:            analyzers.OptIn(),
+	}
+	if err := cfg.ApplySettings(all); err != nil {
+		return nil, fmt.Errorf("%s: %w", Name, err)
+	}
+
+	return cfg.FilterAnalyzers(all), nil
+}
+
+// GetLoadMode returns the load mode required by the analyzers.
+// Several golint-sl analyzers use pass.TypesInfo, so we need TypesInfo mode.
+func (p *golintslPlugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
+
+// sortedKeys returns the keys of m in order, for deterministic errors
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
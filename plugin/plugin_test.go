@@ -0,0 +1,135 @@
+package plugin_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golangci/plugin-module-register/register"
+
+	"github.com/spechtlabs/golint-sl/analyzers"
+	"github.com/spechtlabs/golint-sl/plugin"
+)
+
+func TestRegistered(t *testing.T) {
+	if _, err := register.GetPlugin(plugin.Name); err != nil {
+		t.Fatalf("GetPlugin(%q) error = %v", plugin.Name, err)
+	}
+}
+
+func TestNewSettingsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    any
+		wantErr []string
+	}{
+		{
+			name:    "typo in analyzers map suggests the closest name",
+			conf:    map[string]any{"analyzers": map[string]any{"todotraker": false}},
+			wantErr: []string{`unknown analyzer "todotraker" in analyzers`, `did you mean "todotracker"?`},
+		},
+		{
+			name:    "unknown name without a close match",
+			conf:    map[string]any{"disabled-analyzers": []any{"gofumpt"}},
+			wantErr: []string{`unknown analyzer "gofumpt" in disabled-analyzers`, "golint-sl help"},
+		},
+		{
+			name:    "unknown name in enabled-analyzers",
+			conf:    map[string]any{"enabled-analyzers": []any{"fieldpading"}},
+			wantErr: []string{`unknown analyzer "fieldpading" in enabled-analyzers`, `did you mean "fieldpadding"?`},
+		},
+		{
+			name:    "unknown name in analyzer-settings",
+			conf:    map[string]any{"analyzer-settings": map[string]any{"funcsize": map[string]any{"warn": 100}}},
+			wantErr: []string{`unknown analyzer "funcsize" in analyzer-settings`},
+		},
+		{
+			name:    "unknown setting key",
+			conf:    map[string]any{"disable": []any{"todotracker"}},
+			wantErr: []string{"decoding settings", `unknown field "disable"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := plugin.New(tt.conf)
+			if err == nil {
+				t.Fatalf("New() error = nil, want an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("New() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildAnalyzers(t *testing.T) {
+	const optInName = "fieldpadding"
+	if !analyzers.OptIn()[optInName] {
+		t.Fatalf("%s is not opt-in", optInName)
+	}
+
+	tests := []struct {
+		name    string
+		conf    any
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "no settings runs defaults",
+			conf:    nil,
+			want:    []string{"nilcheck", "todotracker"},
+			notWant: []string{optInName},
+		},
+		{
+			name:    "analyzers map disables and enables by name",
+			conf:    map[string]any{"analyzers": map[string]any{"todotracker": false, optInName: true}},
+			want:    []string{"nilcheck", optInName},
+			notWant: []string{"todotracker"},
+		},
+		{
+			name:    "default false runs only named analyzers",
+			conf:    map[string]any{"analyzers": map[string]any{"default": false, "nilcheck": true}},
+			want:    []string{"nilcheck"},
+			notWant: []string{"todotracker"},
+		},
+		{
+			name:    "disabled-analyzers and enabled-analyzers lists",
+			conf:    map[string]any{"disabled-analyzers": []any{"todotracker"}, "enabled-analyzers": []any{optInName}},
+			want:    []string{"nilcheck", optInName},
+			notWant: []string{"todotracker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := plugin.New(tt.conf)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := p.GetLoadMode(); got != register.LoadModeTypesInfo {
+				t.Errorf("GetLoadMode() = %q, want %q", got, register.LoadModeTypesInfo)
+			}
+
+			built, err := p.BuildAnalyzers()
+			if err != nil {
+				t.Fatalf("BuildAnalyzers() error = %v", err)
+			}
+			names := make(map[string]bool, len(built))
+			for _, a := range built {
+				names[a.Name] = true
+			}
+			for _, name := range tt.want {
+				if !names[name] {
+					t.Errorf("BuildAnalyzers() is missing %s", name)
+				}
+			}
+			for _, name := range tt.notWant {
+				if names[name] {
+					t.Errorf("BuildAnalyzers() includes %s", name)
+				}
+			}
+		})
+	}
+}
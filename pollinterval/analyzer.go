@@ -0,0 +1,524 @@
+// Package pollinterval provides an analyzer that checks polling loops in
+// controllers and agents for intervals that do not hold up at fleet scale.
+//
+// A loop that calls an upstream every 100ms is harmless on one laptop and a
+// denial of service from a thousand replicas. Fixed intervals make replicas
+// started together poll in lockstep, and loops that keep polling at full
+// speed while the upstream fails make its recovery harder.
+package pollinterval
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check polling loops for short, fixed, and non-backing-off intervals
+
+A polling loop is a for or range loop that waits (time.Sleep, time.After,
+a ticker or timer channel) and makes an outbound call (net/http,
+database/sql, or any call that takes a context.Context). This analyzer
+reports:
+1. Constant intervals below -min-interval (1s by default)
+2. Loops in functions without jitter, so replicas poll in lockstep
+   (advisory; math/rand and functions matching -jitter count as jitter)
+3. Error branches that keep polling at the same interval instead of
+   backing off
+4. time.Tick, whose ticker cannot be stopped
+
+Functions using k8s.io/apimachinery/pkg/util/wait, such as wait.Until,
+wait.JitterUntil, or wait.PollUntilContextCancel, are not checked.
+
+Bad:
+    for {
+        if err := c.sync(ctx); err != nil {
+            log.Error(err)
+        }
+        time.Sleep(100 * time.Millisecond)
+    }
+
+Good:
+    wait.JitterUntilWithContext(ctx, c.syncOnce, c.interval, 0.1, true)
+
+Flags:
+    -min-interval  shortest acceptable constant poll interval (default 1s)
+    -jitter        comma-separated globs of qualified function names that
+                   add jitter (default *jitter*)`
+
+var (
+	minInterval time.Duration
+	jitter      string
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "pollinterval",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("pollinterval", flag.ExitOnError)
+	fs.DurationVar(&minInterval, "min-interval", time.Second,
+		"shortest acceptable constant poll interval")
+	fs.StringVar(&jitter, "jitter", "*jitter*",
+		"comma-separated globs of qualified function names that add jitter")
+	return *fs
+}
+
+const waitPackage = "k8s.io/apimachinery/pkg/util/wait"
+
+// outboundPackages are packages whose calls always leave the process
+var outboundPackages = map[string]bool{
+	"net":          true,
+	"net/http":     true,
+	"database/sql": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var jitterGlobs []*regexp.Regexp
+	for _, glob := range strings.Split(jitter, ",") {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			jitterGlobs = append(jitterGlobs, globRegexp(glob))
+		}
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil || strings.HasSuffix(pass.Fset.Position(fn.Pos()).Filename, "_test.go") {
+			return
+		}
+
+		checkTick(reporter, pass, fn.Body)
+
+		uses := scanCalls(pass, fn.Body, jitterGlobs)
+		if uses.wait {
+			return
+		}
+
+		c := &checker{pass: pass, reporter: reporter, jittered: uses.jitter}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch loop := n.(type) {
+			case *ast.ForStmt:
+				c.checkLoop(fn.Body, loop, loop.Body, nil)
+			case *ast.RangeStmt:
+				c.checkLoop(fn.Body, loop, loop.Body, loop.X)
+			}
+			return true
+		})
+	})
+
+	return nil, nil
+}
+
+// funcUses records which kinds of helpers a function calls
+type funcUses struct {
+	wait   bool // k8s wait helpers
+	jitter bool // math/rand or a -jitter function
+}
+
+// scanCalls looks for wait helpers and jitter anywhere in body
+func scanCalls(pass *analysis.Pass, body *ast.BlockStmt, jitterGlobs []*regexp.Regexp) funcUses {
+	var uses funcUses
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := callee(pass, call)
+		if fn == nil || fn.Pkg() == nil {
+			return true
+		}
+		switch path := fn.Pkg().Path(); {
+		case path == waitPackage:
+			uses.wait = true
+		case path == "math/rand" || path == "math/rand/v2":
+			uses.jitter = true
+		default:
+			name := qualifiedName(fn)
+			for _, re := range jitterGlobs {
+				if re.MatchString(name) {
+					uses.jitter = true
+				}
+			}
+		}
+		return true
+	})
+	return uses
+}
+
+// checkTick reports every time.Tick call in body
+func checkTick(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isTimeFunc(pass, call, "Tick") {
+			reporter.Reportf(call.Pos(),
+				"time.Tick cannot be stopped, so the ticker outlives the loop; use time.NewTicker and defer ticker.Stop()")
+		}
+		return true
+	})
+}
+
+// checker checks the polling loops of one function
+type checker struct {
+	pass     *analysis.Pass
+	reporter *nolint.Reporter
+	jittered bool
+}
+
+// wait is one place a polling loop waits for its next round
+type wait struct {
+	dur ast.Expr // nil when the interval is unknown
+}
+
+// checkLoop checks a loop if it is a polling loop. rangeX is the range
+// expression of a range loop, which waits when it is a ticker channel.
+func (c *checker) checkLoop(fnBody *ast.BlockStmt, loop ast.Node, body *ast.BlockStmt, rangeX ast.Expr) {
+	var waits []wait
+	if rangeX != nil {
+		if w, ok := c.channelWait(fnBody, rangeX); ok {
+			waits = append(waits, w)
+		}
+	}
+
+	outbound := false
+	var errBranches []*ast.IfStmt
+	inspectLoopBody(body, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			switch {
+			case isTimeFunc(c.pass, node, "Sleep"), isTimeFunc(c.pass, node, "After"):
+				if len(node.Args) == 1 {
+					waits = append(waits, wait{dur: node.Args[0]})
+				}
+			case c.isOutbound(node):
+				outbound = true
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				if w, ok := c.channelWait(fnBody, node.X); ok {
+					waits = append(waits, w)
+				}
+			}
+		case *ast.IfStmt:
+			if c.isErrCheck(node.Cond) {
+				errBranches = append(errBranches, node)
+			}
+		}
+	})
+
+	if len(waits) == 0 || !outbound {
+		return
+	}
+
+	fixed := true
+	for _, w := range waits {
+		c.checkFloor(w)
+		if !c.isFixed(body, w) {
+			fixed = false
+		}
+	}
+
+	if !c.jittered {
+		c.reporter.Report(&analysis.Diagnostic{
+			Pos:      loop.Pos(),
+			Category: nolint.CategoryAdvisory,
+			Message:  "poll loop waits a fixed interval; add jitter so replicas do not poll in lockstep",
+		})
+	}
+
+	if !fixed {
+		return
+	}
+	for _, branch := range errBranches {
+		if !leavesLoop(branch.Body) {
+			c.reporter.Reportf(branch.Pos(),
+				"poll loop keeps polling at the same interval while the call fails; back off until it succeeds again")
+		}
+	}
+}
+
+// inspectLoopBody calls visit for the nodes of a loop body, without
+// descending into function literals and nested loops, which are separate
+func inspectLoopBody(body *ast.BlockStmt, visit func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.FuncLit, *ast.ForStmt, *ast.RangeStmt:
+			return false
+		}
+		visit(n)
+		return true
+	})
+}
+
+// channelWait recognizes ticker.C, timer.C, and time.Tick(d) channels and
+// finds the interval the ticker or timer was created with
+func (c *checker) channelWait(fnBody *ast.BlockStmt, expr ast.Expr) (wait, bool) {
+	expr = ast.Unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok && isTimeFunc(c.pass, call, "Tick") && len(call.Args) == 1 {
+		return wait{dur: call.Args[0]}, true
+	}
+
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "C" || !isTimeType(c.pass.TypesInfo.TypeOf(sel.X)) {
+		return wait{}, false
+	}
+
+	var w wait
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		if obj := c.pass.TypesInfo.ObjectOf(ident); obj != nil {
+			w.dur = c.tickerInterval(fnBody, obj)
+		}
+	}
+	return w, true
+}
+
+// tickerInterval finds the interval obj was created with by time.NewTicker
+// or time.NewTimer in the function
+func (c *checker) tickerInterval(fnBody *ast.BlockStmt, obj types.Object) ast.Expr {
+	var dur ast.Expr
+	ast.Inspect(fnBody, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok || c.pass.TypesInfo.ObjectOf(ident) != obj {
+				continue
+			}
+			call, ok := assign.Rhs[i].(*ast.CallExpr)
+			if ok && len(call.Args) == 1 && (isTimeFunc(c.pass, call, "NewTicker") || isTimeFunc(c.pass, call, "NewTimer")) {
+				dur = call.Args[0]
+			}
+		}
+		return true
+	})
+	return dur
+}
+
+// checkFloor reports constant intervals below the configured minimum
+func (c *checker) checkFloor(w wait) {
+	if w.dur == nil {
+		return
+	}
+	tv, ok := c.pass.TypesInfo.Types[w.dur]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return
+	}
+	n, exact := constant.Int64Val(tv.Value)
+	if !exact || time.Duration(n) >= minInterval {
+		return
+	}
+	c.reporter.Reportf(w.dur.Pos(),
+		"poll interval %s is below %s and hammers the upstream at fleet scale; make it configurable with a floor of %s",
+		time.Duration(n), minInterval, minInterval)
+}
+
+// isFixed checks if the interval of w stays the same across iterations: it
+// is a constant, or built from variables the loop never assigns
+func (c *checker) isFixed(body *ast.BlockStmt, w wait) bool {
+	if w.dur == nil {
+		// A ticker keeps its period unless the loop resets it
+		return !callsMethod(body, "Reset")
+	}
+
+	fixed := true
+	ast.Inspect(w.dur, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			// Calls like backoff.Next() may return anything, conversions
+			// and constants do not
+			fun := c.pass.TypesInfo.Types[node.Fun]
+			if tv := c.pass.TypesInfo.Types[node]; tv.Value == nil && !fun.IsType() {
+				fixed = false
+			}
+		case *ast.Ident:
+			if obj := c.pass.TypesInfo.ObjectOf(node); obj != nil && assigns(c.pass, body, obj) {
+				fixed = false
+			}
+		}
+		return fixed
+	})
+	return fixed
+}
+
+// callsMethod checks if body calls a method with the given name
+func callsMethod(body *ast.BlockStmt, name string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// assigns checks if body assigns to obj
+func assigns(pass *analysis.Pass, body *ast.BlockStmt, obj types.Object) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+					found = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if ident, ok := node.X.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isErrCheck checks if cond is err != nil for an error value
+func (c *checker) isErrCheck(cond ast.Expr) bool {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	if ident, ok := bin.Y.(*ast.Ident); !ok || ident.Name != "nil" {
+		return false
+	}
+	t := c.pass.TypesInfo.TypeOf(bin.X)
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// leavesLoop checks if an error branch returns or breaks out of the loop
+func leavesLoop(body *ast.BlockStmt) bool {
+	leaves := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			leaves = true
+		case *ast.BranchStmt:
+			if node.Tok == token.BREAK || node.Tok == token.GOTO {
+				leaves = true
+			}
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				leaves = true
+			}
+		}
+		return !leaves
+	})
+	return leaves
+}
+
+// isOutbound checks if call leaves the process: a call into net/http,
+// database/sql, or net, or any call that takes a context.Context
+func (c *checker) isOutbound(call *ast.CallExpr) bool {
+	fn := callee(c.pass, call)
+	if fn == nil {
+		return false
+	}
+	if fn.Pkg() != nil {
+		if path := fn.Pkg().Path(); outboundPackages[path] {
+			return true
+		} else if path == "context" || path == "time" {
+			return false
+		}
+	}
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if isContextType(params.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// callee returns the function or method called, if it is statically known
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+// isTimeFunc checks if call is the package-level function time.<name>
+func isTimeFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	fn := callee(pass, call)
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == "time" && fn.Name() == name &&
+		fn.Type().(*types.Signature).Recv() == nil
+}
+
+// isTimeType checks if t is a *time.Ticker or *time.Timer
+func isTimeType(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "time" {
+		return false
+	}
+	return named.Obj().Name() == "Ticker" || named.Obj().Name() == "Timer"
+}
+
+// isContextType checks if t is context.Context
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// qualifiedName returns the import path qualified name of fn, including
+// the receiver type for methods
+func qualifiedName(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	if fn.Pkg() == nil {
+		return name
+	}
+	return fn.Pkg().Path() + "." + name
+}
+
+// globRegexp compiles a case-insensitive glob where * matches anything
+func globRegexp(glob string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + quoted + "$")
+}
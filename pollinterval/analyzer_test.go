@@ -0,0 +1,14 @@
+package pollinterval_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/pollinterval"
+)
+
+func TestPollIntervalAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, pollinterval.Analyzer, "a")
+}
@@ -0,0 +1,102 @@
+package a
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type Agent struct {
+	client   *http.Client
+	url      string
+	interval time.Duration
+}
+
+func (a *Agent) sync(ctx context.Context) error {
+	return nil
+}
+
+// Hardcoded 100ms poll of an HTTP endpoint
+func (a *Agent) Watch() {
+	for { // want `poll loop waits a fixed interval; add jitter`
+		resp, err := a.client.Get(a.url)
+		if err != nil { // want `poll loop keeps polling at the same interval while the call fails`
+			log.Print(err)
+			time.Sleep(100 * time.Millisecond) // want `poll interval 100ms is below 1s and hammers the upstream at fleet scale`
+			continue
+		}
+		resp.Body.Close()
+		time.Sleep(100 * time.Millisecond) // want `poll interval 100ms is below 1s`
+	}
+}
+
+// Ticker without jitter or backoff
+func (a *Agent) Run(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C { // want `poll loop waits a fixed interval`
+		if err := a.sync(ctx); err != nil { // want `poll loop keeps polling at the same interval`
+			log.Print(err)
+		}
+	}
+}
+
+// time.Tick inside select
+func (a *Agent) Refresh(ctx context.Context) error {
+	for { // want `poll loop waits a fixed interval`
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.Tick(200 * time.Millisecond): // want `time.Tick cannot be stopped` `poll interval 200ms is below 1s`
+			if err := a.sync(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Jittered interval with exponential backoff
+func (a *Agent) Loop(ctx context.Context) {
+	interval := a.interval
+	for ctx.Err() == nil {
+		if err := a.sync(ctx); err != nil {
+			interval = min(interval*2, 5*time.Minute)
+		} else {
+			interval = a.interval
+		}
+		time.Sleep(interval + rand.N(interval/10))
+	}
+}
+
+// The k8s wait helpers handle interval, jitter, and cancellation
+func (a *Agent) Poll(ctx context.Context) error {
+	return wait.PollUntilContextCancel(ctx, 100*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+		return a.sync(ctx) == nil, nil
+	})
+}
+
+// Waiting without outbound calls is not polling
+func Spin(done func() bool) {
+	for !done() {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Jitter from a helper counts as well
+func withJitter(d time.Duration) time.Duration {
+	return d
+}
+
+func (a *Agent) Helper(ctx context.Context) {
+	for {
+		if err := a.sync(ctx); err != nil {
+			return
+		}
+		time.Sleep(withJitter(a.interval))
+	}
+}
@@ -0,0 +1,19 @@
+package wait
+
+import (
+	"context"
+	"time"
+)
+
+type ConditionWithContextFunc func(context.Context) (bool, error)
+
+func PollUntilContextCancel(ctx context.Context, interval time.Duration, immediate bool, condition ConditionWithContextFunc) error {
+	return nil
+}
+
+func JitterUntilWithContext(ctx context.Context, f func(context.Context), period time.Duration, jitterFactor float64, sliding bool) {
+}
+
+func Jitter(duration time.Duration, maxFactor float64) time.Duration {
+	return duration
+}
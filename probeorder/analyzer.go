@@ -0,0 +1,477 @@
+// Package probeorder provides an analyzer that detects file system operations
+// with time-of-check-to-time-of-use (TOCTOU) races.
+//
+// Checking whether a file exists and then acting on it leaves a window where
+// another process can create, replace, or remove it. Attempting the operation
+// and handling the error is both simpler and correct.
+package probeorder
+
+import (
+	"flag"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect file system check-then-use races
+
+This analyzer detects:
+1. os.Stat/os.Lstat existence checks (os.IsNotExist, errors.Is(err,
+   fs.ErrNotExist), or err == nil with the FileInfo discarded) followed by
+   an operation on the same path
+2. os.MkdirAll/os.Mkdir errors ignored before writing into the directory
+3. Paths from HTTP request input opened without containment validation
+   (filepath.IsLocal, strings.HasPrefix after filepath.Join, filepath.Base)
+4. os.Rename without a cross-filesystem fallback (enabled with -check-rename)
+
+Bad:
+    if _, err := os.Stat(path); os.IsNotExist(err) {
+        return nil, ErrNotFound
+    }
+    f, err := os.Open(path)
+
+Good:
+    f, err := os.Open(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return nil, ErrNotFound
+    }
+
+Flags:
+    -check-rename  report os.Rename calls without an EXDEV fallback`
+
+var checkRename bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "probeorder",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("probeorder", flag.ExitOnError)
+	fs.BoolVar(&checkRename, "check-rename", false,
+		"report os.Rename calls without a cross-filesystem fallback")
+	return *fs
+}
+
+// PathSources are methods whose results are user-controlled strings, keyed by
+// "pkgpath.Type.Method". Analyzers tracking untrusted paths share this list.
+var PathSources = map[string]bool{
+	"net/http.Request.FormValue":     true,
+	"net/http.Request.PostFormValue": true,
+	"net/http.Request.PathValue":     true,
+	"net/url.Values.Get":             true,
+}
+
+// pathOps are os functions that act on the path in their first argument
+var pathOps = map[string]bool{
+	"Open":      true,
+	"OpenFile":  true,
+	"Create":    true,
+	"ReadFile":  true,
+	"WriteFile": true,
+	"Remove":    true,
+	"RemoveAll": true,
+	"Chmod":     true,
+	"Chown":     true,
+	"Truncate":  true,
+	"ReadDir":   true,
+}
+
+// writeOps are os functions that create or write files
+var writeOps = map[string]bool{
+	"Create":    true,
+	"OpenFile":  true,
+	"WriteFile": true,
+}
+
+// validators are functions that establish a path stays within a root
+var validators = map[string]bool{
+	"path/filepath.IsLocal": true,
+	"path/filepath.Rel":     true,
+	"strings.HasPrefix":     true,
+}
+
+// taint is shared by every variable derived from the same user input
+type taint struct {
+	source    string
+	validated bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			body = node.Body
+		case *ast.FuncLit:
+			body = node.Body
+		}
+		if body == nil {
+			return
+		}
+
+		checkProbes(reporter, pass, body)
+		checkIgnoredMkdir(reporter, pass, body)
+		checkUserPaths(reporter, pass, body)
+		if checkRename {
+			checkRenames(reporter, pass, body)
+		}
+	})
+
+	return nil, nil
+}
+
+// inspectFunc walks a function body without descending into nested closures,
+// which are analyzed on their own
+func inspectFunc(body *ast.BlockStmt, fn func(ast.Node) bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		return fn(n)
+	})
+}
+
+// calleeName returns "pkgpath.Func" for package-level function calls and
+// "pkgpath.Type.Method" for method calls
+func calleeName(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return ""
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return fn.Pkg().Path() + "." + fn.Name()
+	}
+
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return ""
+	}
+	return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+}
+
+// osFunc returns the name of the os function called, or ""
+func osFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	name, ok := strings.CutPrefix(calleeName(pass, call), "os.")
+	if !ok || strings.Contains(name, ".") {
+		return ""
+	}
+	return name
+}
+
+// pathKey identifies a path expression by its source text
+func pathKey(expr ast.Expr) string {
+	return types.ExprString(ast.Unparen(expr))
+}
+
+// objectOf returns the variable an identifier refers to
+func objectOf(pass *analysis.Pass, expr ast.Expr) types.Object {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	return pass.TypesInfo.ObjectOf(ident)
+}
+
+// isExistenceCheck returns the error checked by os.IsNotExist(err),
+// os.IsExist(err), or errors.Is(err, fs.ErrNotExist)
+func isExistenceCheck(pass *analysis.Pass, call *ast.CallExpr) ast.Expr {
+	switch calleeName(pass, call) {
+	case "os.IsNotExist", "os.IsExist":
+		if len(call.Args) == 1 {
+			return call.Args[0]
+		}
+	case "errors.Is":
+		if len(call.Args) == 2 {
+			target := types.ExprString(call.Args[1])
+			if strings.HasSuffix(target, "ErrNotExist") || strings.HasSuffix(target, "ErrExist") {
+				return call.Args[0]
+			}
+		}
+	}
+	return nil
+}
+
+// statResult is an os.Stat call whose error is held in a variable
+type statResult struct {
+	path string
+	// infoDiscarded is set when the FileInfo is assigned to _, so the call
+	// can only be asking whether the path exists
+	infoDiscarded bool
+}
+
+// checkProbes flags operations on a path after an existence check on it. A
+// Stat is an existence check when its error is tested with os.IsNotExist or
+// errors.Is(err, fs.ErrNotExist), or when its FileInfo is discarded; a Stat
+// that reads the FileInfo, like the mode to write a file back with, is not.
+func checkProbes(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	// Errors returned by os.Stat, mapped to the probed call
+	statErrs := make(map[types.Object]statResult)
+	// Paths whose existence was checked, mapped to the check position
+	probed := make(map[string]token.Pos)
+
+	markProbed := func(errExpr ast.Expr, pos token.Pos, existenceTest bool) {
+		stat, ok := statErrs[objectOf(pass, errExpr)]
+		if !ok || (!existenceTest && !stat.infoDiscarded) {
+			return
+		}
+		if _, seen := probed[stat.path]; !seen {
+			probed[stat.path] = pos
+		}
+	}
+
+	inspectFunc(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			// _, err := os.Stat(path)
+			if len(node.Lhs) == 2 && len(node.Rhs) == 1 {
+				if call, ok := node.Rhs[0].(*ast.CallExpr); ok && len(call.Args) == 1 {
+					if fn := osFunc(pass, call); fn == "Stat" || fn == "Lstat" {
+						if obj := objectOf(pass, node.Lhs[1]); obj != nil {
+							statErrs[obj] = statResult{path: pathKey(call.Args[0]), infoDiscarded: isBlank(node.Lhs[0])}
+						}
+					}
+				}
+			}
+			// Reassigning the path starts over
+			for _, lhs := range node.Lhs {
+				delete(probed, pathKey(lhs))
+			}
+
+		case *ast.BinaryExpr:
+			// err == nil after os.Stat means "exists"
+			if node.Op == token.EQL && isNil(node.Y) {
+				markProbed(node.X, node.Pos(), false)
+			}
+
+		case *ast.CallExpr:
+			if errExpr := isExistenceCheck(pass, node); errExpr != nil {
+				markProbed(errExpr, node.Pos(), true)
+				return true
+			}
+
+			fn := osFunc(pass, node)
+			if !pathOps[fn] || len(node.Args) == 0 {
+				return true
+			}
+			path := pathKey(node.Args[0])
+			if pos, ok := probed[path]; ok {
+				reporter.Reportf(node.Pos(),
+					"os.%s(%s) after checking its existence at line %d is a TOCTOU race; "+
+						"attempt the operation and handle errors.Is(err, fs.ErrNotExist) instead",
+					fn, path, pass.Fset.Position(pos).Line)
+			}
+		}
+		return true
+	})
+}
+
+func isNil(expr ast.Expr) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// checkIgnoredMkdir flags ignored MkdirAll errors followed by writes into the directory
+func checkIgnoredMkdir(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	type ignoredMkdir struct {
+		call *ast.CallExpr
+		fn   string
+	}
+	ignored := make(map[string]ignoredMkdir)
+
+	inspectFunc(body, func(n ast.Node) bool {
+		var call *ast.CallExpr
+		switch node := n.(type) {
+		case *ast.ExprStmt:
+			call, _ = node.X.(*ast.CallExpr)
+		case *ast.AssignStmt:
+			if len(node.Lhs) == 1 && len(node.Rhs) == 1 && isBlank(node.Lhs[0]) {
+				call, _ = node.Rhs[0].(*ast.CallExpr)
+			}
+		case *ast.CallExpr:
+			fn := osFunc(pass, node)
+			if !writeOps[fn] || len(node.Args) == 0 {
+				return true
+			}
+			dir := parentDir(pass, node.Args[0])
+			if m, ok := ignored[dir]; ok && dir != "" {
+				reporter.Reportf(m.call.Pos(),
+					"os.%s error is ignored but os.%s writes into %s at line %d; "+
+						"a failed %s surfaces later as a confusing open error",
+					m.fn, fn, dir, pass.Fset.Position(node.Pos()).Line, m.fn)
+				delete(ignored, dir)
+			}
+			return true
+		}
+
+		if call == nil || len(call.Args) == 0 {
+			return true
+		}
+		if fn := osFunc(pass, call); fn == "MkdirAll" || fn == "Mkdir" {
+			ignored[pathKey(call.Args[0])] = ignoredMkdir{call: call, fn: fn}
+		}
+		return true
+	})
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// parentDir returns the directory a write path is built from:
+// filepath.Join(dir, ...) or path.Join(dir, ...)
+func parentDir(pass *analysis.Pass, expr ast.Expr) string {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 {
+		return ""
+	}
+	switch calleeName(pass, call) {
+	case "path/filepath.Join", "path.Join":
+		return pathKey(call.Args[0])
+	}
+	return ""
+}
+
+// checkUserPaths flags request-derived paths reaching os operations without
+// containment validation
+func checkUserPaths(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	tainted := make(map[types.Object]*taint)
+
+	inspectFunc(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				obj := objectOf(pass, lhs)
+				if obj == nil {
+					continue
+				}
+				if t := taintOf(pass, node.Rhs[i], tainted); t != nil {
+					tainted[obj] = t
+				} else {
+					delete(tainted, obj)
+				}
+			}
+
+		case *ast.CallExpr:
+			if validators[calleeName(pass, node)] {
+				for _, arg := range node.Args {
+					if t := taintOf(pass, arg, tainted); t != nil {
+						t.validated = true
+					}
+				}
+				return true
+			}
+
+			fn := osFunc(pass, node)
+			if !pathOps[fn] || len(node.Args) == 0 {
+				return true
+			}
+			if t := taintOf(pass, node.Args[0], tainted); t != nil && !t.validated {
+				reporter.Reportf(node.Args[0].Pos(),
+					"path from %s reaches os.%s without containment validation; "+
+						"check filepath.IsLocal or use os.Root to prevent path traversal",
+					t.source, fn)
+			}
+		}
+		return true
+	})
+}
+
+// taintOf returns the user-input taint carried by expr, if any
+func taintOf(pass *analysis.Pass, expr ast.Expr, tainted map[types.Object]*taint) *taint {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return tainted[pass.TypesInfo.Uses[e]]
+
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			if t := taintOf(pass, e.X, tainted); t != nil {
+				return t
+			}
+			return taintOf(pass, e.Y, tainted)
+		}
+
+	case *ast.CallExpr:
+		name := calleeName(pass, e)
+		if PathSources[name] {
+			sel := e.Fun.(*ast.SelectorExpr)
+			return &taint{source: types.ExprString(sel)}
+		}
+		switch name {
+		case "path/filepath.Base", "path.Base":
+			// Base strips every directory component
+			return nil
+		case "path/filepath.Join", "path.Join", "path/filepath.Clean", "path.Clean":
+			for _, arg := range e.Args {
+				if t := taintOf(pass, arg, tainted); t != nil {
+					return t
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkRenames flags os.Rename in functions without an EXDEV or copy fallback
+func checkRenames(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var renames []*ast.CallExpr
+	hasFallback := false
+
+	inspectFunc(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			switch calleeName(pass, node) {
+			case "os.Rename":
+				renames = append(renames, node)
+			case "io.Copy", "io.CopyBuffer":
+				hasFallback = true
+			}
+		case *ast.SelectorExpr:
+			if node.Sel.Name == "EXDEV" {
+				hasFallback = true
+			}
+		}
+		return true
+	})
+
+	if hasFallback {
+		return
+	}
+	for _, call := range renames {
+		reporter.Reportf(call.Pos(),
+			"os.Rename fails with EXDEV across filesystems; create the source in the destination directory or fall back to copy and remove")
+	}
+}
@@ -0,0 +1,24 @@
+package probeorder_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/probeorder"
+)
+
+func TestProbeOrderAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, probeorder.Analyzer, "a")
+}
+
+func TestProbeOrderCheckRename(t *testing.T) {
+	if err := probeorder.Analyzer.Flags.Set("check-rename", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = probeorder.Analyzer.Flags.Set("check-rename", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, probeorder.Analyzer, "rename")
+}
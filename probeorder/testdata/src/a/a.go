@@ -0,0 +1,136 @@
+package a
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Bad: Stat then Open
+func LoadStatThenOpen(path string) (*os.File, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return os.Open(path) // want `os.Open\(path\) after checking its existence at line \d+ is a TOCTOU race`
+}
+
+// Bad: remove if it exists
+func Cleanup(path string) error {
+	_, err := os.Stat(path)
+	if err == nil {
+		return os.Remove(path) // want `os.Remove\(path\) after checking its existence at line \d+ is a TOCTOU race`
+	}
+	return nil
+}
+
+// Bad: errors.Is probe
+func CreateIfMissing(path string) (*os.File, error) {
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return os.Create(path) // want `os.Create\(path\) after checking its existence`
+	}
+	return nil, nil
+}
+
+// Good: open and handle ErrNotExist
+func Load(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Good: Stat for metadata only
+func Size(path string) (int64, []byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := os.ReadFile(path)
+	return info.Size(), data, err
+}
+
+// Good: Stat reads the mode to write the file back with
+func Rewrite(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode()
+	}
+	return os.WriteFile(path, data, mode)
+}
+
+// Bad: the FileInfo is read, but the error is tested for existence
+func RewriteIfPresent(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return os.WriteFile(path, data, info.Mode()) // want `os.WriteFile\(path\) after checking its existence`
+}
+
+// Good: a different path
+func CopyOther(src, dst string) (*os.File, error) {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return os.Create(dst)
+}
+
+// Bad: MkdirAll error ignored
+func WriteReport(dir string, data []byte) error {
+	os.MkdirAll(dir, 0o755) // want `os.MkdirAll error is ignored but os.WriteFile writes into dir`
+	return os.WriteFile(filepath.Join(dir, "report.json"), data, 0o644)
+}
+
+// Good: MkdirAll checked
+func WriteReportChecked(dir string, data []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "report.json"), data, 0o644)
+}
+
+// Bad: request path opened directly
+func Download(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("file")
+	data, err := os.ReadFile(filepath.Join("/srv/files", name)) // want `path from r.URL.Query\(\).Get reaches os.ReadFile without containment validation`
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func Delete(r *http.Request) error {
+	p := "/srv/uploads/" + r.FormValue("name")
+	return os.Remove(p) // want `path from r.FormValue reaches os.Remove without containment validation`
+}
+
+// Good: validated with IsLocal
+func DownloadLocal(r *http.Request) ([]byte, error) {
+	name := r.PathValue("file")
+	if !filepath.IsLocal(name) {
+		return nil, ErrNotFound
+	}
+	return os.ReadFile(filepath.Join("/srv/files", name))
+}
+
+// Good: prefix check after Join
+func DownloadPrefixed(r *http.Request) ([]byte, error) {
+	p := filepath.Join("/srv/files", r.FormValue("file"))
+	if !strings.HasPrefix(p, "/srv/files/") {
+		return nil, ErrNotFound
+	}
+	return os.ReadFile(p)
+}
+
+// Good: Base strips directories
+func DownloadBase(r *http.Request) ([]byte, error) {
+	name := filepath.Base(r.FormValue("file"))
+	return os.ReadFile(filepath.Join("/srv/files", name))
+}
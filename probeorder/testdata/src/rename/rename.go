@@ -0,0 +1,31 @@
+package rename
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+func Publish(tmp, dst string) error {
+	return os.Rename(tmp, dst) // want `os.Rename fails with EXDEV across filesystems`
+}
+
+func PublishWithFallback(tmp, dst string) error {
+	err := os.Rename(tmp, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	in, err := os.Open(tmp)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,299 @@
+// Package readadoption provides an analyzer that detects io.Reader consumption
+// mistakes.
+//
+// A reader is a stream: once json.NewDecoder(resp.Body).Decode has consumed it,
+// io.ReadAll(resp.Body) returns nothing. Wrapping a reader in bufio and then
+// reading the underlying reader loses whatever bufio already buffered. Read may
+// return fewer bytes than requested without an error.
+package readadoption
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect io.Reader consumption mistakes
+
+This analyzer detects:
+1. A reader consumed twice in the same function (json.NewDecoder(r).Decode
+   followed by io.ReadAll(r)); the second read gets nothing
+2. Reading from a reader after wrapping it in bufio.NewReader/NewScanner,
+   which loses the data bufio already buffered
+3. Read calls whose byte count is ignored; Read may return a short buffer,
+   use io.ReadFull
+
+A Seek on the reader, or reassigning the variable, resets tracking.
+To read a body twice, buffer it once or use io.TeeReader:
+
+    data, err := io.ReadAll(resp.Body)
+    ...
+    json.Unmarshal(data, &v)
+
+Unclosed multipart files (FileHeader.Open) are reported by resourceclose.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "readadoption",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// consumer describes a function that reads its argument to completion (or buffers it)
+type consumer struct {
+	argIndex  int
+	buffering bool // bufio wrappers read ahead into their own buffer
+}
+
+// consumers maps "pkgpath.Func" to the argument it consumes
+var consumers = map[string]consumer{
+	"io.ReadAll":                  {argIndex: 0},
+	"io/ioutil.ReadAll":           {argIndex: 0},
+	"io.Copy":                     {argIndex: 1},
+	"io.CopyBuffer":               {argIndex: 1},
+	"encoding/json.NewDecoder":    {argIndex: 0},
+	"encoding/xml.NewDecoder":     {argIndex: 0},
+	"encoding/csv.NewReader":      {argIndex: 0},
+	"gopkg.in/yaml.v3.NewDecoder": {argIndex: 0},
+	"bufio.NewReader":             {argIndex: 0, buffering: true},
+	"bufio.NewReaderSize":         {argIndex: 0, buffering: true},
+	"bufio.NewScanner":            {argIndex: 0, buffering: true},
+}
+
+// consumption records where a reader expression was first consumed
+type consumption struct {
+	call      string
+	pos       token.Pos
+	scope     ast.Node // innermost block the consumption happened in
+	buffering bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			body = node.Body
+		case *ast.FuncLit:
+			body = node.Body
+		}
+		if body == nil {
+			return
+		}
+
+		checkDoubleConsumption(reporter, pass, body)
+		checkIgnoredReadCount(reporter, pass, body)
+	})
+
+	return nil, nil
+}
+
+// consumerOf returns the consumed argument and consumer info for a call
+func consumerOf(pass *analysis.Pass, call *ast.CallExpr) (string, ast.Expr, consumer, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil, consumer{}, false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return "", nil, consumer{}, false
+	}
+
+	name := fn.Pkg().Path() + "." + fn.Name()
+	c, ok := consumers[name]
+	if !ok || c.argIndex >= len(call.Args) {
+		return "", nil, consumer{}, false
+	}
+
+	return fn.Pkg().Name() + "." + fn.Name(), call.Args[c.argIndex], c, true
+}
+
+// readerKey returns a stable key for reader expressions we can track:
+// identifiers and field selectors such as resp.Body
+func readerKey(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if base := readerKey(e.X); base != "" {
+			return base + "." + e.Sel.Name
+		}
+	}
+	return ""
+}
+
+// checkDoubleConsumption flags readers consumed more than once
+func checkDoubleConsumption(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	consumed := make(map[string]consumption)
+	var scopes []ast.Node
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Analyzed separately
+			return false
+
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			scopes = append(scopes, node)
+			ast.Inspect(node, func(child ast.Node) bool {
+				if child == node {
+					return true
+				}
+				return visit(child)
+			})
+			scopes = scopes[:len(scopes)-1]
+			return false
+
+		case *ast.AssignStmt:
+			// Reassigning a reader starts a fresh stream
+			for _, lhs := range node.Lhs {
+				delete(consumed, readerKey(lhs))
+			}
+
+		case *ast.CallExpr:
+			checkCall(reporter, pass, node, consumed, scopes)
+		}
+		return true
+	}
+
+	visit(body)
+}
+
+// checkCall records consumption or reports a second read of the same reader
+func checkCall(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr, consumed map[string]consumption, scopes []ast.Node) {
+	// r.Seek(...) rewinds the reader
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Seek" {
+		delete(consumed, readerKey(sel.X))
+		return
+	}
+
+	name, arg, c, ok := consumerOf(pass, call)
+	if !ok {
+		// Direct reads after bufio wrapping: r.Read(buf)
+		checkDirectRead(reporter, call, consumed, scopes)
+		return
+	}
+
+	key := readerKey(arg)
+	if key == "" {
+		return
+	}
+
+	if prev, seen := consumed[key]; seen && inScope(prev.scope, scopes) {
+		if prev.buffering {
+			reporter.Reportf(arg.Pos(),
+				"%s is read again after being wrapped by %s at line %d; the buffered data is lost, read through the bufio reader",
+				key, prev.call, pass.Fset.Position(prev.pos).Line)
+		} else {
+			reporter.Reportf(arg.Pos(),
+				"%s was already consumed by %s at line %d; the second read gets nothing, buffer the data once or use io.TeeReader",
+				key, prev.call, pass.Fset.Position(prev.pos).Line)
+		}
+		return
+	}
+
+	consumed[key] = consumption{
+		call:      name,
+		pos:       call.Pos(),
+		scope:     scopes[len(scopes)-1],
+		buffering: c.buffering,
+	}
+}
+
+// checkDirectRead flags r.Read(...) on a reader that has been wrapped by bufio
+func checkDirectRead(reporter *nolint.Reporter, call *ast.CallExpr, consumed map[string]consumption, scopes []ast.Node) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Read" {
+		return
+	}
+
+	key := readerKey(sel.X)
+	prev, seen := consumed[key]
+	if !seen || !prev.buffering || !inScope(prev.scope, scopes) {
+		return
+	}
+
+	reporter.Reportf(call.Pos(),
+		"%s.Read after wrapping %s in %s loses the data bufio already buffered; read through the bufio reader",
+		key, key, prev.call)
+}
+
+// inScope checks if scope is on the current scope stack, i.e. the earlier
+// consumption happened on every path to the current one (not in a sibling branch)
+func inScope(scope ast.Node, scopes []ast.Node) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIgnoredReadCount flags r.Read(buf) calls whose byte count is discarded
+func checkIgnoredReadCount(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		var call *ast.CallExpr
+
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ExprStmt:
+			call, _ = node.X.(*ast.CallExpr)
+		case *ast.AssignStmt:
+			if len(node.Lhs) != 2 || len(node.Rhs) != 1 {
+				return true
+			}
+			if ident, ok := node.Lhs[0].(*ast.Ident); !ok || ident.Name != "_" {
+				return true
+			}
+			call, _ = node.Rhs[0].(*ast.CallExpr)
+		}
+
+		if call != nil && isReadMethod(pass, call) {
+			reporter.Reportf(call.Pos(),
+				"Read byte count is ignored; Read may fill only part of the buffer, use io.ReadFull")
+		}
+		return true
+	})
+}
+
+// isReadMethod checks for a Read(p []byte) (n int, err error) method call
+func isReadMethod(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Read" || len(call.Args) != 1 {
+		return false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil || sig.Params().Len() != 1 || sig.Results().Len() != 2 {
+		return false
+	}
+
+	slice, ok := sig.Params().At(0).Type().(*types.Slice)
+	if !ok {
+		return false
+	}
+	elem, ok := slice.Elem().(*types.Basic)
+	return ok && elem.Kind() == types.Byte
+}
@@ -0,0 +1,14 @@
+package readadoption_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/readadoption"
+)
+
+func TestReadAdoptionAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, readadoption.Analyzer, "a")
+}
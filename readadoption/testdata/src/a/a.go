@@ -0,0 +1,83 @@
+package a
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+)
+
+type Payload struct{}
+
+// Bad: body decoded then ReadAll'd
+func DecodeTwice(resp *http.Response) ([]byte, error) {
+	var p Payload
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body) // want `resp.Body was already consumed by json.NewDecoder at line 17`
+}
+
+// Good: tee'd reader
+func DecodeAndKeep(resp *http.Response) ([]byte, error) {
+	var buf bytes.Buffer
+	var p Payload
+	if err := json.NewDecoder(io.TeeReader(resp.Body, &buf)).Decode(&p); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(&buf)
+}
+
+// Good: consumed in different branches
+func DecodeOrRead(resp *http.Response, raw bool) ([]byte, error) {
+	if raw {
+		return io.ReadAll(resp.Body)
+	}
+	var p Payload
+	err := json.NewDecoder(resp.Body).Decode(&p)
+	return nil, err
+}
+
+// Good: rewound before the second read
+func ReadTwice(r *bytes.Reader) error {
+	if _, err := io.ReadAll(r); err != nil {
+		return err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.ReadAll(r)
+	return err
+}
+
+// Bad: reading underneath bufio
+func Handshake(conn net.Conn) error {
+	br := bufio.NewReader(conn)
+	if _, err := br.ReadString('\n'); err != nil {
+		return err
+	}
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(conn, buf)
+	if err != nil {
+		return err
+	}
+	n, err := conn.Read(buf) // want `conn.Read after wrapping conn in bufio.NewReader loses the data bufio already buffered`
+	_ = n
+	return err
+}
+
+// Bad: ignored byte count
+func Header(r io.Reader) ([]byte, error) {
+	buf := make([]byte, 16)
+	_, err := r.Read(buf) // want `Read byte count is ignored`
+	return buf, err
+}
+
+// Good: io.ReadFull
+func HeaderFull(r io.Reader) ([]byte, error) {
+	buf := make([]byte, 16)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
@@ -16,6 +16,7 @@ import (
 	"golang.org/x/tools/go/ast/inspector"
 
 	"github.com/spechtlabs/golint-sl/internal/nolint"
+	"github.com/spechtlabs/golint-sl/internal/reconcile"
 )
 
 const Doc = `enforce Kubernetes reconciler best practices
@@ -26,16 +27,27 @@ This analyzer ensures reconcilers:
 3. Don't access global state
 4. Use proper logging patterns with structured fields
 5. Handle not-found errors correctly (don't requeue)
+6. Handle finalizers completely: check the deletion timestamp, add the
+   finalizer, and remove it once cleanup is done
+
+Mutex use is reported only for locks on receiver fields or package-level
+variables, and only controller-runtime client.Get calls need a not-found
+check.
 
 These patterns ensure reliable, idempotent reconciliation.`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "reconciler",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+const (
+	clientPkg         = "sigs.k8s.io/controller-runtime/pkg/client"
+	controllerutilPkg = "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
 // ReconcileFunc tracks information about a Reconcile function
 type ReconcileFunc struct {
 	Decl          *ast.FuncDecl
@@ -58,7 +70,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
-		if !isReconcileFunction(fn) {
+		if !reconcile.IsMethod(fn) {
 			return
 		}
 
@@ -66,10 +78,13 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		checkReconcileSignature(reporter, fn)
 
 		// Check for forbidden patterns in reconciler
-		checkReconcilerBody(reporter, fn)
+		checkReconcilerBody(reporter, pass, fn)
 
 		// Check error handling patterns
-		checkErrorHandling(reporter, fn)
+		checkErrorHandling(reporter, pass, fn)
+
+		// Check finalizer handling
+		checkFinalizers(reporter, pass, fn)
 
 		// Check for proper logging
 		checkLoggingPatterns(reporter, fn)
@@ -78,37 +93,6 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	return nil, nil
 }
 
-// isReconcileFunction checks if a function is a Kubernetes reconciler
-// Only returns true for the actual Reconcile method, not helper methods
-func isReconcileFunction(fn *ast.FuncDecl) bool {
-	if fn.Name == nil {
-		return false
-	}
-
-	// Only check functions named exactly "Reconcile"
-	if fn.Name.Name != "Reconcile" {
-		return false
-	}
-
-	// Must have a receiver (it's a method)
-	if fn.Recv == nil || len(fn.Recv.List) == 0 {
-		return false
-	}
-
-	// Verify the receiver type looks like a reconciler/controller/operator
-	recv := fn.Recv.List[0]
-	recvType := types.ExprString(recv.Type)
-
-	patterns := []string{"Reconciler", "Controller", "Operator"}
-	for _, pattern := range patterns {
-		if strings.Contains(recvType, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // checkReconcileSignature verifies the Reconcile function has correct signature
 func checkReconcileSignature(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	if fn.Type.Results == nil {
@@ -149,11 +133,16 @@ func checkReconcileSignature(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 }
 
 // checkReconcilerBody looks for forbidden patterns in reconciler body
-func checkReconcilerBody(reporter *nolint.Reporter, fn *ast.FuncDecl) {
+func checkReconcilerBody(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
 	if fn.Body == nil {
 		return
 	}
 
+	var recv types.Object
+	if names := fn.Recv.List[0].Names; len(names) > 0 {
+		recv = pass.TypesInfo.Defs[names[0]]
+	}
+
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
 		if !ok {
@@ -162,7 +151,7 @@ func checkReconcilerBody(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 
 		checkForbiddenCalls(reporter, call)
 		checkTimeNow(reporter, call)
-		checkGlobalAccess(reporter, call)
+		checkGlobalAccess(reporter, pass, call, recv)
 
 		return true
 	})
@@ -233,22 +222,71 @@ func checkTimeNow(reporter *nolint.Reporter, call *ast.CallExpr) {
 }
 
 // checkGlobalAccess looks for global variable access
-func checkGlobalAccess(reporter *nolint.Reporter, call *ast.CallExpr) {
-	// Check for sync.Mutex Lock/Unlock on package-level variables
+func checkGlobalAccess(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr, recv types.Object) {
+	// Check for sync.Mutex Lock/Unlock on receiver fields or package-level variables
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
 	}
 
-	if sel.Sel.Name == "Lock" || sel.Sel.Name == "Unlock" {
-		// This could indicate shared state
-		reporter.Reportf(call.Pos(),
-			"reconciler using mutex may indicate shared state; consider using controller-runtime's built-in concurrency model")
+	if sel.Sel.Name != "Lock" && sel.Sel.Name != "Unlock" {
+		return
+	}
+
+	method, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || method.Pkg() == nil || method.Pkg().Path() != "sync" {
+		return
+	}
+
+	// A mutex on a local value only guards that value
+	if !isSharedState(pass, sel.X, recv) {
+		return
+	}
+
+	// This could indicate shared state
+	reporter.Reportf(call.Pos(),
+		"reconciler using mutex may indicate shared state; consider using controller-runtime's built-in concurrency model")
+}
+
+// isSharedState checks if expr is rooted at the receiver or at a
+// package-level variable
+func isSharedState(pass *analysis.Pass, expr ast.Expr, recv types.Object) bool {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.UnaryExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			// pkg.Var refers to a package-level variable of another package
+			if ident, ok := e.X.(*ast.Ident); ok {
+				if _, ok := pass.TypesInfo.Uses[ident].(*types.PkgName); ok {
+					return true
+				}
+			}
+			expr = e.X
+		case *ast.Ident:
+			obj := pass.TypesInfo.Uses[e]
+			if obj == nil {
+				return false
+			}
+			if recv != nil && obj == recv {
+				return true
+			}
+			v, ok := obj.(*types.Var)
+			return ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+		default:
+			return false
+		}
 	}
 }
 
 // checkErrorHandling ensures proper error handling patterns
-func checkErrorHandling(reporter *nolint.Reporter, fn *ast.FuncDecl) {
+func checkErrorHandling(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
 	if fn.Body == nil {
 		return
 	}
@@ -287,11 +325,8 @@ func checkErrorHandling(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 			return true
 		}
 
-		if sel.Sel.Name == "Get" {
-			// Check if it's a client call (has context as first arg)
-			if len(call.Args) >= 2 {
-				hasClientGet = true
-			}
+		if sel.Sel.Name == "Get" && isClientGet(pass, sel, call) {
+			hasClientGet = true
 		}
 
 		return true
@@ -303,6 +338,77 @@ func checkErrorHandling(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	}
 }
 
+// isClientGet checks if a Get call goes to a controller-runtime client, either
+// by the package declaring the method or by a NamespacedName key argument
+func isClientGet(pass *analysis.Pass, sel *ast.SelectorExpr, call *ast.CallExpr) bool {
+	if len(call.Args) < 2 {
+		return false
+	}
+
+	if method, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func); ok && method.Pkg() != nil &&
+		method.Pkg().Path() == clientPkg {
+		return true
+	}
+
+	named, ok := types.Unalias(pass.TypesInfo.TypeOf(call.Args[1])).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "NamespacedName" && obj.Pkg() != nil && obj.Pkg().Path() == "k8s.io/apimachinery/pkg/types"
+}
+
+// checkFinalizers reports incomplete finalizer handling: deletion checks
+// without adding or removing a finalizer, finalizer calls without a deletion
+// check, and finalizers that are added but never removed
+func checkFinalizers(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	if fn.Body == nil {
+		return
+	}
+
+	checksDeletion := false
+	usesFinalizerConst := false
+	adds, removes := false, false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if node.Sel.Name == "GetDeletionTimestamp" || node.Sel.Name == "DeletionTimestamp" {
+				checksDeletion = true
+			}
+			if fun, ok := pass.TypesInfo.Uses[node.Sel].(*types.Func); ok && fun.Pkg() != nil &&
+				fun.Pkg().Path() == controllerutilPkg {
+				switch fun.Name() {
+				case "AddFinalizer":
+					adds = true
+				case "RemoveFinalizer":
+					removes = true
+				}
+			}
+		case *ast.Ident:
+			if c, ok := pass.TypesInfo.Uses[node].(*types.Const); ok &&
+				strings.Contains(strings.ToLower(c.Name()), "finalizer") {
+				usesFinalizerConst = true
+			}
+		}
+		return true
+	})
+
+	switch {
+	case (checksDeletion || usesFinalizerConst) && !adds && !removes:
+		reporter.Reportf(fn.Pos(),
+			"incomplete finalizer handling: Reconcile handles deletion but never calls controllerutil.AddFinalizer or RemoveFinalizer; "+
+				"without a finalizer the object can be gone before cleanup runs")
+	case (adds || removes) && !checksDeletion:
+		reporter.Reportf(fn.Pos(),
+			"incomplete finalizer handling: Reconcile manages a finalizer but never checks GetDeletionTimestamp(); "+
+				"run cleanup and remove the finalizer only when the object is being deleted")
+	case adds && !removes:
+		reporter.Reportf(fn.Pos(),
+			"incomplete finalizer handling: Reconcile adds a finalizer but never calls controllerutil.RemoveFinalizer; "+
+				"deleted objects will hang in Terminating")
+	}
+}
+
 // checkLoggingPatterns ensures structured logging is used
 func checkLoggingPatterns(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	if fn.Body == nil {
@@ -0,0 +1,29 @@
+package reconciler_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/reconciler"
+)
+
+func TestReconcilerNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, reconciler.Analyzer, "nolint")
+}
+
+func TestReconcilerMutexes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, reconciler.Analyzer, "mutexes")
+}
+
+func TestReconcilerClientGet(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, reconciler.Analyzer, "clientget")
+}
+
+func TestReconcilerFinalizers(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, reconciler.Analyzer, "finalizers")
+}
@@ -0,0 +1,74 @@
+package clientget
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Request struct {
+	NamespacedName types.NamespacedName
+}
+
+type Result struct{}
+
+type cache struct{}
+
+func (c *cache) Get(ctx context.Context, key string) (string, bool) { return "", false }
+
+type keyedStore interface {
+	Get(ctx context.Context, key types.NamespacedName) error
+}
+
+type ClientReconciler struct {
+	client.Client
+}
+
+func (r *ClientReconciler) Reconcile(ctx context.Context, req Request) (Result, error) { // want `reconciler does client.Get but doesn't check for IsNotFound`
+	var obj client.Object
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return Result{}, err
+	}
+	return Result{}, nil
+}
+
+type HandledReconciler struct {
+	client.Client
+}
+
+func (r *HandledReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	var obj client.Object
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return Result{}, nil
+		}
+		return Result{}, err
+	}
+	return Result{}, nil
+}
+
+// Any Get taking a NamespacedName reads from the API server
+type StoreReconciler struct {
+	store keyedStore
+}
+
+func (r *StoreReconciler) Reconcile(ctx context.Context, req Request) (Result, error) { // want `reconciler does client.Get but doesn't check for IsNotFound`
+	if err := r.store.Get(ctx, req.NamespacedName); err != nil {
+		return Result{}, err
+	}
+	return Result{}, nil
+}
+
+// Map-like and cache lookups are not client.Get
+type CacheReconciler struct {
+	cache *cache
+}
+
+func (r *CacheReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	if _, ok := r.cache.Get(ctx, req.NamespacedName.Name); ok {
+		return Result{}, nil
+	}
+	return Result{}, nil
+}
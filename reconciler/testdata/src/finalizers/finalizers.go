@@ -0,0 +1,79 @@
+package finalizers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const widgetFinalizer = "example.com/widget"
+
+type Request struct{}
+
+type Result struct{}
+
+func cleanup(ctx context.Context, obj client.Object) error { return nil }
+
+// Complete: checks deletion, adds the finalizer, and removes it after cleanup
+type CompleteReconciler struct {
+	obj client.Object
+}
+
+func (r *CompleteReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	if !r.obj.GetDeletionTimestamp().IsZero() {
+		if err := cleanup(ctx, r.obj); err != nil {
+			return Result{}, err
+		}
+		controllerutil.RemoveFinalizer(r.obj, widgetFinalizer)
+		return Result{}, nil
+	}
+	controllerutil.AddFinalizer(r.obj, widgetFinalizer)
+	return Result{}, nil
+}
+
+type DeletionOnlyReconciler struct {
+	obj client.Object
+}
+
+func (r *DeletionOnlyReconciler) Reconcile(ctx context.Context, req Request) (Result, error) { // want `incomplete finalizer handling: Reconcile handles deletion but never calls controllerutil.AddFinalizer or RemoveFinalizer`
+	if !r.obj.GetDeletionTimestamp().IsZero() {
+		return Result{}, cleanup(ctx, r.obj)
+	}
+	return Result{}, nil
+}
+
+type ConstOnlyReconciler struct {
+	obj client.Object
+}
+
+func (r *ConstOnlyReconciler) Reconcile(ctx context.Context, req Request) (Result, error) { // want `incomplete finalizer handling: Reconcile handles deletion but never calls`
+	if controllerutil.ContainsFinalizer(r.obj, widgetFinalizer) {
+		return Result{}, cleanup(ctx, r.obj)
+	}
+	return Result{}, nil
+}
+
+type NoDeletionCheckReconciler struct {
+	obj client.Object
+}
+
+func (r *NoDeletionCheckReconciler) Reconcile(ctx context.Context, req Request) (Result, error) { // want `incomplete finalizer handling: Reconcile manages a finalizer but never checks GetDeletionTimestamp\(\)`
+	if err := cleanup(ctx, r.obj); err != nil {
+		return Result{}, err
+	}
+	controllerutil.RemoveFinalizer(r.obj, widgetFinalizer)
+	return Result{}, nil
+}
+
+type NeverRemovedReconciler struct {
+	obj client.Object
+}
+
+func (r *NeverRemovedReconciler) Reconcile(ctx context.Context, req Request) (Result, error) { // want `incomplete finalizer handling: Reconcile adds a finalizer but never calls controllerutil.RemoveFinalizer`
+	if !r.obj.GetDeletionTimestamp().IsZero() {
+		return Result{}, cleanup(ctx, r.obj)
+	}
+	controllerutil.AddFinalizer(r.obj, widgetFinalizer)
+	return Result{}, nil
+}
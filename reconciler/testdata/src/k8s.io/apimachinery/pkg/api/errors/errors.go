@@ -0,0 +1,3 @@
+package errors
+
+func IsNotFound(err error) bool { return false }
@@ -0,0 +1,6 @@
+package types
+
+type NamespacedName struct {
+	Namespace string
+	Name      string
+}
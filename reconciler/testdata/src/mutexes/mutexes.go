@@ -0,0 +1,76 @@
+package mutexes
+
+import (
+	"context"
+	"sync"
+)
+
+type Request struct{}
+
+type Result struct{}
+
+var cacheMu sync.Mutex
+
+type batch struct {
+	mu    sync.Mutex
+	items []string
+}
+
+type FieldReconciler struct {
+	mu sync.Mutex
+}
+
+func (r *FieldReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	r.mu.Lock()   // want `reconciler using mutex may indicate shared state`
+	r.mu.Unlock() // want `reconciler using mutex may indicate shared state`
+	return Result{}, nil
+}
+
+type GlobalReconciler struct{}
+
+func (r *GlobalReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	cacheMu.Lock()         // want `reconciler using mutex may indicate shared state`
+	defer cacheMu.Unlock() // want `reconciler using mutex may indicate shared state`
+	return Result{}, nil
+}
+
+type EmbeddedReconciler struct {
+	sync.Mutex
+}
+
+func (r *EmbeddedReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	r.Lock()         // want `reconciler using mutex may indicate shared state`
+	defer r.Unlock() // want `reconciler using mutex may indicate shared state`
+	return Result{}, nil
+}
+
+// A mutex on a short-lived local value guards nothing shared
+type LocalReconciler struct{}
+
+func (r *LocalReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	b := &batch{}
+	b.mu.Lock()
+	b.items = append(b.items, "a")
+	b.mu.Unlock()
+
+	var mu sync.Mutex
+	mu.Lock()
+	mu.Unlock()
+	return Result{}, nil
+}
+
+type lease struct{}
+
+func (l *lease) Lock()   {}
+func (l *lease) Unlock() {}
+
+// Lock and Unlock that are not sync methods are not mutexes
+type LeaseReconciler struct {
+	lease *lease
+}
+
+func (r *LeaseReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	r.lease.Lock()
+	r.lease.Unlock()
+	return Result{}, nil
+}
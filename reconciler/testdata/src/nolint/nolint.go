@@ -0,0 +1,15 @@
+package nolint
+
+type FooReconciler struct{}
+
+// Reported: Reconcile without results
+func (r *FooReconciler) Reconcile() { // want `Reconcile function must return \(reconcile.Result, error\)`
+}
+
+type BarReconciler struct{}
+
+// Suppressed on the line above
+//
+//nolint:reconciler // adapter for a legacy controller interface
+func (r *BarReconciler) Reconcile() {
+}
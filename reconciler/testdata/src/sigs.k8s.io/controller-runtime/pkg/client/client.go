@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type Object interface {
+	GetDeletionTimestamp() *Time
+	GetFinalizers() []string
+}
+
+type Time struct{}
+
+func (t *Time) IsZero() bool { return t == nil }
+
+type ObjectKey = types.NamespacedName
+
+type Client interface {
+	Get(ctx context.Context, key ObjectKey, obj Object) error
+	Update(ctx context.Context, obj Object) error
+}
+
+func IgnoreNotFound(err error) error { return err }
@@ -0,0 +1,346 @@
+// Package requeueresult provides an analyzer that detects controller-runtime
+// reconcile.Result values that do not requeue the way the reconciler
+// intends.
+//
+// The mistakes compile and look reasonable in review: controller-runtime
+// ignores the Result when Reconcile also returns an error, a RequeueAfter
+// of zero silently means "do not requeue", and Requeue: true retries
+// through the rate limiter without a delay chosen for the resource.
+package requeueresult
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+	"github.com/spechtlabs/golint-sl/internal/reconcile"
+)
+
+const Doc = `detect controller-runtime Result values that requeue incorrectly
+
+Inside Reconcile methods, this analyzer reports:
+1. Returning a Result that requeues together with a non-nil error;
+   controller-runtime ignores the Result and logs a warning
+2. RequeueAfter: 0, or RequeueAfter set from a variable that may be zero;
+   a zero RequeueAfter does not requeue at all
+3. Requeue: true without RequeueAfter; use RequeueAfter with a backoff so
+   the delay suits the resource
+
+A RequeueAfter variable is trusted when an if condition in Reconcile
+compares it, or when every assignment to it is a non-zero constant.
+Requeue: true is allowed after an IsConflict check, where an immediate
+rate-limited retry is what is wanted.
+
+Bad:
+    if err := r.sync(ctx, obj); err != nil {
+        return ctrl.Result{Requeue: true}, err // Requeue is ignored
+    }
+    return ctrl.Result{RequeueAfter: obj.Spec.Interval.Duration}, nil
+
+Good:
+    if err := r.sync(ctx, obj); err != nil {
+        return ctrl.Result{}, err // retried with backoff
+    }
+    if obj.Spec.Interval.Duration <= 0 {
+        return ctrl.Result{RequeueAfter: defaultInterval}, nil
+    }
+    return ctrl.Result{RequeueAfter: obj.Spec.Interval.Duration}, nil`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "requeueresult",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// reconcilePkg declares Result; ctrl.Result is an alias of it
+const reconcilePkg = "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CompositeLit)(nil),
+	}
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		lit := n.(*ast.CompositeLit)
+		if !isResult(pass, lit) {
+			return true
+		}
+
+		fn := enclosingReconcile(stack)
+		if fn == nil {
+			return true
+		}
+
+		checkResult(reporter, pass, fn, lit, stack)
+		return true
+	})
+
+	return nil, nil
+}
+
+// enclosingReconcile returns the Reconcile method the innermost function of
+// stack is, or nil if it is another function or a closure
+func enclosingReconcile(stack []ast.Node) *ast.FuncDecl {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.FuncLit:
+			return nil
+		case *ast.FuncDecl:
+			if node.Body == nil || !reconcile.IsMethod(node) {
+				return nil
+			}
+			return node
+		}
+	}
+	return nil
+}
+
+// isResult checks if lit is a reconcile.Result literal
+func isResult(pass *analysis.Pass, lit *ast.CompositeLit) bool {
+	named, ok := types.Unalias(pass.TypesInfo.TypeOf(lit)).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "Result" && obj.Pkg() != nil && obj.Pkg().Path() == reconcilePkg
+}
+
+// resultFields holds the values of the requeue fields of a Result literal
+type resultFields struct {
+	requeue      ast.Expr
+	requeueAfter ast.Expr
+}
+
+func fieldsOf(lit *ast.CompositeLit) resultFields {
+	var fields resultFields
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Requeue":
+			fields.requeue = kv.Value
+		case "RequeueAfter":
+			fields.requeueAfter = kv.Value
+		}
+	}
+	return fields
+}
+
+func checkResult(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, lit *ast.CompositeLit, stack []ast.Node) {
+	fields := fieldsOf(lit)
+
+	if requeues(pass, fields) && returnedWithError(pass, lit, stack) {
+		reporter.Reportf(lit.Pos(),
+			"%s is ignored when Reconcile returns a non-nil error; return ctrl.Result{}, err to retry with backoff, or the Result with a nil error",
+			render(lit, fields))
+		return
+	}
+
+	if fields.requeueAfter != nil {
+		checkRequeueAfter(reporter, pass, fn, fields.requeueAfter)
+	}
+
+	if fields.requeueAfter == nil && isConstant(pass, fields.requeue, constant.MakeBool(true)) && !afterConflict(stack) {
+		reporter.Reportf(lit.Pos(),
+			"Result{Requeue: true} retries without a delay chosen for the resource; use RequeueAfter with a backoff instead")
+	}
+}
+
+// render prints lit with only its requeue fields, like ctrl.Result{Requeue: true}
+func render(lit *ast.CompositeLit, fields resultFields) string {
+	var elts []string
+	if fields.requeue != nil {
+		elts = append(elts, "Requeue: "+types.ExprString(fields.requeue))
+	}
+	if fields.requeueAfter != nil {
+		elts = append(elts, "RequeueAfter: "+types.ExprString(fields.requeueAfter))
+	}
+	return types.ExprString(lit.Type) + "{" + strings.Join(elts, ", ") + "}"
+}
+
+// requeues checks if the fields may ask for a requeue: Requeue that is not
+// constant false, or RequeueAfter that is not constant zero
+func requeues(pass *analysis.Pass, fields resultFields) bool {
+	if fields.requeue != nil && !isConstant(pass, fields.requeue, constant.MakeBool(false)) {
+		return true
+	}
+	return fields.requeueAfter != nil && !isConstant(pass, fields.requeueAfter, constant.MakeInt64(0))
+}
+
+// returnedWithError checks if lit is the Result of a return statement whose
+// error is not nil
+func returnedWithError(pass *analysis.Pass, lit *ast.CompositeLit, stack []ast.Node) bool {
+	if len(stack) < 2 {
+		return false
+	}
+	ret, ok := stack[len(stack)-2].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 2 || ret.Results[0] != lit {
+		return false
+	}
+	tv, ok := pass.TypesInfo.Types[ret.Results[1]]
+	return !ok || !tv.IsNil()
+}
+
+func checkRequeueAfter(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, value ast.Expr) {
+	if isConstant(pass, value, constant.MakeInt64(0)) {
+		reporter.Reportf(value.Pos(),
+			"RequeueAfter: 0 does not requeue; use a positive duration, or return ctrl.Result{} to stop requeueing")
+		return
+	}
+
+	// Only variables and fields are checked; calls like backoff.Next() and
+	// arithmetic are trusted
+	value = ast.Unparen(value)
+	var obj types.Object
+	switch v := value.(type) {
+	case *ast.Ident:
+		obj = pass.TypesInfo.Uses[v]
+	case *ast.SelectorExpr:
+		obj = pass.TypesInfo.Uses[v.Sel]
+	}
+	if _, ok := obj.(*types.Var); !ok {
+		return
+	}
+
+	if comparedInCondition(fn.Body, value) || assignedNonZero(pass, fn.Body, obj) {
+		return
+	}
+
+	reporter.Reportf(value.Pos(),
+		"RequeueAfter: %s may be zero, which does not requeue; check that it is positive first",
+		types.ExprString(value))
+}
+
+// comparedInCondition checks if an if condition in body compares value
+func comparedInCondition(body *ast.BlockStmt, value ast.Expr) bool {
+	want := types.ExprString(value)
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok || found {
+			return !found
+		}
+		ast.Inspect(ifStmt.Cond, func(c ast.Node) bool {
+			bin, ok := c.(*ast.BinaryExpr)
+			if !ok {
+				return true
+			}
+			switch bin.Op {
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+				if types.ExprString(ast.Unparen(bin.X)) == want || types.ExprString(ast.Unparen(bin.Y)) == want {
+					found = true
+				}
+			}
+			return !found
+		})
+		return !found
+	})
+	return found
+}
+
+// assignedNonZero checks if obj is a local variable of body and every
+// assignment to it is a non-zero constant
+func assignedNonZero(pass *analysis.Pass, body *ast.BlockStmt, obj types.Object) bool {
+	if obj.Pos() < body.Pos() || obj.Pos() >= body.End() {
+		return false
+	}
+
+	assigned, nonZero := false, true
+	record := func(lhs *ast.Ident, rhs ast.Expr) {
+		if pass.TypesInfo.ObjectOf(lhs) != obj {
+			return
+		}
+		assigned = true
+		tv, ok := pass.TypesInfo.Types[rhs]
+		if rhs == nil || !ok || tv.Value == nil || constant.Sign(tv.Value) == 0 {
+			nonZero = false
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				var rhs ast.Expr
+				if len(node.Rhs) == len(node.Lhs) && node.Tok != token.ADD_ASSIGN && node.Tok != token.SUB_ASSIGN {
+					rhs = node.Rhs[i]
+				}
+				record(ident, rhs)
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				var rhs ast.Expr
+				if len(node.Values) == len(node.Names) {
+					rhs = node.Values[i]
+				}
+				record(name, rhs)
+			}
+		case *ast.UnaryExpr:
+			// &delay lets anything change it
+			if ident, ok := node.X.(*ast.Ident); ok && node.Op == token.AND && pass.TypesInfo.ObjectOf(ident) == obj {
+				nonZero = false
+			}
+		}
+		return true
+	})
+
+	return assigned && nonZero
+}
+
+// afterConflict checks if stack is inside an if statement whose condition
+// checks for a conflict error
+func afterConflict(stack []ast.Node) bool {
+	for _, node := range stack {
+		ifStmt, ok := node.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		conflict := false
+		ast.Inspect(ifStmt.Cond, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && strings.HasSuffix(types.ExprString(call.Fun), "IsConflict") {
+				conflict = true
+			}
+			return !conflict
+		})
+		if conflict {
+			return true
+		}
+	}
+	return false
+}
+
+// isConstant checks if expr is a constant equal to want
+func isConstant(pass *analysis.Pass, expr ast.Expr, want constant.Value) bool {
+	if expr == nil {
+		return false
+	}
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != want.Kind() {
+		return false
+	}
+	return constant.Compare(tv.Value, token.EQL, want)
+}
@@ -0,0 +1,14 @@
+package requeueresult_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/requeueresult"
+)
+
+func TestRequeueResult(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, requeueresult.Analyzer, "results")
+}
@@ -0,0 +1,132 @@
+package results
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const defaultInterval = 5 * time.Minute
+
+type Widget struct {
+	Spec WidgetSpec
+}
+
+type WidgetSpec struct {
+	Interval time.Duration
+}
+
+// CleanReconciler uses Result correctly
+type CleanReconciler struct {
+	client.Client
+	backoff func() time.Duration
+}
+
+func (r *CleanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var w Widget
+	if err := r.Get(ctx, req.NamespacedName, &w); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if err := r.Update(ctx, &w); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if w.Spec.Interval == 0 {
+		return ctrl.Result{RequeueAfter: r.backoff()}, nil
+	}
+	if w.Spec.Interval < time.Minute {
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	delay := defaultInterval
+	if req.NamespacedName.Namespace == "kube-system" {
+		delay = 10 * time.Minute
+	}
+	if req.NamespacedName.Name == "" {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: w.Spec.Interval}, nil
+}
+
+// ErrorReconciler returns a Result with an error
+type ErrorReconciler struct {
+	client.Client
+}
+
+func (r *ErrorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var w Widget
+	if err := r.Get(ctx, req.NamespacedName, &w); err != nil {
+		return ctrl.Result{Requeue: true}, err // want `ctrl.Result\{Requeue: true\} is ignored when Reconcile returns a non-nil error`
+	}
+	if err := r.Update(ctx, &w); err != nil {
+		return reconcile.Result{RequeueAfter: time.Minute}, err // want `reconcile.Result\{RequeueAfter: time.Minute\} is ignored when Reconcile returns a non-nil error`
+	}
+	return ctrl.Result{Requeue: false}, nil
+}
+
+// ZeroReconciler requeues after a duration that may be zero
+type ZeroReconciler struct {
+	client.Client
+	interval time.Duration
+}
+
+func (r *ZeroReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var w Widget
+	if err := r.Get(ctx, req.NamespacedName, &w); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if req.NamespacedName.Name == "paused" {
+		return ctrl.Result{RequeueAfter: 0}, nil // want `RequeueAfter: 0 does not requeue`
+	}
+
+	if req.NamespacedName.Name == "config" {
+		return ctrl.Result{RequeueAfter: r.interval}, nil // want `RequeueAfter: r.interval may be zero, which does not requeue`
+	}
+
+	var delay time.Duration
+	if req.NamespacedName.Namespace == "kube-system" {
+		delay = time.Minute
+	}
+	return ctrl.Result{RequeueAfter: delay}, nil // want `RequeueAfter: delay may be zero, which does not requeue`
+}
+
+// TightReconciler requeues without a delay
+type TightReconciler struct {
+	client.Client
+}
+
+func (r *TightReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var w Widget
+	if err := r.Get(ctx, req.NamespacedName, &w); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if w.Spec.Interval > 0 {
+		return ctrl.Result{Requeue: true}, nil // want `Result\{Requeue: true\} retries without a delay chosen for the resource`
+	}
+
+	result := ctrl.Result{Requeue: true} // want `Result\{Requeue: true\} retries without a delay chosen for the resource`
+	return result, nil
+}
+
+// helper is not Reconcile, so it is not checked
+func (r *TightReconciler) helper(err error) (ctrl.Result, error) {
+	return ctrl.Result{Requeue: true}, err
+}
+
+// Store is not a reconciler
+type Store struct{}
+
+func (s *Store) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ctrl.Result{RequeueAfter: 0}, nil
+}
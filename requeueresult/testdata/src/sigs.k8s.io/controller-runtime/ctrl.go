@@ -0,0 +1,7 @@
+package ctrl
+
+import "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+type Request = reconcile.Request
+
+type Result = reconcile.Result
@@ -0,0 +1,16 @@
+package reconcile
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Request struct {
+	NamespacedName client.ObjectKey
+}
+
+type Result struct {
+	Requeue      bool
+	RequeueAfter time.Duration
+}
@@ -3,6 +3,7 @@
 package resourceclose
 
 import (
+	"flag"
 	"go/ast"
 	"go/token"
 	"go/types"
@@ -22,17 +23,48 @@ This analyzer detects:
 2. File handles not closed (file.Close())
 3. Database rows not closed (rows.Close())
 4. gRPC streams not closed
+5. Multipart files (FileHeader.Open, Request.FormFile) not closed
+
+Resources returned to the caller, directly, in a struct literal, or through
+a named result, are the caller's to close. They are still reported on error
+paths that return after opening them without closing them.
+
+Resources used by a go func() literal, captured or passed as an argument,
+must be closed inside the goroutine unless the function waits for it.
+
+Resources a method stores in a field of its receiver (s.file = f) belong
+to the receiver, which closes them later.
+
+Calls to close helpers, like closeQuietly(f) or multierr.Close(f), close
+their first argument.
 
 Unclosed resources cause memory leaks, file descriptor exhaustion,
-and connection pool starvation.`
+and connection pool starvation.
+
+Flags:
+    -close-helpers  comma-separated functions that close their first argument
+                    (default closeQuietly,CloseQuietly,multierr.Close)`
+
+// defaultCloseHelpers are the close helpers recognized without configuration
+const defaultCloseHelpers = "closeQuietly,CloseQuietly,multierr.Close"
+
+var closeHelpers string
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "resourceclose",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("resourceclose", flag.ExitOnError)
+	fs.StringVar(&closeHelpers, "close-helpers", defaultCloseHelpers,
+		"comma-separated functions that close their first argument")
+	return *fs
+}
+
 // resourcePattern defines a pattern for detecting unclosed resources
 type resourcePattern struct {
 	AssignType  string   // e.g., "*http.Response"
@@ -85,6 +117,13 @@ var patterns = []resourcePattern{
 		Message:     "gRPC connection must be closed: defer conn.Close()",
 		CreateFuncs: []string{"Dial", "DialContext", "NewClient"},
 	},
+	{
+		AssignType:  "mime/multipart.File",
+		CloseField:  "",
+		CloseCall:   "Close",
+		Message:     "multipart file must be closed: defer file.Close()",
+		CreateFuncs: []string{"Open", "FormFile"},
+	},
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
@@ -174,6 +213,15 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 	// DEBUG: uncomment to trace
 	// fmt.Printf("DEBUG: resourceVars=%v closedResources=%v\n", resourceVars, closedResources)
 
+	// Resources handed to the caller are the caller's to close
+	returnedResources := findReturnedResources(pass, fn)
+
+	// Resources stored in the receiver are the receiver's to close
+	storedResources := findStoredResources(pass, fn)
+
+	// Resources used by goroutines must be closed by them
+	goroutineResources := checkGoroutines(reporter, pass, fn, resourceVars)
+
 	// Report unclosed resources
 	for varName, info := range resourceVars {
 		closeKey := varName
@@ -181,12 +229,397 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 			closeKey = varName + "." + info.closeField
 		}
 
-		if !closedResources[closeKey] && !closedResources[varName] {
-			reporter.Reportf(info.pos, "%s", info.message)
+		if closedResources[closeKey] || closedResources[varName] || goroutineResources[varName] || storedResources[varName] {
+			continue
+		}
+
+		if returnedResources[varName] {
+			checkErrorReturns(reporter, pass, fn, varName, info)
+			continue
 		}
+
+		reporter.Reportf(info.pos, "%s", info.message)
 	}
 }
 
+// checkGoroutines reports resources that a go func() literal captures or
+// receives as an argument but never closes itself. A Close in the enclosing
+// function races with the goroutine unless the function waits for it. It
+// returns the resources handled here, closed by or reported for a goroutine,
+// so they are not reported again.
+func checkGoroutines(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, resourceVars map[string]resourceInfo) map[string]bool {
+	handled := make(map[string]bool)
+	if len(resourceVars) == 0 {
+		return handled
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		goStmt, ok := n.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+		lit, ok := goStmt.Call.Fun.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		// The name each resource has inside the goroutine
+		inside := make(map[string]string)
+		for name := range capturedNames(pass, lit) {
+			if _, ok := resourceVars[name]; ok {
+				inside[name] = name
+			}
+		}
+		params := paramNames(lit)
+		for i, arg := range goStmt.Call.Args {
+			ident, ok := arg.(*ast.Ident)
+			if !ok || i >= len(params) || params[i] == "_" {
+				continue
+			}
+			if _, ok := resourceVars[ident.Name]; ok {
+				inside[ident.Name] = params[i]
+			}
+		}
+
+		for varName, name := range inside {
+			if closesInside(lit.Body, name, resourceVars[varName].closeField) {
+				handled[varName] = true
+				continue
+			}
+			// After waiting, the enclosing function may close it; whether it
+			// does is checked with the other resources
+			if waitsAfter(fn.Body, goStmt) {
+				continue
+			}
+			handled[varName] = true
+			reporter.Reportf(goStmt.Pos(),
+				"resource %s captured by goroutine is never closed inside it; close it in the goroutine (defer %s.Close())",
+				varName, closeExpr(name, resourceVars[varName].closeField))
+		}
+		return true
+	})
+
+	return handled
+}
+
+// capturedNames returns the names of variables used in lit but declared
+// outside of it
+func capturedNames(pass *analysis.Pass, lit *ast.FuncLit) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if ok && !obj.IsField() && (obj.Pos() < lit.Pos() || obj.Pos() > lit.End()) {
+			names[ident.Name] = true
+		}
+		return true
+	})
+	return names
+}
+
+// paramNames returns the parameter names of lit in order
+func paramNames(lit *ast.FuncLit) []string {
+	var names []string
+	for _, field := range lit.Type.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, "_")
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// closesInside checks if body closes name, or its closeField, anywhere,
+// including in defers
+func closesInside(body *ast.BlockStmt, name, closeField string) bool {
+	closed := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			checkCloseCall(call, closed)
+		}
+		return true
+	})
+	return closed[name] || closed[closeExpr(name, closeField)]
+}
+
+// closeExpr returns the expression that is closed, e.g. resp.Body
+func closeExpr(name, closeField string) string {
+	if closeField == "" {
+		return name
+	}
+	return name + "." + closeField
+}
+
+// waitsAfter checks if the function calls Wait (sync.WaitGroup, errgroup)
+// after starting the goroutine, so a Close in the function happens after the
+// goroutine is done
+func waitsAfter(body *ast.BlockStmt, goStmt *ast.GoStmt) bool {
+	waits := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || call.Pos() < goStmt.End() {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Wait" {
+			waits = true
+		}
+		return !waits
+	})
+	return waits
+}
+
+// findReturnedResources finds variables returned to the caller, either
+// directly, inside a composite literal, or through a named result
+func findReturnedResources(pass *analysis.Pass, fn *ast.FuncDecl) map[string]bool {
+	returned := make(map[string]bool)
+
+	namedResults := make(map[string]bool)
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			for _, name := range field.Names {
+				namedResults[name.Name] = true
+			}
+		}
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Returns in closures don't return from fn
+			return false
+		case *ast.ReturnStmt:
+			// A bare return returns every named result
+			if len(node.Results) == 0 {
+				for name := range namedResults {
+					returned[name] = true
+				}
+			}
+			for _, result := range node.Results {
+				collectResourceNames(result, returned)
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.ASSIGN || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && namedResults[ident.Name] {
+					collectResourceNames(node.Rhs[i], returned)
+				}
+			}
+		}
+		return true
+	})
+
+	return returned
+}
+
+// findStoredResources finds variables a method assigns to a field of its
+// receiver, directly or appended to a slice field: s.file = f
+func findStoredResources(pass *analysis.Pass, fn *ast.FuncDecl) map[string]bool {
+	stored := make(map[string]bool)
+	if fn.Recv == nil || len(fn.Recv.List) == 0 || len(fn.Recv.List[0].Names) == 0 {
+		return stored
+	}
+	recv := pass.TypesInfo.Defs[fn.Recv.List[0].Names[0]]
+	if recv == nil {
+		return stored
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			sel, ok := lhs.(*ast.SelectorExpr)
+			if !ok || pass.TypesInfo.Uses[rootIdent(sel)] != recv {
+				continue
+			}
+			rhs := assign.Rhs[i]
+			if call, ok := rhs.(*ast.CallExpr); ok && isAppend(pass, call) {
+				for _, arg := range call.Args[1:] {
+					collectResourceNames(arg, stored)
+				}
+				continue
+			}
+			collectResourceNames(rhs, stored)
+		}
+		return true
+	})
+
+	return stored
+}
+
+// rootIdent returns the identifier a selector chain starts at, e.g. s in
+// s.conn.file
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// isAppend checks if call is the builtin append
+func isAppend(pass *analysis.Pass, call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	_, ok = pass.TypesInfo.Uses[ident].(*types.Builtin)
+	return ok && ident.Name == "append"
+}
+
+// collectResourceNames adds the identifiers expr hands over as a value: the
+// identifier itself or the elements of a (pointer to a) composite literal
+func collectResourceNames(expr ast.Expr, names map[string]bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		names[e.Name] = true
+	case *ast.ParenExpr:
+		collectResourceNames(e.X, names)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			collectResourceNames(e.X, names)
+		}
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			collectResourceNames(elt, names)
+		}
+	}
+}
+
+// checkErrorReturns reports error returns after a returned resource was
+// opened that neither close nor return it. The check of the error from the
+// opening call itself is skipped, since the resource is not valid there.
+func checkErrorReturns(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, varName string, info resourceInfo) {
+	if info.assign == nil {
+		return
+	}
+	openCheck := findOpenErrCheck(fn, info.assign)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if node == openCheck {
+				// Descend into the else branch and init only
+				if node.Else != nil {
+					ast.Inspect(node.Else, func(m ast.Node) bool {
+						if ret, ok := m.(*ast.ReturnStmt); ok {
+							reportLeakingReturn(reporter, pass, ret, varName, info)
+						}
+						_, isLit := m.(*ast.FuncLit)
+						return !isLit
+					})
+				}
+				return false
+			}
+		case *ast.ReturnStmt:
+			reportLeakingReturn(reporter, pass, node, varName, info)
+		}
+		return true
+	})
+}
+
+// reportLeakingReturn reports ret if it comes after the resource was opened,
+// returns a non-nil error, and does not hand the resource to the caller
+func reportLeakingReturn(reporter *nolint.Reporter, pass *analysis.Pass, ret *ast.ReturnStmt, varName string, info resourceInfo) {
+	if ret.Pos() < info.assign.End() {
+		return
+	}
+
+	names := make(map[string]bool)
+	returnsError := false
+	for _, result := range ret.Results {
+		collectResourceNames(result, names)
+		if isNonNilError(pass, result) {
+			returnsError = true
+		}
+	}
+	if names[varName] || !returnsError {
+		return
+	}
+
+	reporter.Reportf(ret.Pos(),
+		"%s is returned to the caller but leaks on this error path; close it before returning the error", varName)
+}
+
+// isNonNilError checks if expr is an error value other than nil
+func isNonNilError(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.IsNil() {
+		return false
+	}
+	errType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(tv.Type, errType)
+}
+
+// findOpenErrCheck returns the first if statement after assign that checks
+// the error assign returned, e.g. if err != nil { return nil, err }
+func findOpenErrCheck(fn *ast.FuncDecl, assign *ast.AssignStmt) *ast.IfStmt {
+	errName := ""
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && strings.Contains(strings.ToLower(ident.Name), "err") {
+			errName = ident.Name
+		}
+	}
+	if errName == "" {
+		return nil
+	}
+
+	var found *ast.IfStmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok || ifStmt.Pos() < assign.End() {
+			return true
+		}
+		if containsIdentName(ifStmt.Cond, errName) {
+			found = ifStmt
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// containsIdentName checks if expr references an identifier called name
+func containsIdentName(expr ast.Expr, name string) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
 // checkTestCleanup checks for t.Cleanup(func() { ... Close() ... }) patterns
 func checkTestCleanup(call *ast.CallExpr, closedResources map[string]bool) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
@@ -374,6 +807,7 @@ type resourceInfo struct {
 	pos        token.Pos
 	closeField string
 	message    string
+	assign     *ast.AssignStmt // the statement that opened the resource
 }
 
 // isStdioAssignment checks if the RHS is os.Stdout, os.Stderr, or os.Stdin
@@ -449,6 +883,7 @@ func checkAssignment(pass *analysis.Pass, assign *ast.AssignStmt, resourceVars m
 					pos:        assign.Pos(),
 					closeField: pattern.CloseField,
 					message:    pattern.Message,
+					assign:     assign,
 				}
 				break
 			}
@@ -489,21 +924,27 @@ func isCreateFunc(funcName string, createFuncs []string) bool {
 }
 
 func checkDefer(deferStmt *ast.DeferStmt, closedResources map[string]bool) {
-	// Handle direct defer: defer resp.Body.Close()
-	checkCloseCall(deferStmt.Call, closedResources)
-
-	// Handle defer with function literal: defer func() { resp.Body.Close() }()
-	if call, ok := deferStmt.Call.Fun.(*ast.FuncLit); ok {
-		ast.Inspect(call.Body, func(n ast.Node) bool {
-			if callExpr, ok := n.(*ast.CallExpr); ok {
-				checkCloseCall(callExpr, closedResources)
-			}
-			return true
-		})
-	}
+	// Handles direct defers (defer resp.Body.Close()), helpers in the
+	// arguments (defer multierr.AppendInvoke(&err, multierr.Close(f))), and
+	// function literals, including if init clauses in them:
+	// defer func() { if cerr := f.Close(); cerr != nil { ... } }()
+	ast.Inspect(deferStmt.Call, func(n ast.Node) bool {
+		if callExpr, ok := n.(*ast.CallExpr); ok {
+			checkCloseCall(callExpr, closedResources)
+		}
+		return true
+	})
 }
 
 func checkCloseCall(call *ast.CallExpr, closedResources map[string]bool) {
+	// Close helpers close their first argument: closeQuietly(f)
+	if isCloseHelper(call) && len(call.Args) > 0 {
+		if target := exprToString(call.Args[0]); target != "" {
+			closedResources[target] = true
+		}
+		return
+	}
+
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -520,6 +961,27 @@ func checkCloseCall(call *ast.CallExpr, closedResources map[string]bool) {
 	}
 }
 
+// isCloseHelper checks if call calls one of the -close-helpers. Entries
+// match the called expression, like multierr.Close, and unqualified entries
+// also match the function name alone.
+func isCloseHelper(call *ast.CallExpr) bool {
+	called := exprToString(call.Fun)
+	if called == "" {
+		return false
+	}
+	name := called[strings.LastIndex(called, ".")+1:]
+	for _, helper := range strings.Split(closeHelpers, ",") {
+		helper = strings.TrimSpace(helper)
+		if helper == "" {
+			continue
+		}
+		if helper == called || (!strings.Contains(helper, ".") && helper == name) {
+			return true
+		}
+	}
+	return false
+}
+
 func exprToString(expr ast.Expr) string {
 	switch e := expr.(type) {
 	case *ast.Ident:
@@ -0,0 +1,31 @@
+package resourceclose_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/resourceclose"
+)
+
+func TestResourceCloseAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, resourceclose.Analyzer, "a")
+}
+
+func TestResourceCloseOwnership(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, resourceclose.Analyzer, "owned")
+}
+
+func TestResourceCloseHelpers(t *testing.T) {
+	if err := resourceclose.Analyzer.Flags.Set("close-helpers", "release"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = resourceclose.Analyzer.Flags.Set("close-helpers", "closeQuietly,CloseQuietly,multierr.Close")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, resourceclose.Analyzer, "helpers")
+}
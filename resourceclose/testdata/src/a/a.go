@@ -0,0 +1,194 @@
+package a
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// Bad: opened and never closed
+func Read(path string) error {
+	f, err := os.Open(path) // want `file must be closed`
+	if err != nil {
+		return err
+	}
+	_ = f.Name()
+	return nil
+}
+
+// Good: closed with defer
+func ReadClosed(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_ = f.Name()
+	return nil
+}
+
+// Good: returned to the caller
+func OpenData(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type Store struct {
+	file *os.File
+	name string
+}
+
+// Good: returned inside a struct literal
+func OpenStore(path string) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{file: f, name: path}, nil
+}
+
+// Good: assigned to a named result
+func OpenNamed(path string) (f *os.File, err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	f = file
+	return f, nil
+}
+
+// Good: opened into a named result and returned with a bare return
+func OpenBare(path string) (f *os.File, err error) {
+	f, err = os.Open(path)
+	return
+}
+
+var errEmpty = errors.New("empty file")
+
+// Bad: returned, but leaks when validation fails
+func OpenChecked(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err // want `f is returned to the caller but leaks on this error path`
+	}
+	if info.Size() == 0 {
+		return nil, errEmpty // want `f is returned to the caller but leaks on this error path`
+	}
+	return f, nil
+}
+
+// Good: closed on the error path
+func OpenValidated(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Stat(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Good: closed inside the goroutine
+func StreamClosed(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer f.Close()
+		_ = f.Name()
+	}()
+	return nil
+}
+
+// Bad: captured by the goroutine, closed by the outer function
+func StreamRace(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	go func() { // want `resource f captured by goroutine is never closed inside it`
+		_ = f.Name()
+	}()
+	return nil
+}
+
+// Bad: captured by the goroutine and never closed
+func StreamLeak(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	go func() { // want `resource f captured by goroutine is never closed inside it`
+		_ = f.Name()
+	}()
+	return nil
+}
+
+// Good: passed as a parameter and closed under its parameter name
+func StreamParam(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	go func(file *os.File) {
+		defer file.Close()
+		_ = file.Name()
+	}(f)
+	return nil
+}
+
+// Bad: passed as a parameter and never closed
+func StreamParamLeak(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	go func(file *os.File) { // want `resource f captured by goroutine is never closed inside it; close it in the goroutine \(defer file.Close\(\)\)`
+		_ = file.Name()
+	}(f)
+	return nil
+}
+
+// Good: the function waits for the goroutine before closing
+func StreamWait(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = f.Name()
+	}()
+	wg.Wait()
+	return nil
+}
+
+// Bad: waiting for the goroutine does not close the file
+func StreamWaitLeak(path string) error {
+	f, err := os.Open(path) // want `file must be closed`
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = f.Name()
+	}()
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,21 @@
+package multierr
+
+import "io"
+
+type Invoker interface {
+	Invoke() error
+}
+
+type invoker func() error
+
+func (i invoker) Invoke() error { return i() }
+
+func Close(closer io.Closer) Invoker {
+	return invoker(closer.Close)
+}
+
+func AppendInvoke(into *error, invoker Invoker) {
+	if err := invoker.Invoke(); err != nil && *into == nil {
+		*into = err
+	}
+}
@@ -0,0 +1,33 @@
+package helpers
+
+import "os"
+
+func release(f *os.File) {
+	_ = f.Close()
+}
+
+func closeQuietly(f *os.File) {
+	_ = f.Close()
+}
+
+// Good: release is configured as a close helper
+func ReadReleased(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer release(f)
+	_ = f.Name()
+	return nil
+}
+
+// Bad: the configured helpers replace the defaults
+func ReadQuietly(path string) error {
+	f, err := os.Open(path) // want `file must be closed`
+	if err != nil {
+		return err
+	}
+	defer closeQuietly(f)
+	_ = f.Name()
+	return nil
+}
@@ -0,0 +1,107 @@
+package owned
+
+import (
+	"os"
+
+	"go.uber.org/multierr"
+)
+
+// Good: the close error is kept in the named result
+func ReadKeepingCloseError(path string) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	_ = f.Name()
+	return nil
+}
+
+// Bad: the deferred function checks another file
+func ReadCheckingOther(path string, other *os.File) (err error) {
+	f, err := os.Open(path) // want `file must be closed`
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := other.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	_ = f.Name()
+	return nil
+}
+
+type Store struct {
+	file *os.File
+}
+
+// Good: the store owns the file and closes it in Close
+func (s *Store) Open(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	s.file = f
+	return nil
+}
+
+func (s *Store) Close() error {
+	return s.file.Close()
+}
+
+// Bad: storing into a field of a local value does not hand the file over
+func (s *Store) Peek(path string) error {
+	f, err := os.Open(path) // want `file must be closed`
+	if err != nil {
+		return err
+	}
+	var tmp Store
+	tmp.file = f
+	_ = tmp.file.Name()
+	return nil
+}
+
+func closeQuietly(f *os.File) {
+	_ = f.Close()
+}
+
+// Good: closed through a helper
+func ReadQuietly(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer closeQuietly(f)
+	_ = f.Name()
+	return nil
+}
+
+// Good: closed through multierr
+func ReadAppending(path string) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer multierr.AppendInvoke(&err, multierr.Close(f))
+	_ = f.Name()
+	return nil
+}
+
+func logName(f *os.File) {
+	_ = f.Name()
+}
+
+// Bad: the deferred call is not a close helper
+func ReadLogging(path string) error {
+	f, err := os.Open(path) // want `file must be closed`
+	if err != nil {
+		return err
+	}
+	defer logName(f)
+	return nil
+}
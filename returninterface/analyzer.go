@@ -5,6 +5,7 @@
 package returninterface
 
 import (
+	"flag"
 	"go/ast"
 	"go/types"
 	"strings"
@@ -42,16 +43,33 @@ Bad pattern:
 
 Exceptions:
 - Factory functions that must return different implementations
+- Interfaces with more than one implementation in the package, where
+  choosing between them is the point of returning the interface
+- Type parameters, even when constrained by an interface
 - Standard library interfaces (io.Reader, error)
-- Methods implementing interfaces`
+- Methods implementing interfaces
+
+Flags:
+    -acceptable  comma-separated additional interfaces that may be returned,
+                 as written at the return, like storage.Backend`
+
+var acceptable string
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "returninterface",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("returninterface", flag.ExitOnError)
+	fs.StringVar(&acceptable, "acceptable", "",
+		"comma-separated additional interfaces that may be returned, like storage.Backend")
+	return *fs
+}
+
 // Standard library interfaces that are acceptable to return
 var acceptableReturnInterfaces = map[string]bool{
 	// Error handling
@@ -92,6 +110,16 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
+	allowed := make(map[string]bool, len(acceptableReturnInterfaces))
+	for name := range acceptableReturnInterfaces {
+		allowed[name] = true
+	}
+	for _, name := range strings.Split(acceptable, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
 	}
@@ -102,13 +130,13 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
-		checkFunction(reporter, pass, fn)
+		checkFunction(reporter, pass, fn, allowed)
 	})
 
 	return nil, nil
 }
 
-func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, allowed map[string]bool) {
 	if fn.Type.Results == nil {
 		return
 	}
@@ -131,13 +159,59 @@ func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncD
 
 	for _, result := range fn.Type.Results.List {
 		// Check if return type is an interface
-		if isNonAcceptableInterface(pass, result.Type) {
-			typeName := types.ExprString(result.Type)
+		if !isNonAcceptableInterface(pass, result.Type, allowed) {
+			continue
+		}
+
+		typeName := types.ExprString(result.Type)
+		impls := implementations(pass, result.Type)
+		switch len(impls) {
+		case 0:
 			reporter.Reportf(result.Pos(),
 				"function %q returns interface %q; return concrete type instead (\"accept interfaces, return structs\")",
 				fn.Name.Name, typeName)
+		case 1:
+			reporter.Reportf(result.Pos(),
+				"function %q returns interface %q; return concrete type %s, its only implementation, instead (\"accept interfaces, return structs\")",
+				fn.Name.Name, typeName, impls[0])
+		default:
+			// Several implementations: returning the interface lets the
+			// function choose between them
+		}
+	}
+}
+
+// implementations returns the concrete types declared in the package that
+// implement the interface expr denotes, as T or *T
+func implementations(pass *analysis.Pass, expr ast.Expr) []string {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return nil
+	}
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var impls []string
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+		switch {
+		case types.Implements(named, iface):
+			impls = append(impls, name)
+		case types.Implements(types.NewPointer(named), iface):
+			impls = append(impls, "*"+name)
 		}
 	}
+	return impls
 }
 
 func isFactoryFunction(name string) bool {
@@ -150,12 +224,18 @@ func isFactoryFunction(name string) bool {
 	return false
 }
 
-func isNonAcceptableInterface(pass *analysis.Pass, expr ast.Expr) bool {
+func isNonAcceptableInterface(pass *analysis.Pass, expr ast.Expr, allowed map[string]bool) bool {
 	// Get the type
 	tv, ok := pass.TypesInfo.Types[expr]
 	if !ok {
 		// Fallback to AST-based check
-		return isInterfaceAST(expr)
+		return isInterfaceAST(expr, allowed)
+	}
+
+	// A type parameter's underlying type is its constraint, but callers get
+	// the concrete type they instantiate it with
+	if _, ok := tv.Type.(*types.TypeParam); ok {
+		return false
 	}
 
 	// Check if it's an interface type
@@ -171,7 +251,7 @@ func isNonAcceptableInterface(pass *analysis.Pass, expr ast.Expr) bool {
 
 	// Check if it's an acceptable interface
 	typeName := types.ExprString(expr)
-	if acceptableReturnInterfaces[typeName] {
+	if allowed[typeName] {
 		return false
 	}
 
@@ -185,7 +265,7 @@ func isNonAcceptableInterface(pass *analysis.Pass, expr ast.Expr) bool {
 	return true
 }
 
-func isInterfaceAST(expr ast.Expr) bool {
+func isInterfaceAST(expr ast.Expr, allowed map[string]bool) bool {
 	switch t := expr.(type) {
 	case *ast.InterfaceType:
 		return true
@@ -207,7 +287,7 @@ func isInterfaceAST(expr ast.Expr) bool {
 	case *ast.SelectorExpr:
 		// pkg.Type - check against acceptable list
 		typeName := types.ExprString(t)
-		return !acceptableReturnInterfaces[typeName]
+		return !allowed[typeName]
 	}
 
 	return false
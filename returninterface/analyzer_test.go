@@ -0,0 +1,26 @@
+package returninterface_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/returninterface"
+)
+
+func TestReturnInterface(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, returninterface.Analyzer, "returns")
+}
+
+func TestReturnInterfaceAcceptable(t *testing.T) {
+	if err := returninterface.Analyzer.Flags.Set("acceptable", "Plugin, net.Conn"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = returninterface.Analyzer.Flags.Set("acceptable", "")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, returninterface.Analyzer, "acceptable")
+}
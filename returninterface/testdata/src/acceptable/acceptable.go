@@ -0,0 +1,26 @@
+package acceptable
+
+import "net"
+
+type Plugin interface {
+	Run() error
+}
+
+type echoPlugin struct{}
+
+func (echoPlugin) Run() error { return nil }
+
+// Not reported: Plugin is configured as acceptable
+func LoadPlugin(path string) Plugin {
+	return echoPlugin{}
+}
+
+// Not reported: net.Conn is configured as acceptable
+func Dial(addr string) net.Conn {
+	return nil
+}
+
+// Reported: not configured
+func Listen(addr string) net.Listener { // want `function "Listen" returns interface "net.Listener"`
+	return nil
+}
@@ -0,0 +1,54 @@
+package returns
+
+import "fmt"
+
+type Storage interface {
+	Load(key string) ([]byte, error)
+}
+
+type fileStorage struct{}
+
+func (f *fileStorage) Load(string) ([]byte, error) { return nil, nil }
+
+type memoryStorage struct{}
+
+func (m *memoryStorage) Load(string) ([]byte, error) { return nil, nil }
+
+// Not reported: chooses between the implementations
+func SelectStorage(kind string) Storage {
+	if kind == "memory" {
+		return &memoryStorage{}
+	}
+	return &fileStorage{}
+}
+
+type Notifier interface {
+	Notify(msg string) error
+}
+
+type emailNotifier struct{}
+
+func (e emailNotifier) Notify(string) error { return nil }
+
+// Reported: there is only one implementation to return
+func DefaultNotifier() Notifier { // want `function "DefaultNotifier" returns interface "Notifier"; return concrete type emailNotifier, its only implementation, instead`
+	return emailNotifier{}
+}
+
+type Stringer interface {
+	String() string
+}
+
+// Not reported: callers get the type they instantiate T with
+func Map[T Stringer](items []T, fn func(T) T) T {
+	var last T
+	for _, item := range items {
+		last = fn(item)
+	}
+	return last
+}
+
+// Reported: implemented outside the package
+func Describe(v any) fmt.Formatter { // want `function "Describe" returns interface "fmt.Formatter"; return concrete type instead`
+	return nil
+}
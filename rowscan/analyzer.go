@@ -0,0 +1,490 @@
+// Package rowscan provides an analyzer that detects drift between SELECT
+// column lists and the `db:` tags of the structs they are scanned into.
+//
+// sqlx.StructScan, sqlx Select/Get, and scany's pgxscan map columns to struct
+// fields by tag at runtime. Adding a column to a query but not to the struct
+// (or the other way around) compiles fine and fails, or silently leaves a
+// field zero, only when the query runs.
+package rowscan
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect SELECT column lists that don't match the scanned struct's db tags
+
+This analyzer detects, for queries with constant SQL scanned into structs:
+1. Selected columns with no destination field (sqlx: "missing destination name")
+2. Struct fields with a db tag that no selected column populates
+3. SELECT * into a struct (with -select-star), which drifts silently
+
+Supported call shapes:
+    db.Select(&users, "SELECT id, name FROM users")
+    db.GetContext(ctx, &user, "SELECT id, name FROM users WHERE id = $1", id)
+    rows, _ := db.Queryx("SELECT id, name FROM users"); rows.StructScan(&u)
+    pgxscan.Select(ctx, pool, &users, "SELECT id, name FROM users")
+
+SQL parsing is intentionally minimal: a flat SELECT ... FROM column list with
+optional table prefixes and AS aliases. Anything more complex is skipped.`
+
+var selectStar bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "rowscan",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("rowscan", flag.ExitOnError)
+	fs.BoolVar(&selectStar, "select-star", false,
+		"report SELECT * scanned into structs")
+	return *fs
+}
+
+// scanPackages are the last import path elements of supported scanning libraries
+var scanPackages = map[string]bool{
+	"sqlx":    true,
+	"pgxscan": true,
+	"sqlscan": true,
+}
+
+// directScanFuncs take a destination and a query in one call
+var directScanFuncs = map[string]bool{
+	"Select":        true,
+	"Get":           true,
+	"SelectContext": true,
+	"GetContext":    true,
+}
+
+// queryFuncs return rows that are scanned later
+var queryFuncs = map[string]bool{
+	"Queryx":        true,
+	"QueryxContext": true,
+	"Query":         true,
+	"QueryContext":  true,
+}
+
+// rowsScanFuncs scan rows into a destination: rows.StructScan(&u), pgxscan.ScanAll(&u, rows)
+var rowsScanFuncs = map[string]bool{
+	"StructScan": true,
+	"ScanAll":    true,
+	"ScanOne":    true,
+}
+
+// pendingQuery is a constant SQL query whose rows are held in a variable
+type pendingQuery struct {
+	sql string
+	pos token.Pos
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return
+		}
+
+		checkFunction(reporter, pass, fn)
+	})
+
+	return nil, nil
+}
+
+func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl) {
+	// Rows variables holding the result of a constant query
+	rowsVars := make(map[types.Object]pendingQuery)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			trackQuery(pass, node, rowsVars)
+		case *ast.CallExpr:
+			checkScanCall(reporter, pass, node, rowsVars)
+		}
+		return true
+	})
+}
+
+// scanFunc returns the name of a function from a supported scanning library
+func scanFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return ""
+	}
+
+	path := fn.Pkg().Path()
+	if !scanPackages[path[strings.LastIndex(path, "/")+1:]] {
+		return ""
+	}
+	return fn.Name()
+}
+
+// trackQuery records rows, err := db.Queryx("SELECT ...")
+func trackQuery(pass *analysis.Pass, assign *ast.AssignStmt, rowsVars map[types.Object]pendingQuery) {
+	if len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+		return
+	}
+
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || !queryFuncs[scanFunc(pass, call)] {
+		return
+	}
+
+	sql, pos, _ := constantQuery(pass, call.Args)
+	if sql == "" {
+		return
+	}
+
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+		rowsVars[obj] = pendingQuery{sql: sql, pos: pos}
+	}
+}
+
+// constantQuery returns the first constant string argument, its position and index
+func constantQuery(pass *analysis.Pass, args []ast.Expr) (string, token.Pos, int) {
+	for i, arg := range args {
+		tv, ok := pass.TypesInfo.Types[arg]
+		if ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value), arg.Pos(), i
+		}
+	}
+	return "", token.NoPos, -1
+}
+
+// checkScanCall compares a query's columns against its destination struct
+func checkScanCall(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr, rowsVars map[types.Object]pendingQuery) {
+	name := scanFunc(pass, call)
+
+	switch {
+	case directScanFuncs[name]:
+		// The destination is the argument right before the query
+		sql, pos, idx := constantQuery(pass, call.Args)
+		if idx < 1 {
+			return
+		}
+		compare(reporter, pass, sql, pos, call.Args[idx-1])
+
+	case name == "StructScan" && len(call.Args) == 1:
+		sel := call.Fun.(*ast.SelectorExpr)
+		if q, ok := lookupRows(pass, sel.X, rowsVars); ok {
+			compare(reporter, pass, q.sql, q.pos, call.Args[0])
+		}
+
+	case rowsScanFuncs[name] && len(call.Args) == 2:
+		// pgxscan.ScanAll(&dest, rows)
+		if q, ok := lookupRows(pass, call.Args[1], rowsVars); ok {
+			compare(reporter, pass, q.sql, q.pos, call.Args[0])
+		}
+	}
+}
+
+func lookupRows(pass *analysis.Pass, expr ast.Expr, rowsVars map[types.Object]pendingQuery) (pendingQuery, bool) {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return pendingQuery{}, false
+	}
+	q, ok := rowsVars[pass.TypesInfo.ObjectOf(ident)]
+	return q, ok
+}
+
+// compare reports mismatches between the SELECT columns and the destination's db tags
+func compare(reporter *nolint.Reporter, pass *analysis.Pass, sql string, pos token.Pos, dest ast.Expr) {
+	st, structName := destStruct(pass.TypesInfo.TypeOf(dest))
+	if st == nil {
+		return
+	}
+
+	columns, star, ok := parseSelect(sql)
+	if !ok {
+		return
+	}
+
+	if star {
+		if selectStar {
+			reporter.Reportf(pos,
+				"SELECT * scanned into %s drifts silently when the table changes; list the columns explicitly",
+				structName)
+		}
+		return
+	}
+
+	fields := make(map[string]string) // column -> field name
+	var fieldOrder []string
+	collectFields(st, fields, &fieldOrder)
+
+	selected := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		selected[col] = true
+		if _, ok := fields[col]; !ok {
+			reporter.Reportf(pos,
+				"column %q selected by query has no destination field in %s; add a field tagged `db:%q`",
+				col, structName, col)
+		}
+	}
+
+	for _, col := range fieldOrder {
+		if !selected[col] {
+			reporter.Reportf(pos,
+				"field %s.%s (db:%q) is not populated by this query; select the column or drop the field",
+				structName, fields[col], col)
+		}
+	}
+}
+
+// destStruct unwraps *T, *[]T, *[]*T to the struct being scanned into
+func destStruct(t types.Type) (*types.Struct, string) {
+	if t == nil {
+		return nil, ""
+	}
+
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return nil, ""
+	}
+	t = ptr.Elem()
+
+	if slice, ok := t.Underlying().(*types.Slice); ok {
+		t = slice.Elem()
+	}
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil, ""
+	}
+
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok || isScannable(named, st) {
+		return nil, ""
+	}
+	return st, named.Obj().Name()
+}
+
+// isScannable mirrors sqlx: types implementing sql.Scanner or structs without
+// exported fields (time.Time) are scanned as a single column, not a column set
+func isScannable(named *types.Named, st *types.Struct) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), "Scan")
+	if _, ok := obj.(*types.Func); ok {
+		return true
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Exported() {
+			return false
+		}
+	}
+	return true
+}
+
+// collectFields gathers the column names a struct can receive, following
+// sqlx's default mapper: the db tag, or the lowercased field name
+func collectFields(st *types.Struct, fields map[string]string, order *[]string) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag, hasTag := reflect.StructTag(st.Tag(i)).Lookup("db")
+		name, _, _ := strings.Cut(tag, ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if field.Embedded() && !hasTag {
+			if embedded, ok := field.Type().Underlying().(*types.Struct); ok {
+				collectFields(embedded, fields, order)
+				continue
+			}
+		}
+
+		if !field.Exported() {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name())
+		}
+		name = strings.ToLower(name)
+
+		if _, dup := fields[name]; !dup {
+			*order = append(*order, name)
+		}
+		fields[name] = field.Name()
+	}
+}
+
+// parseSelect extracts the result column names of a flat SELECT ... FROM query.
+// It reports ok=false for anything it doesn't understand.
+func parseSelect(sql string) (columns []string, star bool, ok bool) {
+	s := strings.TrimSpace(sql)
+	upper := strings.ToUpper(s)
+	if !strings.HasPrefix(upper, "SELECT ") && !strings.HasPrefix(upper, "SELECT\n") && !strings.HasPrefix(upper, "SELECT\t") {
+		return nil, false, false
+	}
+
+	// Compound queries have more than one column list
+	for _, kw := range []string{" UNION ", " INTERSECT ", " EXCEPT "} {
+		if strings.Contains(upper, kw) {
+			return nil, false, false
+		}
+	}
+
+	body := s[len("SELECT"):]
+	end := topLevelKeyword(body, "FROM")
+	if end < 0 {
+		return nil, false, false
+	}
+	list := strings.TrimSpace(body[:end])
+	if strings.HasPrefix(strings.ToUpper(list), "DISTINCT ") {
+		list = strings.TrimSpace(list[len("DISTINCT "):])
+	}
+
+	for _, item := range splitTopLevel(list) {
+		item = strings.TrimSpace(item)
+		if item == "*" || strings.HasSuffix(item, ".*") {
+			star = true
+			continue
+		}
+
+		col, ok := columnName(item)
+		if !ok {
+			return nil, false, false
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, star, len(columns) > 0 || star
+}
+
+// topLevelKeyword finds keyword outside parentheses and quotes, as a whole word
+func topLevelKeyword(s, keyword string) int {
+	depth := 0
+	var quote byte
+	upper := strings.ToUpper(s)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(upper[i:], keyword) && isBoundary(s, i-1) && isBoundary(s, i+len(keyword)):
+			return i
+		}
+	}
+	return -1
+}
+
+func isBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	c := s[i]
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r' || c == ',' || c == '(' || c == ')'
+}
+
+// splitTopLevel splits a column list on commas outside parentheses
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// columnName resolves the result name of one select item: alias, or the
+// unqualified column identifier. Expressions without an alias are rejected.
+func columnName(item string) (string, bool) {
+	fields := strings.Fields(item)
+	var name string
+
+	switch {
+	case len(fields) == 1 && isQualifiedIdentifier(fields[0]):
+		name = fields[0][strings.LastIndex(fields[0], ".")+1:]
+	case len(fields) >= 3 && strings.EqualFold(fields[len(fields)-2], "AS"):
+		name = fields[len(fields)-1]
+	case len(fields) == 2 && isQualifiedIdentifier(fields[0]):
+		name = fields[1]
+	default:
+		return "", false
+	}
+
+	name = strings.Trim(name, "\"`")
+	if !isIdentifier(name) {
+		return "", false
+	}
+	return strings.ToLower(name), true
+}
+
+// isQualifiedIdentifier accepts col, t.col, and quoted forms
+func isQualifiedIdentifier(s string) bool {
+	for _, part := range strings.Split(s, ".") {
+		if !isIdentifier(strings.Trim(part, "\"`")) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package rowscan_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/rowscan"
+)
+
+func TestRowScanAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, rowscan.Analyzer, "a")
+}
+
+func TestRowScanSelectStar(t *testing.T) {
+	if err := rowscan.Analyzer.Flags.Set("select-star", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rowscan.Analyzer.Flags.Set("select-star", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, rowscan.Analyzer, "star")
+}
@@ -0,0 +1,69 @@
+package a
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Base struct {
+	ID int64 `db:"id"`
+}
+
+type User struct {
+	Base
+	Name     string `db:"name"`
+	Email    string `db:"email"`
+	Internal string `db:"-"`
+}
+
+// Good: aligned query and struct
+func ListUsers(db *sqlx.DB) ([]User, error) {
+	var users []User
+	err := db.Select(&users, "SELECT u.id, u.name, u.email FROM users u")
+	return users, err
+}
+
+// Good: aliases
+func GetUser(ctx context.Context, db *sqlx.DB, id int64) (User, error) {
+	var u User
+	err := db.GetContext(ctx, &u, "SELECT id, full_name AS name, lower(mail) AS email FROM users WHERE id = $1", id)
+	return u, err
+}
+
+// Bad: column without a field, field without a column
+func GetUserBad(db *sqlx.DB, id int64) (User, error) {
+	var u User
+	err := db.Get(&u, "SELECT id, name, created_at FROM users WHERE id = $1", id) // want `column "created_at" selected by query has no destination field in User` `field User.Email \(db:"email"\) is not populated by this query`
+	return u, err
+}
+
+// Bad: rows scanned later
+func IterUsers(db *sqlx.DB) error {
+	rows, err := db.Queryx("SELECT id, name, email, role FROM users") // want `column "role" selected by query has no destination field in User`
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u User
+		if err := rows.StructScan(&u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Skipped: SELECT * without -select-star
+func AllColumns(db *sqlx.DB) ([]User, error) {
+	var users []User
+	err := db.Select(&users, "SELECT * FROM users")
+	return users, err
+}
+
+// Skipped: complex query
+func Complex(db *sqlx.DB) ([]User, error) {
+	var users []User
+	err := db.Select(&users, "SELECT id, name FROM admins UNION SELECT id, name FROM users")
+	return users, err
+}
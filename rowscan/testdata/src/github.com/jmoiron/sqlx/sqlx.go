@@ -0,0 +1,23 @@
+package sqlx
+
+import "context"
+
+type DB struct{}
+
+type Rows struct{}
+
+func (db *DB) Select(dest interface{}, query string, args ...interface{}) error { return nil }
+
+func (db *DB) Get(dest interface{}, query string, args ...interface{}) error { return nil }
+
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+func (db *DB) Queryx(query string, args ...interface{}) (*Rows, error) { return nil, nil }
+
+func (r *Rows) Next() bool { return false }
+
+func (r *Rows) StructScan(dest interface{}) error { return nil }
+
+func (r *Rows) Close() error { return nil }
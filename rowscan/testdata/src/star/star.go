@@ -0,0 +1,14 @@
+package star
+
+import "github.com/jmoiron/sqlx"
+
+type User struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func AllColumns(db *sqlx.DB) ([]User, error) {
+	var users []User
+	err := db.Select(&users, "SELECT * FROM users") // want `SELECT \* scanned into User drifts silently`
+	return users, err
+}
@@ -65,7 +65,7 @@ Exceptions:
 var Analyzer = &analysis.Analyzer{
 	Name:     "sentinelerrors",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -73,37 +73,34 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
-	// Track which functions are at package level (init, etc.)
-	var currentFunc *ast.FuncDecl
-	var inTestFile bool
-
 	nodeFilter := []ast.Node{
-		(*ast.File)(nil),
-		(*ast.FuncDecl)(nil),
 		(*ast.CallExpr)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
-		switch node := n.(type) {
-		case *ast.File:
-			filename := pass.Fset.Position(node.Pos()).Filename
-			inTestFile = strings.HasSuffix(filename, "_test.go")
-
-		case *ast.FuncDecl:
-			currentFunc = node
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
 
-		case *ast.CallExpr:
-			if inTestFile {
-				return
-			}
+		file := stack[0].(*ast.File)
+		if strings.HasSuffix(pass.Fset.Position(file.Pos()).Filename, "_test.go") {
+			return false
+		}
 
-			// Skip main function - one-off errors are acceptable
-			if currentFunc != nil && currentFunc.Name.Name == "main" {
-				return
-			}
+		currentFunc, inFunc := enclosingFunc(stack)
+		if !inFunc {
+			// Package-level declarations like var ErrX = errors.New(...)
+			// are the sentinels this analyzer asks for
+			return true
+		}
 
-			checkErrorsNew(reporter, node, currentFunc)
+		// Skip main function - one-off errors are acceptable
+		if currentFunc != nil && currentFunc.Name.Name == "main" {
+			return true
 		}
+
+		checkErrorsNew(reporter, n.(*ast.CallExpr), currentFunc)
+		return true
 	})
 
 	return nil, nil
@@ -123,11 +120,6 @@ func checkErrorsNew(reporter *nolint.Reporter, call *ast.CallExpr, currentFunc *
 
 	// Check for errors.New()
 	if pkgIdent.Name == "errors" && selector.Sel.Name == "New" {
-		// Check if this is at package level (var declaration) - that's fine
-		if isPackageLevelVar(call) {
-			return
-		}
-
 		// Check if the error message is dynamic (contains variables)
 		if len(call.Args) > 0 {
 			if hasVariableContent(call.Args[0]) {
@@ -162,16 +154,20 @@ func checkErrorsNew(reporter *nolint.Reporter, call *ast.CallExpr, currentFunc *
 	}
 }
 
-func isPackageLevelVar(call *ast.CallExpr) bool {
-	_ = call // Used for potential future enhancement
-	// Check if this call is in a var declaration at package level
-	// This is complex to determine from the call alone
-	// For now, we use a heuristic: check if we're in a function
-	// If not in a function, it's package level
-
-	// This would need more sophisticated scope analysis
-	// For now, we'll rely on the currentFunc check in the caller
-	return false
+// enclosingFunc returns the innermost function declaration around the top of
+// stack, and whether the node is inside any function at all. Function
+// literals outside a declaration report true with a nil declaration.
+func enclosingFunc(stack []ast.Node) (*ast.FuncDecl, bool) {
+	inFunc := false
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.FuncDecl:
+			return node, true
+		case *ast.FuncLit:
+			inFunc = true
+		}
+	}
+	return nil, inFunc
 }
 
 func hasVariableContent(expr ast.Expr) bool {
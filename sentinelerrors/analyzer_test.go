@@ -0,0 +1,19 @@
+package sentinelerrors_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/sentinelerrors"
+)
+
+func TestSentinelErrorsNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sentinelerrors.Analyzer, "nolint")
+}
+
+func TestSentinelErrorsPackageLevel(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sentinelerrors.Analyzer, "packagelevel")
+}
@@ -0,0 +1,19 @@
+package nolint
+
+import "errors"
+
+// Reported: inline errors.New
+func Reported() error {
+	return errors.New("not found") // want `inline errors.New\(\) in function "Reported"`
+}
+
+// Suppressed inline
+func Suppressed() error {
+	return errors.New("not found") //nolint:sentinelerrors // message is part of a wire protocol
+}
+
+// Suppressed on the line above
+func Above() error {
+	//nolint:sentinelerrors // one-off validation error
+	return errors.New("empty name")
+}
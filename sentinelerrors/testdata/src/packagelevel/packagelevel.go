@@ -0,0 +1,49 @@
+package packagelevel
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinels before any function
+var (
+	ErrNotFound     = errors.New("item not found")
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+var ErrClosed = fmt.Errorf("store closed")
+
+func Get(id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+	return ErrNotFound
+}
+
+// Sentinels after a function are not attributed to it
+var ErrConflict = errors.New("conflict")
+
+var (
+	ErrTimeout = errors.New("timeout")
+	errBusy    = errors.New("busy")
+)
+
+func Put(id string) error {
+	if id == "" {
+		return errors.New("empty id") // want `inline errors.New\(\) in function "Put"`
+	}
+	if id == "busy" {
+		return errBusy
+	}
+	return ErrConflict
+}
+
+// Function literals in package-level vars run later, like any function
+var validate = func(id string) error {
+	if id == "" {
+		return errors.New("empty id") // want `inline errors.New\(\) in function ""`
+	}
+	return nil
+}
+
+var ErrLast = errors.New("declared last")
@@ -0,0 +1,308 @@
+// Package shutdownorder provides an analyzer that checks entrypoints wire
+// graceful shutdown.
+//
+// A service that blocks in ListenAndServe without trapping SIGTERM is killed
+// mid-request on every deploy, and an os.Exit after defer db.Close() skips
+// the cleanup the defer was written for.
+package shutdownorder
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check entrypoints handle termination signals and let deferred cleanup run
+
+In func main of package main and in the Run and RunE functions of cobra
+commands, this analyzer detects:
+1. Long-running calls, like ListenAndServe, Serve, or Run and Start taking
+   a context, without any signal handling through signal.NotifyContext or
+   signal.Notify, so SIGTERM kills the process without a graceful shutdown
+2. os.Exit and Fatal logging calls after a defer in the same function,
+   which exit without running the deferred cleanup
+
+Bad:
+    func main() {
+        db := openDB()
+        defer db.Close()
+        srv := &http.Server{Addr: ":8080"}
+        if err := srv.ListenAndServe(); err != nil {
+            log.Fatal(err) // db.Close never runs
+        }
+    }
+
+Good:
+    func main() {
+        if err := run(); err != nil {
+            log.Fatal(err)
+        }
+    }
+
+    func run() error {
+        ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+        defer stop()
+        db := openDB()
+        defer db.Close()
+        srv := &http.Server{Addr: ":8080"}
+        go func() {
+            <-ctx.Done()
+            shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+            defer cancel()
+            _ = srv.Shutdown(shutdownCtx)
+        }()
+        if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+            return err
+        }
+        return nil
+    }`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "shutdownorder",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// servingMethods block until the server stops
+var servingMethods = map[string]bool{
+	"Serve":             true,
+	"ServeTLS":          true,
+	"ListenAndServe":    true,
+	"ListenAndServeTLS": true,
+}
+
+// contextMethods block until the context they take is cancelled
+var contextMethods = map[string]bool{
+	"Run":   true,
+	"Start": true,
+}
+
+// cobraFields are the fields of cobra.Command that hold the command's entrypoint
+var cobraFields = map[string]bool{
+	"Run":  true,
+	"RunE": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Functions of this package that set up signal handling, like
+	// setupSignals() calling signal.NotifyContext
+	handlers := make(map[*types.Func]bool)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body != nil && handlesSignals(pass, fn.Body, nil) {
+			if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+				handlers[obj] = true
+			}
+		}
+	})
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.KeyValueExpr)(nil),
+		(*ast.AssignStmt)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		if strings.HasSuffix(pass.Fset.File(n.Pos()).Name(), "_test.go") {
+			return
+		}
+
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if pass.Pkg.Name() == "main" && node.Name.Name == "main" && node.Recv == nil && node.Body != nil {
+				checkEntrypoint(reporter, pass, "main", node.Body, handlers)
+			}
+
+		case *ast.KeyValueExpr:
+			// &cobra.Command{RunE: func(cmd *cobra.Command, args []string) error { ... }}
+			key, ok := node.Key.(*ast.Ident)
+			if !ok || !cobraFields[key.Name] {
+				return
+			}
+			if fn, ok := node.Value.(*ast.FuncLit); ok && isCobraRun(pass, fn) {
+				checkEntrypoint(reporter, pass, key.Name, fn.Body, handlers)
+			}
+
+		case *ast.AssignStmt:
+			// cmd.RunE = func(cmd *cobra.Command, args []string) error { ... }
+			if len(node.Lhs) != 1 || len(node.Rhs) != 1 {
+				return
+			}
+			sel, ok := node.Lhs[0].(*ast.SelectorExpr)
+			if !ok || !cobraFields[sel.Sel.Name] {
+				return
+			}
+			if fn, ok := node.Rhs[0].(*ast.FuncLit); ok && isCobraRun(pass, fn) {
+				checkEntrypoint(reporter, pass, sel.Sel.Name, fn.Body, handlers)
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// checkEntrypoint reports long-running calls without signal handling and
+// exits that skip deferred calls in the entrypoint body
+func checkEntrypoint(reporter *nolint.Reporter, pass *analysis.Pass, name string, body *ast.BlockStmt, handlers map[*types.Func]bool) {
+	if !handlesSignals(pass, body, handlers) {
+		if call, method := longRunningCall(pass, body); call != nil {
+			reporter.Reportf(call.Pos(),
+				"%s blocks until shutdown but %s never handles SIGINT or SIGTERM; "+
+					"use signal.NotifyContext and shut down with a timeout when the context is done",
+				method, name)
+		}
+	}
+
+	checkExits(reporter, pass, body)
+}
+
+// handlesSignals checks if body calls signal.Notify or signal.NotifyContext,
+// or one of handlers
+func handlesSignals(pass *analysis.Pass, body *ast.BlockStmt, handlers map[*types.Func]bool) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		fn := calledFunc(pass, call)
+		if fn == nil {
+			return true
+		}
+		switch {
+		case handlers[fn]:
+			found = true
+		case fn.Pkg() != nil && fn.Pkg().Path() == "os/signal" && (fn.Name() == "Notify" || fn.Name() == "NotifyContext"):
+			found = true
+		case fn.Name() == "SetupSignalHandler":
+			// controller-runtime's ctrl.SetupSignalHandler()
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// longRunningCall returns the first call in body that serves until the
+// process stops, and the name of the method
+func longRunningCall(pass *analysis.Pass, body *ast.BlockStmt) (*ast.CallExpr, string) {
+	var found *ast.CallExpr
+	var method string
+	ast.Inspect(body, func(n ast.Node) bool {
+		// Cobra commands declared inside main are entrypoints of their own
+		if fn, ok := n.(*ast.FuncLit); ok && isCobraRun(pass, fn) {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found != nil {
+			return found == nil
+		}
+		fn := calledFunc(pass, call)
+		if fn == nil {
+			return true
+		}
+		switch {
+		case servingMethods[fn.Name()]:
+			found, method = call, fn.Name()
+		case contextMethods[fn.Name()] && len(call.Args) > 0 && isContext(pass.TypesInfo.TypeOf(call.Args[0])):
+			found, method = call, fn.Name()
+		}
+		return found == nil
+	})
+	return found, method
+}
+
+// checkExits reports os.Exit and Fatal calls that follow a defer statement
+// in the same function
+func checkExits(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var deferred *ast.DeferStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			if deferred == nil {
+				deferred = node
+			}
+			return false
+		case *ast.CallExpr:
+			if deferred == nil {
+				return true
+			}
+			if exit := exitName(pass, node); exit != "" {
+				reporter.Reportf(node.Pos(),
+					"%s skips the deferred call to %s; return an error and exit from a function without defers",
+					exit, types.ExprString(deferred.Call.Fun))
+			}
+		}
+		return true
+	})
+}
+
+// exitName returns the name of call if it ends the process without
+// running deferred calls: os.Exit, or a Fatal function or method of a
+// logger like log.Fatal or logger.Fatalf
+func exitName(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn := calledFunc(pass, call)
+	if fn == nil || fn.Pkg() == nil {
+		return ""
+	}
+	switch {
+	case fn.Pkg().Path() == "os" && fn.Name() == "Exit":
+		return "os.Exit"
+	case strings.HasPrefix(fn.Name(), "Fatal"):
+		return types.ExprString(call.Fun)
+	}
+	return ""
+}
+
+// isCobraRun checks if fn has the signature of a cobra command's Run or
+// RunE: func(*cobra.Command, []string)
+func isCobraRun(pass *analysis.Pass, fn *ast.FuncLit) bool {
+	sig, ok := pass.TypesInfo.TypeOf(fn).(*types.Signature)
+	if !ok || sig.Params().Len() != 2 {
+		return false
+	}
+	ptr, ok := sig.Params().At(0).Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return strings.HasSuffix(named.Obj().Pkg().Path(), "spf13/cobra") && named.Obj().Name() == "Command"
+}
+
+// calledFunc returns the function or method a call invokes, or nil
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
@@ -0,0 +1,14 @@
+package shutdownorder_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/shutdownorder"
+)
+
+func TestShutdownOrderAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, shutdownorder.Analyzer, "good", "bad", "cli")
+}
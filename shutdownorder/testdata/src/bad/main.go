@@ -0,0 +1,20 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("postgres", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	srv := &http.Server{Addr: ":8080"}
+	if err := srv.ListenAndServe(); err != nil { // want `ListenAndServe blocks until shutdown but main never handles SIGINT or SIGTERM`
+		log.Fatalf("serve: %v", err) // want `log.Fatalf skips the deferred call to db.Close`
+	}
+}
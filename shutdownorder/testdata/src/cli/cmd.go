@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+type Manager struct{}
+
+func (m *Manager) Start(ctx context.Context) error { return nil }
+
+func (m *Manager) Close() error { return nil }
+
+func setupSignals() context.Context {
+	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
+	return ctx
+}
+
+var serveCmd = &cobra.Command{
+	Use: "serve",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := &Manager{}
+		return mgr.Start(context.Background()) // want `Start blocks until shutdown but RunE never handles SIGINT or SIGTERM`
+	},
+}
+
+var runCmd = &cobra.Command{
+	Use: "run",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := &Manager{}
+		return mgr.Start(setupSignals())
+	},
+}
+
+var exitCmd = &cobra.Command{
+	Use: "exit",
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr := &Manager{}
+		defer mgr.Close()
+		if len(args) == 0 {
+			os.Exit(2) // want `os.Exit skips the deferred call to mgr.Close`
+		}
+	},
+}
+
+func main() {
+	root := &cobra.Command{Use: "app"}
+	root.RunE = func(cmd *cobra.Command, args []string) error {
+		return (&Manager{}).Start(cmd.Context()) // want `Start blocks until shutdown but RunE never handles SIGINT or SIGTERM`
+	}
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
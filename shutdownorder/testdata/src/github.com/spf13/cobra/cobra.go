@@ -0,0 +1,13 @@
+package cobra
+
+import "context"
+
+type Command struct {
+	Use  string
+	Run  func(cmd *Command, args []string)
+	RunE func(cmd *Command, args []string) error
+}
+
+func (c *Command) Execute() error { return nil }
+
+func (c *Command) Context() context.Context { return context.Background() }
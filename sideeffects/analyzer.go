@@ -6,6 +6,7 @@
 package sideeffects
 
 import (
+	"flag"
 	"go/types"
 	"strings"
 
@@ -24,12 +25,31 @@ This analyzer uses SSA (Static Single Assignment) form to track data flow and de
 3. Pure functions performing I/O operations
 4. Global state mutations in handler functions
 
-SSA provides a more accurate view of program flow than AST alone.`
+SSA provides a more accurate view of program flow than AST alone.
+
+Reconcilers are methods of types that embed a controller-runtime type or
+implement reconcile.Reconciler, and methods of types whose name contains
+one of the -controller-receivers patterns and that have a Reconcile method.
+A PIDController without Reconcile is not a reconciler.
+
+Flags:
+    -forbidden-calls       comma-separated calls reconcilers must not make
+                           (default net/http.Get,net/http.Post,net/http.Do,
+                           database/sql.Open,database/sql.(*DB).Exec,
+                           database/sql.(*DB).Query)
+    -controller-receivers  comma-separated patterns of reconciler type names
+                           (default Reconciler,Controller,KubeOperator)
+    -pure-patterns         comma-separated patterns of function names that
+                           must be pure (default *Validator,*Parser,*Formatter)
+    -pure-prefixes         comma-separated prefixes of function names that
+                           must be pure, matched case-insensitively (default
+                           validate,parse,format,compute,calculate,convert)`
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "sideeffects",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(buildssa.Analyzer),
 	Run:      run,
 }
 
@@ -39,8 +59,12 @@ type Config struct {
 	ForbiddenCallsInReconcilers []string
 	// ForbiddenImportsInControllers are packages that controllers shouldn't import directly
 	ForbiddenImportsInControllers []string
+	// ControllerReceivers are patterns of reconciler receiver type names
+	ControllerReceivers []string
 	// PureFunctionPatterns are function name patterns that should have no side effects
 	PureFunctionPatterns []string
+	// PureFunctionPrefixes are lowercase function name prefixes that should have no side effects
+	PureFunctionPrefixes []string
 }
 
 var defaultConfig = Config{
@@ -56,25 +80,84 @@ var defaultConfig = Config{
 		"database/sql",
 		"net/http",
 	},
+	ControllerReceivers: []string{
+		"Reconciler",
+		"Controller",
+		"KubeOperator",
+	},
 	PureFunctionPatterns: []string{
 		"*Validator",
 		"*Parser",
 		"*Formatter",
 	},
+	PureFunctionPrefixes: []string{
+		"validate",
+		"parse",
+		"format",
+		"compute",
+		"calculate",
+		"convert",
+	},
+}
+
+// controllerRuntime is the import path prefix of controller-runtime
+const controllerRuntime = "sigs.k8s.io/controller-runtime"
+
+// Configured patterns, comma-separated, defaulting to defaultConfig
+var (
+	forbiddenCalls      string
+	controllerReceivers string
+	purePatterns        string
+	purePrefixes        string
+)
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("sideeffects", flag.ExitOnError)
+	fs.StringVar(&forbiddenCalls, "forbidden-calls", strings.Join(defaultConfig.ForbiddenCallsInReconcilers, ","),
+		"comma-separated calls reconcilers must not make")
+	fs.StringVar(&controllerReceivers, "controller-receivers", strings.Join(defaultConfig.ControllerReceivers, ","),
+		"comma-separated patterns of reconciler type names")
+	fs.StringVar(&purePatterns, "pure-patterns", strings.Join(defaultConfig.PureFunctionPatterns, ","),
+		"comma-separated patterns of function names that must be pure")
+	fs.StringVar(&purePrefixes, "pure-prefixes", strings.Join(defaultConfig.PureFunctionPrefixes, ","),
+		"comma-separated prefixes of function names that must be pure")
+	return *fs
+}
+
+// configFromFlags returns defaultConfig with the patterns set by the flags
+func configFromFlags() Config {
+	cfg := defaultConfig
+	cfg.ForbiddenCallsInReconcilers = splitList(forbiddenCalls)
+	cfg.ControllerReceivers = splitList(controllerReceivers)
+	cfg.PureFunctionPatterns = splitList(purePatterns)
+	cfg.PureFunctionPrefixes = splitList(strings.ToLower(purePrefixes))
+	return cfg
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	ssaInfo := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	cfg := configFromFlags()
 
 	for _, fn := range ssaInfo.SrcFuncs {
 		// Check if this is a reconciler function
-		if isReconcilerFunc(fn) {
-			checkReconcilerSideEffects(reporter, fn)
+		if isReconcilerFunc(fn, cfg) {
+			checkReconcilerSideEffects(reporter, fn, cfg)
 		}
 
 		// Check if this function should be pure
-		if shouldBePure(fn) {
+		if shouldBePure(fn, cfg) {
 			checkPureFunctionSideEffects(reporter, fn)
 		}
 
@@ -87,39 +170,100 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	return nil, nil
 }
 
-// isReconcilerFunc checks if a function is a Kubernetes reconciler
-func isReconcilerFunc(fn *ssa.Function) bool {
+// isReconcilerFunc checks if a function is a method of a Kubernetes
+// reconciler: its receiver type embeds a controller-runtime type or
+// implements reconcile.Reconciler, or its name matches one of the
+// ControllerReceivers and it has a Reconcile method
+func isReconcilerFunc(fn *ssa.Function, cfg Config) bool {
 	if fn == nil || fn.Signature == nil {
 		return false
 	}
 
-	// Check method receiver for Reconciler pattern
 	recv := fn.Signature.Recv()
 	if recv == nil {
 		return false
 	}
+	named := namedType(recv.Type())
+	if named == nil {
+		return false
+	}
 
-	recvType := recv.Type().String()
+	if embedsControllerRuntime(named) {
+		return true
+	}
+	reconcile := reconcileMethod(named)
+	if reconcile == nil {
+		return false
+	}
+	if referencesControllerRuntime(reconcile.Signature()) {
+		return true
+	}
 
-	// Common patterns for reconcilers
-	patterns := []string{
-		"Reconciler",
-		"Controller",
-		"*KubeOperator",
+	name := named.Obj().Name()
+	for _, pattern := range cfg.ControllerReceivers {
+		if strings.Contains(name, strings.Trim(pattern, "*")) {
+			return true
+		}
 	}
+	return false
+}
 
-	for _, pattern := range patterns {
-		if strings.Contains(recvType, pattern) {
+// namedType returns the named type t is, or points to
+func namedType(t types.Type) *types.Named {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, _ := types.Unalias(t).(*types.Named)
+	return named
+}
+
+// reconcileMethod returns the Reconcile method of named, or nil
+func reconcileMethod(named *types.Named) *types.Func {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), "Reconcile")
+	method, _ := obj.(*types.Func)
+	return method
+}
+
+// embedsControllerRuntime checks if named is a struct embedding a type from
+// controller-runtime, like client.Client
+func embedsControllerRuntime(named *types.Named) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Embedded() && isControllerRuntimeType(field.Type()) {
 			return true
 		}
 	}
+	return false
+}
 
-	// Check if the function name is "Reconcile"
-	return fn.Name() == "Reconcile"
+// referencesControllerRuntime checks if a parameter or result of sig has a
+// controller-runtime type, as reconcile.Reconciler's Reconcile method does
+func referencesControllerRuntime(sig *types.Signature) bool {
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			if isControllerRuntimeType(tuple.At(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isControllerRuntimeType checks if t, or the type it points to, is
+// declared in controller-runtime
+func isControllerRuntimeType(t types.Type) bool {
+	named := namedType(t)
+	if named == nil || named.Obj().Pkg() == nil {
+		return false
+	}
+	return strings.HasPrefix(named.Obj().Pkg().Path(), controllerRuntime)
 }
 
 // checkReconcilerSideEffects analyzes a reconciler function for forbidden calls
-func checkReconcilerSideEffects(reporter *nolint.Reporter, fn *ssa.Function) {
+func checkReconcilerSideEffects(reporter *nolint.Reporter, fn *ssa.Function, cfg Config) {
 	// Walk all blocks in the function
 	for _, block := range fn.Blocks {
 		for _, instr := range block.Instrs {
@@ -136,7 +280,7 @@ func checkReconcilerSideEffects(reporter *nolint.Reporter, fn *ssa.Function) {
 			calleeName := callee.String()
 
 			// Check against forbidden calls
-			for _, forbidden := range defaultConfig.ForbiddenCallsInReconcilers {
+			for _, forbidden := range cfg.ForbiddenCallsInReconcilers {
 				if strings.Contains(calleeName, forbidden) || matchesCallPattern(callee, forbidden) {
 					reporter.Reportf(call.Pos(),
 						"reconciler should not make direct %s call; use service layer abstraction",
@@ -160,9 +304,9 @@ func checkReconcilerSideEffects(reporter *nolint.Reporter, fn *ssa.Function) {
 }
 
 // shouldBePure checks if a function should be pure based on naming conventions
-func shouldBePure(fn *ssa.Function) bool {
+func shouldBePure(fn *ssa.Function, cfg Config) bool {
 	name := fn.Name()
-	for _, pattern := range defaultConfig.PureFunctionPatterns {
+	for _, pattern := range cfg.PureFunctionPatterns {
 		pattern = strings.TrimPrefix(pattern, "*")
 		if strings.Contains(name, pattern) {
 			return true
@@ -171,8 +315,7 @@ func shouldBePure(fn *ssa.Function) bool {
 
 	// Functions named "validate*", "parse*", "format*" should be pure
 	lowerName := strings.ToLower(name)
-	purePatterns := []string{"validate", "parse", "format", "compute", "calculate", "convert"}
-	for _, p := range purePatterns {
+	for _, p := range cfg.PureFunctionPrefixes {
 		if strings.HasPrefix(lowerName, p) {
 			return true
 		}
@@ -0,0 +1,30 @@
+package sideeffects_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/sideeffects"
+)
+
+func TestSideEffectsReconcilers(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sideeffects.Analyzer, "controllers")
+}
+
+func TestSideEffectsFlags(t *testing.T) {
+	for name, value := range map[string]string{"forbidden-calls": "legacysdk.Send", "pure-prefixes": "validate"} {
+		if err := sideeffects.Analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		_ = sideeffects.Analyzer.Flags.Set("forbidden-calls",
+			"net/http.Get,net/http.Post,net/http.Do,database/sql.Open,database/sql.(*DB).Exec,database/sql.(*DB).Query")
+		_ = sideeffects.Analyzer.Flags.Set("pure-prefixes", "validate,parse,format,compute,calculate,convert")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sideeffects.Analyzer, "legacy")
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PIDController is a control-loop type, not a Kubernetes controller.
+type PIDController struct {
+	Kp, Ki, Kd float64
+	integral   float64
+	last       float64
+	endpoint   string
+}
+
+// Compute returns the next control output.
+func (c *PIDController) Compute(setpoint, measured, dt float64) float64 {
+	err := setpoint - measured
+	c.integral += err * dt
+	derivative := (err - c.last) / dt
+	c.last = err
+	return c.Kp*err + c.Ki*c.integral + c.Kd*derivative
+}
+
+// Publish pushes the last error to a metrics endpoint.
+func (c *PIDController) Publish() error {
+	resp, err := http.Post(c.endpoint, "text/plain", strings.NewReader("pid"))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// MemcachedReconciler implements reconcile.Reconciler.
+type MemcachedReconciler struct {
+	endpoint string
+}
+
+func (r *MemcachedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, r.notify()
+}
+
+func (r *MemcachedReconciler) notify() error {
+	resp, err := http.Get(r.endpoint) // want `reconciler should not make direct net/http.Get call` `reconciler should not make HTTP calls directly`
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Syncer embeds client.Client, so it is a reconciler without Reconcile.
+type Syncer struct {
+	client.Client
+	endpoint string
+}
+
+func (s *Syncer) Sync(ctx context.Context) error {
+	resp, err := http.Get(s.endpoint) // want `reconciler should not make direct net/http.Get call` `reconciler should not make HTTP calls directly`
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// CacheController has a Reconcile method and a matching name.
+type CacheController struct {
+	endpoint string
+}
+
+func (c *CacheController) Reconcile(ctx context.Context, key string) error {
+	resp, err := http.Get(c.endpoint) // want `reconciler should not make direct net/http.Get call` `reconciler should not make HTTP calls directly`
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,28 @@
+package legacy
+
+import (
+	"context"
+	"os"
+
+	"legacysdk"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type WidgetReconciler struct{}
+
+func (r *WidgetReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	err := legacysdk.Send("/widgets", nil) // want `reconciler should not make direct legacysdk.Send call; use service layer abstraction`
+	return reconcile.Result{}, err
+}
+
+// ParseWidget reads a file, which is fine once -pure-prefixes drops parse.
+func ParseWidget(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// ValidateWidget still must be pure.
+func ValidateWidget(path string) error {
+	_, err := os.Stat(path) // want `function "ValidateWidget" should be pure but contains I/O operation Stat`
+	return err
+}
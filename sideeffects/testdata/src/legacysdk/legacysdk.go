@@ -0,0 +1,4 @@
+package legacysdk
+
+// Send calls the legacy REST API.
+func Send(path string, body []byte) error { return nil }
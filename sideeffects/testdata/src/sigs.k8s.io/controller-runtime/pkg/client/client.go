@@ -0,0 +1,14 @@
+package client
+
+import "context"
+
+type Object interface{}
+
+type ObjectKey struct{ Namespace, Name string }
+
+type Client interface {
+	Get(ctx context.Context, key ObjectKey, obj Object) error
+	Update(ctx context.Context, obj Object) error
+}
+
+func IgnoreNotFound(err error) error { return err }
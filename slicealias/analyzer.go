@@ -0,0 +1,158 @@
+// Package slicealias provides an analyzer that detects exported methods
+// returning a struct's internal slice or map field.
+//
+// Slices and maps are references: returning c.items hands callers the
+// struct's own backing array or map, which they can modify behind the
+// struct's back and without its lock.
+package slicealias
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/goversion"
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect exported methods returning internal slice or map fields
+
+A method with a pointer receiver that returns one of the receiver's slice
+or map fields as is lets callers modify the struct's state, without its
+lock and without the struct noticing:
+
+    func (c *Cache) Items() []Item {
+        c.mu.RLock()
+        defer c.mu.RUnlock()
+        return c.items // callers append to and overwrite c.items
+    }
+
+Return a copy with slices.Clone or maps.Clone, or an iterator with
+slices.Values or maps.All, instead. The advice follows the module's Go
+version: iterators need Go 1.23 and the slices and maps packages Go 1.21.
+
+Unexported methods, returns of copies, and fields whose doc or line
+comment says they are immutable or read-only are not reported.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "slicealias",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	immutable := immutableFields(pass, inspect)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Recv == nil || fn.Body == nil || !fn.Name.IsExported() {
+			return
+		}
+		recvField := fn.Recv.List[0]
+		if _, isPointer := recvField.Type.(*ast.StarExpr); !isPointer || len(recvField.Names) == 0 {
+			return
+		}
+		recv := pass.TypesInfo.Defs[recvField.Names[0]]
+		if recv == nil {
+			return
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				// Returns of closures are not the method's results
+				return false
+			case *ast.ReturnStmt:
+				for _, result := range node.Results {
+					checkResult(reporter, pass, fn, recv, result, immutable)
+				}
+			}
+			return true
+		})
+	})
+
+	return nil, nil
+}
+
+// checkResult reports result if it is a slice or map field of recv
+func checkResult(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, recv types.Object, result ast.Expr, immutable map[*types.Var]bool) {
+	sel, ok := ast.Unparen(result).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || pass.TypesInfo.Uses[ident] != recv {
+		return
+	}
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return
+	}
+	field, ok := selection.Obj().(*types.Var)
+	if !ok || immutable[field] {
+		return
+	}
+
+	var kind, clone, iterator string
+	switch field.Type().Underlying().(type) {
+	case *types.Slice:
+		kind, clone, iterator = "slice", "slices.Clone", "slices.Values"
+	case *types.Map:
+		kind, clone, iterator = "map", "maps.Clone", "maps.All"
+	default:
+		return
+	}
+
+	name := types.ExprString(sel)
+	var advice string
+	switch {
+	case goversion.Before(pass, result.Pos(), goversion.SlicesMaps):
+		advice = "return a copy"
+	case goversion.Before(pass, result.Pos(), goversion.Iterators):
+		advice = fmt.Sprintf("return %s(%s)", clone, name)
+	default:
+		advice = fmt.Sprintf("return %s(%s) or an iterator with %s", clone, name, iterator)
+	}
+
+	reporter.Reportf(result.Pos(),
+		"%s returns the internal %s field %s, which callers can modify without the receiver knowing; %s",
+		fn.Name.Name, kind, name, advice)
+}
+
+// immutableFields finds the struct fields of the package whose doc or line
+// comment says they are immutable or read-only
+func immutableFields(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Var]bool {
+	immutable := make(map[*types.Var]bool)
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		for _, field := range n.(*ast.StructType).Fields.List {
+			if !documentedImmutable(field.Doc) && !documentedImmutable(field.Comment) {
+				continue
+			}
+			for _, name := range field.Names {
+				if v, ok := pass.TypesInfo.Defs[name].(*types.Var); ok {
+					immutable[v] = true
+				}
+			}
+		}
+	})
+	return immutable
+}
+
+// documentedImmutable checks if the comment calls the field immutable or
+// read-only
+func documentedImmutable(cg *ast.CommentGroup) bool {
+	if cg == nil {
+		return false
+	}
+	text := strings.ToLower(cg.Text())
+	return strings.Contains(text, "immutable") || strings.Contains(text, "read-only") || strings.Contains(text, "readonly")
+}
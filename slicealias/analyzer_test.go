@@ -0,0 +1,20 @@
+package slicealias_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/slicealias"
+)
+
+func TestSliceAlias(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, slicealias.Analyzer, "a")
+}
+
+func TestSliceAliasGoVersion(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, filepath.Join(testdata, "legacy"), slicealias.Analyzer, "example.com/legacy")
+}
@@ -0,0 +1,12 @@
+//go:build go1.21
+
+package legacy
+
+// Go 1.21 has maps.Clone but not maps.All
+type Index struct {
+	byName map[string]int
+}
+
+func (i *Index) ByName() map[string]int {
+	return i.byName // want `return maps.Clone\(i.byName\)$`
+}
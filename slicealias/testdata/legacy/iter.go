@@ -0,0 +1,11 @@
+//go:build go1.23
+
+package legacy
+
+type Registry struct {
+	names []string
+}
+
+func (r *Registry) Names() []string {
+	return r.names // want `return slices.Clone\(r.names\) or an iterator with slices.Values$`
+}
@@ -0,0 +1,10 @@
+package legacy
+
+// Go 1.20 has neither slices.Clone nor iterators
+type Cache struct {
+	items []string
+}
+
+func (c *Cache) Items() []string {
+	return c.items // want `Items returns the internal slice field c.items, which callers can modify without the receiver knowing; return a copy$`
+}
@@ -0,0 +1,84 @@
+package a
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+)
+
+type Item struct{ ID string }
+
+type Cache struct {
+	mu     sync.RWMutex
+	items  []Item
+	byID   map[string]Item
+	name   string
+	counts [4]int
+
+	// defaults are set once by NewCache and immutable afterwards
+	defaults []Item
+	tags     map[string]string // read-only after construction
+}
+
+func (c *Cache) Items() []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.items // want `Items returns the internal slice field c.items, which callers can modify without the receiver knowing; return slices.Clone\(c.items\) or an iterator with slices.Values`
+}
+
+func (c *Cache) Index() map[string]Item {
+	return (c.byID) // want `Index returns the internal map field c.byID, which callers can modify without the receiver knowing; return maps.Clone\(c.byID\) or an iterator with maps.All`
+}
+
+func (c *Cache) Lookup(id string) (Item, map[string]Item, bool) {
+	item, ok := c.byID[id]
+	if !ok {
+		return Item{}, c.byID, false // want `Lookup returns the internal map field c.byID`
+	}
+	return item, nil, true
+}
+
+// ItemsCopy returns a copy, so callers cannot modify the cache
+func (c *Cache) ItemsCopy() []Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.items)
+}
+
+func (c *Cache) IndexCopy() map[string]Item {
+	return maps.Clone(c.byID)
+}
+
+func (c *Cache) All() iter.Seq[Item] {
+	return slices.Values(c.items)
+}
+
+// Name and Counts return values, not references
+func (c *Cache) Name() string { return c.name }
+
+func (c *Cache) Counts() [4]int { return c.counts }
+
+func (c *Cache) First() Item { return c.items[0] }
+
+// Defaults and Tags return fields documented as immutable
+func (c *Cache) Defaults() []Item { return c.defaults }
+
+func (c *Cache) Tags() map[string]string { return c.tags }
+
+// itemsLocked is unexported, so only the package can misuse it
+func (c *Cache) itemsLocked() []Item { return c.items }
+
+// Filter returns a closure's result, not the field
+func (c *Cache) Filter() func() []Item {
+	return func() []Item {
+		return c.items
+	}
+}
+
+// Snapshot has a value receiver
+type Snapshot struct {
+	items []Item
+}
+
+func (s Snapshot) Items() []Item { return s.items }
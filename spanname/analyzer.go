@@ -0,0 +1,306 @@
+// Package spanname provides an analyzer that enforces OpenTelemetry span,
+// tracer, and attribute naming conventions.
+//
+// Span names built with fmt.Sprintf("get user %s", id) create one span name per
+// ID, which explodes cardinality in tracing backends and makes spans impossible
+// to aggregate. IDs belong in attributes; span names should be low-cardinality
+// operation names.
+package spanname
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `enforce OpenTelemetry span and attribute naming conventions
+
+This analyzer detects:
+1. Dynamic span names (fmt.Sprintf, string concatenation) in tracer.Start
+2. Constant span names not matching the naming convention
+3. otel.Tracer("") or tracer names unrelated to the package path
+4. Attribute keys that look like semantic convention keys but use the wrong form
+
+Bad:
+    ctx, span := tracer.Start(ctx, fmt.Sprintf("GetUser %s", id))
+
+Good:
+    ctx, span := tracer.Start(ctx, "users.GetUser",
+        trace.WithAttributes(attribute.String("user.id", id)))
+
+Span names are aggregated by tracing backends; every unique name becomes its
+own series. Put identifiers in attributes instead.
+
+Flags:
+    -pattern         regular expression constant span names must match
+    -attribute-keys  extra wrong=right attribute key pairs, comma separated`
+
+// defaultPattern accepts dotted or slashed operation names such as
+// "users.GetUser" or "billing/charge".
+const defaultPattern = `^[A-Za-z][\w-]*([./][A-Za-z][\w-]*)+$`
+
+var (
+	namePattern   string
+	attributeKeys string
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "spanname",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("spanname", flag.ExitOnError)
+	fs.StringVar(&namePattern, "pattern", defaultPattern,
+		"regular expression constant span names must match")
+	fs.StringVar(&attributeKeys, "attribute-keys", "",
+		"extra wrong=right attribute key pairs, comma separated")
+	return *fs
+}
+
+// otelPrefix is the import path prefix of the OpenTelemetry Go SDK and API
+const otelPrefix = "go.opentelemetry.io/otel"
+
+// semconvKeys maps common misspellings of semantic convention attribute keys
+// to the key the specification defines.
+var semconvKeys = map[string]string{
+	"http.status":      "http.response.status_code",
+	"http.status_code": "http.response.status_code",
+	"http.code":        "http.response.status_code",
+	"http.method":      "http.request.method",
+	"http.url":         "url.full",
+	"http.path":        "url.path",
+	"db.type":          "db.system",
+	"net.peer.ip":      "network.peer.address",
+	"net.peer.port":    "network.peer.port",
+	"exception":        "exception.message",
+}
+
+// attributeFuncs are the attribute constructors that take a key as first argument
+var attributeFuncs = map[string]bool{
+	"String":       true,
+	"StringSlice":  true,
+	"Int":          true,
+	"IntSlice":     true,
+	"Int64":        true,
+	"Int64Slice":   true,
+	"Float64":      true,
+	"Float64Slice": true,
+	"Bool":         true,
+	"BoolSlice":    true,
+	"Stringer":     true,
+}
+
+// dynamicFormatters are functions whose results vary with their arguments
+var dynamicFormatters = map[string]bool{
+	"fmt.Sprintf":  true,
+	"fmt.Sprint":   true,
+	"fmt.Sprintln": true,
+	"strconv.Itoa": true,
+	"strings.Join": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	pattern, err := regexp.Compile(namePattern)
+	if err != nil {
+		return nil, fmt.Errorf("spanname: invalid -pattern %q: %w", namePattern, err)
+	}
+
+	keys, err := parseAttributeKeys(attributeKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+
+		if isAttributeKeyConversion(pass, call) {
+			checkAttributeKey(reporter, pass, call.Args[0], keys)
+			return
+		}
+
+		fn := calledFunc(pass, call)
+		if fn == nil || fn.Pkg() == nil || !strings.HasPrefix(fn.Pkg().Path(), otelPrefix) {
+			return
+		}
+
+		switch {
+		case fn.Name() == "Start" && isMethod(fn) && len(call.Args) >= 2:
+			checkSpanName(reporter, pass, call.Args[1], pattern)
+		case fn.Name() == "Tracer" && len(call.Args) >= 1:
+			checkTracerName(reporter, pass, call.Args[0])
+		case attributeFuncs[fn.Name()] && fn.Pkg().Path() == otelPrefix+"/attribute" && len(call.Args) >= 1:
+			checkAttributeKey(reporter, pass, call.Args[0], keys)
+		}
+	})
+
+	return nil, nil
+}
+
+// parseAttributeKeys merges the builtin semconv table with wrong=right pairs from flags
+func parseAttributeKeys(extra string) (map[string]string, error) {
+	keys := make(map[string]string, len(semconvKeys))
+	for k, v := range semconvKeys {
+		keys[k] = v
+	}
+
+	for _, pair := range strings.Split(extra, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		wrong, right, ok := strings.Cut(pair, "=")
+		if !ok || wrong == "" || right == "" {
+			return nil, fmt.Errorf("spanname: invalid -attribute-keys entry %q, want wrong=right", pair)
+		}
+		keys[wrong] = right
+	}
+
+	return keys, nil
+}
+
+// calledFunc resolves the function or method a call invokes
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+
+	fn, _ := pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+// isAttributeKeyConversion checks for attribute.Key("...") conversions
+func isAttributeKeyConversion(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if len(call.Args) != 1 {
+		return false
+	}
+
+	tv, ok := pass.TypesInfo.Types[call.Fun]
+	if !ok || !tv.IsType() {
+		return false
+	}
+
+	named, ok := tv.Type.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+
+	return named.Obj().Name() == "Key" && named.Obj().Pkg().Path() == otelPrefix+"/attribute"
+}
+
+func isMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() != nil
+}
+
+// checkSpanName classifies the span name argument of tracer.Start
+func checkSpanName(reporter *nolint.Reporter, pass *analysis.Pass, arg ast.Expr, pattern *regexp.Regexp) {
+	if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil {
+		if tv.Value.Kind() != constant.String {
+			return
+		}
+		name := constant.StringVal(tv.Value)
+		if !pattern.MatchString(name) {
+			reporter.Reportf(arg.Pos(),
+				"span name %q does not match the naming convention %s; use package.Operation or service.operation",
+				name, pattern)
+		}
+		return
+	}
+
+	if desc := dynamicDescription(pass, arg); desc != "" {
+		reporter.Reportf(arg.Pos(),
+			"span name is built with %s; dynamic span names explode tracing cardinality, use a constant name and put IDs in attributes",
+			desc)
+	}
+}
+
+// dynamicDescription reports how a non-constant span name is built, or "" if
+// it is a plain variable whose origin cannot be classified
+func dynamicDescription(pass *analysis.Pass, expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		if fn := calledFunc(pass, e); fn != nil && fn.Pkg() != nil {
+			name := fn.Pkg().Name() + "." + fn.Name()
+			if dynamicFormatters[name] {
+				return name
+			}
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return "string concatenation"
+		}
+	}
+	return ""
+}
+
+// checkTracerName flags otel.Tracer("") and names unrelated to the package path
+func checkTracerName(reporter *nolint.Reporter, pass *analysis.Pass, arg ast.Expr) {
+	tv, ok := pass.TypesInfo.Types[arg]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+
+	name := constant.StringVal(tv.Value)
+	pkgPath := pass.Pkg.Path()
+
+	if name == "" {
+		reporter.Reportf(arg.Pos(),
+			"tracer name is empty; use the instrumenting package path, e.g. %s", strconv.Quote(pkgPath))
+		return
+	}
+
+	// The module path or the package path itself are both acceptable
+	if name == pkgPath || strings.HasPrefix(pkgPath, name+"/") {
+		return
+	}
+
+	reporter.Reportf(arg.Pos(),
+		"tracer name %q does not match the package path %q; name tracers after the instrumenting package",
+		name, pkgPath)
+}
+
+// checkAttributeKey flags attribute keys that misspell a semantic convention key
+func checkAttributeKey(reporter *nolint.Reporter, pass *analysis.Pass, arg ast.Expr, keys map[string]string) {
+	tv, ok := pass.TypesInfo.Types[arg]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+
+	key := constant.StringVal(tv.Value)
+	if right, ok := keys[key]; ok {
+		reporter.Reportf(arg.Pos(),
+			"attribute key %q is not the semantic convention key; use %q", key, right)
+	}
+}
@@ -0,0 +1,14 @@
+package spanname_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/spanname"
+)
+
+func TestSpanNameAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, spanname.Analyzer, "a")
+}
@@ -0,0 +1,47 @@
+package a
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("a")
+
+var emptyTracer = otel.Tracer("") // want `tracer name is empty`
+
+var otherTracer = otel.Tracer("github.com/other/pkg") // want `tracer name "github.com/other/pkg" does not match the package path "a"`
+
+// Bad: Sprintf span name
+func GetUser(ctx context.Context, id string) {
+	_, span := tracer.Start(ctx, fmt.Sprintf("GetUser %s", id)) // want `span name is built with fmt.Sprintf`
+	defer span.End()
+}
+
+// Bad: concatenated span name
+func DeleteUser(ctx context.Context, id string) {
+	_, span := tracer.Start(ctx, "users.DeleteUser."+id) // want `span name is built with string concatenation`
+	defer span.End()
+}
+
+// Bad: not matching the convention
+func ListUsers(ctx context.Context) {
+	_, span := tracer.Start(ctx, "list all users") // want `span name "list all users" does not match the naming convention`
+	defer span.End()
+}
+
+// Good: constant conventional name with IDs in attributes
+func UpdateUser(ctx context.Context, id string) {
+	_, span := tracer.Start(ctx, "users.UpdateUser")
+	defer span.End()
+	_ = attribute.String("user.id", id)
+}
+
+// Bad: wrong semconv key
+func Record(code int) {
+	_ = attribute.Int("http.status", code) // want `attribute key "http.status" is not the semantic convention key; use "http.response.status_code"`
+	_ = attribute.Key("http.method")       // want `attribute key "http.method" is not the semantic convention key`
+	_ = attribute.Int("http.response.status_code", code)
+}
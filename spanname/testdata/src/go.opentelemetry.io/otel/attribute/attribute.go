@@ -0,0 +1,12 @@
+package attribute
+
+type Key string
+
+type KeyValue struct {
+	Key   Key
+	Value any
+}
+
+func String(k, v string) KeyValue { return KeyValue{Key: Key(k), Value: v} }
+
+func Int(k string, v int) KeyValue { return KeyValue{Key: Key(k), Value: v} }
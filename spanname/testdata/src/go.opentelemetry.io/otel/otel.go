@@ -0,0 +1,5 @@
+package otel
+
+import "go.opentelemetry.io/otel/trace"
+
+func Tracer(name string, opts ...trace.TracerOption) trace.Tracer { return nil }
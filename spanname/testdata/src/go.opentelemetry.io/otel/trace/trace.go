@@ -0,0 +1,15 @@
+package trace
+
+import "context"
+
+type Span interface {
+	End()
+}
+
+type SpanStartOption interface{}
+
+type TracerOption interface{}
+
+type Tracer interface {
+	Start(ctx context.Context, spanName string, opts ...SpanStartOption) (context.Context, Span)
+}
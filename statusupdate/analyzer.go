@@ -4,7 +4,6 @@ package statusupdate
 
 import (
 	"go/ast"
-	"go/types"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -12,6 +11,7 @@ import (
 	"golang.org/x/tools/go/ast/inspector"
 
 	"github.com/spechtlabs/golint-sl/internal/nolint"
+	"github.com/spechtlabs/golint-sl/internal/reconcile"
 )
 
 const Doc = `ensure reconcilers update Status after changes
@@ -20,6 +20,13 @@ This analyzer detects reconcilers that:
 1. Modify spec or status fields but don't call Status().Update()
 2. Create/Update resources but don't reflect state in Status
 3. Handle errors without updating Status.Conditions
+4. Return paths that change Status fields or conditions but never call
+   Status().Update() or Status().Patch() before the return
+
+Every return statement is checked against the statements that lead to it.
+A deferred status update covers all later returns. The not-found early
+return after IsNotFound, and returning ctrl.Result{Requeue: true} after
+IsConflict, are exempt.
 
 Kubernetes best practice is to always update Status to reflect current state,
 including error conditions. This allows users and other controllers to observe
@@ -28,7 +35,7 @@ the actual state of resources.`
 var Analyzer = &analysis.Analyzer{
 	Name:     "statusupdate",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -46,38 +53,17 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
-		if !isReconcileFunction(fn) {
+		if !reconcile.IsMethod(fn) {
 			return
 		}
 
 		checkReconcilerStatus(reporter, fn)
+		checkStatusPaths(reporter, fn)
 	})
 
 	return nil, nil
 }
 
-func isReconcileFunction(fn *ast.FuncDecl) bool {
-	if fn.Name == nil || fn.Name.Name != "Reconcile" {
-		return false
-	}
-
-	if fn.Recv == nil || len(fn.Recv.List) == 0 {
-		return false
-	}
-
-	recv := fn.Recv.List[0]
-	recvType := types.ExprString(recv.Type)
-
-	patterns := []string{"Reconciler", "Controller", "Operator"}
-	for _, pattern := range patterns {
-		if strings.Contains(recvType, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
 func checkReconcilerStatus(reporter *nolint.Reporter, fn *ast.FuncDecl) {
 	hasResourceMutation := false
 	hasStatusUpdate := false
@@ -214,3 +200,276 @@ func hasComplexLogic(fn *ast.FuncDecl) bool {
 
 	return complexity >= 3
 }
+
+// statusPaths walks the return paths of a reconciler and tracks whether
+// Status was changed in memory without being persisted
+type statusPaths struct {
+	reporter *nolint.Reporter
+
+	// deferred is set once a deferred status update has been seen, which
+	// covers every later return
+	deferred bool
+}
+
+// pathExemption describes the condition of the if statement a block is
+// nested in
+type pathExemption struct {
+	notFound bool
+	conflict bool
+}
+
+func checkStatusPaths(reporter *nolint.Reporter, fn *ast.FuncDecl) {
+	p := &statusPaths{reporter: reporter}
+	p.block(fn.Body.List, false, pathExemption{})
+}
+
+// block walks stmts in order, starting with dirty status, and returns whether
+// status may still be dirty at the end and whether the block always returns
+func (p *statusPaths) block(stmts []ast.Stmt, dirty bool, exempt pathExemption) (bool, bool) {
+	for _, stmt := range stmts {
+		var terminates bool
+		dirty, terminates = p.stmt(stmt, dirty, exempt)
+		if terminates {
+			return dirty, true
+		}
+	}
+	return dirty, false
+}
+
+func (p *statusPaths) stmt(stmt ast.Stmt, dirty bool, exempt pathExemption) (bool, bool) {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		p.checkReturn(s, dirty, exempt)
+		return false, true
+
+	case *ast.DeferStmt:
+		if persistsStatus(s.Call) {
+			p.deferred = true
+		}
+		return dirty, false
+
+	case *ast.BlockStmt:
+		return p.block(s.List, dirty, exempt)
+
+	case *ast.LabeledStmt:
+		return p.stmt(s.Stmt, dirty, exempt)
+
+	case *ast.IfStmt:
+		if s.Init != nil {
+			dirty = statusEffects(s.Init, dirty)
+		}
+		dirty = statusEffects(s.Cond, dirty)
+
+		inner := exempt
+		if callsFunc(s.Cond, "IsNotFound") {
+			inner.notFound = true
+		}
+		if callsFunc(s.Cond, "IsConflict") {
+			inner.conflict = true
+		}
+
+		bodyDirty, bodyReturns := p.block(s.Body.List, dirty, inner)
+		elseDirty, elseReturns := dirty, false
+		if s.Else != nil {
+			elseDirty, elseReturns = p.stmt(s.Else, dirty, exempt)
+		}
+		return mergeBranches([]bool{bodyDirty, elseDirty}, []bool{bodyReturns, elseReturns})
+
+	case *ast.ForStmt:
+		if s.Init != nil {
+			dirty = statusEffects(s.Init, dirty)
+		}
+		bodyDirty, _ := p.block(s.Body.List, dirty, exempt)
+		return dirty || bodyDirty, false
+
+	case *ast.RangeStmt:
+		bodyDirty, _ := p.block(s.Body.List, dirty, exempt)
+		return dirty || bodyDirty, false
+
+	case *ast.SwitchStmt:
+		if s.Init != nil {
+			dirty = statusEffects(s.Init, dirty)
+		}
+		return p.clauses(s.Body, dirty, exempt)
+
+	case *ast.TypeSwitchStmt:
+		if s.Init != nil {
+			dirty = statusEffects(s.Init, dirty)
+		}
+		return p.clauses(s.Body, dirty, exempt)
+
+	case *ast.SelectStmt:
+		return p.clauses(s.Body, dirty, exempt)
+	}
+
+	return statusEffects(stmt, dirty), false
+}
+
+// clauses walks every case of a switch or select statement. Without a
+// default case, control can also skip all of them.
+func (p *statusPaths) clauses(body *ast.BlockStmt, dirty bool, exempt pathExemption) (bool, bool) {
+	var dirties, returns []bool
+	hasDefault := false
+	for _, clause := range body.List {
+		var stmts []ast.Stmt
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			hasDefault = hasDefault || c.List == nil
+			stmts = c.Body
+		case *ast.CommClause:
+			hasDefault = hasDefault || c.Comm == nil
+			if c.Comm != nil {
+				dirty = statusEffects(c.Comm, dirty)
+			}
+			stmts = c.Body
+		}
+		d, r := p.block(stmts, dirty, exempt)
+		dirties = append(dirties, d)
+		returns = append(returns, r)
+	}
+	if !hasDefault {
+		dirties = append(dirties, dirty)
+		returns = append(returns, false)
+	}
+	return mergeBranches(dirties, returns)
+}
+
+// mergeBranches joins the branches that fall through: status may be dirty
+// after them if it is dirty in any, and the statement always returns only
+// if every branch does
+func mergeBranches(dirties, returns []bool) (bool, bool) {
+	dirty, terminates := false, true
+	for i := range dirties {
+		if returns[i] {
+			continue
+		}
+		terminates = false
+		dirty = dirty || dirties[i]
+	}
+	return dirty, terminates
+}
+
+func (p *statusPaths) checkReturn(ret *ast.ReturnStmt, dirty bool, exempt pathExemption) {
+	if p.deferred || !statusEffects(ret, dirty) {
+		return
+	}
+	if exempt.notFound || callsFunc(ret, "IgnoreNotFound") {
+		return
+	}
+	if exempt.conflict && requeues(ret) {
+		return
+	}
+
+	p.reporter.Reportf(ret.Pos(),
+		"Status is changed on this path but not persisted before returning; call Status().Update() or Status().Patch(), or update Status in a deferred function")
+}
+
+// statusEffects applies the status changes and updates in node, in source
+// order, to dirty. Function literals are not followed.
+func statusEffects(node ast.Node, dirty bool) bool {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if sel, ok := lhs.(*ast.SelectorExpr); ok && isStatusFieldAccess(sel) {
+					dirty = true
+				}
+			}
+		case *ast.CallExpr:
+			if persistsStatus(n) {
+				dirty = false
+				return false
+			}
+			if setsCondition(n) {
+				dirty = true
+			}
+		}
+		return true
+	})
+	return dirty
+}
+
+// persistsStatus checks for Status().Update() and Status().Patch() calls,
+// directly or inside a deferred function literal
+func persistsStatus(call *ast.CallExpr) bool {
+	if lit, ok := call.Fun.(*ast.FuncLit); ok {
+		found := false
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			if c, ok := n.(*ast.CallExpr); ok && persistsStatus(c) {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Update" && sel.Sel.Name != "Patch") {
+		return false
+	}
+	inner, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+	return ok && innerSel.Sel.Name == "Status"
+}
+
+// setsCondition checks for SetStatusCondition and the condition helpers
+// that write to Status.Conditions
+func setsCondition(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "SetStatusCondition", "RemoveStatusCondition":
+		return true
+	}
+	return false
+}
+
+// callsFunc checks if node calls a function or method named name
+func callsFunc(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			found = found || fun.Name == name
+		case *ast.SelectorExpr:
+			found = found || fun.Sel.Name == name
+		}
+		return !found
+	})
+	return found
+}
+
+// requeues checks if ret returns a Result literal with Requeue: true
+func requeues(ret *ast.ReturnStmt) bool {
+	for _, result := range ret.Results {
+		lit, ok := result.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok || key.Name != "Requeue" {
+				continue
+			}
+			if value, ok := kv.Value.(*ast.Ident); ok && value.Name == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
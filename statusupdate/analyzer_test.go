@@ -0,0 +1,19 @@
+package statusupdate_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/statusupdate"
+)
+
+func TestStatusUpdateNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, statusupdate.Analyzer, "nolint")
+}
+
+func TestStatusUpdatePaths(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, statusupdate.Analyzer, "paths")
+}
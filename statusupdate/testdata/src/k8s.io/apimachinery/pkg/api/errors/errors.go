@@ -0,0 +1,5 @@
+package errors
+
+func IsNotFound(err error) bool { return false }
+
+func IsConflict(err error) bool { return false }
@@ -0,0 +1,8 @@
+package meta
+
+type Condition struct {
+	Type   string
+	Status string
+}
+
+func SetStatusCondition(conditions *[]Condition, condition Condition) {}
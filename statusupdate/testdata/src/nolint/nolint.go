@@ -0,0 +1,27 @@
+package nolint
+
+import "context"
+
+type Object struct{}
+
+type Client interface {
+	Update(ctx context.Context, obj *Object) error
+}
+
+type FooReconciler struct {
+	client Client
+}
+
+// Reported: mutates without updating status
+func (r *FooReconciler) Reconcile(ctx context.Context, obj *Object) error { // want `doesn't update Status;`
+	return r.client.Update(ctx, obj)
+}
+
+type BarReconciler struct {
+	client Client
+}
+
+// Suppressed inline
+func (r *BarReconciler) Reconcile(ctx context.Context, obj *Object) error { //nolint:statusupdate // status is owned by another controller
+	return r.client.Update(ctx, obj)
+}
@@ -0,0 +1,125 @@
+package paths
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type WidgetStatus struct {
+	Phase      string
+	Conditions []meta.Condition
+}
+
+type Widget struct {
+	Status WidgetStatus
+}
+
+func deploy(ctx context.Context, w *Widget) error { return nil }
+
+// The deferred status update covers every return after it
+type DeferredReconciler struct {
+	client.Client
+}
+
+func (r *DeferredReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	w := &Widget{}
+	if err := r.Get(ctx, req.NamespacedName, w); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	defer func() {
+		_ = r.Status().Update(ctx, w)
+	}()
+
+	if err := deploy(ctx, w); err != nil {
+		w.Status.Phase = "Failed"
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&w.Status.Conditions, meta.Condition{Type: "Ready", Status: "True"})
+	return ctrl.Result{}, nil
+}
+
+// The error branch sets the phase but returns before persisting it
+type LeakyReconciler struct {
+	client.Client
+}
+
+func (r *LeakyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	w := &Widget{}
+	if err := r.Get(ctx, req.NamespacedName, w); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	w.Status.Phase = "Deploying"
+	if err := deploy(ctx, w); err != nil {
+		w.Status.Phase = "Failed"
+		return ctrl.Result{}, err // want `Status is changed on this path but not persisted before returning`
+	}
+
+	meta.SetStatusCondition(&w.Status.Conditions, meta.Condition{Type: "Ready", Status: "True"})
+	if err := r.Status().Update(ctx, w); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// Every return that follows a status change persists it first
+type ExplicitReconciler struct {
+	client.Client
+}
+
+func (r *ExplicitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	w := &Widget{}
+	if err := r.Get(ctx, req.NamespacedName, w); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if err := deploy(ctx, w); err != nil {
+		w.Status.Phase = "Failed"
+		_ = r.Status().Update(ctx, w)
+		return ctrl.Result{}, err
+	}
+
+	switch w.Status.Phase {
+	case "":
+		w.Status.Phase = "Pending"
+	default:
+		meta.SetStatusCondition(&w.Status.Conditions, meta.Condition{Type: "Ready", Status: "True"})
+	}
+	return ctrl.Result{}, r.Status().Patch(ctx, w, nil)
+}
+
+// A condition set in one switch case is still unsaved after the switch
+type SwitchReconciler struct {
+	client.Client
+}
+
+func (r *SwitchReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	w := &Widget{}
+	if err := r.Get(ctx, req.NamespacedName, w); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	switch w.Status.Phase {
+	case "":
+		meta.SetStatusCondition(&w.Status.Conditions, meta.Condition{Type: "Ready", Status: "False"})
+	case "Failed":
+		return ctrl.Result{}, nil
+	}
+
+	if err := deploy(ctx, w); err != nil {
+		return ctrl.Result{}, err // want `Status is changed on this path but not persisted before returning`
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, w)
+}
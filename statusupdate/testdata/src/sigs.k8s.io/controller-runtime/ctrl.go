@@ -0,0 +1,11 @@
+package ctrl
+
+import "sigs.k8s.io/controller-runtime/pkg/client"
+
+type Request struct {
+	NamespacedName client.ObjectKey
+}
+
+type Result struct {
+	Requeue bool
+}
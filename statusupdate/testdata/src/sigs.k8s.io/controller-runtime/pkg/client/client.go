@@ -0,0 +1,23 @@
+package client
+
+import "context"
+
+type Object interface{}
+
+type ObjectKey struct{ Namespace, Name string }
+
+type Patch interface{}
+
+type StatusWriter interface {
+	Update(ctx context.Context, obj Object) error
+	Patch(ctx context.Context, obj Object, patch Patch) error
+}
+
+type Client interface {
+	Get(ctx context.Context, key ObjectKey, obj Object) error
+	Create(ctx context.Context, obj Object) error
+	Update(ctx context.Context, obj Object) error
+	Status() StatusWriter
+}
+
+func IgnoreNotFound(err error) error { return err }
@@ -0,0 +1,472 @@
+// Package structtags provides an analyzer that checks the json, yaml, and
+// mapstructure tags of exported structs for consistency.
+//
+// API and config structs are read by tools and people in other languages,
+// so a field without a tag, a camelCase name among snake_case siblings, or
+// a yaml tag that disagrees with the json tag breaks clients in ways the
+// compiler never sees.
+package structtags
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check json, yaml, and mapstructure tags of exported structs for consistency
+
+In exported struct types:
+1. When any field has a json, yaml, or mapstructure tag, every exported
+   field needs one
+2. Tag names follow the naming convention, snake_case by default
+3. The tags of a field agree on its name, ignoring the case convention,
+   so json:"user_name" and yaml:"userName" agree
+4. Fields marked validate:"required" are not omitempty
+
+Embedded fields, inlined fields (inline, squash), and fields tagged "-"
+are skipped. Diagnostics suggest the
+expected tag and, for raw string tags, a fix.
+
+Flags:
+    -naming  naming convention of tag names: snake_case, camelCase,
+             kebab-case, or PascalCase (default snake_case)
+    -keys    comma-separated tag keys to check (default json,yaml,mapstructure)`
+
+var (
+	naming string
+	keys   string
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "structtags",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("structtags", flag.ExitOnError)
+	fs.StringVar(&naming, "naming", "snake_case",
+		"naming convention of tag names: snake_case, camelCase, kebab-case, or PascalCase")
+	fs.StringVar(&keys, "keys", "json,yaml,mapstructure",
+		"comma-separated tag keys to check")
+	return *fs
+}
+
+// convention is a naming convention for tag names
+type convention struct {
+	name    string
+	pattern *regexp.Regexp
+	join    func(words []string) string
+}
+
+var conventions = map[string]convention{
+	"snake_case": {
+		pattern: regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+		join:    func(words []string) string { return strings.ToLower(strings.Join(words, "_")) },
+	},
+	"kebab-case": {
+		pattern: regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+		join:    func(words []string) string { return strings.ToLower(strings.Join(words, "-")) },
+	},
+	"camelCase": {
+		pattern: regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+		join: func(words []string) string {
+			return strings.ToLower(words[0]) + titleWords(words[1:])
+		},
+	},
+	"PascalCase": {
+		pattern: regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+		join:    titleWords,
+	},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	conv, ok := conventions[naming]
+	if !ok {
+		return nil, fmt.Errorf("structtags: invalid -naming %q: want snake_case, camelCase, kebab-case, or PascalCase", naming)
+	}
+	conv.name = naming
+
+	var tagKeys []string
+	for _, key := range strings.Split(keys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			tagKeys = append(tagKeys, key)
+		}
+	}
+
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		spec := n.(*ast.TypeSpec)
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok || !spec.Name.IsExported() {
+			return
+		}
+		checkStruct(reporter, spec.Name.Name, st, tagKeys, conv)
+	})
+
+	return nil, nil
+}
+
+// field is an exported, named struct field with its parsed tag
+type field struct {
+	name  string
+	decl  *ast.Field
+	tags  map[string]tagEntry
+	owner string
+}
+
+// checkStruct applies the rules to the exported fields of st
+func checkStruct(reporter *nolint.Reporter, owner string, st *ast.StructType, tagKeys []string, conv convention) {
+	var fields []field
+	used := make(map[string]bool) // keys any field has
+	for _, decl := range st.Fields.List {
+		// Embedded and inlined fields are flattened by the encoders
+		if len(decl.Names) == 0 {
+			continue
+		}
+		tags := make(map[string]tagEntry)
+		inline := false
+		for _, entry := range parseTag(decl.Tag) {
+			tags[entry.key] = entry
+			inline = inline || isInline(entry)
+		}
+		if inline {
+			continue
+		}
+		for _, key := range tagKeys {
+			if _, ok := tags[key]; ok {
+				used[key] = true
+			}
+		}
+		for _, name := range decl.Names {
+			if name.IsExported() {
+				fields = append(fields, field{name: name.Name, decl: decl, tags: tags, owner: owner})
+			}
+		}
+	}
+	if len(used) == 0 {
+		return
+	}
+
+	for _, f := range fields {
+		checkMissing(reporter, f, tagKeys, used, conv)
+		checkNames(reporter, f, tagKeys, conv)
+		checkRequired(reporter, f, tagKeys)
+	}
+}
+
+// checkMissing reports f if it lacks a tag key some sibling has
+func checkMissing(reporter *nolint.Reporter, f field, tagKeys []string, used map[string]bool, conv convention) {
+	var missing []string
+	for _, key := range tagKeys {
+		if _, ok := f.tags[key]; used[key] && !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	// Use the name the other tags give the field, in the convention
+	expected := conv.join(splitWords(f.name))
+	for _, key := range tagKeys {
+		if entry, ok := f.tags[key]; ok && entry.name() != "" && entry.name() != "-" {
+			expected = conv.join(splitWords(entry.name()))
+			break
+		}
+	}
+
+	var add []string
+	for _, key := range missing {
+		add = append(add, fmt.Sprintf("%s:%q", key, expected))
+	}
+	addText := strings.Join(add, " ")
+
+	noun := "tag"
+	if len(missing) > 1 {
+		noun = "tags"
+	}
+	diag := &analysis.Diagnostic{
+		Pos: f.decl.Pos(),
+		Message: fmt.Sprintf("exported field %s.%s is missing the %s %s that other fields of %s have; add %s",
+			f.owner, f.name, strings.Join(missing, " and "), noun, f.owner, addText),
+	}
+
+	switch {
+	case len(f.decl.Names) > 1:
+		// A tag added to A, B string would name both fields the same
+	case f.decl.Tag == nil:
+		diag.SuggestedFixes = fix("Add "+addText, f.decl.Type.End(), f.decl.Type.End(), " `"+addText+"`")
+	case isRaw(f.decl.Tag):
+		end := f.decl.Tag.End() - 1
+		if len(f.decl.Tag.Value) > 2 {
+			addText = " " + addText
+		}
+		diag.SuggestedFixes = fix("Add "+strings.TrimSpace(addText), end, end, addText)
+	}
+
+	reporter.Report(diag)
+}
+
+// checkNames reports tag names that break the convention or disagree with
+// the name the first tag gives the field
+func checkNames(reporter *nolint.Reporter, f field, tagKeys []string, conv convention) {
+	var first *tagEntry
+	for _, key := range tagKeys {
+		entry, ok := f.tags[key]
+		if !ok || entry.name() == "-" {
+			continue
+		}
+		name := entry.name()
+		if name == "" {
+			name = defaultName(key, f.name)
+		}
+
+		if first != nil && normalize(name) != normalize(first.effectiveName(f.name)) {
+			expected := conv.join(splitWords(first.effectiveName(f.name)))
+			reporter.Report(&analysis.Diagnostic{
+				Pos: f.decl.Pos(),
+				Message: fmt.Sprintf("%s tag %q of field %s.%s does not match its %s tag %q; use %q for both",
+					key, name, f.owner, f.name, first.key, first.effectiveName(f.name), expected),
+				SuggestedFixes: renameFix(entry, expected),
+			})
+			continue
+		}
+
+		if !conv.pattern.MatchString(name) {
+			expected := conv.join(splitWords(name))
+			reporter.Report(&analysis.Diagnostic{
+				Pos: f.decl.Pos(),
+				Message: fmt.Sprintf("%s tag %q of field %s.%s is not %s; use %q",
+					key, name, f.owner, f.name, conv.name, expected),
+				SuggestedFixes: renameFix(entry, expected),
+			})
+		}
+
+		if first == nil {
+			first = &entry
+		}
+	}
+}
+
+// checkRequired reports omitempty on fields marked validate:"required"
+func checkRequired(reporter *nolint.Reporter, f field, tagKeys []string) {
+	validate, ok := f.tags["validate"]
+	if !ok || !hasOption(validate.value, "required") {
+		return
+	}
+
+	for _, key := range tagKeys {
+		entry, ok := f.tags[key]
+		if !ok {
+			continue
+		}
+		_, opts, _ := strings.Cut(entry.value, ",")
+		if !hasOption(opts, "omitempty") {
+			continue
+		}
+
+		diag := &analysis.Diagnostic{
+			Pos: f.decl.Pos(),
+			Message: fmt.Sprintf("field %s.%s is validate:\"required\", but its %s tag has omitempty, which drops the value when it is empty; remove omitempty",
+				f.owner, f.name, key),
+		}
+		if entry.pos.IsValid() {
+			at := strings.Index(entry.value+",", ",omitempty,")
+			start := entry.pos + token.Pos(at)
+			diag.SuggestedFixes = fix("Remove omitempty", start, start+token.Pos(len(",omitempty")), "")
+		}
+		reporter.Report(diag)
+	}
+}
+
+// tagEntry is a key:"value" pair of a struct tag
+type tagEntry struct {
+	key, value string
+	pos        token.Pos // start of the value, or NoPos if it cannot be edited
+}
+
+// name returns the name part of the value, before any options
+func (e tagEntry) name() string {
+	name, _, _ := strings.Cut(e.value, ",")
+	return name
+}
+
+// effectiveName returns the name the encoder uses for field
+func (e tagEntry) effectiveName(field string) string {
+	if name := e.name(); name != "" {
+		return name
+	}
+	return defaultName(e.key, field)
+}
+
+// isInline checks if entry inlines the field into its parent, where its
+// name is not used
+func isInline(e tagEntry) bool {
+	_, opts, _ := strings.Cut(e.value, ",")
+	return hasOption(opts, "inline") || hasOption(opts, "squash") || hasOption(opts, "remain")
+}
+
+// defaultName returns the name an encoder uses for a field without a name in
+// its tag: yaml lowercases it, the others use it as is
+func defaultName(key, field string) string {
+	if key == "yaml" {
+		return strings.ToLower(field)
+	}
+	return field
+}
+
+// parseTag splits a struct tag into its entries, following the convention
+// of reflect.StructTag. Values can only be edited in raw string tags without
+// escapes.
+func parseTag(lit *ast.BasicLit) []tagEntry {
+	if lit == nil {
+		return nil
+	}
+	tag, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+	raw := isRaw(lit)
+
+	var entries []tagEntry
+	offset := 0
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag, offset = tag[i:], offset+i
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag, offset = tag[i+1:], offset+i+1
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quoted := tag[:i+1]
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			break
+		}
+
+		entry := tagEntry{key: key, value: value}
+		if raw && !strings.Contains(quoted, `\`) {
+			entry.pos = lit.Pos() + 1 + token.Pos(offset) + 1
+		}
+		entries = append(entries, entry)
+		tag, offset = tag[i+1:], offset+i+1
+	}
+	return entries
+}
+
+// isRaw checks if lit is a raw string literal
+func isRaw(lit *ast.BasicLit) bool {
+	return strings.HasPrefix(lit.Value, "`")
+}
+
+// hasOption checks if the comma-separated list opts contains option
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// renameFix returns a fix replacing the name in entry, if it can be edited
+func renameFix(entry tagEntry, name string) []analysis.SuggestedFix {
+	if !entry.pos.IsValid() {
+		return nil
+	}
+	return fix(fmt.Sprintf("Rename to %q", name), entry.pos, entry.pos+token.Pos(len(entry.name())), name)
+}
+
+// fix returns a suggested fix with a single edit
+func fix(message string, pos, end token.Pos, text string) []analysis.SuggestedFix {
+	return []analysis.SuggestedFix{{
+		Message:   message,
+		TextEdits: []analysis.TextEdit{{Pos: pos, End: end, NewText: []byte(text)}},
+	}}
+}
+
+// splitWords splits a Go identifier or tag name into words at underscores,
+// hyphens, and case changes, keeping initialisms together: UserID is User
+// and ID, HTTPServer is HTTP and Server
+func splitWords(s string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			next := rune(0)
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+			lowerToUpper := !unicode.IsUpper(prev) && unicode.IsUpper(cur)
+			endOfInitialism := unicode.IsUpper(prev) && unicode.IsUpper(cur) && unicode.IsLower(next)
+			if lowerToUpper || endOfInitialism {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	if len(words) == 0 {
+		return []string{s}
+	}
+	return words
+}
+
+// titleWords joins words with each one capitalized and the rest lowercase
+func titleWords(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		lower := strings.ToLower(word)
+		b.WriteString(strings.ToUpper(lower[:1]) + lower[1:])
+	}
+	return b.String()
+}
+
+// normalize returns name without case or separators, so names in different
+// conventions compare equal
+func normalize(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), ""))
+}
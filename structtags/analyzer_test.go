@@ -0,0 +1,29 @@
+package structtags_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/structtags"
+)
+
+func TestStructTags(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, structtags.Analyzer, "a")
+}
+
+func TestStructTagsFlags(t *testing.T) {
+	for name, value := range map[string]string{"naming": "camelCase", "keys": "json,yaml"} {
+		if err := structtags.Analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		_ = structtags.Analyzer.Flags.Set("naming", "snake_case")
+		_ = structtags.Analyzer.Flags.Set("keys", "json,yaml,mapstructure")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, structtags.Analyzer, "camel")
+}
@@ -0,0 +1,67 @@
+package a
+
+// Good: consistent snake_case json and yaml tags
+type Config struct {
+	Name     string `json:"name" yaml:"name"`
+	UserID   string `json:"user_id" yaml:"user_id"`
+	Internal string `json:"-" yaml:"-"`
+	private  string
+}
+
+// Missing tags
+type Server struct {
+	Host    string `json:"host"`
+	Port    int    // want `exported field Server.Port is missing the json tag that other fields of Server have; add json:"port"`
+	MaxConn int    `json:"max_conn,omitempty"`
+}
+
+// Missing both tags
+type Database struct {
+	URL        string `json:"url" yaml:"url"`
+	PoolSize   int    // want `exported field Database.PoolSize is missing the json and yaml tags that other fields of Database have; add json:"pool_size" yaml:"pool_size"`
+	A, B       string // want `exported field Database.A is missing the json and yaml tags` `exported field Database.B is missing the json and yaml tags`
+	ReadOnly   bool   `json:"read_only" yaml:"read_only"`
+	TimeoutSec int    `json:"timeout_sec" yaml:"timeout_sec"`
+}
+
+// Naming convention
+type User struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`           // want `json tag "firstName" of field User.FirstName is not snake_case; use "first_name"`
+	LastName  string `json:",omitempty"`          // want `json tag "LastName" of field User.LastName is not snake_case; use "last_name"`
+	HTTPProxy string `json:"HTTPProxy,omitempty"` // want `json tag "HTTPProxy" of field User.HTTPProxy is not snake_case; use "http_proxy"`
+}
+
+// json and yaml disagree
+type Cluster struct {
+	Region string `json:"region" yaml:"zone"` // want `yaml tag "zone" of field Cluster.Region does not match its json tag "region"; use "region" for both`
+	// Agree modulo the case convention, but yaml breaks it
+	NodeCount int `json:"node_count" yaml:"nodeCount"` // want `yaml tag "nodeCount" of field Cluster.NodeCount is not snake_case; use "node_count"`
+	// yaml defaults to the lowercased field name
+	Version string `json:"version" yaml:",omitempty"`
+}
+
+// Required fields must not be omitempty
+type Request struct {
+	Name  string `json:"name,omitempty" validate:"required"` // want `field Request.Name is validate:"required", but its json tag has omitempty`
+	Email string `json:"email" validate:"required,email"`
+	Note  string `json:"note,omitempty" validate:"max=100"`
+}
+
+// Inlined fields are not named
+type Wrapper struct {
+	Name   string `json:"name" yaml:"name" mapstructure:"name"`
+	Meta   Config `json:"meta" yaml:",inline"`
+	Config Config `mapstructure:",squash"`
+}
+
+// Unexported structs are not checked
+type settings struct {
+	Name string `json:"name"`
+	Port int
+}
+
+// Structs without tags are not checked
+type Point struct {
+	X, Y int
+}
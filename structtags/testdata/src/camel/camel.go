@@ -0,0 +1,11 @@
+package camel
+
+type Profile struct {
+	UserID    string `json:"userId"`
+	HTTPProxy string `json:"http_proxy"` // want `json tag "http_proxy" of field Profile.HTTPProxy is not camelCase; use "httpProxy"`
+	Email     string `json:"email"`
+	// mapstructure is not checked with -keys=json,yaml
+	Token string `json:"token" mapstructure:"TOKEN"`
+	// Tags that are not raw strings are checked without a fix
+	Region string "json:\"Region\"" // want `json tag "Region" of field Profile.Region is not camelCase; use "region"`
+}
@@ -3,6 +3,7 @@
 package syncaccess
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
@@ -12,6 +13,7 @@ import (
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
 
+	"github.com/spechtlabs/golint-sl/internal/goversion"
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
 
@@ -22,6 +24,16 @@ This analyzer detects:
 2. Struct fields accessed in goroutines without mutex protection
 3. Maps accessed concurrently without sync.Map or mutex
 4. Channels that may deadlock (unbuffered with no receiver)
+5. Lock or RLock without a matching Unlock or RUnlock in the same method
+6. Fields accessed before the method takes the receiver's lock
+7. Methods that only read fields but take the write lock of an RWMutex
+8. Map fields, and slice fields appended to, that several methods access
+   without the struct's lock, on types used concurrently: their methods
+   start goroutines touching the receiver, or they are HTTP handlers
+
+Loop variables captured by goroutines are reported only for code checked
+against Go versions before 1.22, which share one variable across
+iterations.
 
 Data races cause unpredictable behavior and are hard to debug.
 Use proper synchronization:
@@ -52,7 +64,7 @@ Use proper synchronization:
 var Analyzer = &analysis.Analyzer{
 	Name:     "syncaccess",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
@@ -74,13 +86,15 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		switch node := n.(type) {
 		case *ast.FuncDecl:
 			currentFunc = node
-			checkMutexUsage(reporter, node, structsWithMutex)
+			checkMutexUsage(reporter, pass, node, structsWithMutex)
 
 		case *ast.GoStmt:
-			checkGoroutineCaptures(reporter, node, currentFunc)
+			checkGoroutineCaptures(reporter, pass, node, currentFunc)
 		}
 	})
 
+	checkSharedFields(reporter, pass, inspect)
+
 	return nil, nil
 }
 
@@ -116,7 +130,7 @@ func findStructsWithMutex(pass *analysis.Pass) map[string]bool {
 }
 
 // checkGoroutineCaptures checks for variables captured by goroutines
-func checkGoroutineCaptures(reporter *nolint.Reporter, goStmt *ast.GoStmt, currentFunc *ast.FuncDecl) {
+func checkGoroutineCaptures(reporter *nolint.Reporter, pass *analysis.Pass, goStmt *ast.GoStmt, currentFunc *ast.FuncDecl) {
 	funcLit, ok := goStmt.Call.Fun.(*ast.FuncLit)
 	if !ok {
 		return
@@ -130,13 +144,24 @@ func checkGoroutineCaptures(reporter *nolint.Reporter, goStmt *ast.GoStmt, curre
 
 	// Check for problematic captures
 	for varName, varInfo := range capturedVars {
+		// Variables declared inside the goroutine share only the name
+		v := capturedVar(pass, funcLit, varName)
+		if v == nil {
+			continue
+		}
+		varInfo.isProtected = isProtected(pass, funcLit, goStmt, currentFunc, v)
+
 		// Skip channels - they are inherently thread-safe in Go
 		if varInfo.isChannel {
 			continue
 		}
 
-		// Check if it's a loop variable (common bug)
+		// Check if it's a loop variable (common bug). Since Go 1.22 each
+		// iteration has its own, so capturing it is safe
 		if isLoopVariable(currentFunc, varName, goStmt) {
+			if goversion.AtLeast(pass, goStmt.Pos(), goversion.LoopVar) {
+				continue
+			}
 			reporter.Reportf(varInfo.pos,
 				"loop variable %q captured by goroutine; this may cause unexpected behavior - pass as parameter instead",
 				varName)
@@ -278,6 +303,189 @@ func findCapturedVars(funcLit *ast.FuncLit, parentVars map[string]varInfo) map[s
 	return captured
 }
 
+// isProtected checks if every use of the captured variable v in the
+// goroutine is synchronized: the variable is a synchronization primitive
+// itself, or each use goes through sync/atomic, a channel operation, or
+// once.Do. Maps that the goroutine only reads and the parent no longer
+// writes after starting it are protected too.
+func isProtected(pass *analysis.Pass, funcLit *ast.FuncLit, goStmt *ast.GoStmt, fn *ast.FuncDecl, v *types.Var) bool {
+	uses := usesOf(pass, funcLit.Body, v)
+	if len(uses) == 0 {
+		return false
+	}
+
+	if isSyncPrimitive(v.Type()) {
+		return true
+	}
+
+	synchronized := true
+	for _, path := range uses {
+		if !usedAtomically(pass, path) && !usedOnChannel(path) && !usedInOnce(pass, path) {
+			synchronized = false
+			break
+		}
+	}
+	if synchronized {
+		return true
+	}
+
+	if _, isMap := v.Type().Underlying().(*types.Map); !isMap {
+		return false
+	}
+	for _, path := range uses {
+		if writesMap(path) {
+			return false
+		}
+	}
+	return !writesMapAfter(pass, fn.Body, v, goStmt.End())
+}
+
+// capturedVar returns the variable called name that funcLit uses but that
+// is declared outside of it, or nil
+func capturedVar(pass *analysis.Pass, funcLit *ast.FuncLit, name string) *types.Var {
+	var captured *types.Var
+	ast.Inspect(funcLit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || captured != nil || ident.Name != name {
+			return captured == nil
+		}
+		v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if ok && (v.Pos() < funcLit.Pos() || v.Pos() >= funcLit.End()) {
+			captured = v
+		}
+		return false
+	})
+	return captured
+}
+
+// usesOf returns the path from body to each use of v. Identifiers that
+// only share its name, like the v in switch v := v.(type), are skipped.
+func usesOf(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var) [][]ast.Node {
+	var uses [][]ast.Node
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		if ident, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[ident] == v {
+			uses = append(uses, append([]ast.Node(nil), stack...))
+		}
+		return true
+	})
+	return uses
+}
+
+// isSyncPrimitive checks for types that are safe to share between
+// goroutines: channels and the types of sync and sync/atomic
+func isSyncPrimitive(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if _, ok := t.Underlying().(*types.Chan); ok {
+		return true
+	}
+	return isSafeUnlocked(t)
+}
+
+// usedAtomically checks if the use at the end of path is an argument of a
+// sync/atomic function, like atomic.AddInt64(&n, 1)
+func usedAtomically(pass *analysis.Pass, path []ast.Node) bool {
+	for i := len(path) - 2; i >= 0; i-- {
+		call, ok := path[i].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || path[i+1] == call.Fun {
+			return false
+		}
+		fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+		return ok && fn.Pkg() != nil && fn.Pkg().Path() == "sync/atomic"
+	}
+	return false
+}
+
+// usedOnChannel checks if the use at the end of path is a channel send or
+// receive, or the value sent
+func usedOnChannel(path []ast.Node) bool {
+	if len(path) < 2 {
+		return false
+	}
+	switch parent := path[len(path)-2].(type) {
+	case *ast.SendStmt:
+		return true
+	case *ast.UnaryExpr:
+		return parent.Op == token.ARROW
+	}
+	return false
+}
+
+// usedInOnce checks if the use at the end of path is inside a function
+// passed to once.Do
+func usedInOnce(pass *analysis.Pass, path []ast.Node) bool {
+	for i := len(path) - 2; i > 0; i-- {
+		if _, ok := path[i].(*ast.FuncLit); !ok {
+			continue
+		}
+		call, ok := path[i-1].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Do" && isSyncType(pass.TypesInfo.TypeOf(sel.X), "Once") {
+			return true
+		}
+	}
+	return false
+}
+
+// writesMap checks if the use at the end of path modifies the map, like
+// m[k] = v, m[k]++ or delete(m, k)
+func writesMap(path []ast.Node) bool {
+	if len(path) < 2 {
+		return false
+	}
+	ident := path[len(path)-1]
+	switch parent := path[len(path)-2].(type) {
+	case *ast.IndexExpr:
+		if len(path) < 3 || parent.X != ident {
+			return false
+		}
+		switch stmt := path[len(path)-3].(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				if lhs == parent {
+					return true
+				}
+			}
+		case *ast.IncDecStmt:
+			return true
+		}
+	case *ast.CallExpr:
+		if fun, ok := parent.Fun.(*ast.Ident); ok && (fun.Name == "delete" || fun.Name == "clear") {
+			return true
+		}
+	case *ast.AssignStmt:
+		for _, lhs := range parent.Lhs {
+			if lhs == ident {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writesMapAfter checks if body modifies the map v after pos
+func writesMapAfter(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var, pos token.Pos) bool {
+	for _, path := range usesOf(pass, body, v) {
+		if path[len(path)-1].Pos() >= pos && writesMap(path) {
+			return true
+		}
+	}
+	return false
+}
+
 // isLoopVariable checks if a variable is a loop iteration variable
 func isLoopVariable(fn *ast.FuncDecl, varName string, goStmt *ast.GoStmt) bool {
 	if fn == nil || fn.Body == nil {
@@ -337,9 +545,26 @@ func containsNode(parent ast.Node, target ast.Node) bool {
 	return found
 }
 
-// checkMutexUsage checks that struct methods use mutex properly
-func checkMutexUsage(reporter *nolint.Reporter, fn *ast.FuncDecl, structsWithMutex map[string]bool) {
-	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+// lockCall is a call to a method of a sync.Mutex or sync.RWMutex
+type lockCall struct {
+	call   *ast.CallExpr
+	recv   ast.Expr
+	mutex  string // the mutex expression, like c.mu
+	method string // Lock, RLock, Unlock or RUnlock
+	rw     bool   // the mutex is a sync.RWMutex
+}
+
+// unlockFor maps lock methods to the methods that release them
+var unlockFor = map[string]string{
+	"Lock":  "Unlock",
+	"RLock": "RUnlock",
+}
+
+// checkMutexUsage checks that struct methods use mutex properly. It walks
+// the method in statement order, so accesses before the first lock and
+// locks without a matching unlock are found as well as missing locks.
+func checkMutexUsage(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, structsWithMutex map[string]bool) {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 || fn.Body == nil {
 		return
 	}
 
@@ -355,40 +580,72 @@ func checkMutexUsage(reporter *nolint.Reporter, fn *ast.FuncDecl, structsWithMut
 	}
 
 	// Check if this type has a mutex
-	if !structsWithMutex[recvType] {
+	if !structsWithMutex[recvType] || len(fn.Recv.List[0].Names) == 0 {
 		return
 	}
+	recvName := fn.Recv.List[0].Names[0].Name
 
-	// Check if the method accesses fields without locking
-	hasLock := false
+	var locks []lockCall
+	unlocked := make(map[string]bool)
+	var accessBeforeLock *ast.SelectorExpr
 	hasFieldAccess := false
+	writes := false
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.CallExpr:
-			// Check for Lock() call
-			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
-				if sel.Sel.Name == "Lock" || sel.Sel.Name == "RLock" {
-					hasLock = true
+			if lc, ok := mutexCall(pass, node); ok && ownsMutex(pass, lc.recv, recvName) {
+				if _, isLock := unlockFor[lc.method]; isLock {
+					locks = append(locks, lc)
+				} else {
+					unlocked[lc.mutex+"."+lc.method] = true
+				}
+				return true
+			}
+			// Method calls on the receiver or its fields, and calls taking
+			// them as arguments, may modify them
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && (isIdent(sel.X, recvName) || rootedAt(sel.X, recvName)) {
+				writes = true
+			}
+			for _, arg := range node.Args {
+				if rootedAt(arg, recvName) {
+					writes = true
 				}
 			}
 
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if rootedAt(lhs, recvName) {
+					writes = true
+				}
+			}
+
+		case *ast.IncDecStmt:
+			if rootedAt(node.X, recvName) {
+				writes = true
+			}
+
+		case *ast.UnaryExpr:
+			if node.Op == token.AND && rootedAt(node.X, recvName) {
+				writes = true
+			}
+
 		case *ast.SelectorExpr:
 			// Check for field access on receiver
-			if ident, ok := node.X.(*ast.Ident); ok {
-				// Check if it's the receiver
-				if fn.Recv != nil && len(fn.Recv.List) > 0 {
-					if len(fn.Recv.List[0].Names) > 0 {
-						recvName := fn.Recv.List[0].Names[0].Name
-						if ident.Name == recvName {
-							// Skip mutex field itself
-							if node.Sel.Name != "mu" && node.Sel.Name != "mutex" &&
-								!strings.Contains(strings.ToLower(node.Sel.Name), "mutex") {
-								hasFieldAccess = true
-							}
-						}
-					}
-				}
+			ident, ok := node.X.(*ast.Ident)
+			if !ok || ident.Name != recvName || isMutex(pass.TypesInfo.TypeOf(node)) {
+				return true
+			}
+			// Skip mutex field itself
+			if node.Sel.Name == "mu" || node.Sel.Name == "mutex" ||
+				strings.Contains(strings.ToLower(node.Sel.Name), "mutex") {
+				return true
+			}
+			hasFieldAccess = true
+			selection, ok := pass.TypesInfo.Selections[node]
+			if ok && selection.Kind() == types.FieldVal && !isSafeUnlocked(selection.Type()) &&
+				len(locks) == 0 && accessBeforeLock == nil {
+				accessBeforeLock = node
 			}
 		}
 
@@ -396,9 +653,435 @@ func checkMutexUsage(reporter *nolint.Reporter, fn *ast.FuncDecl, structsWithMut
 	})
 
 	// If there's field access but no lock, warn
-	if hasFieldAccess && !hasLock {
-		reporter.Reportf(fn.Pos(),
-			"method %q on type with mutex accesses fields without Lock(); consider adding synchronization",
-			fn.Name.Name)
+	if len(locks) == 0 {
+		if hasFieldAccess {
+			reporter.Reportf(fn.Pos(),
+				"method %q on type with mutex accesses fields without Lock(); consider adding synchronization",
+				fn.Name.Name)
+		}
+		return
+	}
+
+	// Methods named like removeLocked expect the caller to hold the lock
+	if accessBeforeLock != nil && !strings.HasSuffix(fn.Name.Name, "Locked") {
+		reporter.Reportf(accessBeforeLock.Pos(),
+			"%s is accessed before %s.%s() in %q; move the access after the lock",
+			types.ExprString(accessBeforeLock), locks[0].mutex, locks[0].method, fn.Name.Name)
+	}
+
+	// Methods like Lock() or lockAll() hand the held lock to their caller
+	if !strings.HasSuffix(strings.ToLower(fn.Name.Name), "lock") {
+		reported := make(map[string]bool)
+		for _, lc := range locks {
+			unlock := unlockFor[lc.method]
+			key := lc.mutex + "." + unlock
+			if unlocked[key] || reported[key] {
+				continue
+			}
+			reported[key] = true
+			reporter.Reportf(lc.call.Pos(),
+				"%s.%s() has no matching %s.%s() in %q; add defer %s.%s() right after locking",
+				lc.mutex, lc.method, lc.mutex, unlock, fn.Name.Name, lc.mutex, unlock)
+		}
+	}
+
+	if writes {
+		return
+	}
+	for _, lc := range locks {
+		if lc.rw && lc.method == "Lock" {
+			reporter.Report(&analysis.Diagnostic{
+				Pos:      lc.call.Pos(),
+				Category: nolint.CategoryAdvisory,
+				Message: fmt.Sprintf("%q only reads fields but takes %s.Lock(); use %s.RLock() so readers don't block each other",
+					fn.Name.Name, lc.mutex, lc.mutex),
+			})
+			return
+		}
+	}
+}
+
+// method is a method declaration with the object of its receiver
+type method struct {
+	decl *ast.FuncDecl
+	recv types.Object
+}
+
+// fieldAccess is a use of a struct field in a method
+type fieldAccess struct {
+	sel       *ast.SelectorExpr
+	method    *ast.FuncDecl
+	write     bool
+	protected bool // a lock of the receiver is taken before the access
+}
+
+// checkSharedFields reports map fields, and slice fields that are appended
+// to, which several methods of a concurrently used type access without
+// holding a lock of the struct. It is conservative: fields written nowhere,
+// or accessed by a single method, are left alone.
+func checkSharedFields(reporter *nolint.Reporter, pass *analysis.Pass, inspect *inspector.Inspector) {
+	methods := make(map[*types.Named][]method)
+	var order []*types.Named
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Recv == nil || len(fn.Recv.List[0].Names) == 0 || fn.Body == nil {
+			return
+		}
+		recv := pass.TypesInfo.Defs[fn.Recv.List[0].Names[0]]
+		named := receiverNamed(recv)
+		if named == nil {
+			return
+		}
+		if _, seen := methods[named]; !seen {
+			order = append(order, named)
+		}
+		methods[named] = append(methods[named], method{decl: fn, recv: recv})
+	})
+
+	handlers := registeredHandlers(pass, inspect)
+	for _, named := range order {
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			continue
+		}
+		reason := concurrentUse(pass, named, methods[named], handlers)
+		if reason == "" {
+			continue
+		}
+
+		accesses := make(map[*types.Var][]fieldAccess)
+		var fields []*types.Var
+		for _, m := range methods[named] {
+			for _, access := range fieldAccesses(pass, m) {
+				field := pass.TypesInfo.Selections[access.sel].Obj().(*types.Var)
+				if _, seen := accesses[field]; !seen {
+					fields = append(fields, field)
+				}
+				accesses[field] = append(accesses[field], access)
+			}
+		}
+
+		for _, field := range fields {
+			checkSharedField(reporter, named, field, accesses[field], reason)
+		}
+	}
+}
+
+// checkSharedField reports the first unprotected access of field when
+// several methods access it and one of them writes it
+func checkSharedField(reporter *nolint.Reporter, named *types.Named, field *types.Var, accesses []fieldAccess, reason string) {
+	var kind string
+	switch field.Type().Underlying().(type) {
+	case *types.Map:
+		kind = "map"
+	case *types.Slice:
+		kind = "slice"
+	default:
+		return
+	}
+
+	byMethod := make(map[*ast.FuncDecl]bool)
+	written := false
+	var unprotected *fieldAccess
+	for i, access := range accesses {
+		byMethod[access.method] = true
+		written = written || access.write
+		if !access.protected && unprotected == nil {
+			unprotected = &accesses[i]
+		}
+	}
+	if len(byMethod) < 2 || !written || unprotected == nil {
+		return
+	}
+
+	advice := "guard it with a sync.Mutex field, or use sync.Map"
+	if kind == "slice" {
+		advice = "guard it with a sync.Mutex field"
+	}
+	reporter.Reportf(unprotected.sel.Pos(),
+		"%s field %s.%s is accessed by %d methods of a type %s, but %q accesses it without holding a lock; %s",
+		kind, named.Obj().Name(), field.Name(), len(byMethod), reason, unprotected.method.Name.Name, advice)
+}
+
+// receiverNamed returns the named type of a method receiver
+func receiverNamed(recv types.Object) *types.Named {
+	if recv == nil {
+		return nil
+	}
+	return namedOf(recv.Type())
+}
+
+// namedOf returns the named type t or *t, if t is one
+func namedOf(t types.Type) *types.Named {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+// fieldAccesses returns the uses of map and slice fields of the receiver in
+// the method. Writes of slice fields are appends.
+func fieldAccesses(pass *analysis.Pass, m method) []fieldAccess {
+	// Methods named like removeLocked expect the caller to hold the lock
+	heldByCaller := strings.HasSuffix(m.decl.Name.Name, "Locked")
+
+	var locks []token.Pos
+	ast.Inspect(m.decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if lc, ok := mutexCall(pass, call); ok && unlockFor[lc.method] != "" && ownsMutex(pass, lc.recv, m.recv.Name()) {
+				locks = append(locks, call.Pos())
+			}
+		}
+		return true
+	})
+
+	var accesses []fieldAccess
+	var stack []ast.Node
+	ast.Inspect(m.decl.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok || pass.TypesInfo.Uses[ident] != m.recv {
+			return true
+		}
+		selection, ok := pass.TypesInfo.Selections[sel]
+		if !ok || selection.Kind() != types.FieldVal {
+			return true
+		}
+
+		write := false
+		switch selection.Type().Underlying().(type) {
+		case *types.Map:
+			write = writesMap(stack)
+		case *types.Slice:
+			write = appendsTo(stack)
+		default:
+			return true
+		}
+
+		protected := heldByCaller
+		for _, pos := range locks {
+			if pos < sel.Pos() {
+				protected = true
+			}
+		}
+		accesses = append(accesses, fieldAccess{sel: sel, method: m.decl, write: write, protected: protected})
+		return true
+	})
+
+	return accesses
+}
+
+// appendsTo checks if the field at the end of path is assigned the result of
+// append, like c.items = append(c.items, item)
+func appendsTo(path []ast.Node) bool {
+	if len(path) < 2 {
+		return false
+	}
+	assign, ok := path[len(path)-2].(*ast.AssignStmt)
+	if !ok {
+		return false
+	}
+	for i, lhs := range assign.Lhs {
+		if lhs != path[len(path)-1] || i >= len(assign.Rhs) {
+			continue
+		}
+		call, ok := assign.Rhs[i].(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		fun, ok := call.Fun.(*ast.Ident)
+		return ok && fun.Name == "append"
+	}
+	return false
+}
+
+// concurrentUse returns why the methods of named may run concurrently, or ""
+func concurrentUse(pass *analysis.Pass, named *types.Named, methods []method, handlers map[*types.Named]bool) string {
+	if handlers[named] || isHTTPHandler(named) {
+		return "serving HTTP requests"
+	}
+	for _, m := range methods {
+		spawns := false
+		ast.Inspect(m.decl.Body, func(n ast.Node) bool {
+			if goStmt, ok := n.(*ast.GoStmt); ok && usesReceiver(pass, goStmt.Call, m.recv) {
+				spawns = true
+			}
+			return !spawns
+		})
+		if spawns {
+			return "whose methods start goroutines using the receiver"
+		}
+	}
+	return ""
+}
+
+// usesReceiver checks if node refers to the receiver
+func usesReceiver(pass *analysis.Pass, node ast.Node, recv types.Object) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[ident] == recv {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// isHTTPHandler checks if named or a pointer to it has a ServeHTTP method
+// with the signature of net/http.Handler
+func isHTTPHandler(named *types.Named) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), "ServeHTTP")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	params := fn.Type().(*types.Signature).Params()
+	return params.Len() == 2 &&
+		types.TypeString(params.At(0).Type(), nil) == "net/http.ResponseWriter" &&
+		types.TypeString(params.At(1).Type(), nil) == "*net/http.Request"
+}
+
+// registeredHandlers finds types whose method values are registered with
+// Handle or HandleFunc, like mux.HandleFunc("/items", s.listItems)
+func registeredHandlers(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Named]bool {
+	handlers := make(map[*types.Named]bool)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		var name string
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			name = fun.Sel.Name
+		case *ast.Ident:
+			name = fun.Name
+		}
+		if name != "Handle" && name != "HandleFunc" {
+			return
+		}
+		for _, arg := range call.Args {
+			sel, ok := arg.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			if selection, ok := pass.TypesInfo.Selections[sel]; ok && selection.Kind() == types.MethodVal {
+				if named := namedOf(selection.Recv()); named != nil {
+					handlers[named] = true
+				}
+			}
+		}
+	})
+	return handlers
+}
+
+// mutexCall checks if call invokes a method of sync.Mutex or sync.RWMutex,
+// directly or through an embedded mutex
+func mutexCall(pass *analysis.Pass, call *ast.CallExpr) (lockCall, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return lockCall{}, false
+	}
+	switch sel.Sel.Name {
+	case "Lock", "RLock", "Unlock", "RUnlock":
+	default:
+		return lockCall{}, false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sync" {
+		return lockCall{}, false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return lockCall{}, false
+	}
+
+	return lockCall{
+		call:   call,
+		recv:   sel.X,
+		mutex:  types.ExprString(sel.X),
+		method: sel.Sel.Name,
+		rw:     isRWMutex(recv.Type()),
+	}, true
+}
+
+// rootedAt checks if expr is a field of the receiver named recv, possibly
+// indexed or dereferenced, like c.items[i] or *c.count
+func rootedAt(expr ast.Expr, recv string) bool {
+	for {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := e.X.(*ast.Ident); ok {
+				return ident.Name == recv
+			}
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return false
+		}
+	}
+}
+
+// isSafeUnlocked checks for field types that synchronize themselves, like
+// channels, sync.Once and atomic values
+func isSafeUnlocked(t types.Type) bool {
+	if _, ok := t.Underlying().(*types.Chan); ok {
+		return true
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	path := named.Obj().Pkg().Path()
+	return path == "sync" || path == "sync/atomic"
+}
+
+// ownsMutex checks if the mutex expression is the receiver named recv, with
+// an embedded mutex, or one of its mutex fields, like c.mu
+func ownsMutex(pass *analysis.Pass, mutex ast.Expr, recv string) bool {
+	if sel, ok := mutex.(*ast.SelectorExpr); ok && isMutex(pass.TypesInfo.TypeOf(sel)) {
+		mutex = sel.X
+	}
+	return isIdent(mutex, recv)
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// isMutex checks if t is sync.Mutex or sync.RWMutex, or a pointer to one
+func isMutex(t types.Type) bool {
+	return isSyncType(t, "Mutex") || isSyncType(t, "RWMutex")
+}
+
+func isRWMutex(t types.Type) bool {
+	return isSyncType(t, "RWMutex")
+}
+
+func isSyncType(t types.Type, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
 	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "sync" && obj.Name() == name
 }
@@ -0,0 +1,35 @@
+package syncaccess_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/syncaccess"
+)
+
+func TestSyncAccessNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, syncaccess.Analyzer, "nolint")
+}
+
+func TestSyncAccessLocks(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, syncaccess.Analyzer, "locks")
+}
+
+func TestSyncAccessProtected(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, syncaccess.Analyzer, "protected")
+}
+
+func TestSyncAccessSharedFields(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, syncaccess.Analyzer, "fields")
+}
+
+func TestSyncAccessLoopVarVersion(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, filepath.Join(testdata, "loopvar"), syncaccess.Analyzer, "example.com/loopvar")
+}
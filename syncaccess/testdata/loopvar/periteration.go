@@ -0,0 +1,10 @@
+//go:build go1.22
+
+package loopvar
+
+// PerIteration gets a new variable per iteration from Go 1.22 on.
+func PerIteration(n int) {
+	for i := 0; i < n; i++ {
+		go func() { use(i) }()
+	}
+}
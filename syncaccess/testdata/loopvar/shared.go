@@ -0,0 +1,12 @@
+// Package loopvar targets Go 1.21, where loop variables are shared by all
+// iterations.
+package loopvar
+
+func use(int) {}
+
+// Shared captures the variable all iterations share.
+func Shared(n int) {
+	for i := 0; i < n; i++ {
+		go func() { use(i) }() // want `loop variable "i" captured by goroutine`
+	}
+}
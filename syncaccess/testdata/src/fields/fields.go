@@ -0,0 +1,118 @@
+package fields
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// UnprotectedCache refreshes itself in the background and serves reads
+type UnprotectedCache struct {
+	items map[string]string
+}
+
+func (c *UnprotectedCache) Start() {
+	go func() {
+		for range time.Tick(time.Minute) {
+			c.items = map[string]string{} // want `map field UnprotectedCache.items is accessed by 3 methods of a type whose methods start goroutines using the receiver, but "Start" accesses it without holding a lock; guard it with a sync.Mutex field, or use sync.Map`
+		}
+	}()
+}
+
+func (c *UnprotectedCache) Get(key string) string {
+	return c.items[key]
+}
+
+func (c *UnprotectedCache) Set(key, value string) {
+	c.items[key] = value
+}
+
+// ProtectedCache takes its lock before every access
+type ProtectedCache struct {
+	mu    sync.RWMutex
+	items map[string]string
+}
+
+func (c *ProtectedCache) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	go c.refresh()
+}
+
+func (c *ProtectedCache) refresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = map[string]string{}
+}
+
+func (c *ProtectedCache) Get(key string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.items[key]
+}
+
+func (c *ProtectedCache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = value
+}
+
+// Server is an HTTP handler, so requests run its methods concurrently
+type Server struct {
+	seen []string
+	hits map[string]int
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.seen = append(s.seen, r.URL.Path) // want `slice field Server.seen is accessed by 2 methods of a type serving HTTP requests, but "ServeHTTP" accesses it without holding a lock; guard it with a sync.Mutex field`
+}
+
+func (s *Server) Seen() int {
+	return len(s.seen)
+}
+
+// Count only reads hits, which nothing writes after construction
+func (s *Server) Count(path string) int {
+	return s.hits[path]
+}
+
+func (s *Server) Total() int {
+	total := 0
+	for _, n := range s.hits {
+		total += n
+	}
+	return total
+}
+
+// API registers its methods as handler functions
+type API struct {
+	sessions map[string]string
+}
+
+func (a *API) Login(w http.ResponseWriter, r *http.Request) {
+	a.sessions[r.FormValue("user")] = r.FormValue("token") // want `map field API.sessions is accessed by 2 methods of a type serving HTTP requests, but "Login" accesses it without holding a lock`
+}
+
+func (a *API) Logout(w http.ResponseWriter, r *http.Request) {
+	delete(a.sessions, r.FormValue("user"))
+}
+
+func Routes(a *API) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", a.Login)
+	mux.HandleFunc("/logout", a.Logout)
+	return mux
+}
+
+// Local is never used concurrently
+type Local struct {
+	items map[string]string
+}
+
+func (l *Local) Get(key string) string {
+	return l.items[key]
+}
+
+func (l *Local) Set(key, value string) {
+	l.items[key] = value
+}
@@ -0,0 +1,109 @@
+package locks
+
+import "sync"
+
+type Counter struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (c *Counter) Increment() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+}
+
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	c.count = 0
+	c.mu.Unlock()
+}
+
+func (c *Counter) Forgotten() {
+	c.mu.Lock() // want `c.mu.Lock\(\) has no matching c.mu.Unlock\(\) in "Forgotten"`
+	c.count++
+}
+
+func (c *Counter) EarlyRead() int {
+	if c.count > 10 { // want `c.count is accessed before c.mu.Lock\(\) in "EarlyRead"`
+		return c.count
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+func (c *Counter) UnlockInClosure() {
+	c.mu.Lock()
+	defer func() {
+		c.mu.Unlock()
+	}()
+	c.count++
+}
+
+// lock hands the held lock to its caller
+func (c *Counter) lock() {
+	c.mu.Lock()
+}
+
+func (c *Counter) Unguarded() int { // want `method "Unguarded" on type with mutex accesses fields without Lock\(\)`
+	return c.count
+}
+
+type Cache struct {
+	mu    sync.RWMutex
+	items map[string]string
+	hits  int
+}
+
+func (c *Cache) Get(key string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.items[key]
+}
+
+func (c *Cache) Mismatched(key string) string {
+	c.mu.RLock() // want `c.mu.RLock\(\) has no matching c.mu.RUnlock\(\) in "Mismatched"`
+	defer c.mu.Unlock()
+	return c.items[key]
+}
+
+func (c *Cache) ReadWithLock(key string) string {
+	c.mu.Lock() // want `"ReadWithLock" only reads fields but takes c.mu.Lock\(\); use c.mu.RLock\(\)`
+	defer c.mu.Unlock()
+	return c.items[key]
+}
+
+func (c *Cache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = value
+}
+
+func (c *Cache) Count() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hits++
+}
+
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.items)
+}
+
+type Embedded struct {
+	sync.Mutex
+	n int
+}
+
+func (e *Embedded) Add() {
+	e.Lock() // want `e.Lock\(\) has no matching e.Unlock\(\) in "Add"`
+	e.n++
+}
+
+func (e *Embedded) Sub() {
+	e.Lock()
+	defer e.Unlock()
+	e.n--
+}
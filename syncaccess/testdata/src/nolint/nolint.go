@@ -0,0 +1,17 @@
+package nolint
+
+func use(int) {}
+
+// Reported: loop variable captured by a goroutine
+func Reported(n int) {
+	for i := 0; i < n; i++ {
+		go func() { use(i) }() // want `loop variable "i" captured by goroutine`
+	}
+}
+
+// Suppressed inline
+func Suppressed(n int) {
+	for i := 0; i < n; i++ {
+		go func() { use(i) }() //nolint:syncaccess // per-iteration variables since Go 1.22
+	}
+}
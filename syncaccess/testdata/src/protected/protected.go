@@ -0,0 +1,112 @@
+package protected
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type Config struct{ Name string }
+
+func load() *Config { return &Config{} }
+
+func use(string) {}
+
+func AtomicCounter() {
+	var n *int64 = new(int64)
+	go func() {
+		atomic.AddInt64(n, 1)
+	}()
+	_ = atomic.LoadInt64(n)
+}
+
+func AtomicValue() {
+	var count *atomic.Int64 = new(atomic.Int64)
+	go func() {
+		count.Add(1)
+	}()
+}
+
+func ChannelOnly() {
+	results := make(chan *Config, 1)
+	cfg := &Config{}
+	go func() {
+		results <- cfg
+	}()
+	<-results
+}
+
+func Once() {
+	var once sync.Once
+	cfg := &Config{}
+	go func() {
+		once.Do(func() {
+			cfg.Name = load().Name
+		})
+	}()
+}
+
+func Primitives() {
+	wg := &sync.WaitGroup{}
+	mu := &sync.Mutex{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+	}()
+	wg.Wait()
+}
+
+func ReadOnlyMap() {
+	names := map[string]string{"a": "b"}
+	names["c"] = "d"
+	go func() {
+		use(names["a"])
+	}()
+}
+
+func RacyMapWrite() {
+	counts := map[string]int{}
+	go func() {
+		counts["a"]++ // want `shared variable "counts" captured by goroutine without synchronization`
+	}()
+	use("")
+}
+
+func MapWrittenAfterStart() {
+	names := map[string]string{}
+	go func() {
+		use(names["a"]) // want `shared variable "names" captured by goroutine without synchronization`
+	}()
+	names["a"] = "b"
+}
+
+func MixedAccess() {
+	cfg := &Config{}
+	go func() {
+		once := &sync.Once{}
+		once.Do(func() { cfg.Name = "a" })
+		use(cfg.Name) // want `shared variable "cfg" captured by goroutine without synchronization`
+	}()
+}
+
+// TypeSwitch shadows the captured variable; only the outer v is captured
+func TypeSwitch(v interface{}) {
+	go func() {
+		switch v := v.(type) {
+		case []interface{}:
+			_ = v
+		}
+	}()
+}
+
+// TypeSwitchMap shadows a captured map with a type switch
+func TypeSwitchMap(v interface{}, counts map[string]int) {
+	go func() {
+		switch counts := v.(type) {
+		case map[string]int:
+			counts["a"]++
+		}
+		_ = counts["b"]
+	}()
+}
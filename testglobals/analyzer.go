@@ -0,0 +1,420 @@
+// Package testglobals provides an analyzer that detects package-level state
+// shared between tests in ways that make them interfere with each other.
+//
+// A map declared next to the tests and filled by two of them works until the
+// tests run in a different order, with -count, or in parallel. Flaky tests
+// caused by shared fixtures are hard to reproduce because each test passes
+// on its own.
+package testglobals
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect package-level mutable state shared across tests
+
+In _test.go files, this analyzer detects:
+1. Package-level maps, slices, arrays, and structs declared in test files
+   and written by more than one Test function
+2. Writes to such variables from tests that call t.Parallel, which run
+   concurrently with other tests
+3. TestMain setting package variables that tests read without restoring
+   or releasing them after m.Run
+4. sync.Once-guarded setup of package variables that depends on values
+   from the calling test: the first test to run decides for all of them
+
+Bad:
+    var cache = map[string]int{}
+
+    func TestA(t *testing.T) {
+        t.Parallel()
+        cache["a"] = 1
+    }
+
+    func TestB(t *testing.T) {
+        cache["b"] = 2
+    }
+
+Good:
+    func TestA(t *testing.T) {
+        t.Parallel()
+        cache := map[string]int{"a": 1}
+        ...
+    }
+
+Package-level tables that tests only read are fine.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "testglobals",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+// write is an assignment to a package-level variable
+type write struct {
+	v    *types.Var
+	node ast.Node
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	testFiles := make(map[*token.File]bool)
+	for _, file := range pass.Files {
+		tf := pass.Fset.File(file.Pos())
+		if strings.HasSuffix(tf.Name(), "_test.go") {
+			testFiles[tf] = true
+		}
+	}
+	if len(testFiles) == 0 {
+		return nil, nil
+	}
+	inTestFile := func(pos token.Pos) bool {
+		return testFiles[pass.Fset.File(pos)]
+	}
+
+	writers := make(map[*types.Var][]string) // variable -> tests writing it
+	readers := make(map[*types.Var]bool)     // variables read by tests
+	var testMains []*ast.FuncDecl
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil || !inTestFile(fn.Pos()) {
+			return
+		}
+
+		switch {
+		case isTestMain(pass, fn):
+			testMains = append(testMains, fn)
+		case isTestFunc(pass, fn):
+			for _, w := range writesIn(pass, fn.Body) {
+				if !inTestFile(w.v.Pos()) || !isMutable(w.v.Type()) {
+					continue
+				}
+				if names := writers[w.v]; len(names) == 0 || names[len(names)-1] != fn.Name.Name {
+					writers[w.v] = append(names, fn.Name.Name)
+				}
+			}
+			checkParallelWrites(reporter, pass, fn, inTestFile)
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok {
+					if v := packageVar(pass, ident); v != nil {
+						readers[v] = true
+					}
+				}
+				return true
+			})
+		}
+	})
+
+	// Report shared variables in declaration order
+	vars := make([]*types.Var, 0, len(writers))
+	for v, names := range writers {
+		if len(names) > 1 {
+			vars = append(vars, v)
+		}
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Pos() < vars[j].Pos() })
+	for _, v := range vars {
+		reporter.Reportf(v.Pos(),
+			"package-level %s is written by %s; tests sharing mutable state interfere with each other, declare it inside each test",
+			v.Name(), strings.Join(writers[v], ", "))
+	}
+
+	for _, fn := range testMains {
+		checkTestMain(reporter, pass, fn, readers)
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		if inTestFile(n.Pos()) {
+			checkOnceSetup(reporter, pass, n.(*ast.CallExpr))
+		}
+	})
+
+	return nil, nil
+}
+
+// checkParallelWrites reports writes to package-level variables from test
+// code that runs in parallel: a test or subtest that calls t.Parallel, or
+// any code nested in one
+func checkParallelWrites(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, inTestFile func(token.Pos) bool) {
+	var visit func(body *ast.BlockStmt, parallel bool)
+	visit = func(body *ast.BlockStmt, parallel bool) {
+		parallel = parallel || callsParallel(pass, body)
+		ast.Inspect(body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				visit(lit.Body, parallel)
+				return false
+			}
+			if !parallel {
+				return true
+			}
+			for _, w := range writesOf(pass, n) {
+				if inTestFile(w.v.Pos()) && isMutable(w.v.Type()) {
+					reporter.Reportf(w.node.Pos(),
+						"%s is written by parallel test %s while other tests may use it; declare it inside the test",
+						w.v.Name(), fn.Name.Name)
+				}
+			}
+			return true
+		})
+	}
+	visit(fn.Body, false)
+}
+
+// callsParallel checks if body calls Parallel on a *testing.T, outside of
+// nested function literals
+func callsParallel(pass *analysis.Pass, body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if ok && sel.Sel.Name == "Parallel" && isTestingType(pass.TypesInfo.TypeOf(sel.X), "T") {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// checkTestMain reports package variables that TestMain sets, tests read,
+// and nothing refers to again after m.Run or in a deferred call
+func checkTestMain(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, readers map[*types.Var]bool) {
+	runPos := token.NoPos
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && runPos == token.NoPos {
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if ok && sel.Sel.Name == "Run" && isTestingType(pass.TypesInfo.TypeOf(sel.X), "M") {
+				runPos = call.End()
+			}
+		}
+		return true
+	})
+	if runPos == token.NoPos {
+		return
+	}
+
+	// Variables referenced after m.Run or in deferred calls are cleaned up
+	cleaned := make(map[*types.Var]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if d, ok := n.(*ast.DeferStmt); ok {
+			ast.Inspect(d, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok {
+					if v := packageVar(pass, ident); v != nil {
+						cleaned[v] = true
+					}
+				}
+				return true
+			})
+		}
+		if ident, ok := n.(*ast.Ident); ok && ident.Pos() > runPos {
+			if v := packageVar(pass, ident); v != nil {
+				cleaned[v] = true
+			}
+		}
+		return true
+	})
+
+	reported := make(map[*types.Var]bool)
+	for _, w := range writesIn(pass, fn.Body) {
+		if w.node.Pos() > runPos || !readers[w.v] || cleaned[w.v] || reported[w.v] {
+			continue
+		}
+		reported[w.v] = true
+		reporter.Reportf(w.node.Pos(),
+			"TestMain sets %s, which tests read, and never restores it; reset it after m.Run or set it up in each test",
+			w.v.Name())
+	}
+}
+
+// checkOnceSetup reports once.Do(func() { ... }) calls that set package
+// variables from values of the enclosing function, like the calling test's
+// parameters or table entries
+func checkOnceSetup(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Do" || len(call.Args) != 1 || !isSyncOnce(pass.TypesInfo.TypeOf(sel.X)) {
+		return
+	}
+	lit, ok := call.Args[0].(*ast.FuncLit)
+	if !ok {
+		return
+	}
+
+	writes := writesIn(pass, lit.Body)
+	if len(writes) == 0 {
+		return
+	}
+
+	// A local of the enclosing function, declared outside the closure
+	var dep *types.Var
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return dep == nil
+		}
+		v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || v.IsField() || v.Pkg() != pass.Pkg || v.Parent() == pass.Pkg.Scope() {
+			return true
+		}
+		if v.Pos() < lit.Pos() || v.Pos() >= lit.End() {
+			dep = v
+		}
+		return dep == nil
+	})
+	if dep == nil {
+		return
+	}
+
+	reporter.Reportf(call.Pos(),
+		"sync.Once setup of %s depends on %s from the caller; the first test to run decides the value for every test",
+		writes[0].v.Name(), dep.Name())
+}
+
+// writesIn returns the writes to package-level variables in body,
+// including nested function literals
+func writesIn(pass *analysis.Pass, body *ast.BlockStmt) []write {
+	var writes []write
+	ast.Inspect(body, func(n ast.Node) bool {
+		writes = append(writes, writesOf(pass, n)...)
+		return true
+	})
+	return writes
+}
+
+// writesOf returns the package-level variables n writes: assignments to the
+// variable or its elements and fields, ++ and --, and delete and clear
+func writesOf(pass *analysis.Pass, n ast.Node) []write {
+	var writes []write
+	add := func(expr ast.Expr) {
+		if ident := rootIdent(expr); ident != nil {
+			if v := packageVar(pass, ident); v != nil {
+				writes = append(writes, write{v: v, node: n})
+			}
+		}
+	}
+
+	switch node := n.(type) {
+	case *ast.AssignStmt:
+		if node.Tok == token.DEFINE {
+			break
+		}
+		for _, lhs := range node.Lhs {
+			add(lhs)
+		}
+	case *ast.IncDecStmt:
+		add(node.X)
+	case *ast.CallExpr:
+		ident, ok := ast.Unparen(node.Fun).(*ast.Ident)
+		if !ok || len(node.Args) == 0 {
+			break
+		}
+		if b, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); ok && (b.Name() == "delete" || b.Name() == "clear") {
+			add(node.Args[0])
+		}
+	}
+	return writes
+}
+
+// rootIdent returns the variable at the root of an element, field, or
+// dereference expression
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// packageVar returns the package-level variable ident refers to, if any
+func packageVar(pass *analysis.Pass, ident *ast.Ident) *types.Var {
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || v.Pkg() != pass.Pkg || v.Parent() != pass.Pkg.Scope() {
+		return nil
+	}
+	return v
+}
+
+// isMutable checks if values of t are shared containers that tests can
+// change in place
+func isMutable(t types.Type) bool {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Map, *types.Slice, *types.Array:
+		return true
+	case *types.Struct:
+		return u.NumFields() > 0
+	}
+	return false
+}
+
+// isTestFunc checks if fn is a top-level TestXxx(t *testing.T)
+func isTestFunc(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") || fn.Name.Name == "TestMain" {
+		return false
+	}
+	params := fn.Type.Params.List
+	return len(params) == 1 && isTestingType(pass.TypesInfo.TypeOf(params[0].Type), "T")
+}
+
+// isTestMain checks if fn is TestMain(m *testing.M)
+func isTestMain(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || fn.Name.Name != "TestMain" {
+		return false
+	}
+	params := fn.Type.Params.List
+	return len(params) == 1 && isTestingType(pass.TypesInfo.TypeOf(params[0].Type), "M")
+}
+
+// isTestingType checks if t is *testing.<name>
+func isTestingType(t types.Type, name string) bool {
+	return isNamed(t, "testing", name)
+}
+
+// isSyncOnce checks if t is sync.Once or *sync.Once
+func isSyncOnce(t types.Type) bool {
+	return isNamed(t, "sync", "Once")
+}
+
+// isNamed checks if t, or the type it points to, is pkg.name
+func isNamed(t types.Type, pkg, name string) bool {
+	if t == nil {
+		return false
+	}
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkg && named.Obj().Name() == name
+}
@@ -0,0 +1,14 @@
+package testglobals_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/testglobals"
+)
+
+func TestTestGlobalsAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, testglobals.Analyzer, "shared", "table", "testmain", "once")
+}
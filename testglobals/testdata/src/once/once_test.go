@@ -0,0 +1,39 @@
+package once
+
+import (
+	"sync"
+	"testing"
+)
+
+type server struct{ dir string }
+
+var (
+	setupOnce sync.Once
+	srv       *server
+)
+
+func setup(t *testing.T) *server {
+	setupOnce.Do(func() { // want `sync.Once setup of srv depends on t from the caller; the first test to run decides the value for every test`
+		srv = &server{dir: t.TempDir()}
+	})
+	return srv
+}
+
+var (
+	defaultOnce sync.Once
+	defaultSrv  *server
+)
+
+func setupDefault() *server {
+	defaultOnce.Do(func() {
+		dir := "/tmp/fixtures"
+		defaultSrv = &server{dir: dir}
+	})
+	return defaultSrv
+}
+
+func TestServer(t *testing.T) {
+	if setup(t) == nil || setupDefault() == nil {
+		t.Fatal("no server")
+	}
+}
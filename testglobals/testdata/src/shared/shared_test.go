@@ -0,0 +1,38 @@
+package shared
+
+import "testing"
+
+var cache = map[string]int{} // want `package-level cache is written by TestStore, TestEvict; tests sharing mutable state interfere with each other`
+
+var calls []string // want `package-level calls is written by TestEvict, TestSubtests`
+
+var counter int
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	cache["a"] = 1 // want `cache is written by parallel test TestStore while other tests may use it`
+	counter++
+}
+
+func TestEvict(t *testing.T) {
+	cache["b"] = 2
+	delete(cache, "b")
+	calls = append(calls, "evict")
+	Registry["evict"] = 1
+}
+
+func TestSubtests(t *testing.T) {
+	for _, name := range []string{"x", "y"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			calls = append(calls, name) // want `calls is written by parallel test TestSubtests while other tests may use it`
+		})
+	}
+}
+
+func TestLocal(t *testing.T) {
+	t.Parallel()
+	cache := map[string]int{}
+	cache["local"] = 1
+	_ = counter
+}
@@ -0,0 +1,31 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+var upperTests = []struct {
+	in, want string
+}{
+	{"a", "A"},
+	{"b", "B"},
+}
+
+func TestUpper(t *testing.T) {
+	t.Parallel()
+	for _, tt := range upperTests {
+		if got := strings.ToUpper(tt.in); got != tt.want {
+			t.Errorf("ToUpper(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestUpperAgain(t *testing.T) {
+	for _, tt := range upperTests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			_ = strings.ToUpper(tt.in)
+		})
+	}
+}
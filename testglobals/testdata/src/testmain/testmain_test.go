@@ -0,0 +1,36 @@
+package testmain
+
+import (
+	"os"
+	"testing"
+)
+
+var debug bool
+
+var endpoint string
+
+type database struct{ dsn string }
+
+func (d *database) Close() {}
+
+var db *database
+
+var unused string
+
+func TestMain(m *testing.M) {
+	debug = true          // want `TestMain sets debug, which tests read, and never restores it`
+	endpoint = "http://x" // endpoint is restored below
+	db = &database{dsn: "test"}
+	unused = "never read"
+	defer func() { endpoint = "" }()
+
+	code := m.Run()
+	db.Close()
+	os.Exit(code)
+}
+
+func TestDebug(t *testing.T) {
+	if !debug || endpoint == "" || db == nil {
+		t.Fatal("not set up")
+	}
+}
@@ -0,0 +1,378 @@
+// Package testhelper provides an analyzer that checks test helpers and
+// subtests follow the conventions of the testing package.
+//
+// A helper without t.Helper() makes every failure point at the helper
+// instead of the test that called it, and a parallel subtest that captures
+// the range variable runs every case against the last element before Go 1.22.
+package testhelper
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/goversion"
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check test helpers call t.Helper and parallel subtests rebind range variables
+
+In _test.go files, this analyzer detects:
+1. Functions taking *testing.T, *testing.B, *testing.F or testing.TB that
+   are called from more than one test but don't call t.Helper() as their
+   first statement, so failures point at the helper instead of the test
+2. Parallel subtests started with t.Run in a range loop whose closure
+   captures the range variable without rebinding it, which before Go 1.22
+   runs every subtest with the last element
+3. With -require-parallel, Test functions that never call t.Parallel()
+
+Bad:
+    func assertUser(t *testing.T, got, want User) {
+        if got != want {
+            t.Errorf("got %v, want %v", got, want)
+        }
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            t.Parallel()
+            check(t, tc)
+        })
+    }
+
+Good:
+    func assertUser(t *testing.T, got, want User) {
+        t.Helper()
+        ...
+    }
+
+    for _, tc := range cases {
+        tc := tc
+        t.Run(tc.name, func(t *testing.T) {
+            t.Parallel()
+            check(t, tc)
+        })
+    }
+
+Flags:
+    -require-parallel  report Test functions that never call t.Parallel (default false)`
+
+var requireParallel bool
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "testhelper",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("testhelper", flag.ExitOnError)
+	fs.BoolVar(&requireParallel, "require-parallel", false,
+		"report Test functions that never call t.Parallel")
+	return *fs
+}
+
+// helper is a function of the test files that takes a testing value
+type helper struct {
+	decl  *ast.FuncDecl
+	param string   // name of the testing parameter
+	tests []string // tests calling the helper, in order
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	testFiles := make(map[*token.File]*ast.File)
+	for _, file := range pass.Files {
+		tf := pass.Fset.File(file.Pos())
+		if strings.HasSuffix(tf.Name(), "_test.go") {
+			testFiles[tf] = file
+		}
+	}
+	if len(testFiles) == 0 {
+		return nil, nil
+	}
+	fileOf := func(pos token.Pos) *ast.File {
+		return testFiles[pass.Fset.File(pos)]
+	}
+
+	helpers := make(map[*types.Func]*helper)
+	var order []*types.Func
+	var tests []*ast.FuncDecl
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil || fileOf(fn.Pos()) == nil {
+			return
+		}
+
+		if isTestFunc(pass, fn) {
+			tests = append(tests, fn)
+			return
+		}
+		if isTestEntryPoint(fn.Name.Name) {
+			return
+		}
+		if param := testingParam(pass, fn); param != "" {
+			obj := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+			helpers[obj] = &helper{decl: fn, param: param}
+			order = append(order, obj)
+		}
+	})
+
+	for _, test := range tests {
+		ast.Inspect(test.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if h := helpers[calledFunc(pass, call)]; h != nil {
+				if len(h.tests) == 0 || h.tests[len(h.tests)-1] != test.Name.Name {
+					h.tests = append(h.tests, test.Name.Name)
+				}
+			}
+			return true
+		})
+
+		if requireParallel {
+			checkParallel(reporter, pass, test)
+		}
+	}
+
+	for _, obj := range order {
+		if h := helpers[obj]; len(h.tests) > 1 {
+			checkHelper(reporter, pass, h)
+		}
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.RangeStmt)(nil)}, func(n ast.Node) {
+		// Since Go 1.22 each iteration has its own variables; code of an
+		// unknown version is not reported either
+		loop := n.(*ast.RangeStmt)
+		if loop.Tok != token.DEFINE || !goversion.Before(pass, loop.Pos(), goversion.LoopVar) {
+			return
+		}
+		checkSubtests(reporter, pass, loop)
+	})
+
+	return nil, nil
+}
+
+// checkHelper reports a helper used by several tests whose first statement
+// is not a call to Helper
+func checkHelper(reporter *nolint.Reporter, pass *analysis.Pass, h *helper) {
+	body := h.decl.Body
+	if len(body.List) > 0 && isHelperCall(pass, body.List[0], h.param) {
+		return
+	}
+
+	diag := &analysis.Diagnostic{
+		Pos: h.decl.Name.Pos(),
+		Message: fmt.Sprintf("test helper %s is used by %s but does not call %s.Helper() first; failures will point at the helper instead of the test",
+			h.decl.Name.Name, strings.Join(h.tests, ", "), h.param),
+	}
+
+	// Offer to add the call when the helper has none, moving an existing
+	// call is left to the author
+	hasHelper := false
+	for _, stmt := range body.List {
+		hasHelper = hasHelper || isHelperCall(pass, stmt, h.param)
+	}
+	if !hasHelper && len(body.List) > 0 {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Call %s.Helper()", h.param),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     body.List[0].Pos(),
+				End:     body.List[0].Pos(),
+				NewText: []byte(h.param + ".Helper()\n\t"),
+			}},
+		}}
+	}
+
+	reporter.Report(diag)
+}
+
+// isHelperCall checks if stmt is param.Helper()
+func isHelperCall(pass *analysis.Pass, stmt ast.Stmt, param string) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Helper" {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == param && isTestingValue(pass.TypesInfo.TypeOf(ident))
+}
+
+// checkSubtests reports parallel subtests started in loop that capture one
+// of its range variables
+func checkSubtests(reporter *nolint.Reporter, pass *analysis.Pass, loop *ast.RangeStmt) {
+	rangeVars := make(map[types.Object]string)
+	for _, expr := range []ast.Expr{loop.Key, loop.Value} {
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+			if obj := pass.TypesInfo.Defs[ident]; obj != nil {
+				rangeVars[obj] = ident.Name
+			}
+		}
+	}
+	if len(rangeVars) == 0 {
+		return
+	}
+
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Run" || !isTestingType(pass.TypesInfo.TypeOf(sel.X), "T") {
+			return true
+		}
+		subtest, ok := call.Args[1].(*ast.FuncLit)
+		if !ok || !callsParallel(pass, subtest.Body) {
+			return true
+		}
+
+		reported := make(map[string]bool)
+		ast.Inspect(subtest.Body, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			name, captured := rangeVars[pass.TypesInfo.Uses[ident]]
+			if captured && !reported[name] {
+				reported[name] = true
+				reporter.Reportf(ident.Pos(),
+					"parallel subtest captures range variable %s; before Go 1.22 every subtest sees the last element, rebind it with %s := %s before t.Run",
+					name, name, name)
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// checkParallel reports a Test function that never calls t.Parallel.
+// Tests using t.Setenv or t.Chdir can't run in parallel and are skipped.
+func checkParallel(reporter *nolint.Reporter, pass *analysis.Pass, test *ast.FuncDecl) {
+	if callsParallel(pass, test.Body) {
+		return
+	}
+
+	serial := false
+	ast.Inspect(test.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && (sel.Sel.Name == "Setenv" || sel.Sel.Name == "Chdir") &&
+				isTestingValue(pass.TypesInfo.TypeOf(sel.X)) {
+				serial = true
+			}
+		}
+		return !serial
+	})
+	if serial {
+		return
+	}
+
+	reporter.Reportf(test.Name.Pos(),
+		"%s never calls t.Parallel(); call it first so the test runs alongside the others", test.Name.Name)
+}
+
+// callsParallel checks if body calls Parallel on a *testing.T, outside
+// nested function literals
+func callsParallel(pass *analysis.Pass, body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if ok && sel.Sel.Name == "Parallel" && isTestingType(pass.TypesInfo.TypeOf(sel.X), "T") {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// calledFunc returns the function a call invokes directly, or nil
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	fn, _ := pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+// testingParam returns the name of the first parameter of fn that is a
+// *testing.T, *testing.B, *testing.F or testing.TB
+func testingParam(pass *analysis.Pass, fn *ast.FuncDecl) string {
+	for _, field := range fn.Type.Params.List {
+		if !isTestingValue(pass.TypesInfo.TypeOf(field.Type)) {
+			continue
+		}
+		if len(field.Names) == 0 || field.Names[0].Name == "_" {
+			return ""
+		}
+		return field.Names[0].Name
+	}
+	return ""
+}
+
+// isTestEntryPoint checks for names the testing package calls itself
+func isTestEntryPoint(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestFunc checks if fn is a Test function taking *testing.T
+func isTestFunc(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") || fn.Name.Name == "TestMain" {
+		return false
+	}
+	params := fn.Type.Params.List
+	return len(params) == 1 && isTestingType(pass.TypesInfo.TypeOf(params[0].Type), "T")
+}
+
+// isTestingValue checks if t is *testing.T, *testing.B, *testing.F or
+// testing.TB
+func isTestingValue(t types.Type) bool {
+	return isTestingType(t, "T") || isTestingType(t, "B") || isTestingType(t, "F") || isTestingType(t, "TB")
+}
+
+// isTestingType checks if t, or the type it points to, is testing.<name>
+func isTestingType(t types.Type, name string) bool {
+	if t == nil {
+		return false
+	}
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "testing" && named.Obj().Name() == name
+}
@@ -0,0 +1,25 @@
+package testhelper_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/testhelper"
+)
+
+func TestTestHelperAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, testhelper.Analyzer, "helpers")
+	analysistest.Run(t, testdata, testhelper.Analyzer, "legacy", "subtests", "unknownversion")
+}
+
+func TestTestHelperRequireParallel(t *testing.T) {
+	if err := testhelper.Analyzer.Flags.Set("require-parallel", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = testhelper.Analyzer.Flags.Set("require-parallel", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, testhelper.Analyzer, "parallel")
+}
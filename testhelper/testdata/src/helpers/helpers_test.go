@@ -0,0 +1,45 @@
+package helpers
+
+import "testing"
+
+type User struct{ Name string }
+
+func assertUser(t *testing.T, got, want User) { // want `test helper assertUser is used by TestCreate, TestUpdate but does not call t.Helper\(\) first`
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func assertName(t *testing.T, got User, name string) {
+	t.Helper()
+	if got.Name != name {
+		t.Errorf("got %q, want %q", got.Name, name)
+	}
+}
+
+func mustLoad(tb testing.TB, name string) User { // want `test helper mustLoad is used by TestCreate, TestUpdate but does not call tb.Helper\(\) first`
+	if name == "" {
+		tb.Fatal("empty name")
+	}
+	tb.Helper()
+	return User{Name: name}
+}
+
+func onlyOnce(t *testing.T) {
+	t.Log("used by one test")
+}
+
+func TestCreate(t *testing.T) {
+	u := mustLoad(t, "a")
+	assertUser(t, u, User{Name: "a"})
+	assertName(t, u, "a")
+	onlyOnce(t)
+}
+
+func TestUpdate(t *testing.T) {
+	u := mustLoad(t, "b")
+	t.Run("rename", func(t *testing.T) {
+		assertUser(t, u, User{Name: "b"})
+		assertName(t, u, "b")
+	})
+}
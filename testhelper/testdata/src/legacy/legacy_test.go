@@ -0,0 +1,47 @@
+//go:build go1.21
+
+package legacy
+
+import "testing"
+
+type testCase struct {
+	name  string
+	input int
+}
+
+var cases = []testCase{{"one", 1}, {"two", 2}}
+
+func check(t *testing.T, tc testCase) {
+	t.Helper()
+	if tc.input < 0 {
+		t.Fail()
+	}
+}
+
+func TestCaptured(t *testing.T) {
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			check(t, tc) // want `parallel subtest captures range variable tc; before Go 1.22 every subtest sees the last element`
+			_ = i        // want `parallel subtest captures range variable i`
+		})
+	}
+}
+
+func TestRebound(t *testing.T) {
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			check(t, tc)
+		})
+	}
+}
+
+func TestSequential(t *testing.T) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			check(t, tc)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package parallel
+
+import "testing"
+
+func TestSerial(t *testing.T) { // want `TestSerial never calls t.Parallel\(\)`
+	t.Log("serial")
+}
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+	t.Log("parallel")
+}
+
+func TestEnvironment(t *testing.T) {
+	t.Setenv("HOME", "/tmp")
+}
+
+func TestOnlySubtestsParallel(t *testing.T) { // want `TestOnlySubtestsParallel never calls t.Parallel\(\)`
+	t.Run("sub", func(t *testing.T) {
+		t.Parallel()
+	})
+}
@@ -0,0 +1,16 @@
+//go:build go1.22
+
+package subtests
+
+import "testing"
+
+var cases = []string{"one", "two"}
+
+func TestCaptured(t *testing.T) {
+	for _, tc := range cases {
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+			_ = tc
+		})
+	}
+}
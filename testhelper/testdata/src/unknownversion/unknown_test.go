@@ -0,0 +1,18 @@
+// Package unknownversion has no go.mod or build constraint, so its Go
+// version is unknown and captured range variables are not reported
+package unknownversion
+
+import "testing"
+
+var names = []string{"one", "two"}
+
+func TestCaptured(t *testing.T) {
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if name == "" {
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Package timectx provides an analyzer that detects hand-rolled deadline
+// arithmetic in functions that already carry a context.Context.
+//
+// A loop that checks time.Since(start) > timeout duplicates the deadline the
+// context already has, and the two usually disagree: the caller cancels and
+// the loop keeps going, or the loop gives up while the caller still had time.
+package timectx
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect manual deadline checks that should use the context's deadline
+
+This analyzer detects, in functions that accept a context.Context:
+1. Elapsed-time deadline checks (time.Since(start) > timeout,
+   time.Now().After(deadline)) that abort work
+2. ctx.Deadline() results whose ok value is discarded, or deadlines used
+   before ok has been checked
+3. time.Until(deadline) compared against a constant instead of deriving a
+   child context
+
+Bad:
+    start := time.Now()
+    for {
+        if time.Since(start) > 30*time.Second {
+            return ErrTimeout
+        }
+        ...
+    }
+
+Good:
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+    for {
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+        ...
+    }`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "timectx",
+	Doc:      Doc,
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return
+		}
+
+		if getContextParam(fn) == "" {
+			return
+		}
+
+		checkElapsedDeadlines(reporter, pass, fn.Body)
+		checkDeadlineResults(reporter, pass, fn.Body)
+		checkUntilComparisons(reporter, pass, fn.Body)
+	})
+
+	return nil, nil
+}
+
+// getContextParam returns the name of the context parameter if present
+func getContextParam(fn *ast.FuncDecl) string {
+	if fn.Type.Params == nil {
+		return ""
+	}
+
+	for _, param := range fn.Type.Params.List {
+		paramType := types.ExprString(param.Type)
+		if strings.Contains(paramType, "context.Context") || paramType == "Context" {
+			if len(param.Names) > 0 {
+				return param.Names[0].Name
+			}
+			return "ctx" // Anonymous context param
+		}
+	}
+
+	return ""
+}
+
+// timeFunc returns the name of a package-level time function called by expr
+func timeFunc(pass *analysis.Pass, expr ast.Expr) (string, *ast.CallExpr) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return "", nil
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" {
+		return "", nil
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return "", nil
+	}
+	if sig.Recv() != nil {
+		// Methods on time.Time / time.Duration: Now().After(...), Now().Sub(...)
+		if name, _ := timeFunc(pass, sel.X); name == "Now" {
+			return "Now()." + fn.Name(), call
+		}
+		return "", nil
+	}
+
+	return fn.Name(), call
+}
+
+// isElapsedCheck reports whether cond compares elapsed time against a limit
+func isElapsedCheck(pass *analysis.Pass, cond ast.Expr) bool {
+	found := false
+
+	ast.Inspect(cond, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.BinaryExpr:
+			switch node.Op {
+			case token.GTR, token.GEQ, token.LSS, token.LEQ:
+				for _, side := range []ast.Expr{node.X, node.Y} {
+					if name, _ := timeFunc(pass, side); name == "Since" || name == "Now().Sub" {
+						found = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if name, _ := timeFunc(pass, node); name == "Now().After" || name == "Now().Before" {
+				found = true
+			}
+		}
+		return !found
+	})
+
+	return found
+}
+
+// abortsWork checks if a block leaves the surrounding loop or function
+func abortsWork(block *ast.BlockStmt) bool {
+	aborts := false
+	ast.Inspect(block, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			aborts = true
+		case *ast.BranchStmt:
+			if node.Tok == token.BREAK || node.Tok == token.GOTO {
+				aborts = true
+			}
+		}
+		return !aborts
+	})
+	return aborts
+}
+
+// checkElapsedDeadlines flags hand-rolled timeouts in if and for conditions
+func checkElapsedDeadlines(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	const msg = "manual elapsed-time deadline in a function with a context; " +
+		"derive ctx with context.WithTimeout and check ctx.Err() or ctx.Done() instead"
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			// Only deadlines that stop work; a slow-request log line is fine
+			if isElapsedCheck(pass, node.Cond) && abortsWork(node.Body) {
+				reporter.Reportf(node.Cond.Pos(), "%s", msg)
+			}
+		case *ast.ForStmt:
+			if node.Cond != nil && isElapsedCheck(pass, node.Cond) {
+				reporter.Reportf(node.Cond.Pos(), "%s", msg)
+			}
+		}
+		return true
+	})
+}
+
+// isContextDeadlineCall checks for ctx.Deadline() on a context.Context
+func isContextDeadlineCall(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Deadline" {
+		return false
+	}
+
+	return isContextType(pass.TypesInfo.TypeOf(sel.X))
+}
+
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// deadlineResult is a deadline, ok := ctx.Deadline() pair
+type deadlineResult struct {
+	deadline types.Object
+	ok       types.Object
+}
+
+// checkDeadlineResults flags ctx.Deadline() calls whose ok result is ignored
+// and deadlines used before ok has been checked
+func checkDeadlineResults(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	var results []deadlineResult
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ExprStmt:
+			if isContextDeadlineCall(pass, node.X) {
+				reporter.Reportf(node.Pos(),
+					"ctx.Deadline() result is discarded; use ctx.Err() or ctx.Done() to observe the deadline")
+			}
+		case *ast.AssignStmt:
+			if len(node.Rhs) != 1 || len(node.Lhs) != 2 || !isContextDeadlineCall(pass, node.Rhs[0]) {
+				return true
+			}
+			deadlineIdent, ok1 := node.Lhs[0].(*ast.Ident)
+			okIdent, ok2 := node.Lhs[1].(*ast.Ident)
+			if !ok1 || !ok2 {
+				return true
+			}
+			if okIdent.Name == "_" {
+				reporter.Reportf(node.Pos(),
+					"ctx.Deadline() ok value is discarded; when ok is false the deadline is the zero time and has no meaning")
+				return true
+			}
+			results = append(results, deadlineResult{
+				deadline: pass.TypesInfo.ObjectOf(deadlineIdent),
+				ok:       pass.TypesInfo.ObjectOf(okIdent),
+			})
+		}
+		return true
+	})
+
+	for _, r := range results {
+		checkDeadlineBeforeOk(reporter, pass, body, r)
+	}
+}
+
+// checkDeadlineBeforeOk flags uses of the deadline that precede any read of ok
+func checkDeadlineBeforeOk(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt, r deadlineResult) {
+	if r.deadline == nil || r.ok == nil {
+		return
+	}
+
+	firstOkRead := token.NoPos
+	var deadlineUses []*ast.Ident
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch pass.TypesInfo.Uses[ident] {
+		case r.ok:
+			if !firstOkRead.IsValid() {
+				firstOkRead = ident.Pos()
+			}
+		case r.deadline:
+			deadlineUses = append(deadlineUses, ident)
+		}
+		return true
+	})
+
+	for _, use := range deadlineUses {
+		if !firstOkRead.IsValid() || use.Pos() < firstOkRead {
+			reporter.Reportf(use.Pos(),
+				"deadline %q from ctx.Deadline() is used before checking ok; handle the no-deadline case first",
+				use.Name)
+			return
+		}
+	}
+}
+
+// checkUntilComparisons flags time.Until(deadline) compared against constants
+func checkUntilComparisons(reporter *nolint.Reporter, pass *analysis.Pass, body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+
+		switch bin.Op {
+		case token.GTR, token.GEQ, token.LSS, token.LEQ:
+		default:
+			return true
+		}
+
+		for _, pair := range [][2]ast.Expr{{bin.X, bin.Y}, {bin.Y, bin.X}} {
+			name, _ := timeFunc(pass, pair[0])
+			if name != "Until" {
+				continue
+			}
+			if tv, ok := pass.TypesInfo.Types[pair[1]]; ok && tv.Value != nil {
+				reporter.Reportf(bin.Pos(),
+					"time.Until compared against a constant; derive a child context with context.WithTimeout instead of budgeting by hand")
+				return true
+			}
+		}
+		return true
+	})
+}
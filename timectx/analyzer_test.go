@@ -0,0 +1,14 @@
+package timectx_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/timectx"
+)
+
+func TestTimeCtxAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, timectx.Analyzer, "a")
+}
@@ -0,0 +1,95 @@
+package a
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+var errTimeout = errors.New("timeout")
+
+func poll(ctx context.Context) bool { return false }
+
+// Bad: manual elapsed check alongside ctx
+func WaitReady(ctx context.Context) error {
+	start := time.Now()
+	for !poll(ctx) {
+		if time.Since(start) > 30*time.Second { // want `manual elapsed-time deadline in a function with a context`
+			return errTimeout
+		}
+	}
+	return nil
+}
+
+// Bad: deadline as loop condition
+func Drain(ctx context.Context, deadline time.Time) {
+	for !time.Now().After(deadline) { // want `manual elapsed-time deadline in a function with a context`
+		poll(ctx)
+	}
+}
+
+// Good: slow-request logging is not a deadline
+func Handle(ctx context.Context) {
+	start := time.Now()
+	poll(ctx)
+	if time.Since(start) > time.Second {
+		log.Println("slow request")
+	}
+}
+
+// Good: WithTimeout
+func WaitReadyCtx(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for !poll(ctx) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bad: ok discarded
+func Remaining(ctx context.Context) time.Duration {
+	deadline, _ := ctx.Deadline() // want `ctx.Deadline\(\) ok value is discarded`
+	return time.Until(deadline)
+}
+
+// Bad: deadline used before ok is checked
+func RemainingUnchecked(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	remaining := time.Until(deadline) // want `deadline "deadline" from ctx.Deadline\(\) is used before checking ok`
+	if !ok {
+		return time.Hour
+	}
+	return remaining
+}
+
+// Good: ok checked
+func RemainingChecked(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return time.Hour
+	}
+	return time.Until(deadline)
+}
+
+// Bad: budgeting by hand
+func Budget(ctx context.Context) error {
+	deadline, ok := ctx.Deadline()
+	if ok && time.Until(deadline) < 5*time.Second { // want `time.Until compared against a constant`
+		return errTimeout
+	}
+	return nil
+}
+
+// Good: no context, nothing to compare against
+func WaitNoContext() error {
+	start := time.Now()
+	for {
+		if time.Since(start) > time.Second {
+			return errTimeout
+		}
+	}
+}
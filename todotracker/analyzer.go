@@ -5,6 +5,8 @@
 package todotracker
 
 import (
+	"flag"
+	"fmt"
 	"go/ast"
 	"regexp"
 	"strings"
@@ -19,34 +21,88 @@ import (
 const Doc = `ensure TODO/FIXME comments have owners and context
 
 Orphaned TODOs without owners tend to never get done. This analyzer
-enforces that TODO/FIXME comments include:
-1. An owner (username, email, or team)
+enforces that TODO, FIXME, HACK, and XXX comments include:
+1. An owner (username, email, or team) in parentheses or brackets
 2. Context about what needs to be done
+3. With -require-issue, a reference to the issue tracking it
 
 Good:
     // TODO(username): Implement retry logic for transient failures
     // FIXME(@team-platform): This breaks when input exceeds 1MB
-    // TODO(jira:PROJ-123): Add caching layer
+    // TODO[alice]: Add caching layer, see #1234
+    // HACK(jira:PROJ-123): Work around the upstream bug
 
 Bad:
     // TODO: fix this
     // FIXME
-    // TODO - make this better`
+    // TODO - make this better
+
+Markers are matched as uppercase words, so prose like "a quick hack" is
+not a marker.
+
+Flags:
+    -owner-format   regular expression the whole owner must match
+    -require-issue  also require an issue reference
+    -issue-pattern  regular expression of issue references (default #\d+|[A-Z]+-\d+)`
+
+// defaultIssuePattern matches GitHub issues like #1234 and JIRA keys like
+// PROJ-123
+const defaultIssuePattern = `#\d+|[A-Z]+-\d+`
+
+var (
+	ownerFormat  string
+	requireIssue bool
+	issuePattern string
+)
 
 var Analyzer = &analysis.Analyzer{
 	Name:     "todotracker",
 	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
 	Run:      run,
 }
 
-// Pattern to match well-formed TODOs: TODO(owner): description
-var wellFormedTODO = regexp.MustCompile(`(?i)(TODO|FIXME)\s*\([^)]+\)\s*:\s*\S+`)
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("todotracker", flag.ExitOnError)
+	fs.StringVar(&ownerFormat, "owner-format", "",
+		"regular expression the whole owner must match")
+	fs.BoolVar(&requireIssue, "require-issue", false,
+		"also require an issue reference")
+	fs.StringVar(&issuePattern, "issue-pattern", defaultIssuePattern,
+		"regular expression of issue references")
+	return *fs
+}
+
+// marker matches the TODO-style markers
+var marker = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX)\b`)
+
+// closing maps the brackets an owner may be enclosed in to their ends
+var closing = map[byte]byte{'(': ')', '[': ']'}
 
-// Pattern to match any TODO/FIXME
-var anyTODO = regexp.MustCompile(`(?i)(TODO|FIXME)`)
+// rules holds the compiled flags
+type rules struct {
+	owner *regexp.Regexp // nil accepts any owner
+	issue *regexp.Regexp // nil when issues are not required
+}
 
 func run(pass *analysis.Pass) (interface{}, error) {
+	var r rules
+	if ownerFormat != "" {
+		owner, err := regexp.Compile(`^(?:` + ownerFormat + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("todotracker: invalid -owner-format %q: %w", ownerFormat, err)
+		}
+		r.owner = owner
+	}
+	if requireIssue {
+		issue, err := regexp.Compile(issuePattern)
+		if err != nil {
+			return nil, fmt.Errorf("todotracker: invalid -issue-pattern %q: %w", issuePattern, err)
+		}
+		r.issue = issue
+	}
+
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
@@ -59,7 +115,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 		for _, cg := range file.Comments {
 			for _, comment := range cg.List {
-				checkComment(reporter, comment)
+				checkComment(reporter, comment, r)
 			}
 		}
 	})
@@ -67,38 +123,67 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	return nil, nil
 }
 
-func checkComment(reporter *nolint.Reporter, comment *ast.Comment) {
+func checkComment(reporter *nolint.Reporter, comment *ast.Comment, r rules) {
 	text := comment.Text
+	if strings.HasPrefix(text, "/*") {
+		text = strings.TrimSuffix(text, "*/")
+	}
 
-	// Check if this comment contains TODO or FIXME
-	if !anyTODO.MatchString(text) {
+	loc := marker.FindStringSubmatchIndex(text)
+	// Identifiers like context.TODO are code, not markers
+	if loc == nil || (loc[0] > 0 && text[loc[0]-1] == '.') {
 		return
 	}
+	kind := text[loc[2]:loc[3]]
+	rest := strings.TrimLeft(text[loc[1]:], " \t")
 
-	// Check if it's well-formed
-	if wellFormedTODO.MatchString(text) {
-		return // Good TODO
+	// Owner: TODO(owner) or TODO[owner]
+	end, ok := byte(0), false
+	if rest != "" {
+		end, ok = closing[rest[0]]
 	}
-
-	// Extract the TODO/FIXME part for the message
-	todoType := "TODO"
-	if strings.Contains(strings.ToUpper(text), "FIXME") {
-		todoType = "FIXME"
+	if !ok {
+		reporter.Reportf(comment.Pos(),
+			"%s without owner; use %s(owner): description",
+			kind, kind)
+		return
 	}
-
-	// Determine what's wrong
-	if !strings.Contains(text, "(") {
+	open := rest[0]
+	closeAt := strings.IndexByte(rest, end)
+	if closeAt < 0 {
+		reporter.Reportf(comment.Pos(),
+			"%s has an unclosed owner; use %s(owner): description",
+			kind, kind)
+		return
+	}
+	owner := strings.TrimSpace(rest[1:closeAt])
+	if owner == "" {
 		reporter.Reportf(comment.Pos(),
-			"%s without owner; use %s(username): description",
-			todoType, todoType)
-	} else if !strings.Contains(text, ":") {
+			"%s has an empty owner; use %s(owner): description",
+			kind, kind)
+		return
+	}
+	if r.owner != nil && !r.owner.MatchString(owner) {
 		reporter.Reportf(comment.Pos(),
-			"%s without description; use %s(owner): what needs to be done",
-			todoType, todoType)
-	} else {
-		// Has parens and colon but doesn't match pattern - likely malformed
+			"%s owner %q does not match the owner format %s",
+			kind, owner, ownerFormat)
+		return
+	}
+
+	// Description: ": what needs to be done"
+	rest = strings.TrimLeft(rest[closeAt+1:], " \t")
+	description, hasColon := strings.CutPrefix(rest, ":")
+	if !hasColon || strings.TrimSpace(description) == "" {
+		reporter.Reportf(comment.Pos(),
+			"%s%c%s%c without description; use %s%c%s%c: what needs to be done",
+			kind, open, owner, end, kind, open, owner, end)
+		return
+	}
+
+	// Issue references may be part of the owner, like jira:PROJ-123
+	if r.issue != nil && !r.issue.MatchString(text[loc[1]:]) {
 		reporter.Reportf(comment.Pos(),
-			"%s appears malformed; use format: %s(owner): description",
-			todoType, todoType)
+			"%s%c%s%c without issue reference; add one matching %s, like #1234 or PROJ-123",
+			kind, open, owner, end, r.issue)
 	}
 }
@@ -0,0 +1,32 @@
+package todotracker_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/todotracker"
+)
+
+func TestTodoTracker(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, todotracker.Analyzer, "a")
+}
+
+func TestTodoTrackerRequireIssue(t *testing.T) {
+	for name, value := range map[string]string{
+		"require-issue": "true",
+		"owner-format":  "[a-z]+|jira:[A-Z]+-[0-9]+",
+	} {
+		if err := todotracker.Analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		_ = todotracker.Analyzer.Flags.Set("require-issue", "false")
+		_ = todotracker.Analyzer.Flags.Set("owner-format", "")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, todotracker.Analyzer, "issues")
+}
@@ -0,0 +1,27 @@
+package a
+
+// TODO(alice): Implement retry logic for transient failures
+// FIXME(@team-platform): This breaks when input exceeds 1MB
+// TODO[alice]: Accept the bracket style
+// HACK (bob): Work around the upstream bug
+// XXX[carol]: Remove once v2 ships
+
+// TODO: fix this // want `TODO without owner; use TODO\(owner\): description`
+
+// FIXME // want `FIXME without owner`
+
+// HACK - make this better // want `HACK without owner`
+
+// TODO(alice) make this better // want `TODO\(alice\) without description; use TODO\(alice\): what needs to be done`
+
+/* XXX[bob]: */ // want `XX.\[bob\] without description; use XX.\[bob\]: what needs to be done`
+
+// TODO(): fill in // want `TODO has an empty owner`
+
+// TODO(alice: unclosed // want `TODO has an unclosed owner`
+
+// A quick hack, and a todo list in prose, are not markers.
+
+// TODOs and XXXL are not markers either.
+
+// Pass context.TODO() when there is no context.
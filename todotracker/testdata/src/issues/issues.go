@@ -0,0 +1,13 @@
+package issues
+
+// TODO(alice): Add caching layer, see #1234
+// FIXME[bob]: Handle large inputs (PROJ-123)
+// HACK(jira:OPS-7): Work around the upstream bug
+
+// TODO(alice): Add caching layer // want `TODO\(alice\) without issue reference; add one matching`
+
+// TODO[alice]: Bracket style without issue // want `TODO\[alice\] without issue reference`
+
+// TODO(Bob): Owner in the wrong case, see #12 // want `TODO owner "Bob" does not match the owner format`
+
+// TODO: no owner at all, see #12 // want `TODO without owner`
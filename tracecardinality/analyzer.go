@@ -0,0 +1,403 @@
+// Package tracecardinality provides an analyzer that detects unbounded values
+// in span attributes, metric labels, and wide-event fields.
+//
+// spanname checks that names and keys are stable; this analyzer checks the
+// values. Every distinct label value creates a new metric time series, and
+// full error strings, URLs with query strings, or request bodies in span
+// attributes bloat traces past what backends store.
+package tracecardinality
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `detect unbounded values in span attributes, metric labels, and log fields
+
+This analyzer checks the values passed to:
+1. OpenTelemetry attribute constructors (attribute.String, Key.String, ...)
+2. Prometheus label values (WithLabelValues, prometheus.Labels)
+3. zap field constructors (zap.String, zap.ByteString, zap.Stringer)
+
+It reports values that are unbounded user or content data:
+- err.Error(), the full error string
+- raw URLs with query strings (r.URL.String(), r.RequestURI, URL.RawQuery)
+- []byte converted to string, such as request bodies
+- string constants longer than -max-length
+- for metric labels only: UUIDs, identifiers such as userID, and URL paths
+
+Bad:
+    requests.WithLabelValues(userID).Inc()
+    span.SetAttributes(attribute.String("error", err.Error()))
+
+Good:
+    requests.WithLabelValues(plan.Tier).Inc()
+    span.RecordError(err)
+
+Flags:
+    -max-length  longest string constant accepted as a value (default 256)`
+
+var maxLength int
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "tracecardinality",
+	Doc:      Doc,
+	Flags:    flags(),
+	Requires: nolint.Requires(inspect.Analyzer),
+	Run:      run,
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("tracecardinality", flag.ExitOnError)
+	fs.IntVar(&maxLength, "max-length", 256, "longest string constant accepted as a value")
+	return *fs
+}
+
+const (
+	otelPrefix     = "go.opentelemetry.io/otel"
+	attributePkg   = otelPrefix + "/attribute"
+	otelMetricPkg  = otelPrefix + "/metric"
+	prometheusPkg  = "github.com/prometheus/client_golang/prometheus"
+	zapPkg         = "go.uber.org/zap"
+	urlPkg         = "net/url"
+	httpPkg        = "net/http"
+	identifierNote = "an identifier"
+	errorNote      = "the full error string"
+)
+
+// sinkKind is where a value ends up
+type sinkKind int
+
+const (
+	spanAttribute sinkKind = iota
+	metricLabel
+	logField
+)
+
+// attributeFuncs are the attribute constructors and Key methods whose last
+// argument is the value
+var attributeFuncs = map[string]bool{
+	"String":      true,
+	"StringSlice": true,
+	"Stringer":    true,
+}
+
+// zapFuncs are the zap field constructors whose second argument is the value
+var zapFuncs = map[string]bool{
+	"String":     true,
+	"Strings":    true,
+	"ByteString": true,
+	"Stringer":   true,
+}
+
+// unboundedMethods maps "pkg.Type.Method" to a description of its result
+var unboundedMethods = map[string]string{
+	urlPkg + ".URL.String":     "a raw URL with its query string",
+	urlPkg + ".URL.RequestURI": "a raw URL with its query string",
+}
+
+// unboundedFields maps "pkg.Type.Field" to a description of its value
+var unboundedFields = map[string]string{
+	httpPkg + ".Request.RequestURI": "a raw URL with its query string",
+	urlPkg + ".URL.RawQuery":        "a raw query string",
+}
+
+// metricOnlyFields are bounded enough for spans and logs but not for labels
+var metricOnlyFields = map[string]string{
+	urlPkg + ".URL.Path": "a raw URL path",
+}
+
+// identifierName matches names of variables holding per-entity identifiers
+var identifierName = regexp.MustCompile(`(^(id|uuid|guid)$)|([a-z0-9](ID|Id|UUID|Uuid)$)`)
+
+// advice is the suggestion for each sink
+var advice = map[sinkKind]string{
+	spanAttribute: "span attribute value is %s; unbounded values bloat spans and are truncated by backends, truncate or hash it, or put it in logs",
+	metricLabel:   "metric label value is %s; every distinct value creates a new time series, use a bounded category or put it in logs",
+	logField:      "log field value is %s; unbounded values bloat wide events, truncate or hash it",
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.CompositeLit)(nil),
+	}
+
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			// prometheus.Labels{"user": id}
+			if isNamed(pass.TypesInfo.TypeOf(node), prometheusPkg, "Labels") {
+				for _, elt := range node.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						checkValue(reporter, pass, kv.Value, metricLabel)
+					}
+				}
+			}
+		case *ast.CallExpr:
+			checkCall(reporter, pass, node, stack)
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// checkCall checks the value arguments of attribute, label, and field
+// constructors
+func checkCall(reporter *nolint.Reporter, pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	fn := calledFunc(pass, call)
+	if fn == nil || fn.Pkg() == nil || len(call.Args) == 0 {
+		return
+	}
+	path := fn.Pkg().Path()
+	last := call.Args[len(call.Args)-1]
+
+	switch {
+	case path == attributePkg && attributeFuncs[fn.Name()]:
+		// attribute.String(k, v) and attribute.Key(k).String(v)
+		kind := spanAttribute
+		if inMetricCall(pass, stack) {
+			kind = metricLabel
+		}
+		checkValue(reporter, pass, last, kind)
+	case path == prometheusPkg && isMethod(fn) &&
+		(fn.Name() == "WithLabelValues" || fn.Name() == "GetMetricWithLabelValues"):
+		for _, arg := range call.Args {
+			checkValue(reporter, pass, arg, metricLabel)
+		}
+	case path == zapPkg && zapFuncs[fn.Name()] && len(call.Args) == 2:
+		checkValue(reporter, pass, last, logField)
+	}
+}
+
+// inMetricCall checks if the call is nested in an OpenTelemetry metric call,
+// where attributes become metric dimensions
+func inMetricCall(pass *analysis.Pass, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		call, ok := stack[i].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if fn := calledFunc(pass, call); fn != nil && fn.Pkg() != nil && strings.HasPrefix(fn.Pkg().Path(), otelMetricPkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkValue reports expr if it is unbounded for the sink
+func checkValue(reporter *nolint.Reporter, pass *analysis.Pass, expr ast.Expr, kind sinkKind) {
+	desc := unbounded(pass, expr, kind)
+	if desc == "" {
+		return
+	}
+	msg := advice[kind]
+	if kind == logField && strings.HasPrefix(desc, errorNote) {
+		msg = "log field value is %s; use zap.Error(err) so the error is structured"
+	}
+	if kind == spanAttribute && strings.HasPrefix(desc, errorNote) {
+		msg = "span attribute value is %s; use span.RecordError(err) and a bounded error.type attribute"
+	}
+	reporter.Reportf(expr.Pos(), msg, desc)
+}
+
+// unbounded describes why expr is an unbounded value, or returns ""
+func unbounded(pass *analysis.Pass, expr ast.Expr, kind sinkKind) string {
+	expr = ast.Unparen(expr)
+
+	tv := pass.TypesInfo.Types[expr]
+	if tv.Value != nil {
+		if tv.Value.Kind() == constant.String && len(constant.StringVal(tv.Value)) > maxLength {
+			return "a string constant longer than " + strconv.Itoa(maxLength) + " bytes"
+		}
+		return ""
+	}
+
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return unboundedCall(pass, e, kind)
+
+	case *ast.SelectorExpr:
+		if desc := fieldDescription(pass, e, kind); desc != "" {
+			return desc
+		}
+		if kind == metricLabel && identifierName.MatchString(e.Sel.Name) {
+			return identifierNote + " (" + e.Sel.Name + ")"
+		}
+
+	case *ast.Ident:
+		if kind == metricLabel && identifierName.MatchString(e.Name) {
+			return identifierNote + " (" + e.Name + ")"
+		}
+	}
+
+	if isByteSlice(pass.TypesInfo.TypeOf(expr)) {
+		return "a raw byte slice"
+	}
+	if kind == metricLabel && isNamed(pass.TypesInfo.TypeOf(expr), "", "UUID") {
+		return "a UUID"
+	}
+	return ""
+}
+
+// unboundedCall describes unbounded call results
+func unboundedCall(pass *analysis.Pass, call *ast.CallExpr, kind sinkKind) string {
+	// string(body)
+	if tv, ok := pass.TypesInfo.Types[call.Fun]; ok && tv.IsType() && len(call.Args) == 1 {
+		if isByteSlice(pass.TypesInfo.TypeOf(call.Args[0])) {
+			return "raw bytes converted to a string"
+		}
+		return ""
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	// err.Error()
+	if sel.Sel.Name == "Error" && len(call.Args) == 0 && implementsError(pass.TypesInfo.TypeOf(sel.X)) {
+		return errorNote + " from " + types.ExprString(call)
+	}
+
+	// uuid.New().String(), id.String()
+	if kind == metricLabel && sel.Sel.Name == "String" && isNamed(pass.TypesInfo.TypeOf(sel.X), "", "UUID") {
+		return "a UUID"
+	}
+
+	fn := calledFunc(pass, call)
+	if fn == nil || fn.Pkg() == nil {
+		return ""
+	}
+	if recv := receiverName(fn); recv != "" {
+		if desc := unboundedMethods[fn.Pkg().Path()+"."+recv+"."+fn.Name()]; desc != "" {
+			return desc
+		}
+	}
+
+	// fmt.Sprintf("%s", err.Error()) is as unbounded as its arguments
+	if fn.Pkg().Path() == "fmt" && strings.HasPrefix(fn.Name(), "Sprint") {
+		for _, arg := range call.Args {
+			if desc := unbounded(pass, arg, kind); desc != "" {
+				return desc
+			}
+		}
+	}
+	return ""
+}
+
+// fieldDescription describes unbounded struct fields such as r.RequestURI
+func fieldDescription(pass *analysis.Pass, sel *ast.SelectorExpr, kind sinkKind) string {
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return ""
+	}
+	t := selection.Recv()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+
+	key := named.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + sel.Sel.Name
+	if desc := unboundedFields[key]; desc != "" {
+		return desc
+	}
+	if kind == metricLabel {
+		return metricOnlyFields[key]
+	}
+	return ""
+}
+
+// calledFunc resolves the function or method a call invokes
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+
+	fn, _ := pass.TypesInfo.Uses[ident].(*types.Func)
+	return fn
+}
+
+func isMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() != nil
+}
+
+// receiverName returns the name of the named receiver type of a method
+func receiverName(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return ""
+	}
+	t := sig.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// isNamed checks if t is the named type pkgPath.name; an empty pkgPath
+// matches any package
+func isNamed(t types.Type, pkgPath, name string) bool {
+	if t == nil {
+		return false
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Name() != name || named.Obj().Pkg() == nil {
+		return false
+	}
+	return pkgPath == "" || named.Obj().Pkg().Path() == pkgPath
+}
+
+// isByteSlice checks for []byte
+func isByteSlice(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	slice, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
+
+// implementsError checks if t implements the error interface
+func implementsError(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	errType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(t, errType)
+}
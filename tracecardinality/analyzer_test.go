@@ -0,0 +1,14 @@
+package tracecardinality_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/tracecardinality"
+)
+
+func TestTraceCardinalityAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, tracecardinality.Analyzer, "a")
+}
@@ -0,0 +1,80 @@
+package a
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+var requests = &prometheus.CounterVec{}
+
+type Plan struct {
+	Tier   string
+	UserID string
+}
+
+// Bad: identifier as a label value
+func CountUser(userID string) {
+	requests.WithLabelValues(userID).Inc() // want `metric label value is an identifier \(userID\); every distinct value creates a new time series`
+}
+
+// Good: bounded enum label
+func CountTier(plan Plan) {
+	requests.WithLabelValues(plan.Tier, "GET").Inc()
+}
+
+// Bad: identifier field and UUID in labels
+func CountPlan(plan Plan) {
+	requests.WithLabelValues(plan.UserID).Inc()         // want `metric label value is an identifier \(UserID\)`
+	requests.WithLabelValues(uuid.New().String()).Inc() // want `metric label value is a UUID`
+	requests.With(prometheus.Labels{"path": ""}).Inc()
+}
+
+// Bad: URL path in a Labels literal
+func CountPath(r *http.Request) {
+	requests.With(prometheus.Labels{"path": r.URL.Path}).Inc() // want `metric label value is a raw URL path`
+}
+
+// Bad: error string as span attribute
+func SpanError(err error) attribute.KeyValue {
+	return attribute.String("error", err.Error()) // want `span attribute value is the full error string from err.Error\(\); use span.RecordError`
+}
+
+// Bad: raw URLs and bodies in span attributes
+func SpanRequest(r *http.Request, body []byte) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("http.url", r.URL.String()),        // want `span attribute value is a raw URL with its query string`
+		attribute.Key("http.target").String(r.RequestURI),   // want `span attribute value is a raw URL with its query string`
+		attribute.String("http.request.body", string(body)), // want `span attribute value is raw bytes converted to a string`
+		attribute.String("http.route", "/users/{id}"),
+		attribute.String("http.path", r.URL.Path),
+	}
+}
+
+// Bad: identifier attribute on a metric
+func MetricAttrs(userID string) metric.AddOption {
+	return metric.WithAttributes(attribute.String("user", userID)) // want `metric label value is an identifier \(userID\)`
+}
+
+// Good: identifiers are fine on spans
+func SpanUser(userID string) attribute.KeyValue {
+	return attribute.String("user.id", userID)
+}
+
+// Bad: error string and body as log fields
+func Fields(err error, body []byte) []zap.Field {
+	return []zap.Field{
+		zap.String("error", err.Error()), // want `log field value is the full error string from err.Error\(\); use zap.Error\(err\)`
+		zap.ByteString("body", body),     // want `log field value is a raw byte slice`
+		zap.Error(err),
+	}
+}
+
+// Suppressed with nolint
+func Debug(r *http.Request) attribute.KeyValue {
+	return attribute.String("http.url", r.URL.String()) //nolint:tracecardinality // internal admin endpoint
+}
@@ -0,0 +1,7 @@
+package metric
+
+import "go.opentelemetry.io/otel/attribute"
+
+type AddOption interface{}
+
+func WithAttributes(attrs ...attribute.KeyValue) AddOption { return nil }
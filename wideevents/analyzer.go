@@ -9,19 +9,25 @@
 //
 // This analyzer:
 // - Bans traditional loggers (logrus, stdlib log, fmt.Print)
-// - Standardizes on zap for structured logging
+// - Standardizes on zap or log/slog for structured logging
 // - Detects scattered log statements (multiple logs per function)
 // - Enforces structured fields over string messages
 // - Integrates with OpenTelemetry/Datadog span attributes
 package wideevents
 
 import (
+	"flag"
+	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 
 	"github.com/spechtlabs/golint-sl/internal/nolint"
 )
@@ -35,8 +41,9 @@ This analyzer implements the "logging sucks" philosophy (https://loggingsucks.co
    - log.* from stdlib (use zap instead)
    - fmt.Print/Printf/Println (use zap.Debug for dev output)
 
-2. ENFORCES structured logging with zap:
-   - Require structured fields (zap.String, zap.Int, etc.)
+2. ENFORCES structured logging with zap or log/slog:
+   - Require structured fields (zap.String, slog.String, etc., or slog's
+     alternating "key", value pairs)
    - Flag bare string messages without context
    - Suggest using span attributes for tracing
 
@@ -44,6 +51,10 @@ This analyzer implements the "logging sucks" philosophy (https://loggingsucks.co
    - If a function has context.Context, use trace.SpanFromContext(ctx) to get the span
    - Add wide-event attributes to the span via span.SetAttributes()
    - Span attributes provide better observability than logs alone
+   - Recording an OpenTelemetry metric with attributes, such as
+     counter.Add(ctx, 1, metric.WithAttributes(...)), counts as well
+   - So does passing ctx to a helper, in any package, whose own body sets
+     span attributes or records such a metric
 
 4. DETECTS anti-patterns:
    - Multiple log statements in a single function (should be one wide event)
@@ -58,13 +69,39 @@ This analyzer implements the "logging sucks" philosophy (https://loggingsucks.co
 
 The goal: One log line per request per service with all necessary context,
 not scattered log statements throughout your code. When you have a context,
-add attributes to the span for better distributed tracing.`
+add attributes to the span for better distributed tracing.
+
+fmt.Print is user output, not logging, in CLI packages: main packages and
+packages with a cmd or cli path element.
+
+Flags:
+    -cli-packages  comma-separated globs of additional CLI packages, matched
+                   against consecutive elements of the import path`
+
+var cliPackages string
 
 var Analyzer = &analysis.Analyzer{
-	Name:     "wideevents",
-	Doc:      Doc,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
-	Run:      run,
+	Name:      "wideevents",
+	Doc:       Doc,
+	Flags:     flags(),
+	Requires:  nolint.Requires(inspect.Analyzer),
+	Run:       run,
+	FactTypes: []analysis.Fact{(*recorderFact)(nil)},
+}
+
+// recorderFact marks a function taking a context whose body sets span
+// attributes or records a metric with attributes, like a telemetry helper
+type recorderFact struct{}
+
+func (*recorderFact) AFact() {}
+
+func (*recorderFact) String() string { return "recorder" }
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("wideevents", flag.ExitOnError)
+	fs.StringVar(&cliPackages, "cli-packages", "",
+		"comma-separated globs of additional CLI packages, matched against consecutive elements of the import path")
+	return *fs
 }
 
 // Banned logging patterns - these should not be used
@@ -105,7 +142,7 @@ var traditionalLogMethods = map[string]bool{
 	"Info":         true,
 	"Infof":        true,
 	"Infow":        true,
-	"InfoContext":  true, // otelzap context-aware methods
+	"InfoContext":  true, // otelzap and slog context-aware methods
 	"Warn":         true,
 	"Warnf":        true,
 	"Warnw":        true,
@@ -133,6 +170,7 @@ var fieldChainMethods = map[string]bool{
 	"WithError":   true, // otelzap.L().WithError(err)
 	"With":        true, // logger.With(zap.String(...))
 	"WithOptions": true, // logger.WithOptions(...)
+	"WithGroup":   true, // slogLogger.WithGroup("request")
 	"Named":       true, // logger.Named("name") - adds logger name as context
 }
 
@@ -154,6 +192,13 @@ var spanFromContextFuncs = map[string]bool{
 	"StartSpan":       true, // common pattern
 }
 
+// metricRecordMethods are the OpenTelemetry instrument methods recording a
+// measurement, like Int64Counter.Add and Float64Histogram.Record
+var metricRecordMethods = map[string]bool{
+	"Add":    true,
+	"Record": true,
+}
+
 var spanSetAttributesMethods = map[string]bool{
 	"SetAttributes": true,
 	"SetAttribute":  true, // some APIs use singular
@@ -161,25 +206,67 @@ var spanSetAttributesMethods = map[string]bool{
 	"SetStatus":     true, // setting status is also valid span usage
 }
 
-// isCLIPackage checks if the package path indicates CLI code where fmt.Print is acceptable
-func isCLIPackage(pass *analysis.Pass) bool {
-	pkgPath := pass.Pkg.Path()
-	// Allow fmt.Print in cmd/ directories (CLI code) and internal/cli
-	return strings.Contains(pkgPath, "/cmd/") ||
-		strings.Contains(pkgPath, "/cli/") ||
-		strings.HasSuffix(pkgPath, "/cmd") ||
-		strings.HasSuffix(pkgPath, "/cli")
+// isCLIPackage checks if the package is CLI code where fmt.Print is
+// acceptable: a main package, a package with a cmd or cli path element, or
+// one matching a -cli-packages glob
+func isCLIPackage(pkgPath, pkgName string, globs []string) bool {
+	if pkgName == "main" {
+		return true
+	}
+
+	elems := strings.Split(pkgPath, "/")
+	for _, elem := range elems {
+		if elem == "cmd" || elem == "cli" {
+			return true
+		}
+	}
+
+	// A glob matches any run of consecutive elements, so "tools/*" matches
+	// example.com/repo/tools/migrate
+	for _, glob := range globs {
+		for i := range elems {
+			for j := i + 1; j <= len(elems); j++ {
+				if ok, _ := path.Match(glob, strings.Join(elems[i:j], "/")); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parseCLIPackages splits and validates the -cli-packages globs
+func parseCLIPackages(value string) ([]string, error) {
+	var globs []string
+	for _, glob := range strings.Split(value, ",") {
+		glob = strings.Trim(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("wideevents: invalid -cli-packages glob %q: %w", glob, err)
+		}
+		globs = append(globs, glob)
+	}
+	return globs, nil
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	reporter := nolint.NewReporter(pass)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
-	isCLI := isCLIPackage(pass)
+
+	globs, err := parseCLIPackages(cliPackages)
+	if err != nil {
+		return nil, err
+	}
+	isCLI := isCLIPackage(pass.Pkg.Path(), pass.Pkg.Name(), globs)
 
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
 	}
 
+	recorders := collectRecorders(pass, inspect)
+
 	inspect.Preorder(nodeFilter, func(n ast.Node) {
 		fn, ok := n.(*ast.FuncDecl)
 		if !ok || fn.Body == nil {
@@ -205,13 +292,111 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 
-		checkFunction(reporter, fn, isCLI)
+		checkFunction(reporter, pass, fn, isCLI, recorders)
 	})
 
 	return nil, nil
 }
 
-func checkFunction(reporter *nolint.Reporter, fn *ast.FuncDecl, isCLI bool) {
+// collectRecorders finds the functions of the package that take a context and
+// set span attributes or record metrics with attributes themselves, and
+// exports a recorderFact for each so callers in other packages see them too
+func collectRecorders(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Func]bool {
+	recorders := make(map[*types.Func]bool)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil || !functionHasContext(fn) {
+			return
+		}
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			return
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if recorders[obj] {
+				return false
+			}
+			if call, ok := n.(*ast.CallExpr); ok && recordsAttributes(pass, call) {
+				recorders[obj] = true
+			}
+			return true
+		})
+	})
+
+	for fn := range recorders {
+		pass.ExportObjectFact(fn, &recorderFact{})
+	}
+	return recorders
+}
+
+// recordsAttributes checks for a span attribute call or a metric recorded
+// with attributes
+func recordsAttributes(pass *analysis.Pass, call *ast.CallExpr) bool {
+	return isSpanSetAttributesCall(call) || isMetricRecordCall(pass, call)
+}
+
+// isMetricRecordCall checks for an OpenTelemetry measurement with attributes,
+// like counter.Add(ctx, 1, metric.WithAttributes(...))
+func isMetricRecordCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !metricRecordMethods[sel.Sel.Name] || len(call.Args) < 3 {
+		return false
+	}
+	if !isContext(pass, call.Args[0]) {
+		return false
+	}
+
+	for _, arg := range call.Args[2:] {
+		opt, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		fn, ok := typeutil.Callee(pass.TypesInfo, opt).(*types.Func)
+		if ok && fn.Pkg() != nil && fn.Pkg().Path() == "go.opentelemetry.io/otel/metric" &&
+			strings.HasPrefix(fn.Name(), "WithAttribute") {
+			return true
+		}
+	}
+	return false
+}
+
+// callsRecorder checks if call passes a context to a recorder function,
+// which sets span attributes on the caller's behalf
+func callsRecorder(pass *analysis.Pass, call *ast.CallExpr, recorders map[*types.Func]bool) bool {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return false
+	}
+
+	passesContext := false
+	for _, arg := range call.Args {
+		if isContext(pass, arg) {
+			passesContext = true
+			break
+		}
+	}
+	if !passesContext {
+		return false
+	}
+
+	if fn.Pkg() == pass.Pkg {
+		return recorders[fn]
+	}
+	return pass.ImportObjectFact(fn, new(recorderFact))
+}
+
+// isContext checks if the static type of expr is context.Context
+func isContext(pass *analysis.Pass, expr ast.Expr) bool {
+	named, ok := types.Unalias(pass.TypesInfo.TypeOf(expr)).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func checkFunction(reporter *nolint.Reporter, pass *analysis.Pass, fn *ast.FuncDecl, isCLI bool, recorders map[*types.Func]bool) {
 	var logCalls []*logCallInfo
 	var logsInLoops []*ast.CallExpr
 
@@ -220,36 +405,46 @@ func checkFunction(reporter *nolint.Reporter, fn *ast.FuncDecl, isCLI bool) {
 	hasSpanUsage := false
 	hasSpanAttributes := false
 
-	// Collect all log calls and span usage in the function
+	// Collect all log calls and span usage in the function in one pass,
+	// tracking how many loops enclose the current node
+	var stack []ast.Node
+	loopDepth := 0
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
-		// Track if we're inside a loop
-		switch node := n.(type) {
-		case *ast.ForStmt, *ast.RangeStmt:
-			// Check for log calls inside this loop
-			ast.Inspect(node, func(inner ast.Node) bool {
-				if call, ok := inner.(*ast.CallExpr); ok {
-					if info := analyzeLogCall(call); info != nil {
-						logsInLoops = append(logsInLoops, call)
-					}
-				}
-				return true
-			})
-			return false // Don't recurse again
+		if n == nil {
+			if isLoop(stack[len(stack)-1]) {
+				loopDepth--
+			}
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		if isLoop(n) {
+			loopDepth++
+		}
 
-		case *ast.CallExpr:
-			// Check banned patterns first (skip fmt.Print* in CLI code)
-			checkBannedLogPatterns(reporter, node, isCLI)
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
 
-			// Check for span usage
-			if isSpanFromContextCall(node) {
-				hasSpanUsage = true
-			}
-			if isSpanSetAttributesCall(node) {
-				hasSpanAttributes = true
-			}
+		// Check banned patterns first (skip fmt.Print* in CLI code)
+		checkBannedLogPatterns(reporter, call, isCLI)
+
+		// Check for span usage
+		if isSpanFromContextCall(call) {
+			hasSpanUsage = true
+		}
+		if recordsAttributes(pass, call) || callsRecorder(pass, call, recorders) {
+			hasSpanUsage = true
+			hasSpanAttributes = true
+		}
 
-			// Analyze the log call
-			if info := analyzeLogCall(node); info != nil {
+		// Analyze the log call. Calls inside loops are reported as log spam
+		// only, and don't count toward the scattered log statements.
+		if info := analyzeLogCall(call); info != nil {
+			if loopDepth > 0 {
+				logsInLoops = append(logsInLoops, call)
+			} else {
 				logCalls = append(logCalls, info)
 			}
 		}
@@ -280,7 +475,7 @@ func checkFunction(reporter *nolint.Reporter, fn *ast.FuncDecl, isCLI bool) {
 	for _, info := range logCalls {
 		if !info.isDebug && !info.hasStructuredFields {
 			reporter.Reportf(info.call.Pos(),
-				"log call without structured fields; use zap.String(\"field\", value) to add context for wide events")
+				"log call without structured fields; use zap.String(\"field\", value) or slog.String(\"field\", value) to add context for wide events")
 		}
 
 		// Check for traditional log methods that should be wide events
@@ -314,6 +509,15 @@ func checkFunction(reporter *nolint.Reporter, fn *ast.FuncDecl, isCLI bool) {
 	}
 }
 
+// isLoop checks if n is a for or range statement
+func isLoop(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.ForStmt, *ast.RangeStmt:
+		return true
+	}
+	return false
+}
+
 type logCallInfo struct {
 	call                *ast.CallExpr
 	method              string
@@ -327,13 +531,27 @@ type logCallInfo struct {
 // contextAwareMethods are methods that accept context.Context as first argument
 // and automatically extract trace context (trace_id, span_id) from it
 var contextAwareMethods = map[string]bool{
-	"ErrorContext": true, // otelzap context-aware methods
+	"ErrorContext": true, // otelzap and slog context-aware methods
 	"InfoContext":  true,
 	"WarnContext":  true,
 	"DebugContext": true,
 	"FatalContext": true,
 }
 
+// slogAttrFuncs are functions of log/slog that build a slog.Attr
+var slogAttrFuncs = map[string]bool{
+	"String":   true,
+	"Int":      true,
+	"Int64":    true,
+	"Uint64":   true,
+	"Float64":  true,
+	"Bool":     true,
+	"Time":     true,
+	"Duration": true,
+	"Any":      true,
+	"Group":    true,
+}
+
 // zapFieldMethods are methods on the zap package that return zap.Field, not log calls
 var zapFieldMethods = map[string]bool{
 	"String":     true,
@@ -377,12 +595,17 @@ func analyzeLogCall(call *ast.CallExpr) *logCallInfo {
 		if ident.Name == "zap" && zapFieldMethods[method] {
 			return nil
 		}
+		// Same for slog.String(), slog.Any(), etc.
+		if ident.Name == "slog" && slogAttrFuncs[method] {
+			return nil
+		}
 	}
 
 	// Check if this is a zap logger call
 	isZapCall := false
 	isLoggerMethod := false
 	hasChainedFields := false
+	var chainedFields []string
 
 	// Check for logger.Info(), logger.Error(), etc.
 	if traditionalLogMethods[method] || allowedDebugMethods[method] {
@@ -426,7 +649,7 @@ func analyzeLogCall(call *ast.CallExpr) *logCallInfo {
 			// Could be zap.L().Info(), otelzap.L().WithError(err).ErrorContext(), etc.
 			isZapCall = true
 			// Check for method chaining that adds fields
-			hasChainedFields = hasFieldChaining(x)
+			hasChainedFields, chainedFields = hasFieldChaining(x)
 		}
 	}
 
@@ -460,12 +683,12 @@ func analyzeLogCall(call *ast.CallExpr) *logCallInfo {
 	}
 
 	// Check for structured fields in arguments
-	info.hasStructuredFields, info.fieldNames = hasStructuredFields(call)
+	info.hasStructuredFields, info.fieldNames = hasStructuredFields(call, method)
 
 	// If method chaining adds fields, mark as having structured fields
 	if hasChainedFields {
 		info.hasStructuredFields = true
-		info.fieldNames = append(info.fieldNames, "error") // WithError adds error field
+		info.fieldNames = append(info.fieldNames, chainedFields...)
 	}
 
 	// Only return if this looks like a logging call
@@ -477,38 +700,72 @@ func analyzeLogCall(call *ast.CallExpr) *logCallInfo {
 }
 
 // hasFieldChaining checks if a call expression has method chaining that adds fields
-// e.g., otelzap.L().WithError(err) or logger.With(zap.String(...))
-func hasFieldChaining(call *ast.CallExpr) bool {
+// e.g., otelzap.L().WithError(err) or logger.With(zap.String(...)), and returns
+// the names of the fields it adds
+func hasFieldChaining(call *ast.CallExpr) (bool, []string) {
 	// Check if this call is a field-adding method
 	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-		methodName := sel.Sel.Name
-		if fieldChainMethods[methodName] {
-			return true
-		}
+		var fieldNames []string
 		// Recurse into the receiver to check for nested chaining
 		if innerCall, ok := sel.X.(*ast.CallExpr); ok {
-			return hasFieldChaining(innerCall)
+			_, fieldNames = hasFieldChaining(innerCall)
+		}
+
+		switch methodName := sel.Sel.Name; {
+		case methodName == "WithError":
+			return true, append(fieldNames, "error") // WithError adds error field
+		case methodName == "WithGroup" && len(call.Args) == 1:
+			// Fields logged later are nested under the group name
+			return true, append(fieldNames, fieldsOf(call.Args, false)...)
+		case fieldChainMethods[methodName]:
+			return true, append(fieldNames, fieldsOf(call.Args, true)...)
 		}
+		return len(fieldNames) > 0, fieldNames
 	}
-	return false
+	return false, nil
+}
+
+func hasStructuredFields(call *ast.CallExpr, method string) (bool, []string) {
+	// Skip the message, and the context before it for *Context methods
+	msgIndex := 0
+	if contextAwareMethods[method] {
+		msgIndex = 1
+	}
+	if len(call.Args) <= msgIndex+1 {
+		return false, nil
+	}
+
+	// Printf-style methods take format arguments, not key/value pairs
+	fieldNames := fieldsOf(call.Args[msgIndex+1:], !strings.HasSuffix(method, "f"))
+	return len(fieldNames) > 0, fieldNames
 }
 
-func hasStructuredFields(call *ast.CallExpr) (bool, []string) {
+// fieldsOf returns the names of the fields added by logger arguments:
+// zap.X and slog.X constructors, and with keyValues, the alternating
+// "key", value pairs accepted by slog and sugared zap
+func fieldsOf(args []ast.Expr, keyValues bool) []string {
 	var fieldNames []string
 
-	// Skip the first argument (message string)
-	for i, arg := range call.Args {
-		if i == 0 {
-			continue // Skip message
+	for i := 0; i < len(args); i++ {
+		// logger.Info("msg", "user_id", id), or a group name with WithGroup
+		if lit, ok := args[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if keyValues && i+1 >= len(args) {
+				continue
+			}
+			fieldNames = append(fieldNames, strings.Trim(lit.Value, "\"`"))
+			if keyValues {
+				i++ // Skip the value
+			}
+			continue
 		}
 
-		// Check for zap.String(), zap.Int(), zap.Error(), etc.
-		if argCall, ok := arg.(*ast.CallExpr); ok {
+		// Check for zap.String(), zap.Int(), zap.Error(), slog.String(), etc.
+		if argCall, ok := args[i].(*ast.CallExpr); ok {
 			if sel, ok := argCall.Fun.(*ast.SelectorExpr); ok {
 				if ident, ok := sel.X.(*ast.Ident); ok {
-					if ident.Name == "zap" {
+					if ident.Name == "zap" || (ident.Name == "slog" && slogAttrFuncs[sel.Sel.Name]) {
 						// zap.Error() is a special case - the field name is "error"
-						if sel.Sel.Name == "Error" || sel.Sel.Name == "NamedError" {
+						if ident.Name == "zap" && (sel.Sel.Name == "Error" || sel.Sel.Name == "NamedError") {
 							fieldNames = append(fieldNames, "error")
 							continue
 						}
@@ -524,7 +781,7 @@ func hasStructuredFields(call *ast.CallExpr) (bool, []string) {
 		}
 	}
 
-	return len(fieldNames) > 0, fieldNames
+	return fieldNames
 }
 
 func checkBannedLogPatterns(reporter *nolint.Reporter, call *ast.CallExpr, isCLI bool) {
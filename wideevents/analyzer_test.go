@@ -0,0 +1,41 @@
+package wideevents_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/wideevents"
+)
+
+func TestWideEventsSlog(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, wideevents.Analyzer, "slogsvc")
+}
+
+func TestWideEventsLoops(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, wideevents.Analyzer, "loops")
+}
+
+func TestWideEventsCLIPackages(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, wideevents.Analyzer, "tools/migrate", "pkg/client", "pkg/climate", "internal/cli")
+}
+
+func TestWideEventsCLIPackagesFlag(t *testing.T) {
+	if err := wideevents.Analyzer.Flags.Set("cli-packages", "hack/*, tools"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wideevents.Analyzer.Flags.Set("cli-packages", "")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, wideevents.Analyzer, "hack/gen", "pkg/client")
+}
+
+func TestWideEventsTelemetryHelpers(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, wideevents.Analyzer, "example.com/app/telemetry", "example.com/app/orders")
+}
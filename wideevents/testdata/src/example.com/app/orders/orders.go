@@ -0,0 +1,58 @@
+package orders
+
+import (
+	"context"
+	"log/slog"
+
+	"example.com/app/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Service struct {
+	cancelled metric.Int64Counter
+}
+
+// Good: the telemetry helper sets the span attributes
+func (s *Service) Place(ctx context.Context, id string) error {
+	telemetry.RecordRequest(ctx, attribute.String("order_id", id))
+	slog.ErrorContext(ctx, "order rejected", slog.String("order_id", id))
+	return nil
+}
+
+// Good: a helper in the same package sets the span attributes
+func (s *Service) Ship(ctx context.Context, id string) error {
+	recordShipment(ctx, id)
+	slog.ErrorContext(ctx, "shipment delayed", slog.String("order_id", id))
+	return nil
+}
+
+func recordShipment(ctx context.Context, id string) { // want recordShipment:"recorder"
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("shipped")
+}
+
+// Good: a metric recorded with attributes is a wide event
+func (s *Service) Cancel(ctx context.Context, id string) error { // want Cancel:"recorder"
+	s.cancelled.Add(ctx, 1, metric.WithAttributes(attribute.String("order_id", id)))
+	slog.ErrorContext(ctx, "order cancelled", slog.String("order_id", id))
+	return nil
+}
+
+// Bad: the helper is not given the context
+func (s *Service) Refund(ctx context.Context, id string) error { // want `function has context.Context but doesn't use span attributes`
+	audit(id)
+	slog.ErrorContext(ctx, "refund failed", slog.String("order_id", id))
+	return nil
+}
+
+func audit(id string) {}
+
+// Bad: only one level of helpers is followed
+func (s *Service) Return(ctx context.Context, id string) error { // want `function has context.Context but doesn't use span attributes`
+	telemetry.Annotate(ctx, id)
+	slog.ErrorContext(ctx, "return failed", slog.String("order_id", id))
+	return nil
+}
@@ -0,0 +1,18 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecordRequest adds the request's wide-event attributes to the span in ctx
+func RecordRequest(ctx context.Context, attrs ...attribute.KeyValue) { // want RecordRequest:"recorder"
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// Annotate only forwards to RecordRequest; it is not a recorder itself
+func Annotate(ctx context.Context, id string) {
+	RecordRequest(ctx, attribute.String("request_id", id))
+}
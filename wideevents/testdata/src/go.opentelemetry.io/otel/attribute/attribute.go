@@ -0,0 +1,14 @@
+package attribute
+
+type Key string
+
+type KeyValue struct {
+	Key   Key
+	Value any
+}
+
+func String(k, v string) KeyValue { return KeyValue{Key: Key(k), Value: v} }
+
+func Int(k string, v int) KeyValue { return KeyValue{Key: Key(k), Value: v} }
+
+func (k Key) String(v string) KeyValue { return KeyValue{Key: k, Value: v} }
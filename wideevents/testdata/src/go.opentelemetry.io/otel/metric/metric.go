@@ -0,0 +1,15 @@
+package metric
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type AddOption interface{}
+
+func WithAttributes(attrs ...attribute.KeyValue) AddOption { return nil }
+
+type Int64Counter interface {
+	Add(ctx context.Context, incr int64, options ...AddOption)
+}
@@ -0,0 +1,15 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Span interface {
+	End()
+	SetAttributes(kv ...attribute.KeyValue)
+	AddEvent(name string)
+}
+
+func SpanFromContext(ctx context.Context) Span { return nil }
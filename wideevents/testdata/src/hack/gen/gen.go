@@ -0,0 +1,7 @@
+package gen
+
+import "fmt"
+
+func Generate(name string) {
+	fmt.Println("generating", name)
+}
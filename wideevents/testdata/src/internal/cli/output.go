@@ -0,0 +1,7 @@
+package cli
+
+import "fmt"
+
+func PrintResult(result string) {
+	fmt.Println(result)
+}
@@ -0,0 +1,67 @@
+package loops
+
+type logger struct{}
+
+func (logger) Info(msg string, fields ...interface{})  {}
+func (logger) Debug(msg string, fields ...interface{}) {}
+
+var log logger
+
+func Single(items []string) {
+	for _, item := range items {
+		log.Info("processing item", "request_id", item) // want `logging inside loop creates log spam`
+	}
+}
+
+func Nested(batches []map[string]int) {
+	for i := 0; i < len(batches); i++ {
+		for key := range batches[i] {
+			log.Info("processing key", "request_id", key) // want `logging inside loop creates log spam`
+		}
+	}
+}
+
+func NestedInIf(batches [][]string, verbose bool) {
+	for _, batch := range batches {
+		if verbose {
+			for _, item := range batch {
+				log.Info("processing item", "request_id", item) // want `logging inside loop creates log spam`
+			}
+		}
+		log.Info("batch done", "request_id", batch[0]) // want `logging inside loop creates log spam`
+	}
+}
+
+func Labeled(batches [][]string) {
+outer:
+	for _, batch := range batches {
+		for _, item := range batch {
+			if item == "" {
+				continue outer
+			}
+			log.Info("processing item", "request_id", item) // want `logging inside loop creates log spam`
+		}
+	}
+}
+
+// Loop logs don't count toward scattered log statements
+
+func AfterLoop(items []string) {
+	for _, item := range items {
+		log.Info("processing item", "request_id", item) // want `logging inside loop creates log spam`
+	}
+	log.Info("items processed", "request_id", "batch")
+}
+
+func Scattered(id string) { // want `function has 2 log statements`
+	log.Info("starting", "request_id", id)
+	log.Info("done", "request_id", id)
+}
+
+func Closure(items []string) {
+	for _, item := range items {
+		go func() {
+			log.Info("processing item", "request_id", item) // want `logging inside loop creates log spam`
+		}()
+	}
+}
@@ -0,0 +1,7 @@
+package client
+
+import "fmt"
+
+func Connect(addr string) {
+	fmt.Println("connecting to", addr) // want `fmt.Println is not for logging`
+}
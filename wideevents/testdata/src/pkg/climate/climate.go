@@ -0,0 +1,7 @@
+package climate
+
+import "fmt"
+
+func Forecast(city string) {
+	fmt.Printf("forecast for %s\n", city) // want `fmt.Printf is not for logging`
+}
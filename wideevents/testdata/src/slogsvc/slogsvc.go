@@ -0,0 +1,73 @@
+package slogsvc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type attrSpan struct{}
+
+func (attrSpan) SetAttributes(kv ...any) {}
+
+func spanFrom(ctx context.Context) attrSpan { return attrSpan{} }
+
+type Service struct {
+	logger *slog.Logger
+}
+
+// Attribute constructors count as structured fields
+
+func (s *Service) Created(ctx context.Context, id string) { // want Created:"recorder"
+	span := spanFrom(ctx)
+	span.SetAttributes("user_id", id)
+	slog.InfoContext(ctx, "user created", slog.String("user_id", id))
+}
+
+func (s *Service) Stored(ctx context.Context, id string, took time.Duration) { // want Stored:"recorder"
+	span := spanFrom(ctx)
+	span.SetAttributes("user_id", id)
+	s.logger.InfoContext(ctx, "user stored",
+		slog.String("user_id", id),
+		slog.Duration("took", took),
+		slog.Group("request", slog.String("request_id", id)),
+	)
+}
+
+// Alternating keys and values count as structured fields
+
+func (s *Service) Deleted(id string) {
+	s.logger.Info("user deleted", "request_id", id)
+}
+
+func Failed(id string, err error) {
+	slog.Error("user lookup failed", "request_id", id, "error", err)
+}
+
+// Loggers with fields attached count as structured
+
+func (s *Service) Grouped(id string) {
+	s.logger.WithGroup("request").Warn("slow request")
+}
+
+func (s *Service) With(id string) {
+	s.logger.With("request_id", id).Info("request done")
+}
+
+// Missing fields are still reported
+
+func (s *Service) Bare() {
+	s.logger.Info("something happened") // want `log call without structured fields`
+}
+
+func BareContext(ctx context.Context) { // want BareContext:"recorder"
+	span := spanFrom(ctx)
+	span.SetAttributes("k", "v")
+	slog.WarnContext(ctx, "retrying") // want `log call without structured fields`
+}
+
+// Fields without request context are still reported
+
+func (s *Service) NoRequestContext(n int) {
+	s.logger.Info("batch done", slog.Int("count", n)) // want `wide event missing request context`
+}
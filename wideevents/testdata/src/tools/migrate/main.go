@@ -0,0 +1,11 @@
+package main
+
+import "fmt"
+
+func main() {
+	report("migrated")
+}
+
+func report(msg string) {
+	fmt.Println(msg)
+}
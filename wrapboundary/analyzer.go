@@ -0,0 +1,482 @@
+// Package wrapboundary provides an analyzer that checks errors are wrapped
+// exactly once where they cross into a boundary layer.
+//
+// errorwrap asks for context on every return, which in a layered service
+// produces messages like "get user: get user: query user: sql: no rows".
+// wrapboundary instead designates boundary packages (service, api) where
+// context is added once: errors coming in from a lower layer must be
+// wrapped, and errors that a function of the same layer already wrapped
+// must be passed through unchanged.
+package wrapboundary
+
+import (
+	"flag"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/spechtlabs/golint-sl/internal/nolint"
+)
+
+const Doc = `check errors are wrapped exactly once where they cross into a boundary layer
+
+Boundary packages are configured with -boundaries. A package belongs to the
+layer of the first boundary its import path ends with or contains as a
+directory, so example.com/app/service/users is part of the service layer.
+
+In boundary packages this analyzer reports:
+1. Errors from another layer returned without wrapping, so logs cannot tell
+   which layer failed
+2. Errors from the same layer wrapped again although the callee already
+   wrapped them, which repeats the context in every message
+
+Wrapped means fmt.Errorf with %w, humane.Wrap, or errors.Wrap/Wrapf/WithMessage
+from github.com/pkg/errors. New errors count as wrapped.
+
+Bad:
+    // package service
+    func (s *Service) User(id string) (*User, error) {
+        u, err := s.store.Get(id)
+        if err != nil {
+            return nil, err // store error leaves the service unwrapped
+        }
+        ...
+    }
+
+    // package service, calling a service function that already wraps
+    if err := s.checkQuota(id); err != nil {
+        return fmt.Errorf("check quota: %w", err) // wrapped twice
+    }
+
+Good:
+    u, err := s.store.Get(id)
+    if err != nil {
+        return nil, fmt.Errorf("get user %s: %w", id, err)
+    }
+
+    if err := s.checkQuota(id); err != nil {
+        return err
+    }
+
+Flags:
+    -boundaries  comma-separated boundary package names or import paths (default "api,service")`
+
+var boundaries string
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "wrapboundary",
+	Doc:       Doc,
+	Flags:     flags(),
+	Requires:  nolint.Requires(inspect.Analyzer),
+	Run:       run,
+	FactTypes: []analysis.Fact{(*wrappedFact)(nil)},
+}
+
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("wrapboundary", flag.ExitOnError)
+	fs.StringVar(&boundaries, "boundaries", "api,service",
+		"comma-separated boundary package names or import paths")
+	return *fs
+}
+
+// wrappedFact marks a function in a boundary package whose returned errors
+// are all wrapped for its layer already
+type wrappedFact struct{}
+
+func (*wrappedFact) AFact() {}
+
+func (*wrappedFact) String() string { return "wrapped" }
+
+// wrapFuncs are the functions that wrap their first error argument, by
+// package path
+var wrapFuncs = map[string]map[string]bool{
+	"github.com/pkg/errors":                       {"Wrap": true, "Wrapf": true, "WithMessage": true, "WithMessagef": true},
+	"github.com/sierrasoftworks/humane-errors-go": {"Wrap": true},
+}
+
+// newFuncs are the functions that create a new error, by package path
+var newFuncs = map[string]map[string]bool{
+	"errors":                {"New": true},
+	"github.com/pkg/errors": {"New": true, "Errorf": true},
+	"github.com/sierrasoftworks/humane-errors-go": {"New": true},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	layer := layerOf(pass.Pkg.Path())
+	if layer == "" {
+		return nil, nil
+	}
+
+	reporter := nolint.NewReporter(pass)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var funcs []*ast.FuncDecl
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil || strings.HasSuffix(pass.Fset.File(fn.Pos()).Name(), "_test.go") {
+			return
+		}
+		funcs = append(funcs, fn)
+	})
+
+	c := &checker{pass: pass, layer: layer, wrapped: make(map[*types.Func]bool)}
+	c.computeFacts(funcs)
+
+	for _, fn := range funcs {
+		for _, ret := range c.errorReturns(fn) {
+			c.checkReturn(reporter, ret)
+		}
+	}
+
+	return nil, nil
+}
+
+// layerOf returns the import path of the boundary layer pkgPath belongs to,
+// or "" if it is not in a boundary layer
+func layerOf(pkgPath string) string {
+	for _, b := range strings.Split(boundaries, ",") {
+		b = strings.Trim(strings.TrimSpace(b), "/")
+		if b == "" {
+			continue
+		}
+		switch {
+		case pkgPath == b || strings.HasSuffix(pkgPath, "/"+b):
+			return pkgPath
+		case strings.HasPrefix(pkgPath, b+"/"):
+			return b
+		}
+		if i := strings.Index(pkgPath, "/"+b+"/"); i >= 0 {
+			return pkgPath[:i+1+len(b)]
+		}
+	}
+	return ""
+}
+
+// checker holds the state for one boundary package
+type checker struct {
+	pass    *analysis.Pass
+	layer   string
+	wrapped map[*types.Func]bool // functions of this package that wrap
+}
+
+// errorReturn is one error value returned by a function
+type errorReturn struct {
+	fn   *ast.FuncDecl
+	stmt *ast.ReturnStmt
+	expr ast.Expr
+}
+
+// errorReturns lists the error-typed results of the return statements of fn,
+// skipping nil and closures
+func (c *checker) errorReturns(fn *ast.FuncDecl) []errorReturn {
+	obj, ok := c.pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	results := obj.Type().(*types.Signature).Results()
+
+	var errIdx []int
+	for i := 0; i < results.Len(); i++ {
+		if isErrorType(results.At(i).Type()) {
+			errIdx = append(errIdx, i)
+		}
+	}
+	if len(errIdx) == 0 {
+		return nil
+	}
+
+	var rets []errorReturn
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != results.Len() {
+				// Bare returns and return f() with multiple results
+				if len(node.Results) == 1 {
+					rets = append(rets, errorReturn{fn: fn, stmt: node, expr: node.Results[0]})
+				}
+				return true
+			}
+			for _, i := range errIdx {
+				if !isNil(c.pass, node.Results[i]) {
+					rets = append(rets, errorReturn{fn: fn, stmt: node, expr: node.Results[i]})
+				}
+			}
+		}
+		return true
+	})
+	return rets
+}
+
+// computeFacts finds the functions whose error returns are all wrapped and
+// exports a fact for each. Functions that return the errors of other
+// wrapping functions wrap too, so this iterates until nothing changes.
+func (c *checker) computeFacts(funcs []*ast.FuncDecl) {
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range funcs {
+			obj, ok := c.pass.TypesInfo.Defs[fn.Name].(*types.Func)
+			if !ok || c.wrapped[obj] {
+				continue
+			}
+			rets := c.errorReturns(fn)
+			if len(rets) == 0 {
+				continue
+			}
+			all := true
+			for _, ret := range rets {
+				if !c.isWrapped(ret) {
+					all = false
+					break
+				}
+			}
+			if all {
+				c.wrapped[obj] = true
+				changed = true
+			}
+		}
+	}
+
+	for fn := range c.wrapped {
+		c.pass.ExportObjectFact(fn, &wrappedFact{})
+	}
+}
+
+// isWrapped checks if a returned error already carries this layer's context
+func (c *checker) isWrapped(ret errorReturn) bool {
+	expr := c.resolve(ret.fn, ret.stmt.Pos(), ret.expr)
+
+	if call, ok := expr.(*ast.CallExpr); ok {
+		if _, ok := c.wrapArg(call); ok || c.isNew(call) {
+			return true
+		}
+		callee := calleeOf(c.pass, call)
+		return callee != nil && c.sameLayer(callee) && c.hasFact(callee)
+	}
+
+	// Sentinel errors declared in this layer
+	if ident, ok := expr.(*ast.Ident); ok {
+		if v, ok := c.pass.TypesInfo.Uses[ident].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+			return layerOf(v.Pkg().Path()) == c.layer
+		}
+	}
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if v, ok := c.pass.TypesInfo.Uses[sel.Sel].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+			return layerOf(v.Pkg().Path()) == c.layer
+		}
+	}
+	return false
+}
+
+// checkReturn reports unwrapped errors from other layers and wraps of errors
+// the same layer already wrapped
+func (c *checker) checkReturn(reporter *nolint.Reporter, ret errorReturn) {
+	expr := c.resolve(ret.fn, ret.stmt.Pos(), ret.expr)
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+
+	if inner, ok := c.wrapArg(call); ok {
+		origin, ok := c.resolve(ret.fn, call.Pos(), inner).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		callee := calleeOf(c.pass, origin)
+		if callee != nil && c.sameLayer(callee) && c.hasFact(callee) {
+			reporter.Reportf(call.Pos(),
+				"error from %s already wrapped at boundary; remove redundant wrap", funcName(callee))
+		}
+		return
+	}
+
+	if c.isNew(call) {
+		return
+	}
+	callee := calleeOf(c.pass, call)
+	if callee == nil || callee.Pkg() == nil || c.sameLayer(callee) {
+		return
+	}
+	reporter.Reportf(ret.expr.Pos(),
+		"error from %s returned unwrapped across boundary; wrap it with what %s was doing",
+		funcName(callee), ret.fn.Name.Name)
+}
+
+// sameLayer checks if fn is declared in the layer being analyzed
+func (c *checker) sameLayer(fn *types.Func) bool {
+	return fn.Pkg() != nil && layerOf(fn.Pkg().Path()) == c.layer
+}
+
+// hasFact checks if fn is known to wrap, from this package or a fact
+func (c *checker) hasFact(fn *types.Func) bool {
+	if fn.Pkg() == c.pass.Pkg {
+		return c.wrapped[fn]
+	}
+	return c.pass.ImportObjectFact(fn, new(wrappedFact))
+}
+
+// resolve follows an error variable back to the expression last assigned to
+// it before pos, so err in "x, err := f(); return err" resolves to f()
+func (c *checker) resolve(fn *ast.FuncDecl, pos token.Pos, expr ast.Expr) ast.Expr {
+	for depth := 0; depth < 8; depth++ {
+		expr = ast.Unparen(expr)
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return expr
+		}
+		obj, ok := c.pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || obj.Pkg() == nil || obj.Parent() == nil || obj.Parent() == obj.Pkg().Scope() {
+			return expr
+		}
+
+		value, at := lastAssignment(c.pass, fn.Body, obj, pos)
+		if value == nil {
+			return expr
+		}
+		expr, pos = value, at
+	}
+	return expr
+}
+
+// lastAssignment returns the value last assigned to obj before pos and the
+// position of that assignment. For multi-value assignments the call is
+// returned.
+func lastAssignment(pass *analysis.Pass, body *ast.BlockStmt, obj *types.Var, pos token.Pos) (ast.Expr, token.Pos) {
+	var value ast.Expr
+	var at token.Pos
+
+	record := func(lhs []*ast.Ident, rhs []ast.Expr, stmtPos token.Pos) {
+		for i, ident := range lhs {
+			if pass.TypesInfo.ObjectOf(ident) != obj || stmtPos < at {
+				continue
+			}
+			switch {
+			case len(rhs) == len(lhs):
+				value, at = rhs[i], stmtPos
+			case len(rhs) == 1:
+				value, at = rhs[0], stmtPos
+			}
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= pos {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if node.End() > pos {
+				return true
+			}
+			var lhs []*ast.Ident
+			for _, l := range node.Lhs {
+				ident, _ := l.(*ast.Ident)
+				if ident == nil {
+					ident = &ast.Ident{}
+				}
+				lhs = append(lhs, ident)
+			}
+			record(lhs, node.Rhs, node.Pos())
+		case *ast.ValueSpec:
+			record(node.Names, node.Values, node.Pos())
+		}
+		return true
+	})
+
+	return value, at
+}
+
+// wrapArg returns the error wrapped by call if it is a wrapping call
+func (c *checker) wrapArg(call *ast.CallExpr) (ast.Expr, bool) {
+	callee := calleeOf(c.pass, call)
+	if callee == nil || callee.Pkg() == nil || len(call.Args) == 0 {
+		return nil, false
+	}
+
+	if callee.Pkg().Path() == "fmt" && callee.Name() == "Errorf" {
+		format := c.pass.TypesInfo.Types[call.Args[0]].Value
+		if format == nil || format.Kind() != constant.String || !strings.Contains(constant.StringVal(format), "%w") {
+			return nil, false
+		}
+		for _, arg := range call.Args[1:] {
+			if isErrorType(c.pass.TypesInfo.TypeOf(arg)) {
+				return arg, true
+			}
+		}
+		return nil, false
+	}
+
+	if wrapFuncs[callee.Pkg().Path()][callee.Name()] {
+		return call.Args[0], true
+	}
+	return nil, false
+}
+
+// isNew checks for calls that create a new error, including fmt.Errorf
+// without %w
+func (c *checker) isNew(call *ast.CallExpr) bool {
+	callee := calleeOf(c.pass, call)
+	if callee == nil || callee.Pkg() == nil {
+		return false
+	}
+	if callee.Pkg().Path() == "fmt" && callee.Name() == "Errorf" {
+		_, wraps := c.wrapArg(call)
+		return !wraps
+	}
+	return newFuncs[callee.Pkg().Path()][callee.Name()]
+}
+
+// calleeOf returns the statically called function or method, if any
+func calleeOf(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		fn, _ := pass.TypesInfo.Uses[fun].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := pass.TypesInfo.Uses[fun.Sel].(*types.Func)
+		return fn
+	}
+	return nil
+}
+
+// funcName formats fn as pkg.Func or pkg.Type.Method
+func funcName(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	if fn.Pkg() == nil {
+		return name
+	}
+	return fn.Pkg().Name() + "." + name
+}
+
+// isErrorType checks if t is the error interface
+func isErrorType(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// isNil checks if expr is the predeclared nil
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = pass.TypesInfo.Uses[ident].(*types.Nil)
+	return ok
+}
@@ -0,0 +1,15 @@
+package wrapboundary_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/spechtlabs/golint-sl/wrapboundary"
+)
+
+func TestWrapBoundaryAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, wrapboundary.Analyzer,
+		"example.com/app/store", "example.com/app/service/users", "example.com/app/service")
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"example.com/app/service/users"
+	"example.com/app/store"
+)
+
+var ErrQuota = errors.New("quota exceeded")
+
+type Service struct {
+	db *store.DB
+}
+
+// Bad: lower-layer error returned unwrapped
+func (s *Service) Name(id string) (string, error) {
+	name, err := s.db.Get(id)
+	if err != nil {
+		return "", err // want `error from store.DB.Get returned unwrapped across boundary`
+	}
+	return name, nil
+}
+
+// Bad: direct return of a lower-layer call
+func (s *Service) Delete(id string) error {
+	return s.db.Delete(id) // want `error from store.DB.Delete returned unwrapped across boundary`
+}
+
+// Good: wrapped once at the boundary
+func (s *Service) Title(id string) (string, error) { // want Title:"wrapped"
+	name, err := s.db.Get(id)
+	if err != nil {
+		return "", fmt.Errorf("get title %s: %w", id, err)
+	}
+	return name, nil
+}
+
+// Bad: users.Lookup already wrapped the error for the service layer
+func (s *Service) User(id string) (string, error) { // want User:"wrapped"
+	name, err := users.Lookup(s.db, id)
+	if err != nil {
+		return "", fmt.Errorf("user: %w", err) // want `error from users.Lookup already wrapped at boundary; remove redundant wrap`
+	}
+	return name, nil
+}
+
+// Good: same-layer error passed through
+func (s *Service) Profile(id string) (string, error) { // want Profile:"wrapped"
+	return users.Lookup(s.db, id)
+}
+
+// Bad: Title in the same package already wraps
+func (s *Service) Header(id string) (string, error) { // want Header:"wrapped"
+	title, err := s.Title(id)
+	if err != nil {
+		return "", fmt.Errorf("header: %w", err) // want `error from service.Service.Title already wrapped at boundary; remove redundant wrap`
+	}
+	return title, nil
+}
+
+// Good: users.Remove does not wrap, so wrapping here is the only wrap
+func (s *Service) Remove(id string) error { // want Remove:"wrapped"
+	if err := users.Remove(s.db, id); err != nil {
+		return fmt.Errorf("remove %s: %w", id, err)
+	}
+	return nil
+}
+
+// Good: new errors and sentinels of this layer
+func (s *Service) Check(n int) error { // want Check:"wrapped"
+	if n > 10 {
+		return ErrQuota
+	}
+	if n < 0 {
+		return fmt.Errorf("negative count %d", n)
+	}
+	return nil
+}
+
+// Suppressed with nolint
+func (s *Service) Raw(id string) error {
+	return s.db.Delete(id) //nolint:wrapboundary // callers match store errors directly
+}
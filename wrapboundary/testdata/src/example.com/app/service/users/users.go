@@ -0,0 +1,25 @@
+// Package users is part of the service layer.
+package users
+
+import (
+	"fmt"
+
+	"example.com/app/store"
+)
+
+// Lookup wraps the store error once for the service layer.
+func Lookup(db *store.DB, id string) (string, error) { // want Lookup:"wrapped"
+	name, err := db.Get(id)
+	if err != nil {
+		return "", fmt.Errorf("look up user %s: %w", id, err)
+	}
+	return name, nil
+}
+
+// Remove passes the store error through unwrapped.
+func Remove(db *store.DB, id string) error {
+	if err := db.Delete(id); err != nil {
+		return err // want `error from store.DB.Delete returned unwrapped across boundary`
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Package store is the lower layer; it is not a boundary.
+package store
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+type DB struct{}
+
+func (db *DB) Get(id string) (string, error) {
+	if id == "" {
+		return "", ErrNotFound
+	}
+	return id, nil
+}
+
+func (db *DB) Delete(id string) error {
+	return ErrNotFound
+}